@@ -9,9 +9,12 @@ import (
 	"syscall"
 	"time"
 
+	"performatic-file-processor/internal/jobs"
 	"performatic-file-processor/internal/server"
 )
 
+const backgroundJobsShutdownTimeout = 5 * time.Minute
+
 func gracefulShutdown(apiServer *http.Server, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -30,6 +33,14 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 		log.Printf("Server forced to shutdown with error: %v", err)
 	}
 
+	// Uploads already accepted keep being chunked and published in background,
+	// so wait for them before exiting
+	jobsCtx, cancelJobs := context.WithTimeout(context.Background(), backgroundJobsShutdownTimeout)
+	defer cancelJobs()
+	if err := jobs.GetInstance().Shutdown(jobsCtx); err != nil {
+		log.Printf("Background jobs forced to stop with error: %v", err)
+	}
+
 	log.Println("Server exiting")
 
 	// Notify the main goroutine that the shutdown is complete
@@ -11,8 +11,14 @@ func main() {
 	factory := bankSlipFactory.NewBankSlipFactory()
 	processors := 30
 	consumer := factory.MakeBankSlipRowsConsumer(processors)
+	returnRecordsConsumer := factory.MakeReturnRecordsConsumer(processors)
 
 	go consumer.Execute(context.Background(), make(chan messaging.Message))
+	go returnRecordsConsumer.Execute(context.Background(), make(chan messaging.Message))
+	go factory.MakePurgeExpiredUploadsService().Execute(context.Background())
+	go factory.MakeDunningService().Execute(context.Background())
+	go factory.MakeIssueScheduledInstallmentsService().Execute(context.Background())
+	go factory.MakeIssueSubscriptionBankSlipsService().Execute(context.Background())
 
 	log.Println("Worker started!")
 	for {
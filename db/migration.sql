@@ -1,23 +1,224 @@
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
+CREATE TABLE beneficiary (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name VARCHAR(255) NOT NULL,
+  document VARCHAR(14) NOT NULL,
+  bank_code VARCHAR(3) NOT NULL,
+  agency VARCHAR(4) NOT NULL,
+  account VARCHAR(7) NOT NULL,
+  wallet VARCHAR(2) NOT NULL,
+  agreement_code VARCHAR(20) NOT NULL DEFAULT '',
+  -- Last "nosso número" allocated, only changed while the row is locked.
+  last_our_number BIGINT NOT NULL DEFAULT 0,
+  -- Last remittance file number, only changed while the row is locked.
+  last_remittance_sequence BIGINT NOT NULL DEFAULT 0,
+  -- Late fee, interest and discount of its bank slips, uploads override it.
+  charge_policy JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (bank_code, agency, account, wallet)
+);
+
 CREATE TABLE bank_slip_file (
   id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
   name VARCHAR(255) NOT NULL,
-  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+  status VARCHAR(50) NOT NULL DEFAULT 'RECEIVED',
+  total_rows INT NOT NULL DEFAULT 0,
+  rejected_rows INT NOT NULL DEFAULT 0,
+  header TEXT NOT NULL DEFAULT '',
+  delimiter VARCHAR(1) NOT NULL DEFAULT ',',
+  content_hash VARCHAR(64) NOT NULL DEFAULT '',
+  idempotency_key VARCHAR(255) UNIQUE,
+  storage_path TEXT NOT NULL DEFAULT '',
+  beneficiary_id UUID NOT NULL REFERENCES beneficiary(id),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT file_status_check CHECK (status IN ('RECEIVED', 'PROCESSING', 'COMPLETED', 'FAILED'))
+);
+
+CREATE INDEX bank_slip_file_content_hash_idx ON bank_slip_file(content_hash, created_at);
+
+-- Monthly billing of a customer, the workers issue the bank slip of
+-- next_period when next_due_date is in the issuance window.
+CREATE TABLE subscription (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  beneficiary_id UUID NOT NULL REFERENCES beneficiary(id),
+  user_name VARCHAR(255) NOT NULL,
+  government_id VARCHAR(14) NOT NULL,
+  user_email VARCHAR(255) NOT NULL,
+  amount_cents BIGINT NOT NULL,
+  day_of_month SMALLINT NOT NULL,
+  start_date DATE NOT NULL,
+  end_date DATE,
+  charge_policy JSONB NOT NULL DEFAULT '{}',
+  status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+  -- First day of the month of the next bank slip, NULL once it ends.
+  next_period DATE,
+  next_due_date DATE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT subscription_status_check CHECK (status IN ('ACTIVE', 'CANCELLED', 'ENDED')),
+  CONSTRAINT subscription_amount_cents_check CHECK (amount_cents > 0),
+  CONSTRAINT subscription_day_of_month_check CHECK (day_of_month BETWEEN 1 AND 31)
 );
 
+CREATE INDEX subscription_beneficiary_id_idx ON subscription(beneficiary_id, created_at);
+CREATE INDEX subscription_next_due_date_idx ON subscription(next_due_date) WHERE status = 'ACTIVE';
+
 CREATE TABLE bank_slip (
   debt_id UUID PRIMARY KEY UNIQUE,
-  debt_amount NUMERIC(10,2) NOT NULL,
+  debt_amount_cents BIGINT NOT NULL,
   debt_due_date DATE NOT NULL,
   user_name VARCHAR(255) NOT NULL,
-  government_id INT NOT NULL,
+  government_id VARCHAR(14) NOT NULL,
   user_email VARCHAR(255) NOT NULL,
-  bank_slip_file_id UUID NOT NULL,
+  -- Bank slips of subscriptions don't come from a file.
+  bank_slip_file_id UUID,
   error_message varchar(255),
   status VARCHAR(50) NOT NULL,
+  beneficiary_id UUID NOT NULL REFERENCES beneficiary(id),
+  -- Allocated in the transaction that inserts the bank slip.
+  our_number BIGINT,
+  barcode VARCHAR(44) NOT NULL DEFAULT '',
+  digitable_line VARCHAR(47) NOT NULL DEFAULT '',
+  pix_payload TEXT NOT NULL DEFAULT '',
+  -- Reported by the bank in the return files.
+  paid_amount_cents BIGINT,
+  paid_at DATE,
+  credited_at DATE,
+  bank_fee_cents BIGINT,
+  -- Resolved when the row is read, so later changes don't affect it.
+  charge_policy JSONB NOT NULL DEFAULT '{}',
+  -- Installments keep the debt they split, numbered from 1 to the count.
+  parent_debt_id UUID,
+  installment_number SMALLINT NOT NULL DEFAULT 0,
+  installment_count SMALLINT NOT NULL DEFAULT 0,
+  subscription_id UUID REFERENCES subscription(id),
   FOREIGN KEY (bank_slip_file_id) REFERENCES bank_slip_file(id),
-  CONSTRAINT status_check CHECK (status IN ('PENDING', 'SUCCESS', 'GENERATING_BILLING_ERROR', 'SENT_EMAIL_WITH_ERROR'))
+  UNIQUE (beneficiary_id, our_number),
+  CONSTRAINT status_check CHECK (status IN ('SCHEDULED', 'PENDING', 'SUCCESS', 'GENERATING_BILLING_ERROR', 'SENT_EMAIL_WITH_ERROR', 'PAID', 'REJECTED_BY_BANK', 'SETTLED', 'OVERDUE', 'EXPIRED', 'CANCELLED', 'REISSUED')),
+  CONSTRAINT debt_amount_cents_check CHECK (debt_amount_cents > 0),
+  CONSTRAINT bank_slip_origin_check CHECK (bank_slip_file_id IS NOT NULL OR subscription_id IS NOT NULL)
+);
+
+CREATE INDEX bank_slip_debt_id_idx ON bank_slip(debt_id);
+CREATE INDEX bank_slip_file_id_status_idx ON bank_slip(bank_slip_file_id, status);
+CREATE INDEX bank_slip_status_due_date_idx ON bank_slip(status, debt_due_date);
+CREATE INDEX bank_slip_parent_debt_id_idx ON bank_slip(parent_debt_id, installment_number);
+-- Return records without our number are matched by the start of the debt id
+-- written in the control number of the remittance.
+CREATE INDEX bank_slip_control_number_idx ON bank_slip(beneficiary_id, upper(left(replace(debt_id::text, '-', ''), 25)));
+
+-- Written in the transaction of every status change of a bank slip.
+CREATE TABLE bank_slip_status_history (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  debt_id UUID NOT NULL REFERENCES bank_slip(debt_id),
+  from_status VARCHAR(50) NOT NULL,
+  to_status VARCHAR(50) NOT NULL,
+  reason TEXT NOT NULL DEFAULT '',
+  changed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  -- Orders the changes written in the same transaction, which share changed_at.
+  position BIGSERIAL NOT NULL
+);
+
+CREATE INDEX bank_slip_status_history_debt_id_idx ON bank_slip_status_history(debt_id, changed_at, position);
+
+-- Dunning emails sent, a bank slip gets each step of the schedule once.
+CREATE TABLE bank_slip_dunning_notice (
+  debt_id UUID NOT NULL REFERENCES bank_slip(debt_id),
+  step VARCHAR(10) NOT NULL,
+  sent_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (debt_id, step)
+);
+
+CREATE TABLE remittance (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  beneficiary_id UUID NOT NULL REFERENCES beneficiary(id),
+  bank_slip_file_id UUID REFERENCES bank_slip_file(id),
+  format VARCHAR(10) NOT NULL,
+  sequence BIGINT NOT NULL,
+  total_bank_slips INT NOT NULL,
+  total_amount_cents BIGINT NOT NULL,
+  content TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (beneficiary_id, sequence),
+  CONSTRAINT remittance_format_check CHECK (format IN ('CNAB240', 'CNAB400'))
 );
 
-CREATE INDEX bank_slip_debt_id_idx ON bank_slip(debt_id);
\ No newline at end of file
+-- A bank slip is registered at the bank by a single remittance.
+CREATE TABLE remittance_bank_slip (
+  debt_id UUID PRIMARY KEY REFERENCES bank_slip(debt_id),
+  remittance_id UUID NOT NULL REFERENCES remittance(id)
+);
+
+CREATE INDEX remittance_bank_slip_remittance_id_idx ON remittance_bank_slip(remittance_id);
+
+CREATE TABLE bank_slip_return_file (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name VARCHAR(255) NOT NULL,
+  beneficiary_id UUID NOT NULL REFERENCES beneficiary(id),
+  format VARCHAR(10) NOT NULL DEFAULT '',
+  bank_code VARCHAR(3) NOT NULL DEFAULT '',
+  status VARCHAR(50) NOT NULL DEFAULT 'RECEIVED',
+  total_records INT NOT NULL DEFAULT 0,
+  processed_records INT NOT NULL DEFAULT 0,
+  reconciled_records INT NOT NULL DEFAULT 0,
+  unmatched_records INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT return_file_status_check CHECK (status IN ('RECEIVED', 'PROCESSING', 'COMPLETED', 'FAILED'))
+);
+
+CREATE TABLE bank_slip_unmatched_return_record (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  bank_slip_return_file_id UUID NOT NULL REFERENCES bank_slip_return_file(id),
+  line_number INT NOT NULL,
+  raw_record TEXT NOT NULL,
+  occurrence_code VARCHAR(2) NOT NULL,
+  our_number BIGINT NOT NULL,
+  control_number VARCHAR(25) NOT NULL,
+  reason VARCHAR(50) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT unmatched_reason_check CHECK (reason IN ('INVALID_RECORD', 'BANK_SLIP_NOT_FOUND', 'UNEXPECTED_STATUS'))
+);
+
+CREATE INDEX bank_slip_unmatched_return_record_file_id_line_idx ON bank_slip_unmatched_return_record(bank_slip_return_file_id, line_number);
+
+CREATE TABLE bank_slip_rejected_row (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  bank_slip_file_id UUID NOT NULL,
+  line_number INT NOT NULL,
+  raw_row TEXT NOT NULL,
+  error_code VARCHAR(50) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (bank_slip_file_id) REFERENCES bank_slip_file(id),
+  CONSTRAINT error_code_check CHECK (error_code IN ('INVALID_GOVERNMENT_ID', 'INVALID_GOVERNMENT_ID_CHECK_DIGITS', 'INVALID_AMOUNT', 'INVALID_DUE_DATE', 'COLUMN_COUNT_MISMATCH', 'DUPLICATE_DEBT_ID', 'MALFORMED_ROW', 'INVALID_CHARGE_POLICY', 'DUE_DATE_NOT_BUSINESS_DAY', 'INVALID_INSTALLMENT_PLAN'))
+);
+
+CREATE INDEX bank_slip_rejected_row_file_id_line_idx ON bank_slip_rejected_row(bank_slip_file_id, line_number);
+
+CREATE TABLE upload_profile (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name VARCHAR(100) NOT NULL UNIQUE,
+  columns JSONB NOT NULL,
+  date_format VARCHAR(20) NOT NULL DEFAULT 'YYYY-MM-DD',
+  decimal_separator CHAR(1) NOT NULL DEFAULT '.',
+  due_date_policy VARCHAR(20) NOT NULL DEFAULT 'KEEP',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT decimal_separator_check CHECK (decimal_separator IN ('.', ',')),
+  CONSTRAINT due_date_policy_check CHECK (due_date_policy IN ('KEEP', 'REJECT', 'ROLL_FORWARD'))
+);
+
+INSERT INTO upload_profile (name, columns, date_format, decimal_separator) VALUES
+  ('default', '{"userName": ["name"], "governmentId": ["governmentId"], "userEmail": ["email"], "debtAmount": ["debtAmount"], "debtDueDate": ["debtDueDate"], "debtId": ["debtId"]}', 'YYYY-MM-DD', '.'),
+  ('br', '{"userName": ["nome", "name"], "governmentId": ["cpf", "cnpj", "governmentId"], "userEmail": ["email", "e-mail"], "debtAmount": ["valor", "debtAmount"], "debtDueDate": ["vencimento", "debtDueDate"], "debtId": ["id_divida", "debtId"]}', 'DD/MM/YYYY', ',');
+
+CREATE TABLE fixed_width_layout (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name VARCHAR(100) NOT NULL UNIQUE,
+  extension VARCHAR(20) UNIQUE,
+  record_type VARCHAR(20) NOT NULL DEFAULT '',
+  fields JSONB NOT NULL,
+  date_format VARCHAR(20) NOT NULL DEFAULT 'DDMMYYYY',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,26 @@
+package ascii
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithoutAccents trims the value and drops its accents, along with whatever
+// is left outside of printable ASCII. Bank files and the Pix BR Code only take
+// ASCII, and their lengths are counted in bytes.
+func WithoutAccents(value string) string {
+	result, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn))), strings.TrimSpace(value))
+	if err != nil {
+		result = strings.TrimSpace(value)
+	}
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, result)
+}
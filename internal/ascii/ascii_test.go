@@ -0,0 +1,14 @@
+package ascii
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithoutAccents(t *testing.T) {
+	assert.Equal(t, "Sao Paulo", WithoutAccents(" São Paulo "))
+	assert.Equal(t, "Cobrancas Ltda", WithoutAccents("Cobranças Ltda"))
+	assert.Equal(t, "Joao 3", WithoutAccents("João ☃3"))
+	assert.Equal(t, "abc", WithoutAccents("a\tb\nc"))
+}
@@ -45,7 +45,8 @@ func (s *TestSuitBankSlipRowsConsumer) TestBankSlipRowsConsumer_ShouldSendMessag
 
 	s.mockProcessBankSlipRowsService.On("Execute", mock.Anything, mock.Anything).Return(nil).Twice()
 
-	ctx, _ := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
 	chann := make(chan messaging.Message)
 
 	var wg sync.WaitGroup
@@ -0,0 +1,49 @@
+package bank_slip
+
+import (
+	"context"
+	"log"
+	bank_slip "performatic-file-processor/internal/bank_slip/services"
+	"performatic-file-processor/internal/messaging"
+)
+
+type ReturnRecordsConsumer struct {
+	processReturnRecordsService bank_slip.ProcessReturnRecordsServiceInterface
+	messageConsumer             messaging.MessageConsumer
+	processors                  int
+}
+
+func NewReturnRecordsConsumer(
+	processReturnRecordsService bank_slip.ProcessReturnRecordsServiceInterface,
+	messageConsumer messaging.MessageConsumer,
+	processors int,
+) *ReturnRecordsConsumer {
+	return &ReturnRecordsConsumer{
+		processReturnRecordsService: processReturnRecordsService,
+		messageConsumer:             messageConsumer,
+		processors:                  processors,
+	}
+}
+
+func (s *ReturnRecordsConsumer) Execute(ctx context.Context, messagesChannel chan messaging.Message) {
+
+	for range s.processors {
+		go s.processReturnRecordsService.Execute(ctx, messagesChannel)
+	}
+
+	s.messageConsumer.SubscribeInTopic(ctx, bank_slip.ReturnRecordsTopic)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Exiting ReturnRecordsConsumer...")
+			return
+		default:
+			message, err := s.messageConsumer.Consume(ctx, bank_slip.ReturnRecordsTopic)
+			if err != nil {
+				continue
+			}
+			messagesChannel <- message
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package bank_slip
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/messaging"
+	"performatic-file-processor/internal/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitReturnRecordsConsumer struct {
+	suite.Suite
+	mockMessageConsumer             *mocks.MessageConsumerMock
+	mockProcessReturnRecordsService *bankSlipMocks.ProcessReturnRecordsServiceMock
+	consumer                        *ReturnRecordsConsumer
+}
+
+func (testSuit *TestSuitReturnRecordsConsumer) SetupTest() {
+	testSuit.mockMessageConsumer = new(mocks.MessageConsumerMock)
+	testSuit.mockProcessReturnRecordsService = new(bankSlipMocks.ProcessReturnRecordsServiceMock)
+
+	testSuit.consumer = NewReturnRecordsConsumer(
+		testSuit.mockProcessReturnRecordsService,
+		testSuit.mockMessageConsumer,
+		2,
+	)
+}
+
+func TestReturnRecordsConsumer(t *testing.T) {
+	suite.Run(t, new(TestSuitReturnRecordsConsumer))
+}
+
+func (s *TestSuitReturnRecordsConsumer) TestReturnRecordsConsumer_ShouldSendMessageToBeProcessed() {
+	mockMessage := mocks.NewMessageMock()
+
+	s.mockMessageConsumer.On("SubscribeInTopic", mock.Anything, "return-records-to-process").Return(nil)
+	s.mockMessageConsumer.On("Consume", mock.Anything, mock.Anything).Return(mockMessage, nil)
+
+	s.mockProcessReturnRecordsService.On("Execute", mock.Anything, mock.Anything).Return(nil).Twice()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	chann := make(chan messaging.Message)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.consumer.Execute(ctx, chann)
+	}()
+	time.Sleep(200 * time.Millisecond)
+	msg := <-chann
+
+	s.mockMessageConsumer.AssertCalled(s.T(), "Consume", ctx, "return-records-to-process")
+	s.mockProcessReturnRecordsService.AssertNumberOfCalls(s.T(), "Execute", 2)
+
+	s.Equal(mockMessage, msg)
+}
@@ -0,0 +1,212 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+type BankSlipStatusChangeResponse struct {
+	FromStatus bankSlipEntities.BankSlipStatus `json:"fromStatus"`
+	ToStatus   bankSlipEntities.BankSlipStatus `json:"toStatus"`
+	Reason     string                          `json:"reason,omitempty"`
+	ChangedAt  time.Time                       `json:"changedAt"`
+}
+
+type BankSlipHistoryResponse struct {
+	DebtId  string                          `json:"debtId"`
+	Status  bankSlipEntities.BankSlipStatus `json:"status"`
+	History []BankSlipStatusChangeResponse  `json:"history"`
+}
+
+type BankSlipAmountResponse struct {
+	DebtId       string                        `json:"debtId"`
+	Date         string                        `json:"date"`
+	DueDate      string                        `json:"dueDate"`
+	Amount       string                        `json:"amount"`
+	Discount     string                        `json:"discount"`
+	LateFee      string                        `json:"lateFee"`
+	Interest     string                        `json:"interest"`
+	DaysLate     int                           `json:"daysLate"`
+	Total        string                        `json:"total"`
+	ChargePolicy bankSlipEntities.ChargePolicy `json:"chargePolicy"`
+}
+
+type RetryBillingResponse struct {
+	DebtId       string                          `json:"debtId"`
+	Status       bankSlipEntities.BankSlipStatus `json:"status"`
+	ErrorMessage *string                         `json:"errorMessage,omitempty"`
+}
+
+type BankSlipController struct {
+	pdfService          bankSlip.GetBankSlipPdfServiceInterface
+	historyService      bankSlip.GetBankSlipStatusHistoryServiceInterface
+	amountService       bankSlip.GetBankSlipAmountServiceInterface
+	retryBillingService bankSlip.RetryBankSlipBillingServiceInterface
+}
+
+func NewBankSlipController(
+	pdfService bankSlip.GetBankSlipPdfServiceInterface,
+	historyService bankSlip.GetBankSlipStatusHistoryServiceInterface,
+	amountService bankSlip.GetBankSlipAmountServiceInterface,
+	retryBillingService bankSlip.RetryBankSlipBillingServiceInterface,
+) *BankSlipController {
+	return &BankSlipController{
+		pdfService:          pdfService,
+		historyService:      historyService,
+		amountService:       amountService,
+		retryBillingService: retryBillingService,
+	}
+}
+
+// DownloadPdfHandler answers with the printable boleto of a bank slip.
+func (controller *BankSlipController) DownloadPdfHandler(w http.ResponseWriter, r *http.Request) {
+	debtId := httprouter.ParamsFromContext(r.Context()).ByName("debtId")
+	if _, err := uuid.Parse(debtId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id da dívida inválido!"})
+		return
+	}
+
+	_, pdf, err := controller.pdfService.Execute(debtId)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Boleto não encontrado!"})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrBankSlipNotIssued) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "Boleto ainda não foi emitido!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao gerar PDF do boleto (debt id: %s): %v\n", debtId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao gerar PDF do boleto!"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", bankSlip.BankSlipPdfFileName(debtId)))
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf)
+}
+
+// GetHistoryHandler answers with the status changes of a bank slip, oldest
+// first, so support can tell how it got to its current status.
+func (controller *BankSlipController) GetHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	debtId := httprouter.ParamsFromContext(r.Context()).ByName("debtId")
+	if _, err := uuid.Parse(debtId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id da dívida inválido!"})
+		return
+	}
+
+	slip, history, err := controller.historyService.Execute(debtId)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Boleto não encontrado!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao obter histórico do boleto (debt id: %s): %v\n", debtId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao obter histórico do boleto!"})
+		return
+	}
+
+	response := BankSlipHistoryResponse{
+		DebtId:  slip.DebtId,
+		Status:  slip.Status,
+		History: make([]BankSlipStatusChangeResponse, 0, len(history)),
+	}
+	for _, statusChange := range history {
+		response.History = append(response.History, BankSlipStatusChangeResponse{
+			FromStatus: statusChange.FromStatus,
+			ToStatus:   statusChange.ToStatus,
+			Reason:     statusChange.Reason,
+			ChangedAt:  statusChange.ChangedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GetAmountHandler answers with what a bank slip is paid with on the date of
+// the query, today when there is none, with its discount, late fee and
+// interest.
+func (controller *BankSlipController) GetAmountHandler(w http.ResponseWriter, r *http.Request) {
+	debtId := httprouter.ParamsFromContext(r.Context()).ByName("debtId")
+	if _, err := uuid.Parse(debtId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id da dívida inválido!"})
+		return
+	}
+
+	date := time.Now()
+	if value := r.URL.Query().Get("date"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Data inválida!"})
+			return
+		}
+		date = parsed
+	}
+
+	slip, amountDue, err := controller.amountService.Execute(debtId, date)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Boleto não encontrado!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao calcular valor do boleto (debt id: %s): %v\n", debtId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao calcular valor do boleto!"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, BankSlipAmountResponse{
+		DebtId:       slip.DebtId,
+		Date:         amountDue.Date.Format(time.DateOnly),
+		DueDate:      slip.DebtDueDate.Format(time.DateOnly),
+		Amount:       amountDue.Amount.String(),
+		Discount:     amountDue.Discount.String(),
+		LateFee:      amountDue.LateFee.String(),
+		Interest:     amountDue.Interest.String(),
+		DaysLate:     amountDue.DaysLate,
+		Total:        amountDue.Total().String(),
+		ChargePolicy: slip.ChargePolicy,
+	})
+}
+
+// RetryBillingHandler bills again a bank slip whose billing failed, and
+// answers with the status it ended up in, GENERATING_BILLING_ERROR again when
+// the billing failed once more.
+func (controller *BankSlipController) RetryBillingHandler(w http.ResponseWriter, r *http.Request) {
+	debtId := httprouter.ParamsFromContext(r.Context()).ByName("debtId")
+	if _, err := uuid.Parse(debtId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id da dívida inválido!"})
+		return
+	}
+
+	slip, err := controller.retryBillingService.Execute(debtId)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Boleto não encontrado!"})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrUnexpectedBankSlipStatus) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "Boleto não está com erro de emissão!", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao emitir boleto novamente (debt id: %s): %v\n", debtId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao emitir boleto novamente!"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, RetryBillingResponse{
+		DebtId:       slip.DebtId,
+		Status:       slip.Status,
+		ErrorMessage: slip.ErrorMessage,
+	})
+}
@@ -0,0 +1,243 @@
+package bank_slip
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitBankSlipController struct {
+	suite.Suite
+	pdfService     *bankSlipMocks.GetBankSlipPdfServiceMock
+	historyService *bankSlipMocks.GetBankSlipStatusHistoryServiceMock
+	amountService  *bankSlipMocks.GetBankSlipAmountServiceMock
+	retryService   *bankSlipMocks.RetryBankSlipBillingServiceMock
+	controller     *BankSlipController
+}
+
+func (testSuit *TestSuitBankSlipController) SetupTest() {
+	testSuit.pdfService = new(bankSlipMocks.GetBankSlipPdfServiceMock)
+	testSuit.historyService = new(bankSlipMocks.GetBankSlipStatusHistoryServiceMock)
+	testSuit.amountService = new(bankSlipMocks.GetBankSlipAmountServiceMock)
+	testSuit.retryService = new(bankSlipMocks.RetryBankSlipBillingServiceMock)
+	testSuit.controller = NewBankSlipController(testSuit.pdfService, testSuit.historyService, testSuit.amountService, testSuit.retryService)
+}
+
+func TestBankSlipController(t *testing.T) {
+	suite.Run(t, new(TestSuitBankSlipController))
+}
+
+func newRequestWithDebtId(debtId string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/bank-slips/"+debtId+"/pdf", nil)
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "debtId", Value: debtId}})
+	return req.WithContext(ctx)
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldDownloadPdf() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	s.pdfService.On("Execute", debtId).Return(&bankSlipEntities.BankSlip{DebtId: debtId}, []byte("%PDF-1.4"), nil).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadPdfHandler(recorder, newRequestWithDebtId(debtId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Equal(s.T(), "application/pdf", recorder.Header().Get("Content-Type"))
+	assert.Equal(s.T(), `attachment; filename="boleto-ea23f2ca-663a-4266-a742-9da4c9f4fcb3.pdf"`, recorder.Header().Get("Content-Disposition"))
+	assert.Equal(s.T(), "%PDF-1.4", recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldRejectInvalidDebtId() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadPdfHandler(recorder, newRequestWithDebtId("invalid"))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Id da dívida inválido!"}`, recorder.Body.String())
+	s.pdfService.AssertNotCalled(s.T(), "Execute", mock.Anything)
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldReturnNotFoundWhenBankSlipDoesNotExist() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	s.pdfService.On("Execute", debtId).Return(nil, nil, bankSlipEntities.ErrBankSlipNotFound).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadPdfHandler(recorder, newRequestWithDebtId(debtId))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Boleto não encontrado!"}`, recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldReturnConflictWhenBoletoIsNotIssued() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	s.pdfService.On("Execute", debtId).Return(nil, nil, bankSlipEntities.ErrBankSlipNotIssued).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadPdfHandler(recorder, newRequestWithDebtId(debtId))
+
+	assert.Equal(s.T(), http.StatusConflict, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Boleto ainda não foi emitido!"}`, recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldReturnInternalErrorWhenPdfFails() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	s.pdfService.On("Execute", debtId).Return(nil, nil, assert.AnError).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadPdfHandler(recorder, newRequestWithDebtId(debtId))
+
+	assert.Equal(s.T(), http.StatusInternalServerError, recorder.Code)
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldAnswerStatusHistory() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	changedAt := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	history := []*bankSlipEntities.BankSlipStatusChange{
+		{DebtId: debtId, FromStatus: bankSlipEntities.BankSlipStatusPending, ToStatus: bankSlipEntities.BankSlipStatusSuccess, ChangedAt: changedAt},
+		{DebtId: debtId, FromStatus: bankSlipEntities.BankSlipStatusSuccess, ToStatus: bankSlipEntities.BankSlipStatusPaid, Reason: "occurrence 06 of return file return1", ChangedAt: changedAt.Add(time.Hour)},
+	}
+	s.historyService.On("Execute", debtId).Return(&bankSlipEntities.BankSlip{DebtId: debtId, Status: bankSlipEntities.BankSlipStatusPaid}, history, nil).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.GetHistoryHandler(recorder, newRequestWithDebtId(debtId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.JSONEq(s.T(), `{
+		"debtId": "ea23f2ca-663a-4266-a742-9da4c9f4fcb3",
+		"status": "PAID",
+		"history": [
+			{"fromStatus": "PENDING", "toStatus": "SUCCESS", "changedAt": "2025-03-10T12:00:00Z"},
+			{"fromStatus": "SUCCESS", "toStatus": "PAID", "reason": "occurrence 06 of return file return1", "changedAt": "2025-03-10T13:00:00Z"}
+		]
+	}`, recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldAnswerNotFoundHistoryOfUnknownBankSlip() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	s.historyService.On("Execute", debtId).Return(nil, nil, bankSlipEntities.ErrBankSlipNotFound).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.GetHistoryHandler(recorder, newRequestWithDebtId(debtId))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Boleto não encontrado!"}`, recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldRejectHistoryOfInvalidDebtId() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.GetHistoryHandler(recorder, newRequestWithDebtId("invalid"))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.historyService.AssertNotCalled(s.T(), "Execute", mock.Anything)
+}
+
+func newAmountRequest(debtId, date string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/bank-slips/"+debtId+"/amount?date="+date, nil)
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "debtId", Value: debtId}})
+	return req.WithContext(ctx)
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldAnswerAmountOnDate() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	date := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	lateFee, _ := bankSlipEntities.ParseCharge("2%", ".")
+	slip := &bankSlipEntities.BankSlip{
+		DebtId:       debtId,
+		DebtDueDate:  time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		ChargePolicy: bankSlipEntities.ChargePolicy{LateFee: &lateFee},
+	}
+	amountDue := bankSlipEntities.AmountDue{Date: date, Amount: 100050, LateFee: 2001, Interest: 500, DaysLate: 15}
+	s.amountService.On("Execute", debtId, date).Return(slip, amountDue, nil).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.GetAmountHandler(recorder, newAmountRequest(debtId, "2026-04-15"))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.JSONEq(s.T(), `{
+		"debtId": "ea23f2ca-663a-4266-a742-9da4c9f4fcb3",
+		"date": "2026-04-15",
+		"dueDate": "2026-03-31",
+		"amount": "1000.50",
+		"discount": "0.00",
+		"lateFee": "20.01",
+		"interest": "5.00",
+		"daysLate": 15,
+		"total": "1025.51",
+		"chargePolicy": {"lateFee": "2.00%"}
+	}`, recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldRejectAmountOnInvalidDate() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.GetAmountHandler(recorder, newAmountRequest("ea23f2ca-663a-4266-a742-9da4c9f4fcb3", "15/04/2026"))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Data inválida!"}`, recorder.Body.String())
+	s.amountService.AssertNotCalled(s.T(), "Execute", mock.Anything, mock.Anything)
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldAnswerNotFoundAmountOfUnknownBankSlip() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	s.amountService.On("Execute", debtId, mock.Anything).Return(nil, nil, bankSlipEntities.ErrBankSlipNotFound).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.GetAmountHandler(recorder, newAmountRequest(debtId, ""))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Boleto não encontrado!"}`, recorder.Body.String())
+}
+
+func newRetryBillingRequest(debtId string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/bank-slips/"+debtId+"/billing", nil)
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "debtId", Value: debtId}})
+	return req.WithContext(ctx)
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldRetryBilling() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	s.retryService.On("Execute", debtId).
+		Return(&bankSlipEntities.BankSlip{DebtId: debtId, Status: bankSlipEntities.BankSlipStatusSuccess}, nil).
+		Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.RetryBillingHandler(recorder, newRetryBillingRequest(debtId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.JSONEq(s.T(), `{"debtId": "ea23f2ca-663a-4266-a742-9da4c9f4fcb3", "status": "SUCCESS"}`, recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldAnswerBillingFailedAgain() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	errorMessage := "beneficiary not found"
+	s.retryService.On("Execute", debtId).
+		Return(&bankSlipEntities.BankSlip{DebtId: debtId, Status: bankSlipEntities.BankSlipStatusGenerateBillingError, ErrorMessage: &errorMessage}, nil).
+		Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.RetryBillingHandler(recorder, newRetryBillingRequest(debtId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.JSONEq(s.T(), `{"debtId": "ea23f2ca-663a-4266-a742-9da4c9f4fcb3", "status": "GENERATING_BILLING_ERROR", "errorMessage": "beneficiary not found"}`, recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipController) TestBankSlipController_ShouldRejectRetryOfBankSlipWithoutBillingError() {
+	debtId := "ea23f2ca-663a-4266-a742-9da4c9f4fcb3"
+	s.retryService.On("Execute", debtId).Return(nil, bankSlipEntities.ErrUnexpectedBankSlipStatus).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.RetryBillingHandler(recorder, newRetryBillingRequest(debtId))
+
+	assert.Equal(s.T(), http.StatusConflict, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), "Boleto não está com erro de emissão!")
+}
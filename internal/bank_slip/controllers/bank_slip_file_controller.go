@@ -0,0 +1,216 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+type BankSlipFileResponse struct {
+	ID            string                                  `json:"id"`
+	FileName      string                                  `json:"fileName"`
+	BeneficiaryId string                                  `json:"beneficiaryId"`
+	Status        bankSlipEntities.BankSlipFileStatus     `json:"status"`
+	TotalRows     int                                     `json:"totalRows"`
+	RejectedRows  int                                     `json:"rejectedRows"`
+	ProcessedRows int                                     `json:"processedRows"`
+	RowsByStatus  map[bankSlipEntities.BankSlipStatus]int `json:"rowsByStatus"`
+	CreatedAt     time.Time                               `json:"createdAt"`
+}
+
+type BankSlipFileController struct {
+	getService          bankSlip.GetBankSlipFileServiceInterface
+	listService         bankSlip.ListBankSlipFilesServiceInterface
+	rejectedRowsService bankSlip.GetBankSlipRejectedRowsServiceInterface
+	originalService     bankSlip.GetBankSlipFileOriginalServiceInterface
+	pdfsService         bankSlip.ExportBankSlipFilePdfsServiceInterface
+}
+
+func NewBankSlipFileController(
+	getService bankSlip.GetBankSlipFileServiceInterface,
+	listService bankSlip.ListBankSlipFilesServiceInterface,
+	rejectedRowsService bankSlip.GetBankSlipRejectedRowsServiceInterface,
+	originalService bankSlip.GetBankSlipFileOriginalServiceInterface,
+	pdfsService bankSlip.ExportBankSlipFilePdfsServiceInterface,
+) *BankSlipFileController {
+	return &BankSlipFileController{
+		getService:          getService,
+		listService:         listService,
+		rejectedRowsService: rejectedRowsService,
+		originalService:     originalService,
+		pdfsService:         pdfsService,
+	}
+}
+
+func (controller *BankSlipFileController) GetBankSlipFileHandler(w http.ResponseWriter, r *http.Request) {
+	fileId := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	if _, err := uuid.Parse(fileId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id do arquivo inválido!"})
+		return
+	}
+
+	file, err := controller.getService.Execute(fileId)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipFileNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Arquivo não encontrado!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao obter arquivo (id: %s): %v\n", fileId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao obter arquivo!"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newBankSlipFileResponse(file))
+}
+
+func (controller *BankSlipFileController) ListBankSlipFilesHandler(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	files, err := controller.listService.Execute(limit, offset)
+	if err != nil {
+		log.Printf("Erro ao listar arquivos: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao listar arquivos!"})
+		return
+	}
+
+	response := make([]BankSlipFileResponse, 0, len(files))
+	for _, file := range files {
+		response = append(response, newBankSlipFileResponse(file))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// DownloadRejectedRowsHandler answers with the rejected rows as they were sent,
+// plus their line number and error code, so the file can be fixed and resent.
+func (controller *BankSlipFileController) DownloadRejectedRowsHandler(w http.ResponseWriter, r *http.Request) {
+	fileId := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	if _, err := uuid.Parse(fileId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id do arquivo inválido!"})
+		return
+	}
+
+	file, rejectedRows, err := controller.rejectedRowsService.Execute(fileId)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipFileNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Arquivo não encontrado!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao obter linhas rejeitadas (id: %s): %v\n", fileId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao obter linhas rejeitadas!"})
+		return
+	}
+
+	delimiter := file.Delimiter
+	if delimiter == "" {
+		delimiter = string(bankSlipEntities.DefaultDelimiter)
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", rejectedRowsFileName(file.FileName)))
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "%s%slineNumber%serrorCode\n", file.Header, delimiter, delimiter)
+	for _, rejectedRow := range rejectedRows {
+		fmt.Fprintf(w, "%s%s%d%s%s\n", rejectedRow.RawRow, delimiter, rejectedRow.LineNumber, delimiter, rejectedRow.ErrorCode)
+	}
+}
+
+// DownloadOriginalHandler answers with the file as it was uploaded, read from
+// the storage shared by every replica.
+func (controller *BankSlipFileController) DownloadOriginalHandler(w http.ResponseWriter, r *http.Request) {
+	fileId := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	if _, err := uuid.Parse(fileId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id do arquivo inválido!"})
+		return
+	}
+
+	file, savedFile, err := controller.originalService.Execute(fileId)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipFileNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Arquivo não encontrado!"})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrOriginalFileNotAvailable) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Arquivo original não está mais disponível!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao obter arquivo original (id: %s): %v\n", fileId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao obter arquivo original!"})
+		return
+	}
+	defer savedFile.Close()
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	w.WriteHeader(http.StatusOK)
+
+	_, err = io.Copy(w, savedFile.Open())
+	if err != nil {
+		log.Printf("Erro ao enviar arquivo original (id: %s): %v\n", fileId, err)
+	}
+}
+
+// DownloadPdfsHandler streams a ZIP with the boleto PDF of every issued bank
+// slip of the file, for printing and mailing them in bulk.
+func (controller *BankSlipFileController) DownloadPdfsHandler(w http.ResponseWriter, r *http.Request) {
+	fileId := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	if _, err := uuid.Parse(fileId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id do arquivo inválido!"})
+		return
+	}
+
+	file, err := controller.getService.Execute(fileId)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipFileNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Arquivo não encontrado!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao obter arquivo (id: %s): %v\n", fileId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao obter arquivo!"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", pdfsFileName(file.FileName)))
+	w.WriteHeader(http.StatusOK)
+
+	exported, err := controller.pdfsService.Execute(fileId, w)
+	if err != nil {
+		log.Printf("Erro ao enviar PDFs dos boletos (id: %s, enviados: %d): %v\n", fileId, exported, err)
+	}
+}
+
+func pdfsFileName(fileName string) string {
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName)) + "-boletos.zip"
+}
+
+func rejectedRowsFileName(fileName string) string {
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName)) + "-rejected.csv"
+}
+
+func newBankSlipFileResponse(file *bankSlipEntities.BankSlipFileMetadata) BankSlipFileResponse {
+	return BankSlipFileResponse{
+		ID:            file.ID,
+		FileName:      file.FileName,
+		BeneficiaryId: file.BeneficiaryId,
+		Status:        file.Status,
+		TotalRows:     file.TotalRows,
+		RejectedRows:  file.RejectedRows,
+		ProcessedRows: file.ProcessedRows(),
+		RowsByStatus:  file.RowsByStatus,
+		CreatedAt:     file.CreatedAt,
+	}
+}
@@ -0,0 +1,260 @@
+package bank_slip
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	sharedMocks "performatic-file-processor/internal/mocks"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitBankSlipFileController struct {
+	suite.Suite
+	getService          *bankSlipMocks.GetBankSlipFileServiceMock
+	listService         *bankSlipMocks.ListBankSlipFilesServiceMock
+	rejectedRowsService *bankSlipMocks.GetBankSlipRejectedRowsServiceMock
+	originalService     *bankSlipMocks.GetBankSlipFileOriginalServiceMock
+	pdfsService         *bankSlipMocks.ExportBankSlipFilePdfsServiceMock
+	controller          *BankSlipFileController
+}
+
+func (testSuit *TestSuitBankSlipFileController) SetupTest() {
+	testSuit.getService = new(bankSlipMocks.GetBankSlipFileServiceMock)
+	testSuit.listService = new(bankSlipMocks.ListBankSlipFilesServiceMock)
+	testSuit.rejectedRowsService = new(bankSlipMocks.GetBankSlipRejectedRowsServiceMock)
+	testSuit.originalService = new(bankSlipMocks.GetBankSlipFileOriginalServiceMock)
+	testSuit.pdfsService = new(bankSlipMocks.ExportBankSlipFilePdfsServiceMock)
+
+	testSuit.controller = NewBankSlipFileController(
+		testSuit.getService,
+		testSuit.listService,
+		testSuit.rejectedRowsService,
+		testSuit.originalService,
+		testSuit.pdfsService,
+	)
+}
+
+func TestBankSlipFileController(t *testing.T) {
+	suite.Run(t, new(TestSuitBankSlipFileController))
+}
+
+func newRequestWithId(id string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/upload/bank-slip/file/"+id, nil)
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: id}})
+	return req.WithContext(ctx)
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldReturnBadRequestWhenIdIsInvalid() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.GetBankSlipFileHandler(recorder, newRequestWithId("any_id"))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.getService.AssertNotCalled(s.T(), "Execute")
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldReturnNotFoundWhenFileDoesNotExist() {
+	fileId := "0b1f6c0e-8d5d-4a6e-9d8e-2d3c1f0a9b7c"
+	s.getService.On("Execute", fileId).Return(nil, bankSlipEntities.ErrBankSlipFileNotFound).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.GetBankSlipFileHandler(recorder, newRequestWithId(fileId))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldReturnInternalErrorWhenServiceFails() {
+	fileId := "0b1f6c0e-8d5d-4a6e-9d8e-2d3c1f0a9b7c"
+	s.getService.On("Execute", fileId).Return(nil, assert.AnError).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.GetBankSlipFileHandler(recorder, newRequestWithId(fileId))
+
+	assert.Equal(s.T(), http.StatusInternalServerError, recorder.Code)
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldReturnFileProgress() {
+	fileId := "0b1f6c0e-8d5d-4a6e-9d8e-2d3c1f0a9b7c"
+	file := bankSlipEntities.NewBankSlipFileMetadata("file.csv")
+	file.ID = fileId
+	file.Processing(10)
+	file.RejectedRows = 2
+	file.RowsByStatus[bankSlipEntities.BankSlipStatusSuccess] = 5
+	file.RowsByStatus[bankSlipEntities.BankSlipStatusPending] = 3
+	s.getService.On("Execute", fileId).Return(file, nil).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.GetBankSlipFileHandler(recorder, newRequestWithId(fileId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	var response BankSlipFileResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fileId, response.ID)
+	assert.Equal(s.T(), bankSlipEntities.BankSlipFileStatusProcessing, response.Status)
+	assert.Equal(s.T(), 10, response.TotalRows)
+	assert.Equal(s.T(), 2, response.RejectedRows)
+	assert.Equal(s.T(), 7, response.ProcessedRows)
+	assert.Equal(s.T(), map[bankSlipEntities.BankSlipStatus]int{
+		bankSlipEntities.BankSlipStatusPending:              3,
+		bankSlipEntities.BankSlipStatusSuccess:              5,
+		bankSlipEntities.BankSlipStatusGenerateBillingError: 0,
+		bankSlipEntities.BankSlipStatusSendingEmailError:    0,
+	}, response.RowsByStatus)
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldListFilesWithPagination() {
+	file := bankSlipEntities.NewBankSlipFileMetadata("file.csv")
+	s.listService.On("Execute", 10, 20).Return([]*bankSlipEntities.BankSlipFileMetadata{file}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/upload/bank-slip/file?limit=10&offset=20", nil)
+	recorder := httptest.NewRecorder()
+	s.controller.ListBankSlipFilesHandler(recorder, req)
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+
+	var response []BankSlipFileResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), response, 1)
+	assert.Equal(s.T(), "file.csv", response[0].FileName)
+	assert.Equal(s.T(), bankSlipEntities.BankSlipFileStatusReceived, response[0].Status)
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldReturnInternalErrorWhenListFails() {
+	s.listService.On("Execute", 0, 0).Return(nil, assert.AnError).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/upload/bank-slip/file", nil)
+	recorder := httptest.NewRecorder()
+	s.controller.ListBankSlipFilesHandler(recorder, req)
+
+	assert.Equal(s.T(), http.StatusInternalServerError, recorder.Code)
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldReturnBadRequestWhenDownloadingRejectedRowsWithInvalidId() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadRejectedRowsHandler(recorder, newRequestWithId("any_id"))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.rejectedRowsService.AssertNotCalled(s.T(), "Execute")
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldReturnNotFoundWhenDownloadingRejectedRowsOfUnknownFile() {
+	fileId := "0b1f6c0e-8d5d-4a6e-9d8e-2d3c1f0a9b7c"
+	s.rejectedRowsService.On("Execute", fileId).Return(nil, nil, bankSlipEntities.ErrBankSlipFileNotFound).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.DownloadRejectedRowsHandler(recorder, newRequestWithId(fileId))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldDownloadRejectedRowsAsCSV() {
+	fileId := "0b1f6c0e-8d5d-4a6e-9d8e-2d3c1f0a9b7c"
+	file := bankSlipEntities.NewBankSlipFileMetadata("boletos.csv")
+	file.ID = fileId
+	file.HeaderRead("name;governmentId;debtId", ";")
+	s.rejectedRowsService.On("Execute", fileId).Return(file, []*bankSlipEntities.BankSlipRejectedRow{
+		bankSlipEntities.NewBankSlipRejectedRow(fileId, 3, "John;abc;debt1", bankSlipEntities.RejectionCodeInvalidGovernmentId),
+		bankSlipEntities.NewBankSlipRejectedRow(fileId, 7, "\"Doe; Mary\";123;debt1", bankSlipEntities.RejectionCodeDuplicateDebtId),
+	}, nil).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.DownloadRejectedRowsHandler(recorder, newRequestWithId(fileId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Equal(s.T(), "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(s.T(), `attachment; filename="boletos-rejected.csv"`, recorder.Header().Get("Content-Disposition"))
+	assert.Equal(s.T(), "name;governmentId;debtId;lineNumber;errorCode\n"+
+		"John;abc;debt1;3;INVALID_GOVERNMENT_ID\n"+
+		"\"Doe; Mary\";123;debt1;7;DUPLICATE_DEBT_ID\n", recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldDownloadOriginalFile() {
+	fileId := "0b1f6c0e-8d5d-4a6e-9d8e-2d3c1f0a9b7c"
+	file := bankSlipEntities.NewBankSlipFileMetadata("boletos.csv")
+	savedFile := sharedMocks.NewSavedFileMock()
+	savedFile.On("Open").Return(strings.NewReader("name,debtId\nJohn,1\n")).Once()
+	savedFile.On("Close").Return().Once()
+	s.originalService.On("Execute", fileId).Return(file, savedFile, nil).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadOriginalHandler(recorder, newRequestWithId(fileId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Equal(s.T(), `attachment; filename="boletos.csv"`, recorder.Header().Get("Content-Disposition"))
+	assert.Equal(s.T(), "name,debtId\nJohn,1\n", recorder.Body.String())
+	savedFile.AssertExpectations(s.T())
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldReturnNotFoundWhenOriginalIsNoLongerRetained() {
+	fileId := "0b1f6c0e-8d5d-4a6e-9d8e-2d3c1f0a9b7c"
+	s.originalService.On("Execute", fileId).Return(nil, nil, bankSlipEntities.ErrOriginalFileNotAvailable).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadOriginalHandler(recorder, newRequestWithId(fileId))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Arquivo original não está mais disponível!"}`, recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldReturnNotFoundWhenDownloadingOriginalOfUnknownFile() {
+	fileId := "0b1f6c0e-8d5d-4a6e-9d8e-2d3c1f0a9b7c"
+	s.originalService.On("Execute", fileId).Return(nil, nil, bankSlipEntities.ErrBankSlipFileNotFound).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadOriginalHandler(recorder, newRequestWithId(fileId))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldStreamZipWithPdfs() {
+	fileId := "0b1f6c0e-8d5d-4a6e-9d8e-2d3c1f0a9b7c"
+	file := bankSlipEntities.NewBankSlipFileMetadata("boletos.csv")
+	s.getService.On("Execute", fileId).Return(file, nil).Once()
+	s.pdfsService.On("Execute", fileId, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(io.Writer).Write([]byte("zip"))
+	}).Return(1, nil).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadPdfsHandler(recorder, newRequestWithId(fileId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Equal(s.T(), "application/zip", recorder.Header().Get("Content-Type"))
+	assert.Equal(s.T(), `attachment; filename="boletos-boletos.zip"`, recorder.Header().Get("Content-Disposition"))
+	assert.Equal(s.T(), "zip", recorder.Body.String())
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldNotStreamPdfsOfUnknownFile() {
+	fileId := "0b1f6c0e-8d5d-4a6e-9d8e-2d3c1f0a9b7c"
+	s.getService.On("Execute", fileId).Return(nil, bankSlipEntities.ErrBankSlipFileNotFound).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadPdfsHandler(recorder, newRequestWithId(fileId))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Arquivo não encontrado!"}`, recorder.Body.String())
+	s.pdfsService.AssertNotCalled(s.T(), "Execute", mock.Anything, mock.Anything)
+}
+
+func (s *TestSuitBankSlipFileController) TestBankSlipFileController_ShouldRejectInvalidIdWhenStreamingPdfs() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.DownloadPdfsHandler(recorder, newRequestWithId("invalid"))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.getService.AssertNotCalled(s.T(), "Execute", mock.Anything)
+}
@@ -0,0 +1,123 @@
+package bank_slip
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+)
+
+type BeneficiaryRequest struct {
+	Name          string `json:"name"`
+	Document      string `json:"document"`
+	BankCode      string `json:"bankCode"`
+	Agency        string `json:"agency"`
+	Account       string `json:"account"`
+	Wallet        string `json:"wallet"`
+	AgreementCode string `json:"agreementCode"`
+	// ChargePolicy is written as {"lateFee": "2%", "interest": "1%",
+	// "discount": "10.00", "discountDays": 5}, interest percentages being
+	// monthly.
+	ChargePolicy bankSlipEntities.ChargePolicy `json:"chargePolicy"`
+}
+
+type BeneficiaryResponse struct {
+	ID            string                        `json:"id"`
+	Name          string                        `json:"name"`
+	Document      string                        `json:"document"`
+	BankCode      string                        `json:"bankCode"`
+	Agency        string                        `json:"agency"`
+	Account       string                        `json:"account"`
+	Wallet        string                        `json:"wallet"`
+	AgreementCode string                        `json:"agreementCode"`
+	ChargePolicy  bankSlipEntities.ChargePolicy `json:"chargePolicy"`
+	CreatedAt     time.Time                     `json:"createdAt"`
+}
+
+type BeneficiaryController struct {
+	createService bankSlip.CreateBeneficiaryServiceInterface
+	listService   bankSlip.ListBeneficiariesServiceInterface
+}
+
+func NewBeneficiaryController(
+	createService bankSlip.CreateBeneficiaryServiceInterface,
+	listService bankSlip.ListBeneficiariesServiceInterface,
+) *BeneficiaryController {
+	return &BeneficiaryController{
+		createService: createService,
+		listService:   listService,
+	}
+}
+
+func (controller *BeneficiaryController) CreateBeneficiaryHandler(w http.ResponseWriter, r *http.Request) {
+	var request BeneficiaryRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if errors.Is(err, bankSlipEntities.ErrInvalidChargePolicy) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Beneficiário inválido!", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Corpo da requisição inválido!"})
+		return
+	}
+
+	beneficiary, err := controller.createService.Execute(
+		request.Name,
+		request.Document,
+		request.BankCode,
+		request.Agency,
+		request.Account,
+		request.Wallet,
+		request.AgreementCode,
+		request.ChargePolicy,
+	)
+	if errors.Is(err, bankSlipEntities.ErrInvalidBeneficiary) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Beneficiário inválido!", "details": err.Error()})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrBeneficiaryAlreadyExists) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "Beneficiário já existe!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao criar beneficiário: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao criar beneficiário!"})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newBeneficiaryResponse(beneficiary))
+}
+
+func (controller *BeneficiaryController) ListBeneficiariesHandler(w http.ResponseWriter, r *http.Request) {
+	beneficiaries, err := controller.listService.Execute()
+	if err != nil {
+		log.Printf("Erro ao listar beneficiários: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao listar beneficiários!"})
+		return
+	}
+
+	response := make([]BeneficiaryResponse, 0, len(beneficiaries))
+	for _, beneficiary := range beneficiaries {
+		response = append(response, newBeneficiaryResponse(beneficiary))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func newBeneficiaryResponse(beneficiary *bankSlipEntities.Beneficiary) BeneficiaryResponse {
+	return BeneficiaryResponse{
+		ID:            beneficiary.ID,
+		Name:          beneficiary.Name,
+		Document:      beneficiary.Document.Formatted(),
+		BankCode:      beneficiary.BankCode,
+		Agency:        beneficiary.Agency,
+		Account:       beneficiary.Account,
+		Wallet:        beneficiary.Wallet,
+		AgreementCode: beneficiary.AgreementCode,
+		ChargePolicy:  beneficiary.ChargePolicy,
+		CreatedAt:     beneficiary.CreatedAt,
+	}
+}
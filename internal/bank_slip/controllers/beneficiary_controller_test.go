@@ -0,0 +1,159 @@
+package bank_slip
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitBeneficiaryController struct {
+	suite.Suite
+	createService *bankSlipMocks.CreateBeneficiaryServiceMock
+	listService   *bankSlipMocks.ListBeneficiariesServiceMock
+	controller    *BeneficiaryController
+}
+
+func (testSuit *TestSuitBeneficiaryController) SetupTest() {
+	testSuit.createService = new(bankSlipMocks.CreateBeneficiaryServiceMock)
+	testSuit.listService = new(bankSlipMocks.ListBeneficiariesServiceMock)
+
+	testSuit.controller = NewBeneficiaryController(
+		testSuit.createService,
+		testSuit.listService,
+	)
+}
+
+func TestBeneficiaryController(t *testing.T) {
+	suite.Run(t, new(TestSuitBeneficiaryController))
+}
+
+func newCreateBeneficiaryRequest() *http.Request {
+	body, _ := json.Marshal(BeneficiaryRequest{
+		Name:          "Cobranças Ltda",
+		Document:      "11.222.333/0001-81",
+		BankCode:      "237",
+		Agency:        "1234",
+		Account:       "12345",
+		Wallet:        "09",
+		AgreementCode: "123456",
+	})
+	return httptest.NewRequest(http.MethodPost, "/beneficiaries", bytes.NewBuffer(body))
+}
+
+func newTestBeneficiary() *bankSlipEntities.Beneficiary {
+	beneficiary, _ := bankSlipEntities.NewBeneficiary("Cobranças Ltda", "11.222.333/0001-81", "237", "1234", "12345", "09", "123456")
+	beneficiary.ID = "beneficiary_id"
+	return beneficiary
+}
+
+func (s *TestSuitBeneficiaryController) TestCreateBeneficiaryHandler_ShouldCreateBeneficiary() {
+	s.createService.On("Execute", "Cobranças Ltda", "11.222.333/0001-81", "237", "1234", "12345", "09", "123456", bankSlipEntities.ChargePolicy{}).Return(newTestBeneficiary(), nil)
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateBeneficiaryHandler(recorder, newCreateBeneficiaryRequest())
+
+	assert.Equal(s.T(), http.StatusCreated, recorder.Code)
+	var response BeneficiaryResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(s.T(), "beneficiary_id", response.ID)
+	assert.Equal(s.T(), "11.222.333/0001-81", response.Document)
+	assert.Equal(s.T(), "0012345", response.Account)
+}
+
+func (s *TestSuitBeneficiaryController) TestCreateBeneficiaryHandler_ShouldReturnBadRequestWhenBodyIsInvalid() {
+	recorder := httptest.NewRecorder()
+	s.controller.CreateBeneficiaryHandler(recorder, httptest.NewRequest(http.MethodPost, "/beneficiaries", bytes.NewBufferString("{")))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.createService.AssertNotCalled(s.T(), "Execute")
+}
+
+func (s *TestSuitBeneficiaryController) TestCreateBeneficiaryHandler_ShouldSendChargePolicy() {
+	chargePolicy := bankSlipEntities.ChargePolicy{
+		LateFee:  &bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindPercent, Value: 200},
+		Interest: &bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindAmount, Value: 50},
+	}
+	beneficiary := newTestBeneficiary()
+	beneficiary.ChargePolicy = chargePolicy
+	s.createService.On("Execute", "Cobranças Ltda", "11.222.333/0001-81", "237", "1234", "12345", "09", "", chargePolicy).Return(beneficiary, nil)
+
+	body := `{"name":"Cobranças Ltda","document":"11.222.333/0001-81","bankCode":"237","agency":"1234","account":"12345","wallet":"09","chargePolicy":{"lateFee":"2%","interest":"0.50"}}`
+	recorder := httptest.NewRecorder()
+	s.controller.CreateBeneficiaryHandler(recorder, httptest.NewRequest(http.MethodPost, "/beneficiaries", bytes.NewBufferString(body)))
+
+	assert.Equal(s.T(), http.StatusCreated, recorder.Code)
+	var response map[string]any
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(s.T(), map[string]any{"lateFee": "2.00%", "interest": "0.50"}, response["chargePolicy"])
+}
+
+func (s *TestSuitBeneficiaryController) TestCreateBeneficiaryHandler_ShouldReturnBadRequestWhenChargePolicyIsInvalid() {
+	body := `{"name":"Cobranças Ltda","chargePolicy":{"lateFee":"2,5,0%"}}`
+	recorder := httptest.NewRecorder()
+	s.controller.CreateBeneficiaryHandler(recorder, httptest.NewRequest(http.MethodPost, "/beneficiaries", bytes.NewBufferString(body)))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), "Beneficiário inválido!")
+	s.createService.AssertNotCalled(s.T(), "Execute")
+}
+
+func (s *TestSuitBeneficiaryController) TestCreateBeneficiaryHandler_ShouldReturnBadRequestWhenBeneficiaryIsInvalid() {
+	invalidErr := fmt.Errorf("%w: name must be not empty", bankSlipEntities.ErrInvalidBeneficiary)
+	s.createService.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, invalidErr)
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateBeneficiaryHandler(recorder, newCreateBeneficiaryRequest())
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(s.T(), `{"error":"Beneficiário inválido!","details":"invalid beneficiary: name must be not empty"}`, recorder.Body.String())
+}
+
+func (s *TestSuitBeneficiaryController) TestCreateBeneficiaryHandler_ShouldReturnConflictWhenBeneficiaryExists() {
+	s.createService.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, bankSlipEntities.ErrBeneficiaryAlreadyExists)
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateBeneficiaryHandler(recorder, newCreateBeneficiaryRequest())
+
+	assert.Equal(s.T(), http.StatusConflict, recorder.Code)
+}
+
+func (s *TestSuitBeneficiaryController) TestCreateBeneficiaryHandler_ShouldReturnInternalErrorWhenServiceFails() {
+	s.createService.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateBeneficiaryHandler(recorder, newCreateBeneficiaryRequest())
+
+	assert.Equal(s.T(), http.StatusInternalServerError, recorder.Code)
+}
+
+func (s *TestSuitBeneficiaryController) TestListBeneficiariesHandler() {
+	s.listService.On("Execute").Return([]*bankSlipEntities.Beneficiary{newTestBeneficiary()}, nil)
+
+	recorder := httptest.NewRecorder()
+	s.controller.ListBeneficiariesHandler(recorder, httptest.NewRequest(http.MethodGet, "/beneficiaries", nil))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	var response []BeneficiaryResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Len(s.T(), response, 1)
+	assert.Equal(s.T(), "Cobranças Ltda", response[0].Name)
+}
+
+func (s *TestSuitBeneficiaryController) TestListBeneficiariesHandler_ShouldReturnInternalErrorWhenServiceFails() {
+	s.listService.On("Execute").Return(nil, assert.AnError)
+
+	recorder := httptest.NewRecorder()
+	s.controller.ListBeneficiariesHandler(recorder, httptest.NewRequest(http.MethodGet, "/beneficiaries", nil))
+
+	assert.Equal(s.T(), http.StatusInternalServerError, recorder.Code)
+}
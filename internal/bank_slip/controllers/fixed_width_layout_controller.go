@@ -0,0 +1,94 @@
+package bank_slip
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+)
+
+type FixedWidthLayoutRequest struct {
+	Name       string                             `json:"name"`
+	Extension  string                             `json:"extension"`
+	RecordType string                             `json:"recordType"`
+	Fields     []bankSlipEntities.FixedWidthField `json:"fields"`
+	DateFormat string                             `json:"dateFormat"`
+}
+
+type FixedWidthLayoutResponse struct {
+	ID         string                             `json:"id"`
+	Name       string                             `json:"name"`
+	Extension  string                             `json:"extension,omitempty"`
+	RecordType string                             `json:"recordType"`
+	Fields     []bankSlipEntities.FixedWidthField `json:"fields"`
+	DateFormat string                             `json:"dateFormat"`
+}
+
+type FixedWidthLayoutController struct {
+	createService bankSlip.CreateFixedWidthLayoutServiceInterface
+	listService   bankSlip.ListFixedWidthLayoutsServiceInterface
+}
+
+func NewFixedWidthLayoutController(
+	createService bankSlip.CreateFixedWidthLayoutServiceInterface,
+	listService bankSlip.ListFixedWidthLayoutsServiceInterface,
+) *FixedWidthLayoutController {
+	return &FixedWidthLayoutController{
+		createService: createService,
+		listService:   listService,
+	}
+}
+
+func (controller *FixedWidthLayoutController) CreateFixedWidthLayoutHandler(w http.ResponseWriter, r *http.Request) {
+	var request FixedWidthLayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Corpo da requisição inválido!"})
+		return
+	}
+
+	layout, err := controller.createService.Execute(request.Name, request.Extension, request.RecordType, request.Fields, request.DateFormat)
+	if errors.Is(err, bankSlipEntities.ErrInvalidFixedWidthLayout) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Leiaute de largura fixa inválido!", "details": err.Error()})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrFixedWidthLayoutAlreadyExists) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "Leiaute de largura fixa já existe!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao criar leiaute de largura fixa: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao criar leiaute de largura fixa!"})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newFixedWidthLayoutResponse(layout))
+}
+
+func (controller *FixedWidthLayoutController) ListFixedWidthLayoutsHandler(w http.ResponseWriter, r *http.Request) {
+	layouts, err := controller.listService.Execute()
+	if err != nil {
+		log.Printf("Erro ao listar leiautes de largura fixa: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao listar leiautes de largura fixa!"})
+		return
+	}
+
+	response := make([]FixedWidthLayoutResponse, 0, len(layouts))
+	for _, layout := range layouts {
+		response = append(response, newFixedWidthLayoutResponse(layout))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func newFixedWidthLayoutResponse(layout *bankSlipEntities.FixedWidthLayout) FixedWidthLayoutResponse {
+	return FixedWidthLayoutResponse{
+		ID:         layout.ID,
+		Name:       layout.Name,
+		Extension:  layout.Extension,
+		RecordType: layout.RecordType,
+		Fields:     layout.Fields,
+		DateFormat: layout.DateFormat,
+	}
+}
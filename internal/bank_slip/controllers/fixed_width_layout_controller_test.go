@@ -0,0 +1,127 @@
+package bank_slip
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitFixedWidthLayoutController struct {
+	suite.Suite
+	createService *bankSlipMocks.CreateFixedWidthLayoutServiceMock
+	listService   *bankSlipMocks.ListFixedWidthLayoutsServiceMock
+	controller    *FixedWidthLayoutController
+}
+
+func (testSuit *TestSuitFixedWidthLayoutController) SetupTest() {
+	testSuit.createService = new(bankSlipMocks.CreateFixedWidthLayoutServiceMock)
+	testSuit.listService = new(bankSlipMocks.ListFixedWidthLayoutsServiceMock)
+
+	testSuit.controller = NewFixedWidthLayoutController(
+		testSuit.createService,
+		testSuit.listService,
+	)
+}
+
+func TestFixedWidthLayoutController(t *testing.T) {
+	suite.Run(t, new(TestSuitFixedWidthLayoutController))
+}
+
+func newTestFixedWidthFields() []bankSlipEntities.FixedWidthField {
+	return []bankSlipEntities.FixedWidthField{
+		{Name: bankSlipEntities.BankSlipFieldDebtId, Start: 2, Length: 5, Type: bankSlipEntities.FixedWidthFieldTypeText},
+		{Name: bankSlipEntities.BankSlipFieldGovernmentId, Start: 7, Length: 11, Type: bankSlipEntities.FixedWidthFieldTypeText},
+		{Name: bankSlipEntities.BankSlipFieldUserName, Start: 18, Length: 10, Type: bankSlipEntities.FixedWidthFieldTypeText},
+		{Name: bankSlipEntities.BankSlipFieldUserEmail, Start: 28, Length: 16, Type: bankSlipEntities.FixedWidthFieldTypeText},
+		{Name: bankSlipEntities.BankSlipFieldDebtAmount, Start: 44, Length: 10, Type: bankSlipEntities.FixedWidthFieldTypeDecimal, Decimals: 2},
+		{Name: bankSlipEntities.BankSlipFieldDebtDueDate, Start: 54, Length: 8, Type: bankSlipEntities.FixedWidthFieldTypeDate},
+	}
+}
+
+func newCreateFixedWidthLayoutRequest() *http.Request {
+	body, _ := json.Marshal(FixedWidthLayoutRequest{
+		Name:       "partner",
+		Extension:  "rem",
+		RecordType: "1",
+		Fields:     newTestFixedWidthFields(),
+	})
+	return httptest.NewRequest(http.MethodPost, "/upload/fixed-width-layouts", bytes.NewBuffer(body))
+}
+
+func (s *TestSuitFixedWidthLayoutController) TestCreateFixedWidthLayoutHandler_ShouldCreateLayout() {
+	layout, _ := bankSlipEntities.NewFixedWidthLayout("partner", "rem", "1", newTestFixedWidthFields(), "")
+	layout.ID = "layout_id"
+	s.createService.On("Execute", "partner", "rem", "1", newTestFixedWidthFields(), "").Return(layout, nil)
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateFixedWidthLayoutHandler(recorder, newCreateFixedWidthLayoutRequest())
+
+	assert.Equal(s.T(), http.StatusCreated, recorder.Code)
+	var response FixedWidthLayoutResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(s.T(), "layout_id", response.ID)
+	assert.Equal(s.T(), ".rem", response.Extension)
+	assert.Equal(s.T(), bankSlipEntities.DefaultFixedWidthDateFormat, response.DateFormat)
+	assert.Equal(s.T(), newTestFixedWidthFields(), response.Fields)
+}
+
+func (s *TestSuitFixedWidthLayoutController) TestCreateFixedWidthLayoutHandler_ShouldReturnBadRequestWhenBodyIsInvalid() {
+	recorder := httptest.NewRecorder()
+	s.controller.CreateFixedWidthLayoutHandler(recorder, httptest.NewRequest(http.MethodPost, "/upload/fixed-width-layouts", bytes.NewBufferString("{")))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.createService.AssertNotCalled(s.T(), "Execute")
+}
+
+func (s *TestSuitFixedWidthLayoutController) TestCreateFixedWidthLayoutHandler_ShouldReturnBadRequestWhenLayoutIsInvalid() {
+	invalidErr := fmt.Errorf("%w: debtId must be given once", bankSlipEntities.ErrInvalidFixedWidthLayout)
+	s.createService.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, invalidErr)
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateFixedWidthLayoutHandler(recorder, newCreateFixedWidthLayoutRequest())
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(s.T(), `{"error":"Leiaute de largura fixa inválido!","details":"invalid fixed-width layout: debtId must be given once"}`, recorder.Body.String())
+}
+
+func (s *TestSuitFixedWidthLayoutController) TestCreateFixedWidthLayoutHandler_ShouldReturnConflictWhenLayoutExists() {
+	s.createService.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, bankSlipEntities.ErrFixedWidthLayoutAlreadyExists)
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateFixedWidthLayoutHandler(recorder, newCreateFixedWidthLayoutRequest())
+
+	assert.Equal(s.T(), http.StatusConflict, recorder.Code)
+}
+
+func (s *TestSuitFixedWidthLayoutController) TestListFixedWidthLayoutsHandler() {
+	layout, _ := bankSlipEntities.NewFixedWidthLayout("partner", "", "1", newTestFixedWidthFields(), "")
+	s.listService.On("Execute").Return([]*bankSlipEntities.FixedWidthLayout{layout}, nil)
+
+	recorder := httptest.NewRecorder()
+	s.controller.ListFixedWidthLayoutsHandler(recorder, httptest.NewRequest(http.MethodGet, "/upload/fixed-width-layouts", nil))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	var response []FixedWidthLayoutResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Len(s.T(), response, 1)
+	assert.Equal(s.T(), "partner", response[0].Name)
+}
+
+func (s *TestSuitFixedWidthLayoutController) TestListFixedWidthLayoutsHandler_ShouldReturnInternalErrorWhenServiceFails() {
+	s.listService.On("Execute").Return(nil, assert.AnError)
+
+	recorder := httptest.NewRecorder()
+	s.controller.ListFixedWidthLayoutsHandler(recorder, httptest.NewRequest(http.MethodGet, "/upload/fixed-width-layouts", nil))
+
+	assert.Equal(s.T(), http.StatusInternalServerError, recorder.Code)
+}
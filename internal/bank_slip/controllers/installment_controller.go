@@ -0,0 +1,150 @@
+package bank_slip
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+type InstallmentPlanRequest struct {
+	Installments int `json:"installments"`
+	// InstallmentDay is the day of the month the installments after the first
+	// are due on, the day of the first one when it's not given.
+	InstallmentDay int `json:"installmentDay"`
+	// FirstDueDate is written as "2026-05-10", the due date of the debt when
+	// it's not given.
+	FirstDueDate string `json:"firstDueDate"`
+}
+
+type InstallmentResponse struct {
+	DebtId            string                          `json:"debtId"`
+	InstallmentNumber int                             `json:"installmentNumber"`
+	InstallmentCount  int                             `json:"installmentCount"`
+	Amount            string                          `json:"amount"`
+	DueDate           string                          `json:"dueDate"`
+	Status            bankSlipEntities.BankSlipStatus `json:"status"`
+}
+
+type InstallmentsResponse struct {
+	ParentDebtId string                `json:"parentDebtId"`
+	Installments []InstallmentResponse `json:"installments"`
+}
+
+type InstallmentController struct {
+	createService bankSlip.CreateInstallmentsServiceInterface
+	listService   bankSlip.ListInstallmentsServiceInterface
+}
+
+func NewInstallmentController(
+	createService bankSlip.CreateInstallmentsServiceInterface,
+	listService bankSlip.ListInstallmentsServiceInterface,
+) *InstallmentController {
+	return &InstallmentController{
+		createService: createService,
+		listService:   listService,
+	}
+}
+
+// CreateInstallmentsHandler splits a bank slip in installments, answering
+// with them in their order.
+func (controller *InstallmentController) CreateInstallmentsHandler(w http.ResponseWriter, r *http.Request) {
+	debtId := httprouter.ParamsFromContext(r.Context()).ByName("debtId")
+	if _, err := uuid.Parse(debtId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id da dívida inválido!"})
+		return
+	}
+
+	var request InstallmentPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Corpo da requisição inválido!"})
+		return
+	}
+	firstDueDate := time.Time{}
+	if request.FirstDueDate != "" {
+		parsed, err := time.Parse(time.DateOnly, request.FirstDueDate)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Data inválida!"})
+			return
+		}
+		firstDueDate = parsed
+	}
+	plan, err := bankSlipEntities.NewInstallmentPlan(request.Installments, request.InstallmentDay)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Parcelamento inválido!", "details": err.Error()})
+		return
+	}
+
+	installments, err := controller.createService.Execute(debtId, plan, firstDueDate)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Boleto não encontrado!"})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrInvalidInstallmentPlan) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Parcelamento inválido!", "details": err.Error()})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrInstallmentPlanAlreadyExists) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "Dívida já parcelada!"})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrUnexpectedBankSlipStatus) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "Boleto não pode ser parcelado!", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao parcelar dívida (debt id: %s): %v\n", debtId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao parcelar dívida!"})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newInstallmentsResponse(debtId, installments))
+}
+
+// ListInstallmentsHandler answers with the installments of a debt, split on
+// upload or by the API.
+func (controller *InstallmentController) ListInstallmentsHandler(w http.ResponseWriter, r *http.Request) {
+	debtId := httprouter.ParamsFromContext(r.Context()).ByName("debtId")
+	if _, err := uuid.Parse(debtId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id da dívida inválido!"})
+		return
+	}
+
+	installments, err := controller.listService.Execute(debtId)
+	if err != nil {
+		log.Printf("Erro ao listar parcelas (debt id: %s): %v\n", debtId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao listar parcelas!"})
+		return
+	}
+	if len(installments) == 0 {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Parcelas não encontradas!"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newInstallmentsResponse(debtId, installments))
+}
+
+func newInstallmentsResponse(parentDebtId string, installments []*bankSlipEntities.BankSlip) InstallmentsResponse {
+	response := InstallmentsResponse{
+		ParentDebtId: parentDebtId,
+		Installments: make([]InstallmentResponse, 0, len(installments)),
+	}
+	for _, installment := range installments {
+		response.Installments = append(response.Installments, InstallmentResponse{
+			DebtId:            installment.DebtId,
+			InstallmentNumber: installment.InstallmentNumber,
+			InstallmentCount:  installment.InstallmentCount,
+			Amount:            installment.DebtAmount.String(),
+			DueDate:           installment.DebtDueDate.Format(time.DateOnly),
+			Status:            installment.Status,
+		})
+	}
+	return response
+}
@@ -0,0 +1,128 @@
+package bank_slip
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const testInstallmentsDebtId = "e1f4b1f2-7c1a-4b8e-9a3d-2f6c8d9e0a11"
+
+type TestSuitInstallmentController struct {
+	suite.Suite
+	createService *bankSlipMocks.CreateInstallmentsServiceMock
+	listService   *bankSlipMocks.ListInstallmentsServiceMock
+	controller    *InstallmentController
+}
+
+func (testSuit *TestSuitInstallmentController) SetupTest() {
+	testSuit.createService = new(bankSlipMocks.CreateInstallmentsServiceMock)
+	testSuit.listService = new(bankSlipMocks.ListInstallmentsServiceMock)
+	testSuit.controller = NewInstallmentController(testSuit.createService, testSuit.listService)
+}
+
+func TestInstallmentController(t *testing.T) {
+	suite.Run(t, new(TestSuitInstallmentController))
+}
+
+func newInstallmentsRequest(method, debtId, body string) *http.Request {
+	req := httptest.NewRequest(method, "/bank-slips/"+debtId+"/installments", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "debtId", Value: debtId}})
+	return req.WithContext(ctx)
+}
+
+func newTestInstallments() []*bankSlipEntities.BankSlip {
+	return []*bankSlipEntities.BankSlip{
+		{DebtId: "debt1", DebtAmount: 50000, DebtDueDate: time.Date(2026, 5, 11, 0, 0, 0, 0, time.UTC), Status: bankSlipEntities.BankSlipStatusPending, ParentDebtId: testInstallmentsDebtId, InstallmentNumber: 1, InstallmentCount: 2},
+		{DebtId: "debt2", DebtAmount: 50000, DebtDueDate: time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC), Status: bankSlipEntities.BankSlipStatusScheduled, ParentDebtId: testInstallmentsDebtId, InstallmentNumber: 2, InstallmentCount: 2},
+	}
+}
+
+func (s *TestSuitInstallmentController) TestInstallmentController_ShouldCreateInstallments() {
+	plan := bankSlipEntities.InstallmentPlan{Installments: 2, Day: 10}
+	s.createService.On("Execute", testInstallmentsDebtId, plan, time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)).Return(newTestInstallments(), nil).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.CreateInstallmentsHandler(recorder, newInstallmentsRequest(http.MethodPost, testInstallmentsDebtId, `{"installments":2,"installmentDay":10,"firstDueDate":"2026-05-10"}`))
+
+	assert.Equal(s.T(), http.StatusCreated, recorder.Code)
+	var response InstallmentsResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(s.T(), testInstallmentsDebtId, response.ParentDebtId)
+	assert.Len(s.T(), response.Installments, 2)
+	assert.Equal(s.T(), InstallmentResponse{
+		DebtId:            "debt2",
+		InstallmentNumber: 2,
+		InstallmentCount:  2,
+		Amount:            "500.00",
+		DueDate:           "2026-06-10",
+		Status:            bankSlipEntities.BankSlipStatusScheduled,
+	}, response.Installments[1])
+}
+
+func (s *TestSuitInstallmentController) TestInstallmentController_ShouldRejectInvalidPlan() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.CreateInstallmentsHandler(recorder, newInstallmentsRequest(http.MethodPost, testInstallmentsDebtId, `{"installments":0}`))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), "Parcelamento inválido!")
+	s.createService.AssertNotCalled(s.T(), "Execute", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TestSuitInstallmentController) TestInstallmentController_ShouldAnswerConflictWhenDebtWasSplit() {
+	s.createService.On("Execute", testInstallmentsDebtId, mock.Anything, time.Time{}).Return(nil, bankSlipEntities.ErrInstallmentPlanAlreadyExists).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.CreateInstallmentsHandler(recorder, newInstallmentsRequest(http.MethodPost, testInstallmentsDebtId, `{"installments":2}`))
+
+	assert.Equal(s.T(), http.StatusConflict, recorder.Code)
+}
+
+func (s *TestSuitInstallmentController) TestInstallmentController_ShouldAnswerNotFoundWhenBankSlipDoesNotExist() {
+	s.createService.On("Execute", testInstallmentsDebtId, mock.Anything, mock.Anything).Return(nil, bankSlipEntities.ErrBankSlipNotFound).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.CreateInstallmentsHandler(recorder, newInstallmentsRequest(http.MethodPost, testInstallmentsDebtId, `{"installments":2}`))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *TestSuitInstallmentController) TestInstallmentController_ShouldListInstallments() {
+	s.listService.On("Execute", testInstallmentsDebtId).Return(newTestInstallments(), nil).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.ListInstallmentsHandler(recorder, newInstallmentsRequest(http.MethodGet, testInstallmentsDebtId, ""))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), `"installmentNumber":1`)
+}
+
+func (s *TestSuitInstallmentController) TestInstallmentController_ShouldAnswerNotFoundWhenDebtHasNoInstallments() {
+	s.listService.On("Execute", testInstallmentsDebtId).Return([]*bankSlipEntities.BankSlip{}, nil).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.ListInstallmentsHandler(recorder, newInstallmentsRequest(http.MethodGet, testInstallmentsDebtId, ""))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *TestSuitInstallmentController) TestInstallmentController_ShouldRejectInvalidDebtId() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.ListInstallmentsHandler(recorder, newInstallmentsRequest(http.MethodGet, "invalid", ""))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+}
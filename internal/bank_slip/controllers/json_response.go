@@ -0,0 +1,20 @@
+package bank_slip
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	response, err := json.Marshal(body)
+	if err != nil {
+		log.Printf("Erro ao serializar resposta: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(response)
+}
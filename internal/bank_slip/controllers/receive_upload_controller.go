@@ -2,23 +2,38 @@ package bank_slip
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
 	bankSlip "performatic-file-processor/internal/bank_slip/services"
+	"performatic-file-processor/internal/jobs"
 )
 
+const BankSlipFileResourcePath = "/upload/bank-slip/file/"
+
 type ReceiveUploadController struct {
-	service bankSlip.ReceiveUploadServiceInterface
+	service       bankSlip.ReceiveUploadServiceInterface
+	maxUploadSize int64
 }
 
 func NewReceiveUploadController(
 	service bankSlip.ReceiveUploadServiceInterface,
+	maxUploadSize int64,
 ) *ReceiveUploadController {
-	return &ReceiveUploadController{service: service}
+	return &ReceiveUploadController{service: service, maxUploadSize: maxUploadSize}
 }
 
 func (controller *ReceiveUploadController) UploadBankSlipFileHandler(w http.ResponseWriter, r *http.Request) {
+	if controller.maxUploadSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, controller.maxUploadSize)
+	}
+
 	multpartFile, handler, err := r.FormFile("file")
+	if isUploadTooLarge(err) {
+		writeUploadTooLarge(w)
+		return
+	}
 	if err != nil {
 		log.Printf("Erro ao obter arquivo multipart: %v\n", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -27,5 +42,89 @@ func (controller *ReceiveUploadController) UploadBankSlipFileHandler(w http.Resp
 		return
 	}
 
-	controller.service.Execute(multpartFile, handler)
+	options := uploadOptionsFromRequest(r)
+	options.DuplicatePolicy = r.FormValue("duplicatePolicy")
+	options.IdempotencyKey = r.Header.Get("Idempotency-Key")
+
+	bankSlipFile, err := controller.service.Execute(multpartFile, handler, options)
+	if writeUploadInputError(w, err) {
+		return
+	}
+	if writeUploadConflict(w, err) {
+		return
+	}
+	if errors.Is(err, jobs.ErrShuttingDown) {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "Servidor em desligamento, tente novamente!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao receber arquivo: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao receber arquivo!"})
+		return
+	}
+
+	w.Header().Set("Location", BankSlipFileResourcePath+bankSlipFile.ID)
+	writeJSON(w, http.StatusAccepted, map[string]string{"id": bankSlipFile.ID})
+}
+
+func isUploadTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+func writeUploadTooLarge(w http.ResponseWriter) {
+	writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "Arquivo maior que o tamanho máximo permitido!"})
+}
+
+func uploadOptionsFromRequest(r *http.Request) bankSlipEntities.UploadOptions {
+	return bankSlipEntities.UploadOptions{
+		Profile:          r.FormValue("profile"),
+		Encoding:         r.FormValue("encoding"),
+		Delimiter:        r.FormValue("delimiter"),
+		DecimalSeparator: r.FormValue("decimalSeparator"),
+		BeneficiaryId:    r.FormValue("beneficiaryId"),
+		FixedWidthLayout: r.FormValue("fixedWidthLayout"),
+		LateFee:          r.FormValue("lateFee"),
+		Interest:         r.FormValue("interest"),
+		Discount:         r.FormValue("discount"),
+		DiscountDays:     r.FormValue("discountDays"),
+	}
+}
+
+// writeUploadInputError answers with a bad request when the upload can't be
+// read because of the file or the options sent with it.
+// writeUploadConflict answers an upload of a file already uploaded, or of an
+// idempotency key already used with another file.
+func writeUploadConflict(w http.ResponseWriter, err error) bool {
+	var duplicateUploadErr *bankSlipEntities.DuplicateUploadError
+	if errors.As(err, &duplicateUploadErr) {
+		w.Header().Set("Location", BankSlipFileResourcePath+duplicateUploadErr.ExistingFileId)
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "Arquivo já enviado!", "id": duplicateUploadErr.ExistingFileId})
+		return true
+	}
+	if errors.Is(err, bankSlipEntities.ErrIdempotencyKeyMismatch) {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Idempotency-Key já utilizada com outro arquivo!"})
+		return true
+	}
+	return false
+}
+
+func writeUploadInputError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, bankSlip.ErrHeaderNotFound):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Cabeçalho do arquivo não encontrado!"})
+	case errors.Is(err, bankSlip.ErrInvalidUploadOptions):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Opções de upload inválidas!", "details": err.Error()})
+	case errors.Is(err, bankSlipEntities.ErrUploadProfileNotFound):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Perfil de upload não encontrado!"})
+	case errors.Is(err, bankSlipEntities.ErrFixedWidthLayoutNotFound):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Leiaute de largura fixa não encontrado!"})
+	case errors.Is(err, bankSlipEntities.ErrBeneficiaryNotFound):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Beneficiário não encontrado!"})
+	case errors.Is(err, bankSlipEntities.ErrInvalidHeader):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Cabeçalho do arquivo inválido!", "details": err.Error()})
+	default:
+		return false
+	}
+	return true
 }
@@ -3,12 +3,16 @@ package bank_slip
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
 	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+	"performatic-file-processor/internal/jobs"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -26,6 +30,7 @@ func (testSuit *TestSuitReceiveUploadController) SetupTest() {
 
 	testSuit.controller = NewReceiveUploadController(
 		testSuit.receiveUploadService,
+		1024,
 	)
 }
 
@@ -48,10 +53,17 @@ func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldIgno
 	s.receiveUploadService.AssertNotCalled(s.T(), "Execute")
 }
 
-func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldCallServiceWhenFormFromFileSucceeds() {
+func newUploadRequest() *http.Request {
+	return newUploadRequestWithFields(map[string]string{})
+}
+
+func newUploadRequestWithFields(fields map[string]string) *http.Request {
 	fileContent := []byte("any_file")
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
 
 	part, _ := writer.CreateFormFile("file", "testfile.txt")
 	part.Write(fileContent)
@@ -60,11 +72,171 @@ func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldCall
 
 	req := httptest.NewRequest(http.MethodPost, "/upload", body)
 	req.Header.Set("Content-Type", writer.FormDataContentType()) // O boundary é automaticamente incluído
+	return req
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldCallServiceWhenFormFromFileSucceeds() {
+	bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.txt")
+	bankSlipFile.ID = "any_id"
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(bankSlipFile, nil)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequest())
+
+	s.receiveUploadService.AssertCalled(s.T(), "Execute", mock.Anything, mock.Anything, bankSlipEntities.UploadOptions{})
+	assert.Equal(s.T(), http.StatusAccepted, recorder.Code)
+	assert.Equal(s.T(), "/upload/bank-slip/file/any_id", recorder.Header().Get("Location"))
+	assert.JSONEq(s.T(), `{"id":"any_id"}`, recorder.Body.String())
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldReturnBadRequestWhenHeaderIsMissing() {
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, bankSlip.ErrHeaderNotFound)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequest())
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.Empty(s.T(), recorder.Header().Get("Location"))
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldReturnServiceUnavailableWhenShuttingDown() {
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, jobs.ErrShuttingDown)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequest())
+
+	assert.Equal(s.T(), http.StatusServiceUnavailable, recorder.Code)
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldReturnInternalErrorWhenServiceFails() {
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequest())
+
+	assert.Equal(s.T(), http.StatusInternalServerError, recorder.Code)
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldForwardProfileToService() {
+	bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.txt")
+	bankSlipFile.ID = "any_id"
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(bankSlipFile, nil)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequestWithFields(map[string]string{"profile": "br"}))
+
+	assert.Equal(s.T(), http.StatusAccepted, recorder.Code)
+	s.receiveUploadService.AssertCalled(s.T(), "Execute", mock.Anything, mock.Anything, bankSlipEntities.UploadOptions{Profile: "br"})
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldReturnBadRequestWhenProfileIsUnknown() {
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, bankSlipEntities.ErrUploadProfileNotFound)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequestWithFields(map[string]string{"profile": "unknown"}))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(s.T(), `{"error":"Perfil de upload não encontrado!"}`, recorder.Body.String())
+}
 
-	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything).Return(nil)
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldReturnBadRequestWhenBeneficiaryIsUnknown() {
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, bankSlipEntities.ErrBeneficiaryNotFound)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequestWithFields(map[string]string{"beneficiaryId": "beneficiary_id"}))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(s.T(), `{"error":"Beneficiário não encontrado!"}`, recorder.Body.String())
+	s.receiveUploadService.AssertCalled(s.T(), "Execute", mock.Anything, mock.Anything, bankSlipEntities.UploadOptions{BeneficiaryId: "beneficiary_id"})
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldReturnBadRequestWhenHeaderDoesNotMatchProfile() {
+	headerErr := fmt.Errorf("%w: missing columns for debtId", bankSlipEntities.ErrInvalidHeader)
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, headerErr)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequest())
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(s.T(), `{"error":"Cabeçalho do arquivo inválido!","details":"invalid header: missing columns for debtId"}`, recorder.Body.String())
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldForwardDialectOverridesToService() {
+	bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.txt")
+	bankSlipFile.ID = "any_id"
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(bankSlipFile, nil)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequestWithFields(map[string]string{
+		"encoding":         "ISO-8859-1",
+		"delimiter":        ";",
+		"decimalSeparator": ",",
+	}))
+
+	assert.Equal(s.T(), http.StatusAccepted, recorder.Code)
+	s.receiveUploadService.AssertCalled(s.T(), "Execute", mock.Anything, mock.Anything, bankSlipEntities.UploadOptions{
+		Encoding:         "ISO-8859-1",
+		Delimiter:        ";",
+		DecimalSeparator: ",",
+	})
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldReturnBadRequestWhenOptionsAreInvalid() {
+	optionsErr := fmt.Errorf("%w: unsupported encoding utf-16", bankSlip.ErrInvalidUploadOptions)
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, optionsErr)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequestWithFields(map[string]string{"encoding": "utf-16"}))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(s.T(), `{"error":"Opções de upload inválidas!","details":"invalid upload options: unsupported encoding utf-16"}`, recorder.Body.String())
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldForwardDuplicatePolicyAndIdempotencyKey() {
+	bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.txt")
+	bankSlipFile.ID = "any_id"
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(bankSlipFile, nil)
+
+	req := newUploadRequestWithFields(map[string]string{"duplicatePolicy": "force"})
+	req.Header.Set("Idempotency-Key", "retry-key")
 
 	recorder := httptest.NewRecorder()
 	s.controller.UploadBankSlipFileHandler(recorder, req)
 
-	s.receiveUploadService.AssertCalled(s.T(), "Execute", mock.Anything, mock.Anything)
+	assert.Equal(s.T(), http.StatusAccepted, recorder.Code)
+	s.receiveUploadService.AssertCalled(s.T(), "Execute", mock.Anything, mock.Anything, bankSlipEntities.UploadOptions{
+		DuplicatePolicy: "force",
+		IdempotencyKey:  "retry-key",
+	})
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldReturnConflictWhenFileWasAlreadyUploaded() {
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, &bankSlipEntities.DuplicateUploadError{ExistingFileId: "existing_id"})
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequest())
+
+	assert.Equal(s.T(), http.StatusConflict, recorder.Code)
+	assert.Equal(s.T(), "/upload/bank-slip/file/existing_id", recorder.Header().Get("Location"))
+	assert.JSONEq(s.T(), `{"error":"Arquivo já enviado!","id":"existing_id"}`, recorder.Body.String())
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldReturnUnprocessableEntityWhenIdempotencyKeyIsReused() {
+	s.receiveUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, bankSlipEntities.ErrIdempotencyKeyMismatch)
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadBankSlipFileHandler(recorder, newUploadRequest())
+
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, recorder.Code)
+}
+
+func (s *TestSuitReceiveUploadController) TestReceiveUploadController_ShouldRefuseFilesLargerThanMaxUploadSize() {
+	controller := NewReceiveUploadController(s.receiveUploadService, 10)
+	recorder := httptest.NewRecorder()
+
+	controller.UploadBankSlipFileHandler(recorder, newUploadRequest())
+
+	assert.Equal(s.T(), http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Arquivo maior que o tamanho máximo permitido!"}`, recorder.Body.String())
+	s.receiveUploadService.AssertNotCalled(s.T(), "Execute", mock.Anything, mock.Anything, mock.Anything)
 }
@@ -0,0 +1,147 @@
+package bank_slip
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+	"performatic-file-processor/internal/cnab"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+type RemittanceRequest struct {
+	BeneficiaryId string `json:"beneficiaryId"`
+	FileId        string `json:"fileId"`
+	Format        string `json:"format"`
+}
+
+type RemittanceResponse struct {
+	ID             string      `json:"id"`
+	BeneficiaryId  string      `json:"beneficiaryId"`
+	FileId         string      `json:"fileId,omitempty"`
+	Format         cnab.Format `json:"format"`
+	Sequence       int64       `json:"sequence"`
+	FileName       string      `json:"fileName"`
+	TotalBankSlips int         `json:"totalBankSlips"`
+	TotalAmount    string      `json:"totalAmount"`
+	CreatedAt      time.Time   `json:"createdAt"`
+}
+
+type RemittanceController struct {
+	createService bankSlip.CreateRemittanceServiceInterface
+	listService   bankSlip.ListRemittancesServiceInterface
+	getService    bankSlip.GetRemittanceServiceInterface
+}
+
+func NewRemittanceController(
+	createService bankSlip.CreateRemittanceServiceInterface,
+	listService bankSlip.ListRemittancesServiceInterface,
+	getService bankSlip.GetRemittanceServiceInterface,
+) *RemittanceController {
+	return &RemittanceController{
+		createService: createService,
+		listService:   listService,
+		getService:    getService,
+	}
+}
+
+// CreateRemittanceHandler writes the remittance of the issued bank slips of a
+// file, or of a beneficiary, that were not sent to the bank yet.
+func (controller *RemittanceController) CreateRemittanceHandler(w http.ResponseWriter, r *http.Request) {
+	var request RemittanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Corpo da requisição inválido!"})
+		return
+	}
+
+	remittance, err := controller.createService.Execute(request.BeneficiaryId, request.FileId, request.Format)
+	if errors.Is(err, bankSlipEntities.ErrInvalidRemittance) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Remessa inválida!", "details": err.Error()})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrBeneficiaryNotFound) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Beneficiário não encontrado!"})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrBankSlipFileNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Arquivo não encontrado!"})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrNothingToRemit) {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Nenhum boleto pendente de remessa!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao gerar remessa: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao gerar remessa!"})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newRemittanceResponse(remittance))
+}
+
+func (controller *RemittanceController) ListRemittancesHandler(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	remittances, err := controller.listService.Execute(limit, offset)
+	if err != nil {
+		log.Printf("Erro ao listar remessas: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao listar remessas!"})
+		return
+	}
+
+	response := make([]RemittanceResponse, 0, len(remittances))
+	for _, remittance := range remittances {
+		response = append(response, newRemittanceResponse(remittance))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// DownloadRemittanceHandler answers with the CNAB file to be sent to the bank,
+// the same content every time it is downloaded.
+func (controller *RemittanceController) DownloadRemittanceHandler(w http.ResponseWriter, r *http.Request) {
+	remittanceId := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	if _, err := uuid.Parse(remittanceId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id da remessa inválido!"})
+		return
+	}
+
+	remittance, err := controller.getService.Execute(remittanceId)
+	if errors.Is(err, bankSlipEntities.ErrRemittanceNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Remessa não encontrada!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao obter remessa (id: %s): %v\n", remittanceId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao obter remessa!"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", remittance.FileName()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(remittance.Content)
+}
+
+func newRemittanceResponse(remittance *bankSlipEntities.Remittance) RemittanceResponse {
+	return RemittanceResponse{
+		ID:             remittance.ID,
+		BeneficiaryId:  remittance.BeneficiaryId,
+		FileId:         remittance.BankSlipFileId,
+		Format:         remittance.Format,
+		Sequence:       remittance.Sequence,
+		FileName:       remittance.FileName(),
+		TotalBankSlips: remittance.TotalBankSlips,
+		TotalAmount:    remittance.TotalAmount.String(),
+		CreatedAt:      remittance.CreatedAt,
+	}
+}
@@ -0,0 +1,138 @@
+package bank_slip
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/cnab"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const testRemittanceId = "5a0e3c1d-2b4f-4c6a-8e9d-1f2a3b4c5d6e"
+
+type TestSuitRemittanceController struct {
+	suite.Suite
+	createService *bankSlipMocks.CreateRemittanceServiceMock
+	listService   *bankSlipMocks.ListRemittancesServiceMock
+	getService    *bankSlipMocks.GetRemittanceServiceMock
+	controller    *RemittanceController
+}
+
+func (testSuit *TestSuitRemittanceController) SetupTest() {
+	testSuit.createService = new(bankSlipMocks.CreateRemittanceServiceMock)
+	testSuit.listService = new(bankSlipMocks.ListRemittancesServiceMock)
+	testSuit.getService = new(bankSlipMocks.GetRemittanceServiceMock)
+
+	testSuit.controller = NewRemittanceController(
+		testSuit.createService,
+		testSuit.listService,
+		testSuit.getService,
+	)
+}
+
+func TestRemittanceController(t *testing.T) {
+	suite.Run(t, new(TestSuitRemittanceController))
+}
+
+func newCreateRemittanceRequest() *http.Request {
+	body, _ := json.Marshal(RemittanceRequest{BeneficiaryId: "beneficiary_id", Format: "CNAB240"})
+	return httptest.NewRequest(http.MethodPost, "/remittances", bytes.NewBuffer(body))
+}
+
+func newTestRemittance() *bankSlipEntities.Remittance {
+	remittance := bankSlipEntities.NewRemittance("beneficiary_id", "", cnab.CNAB240)
+	remittance.ID = testRemittanceId
+	remittance.Numbered(3, []*bankSlipEntities.BankSlip{{DebtAmount: 100050}, {DebtAmount: 50}})
+	remittance.Content = []byte("header\r\ntrailer\r\n")
+	return remittance
+}
+
+func (s *TestSuitRemittanceController) TestCreateRemittanceHandler_ShouldCreateRemittance() {
+	s.createService.On("Execute", "beneficiary_id", "", "CNAB240").Return(newTestRemittance(), nil).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateRemittanceHandler(recorder, newCreateRemittanceRequest())
+
+	assert.Equal(s.T(), http.StatusCreated, recorder.Code)
+	var response RemittanceResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(s.T(), testRemittanceId, response.ID)
+	assert.Equal(s.T(), int64(3), response.Sequence)
+	assert.Equal(s.T(), "remessa-cnab240-000003.rem", response.FileName)
+	assert.Equal(s.T(), 2, response.TotalBankSlips)
+	assert.Equal(s.T(), "1001.00", response.TotalAmount)
+}
+
+func (s *TestSuitRemittanceController) TestCreateRemittanceHandler_ShouldReturnBadRequestWhenBodyIsInvalid() {
+	recorder := httptest.NewRecorder()
+	s.controller.CreateRemittanceHandler(recorder, httptest.NewRequest(http.MethodPost, "/remittances", bytes.NewBufferString("{")))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.createService.AssertNotCalled(s.T(), "Execute")
+}
+
+func (s *TestSuitRemittanceController) TestCreateRemittanceHandler_ShouldMapServiceErrors() {
+	for err, status := range map[error]int{
+		bankSlipEntities.ErrInvalidRemittance:    http.StatusBadRequest,
+		bankSlipEntities.ErrBeneficiaryNotFound:  http.StatusBadRequest,
+		bankSlipEntities.ErrBankSlipFileNotFound: http.StatusNotFound,
+		bankSlipEntities.ErrNothingToRemit:       http.StatusUnprocessableEntity,
+		assert.AnError:                           http.StatusInternalServerError,
+	} {
+		s.createService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, err).Once()
+
+		recorder := httptest.NewRecorder()
+		s.controller.CreateRemittanceHandler(recorder, newCreateRemittanceRequest())
+
+		assert.Equal(s.T(), status, recorder.Code, err.Error())
+	}
+}
+
+func (s *TestSuitRemittanceController) TestListRemittancesHandler_ShouldListRemittances() {
+	s.listService.On("Execute", 10, 20).Return([]*bankSlipEntities.Remittance{newTestRemittance()}, nil).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.ListRemittancesHandler(recorder, httptest.NewRequest(http.MethodGet, "/remittances?limit=10&offset=20", nil))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	var response []RemittanceResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Len(s.T(), response, 1)
+	assert.Equal(s.T(), testRemittanceId, response[0].ID)
+}
+
+func (s *TestSuitRemittanceController) TestDownloadRemittanceHandler_ShouldDownloadFile() {
+	s.getService.On("Execute", testRemittanceId).Return(newTestRemittance(), nil).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.DownloadRemittanceHandler(recorder, newRequestWithId(testRemittanceId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Equal(s.T(), `attachment; filename="remessa-cnab240-000003.rem"`, recorder.Header().Get("Content-Disposition"))
+	assert.Equal(s.T(), "header\r\ntrailer\r\n", recorder.Body.String())
+}
+
+func (s *TestSuitRemittanceController) TestDownloadRemittanceHandler_ShouldReturnNotFound() {
+	s.getService.On("Execute", testRemittanceId).Return(nil, bankSlipEntities.ErrRemittanceNotFound).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.DownloadRemittanceHandler(recorder, newRequestWithId(testRemittanceId))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *TestSuitRemittanceController) TestDownloadRemittanceHandler_ShouldReturnBadRequestWhenIdIsInvalid() {
+	recorder := httptest.NewRecorder()
+	s.controller.DownloadRemittanceHandler(recorder, newRequestWithId("any_id"))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.getService.AssertNotCalled(s.T(), "Execute")
+}
@@ -0,0 +1,173 @@
+package bank_slip
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+	"performatic-file-processor/internal/cnab"
+	"performatic-file-processor/internal/jobs"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+const ReturnFileResourcePath = "/upload/bank-slip/return/"
+
+type ReturnFileResponse struct {
+	ID                string                              `json:"id"`
+	FileName          string                              `json:"fileName"`
+	BeneficiaryId     string                              `json:"beneficiaryId"`
+	Format            cnab.Format                         `json:"format,omitempty"`
+	BankCode          string                              `json:"bankCode,omitempty"`
+	Status            bankSlipEntities.BankSlipFileStatus `json:"status"`
+	TotalRecords      int                                 `json:"totalRecords"`
+	ProcessedRecords  int                                 `json:"processedRecords"`
+	ReconciledRecords int                                 `json:"reconciledRecords"`
+	UnmatchedRecords  int                                 `json:"unmatchedRecords"`
+	CreatedAt         time.Time                           `json:"createdAt"`
+}
+
+type UnmatchedReturnRecordResponse struct {
+	LineNumber     int                              `json:"lineNumber"`
+	OccurrenceCode string                           `json:"occurrenceCode"`
+	OurNumber      int64                            `json:"ourNumber,omitempty"`
+	ControlNumber  string                           `json:"controlNumber,omitempty"`
+	Reason         bankSlipEntities.UnmatchedReason `json:"reason"`
+	RawRecord      string                           `json:"rawRecord"`
+}
+
+type ReturnFileController struct {
+	receiveService          bankSlip.ReceiveReturnFileServiceInterface
+	getService              bankSlip.GetReturnFileServiceInterface
+	unmatchedRecordsService bankSlip.GetUnmatchedReturnRecordsServiceInterface
+	maxUploadSize           int64
+}
+
+func NewReturnFileController(
+	receiveService bankSlip.ReceiveReturnFileServiceInterface,
+	getService bankSlip.GetReturnFileServiceInterface,
+	unmatchedRecordsService bankSlip.GetUnmatchedReturnRecordsServiceInterface,
+	maxUploadSize int64,
+) *ReturnFileController {
+	return &ReturnFileController{
+		receiveService:          receiveService,
+		getService:              getService,
+		unmatchedRecordsService: unmatchedRecordsService,
+		maxUploadSize:           maxUploadSize,
+	}
+}
+
+// UploadReturnFileHandler receives a CNAB return file of a beneficiary, its
+// records are reconciled with the bank slips in background.
+func (controller *ReturnFileController) UploadReturnFileHandler(w http.ResponseWriter, r *http.Request) {
+	if controller.maxUploadSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, controller.maxUploadSize)
+	}
+
+	multipartFile, handler, err := r.FormFile("file")
+	if isUploadTooLarge(err) {
+		writeUploadTooLarge(w)
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao obter arquivo multipart: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao obter arquivo!"})
+		return
+	}
+
+	returnFile, err := controller.receiveService.Execute(multipartFile, handler, r.FormValue("beneficiaryId"))
+	if writeUploadInputError(w, err) {
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrInvalidReturnFile) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Arquivo de retorno inválido!", "details": err.Error()})
+		return
+	}
+	if errors.Is(err, jobs.ErrShuttingDown) {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "Servidor em desligamento, tente novamente!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao receber arquivo de retorno: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao receber arquivo de retorno!"})
+		return
+	}
+
+	w.Header().Set("Location", ReturnFileResourcePath+returnFile.ID)
+	writeJSON(w, http.StatusAccepted, map[string]string{"id": returnFile.ID})
+}
+
+func (controller *ReturnFileController) GetReturnFileHandler(w http.ResponseWriter, r *http.Request) {
+	returnFileId := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	if _, err := uuid.Parse(returnFileId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id do arquivo inválido!"})
+		return
+	}
+
+	returnFile, err := controller.getService.Execute(returnFileId)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipReturnFileNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Arquivo de retorno não encontrado!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao obter arquivo de retorno (id: %s): %v\n", returnFileId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao obter arquivo de retorno!"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReturnFileResponse(returnFile))
+}
+
+// ListUnmatchedRecordsHandler answers with the records of the return file
+// that moved no bank slip, and why, so they can be checked by hand.
+func (controller *ReturnFileController) ListUnmatchedRecordsHandler(w http.ResponseWriter, r *http.Request) {
+	returnFileId := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	if _, err := uuid.Parse(returnFileId); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id do arquivo inválido!"})
+		return
+	}
+
+	_, unmatchedRecords, err := controller.unmatchedRecordsService.Execute(returnFileId)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipReturnFileNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Arquivo de retorno não encontrado!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao obter registros não conciliados (id: %s): %v\n", returnFileId, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao obter registros não conciliados!"})
+		return
+	}
+
+	response := make([]UnmatchedReturnRecordResponse, 0, len(unmatchedRecords))
+	for _, record := range unmatchedRecords {
+		response = append(response, UnmatchedReturnRecordResponse{
+			LineNumber:     record.LineNumber,
+			OccurrenceCode: record.OccurrenceCode,
+			OurNumber:      record.OurNumber,
+			ControlNumber:  record.ControlNumber,
+			Reason:         record.Reason,
+			RawRecord:      record.RawRecord,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func newReturnFileResponse(returnFile *bankSlipEntities.BankSlipReturnFile) ReturnFileResponse {
+	return ReturnFileResponse{
+		ID:                returnFile.ID,
+		FileName:          returnFile.FileName,
+		BeneficiaryId:     returnFile.BeneficiaryId,
+		Format:            returnFile.Format,
+		BankCode:          returnFile.BankCode,
+		Status:            returnFile.Status,
+		TotalRecords:      returnFile.TotalRecords,
+		ProcessedRecords:  returnFile.ProcessedRecords,
+		ReconciledRecords: returnFile.ReconciledRecords,
+		UnmatchedRecords:  returnFile.UnmatchedRecords,
+		CreatedAt:         returnFile.CreatedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package bank_slip
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/jobs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testReturnFileId        = "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+	testReturnBeneficiaryId = "a1b2c3d4-0000-4000-8000-000000000003"
+)
+
+type TestSuitReturnFileController struct {
+	suite.Suite
+	receiveService          *bankSlipMocks.ReceiveReturnFileServiceMock
+	getService              *bankSlipMocks.GetReturnFileServiceMock
+	unmatchedRecordsService *bankSlipMocks.GetUnmatchedReturnRecordsServiceMock
+	controller              *ReturnFileController
+}
+
+func (testSuit *TestSuitReturnFileController) SetupTest() {
+	testSuit.receiveService = new(bankSlipMocks.ReceiveReturnFileServiceMock)
+	testSuit.getService = new(bankSlipMocks.GetReturnFileServiceMock)
+	testSuit.unmatchedRecordsService = new(bankSlipMocks.GetUnmatchedReturnRecordsServiceMock)
+
+	testSuit.controller = NewReturnFileController(
+		testSuit.receiveService,
+		testSuit.getService,
+		testSuit.unmatchedRecordsService,
+		1024,
+	)
+}
+
+func TestReturnFileController(t *testing.T) {
+	suite.Run(t, new(TestSuitReturnFileController))
+}
+
+func newTestReturnFile() *bankSlipEntities.BankSlipReturnFile {
+	returnFile := bankSlipEntities.NewBankSlipReturnFile("retorno.ret", testReturnBeneficiaryId)
+	returnFile.ID = testReturnFileId
+	return returnFile
+}
+
+func (s *TestSuitReturnFileController) TestUploadReturnFileHandler_ShouldAcceptReturnFile() {
+	s.receiveService.On("Execute", mock.Anything, mock.Anything, testReturnBeneficiaryId).Return(newTestReturnFile(), nil).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadReturnFileHandler(recorder, newUploadRequestWithFields(map[string]string{"beneficiaryId": testReturnBeneficiaryId}))
+
+	assert.Equal(s.T(), http.StatusAccepted, recorder.Code)
+	assert.Equal(s.T(), ReturnFileResourcePath+testReturnFileId, recorder.Header().Get("Location"))
+	assert.JSONEq(s.T(), fmt.Sprintf(`{"id":%q}`, testReturnFileId), recorder.Body.String())
+}
+
+func (s *TestSuitReturnFileController) TestUploadReturnFileHandler_ShouldReturnBadRequestWhenReturnFileIsInvalid() {
+	err := fmt.Errorf("%w: file of bank 341 for a beneficiary of bank 001", bankSlipEntities.ErrInvalidReturnFile)
+	s.receiveService.On("Execute", mock.Anything, mock.Anything, testReturnBeneficiaryId).Return(nil, err).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadReturnFileHandler(recorder, newUploadRequestWithFields(map[string]string{"beneficiaryId": testReturnBeneficiaryId}))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	var response map[string]string
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(s.T(), "Arquivo de retorno inválido!", response["error"])
+	assert.Contains(s.T(), response["details"], "bank 341")
+}
+
+func (s *TestSuitReturnFileController) TestUploadReturnFileHandler_ShouldReturnBadRequestWhenBeneficiaryIsNotFound() {
+	s.receiveService.On("Execute", mock.Anything, mock.Anything, testReturnBeneficiaryId).Return(nil, bankSlipEntities.ErrBeneficiaryNotFound).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadReturnFileHandler(recorder, newUploadRequestWithFields(map[string]string{"beneficiaryId": testReturnBeneficiaryId}))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(s.T(), `{"error":"Beneficiário não encontrado!"}`, recorder.Body.String())
+}
+
+func (s *TestSuitReturnFileController) TestUploadReturnFileHandler_ShouldReturnServiceUnavailableWhenShuttingDown() {
+	s.receiveService.On("Execute", mock.Anything, mock.Anything, "").Return(nil, jobs.ErrShuttingDown).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.UploadReturnFileHandler(recorder, newUploadRequest())
+
+	assert.Equal(s.T(), http.StatusServiceUnavailable, recorder.Code)
+}
+
+func (s *TestSuitReturnFileController) TestGetReturnFileHandler_ShouldReturnReturnFile() {
+	returnFile := newTestReturnFile()
+	returnFile.Processing(4)
+	returnFile.ProcessedRecords = 4
+	returnFile.ReconciledRecords = 2
+	returnFile.UnmatchedRecords = 1
+	s.getService.On("Execute", testReturnFileId).Return(returnFile, nil).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.GetReturnFileHandler(recorder, newRequestWithId(testReturnFileId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	var response ReturnFileResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(s.T(), testReturnFileId, response.ID)
+	assert.Equal(s.T(), 4, response.TotalRecords)
+	assert.Equal(s.T(), 2, response.ReconciledRecords)
+	assert.Equal(s.T(), 1, response.UnmatchedRecords)
+}
+
+func (s *TestSuitReturnFileController) TestGetReturnFileHandler_ShouldReturnNotFound() {
+	s.getService.On("Execute", testReturnFileId).Return(nil, bankSlipEntities.ErrBankSlipReturnFileNotFound).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.GetReturnFileHandler(recorder, newRequestWithId(testReturnFileId))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *TestSuitReturnFileController) TestGetReturnFileHandler_ShouldReturnBadRequestWhenIdIsInvalid() {
+	recorder := httptest.NewRecorder()
+	s.controller.GetReturnFileHandler(recorder, newRequestWithId("invalid"))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.getService.AssertNotCalled(s.T(), "Execute")
+}
+
+func (s *TestSuitReturnFileController) TestListUnmatchedRecordsHandler_ShouldReturnUnmatchedRecords() {
+	unmatchedRecords := []*bankSlipEntities.UnmatchedReturnRecord{
+		{ReturnFileId: testReturnFileId, LineNumber: 3, OccurrenceCode: "06", OurNumber: 42, Reason: bankSlipEntities.UnmatchedReasonBankSlipNotFound, RawRecord: "raw"},
+	}
+	s.unmatchedRecordsService.On("Execute", testReturnFileId).Return(newTestReturnFile(), unmatchedRecords, nil).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.ListUnmatchedRecordsHandler(recorder, newRequestWithId(testReturnFileId))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.JSONEq(s.T(), `[{"lineNumber":3,"occurrenceCode":"06","ourNumber":42,"reason":"BANK_SLIP_NOT_FOUND","rawRecord":"raw"}]`, recorder.Body.String())
+}
+
+func (s *TestSuitReturnFileController) TestListUnmatchedRecordsHandler_ShouldReturnNotFound() {
+	s.unmatchedRecordsService.On("Execute", testReturnFileId).Return(nil, nil, bankSlipEntities.ErrBankSlipReturnFileNotFound).Once()
+
+	recorder := httptest.NewRecorder()
+	s.controller.ListUnmatchedRecordsHandler(recorder, newRequestWithId(testReturnFileId))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,124 @@
+package bank_slip
+
+import (
+	"context"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+)
+
+const maxFormValueSize = 1024
+
+type StreamUploadController struct {
+	service       bankSlip.StreamUploadServiceInterface
+	maxUploadSize int64
+}
+
+func NewStreamUploadController(
+	service bankSlip.StreamUploadServiceInterface,
+	maxUploadSize int64,
+) *StreamUploadController {
+	return &StreamUploadController{service: service, maxUploadSize: maxUploadSize}
+}
+
+// StreamBankSlipFileHandler reads the multipart body part by part and hands the
+// file part to the service as it arrives. Form fields are only taken into
+// account when they come before the file.
+func (controller *StreamUploadController) StreamBankSlipFileHandler(w http.ResponseWriter, r *http.Request) {
+	if controller.maxUploadSize > 0 {
+		if r.ContentLength > controller.maxUploadSize {
+			writeUploadTooLarge(w)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, controller.maxUploadSize)
+	}
+	// Big files take longer than the server read timeout to arrive.
+	http.NewResponseController(w).SetReadDeadline(time.Time{})
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Requisição multipart inválida!"})
+		return
+	}
+
+	options := bankSlipEntities.UploadOptions{IdempotencyKey: r.Header.Get("Idempotency-Key")}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Erro ao obter arquivo!"})
+			return
+		}
+		if isUploadTooLarge(err) {
+			writeUploadTooLarge(w)
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Requisição multipart inválida!"})
+			return
+		}
+
+		if part.FormName() == "file" {
+			controller.streamFile(w, r, part, options)
+			return
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxFormValueSize))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Requisição multipart inválida!"})
+			return
+		}
+		setUploadOption(&options, part.FormName(), string(value))
+	}
+}
+
+func (controller *StreamUploadController) streamFile(w http.ResponseWriter, r *http.Request, part *multipart.Part, options bankSlipEntities.UploadOptions) {
+	// Chunks already published must not be cut short if the client goes away.
+	bankSlipFile, err := controller.service.Execute(context.WithoutCancel(r.Context()), part, part.FileName(), options)
+	if isUploadTooLarge(err) {
+		writeUploadTooLarge(w)
+		return
+	}
+	if writeUploadInputError(w, err) || writeUploadConflict(w, err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao receber arquivo: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao receber arquivo!"})
+		return
+	}
+
+	w.Header().Set("Location", BankSlipFileResourcePath+bankSlipFile.ID)
+	writeJSON(w, http.StatusAccepted, map[string]string{"id": bankSlipFile.ID})
+}
+
+func setUploadOption(options *bankSlipEntities.UploadOptions, name string, value string) {
+	switch name {
+	case "profile":
+		options.Profile = value
+	case "encoding":
+		options.Encoding = value
+	case "delimiter":
+		options.Delimiter = value
+	case "decimalSeparator":
+		options.DecimalSeparator = value
+	case "duplicatePolicy":
+		options.DuplicatePolicy = value
+	case "beneficiaryId":
+		options.BeneficiaryId = value
+	case "fixedWidthLayout":
+		options.FixedWidthLayout = value
+	case "lateFee":
+		options.LateFee = value
+	case "interest":
+		options.Interest = value
+	case "discount":
+		options.Discount = value
+	case "discountDays":
+		options.DiscountDays = value
+	}
+}
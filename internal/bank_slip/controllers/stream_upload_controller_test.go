@@ -0,0 +1,157 @@
+package bank_slip
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitStreamUploadController struct {
+	suite.Suite
+	streamUploadService *bankSlipMocks.StreamUploadServiceMock
+	controller          *StreamUploadController
+}
+
+func (testSuit *TestSuitStreamUploadController) SetupTest() {
+	testSuit.streamUploadService = new(bankSlipMocks.StreamUploadServiceMock)
+
+	testSuit.controller = NewStreamUploadController(
+		testSuit.streamUploadService,
+		1024,
+	)
+}
+
+func TestStreamUploadController(t *testing.T) {
+	suite.Run(t, new(TestSuitStreamUploadController))
+}
+
+func newStreamUploadRequest(fields map[string]string, withFile bool) *http.Request {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	if withFile {
+		part, _ := writer.CreateFormFile("file", "testfile.csv")
+		part.Write([]byte("any_file"))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload/bank-slip/file/stream", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func (s *TestSuitStreamUploadController) TestStreamUploadController_ShouldStreamFilePartWithFieldsSentBeforeIt() {
+	bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.csv")
+	bankSlipFile.ID = "any_id"
+	var content []byte
+	s.streamUploadService.On("Execute", mock.Anything, mock.Anything, "testfile.csv", bankSlipEntities.UploadOptions{
+		Profile:        "br",
+		Delimiter:      ";",
+		IdempotencyKey: "retry-key",
+		BeneficiaryId:  "beneficiary_id",
+	}).Run(func(args mock.Arguments) {
+		content, _ = io.ReadAll(args.Get(1).(io.Reader))
+	}).Return(bankSlipFile, nil).Once()
+
+	req := newStreamUploadRequest(map[string]string{"profile": "br", "delimiter": ";", "beneficiaryId": "beneficiary_id"}, true)
+	req.Header.Set("Idempotency-Key", "retry-key")
+	recorder := httptest.NewRecorder()
+
+	s.controller.StreamBankSlipFileHandler(recorder, req)
+
+	assert.Equal(s.T(), http.StatusAccepted, recorder.Code)
+	assert.Equal(s.T(), "/upload/bank-slip/file/any_id", recorder.Header().Get("Location"))
+	assert.JSONEq(s.T(), `{"id": "any_id"}`, recorder.Body.String())
+	assert.Equal(s.T(), "any_file", string(content))
+	s.streamUploadService.AssertExpectations(s.T())
+}
+
+func (s *TestSuitStreamUploadController) TestStreamUploadController_ShouldRefuseRequestWithoutFile() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.StreamBankSlipFileHandler(recorder, newStreamUploadRequest(map[string]string{"profile": "br"}, false))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	expectedErrorResponse, _ := json.Marshal(map[string]string{"error": "Erro ao obter arquivo!"})
+	assert.JSONEq(s.T(), string(expectedErrorResponse), recorder.Body.String())
+	s.streamUploadService.AssertNotCalled(s.T(), "Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TestSuitStreamUploadController) TestStreamUploadController_ShouldRefuseRequestThatIsNotMultipart() {
+	req := httptest.NewRequest(http.MethodPost, "/upload/bank-slip/file/stream", bytes.NewBufferString("any_file"))
+	recorder := httptest.NewRecorder()
+
+	s.controller.StreamBankSlipFileHandler(recorder, req)
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (s *TestSuitStreamUploadController) TestStreamUploadController_ShouldRefuseDeclaredLengthLargerThanMaxUploadSize() {
+	controller := NewStreamUploadController(s.streamUploadService, 10)
+	recorder := httptest.NewRecorder()
+
+	controller.StreamBankSlipFileHandler(recorder, newStreamUploadRequest(map[string]string{}, true))
+
+	assert.Equal(s.T(), http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Arquivo maior que o tamanho máximo permitido!"}`, recorder.Body.String())
+}
+
+func (s *TestSuitStreamUploadController) TestStreamUploadController_ShouldRefuseBodyLargerThanMaxUploadSize() {
+	controller := NewStreamUploadController(s.streamUploadService, 10)
+	s.streamUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		io.ReadAll(args.Get(1).(io.Reader))
+	}).Return(nil, &http.MaxBytesError{Limit: 10}).Maybe()
+
+	req := newStreamUploadRequest(map[string]string{}, true)
+	req.ContentLength = -1
+	recorder := httptest.NewRecorder()
+
+	controller.StreamBankSlipFileHandler(recorder, req)
+
+	assert.Equal(s.T(), http.StatusRequestEntityTooLarge, recorder.Code)
+}
+
+func (s *TestSuitStreamUploadController) TestStreamUploadController_ShouldReturnBadRequestWhenUploadOptionsAreInvalid() {
+	s.streamUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, bankSlip.ErrInvalidUploadOptions).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.StreamBankSlipFileHandler(recorder, newStreamUploadRequest(map[string]string{"encoding": "EBCDIC"}, true))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (s *TestSuitStreamUploadController) TestStreamUploadController_ShouldReturnConflictWhenFileWasAlreadyUploaded() {
+	s.streamUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything, bankSlipEntities.UploadOptions{DuplicatePolicy: "reject"}).
+		Return(nil, &bankSlipEntities.DuplicateUploadError{ExistingFileId: "existing_id"}).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.StreamBankSlipFileHandler(recorder, newStreamUploadRequest(map[string]string{"duplicatePolicy": "reject"}, true))
+
+	assert.Equal(s.T(), http.StatusConflict, recorder.Code)
+	assert.Equal(s.T(), "/upload/bank-slip/file/existing_id", recorder.Header().Get("Location"))
+	assert.JSONEq(s.T(), `{"error":"Arquivo já enviado!","id":"existing_id"}`, recorder.Body.String())
+}
+
+func (s *TestSuitStreamUploadController) TestStreamUploadController_ShouldReturnInternalServerErrorWhenServiceFails() {
+	s.streamUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.StreamBankSlipFileHandler(recorder, newStreamUploadRequest(map[string]string{}, true))
+
+	assert.Equal(s.T(), http.StatusInternalServerError, recorder.Code)
+	assert.JSONEq(s.T(), `{"error": "Erro ao receber arquivo!"}`, recorder.Body.String())
+}
@@ -0,0 +1,270 @@
+package bank_slip
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+// SubscriptionTermsRequest are the terms a subscription is created or updated
+// with, dates written as "2026-11-01".
+type SubscriptionTermsRequest struct {
+	UserName     string `json:"userName"`
+	GovernmentId string `json:"governmentId"`
+	UserEmail    string `json:"userEmail"`
+	// Amount is written as "150.00".
+	Amount     string `json:"amount"`
+	DayOfMonth int    `json:"dayOfMonth"`
+	// EndDate is optional, the subscription never ends without it.
+	EndDate      string                        `json:"endDate"`
+	ChargePolicy bankSlipEntities.ChargePolicy `json:"chargePolicy"`
+}
+
+type CreateSubscriptionRequest struct {
+	BeneficiaryId string `json:"beneficiaryId"`
+	StartDate     string `json:"startDate"`
+	SubscriptionTermsRequest
+}
+
+type SubscriptionResponse struct {
+	ID            string                              `json:"id"`
+	BeneficiaryId string                              `json:"beneficiaryId"`
+	UserName      string                              `json:"userName"`
+	GovernmentId  string                              `json:"governmentId"`
+	UserEmail     string                              `json:"userEmail"`
+	Amount        string                              `json:"amount"`
+	DayOfMonth    int                                 `json:"dayOfMonth"`
+	StartDate     string                              `json:"startDate"`
+	EndDate       string                              `json:"endDate,omitempty"`
+	ChargePolicy  bankSlipEntities.ChargePolicy       `json:"chargePolicy"`
+	Status        bankSlipEntities.SubscriptionStatus `json:"status"`
+	NextDueDate   string                              `json:"nextDueDate,omitempty"`
+	CreatedAt     time.Time                           `json:"createdAt"`
+	UpdatedAt     time.Time                           `json:"updatedAt"`
+}
+
+type SubscriptionController struct {
+	createService bankSlip.CreateSubscriptionServiceInterface
+	listService   bankSlip.ListSubscriptionsServiceInterface
+	getService    bankSlip.GetSubscriptionServiceInterface
+	updateService bankSlip.UpdateSubscriptionServiceInterface
+	cancelService bankSlip.CancelSubscriptionServiceInterface
+}
+
+func NewSubscriptionController(
+	createService bankSlip.CreateSubscriptionServiceInterface,
+	listService bankSlip.ListSubscriptionsServiceInterface,
+	getService bankSlip.GetSubscriptionServiceInterface,
+	updateService bankSlip.UpdateSubscriptionServiceInterface,
+	cancelService bankSlip.CancelSubscriptionServiceInterface,
+) *SubscriptionController {
+	return &SubscriptionController{
+		createService: createService,
+		listService:   listService,
+		getService:    getService,
+		updateService: updateService,
+		cancelService: cancelService,
+	}
+}
+
+func (controller *SubscriptionController) CreateSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	var request CreateSubscriptionRequest
+	if !decodeSubscriptionRequest(w, r, &request) {
+		return
+	}
+	startDate, err := time.Parse(time.DateOnly, request.StartDate)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Data inválida!"})
+		return
+	}
+	terms, ok := subscriptionTerms(w, request.SubscriptionTermsRequest)
+	if !ok {
+		return
+	}
+
+	subscription, err := controller.createService.Execute(request.BeneficiaryId, startDate, terms)
+	if errors.Is(err, bankSlipEntities.ErrBeneficiaryNotFound) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Beneficiário não encontrado!"})
+		return
+	}
+	if err != nil {
+		writeSubscriptionError(w, err, "criar assinatura")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newSubscriptionResponse(subscription))
+}
+
+// ListSubscriptionsHandler lists the subscriptions, of the beneficiary given
+// in the beneficiaryId query parameter when there's one.
+func (controller *SubscriptionController) ListSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	beneficiaryId := r.URL.Query().Get("beneficiaryId")
+	if beneficiaryId != "" {
+		if _, err := uuid.Parse(beneficiaryId); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id do beneficiário inválido!"})
+			return
+		}
+	}
+
+	subscriptions, err := controller.listService.Execute(beneficiaryId)
+	if err != nil {
+		log.Printf("Erro ao listar assinaturas: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao listar assinaturas!"})
+		return
+	}
+
+	response := make([]SubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, newSubscriptionResponse(subscription))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (controller *SubscriptionController) GetSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := subscriptionId(w, r)
+	if !ok {
+		return
+	}
+
+	subscription, err := controller.getService.Execute(id)
+	if err != nil {
+		writeSubscriptionError(w, err, "buscar assinatura")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newSubscriptionResponse(subscription))
+}
+
+// UpdateSubscriptionHandler replaces the terms of an active subscription, the
+// bank slips already issued keep theirs.
+func (controller *SubscriptionController) UpdateSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := subscriptionId(w, r)
+	if !ok {
+		return
+	}
+	var request SubscriptionTermsRequest
+	if !decodeSubscriptionRequest(w, r, &request) {
+		return
+	}
+	terms, ok := subscriptionTerms(w, request)
+	if !ok {
+		return
+	}
+
+	subscription, err := controller.updateService.Execute(id, terms)
+	if err != nil {
+		writeSubscriptionError(w, err, "atualizar assinatura")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newSubscriptionResponse(subscription))
+}
+
+// CancelSubscriptionHandler cancels the subscription, which is kept with the
+// bank slips it issued.
+func (controller *SubscriptionController) CancelSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := subscriptionId(w, r)
+	if !ok {
+		return
+	}
+
+	subscription, err := controller.cancelService.Execute(id)
+	if err != nil {
+		writeSubscriptionError(w, err, "cancelar assinatura")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newSubscriptionResponse(subscription))
+}
+
+func subscriptionId(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	if _, err := uuid.Parse(id); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Id da assinatura inválido!"})
+		return "", false
+	}
+	return id, true
+}
+
+func decodeSubscriptionRequest(w http.ResponseWriter, r *http.Request, request any) bool {
+	err := json.NewDecoder(r.Body).Decode(request)
+	if errors.Is(err, bankSlipEntities.ErrInvalidChargePolicy) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Assinatura inválida!", "details": err.Error()})
+		return false
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Corpo da requisição inválido!"})
+		return false
+	}
+	return true
+}
+
+func subscriptionTerms(w http.ResponseWriter, request SubscriptionTermsRequest) (bankSlipEntities.SubscriptionTerms, bool) {
+	endDate := time.Time{}
+	if request.EndDate != "" {
+		parsed, err := time.Parse(time.DateOnly, request.EndDate)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Data inválida!"})
+			return bankSlipEntities.SubscriptionTerms{}, false
+		}
+		endDate = parsed
+	}
+	return bankSlipEntities.SubscriptionTerms{
+		UserName:     request.UserName,
+		GovernmentId: request.GovernmentId,
+		UserEmail:    request.UserEmail,
+		Amount:       request.Amount,
+		DayOfMonth:   request.DayOfMonth,
+		EndDate:      endDate,
+		ChargePolicy: request.ChargePolicy,
+	}, true
+}
+
+// writeSubscriptionError answers with the status of the error of action, as
+// "criar assinatura".
+func writeSubscriptionError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, bankSlipEntities.ErrInvalidSubscription):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Assinatura inválida!", "details": err.Error()})
+	case errors.Is(err, bankSlipEntities.ErrSubscriptionNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Assinatura não encontrada!"})
+	case errors.Is(err, bankSlipEntities.ErrSubscriptionNotActive):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "Assinatura não está ativa!", "details": err.Error()})
+	default:
+		log.Printf("Erro ao %s: %v\n", action, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Erro ao %s!", action)})
+	}
+}
+
+func newSubscriptionResponse(subscription *bankSlipEntities.Subscription) SubscriptionResponse {
+	response := SubscriptionResponse{
+		ID:            subscription.ID,
+		BeneficiaryId: subscription.BeneficiaryId,
+		UserName:      subscription.UserName,
+		GovernmentId:  subscription.GovernmentId.Formatted(),
+		UserEmail:     subscription.UserEmail,
+		Amount:        subscription.Amount.String(),
+		DayOfMonth:    subscription.DayOfMonth,
+		StartDate:     subscription.StartDate.Format(time.DateOnly),
+		ChargePolicy:  subscription.ChargePolicy,
+		Status:        subscription.Status,
+		CreatedAt:     subscription.CreatedAt,
+		UpdatedAt:     subscription.UpdatedAt,
+	}
+	if !subscription.EndDate.IsZero() {
+		response.EndDate = subscription.EndDate.Format(time.DateOnly)
+	}
+	if !subscription.NextDueDate.IsZero() {
+		response.NextDueDate = subscription.NextDueDate.Format(time.DateOnly)
+	}
+	return response
+}
@@ -0,0 +1,172 @@
+package bank_slip
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testSubscriptionId            = "6b1f9c2e-3d4a-4f5b-8c6d-7e8f9a0b1c2d"
+	testSubscriptionBeneficiaryId = "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+)
+
+type TestSuitSubscriptionController struct {
+	suite.Suite
+	createService *bankSlipMocks.CreateSubscriptionServiceMock
+	listService   *bankSlipMocks.ListSubscriptionsServiceMock
+	getService    *bankSlipMocks.GetSubscriptionServiceMock
+	updateService *bankSlipMocks.UpdateSubscriptionServiceMock
+	cancelService *bankSlipMocks.CancelSubscriptionServiceMock
+	controller    *SubscriptionController
+}
+
+func (testSuit *TestSuitSubscriptionController) SetupTest() {
+	testSuit.createService = new(bankSlipMocks.CreateSubscriptionServiceMock)
+	testSuit.listService = new(bankSlipMocks.ListSubscriptionsServiceMock)
+	testSuit.getService = new(bankSlipMocks.GetSubscriptionServiceMock)
+	testSuit.updateService = new(bankSlipMocks.UpdateSubscriptionServiceMock)
+	testSuit.cancelService = new(bankSlipMocks.CancelSubscriptionServiceMock)
+	testSuit.controller = NewSubscriptionController(
+		testSuit.createService,
+		testSuit.listService,
+		testSuit.getService,
+		testSuit.updateService,
+		testSuit.cancelService,
+	)
+}
+
+func TestSubscriptionController(t *testing.T) {
+	suite.Run(t, new(TestSuitSubscriptionController))
+}
+
+func newSubscriptionRequest(method, id, body string) *http.Request {
+	req := httptest.NewRequest(method, "/subscriptions/"+id, strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: id}})
+	return req.WithContext(ctx)
+}
+
+func newTestSubscription() *bankSlipEntities.Subscription {
+	return &bankSlipEntities.Subscription{
+		ID:            testSubscriptionId,
+		BeneficiaryId: testSubscriptionBeneficiaryId,
+		UserName:      "John Doe",
+		GovernmentId:  "52998224725",
+		UserEmail:     "john.doe@example.com",
+		Amount:        15000,
+		DayOfMonth:    10,
+		StartDate:     time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		Status:        bankSlipEntities.SubscriptionStatusActive,
+		NextPeriod:    time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		NextDueDate:   time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (s *TestSuitSubscriptionController) TestSubscriptionController_ShouldCreateSubscription() {
+	terms := bankSlipEntities.SubscriptionTerms{
+		UserName:     "John Doe",
+		GovernmentId: "52998224725",
+		UserEmail:    "john.doe@example.com",
+		Amount:       "150.00",
+		DayOfMonth:   10,
+		EndDate:      time.Date(2027, 10, 31, 0, 0, 0, 0, time.UTC),
+	}
+	s.createService.On("Execute", testSubscriptionBeneficiaryId, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), terms).Return(newTestSubscription(), nil).Once()
+	recorder := httptest.NewRecorder()
+
+	body := `{"beneficiaryId":"` + testSubscriptionBeneficiaryId + `","startDate":"2026-11-01","endDate":"2027-10-31","userName":"John Doe","governmentId":"52998224725","userEmail":"john.doe@example.com","amount":"150.00","dayOfMonth":10}`
+	s.controller.CreateSubscriptionHandler(recorder, httptest.NewRequest(http.MethodPost, "/subscriptions", strings.NewReader(body)))
+
+	assert.Equal(s.T(), http.StatusCreated, recorder.Code)
+	var response SubscriptionResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(s.T(), testSubscriptionId, response.ID)
+	assert.Equal(s.T(), "529.982.247-25", response.GovernmentId)
+	assert.Equal(s.T(), "150.00", response.Amount)
+	assert.Equal(s.T(), "2026-11-10", response.NextDueDate)
+	assert.Empty(s.T(), response.EndDate)
+}
+
+func (s *TestSuitSubscriptionController) TestSubscriptionController_ShouldRejectInvalidStartDate() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.CreateSubscriptionHandler(recorder, httptest.NewRequest(http.MethodPost, "/subscriptions", strings.NewReader(`{"startDate":"01/11/2026"}`)))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), "Data inválida!")
+	s.createService.AssertNotCalled(s.T(), "Execute", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TestSuitSubscriptionController) TestSubscriptionController_ShouldRejectInvalidSubscription() {
+	s.createService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, bankSlipEntities.ErrInvalidSubscription).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.CreateSubscriptionHandler(recorder, httptest.NewRequest(http.MethodPost, "/subscriptions", strings.NewReader(`{"startDate":"2026-11-01","dayOfMonth":40}`)))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), "Assinatura inválida!")
+}
+
+func (s *TestSuitSubscriptionController) TestSubscriptionController_ShouldListSubscriptionsOfBeneficiary() {
+	s.listService.On("Execute", testSubscriptionBeneficiaryId).Return([]*bankSlipEntities.Subscription{newTestSubscription()}, nil).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.ListSubscriptionsHandler(recorder, httptest.NewRequest(http.MethodGet, "/subscriptions?beneficiaryId="+testSubscriptionBeneficiaryId, nil))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	var response []SubscriptionResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Len(s.T(), response, 1)
+}
+
+func (s *TestSuitSubscriptionController) TestSubscriptionController_ShouldAnswerNotFoundWhenSubscriptionDoesNotExist() {
+	s.getService.On("Execute", testSubscriptionId).Return(nil, bankSlipEntities.ErrSubscriptionNotFound).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.GetSubscriptionHandler(recorder, newSubscriptionRequest(http.MethodGet, testSubscriptionId, ""))
+
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *TestSuitSubscriptionController) TestSubscriptionController_ShouldRejectInvalidId() {
+	recorder := httptest.NewRecorder()
+
+	s.controller.GetSubscriptionHandler(recorder, newSubscriptionRequest(http.MethodGet, "invalid", ""))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (s *TestSuitSubscriptionController) TestSubscriptionController_ShouldUpdateSubscription() {
+	updated := newTestSubscription()
+	updated.Amount = 18000
+	s.updateService.On("Execute", testSubscriptionId, mock.MatchedBy(func(terms bankSlipEntities.SubscriptionTerms) bool {
+		return terms.Amount == "180.00" && terms.EndDate.IsZero()
+	})).Return(updated, nil).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.UpdateSubscriptionHandler(recorder, newSubscriptionRequest(http.MethodPut, testSubscriptionId, `{"userName":"John Doe","governmentId":"52998224725","userEmail":"john.doe@example.com","amount":"180.00","dayOfMonth":10}`))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	assert.Contains(s.T(), recorder.Body.String(), `"amount":"180.00"`)
+}
+
+func (s *TestSuitSubscriptionController) TestSubscriptionController_ShouldAnswerConflictWhenCancellingInactiveSubscription() {
+	s.cancelService.On("Execute", testSubscriptionId).Return(nil, bankSlipEntities.ErrSubscriptionNotActive).Once()
+	recorder := httptest.NewRecorder()
+
+	s.controller.CancelSubscriptionHandler(recorder, newSubscriptionRequest(http.MethodDelete, testSubscriptionId, ""))
+
+	assert.Equal(s.T(), http.StatusConflict, recorder.Code)
+}
@@ -0,0 +1,94 @@
+package bank_slip
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+)
+
+type UploadProfileRequest struct {
+	Name             string                                      `json:"name"`
+	Columns          map[bankSlipEntities.BankSlipField][]string `json:"columns"`
+	DateFormat       string                                      `json:"dateFormat"`
+	DecimalSeparator string                                      `json:"decimalSeparator"`
+	DueDatePolicy    string                                      `json:"dueDatePolicy"`
+}
+
+type UploadProfileResponse struct {
+	ID               string                                      `json:"id"`
+	Name             string                                      `json:"name"`
+	Columns          map[bankSlipEntities.BankSlipField][]string `json:"columns"`
+	DateFormat       string                                      `json:"dateFormat"`
+	DecimalSeparator string                                      `json:"decimalSeparator"`
+	DueDatePolicy    string                                      `json:"dueDatePolicy"`
+}
+
+type UploadProfileController struct {
+	createService bankSlip.CreateUploadProfileServiceInterface
+	listService   bankSlip.ListUploadProfilesServiceInterface
+}
+
+func NewUploadProfileController(
+	createService bankSlip.CreateUploadProfileServiceInterface,
+	listService bankSlip.ListUploadProfilesServiceInterface,
+) *UploadProfileController {
+	return &UploadProfileController{
+		createService: createService,
+		listService:   listService,
+	}
+}
+
+func (controller *UploadProfileController) CreateUploadProfileHandler(w http.ResponseWriter, r *http.Request) {
+	var request UploadProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Corpo da requisição inválido!"})
+		return
+	}
+
+	uploadProfile, err := controller.createService.Execute(request.Name, request.Columns, request.DateFormat, request.DecimalSeparator, request.DueDatePolicy)
+	if errors.Is(err, bankSlipEntities.ErrInvalidUploadProfile) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Perfil de upload inválido!", "details": err.Error()})
+		return
+	}
+	if errors.Is(err, bankSlipEntities.ErrUploadProfileAlreadyExists) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "Perfil de upload já existe!"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao criar perfil de upload: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao criar perfil de upload!"})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newUploadProfileResponse(uploadProfile))
+}
+
+func (controller *UploadProfileController) ListUploadProfilesHandler(w http.ResponseWriter, r *http.Request) {
+	uploadProfiles, err := controller.listService.Execute()
+	if err != nil {
+		log.Printf("Erro ao listar perfis de upload: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao listar perfis de upload!"})
+		return
+	}
+
+	response := make([]UploadProfileResponse, 0, len(uploadProfiles))
+	for _, uploadProfile := range uploadProfiles {
+		response = append(response, newUploadProfileResponse(uploadProfile))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func newUploadProfileResponse(uploadProfile *bankSlipEntities.UploadProfile) UploadProfileResponse {
+	return UploadProfileResponse{
+		ID:               uploadProfile.ID,
+		Name:             uploadProfile.Name,
+		Columns:          uploadProfile.Columns,
+		DateFormat:       uploadProfile.DateFormat,
+		DecimalSeparator: uploadProfile.DecimalSeparator,
+		DueDatePolicy:    string(uploadProfile.DueDatePolicy),
+	}
+}
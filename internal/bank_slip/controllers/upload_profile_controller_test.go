@@ -0,0 +1,115 @@
+package bank_slip
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitUploadProfileController struct {
+	suite.Suite
+	createService *bankSlipMocks.CreateUploadProfileServiceMock
+	listService   *bankSlipMocks.ListUploadProfilesServiceMock
+	controller    *UploadProfileController
+}
+
+func (testSuit *TestSuitUploadProfileController) SetupTest() {
+	testSuit.createService = new(bankSlipMocks.CreateUploadProfileServiceMock)
+	testSuit.listService = new(bankSlipMocks.ListUploadProfilesServiceMock)
+
+	testSuit.controller = NewUploadProfileController(
+		testSuit.createService,
+		testSuit.listService,
+	)
+}
+
+func TestUploadProfileController(t *testing.T) {
+	suite.Run(t, new(TestSuitUploadProfileController))
+}
+
+func newCreateUploadProfileRequest() *http.Request {
+	body, _ := json.Marshal(UploadProfileRequest{
+		Name:             "default",
+		Columns:          bankSlipEntities.DefaultUploadProfile().Columns,
+		DateFormat:       "YYYY-MM-DD",
+		DecimalSeparator: ".",
+		DueDatePolicy:    "KEEP",
+	})
+	return httptest.NewRequest(http.MethodPost, "/upload/profiles", bytes.NewBuffer(body))
+}
+
+func (s *TestSuitUploadProfileController) TestCreateUploadProfileHandler_ShouldCreateProfile() {
+	uploadProfile := bankSlipEntities.DefaultUploadProfile()
+	uploadProfile.ID = "profile_id"
+	s.createService.On("Execute", "default", uploadProfile.Columns, "YYYY-MM-DD", ".", "KEEP").Return(uploadProfile, nil)
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateUploadProfileHandler(recorder, newCreateUploadProfileRequest())
+
+	assert.Equal(s.T(), http.StatusCreated, recorder.Code)
+	var response UploadProfileResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(s.T(), "profile_id", response.ID)
+	assert.Equal(s.T(), []string{"email"}, response.Columns[bankSlipEntities.BankSlipFieldUserEmail])
+	assert.Equal(s.T(), "KEEP", response.DueDatePolicy)
+}
+
+func (s *TestSuitUploadProfileController) TestCreateUploadProfileHandler_ShouldReturnBadRequestWhenBodyIsInvalid() {
+	recorder := httptest.NewRecorder()
+	s.controller.CreateUploadProfileHandler(recorder, httptest.NewRequest(http.MethodPost, "/upload/profiles", bytes.NewBufferString("{")))
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.createService.AssertNotCalled(s.T(), "Execute")
+}
+
+func (s *TestSuitUploadProfileController) TestCreateUploadProfileHandler_ShouldReturnBadRequestWhenProfileIsInvalid() {
+	invalidErr := fmt.Errorf("%w: missing columns for debtId", bankSlipEntities.ErrInvalidUploadProfile)
+	s.createService.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, invalidErr)
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateUploadProfileHandler(recorder, newCreateUploadProfileRequest())
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(s.T(), `{"error":"Perfil de upload inválido!","details":"invalid upload profile: missing columns for debtId"}`, recorder.Body.String())
+}
+
+func (s *TestSuitUploadProfileController) TestCreateUploadProfileHandler_ShouldReturnConflictWhenProfileExists() {
+	s.createService.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, bankSlipEntities.ErrUploadProfileAlreadyExists)
+
+	recorder := httptest.NewRecorder()
+	s.controller.CreateUploadProfileHandler(recorder, newCreateUploadProfileRequest())
+
+	assert.Equal(s.T(), http.StatusConflict, recorder.Code)
+}
+
+func (s *TestSuitUploadProfileController) TestListUploadProfilesHandler() {
+	s.listService.On("Execute").Return([]*bankSlipEntities.UploadProfile{bankSlipEntities.DefaultUploadProfile()}, nil)
+
+	recorder := httptest.NewRecorder()
+	s.controller.ListUploadProfilesHandler(recorder, httptest.NewRequest(http.MethodGet, "/upload/profiles", nil))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	var response []UploadProfileResponse
+	assert.NoError(s.T(), json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Len(s.T(), response, 1)
+	assert.Equal(s.T(), "default", response[0].Name)
+}
+
+func (s *TestSuitUploadProfileController) TestListUploadProfilesHandler_ShouldReturnInternalErrorWhenServiceFails() {
+	s.listService.On("Execute").Return(nil, assert.AnError)
+
+	recorder := httptest.NewRecorder()
+	s.controller.ListUploadProfilesHandler(recorder, httptest.NewRequest(http.MethodGet, "/upload/profiles", nil))
+
+	assert.Equal(s.T(), http.StatusInternalServerError, recorder.Code)
+}
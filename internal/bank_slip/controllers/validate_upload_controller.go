@@ -0,0 +1,78 @@
+package bank_slip
+
+import (
+	"log"
+	"net/http"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+)
+
+type UploadValidationErrorResponse struct {
+	LineNumber int                            `json:"lineNumber"`
+	ErrorCode  bankSlipEntities.RejectionCode `json:"errorCode"`
+	Message    string                         `json:"message"`
+}
+
+type UploadValidationReportResponse struct {
+	FileName         string                                 `json:"fileName"`
+	TotalRows        int                                    `json:"totalRows"`
+	ValidRows        int                                    `json:"validRows"`
+	RejectedRows     int                                    `json:"rejectedRows"`
+	ErrorsByCode     map[bankSlipEntities.RejectionCode]int `json:"errorsByCode"`
+	Errors           []UploadValidationErrorResponse        `json:"errors"`
+	DuplicateDebtIds []string                               `json:"duplicateDebtIds"`
+}
+
+type ValidateUploadController struct {
+	service bankSlip.ValidateUploadServiceInterface
+}
+
+func NewValidateUploadController(
+	service bankSlip.ValidateUploadServiceInterface,
+) *ValidateUploadController {
+	return &ValidateUploadController{service: service}
+}
+
+func (controller *ValidateUploadController) ValidateBankSlipFileHandler(w http.ResponseWriter, r *http.Request) {
+	multpartFile, handler, err := r.FormFile("file")
+	if err != nil {
+		log.Printf("Erro ao obter arquivo multipart: %v\n", err)
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Erro ao obter arquivo!"})
+		return
+	}
+	defer multpartFile.Close()
+
+	report, err := controller.service.Execute(multpartFile, handler, uploadOptionsFromRequest(r))
+	if writeUploadInputError(w, err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao validar arquivo: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erro ao validar arquivo!"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newUploadValidationReportResponse(report))
+}
+
+func newUploadValidationReportResponse(report *bankSlipEntities.UploadValidationReport) UploadValidationReportResponse {
+	errors := make([]UploadValidationErrorResponse, 0, len(report.Errors))
+	for _, rowError := range report.Errors {
+		errors = append(errors, UploadValidationErrorResponse{
+			LineNumber: rowError.LineNumber,
+			ErrorCode:  rowError.ErrorCode,
+			Message:    rowError.Message,
+		})
+	}
+
+	return UploadValidationReportResponse{
+		FileName:         report.FileName,
+		TotalRows:        report.TotalRows,
+		ValidRows:        report.ValidRows,
+		RejectedRows:     report.RejectedRows(),
+		ErrorsByCode:     report.ErrorsByCode,
+		Errors:           errors,
+		DuplicateDebtIds: report.DuplicateDebtIds,
+	}
+}
@@ -0,0 +1,84 @@
+package bank_slip
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	bankSlip "performatic-file-processor/internal/bank_slip/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitValidateUploadController struct {
+	suite.Suite
+	validateUploadService *bankSlipMocks.ValidateUploadServiceMock
+	controller            *ValidateUploadController
+}
+
+func (testSuit *TestSuitValidateUploadController) SetupTest() {
+	testSuit.validateUploadService = new(bankSlipMocks.ValidateUploadServiceMock)
+
+	testSuit.controller = NewValidateUploadController(
+		testSuit.validateUploadService,
+	)
+}
+
+func TestValidateUploadController(t *testing.T) {
+	suite.Run(t, new(TestSuitValidateUploadController))
+}
+
+func (s *TestSuitValidateUploadController) TestValidateUploadController_ShouldReturnBadRequestWhenFileIsMissing() {
+	req := httptest.NewRequest(http.MethodPost, "/upload/bank-slip/file/validate", bytes.NewBuffer(nil))
+
+	recorder := httptest.NewRecorder()
+	s.controller.ValidateBankSlipFileHandler(recorder, req)
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+	s.validateUploadService.AssertNotCalled(s.T(), "Execute")
+}
+
+func (s *TestSuitValidateUploadController) TestValidateUploadController_ShouldReturnReport() {
+	report := bankSlipEntities.NewUploadValidationReport("testfile.txt", 10)
+	report.AddRow(2, &bankSlipEntities.BankSlip{DebtId: "debt1"}, nil)
+	report.AddRow(3, &bankSlipEntities.BankSlip{DebtId: "debt1"}, nil)
+	s.validateUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(report, nil)
+
+	recorder := httptest.NewRecorder()
+	s.controller.ValidateBankSlipFileHandler(recorder, newUploadRequestWithFields(map[string]string{"profile": "br"}))
+
+	assert.Equal(s.T(), http.StatusOK, recorder.Code)
+	s.validateUploadService.AssertCalled(s.T(), "Execute", mock.Anything, mock.Anything, bankSlipEntities.UploadOptions{Profile: "br"})
+	assert.JSONEq(s.T(), `{
+		"fileName": "testfile.txt",
+		"totalRows": 2,
+		"validRows": 1,
+		"rejectedRows": 1,
+		"errorsByCode": {"DUPLICATE_DEBT_ID": 1},
+		"errors": [{"lineNumber": 3, "errorCode": "DUPLICATE_DEBT_ID", "message": "duplicate debtId debt1"}],
+		"duplicateDebtIds": ["debt1"]
+	}`, recorder.Body.String())
+}
+
+func (s *TestSuitValidateUploadController) TestValidateUploadController_ShouldReturnBadRequestWhenHeaderIsMissing() {
+	s.validateUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, bankSlip.ErrHeaderNotFound)
+
+	recorder := httptest.NewRecorder()
+	s.controller.ValidateBankSlipFileHandler(recorder, newUploadRequest())
+
+	assert.Equal(s.T(), http.StatusBadRequest, recorder.Code)
+}
+
+func (s *TestSuitValidateUploadController) TestValidateUploadController_ShouldReturnInternalErrorWhenServiceFails() {
+	s.validateUploadService.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	recorder := httptest.NewRecorder()
+	s.controller.ValidateBankSlipFileHandler(recorder, newUploadRequest())
+
+	assert.Equal(s.T(), http.StatusInternalServerError, recorder.Code)
+}
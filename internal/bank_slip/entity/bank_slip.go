@@ -1,11 +1,15 @@
 package bank_slip
 
 import (
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"slices"
-	"strconv"
 	"strings"
 	"time"
+
+	"performatic-file-processor/internal/calendar"
 )
 
 type BankSlipStatus string
@@ -14,10 +18,36 @@ type Existing = bool
 type Success = bool
 
 const (
+	// BankSlipStatusScheduled is an installment waiting for its issuance
+	// window, it becomes PENDING and is billed when the window opens.
+	BankSlipStatusScheduled            BankSlipStatus = "SCHEDULED"
 	BankSlipStatusPending              BankSlipStatus = "PENDING"
 	BankSlipStatusSuccess              BankSlipStatus = "SUCCESS"
 	BankSlipStatusGenerateBillingError BankSlipStatus = "GENERATING_BILLING_ERROR"
 	BankSlipStatusSendingEmailError    BankSlipStatus = "SENT_EMAIL_WITH_ERROR"
+	// The statuses below are reported by the bank in the return files.
+	BankSlipStatusPaid           BankSlipStatus = "PAID"
+	BankSlipStatusRejectedByBank BankSlipStatus = "REJECTED_BY_BANK"
+	// BankSlipStatusSettled is a paid boleto whose payment the bank credited
+	// to the beneficiary.
+	BankSlipStatusSettled BankSlipStatus = "SETTLED"
+	// BankSlipStatusOverdue is a boleto past its due date and not paid yet,
+	// BankSlipStatusExpired one still not paid after the grace period. Both can
+	// still be paid late.
+	BankSlipStatusOverdue   BankSlipStatus = "OVERDUE"
+	BankSlipStatusExpired   BankSlipStatus = "EXPIRED"
+	BankSlipStatusCancelled BankSlipStatus = "CANCELLED"
+	// BankSlipStatusReissued is a boleto replaced by a new one, as when its due
+	// date or amount changes.
+	BankSlipStatusReissued BankSlipStatus = "REISSUED"
+)
+
+var (
+	ErrBankSlipNotFound  = errors.New("bank slip not found")
+	ErrBankSlipNotIssued = errors.New("bank slip boleto not issued")
+	// ErrUnexpectedBankSlipStatus is returned when a bank slip can't move to a
+	// status from the one it is in, as a paid bank slip being cancelled.
+	ErrUnexpectedBankSlipStatus = errors.New("unexpected bank slip status")
 )
 
 type BankSlipMap = map[DebitId]*BankSlip
@@ -25,21 +55,52 @@ type BankSlipMap = map[DebitId]*BankSlip
 type BankSlipRepository interface {
 	UpdateMany(bankSlips ...*BankSlipMap) error
 	InsertMany(bankSlips *BankSlipMap) (map[DebitId]Success, error)
+	GetByDebtId(debtId string) (*BankSlip, error)
+	// ForEachByFileId reads the bank slips of a file one at a time, in our
+	// number order, so big files don't have to fit in memory.
+	ForEachByFileId(fileId string, statuses []BankSlipStatus, fn func(bankSlip *BankSlip) error) error
+	// Reconcile moves the bank slip of the beneficiary the return record is
+	// about to the status reported by the bank, with the payment data.
+	Reconcile(beneficiaryId string, returnRecord *BankSlipReturnRecord) error
 }
 
 type BankSlip struct {
 	DebtId                 string
-	DebtAmount             float64
+	DebtAmount             Money
 	DebtDueDate            time.Time
-	GovernmentId           int
+	GovernmentId           GovernmentId
 	UserName               string
 	UserEmail              string
 	BankSlipFileMetadataId string
 	ErrorMessage           *string
 	Status                 BankSlipStatus
+	// BeneficiaryId is the account of the file the bank slip came from, or of
+	// its subscription, and OurNumber ("nosso número") identifies the bank
+	// slip in that account. Both are assigned when the bank slip is stored.
+	BeneficiaryId string
+	OurNumber     int64
+	Barcode       string
+	DigitableLine string
+	// PixPayload is the Pix "copia e cola" BR Code the bank slip can also be
+	// paid with, empty when Pix is not configured.
+	PixPayload string
+	// ChargePolicy is resolved when the row is read, from the beneficiary,
+	// the upload and the row, and never changes after that.
+	ChargePolicy ChargePolicy
+	// InstallmentPlan is the one of the row, the bank slips stored are the
+	// installments it splits the debt in. Installments keep the debt id of the
+	// debt in ParentDebtId and their position in InstallmentNumber, from 1 to
+	// InstallmentCount.
+	InstallmentPlan   InstallmentPlan
+	ParentDebtId      string
+	InstallmentNumber int
+	InstallmentCount  int
+	// SubscriptionId is the subscription the bank slip was issued by, bank
+	// slips of subscriptions don't come from a file.
+	SubscriptionId string
 }
 
-func newBankSlip(governmentId int, debtAmount float64, debtDueDate time.Time, debtId, userName, userEmail, bankSlipFileMetadataId string, status BankSlipStatus) *BankSlip {
+func newBankSlip(governmentId GovernmentId, debtAmount Money, debtDueDate time.Time, debtId, userName, userEmail, bankSlipFileMetadataId string, status BankSlipStatus) *BankSlip {
 	return &BankSlip{
 		DebtId:                 debtId,
 		DebtAmount:             debtAmount,
@@ -54,61 +115,168 @@ func newBankSlip(governmentId int, debtAmount float64, debtDueDate time.Time, de
 }
 
 func NewBankSlipFromRow(fileId, data, header string) (*BankSlip, error) {
-	rowItems := strings.Split(data, ",")
-	headerItems := strings.Split(header, ",")
+	headerItems, err := ParseCSVRecord(header, DefaultDelimiter)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing header %s (file id: %s)", err.Error(), fileId)
+	}
+	layout, err := DefaultUploadProfile().ResolveHeader(headerItems)
+	if err != nil {
+		return nil, fmt.Errorf("error is missing some field (file id: %s)", fileId)
+	}
+	return NewBankSlipFromLayoutRow(fileId, data, layout)
+}
+
+func NewBankSlipFromLayoutRow(fileId, data string, layout *BankSlipRowLayout) (*BankSlip, error) {
+	rowItems, err := layout.Split(data)
+	if err != nil {
+		return nil, newBankSlipRowError(RejectionCodeMalformedRow, "error parsing row %s (file id: %s)", err.Error(), fileId)
+	}
+	return NewBankSlipFromRecord(fileId, rowItems, layout)
+}
+
+// ParseCSVRecord parses a single RFC 4180 record, an empty line results in no fields.
+func ParseCSVRecord(record string, delimiter rune) ([]string, error) {
+	fields, err := NewCSVReader(strings.NewReader(record), delimiter).Read()
+	if err == io.EOF {
+		return []string{}, nil
+	}
+	return fields, err
+}
 
-	userNamePosition := slices.IndexFunc(headerItems, func(s string) bool { return strings.Contains(s, "name") })
-	governmentIdPosition := slices.IndexFunc(headerItems, func(s string) bool { return strings.Contains(s, "governmentId") })
-	emailPosition := slices.IndexFunc(headerItems, func(s string) bool { return strings.Contains(s, "email") })
-	amountPosition := slices.IndexFunc(headerItems, func(s string) bool { return strings.Contains(s, "debtAmount") })
-	dueDatePosition := slices.IndexFunc(headerItems, func(s string) bool { return strings.Contains(s, "debtDueDate") })
-	debtIdPosition := slices.IndexFunc(headerItems, func(s string) bool { return strings.Contains(s, "debtId") })
+func NewCSVReader(reader io.Reader, delimiter rune) *csv.Reader {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = delimiter
+	csvReader.FieldsPerRecord = -1
+	return csvReader
+}
 
+func NewBankSlipFromRecord(fileId string, rowItems []string, layout *BankSlipRowLayout) (*BankSlip, error) {
 	if len(fileId) == 0 {
 		return nil, fmt.Errorf("file metadata must be not empty")
 	}
-	someVariableIsMissing := governmentIdPosition == -1 || userNamePosition == -1 || emailPosition == -1 || amountPosition == -1 || dueDatePosition == -1 || debtIdPosition == -1
-	if someVariableIsMissing {
+	if layout == nil {
 		return nil, fmt.Errorf("error is missing some field (file id: %s)", fileId)
 	}
-	if len(rowItems) != len(headerItems) {
-		return nil, fmt.Errorf("error rowItems and headerItems length are different (file id: %s)", fileId)
+	if len(rowItems) != layout.Columns {
+		return nil, newBankSlipRowError(RejectionCodeColumnCountMismatch, "error rowItems and headerItems length are different (file id: %s)", fileId)
+	}
+
+	governmentIdPosition := layout.Positions[BankSlipFieldGovernmentId]
+	governmentId, err := ParseGovernmentId(layout.Value(rowItems, BankSlipFieldGovernmentId))
+	if errors.Is(err, ErrInvalidGovernmentIdCheckDigits) {
+		return nil, newBankSlipRowError(RejectionCodeInvalidGovernmentIdCheckDigits, "error validating governmentId check digits %s Position: %s (file id: %s): %w", rowItems[governmentIdPosition], fmt.Sprint(governmentIdPosition), fileId, err)
+	}
+	if err != nil {
+		return nil, newBankSlipRowError(RejectionCodeInvalidGovernmentId, "error converting governmentId to CPF or CNPJ %s Position: %s (file id: %s): %w", rowItems[governmentIdPosition], fmt.Sprint(governmentIdPosition), fileId, err)
+	}
+	amountPosition := layout.Positions[BankSlipFieldDebtAmount]
+	normalizedAmount, err := layout.NormalizeDecimal(layout.Value(rowItems, BankSlipFieldDebtAmount))
+	if err != nil {
+		return nil, newBankSlipRowError(RejectionCodeInvalidAmount, "error converting debtAmount to money %s Position: %s (file id: %s)", rowItems[amountPosition], fmt.Sprint(amountPosition), fileId)
 	}
-	governmentId, err := strconv.Atoi(rowItems[governmentIdPosition])
+	debtAmount, err := ParseMoney(normalizedAmount)
 	if err != nil {
-		return nil, fmt.Errorf("error converting governmentId to int %s Position: %s (file id: %s)", string(rowItems[governmentIdPosition]), fmt.Sprint(governmentIdPosition), fileId)
+		return nil, newBankSlipRowError(RejectionCodeInvalidAmount, "error converting debtAmount to money %s Position: %s (file id: %s): %w", rowItems[amountPosition], fmt.Sprint(amountPosition), fileId, err)
+	}
+	dueDatePosition := layout.Positions[BankSlipFieldDebtDueDate]
+	debtDueDate, err := layout.DueDate(rowItems)
+	if errors.Is(err, ErrDueDateNotBusinessDay) {
+		return nil, newBankSlipRowError(RejectionCodeDueDateNotBusinessDay, "error validating debtDueDate %s Position: %s (file id: %s): %w", rowItems[dueDatePosition], fmt.Sprint(dueDatePosition), fileId, err)
 	}
-	debtAmount, err := strconv.ParseFloat(rowItems[amountPosition], 64)
 	if err != nil {
-		return nil, fmt.Errorf("error converting debtAmount to float64 %s Position: %s (file id: %s)", string(rowItems[amountPosition]), fmt.Sprint(amountPosition), fileId)
+		return nil, newBankSlipRowError(RejectionCodeInvalidDueDate, "error converting debtDueDate to time.Time %s Position: %s (file id: %s)", rowItems[dueDatePosition], fmt.Sprint(dueDatePosition), fileId)
+	}
+
+	chargePolicy, err := layout.RowChargePolicy(rowItems)
+	if err == nil {
+		err = chargePolicy.ValidateFor(debtAmount)
 	}
-	debtDueDate, err := time.Parse("2006-01-02", rowItems[dueDatePosition])
 	if err != nil {
-		return nil, fmt.Errorf("error converting debtDueDate to time.Time %s Position: %s (file id: %s)", string(rowItems[dueDatePosition]), fmt.Sprint(dueDatePosition), fileId)
+		return nil, newBankSlipRowError(RejectionCodeInvalidChargePolicy, "error reading charge policy (file id: %s): %w", fileId, err)
+	}
+	installmentPlan, err := layout.RowInstallmentPlan(rowItems)
+	if err == nil {
+		err = installmentPlan.ValidateFor(layout.Value(rowItems, BankSlipFieldDebtId), debtAmount, chargePolicy)
+	}
+	if err != nil {
+		return nil, newBankSlipRowError(RejectionCodeInvalidInstallmentPlan, "error reading installment plan (file id: %s): %w", fileId, err)
 	}
 
-	return newBankSlip(
+	bankSlip := newBankSlip(
 		governmentId,
 		debtAmount,
 		debtDueDate,
-		rowItems[debtIdPosition],
-		rowItems[userNamePosition],
-		rowItems[emailPosition],
+		layout.Value(rowItems, BankSlipFieldDebtId),
+		layout.Value(rowItems, BankSlipFieldUserName),
+		layout.Value(rowItems, BankSlipFieldUserEmail),
 		fileId,
 		BankSlipStatusPending,
-	), nil
+	)
+	bankSlip.ChargePolicy = chargePolicy
+	bankSlip.InstallmentPlan = installmentPlan
+	return bankSlip, nil
 }
 
-func (bankSlip *BankSlip) ErrorGeneratingBilling(errorMessage string) {
+// AmountDue is what the bank slip is paid with on date, with its discount,
+// late fee and interest. Due on a day that is not a business day, it is paid
+// as on the due date until the next business day.
+func (bankSlip *BankSlip) AmountDue(date time.Time, businessDays *calendar.Calendar) AmountDue {
+	date = dateOnly(date)
+	if date.After(bankSlip.DebtDueDate) && !date.After(businessDays.NextBusinessDay(bankSlip.DebtDueDate)) {
+		amountDue := bankSlip.ChargePolicy.AmountDue(bankSlip.DebtAmount, bankSlip.DebtDueDate, bankSlip.DebtDueDate)
+		amountDue.Date = date
+		return amountDue
+	}
+	return bankSlip.ChargePolicy.AmountDue(bankSlip.DebtAmount, bankSlip.DebtDueDate, date)
+}
+
+func (bankSlip *BankSlip) ErrorGeneratingBilling(errorMessage string) error {
+	if err := bankSlip.TransitionTo(BankSlipStatusGenerateBillingError); err != nil {
+		return err
+	}
 	bankSlip.ErrorMessage = &errorMessage
-	bankSlip.Status = BankSlipStatusGenerateBillingError
+	return nil
 }
 
-func (bankSlip *BankSlip) ErrorSendingEmail(errorMessage string) {
+// RetryBilling takes a bank slip whose billing failed back to PENDING, without
+// the error, to be billed again.
+func (bankSlip *BankSlip) RetryBilling() error {
+	if bankSlip.Status != BankSlipStatusGenerateBillingError {
+		return fmt.Errorf("%w: %s can't be billed again", ErrUnexpectedBankSlipStatus, bankSlip.Status)
+	}
+	if err := bankSlip.TransitionTo(BankSlipStatusPending); err != nil {
+		return err
+	}
+	bankSlip.ErrorMessage = nil
+	return nil
+}
+
+func (bankSlip *BankSlip) ErrorSendingEmail(errorMessage string) error {
+	if err := bankSlip.TransitionTo(BankSlipStatusSendingEmailError); err != nil {
+		return err
+	}
 	bankSlip.ErrorMessage = &errorMessage
-	bankSlip.Status = BankSlipStatusSendingEmailError
+	return nil
+}
+
+// AttachBoleto keeps the 44 digit barcode and the 47 digit typeable line
+// generated for the bank slip.
+func (bankSlip *BankSlip) AttachBoleto(barcode, digitableLine string) {
+	bankSlip.Barcode = barcode
+	bankSlip.DigitableLine = digitableLine
+}
+
+// AttachPix keeps the Pix BR Code generated for the bank slip.
+func (bankSlip *BankSlip) AttachPix(payload string) {
+	bankSlip.PixPayload = payload
+}
+
+// Issued tells if the boleto can be handed to the payer: it has a barcode and
+// is in one of the BankSlipIssuedStatuses.
+func (bankSlip *BankSlip) Issued() bool {
+	return bankSlip.Barcode != "" && slices.Contains(BankSlipIssuedStatuses, bankSlip.Status)
 }
 
-func (bankSlip *BankSlip) Success() {
-	bankSlip.Status = BankSlipStatusSuccess
+func (bankSlip *BankSlip) Success() error {
+	return bankSlip.TransitionTo(BankSlipStatusSuccess)
 }
@@ -1,19 +1,95 @@
 package bank_slip
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+type BankSlipFileStatus string
+
+const (
+	BankSlipFileStatusReceived   BankSlipFileStatus = "RECEIVED"
+	BankSlipFileStatusProcessing BankSlipFileStatus = "PROCESSING"
+	BankSlipFileStatusCompleted  BankSlipFileStatus = "COMPLETED"
+	BankSlipFileStatusFailed     BankSlipFileStatus = "FAILED"
+)
+
+var (
+	ErrBankSlipFileNotFound     = errors.New("bank slip file not found")
+	ErrOriginalFileNotAvailable = errors.New("original file not available")
+)
 
 type BankSlipFileMetadataRepository interface {
 	Insert(bankSlipFile *BankSlipFileMetadata) error
+	UpdateStatus(bankSlipFile *BankSlipFileMetadata) error
+	UpdateHeader(bankSlipFile *BankSlipFileMetadata) error
+	UpdateContentHash(bankSlipFile *BankSlipFileMetadata) error
+	UpdateStoragePath(bankSlipFile *BankSlipFileMetadata) error
+	AddRejectedRows(fileId string, rejectedRows int) error
+	CompleteWhenAllRowsProcessed(fileId string) error
+	GetById(fileId string) (*BankSlipFileMetadata, error)
+	GetByContentHash(contentHash string) (*BankSlipFileMetadata, error)
+	GetByIdempotencyKey(idempotencyKey string) (*BankSlipFileMetadata, error)
+	List(limit, offset int) ([]*BankSlipFileMetadata, error)
 }
 
 type BankSlipFileMetadata struct {
-	ID        string
-	FileName  string
-	CreatedAt time.Time
+	ID             string
+	FileName       string
+	Status         BankSlipFileStatus
+	TotalRows      int
+	RejectedRows   int
+	RowsByStatus   map[BankSlipStatus]int
+	Header         string
+	Delimiter      string
+	ContentHash    string
+	IdempotencyKey string
+	StoragePath    string
+	BeneficiaryId  string
+	CreatedAt      time.Time
 }
 
 func NewBankSlipFileMetadata(fileName string) *BankSlipFileMetadata {
 	return &BankSlipFileMetadata{
-		FileName: fileName,
+		FileName:     fileName,
+		Status:       BankSlipFileStatusReceived,
+		RowsByStatus: NewBankSlipStatusCount(),
+	}
+}
+
+func NewBankSlipStatusCount() map[BankSlipStatus]int {
+	return map[BankSlipStatus]int{
+		BankSlipStatusPending:              0,
+		BankSlipStatusSuccess:              0,
+		BankSlipStatusGenerateBillingError: 0,
+		BankSlipStatusSendingEmailError:    0,
+	}
+}
+
+func (file *BankSlipFileMetadata) Processing(totalRows int) {
+	file.TotalRows = totalRows
+	file.Status = BankSlipFileStatusProcessing
+}
+
+func (file *BankSlipFileMetadata) HeaderRead(header string, delimiter string) {
+	file.Header = header
+	file.Delimiter = delimiter
+}
+
+func (file *BankSlipFileMetadata) Stored(storagePath string) {
+	file.StoragePath = storagePath
+}
+
+func (file *BankSlipFileMetadata) Failed() {
+	file.Status = BankSlipFileStatusFailed
+}
+
+func (file *BankSlipFileMetadata) ProcessedRows() int {
+	processed := file.RejectedRows
+	for status, count := range file.RowsByStatus {
+		if status != BankSlipStatusPending {
+			processed += count
+		}
 	}
+	return processed
 }
@@ -12,3 +12,25 @@ func TestNewBankSlipFileMetadata(t *testing.T) {
 
 	assert.Equal(t, fileName, bankSlipFile.FileName)
 }
+
+func TestBankSlipFileMetadata_ShouldTrackLifecycle(t *testing.T) {
+	bankSlipFile := NewBankSlipFileMetadata("test_file.txt")
+	assert.Equal(t, BankSlipFileStatusReceived, bankSlipFile.Status)
+
+	bankSlipFile.Processing(10)
+	assert.Equal(t, BankSlipFileStatusProcessing, bankSlipFile.Status)
+	assert.Equal(t, 10, bankSlipFile.TotalRows)
+
+	bankSlipFile.Failed()
+	assert.Equal(t, BankSlipFileStatusFailed, bankSlipFile.Status)
+}
+
+func TestBankSlipFileMetadata_ProcessedRowsShouldIgnorePending(t *testing.T) {
+	bankSlipFile := NewBankSlipFileMetadata("test_file.txt")
+	bankSlipFile.RejectedRows = 1
+	bankSlipFile.RowsByStatus[BankSlipStatusPending] = 4
+	bankSlipFile.RowsByStatus[BankSlipStatusSuccess] = 2
+	bankSlipFile.RowsByStatus[BankSlipStatusSendingEmailError] = 3
+
+	assert.Equal(t, 6, bankSlipFile.ProcessedRows())
+}
@@ -0,0 +1,76 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+)
+
+type RejectionCode string
+
+const (
+	RejectionCodeInvalidGovernmentId RejectionCode = "INVALID_GOVERNMENT_ID"
+	// The document looks like a CPF or CNPJ but its check digits don't match.
+	RejectionCodeInvalidGovernmentIdCheckDigits RejectionCode = "INVALID_GOVERNMENT_ID_CHECK_DIGITS"
+	RejectionCodeInvalidAmount                  RejectionCode = "INVALID_AMOUNT"
+	RejectionCodeInvalidDueDate                 RejectionCode = "INVALID_DUE_DATE"
+	RejectionCodeColumnCountMismatch            RejectionCode = "COLUMN_COUNT_MISMATCH"
+	RejectionCodeDuplicateDebtId                RejectionCode = "DUPLICATE_DEBT_ID"
+	RejectionCodeMalformedRow                   RejectionCode = "MALFORMED_ROW"
+	RejectionCodeInvalidChargePolicy            RejectionCode = "INVALID_CHARGE_POLICY"
+	// The due date is a weekend or holiday and the upload profile rejects them.
+	RejectionCodeDueDateNotBusinessDay RejectionCode = "DUE_DATE_NOT_BUSINESS_DAY"
+	// The installments columns can't split the debt of the row.
+	RejectionCodeInvalidInstallmentPlan RejectionCode = "INVALID_INSTALLMENT_PLAN"
+)
+
+type BankSlipRejectedRowRepository interface {
+	InsertMany(rejectedRows []*BankSlipRejectedRow) error
+	ListByFileId(fileId string) ([]*BankSlipRejectedRow, error)
+}
+
+type BankSlipRejectedRow struct {
+	BankSlipFileMetadataId string
+	LineNumber             int
+	RawRow                 string
+	ErrorCode              RejectionCode
+}
+
+func NewBankSlipRejectedRow(bankSlipFileMetadataId string, lineNumber int, rawRow string, errorCode RejectionCode) *BankSlipRejectedRow {
+	return &BankSlipRejectedRow{
+		BankSlipFileMetadataId: bankSlipFileMetadataId,
+		LineNumber:             lineNumber,
+		RawRow:                 rawRow,
+		ErrorCode:              errorCode,
+	}
+}
+
+// BankSlipRowError is returned when a row can't become a bank slip, the code
+// tells the uploader what must be fixed in the row.
+type BankSlipRowError struct {
+	Code    RejectionCode
+	Message string
+	cause   error
+}
+
+// newBankSlipRowError formats like fmt.Errorf, an error wrapped with %w is kept
+// as the cause.
+func newBankSlipRowError(code RejectionCode, format string, args ...any) *BankSlipRowError {
+	err := fmt.Errorf(format, args...)
+	return &BankSlipRowError{Code: code, Message: err.Error(), cause: errors.Unwrap(err)}
+}
+
+func (e *BankSlipRowError) Unwrap() error {
+	return e.cause
+}
+
+func (e *BankSlipRowError) Error() string {
+	return e.Message
+}
+
+func RejectionCodeOf(err error) RejectionCode {
+	var rowError *BankSlipRowError
+	if errors.As(err, &rowError) {
+		return rowError.Code
+	}
+	return RejectionCodeMalformedRow
+}
@@ -0,0 +1,62 @@
+package bank_slip
+
+import (
+	"errors"
+	"time"
+
+	"performatic-file-processor/internal/cnab"
+)
+
+var (
+	ErrBankSlipReturnFileNotFound = errors.New("bank slip return file not found")
+	ErrInvalidReturnFile          = errors.New("invalid return file")
+)
+
+type BankSlipReturnFileRepository interface {
+	Insert(returnFile *BankSlipReturnFile) error
+	UpdateStatus(returnFile *BankSlipReturnFile) error
+	UpdateHeader(returnFile *BankSlipReturnFile) error
+	AddProcessedRecords(returnFileId string, processed, reconciled, unmatched int) error
+	CompleteWhenAllRecordsProcessed(returnFileId string) error
+	GetById(returnFileId string) (*BankSlipReturnFile, error)
+}
+
+// BankSlipReturnFile is a CNAB "retorno" file, where the bank reports what
+// happened to the boletos of a beneficiary.
+type BankSlipReturnFile struct {
+	ID            string
+	FileName      string
+	BeneficiaryId string
+	Format        cnab.Format
+	BankCode      string
+	Status        BankSlipFileStatus
+	// TotalRecords counts the records after the file header, trailers
+	// included. Reconciled records moved a bank slip, unmatched ones could not.
+	TotalRecords      int
+	ProcessedRecords  int
+	ReconciledRecords int
+	UnmatchedRecords  int
+	CreatedAt         time.Time
+}
+
+func NewBankSlipReturnFile(fileName, beneficiaryId string) *BankSlipReturnFile {
+	return &BankSlipReturnFile{
+		FileName:      fileName,
+		BeneficiaryId: beneficiaryId,
+		Status:        BankSlipFileStatusReceived,
+	}
+}
+
+func (file *BankSlipReturnFile) HeaderRead(header cnab.ReturnHeader) {
+	file.Format = header.Format
+	file.BankCode = header.BankCode
+}
+
+func (file *BankSlipReturnFile) Processing(totalRecords int) {
+	file.TotalRecords = totalRecords
+	file.Status = BankSlipFileStatusProcessing
+}
+
+func (file *BankSlipReturnFile) Failed() {
+	file.Status = BankSlipFileStatusFailed
+}
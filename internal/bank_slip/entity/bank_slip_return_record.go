@@ -0,0 +1,150 @@
+package bank_slip
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"performatic-file-processor/internal/cnab"
+)
+
+// controlNumberLength is how much of the debt id fits in the control number
+// of the remittances.
+const controlNumberLength = 25
+
+type UnmatchedReason string
+
+const (
+	UnmatchedReasonInvalidRecord    UnmatchedReason = "INVALID_RECORD"
+	UnmatchedReasonBankSlipNotFound UnmatchedReason = "BANK_SLIP_NOT_FOUND"
+	// The bank slip is in a status the reported one can't follow, as a
+	// rejection of a paid bank slip.
+	UnmatchedReasonUnexpectedStatus UnmatchedReason = "UNEXPECTED_STATUS"
+)
+
+// BankSlipReturnRecord is what the bank reported about a bank slip in a
+// return file.
+type BankSlipReturnRecord struct {
+	ReturnFileId   string
+	LineNumber     int
+	RawRecord      string
+	OccurrenceCode string
+	OurNumber      int64
+	ControlNumber  string
+	// Status is the one the bank slip moves to, empty when the occurrence
+	// changes nothing. A payment is PAID until the bank reports it credited
+	// to the beneficiary, then SETTLED, and a write-off ("baixa") without a
+	// payment cancels the bank slip.
+	Status     BankSlipStatus
+	PaidAmount Money
+	PaidAt     time.Time
+	CreditedAt time.Time
+	BankFee    Money
+}
+
+var returnStatuses = map[cnab.Occurrence]BankSlipStatus{
+	cnab.OccurrencePaid:       BankSlipStatusPaid,
+	cnab.OccurrenceRejected:   BankSlipStatusRejectedByBank,
+	cnab.OccurrenceWrittenOff: BankSlipStatusCancelled,
+}
+
+// notRegisteredStatuses are the statuses of bank slips never sent to the bank,
+// which no return record can be about.
+var notRegisteredStatuses = []BankSlipStatus{
+	BankSlipStatusScheduled,
+	BankSlipStatusPending,
+	BankSlipStatusGenerateBillingError,
+}
+
+func returnStatusOf(record cnab.ReturnRecord) BankSlipStatus {
+	if record.Occurrence == cnab.OccurrencePaid && !record.CreditedAt.IsZero() {
+		return BankSlipStatusSettled
+	}
+	return returnStatuses[record.Occurrence]
+}
+
+func NewBankSlipReturnRecord(returnFileId string, record cnab.ReturnRecord) *BankSlipReturnRecord {
+	return &BankSlipReturnRecord{
+		ReturnFileId:   returnFileId,
+		LineNumber:     record.Line,
+		RawRecord:      record.Raw,
+		OccurrenceCode: record.Code,
+		OurNumber:      record.OurNumber,
+		ControlNumber:  record.ControlNumber,
+		Status:         returnStatusOf(record),
+		PaidAmount:     Money(record.PaidAmountInCents),
+		PaidAt:         record.OccurredAt,
+		CreditedAt:     record.CreditedAt,
+		BankFee:        Money(record.FeeInCents),
+	}
+}
+
+// Reconciles tells if the record changes the status of a bank slip.
+func (r *BankSlipReturnRecord) Reconciles() bool {
+	return r.Status != ""
+}
+
+// FromStatuses are the statuses a bank slip can move to Status from: only
+// issued bank slips are registered at the bank, and a paid bank slip can
+// still be settled.
+func (r *BankSlipReturnRecord) FromStatuses() []BankSlipStatus {
+	return slices.DeleteFunc(BankSlipStatusesTo(r.Status), func(status BankSlipStatus) bool {
+		return slices.Contains(notRegisteredStatuses, status)
+	})
+}
+
+// AppliedTo tells if a bank slip in status already has what the record
+// reports, as a payment the bank reported again after crediting it.
+func (r *BankSlipReturnRecord) AppliedTo(status BankSlipStatus) bool {
+	return status == r.Status || (status == BankSlipStatusSettled && r.Status == BankSlipStatusPaid)
+}
+
+// StatusReason is the reason of the status change in the history of the bank
+// slip.
+func (r *BankSlipReturnRecord) StatusReason() string {
+	reason := fmt.Sprintf("occurrence %s of return file %s", r.OccurrenceCode, r.ReturnFileId)
+	if r.Status == BankSlipStatusCancelled {
+		return "written off by the bank, " + reason
+	}
+	return reason
+}
+
+// DebtIdPrefix is the start of the debt id sent in the control number, as
+// written in the remittances: upper case, without hyphens.
+func (r *BankSlipReturnRecord) DebtIdPrefix() string {
+	prefix := strings.ToUpper(strings.ReplaceAll(r.ControlNumber, "-", ""))
+	if len(prefix) > controlNumberLength {
+		prefix = prefix[:controlNumberLength]
+	}
+	return prefix
+}
+
+type UnmatchedReturnRecordRepository interface {
+	InsertMany(unmatchedRecords []*UnmatchedReturnRecord) error
+	ListByReturnFileId(returnFileId string) ([]*UnmatchedReturnRecord, error)
+}
+
+// UnmatchedReturnRecord is a record of a return file that moved no bank slip,
+// kept to be checked by hand.
+type UnmatchedReturnRecord struct {
+	ReturnFileId   string
+	LineNumber     int
+	RawRecord      string
+	OccurrenceCode string
+	OurNumber      int64
+	ControlNumber  string
+	Reason         UnmatchedReason
+}
+
+func NewUnmatchedReturnRecord(record *BankSlipReturnRecord, reason UnmatchedReason) *UnmatchedReturnRecord {
+	return &UnmatchedReturnRecord{
+		ReturnFileId:   record.ReturnFileId,
+		LineNumber:     record.LineNumber,
+		RawRecord:      record.RawRecord,
+		OccurrenceCode: record.OccurrenceCode,
+		OurNumber:      record.OurNumber,
+		ControlNumber:  record.ControlNumber,
+		Reason:         reason,
+	}
+}
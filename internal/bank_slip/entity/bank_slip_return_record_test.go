@@ -0,0 +1,88 @@
+package bank_slip
+
+import (
+	"testing"
+	"time"
+
+	"performatic-file-processor/internal/cnab"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBankSlipReturnRecord_ShouldMoveBankSlipByOccurrence(t *testing.T) {
+	cases := map[cnab.Occurrence]BankSlipStatus{
+		cnab.OccurrencePaid:       BankSlipStatusPaid,
+		cnab.OccurrenceRejected:   BankSlipStatusRejectedByBank,
+		cnab.OccurrenceWrittenOff: BankSlipStatusCancelled,
+		cnab.OccurrenceConfirmed:  "",
+		cnab.OccurrenceOther:      "",
+	}
+	for occurrence, status := range cases {
+		record := NewBankSlipReturnRecord("return_file_id", cnab.ReturnRecord{Occurrence: occurrence})
+		assert.Equal(t, status, record.Status, occurrence)
+		assert.Equal(t, status != "", record.Reconciles(), occurrence)
+	}
+}
+
+func TestNewBankSlipReturnRecord_ShouldKeepPayment(t *testing.T) {
+	paidAt := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	record := NewBankSlipReturnRecord("return_file_id", cnab.ReturnRecord{
+		Line:              7,
+		Code:              "06",
+		Occurrence:        cnab.OccurrencePaid,
+		OurNumber:         42,
+		OccurredAt:        paidAt,
+		PaidAmountInCents: 100050,
+		FeeInCents:        180,
+	})
+
+	assert.Equal(t, 7, record.LineNumber)
+	assert.Equal(t, Money(100050), record.PaidAmount)
+	assert.Equal(t, Money(180), record.BankFee)
+	assert.Equal(t, paidAt, record.PaidAt)
+	assert.Equal(t, BankSlipStatusPaid, record.Status)
+	assert.Equal(t, []BankSlipStatus{BankSlipStatusSuccess, BankSlipStatusSendingEmailError, BankSlipStatusOverdue, BankSlipStatusExpired}, record.FromStatuses())
+}
+
+func TestNewBankSlipReturnRecord_ShouldSettlePaymentCreditedToTheBeneficiary(t *testing.T) {
+	record := NewBankSlipReturnRecord("return_file_id", cnab.ReturnRecord{
+		Code:              "06",
+		Occurrence:        cnab.OccurrencePaid,
+		OccurredAt:        time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		CreditedAt:        time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC),
+		PaidAmountInCents: 100050,
+	})
+
+	assert.Equal(t, BankSlipStatusSettled, record.Status)
+	assert.Equal(t, Money(100050), record.PaidAmount)
+	assert.Equal(t, []BankSlipStatus{BankSlipStatusSuccess, BankSlipStatusSendingEmailError, BankSlipStatusPaid, BankSlipStatusOverdue, BankSlipStatusExpired}, record.FromStatuses())
+	assert.True(t, record.AppliedTo(BankSlipStatusSettled))
+	assert.False(t, record.AppliedTo(BankSlipStatusPaid))
+}
+
+func TestNewBankSlipReturnRecord_ShouldCancelWrittenOffBankSlipsRegisteredAtTheBank(t *testing.T) {
+	record := NewBankSlipReturnRecord("return_file_id", cnab.ReturnRecord{Code: "09", Occurrence: cnab.OccurrenceWrittenOff})
+
+	assert.Equal(t, BankSlipStatusCancelled, record.Status)
+	assert.Equal(t, []BankSlipStatus{
+		BankSlipStatusSuccess,
+		BankSlipStatusSendingEmailError,
+		BankSlipStatusRejectedByBank,
+		BankSlipStatusOverdue,
+		BankSlipStatusExpired,
+	}, record.FromStatuses())
+	assert.Equal(t, "written off by the bank, occurrence 09 of return file return_file_id", record.StatusReason())
+}
+
+func TestBankSlipReturnRecord_ShouldTreatPaymentOfSettledBankSlipAsApplied(t *testing.T) {
+	record := &BankSlipReturnRecord{Status: BankSlipStatusPaid}
+
+	assert.True(t, record.AppliedTo(BankSlipStatusSettled))
+	assert.True(t, record.AppliedTo(BankSlipStatusPaid))
+	assert.False(t, record.AppliedTo(BankSlipStatusSuccess))
+}
+
+func TestBankSlipReturnRecord_DebtIdPrefix(t *testing.T) {
+	record := &BankSlipReturnRecord{ControlNumber: "ea23f2ca-663a-4266-a742-9da4c1b2c3d4"}
+	assert.Equal(t, "EA23F2CA663A4266A7429DA4C", record.DebtIdPrefix())
+}
@@ -0,0 +1,210 @@
+package bank_slip
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"performatic-file-processor/internal/calendar"
+)
+
+// BankSlipRowLayout is the resolved form of an upload profile for a given
+// header. It travels with each chunk so workers don't resolve the header again.
+// Rows of fixed-width files are cut by FixedWidth instead of a delimiter.
+// ChargePolicy is the one of the upload, the charge columns of a row override
+// it. DueDatePolicy is the one of the upload profile, applied with the
+// BusinessDays calendar the services set, national holidays only when unset.
+type BankSlipRowLayout struct {
+	Positions        map[BankSlipField]int `json:"positions"`
+	Columns          int                   `json:"columns"`
+	DateLayout       string                `json:"dateLayout"`
+	DecimalSeparator string                `json:"decimalSeparator"`
+	Delimiter        string                `json:"delimiter"`
+	FixedWidth       []FixedWidthField     `json:"fixedWidth,omitempty"`
+	ChargePolicy     ChargePolicy          `json:"chargePolicy,omitzero"`
+	DueDatePolicy    DueDatePolicy         `json:"dueDatePolicy,omitempty"`
+	BusinessDays     *calendar.Calendar    `json:"-"`
+}
+
+const DefaultDelimiter = ','
+
+func NewBankSlipRowLayoutFromMessage(data any) (*BankSlipRowLayout, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var layout BankSlipRowLayout
+	if err := json.Unmarshal(encoded, &layout); err != nil {
+		return nil, err
+	}
+	for _, field := range BankSlipRequiredFields {
+		if _, ok := layout.Positions[field]; !ok {
+			return nil, fmt.Errorf("layout is missing position for %s", field)
+		}
+	}
+	if layout.DueDatePolicy == "" {
+		layout.DueDatePolicy = DueDatePolicyKeep
+	}
+	return &layout, nil
+}
+
+func (l *BankSlipRowLayout) ToMessage() map[string]any {
+	positions := map[string]any{}
+	for field, position := range l.Positions {
+		positions[string(field)] = position
+	}
+	message := map[string]any{
+		"positions":        positions,
+		"columns":          l.Columns,
+		"dateLayout":       l.DateLayout,
+		"decimalSeparator": l.DecimalSeparator,
+		"delimiter":        l.Delimiter,
+	}
+	if l.IsFixedWidth() {
+		message["fixedWidth"] = l.FixedWidth
+	}
+	if l.ChargePolicy != (ChargePolicy{}) {
+		message["chargePolicy"] = l.ChargePolicy
+	}
+	if l.DueDatePolicy != "" && l.DueDatePolicy != DueDatePolicyKeep {
+		message["dueDatePolicy"] = l.DueDatePolicy
+	}
+	return message
+}
+
+func (l *BankSlipRowLayout) IsFixedWidth() bool {
+	return len(l.FixedWidth) > 0
+}
+
+// Split returns the columns of a row, cut by the delimiter or by the
+// positions of the fixed-width fields.
+func (l *BankSlipRowLayout) Split(row string) ([]string, error) {
+	if l.IsFixedWidth() {
+		return splitFixedWidth(row, l.FixedWidth)
+	}
+	return ParseCSVRecord(row, l.Comma())
+}
+
+func (l *BankSlipRowLayout) Comma() rune {
+	if l.Delimiter == "" {
+		return DefaultDelimiter
+	}
+	return []rune(l.Delimiter)[0]
+}
+
+// DueDate reads the due date of a row and applies the due date policy to it.
+func (l *BankSlipRowLayout) DueDate(rowItems []string) (time.Time, error) {
+	dueDate, err := time.Parse(l.DateLayout, l.Value(rowItems, BankSlipFieldDebtDueDate))
+	if err != nil {
+		return time.Time{}, err
+	}
+	businessDays := l.BusinessDays
+	if businessDays == nil {
+		businessDays = calendar.New()
+	}
+	return l.DueDatePolicy.Apply(dueDate, businessDays)
+}
+
+func (l *BankSlipRowLayout) Value(rowItems []string, field BankSlipField) string {
+	return strings.TrimSpace(rowItems[l.Positions[field]])
+}
+
+// RowChargePolicy is the charge policy of the upload with the charge columns
+// the row fills.
+func (l *BankSlipRowLayout) RowChargePolicy(rowItems []string) (ChargePolicy, error) {
+	values := map[BankSlipField]string{}
+	for _, field := range BankSlipChargePolicyFields {
+		if _, ok := l.Positions[field]; ok {
+			values[field] = l.Value(rowItems, field)
+		}
+	}
+	if len(values) == 0 {
+		return l.ChargePolicy, nil
+	}
+	rowPolicy, err := ParseChargePolicy(values, l.DecimalSeparator)
+	if err != nil {
+		return ChargePolicy{}, err
+	}
+	return l.ChargePolicy.Override(rowPolicy), nil
+}
+
+// RowInstallmentPlan is the installment plan of the installment columns the
+// row fills.
+func (l *BankSlipRowLayout) RowInstallmentPlan(rowItems []string) (InstallmentPlan, error) {
+	values := map[BankSlipField]string{}
+	for _, field := range BankSlipInstallmentFields {
+		if _, ok := l.Positions[field]; ok {
+			values[field] = l.Value(rowItems, field)
+		}
+	}
+	return ParseInstallmentPlan(values[BankSlipFieldInstallments], values[BankSlipFieldInstallmentDay])
+}
+
+var (
+	plainDecimalPattern   = map[string]*regexp.Regexp{".": regexp.MustCompile(`^-?\d+(\.\d+)?$`), ",": regexp.MustCompile(`^-?\d+(,\d+)?$`)}
+	groupedDecimalPattern = map[string]*regexp.Regexp{".": regexp.MustCompile(`^-?\d{1,3}(,\d{3})+(\.\d+)?$`), ",": regexp.MustCompile(`^-?\d{1,3}(\.\d{3})+(,\d+)?$`)}
+)
+
+// NormalizeDecimal rewrites an amount written with the layout decimal
+// separator (and optional, well formed thousands grouping) as "1234.56".
+func (l *BankSlipRowLayout) NormalizeDecimal(value string) (string, error) {
+	decimalSeparator := l.DecimalSeparator
+	if decimalSeparator == "" {
+		decimalSeparator = "."
+	}
+	thousandsSeparator := ","
+	if decimalSeparator == "," {
+		thousandsSeparator = "."
+	}
+
+	switch {
+	case plainDecimalPattern[decimalSeparator].MatchString(value):
+	case groupedDecimalPattern[decimalSeparator].MatchString(value):
+		value = strings.ReplaceAll(value, thousandsSeparator, "")
+	default:
+		return "", fmt.Errorf("invalid decimal %s", value)
+	}
+	return strings.Replace(value, decimalSeparator, ".", 1), nil
+}
+
+// SniffDecimalSeparator looks for an amount whose separator can only be a
+// decimal one: both separators present, the same separator repeated (it is the
+// thousands one) or a single separator not followed by exactly three digits.
+// Amounts like "1.234" are ambiguous and an empty string means undecided.
+func SniffDecimalSeparator(amounts []string) string {
+	for _, amount := range amounts {
+		amount = strings.TrimSpace(amount)
+		lastDot, lastComma := strings.LastIndex(amount, "."), strings.LastIndex(amount, ",")
+
+		switch {
+		case lastDot != -1 && lastComma != -1:
+			if lastDot > lastComma {
+				return "."
+			}
+			return ","
+		case lastDot != -1:
+			if decimal, ok := sniffSingleSeparator(amount, ".", lastDot); ok {
+				return decimal
+			}
+		case lastComma != -1:
+			if decimal, ok := sniffSingleSeparator(amount, ",", lastComma); ok {
+				return decimal
+			}
+		}
+	}
+	return ""
+}
+
+func sniffSingleSeparator(amount, separator string, last int) (string, bool) {
+	other := map[string]string{".": ",", ",": "."}[separator]
+	if strings.Count(amount, separator) > 1 {
+		return other, true
+	}
+	if len(amount)-last-1 != 3 {
+		return separator, true
+	}
+	return "", false
+}
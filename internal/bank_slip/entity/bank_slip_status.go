@@ -0,0 +1,142 @@
+package bank_slip
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// BankSlipStatuses lists every status a bank slip can be in, in the order of
+// its lifecycle.
+var BankSlipStatuses = []BankSlipStatus{
+	BankSlipStatusScheduled,
+	BankSlipStatusPending,
+	BankSlipStatusSuccess,
+	BankSlipStatusGenerateBillingError,
+	BankSlipStatusSendingEmailError,
+	BankSlipStatusPaid,
+	BankSlipStatusRejectedByBank,
+	BankSlipStatusSettled,
+	BankSlipStatusOverdue,
+	BankSlipStatusExpired,
+	BankSlipStatusCancelled,
+	BankSlipStatusReissued,
+}
+
+// BankSlipIssuedStatuses are the statuses of the bank slips whose boleto is
+// out with the payer and can still be paid: neither paid, cancelled nor
+// reissued, even once overdue or expired or when the email failed.
+var BankSlipIssuedStatuses = []BankSlipStatus{
+	BankSlipStatusSuccess,
+	BankSlipStatusSendingEmailError,
+	BankSlipStatusOverdue,
+	BankSlipStatusExpired,
+}
+
+// bankSlipStatusTransitions are the statuses a bank slip can move to from
+// each status. Settled, cancelled and reissued bank slips never change again.
+var bankSlipStatusTransitions = map[BankSlipStatus][]BankSlipStatus{
+	BankSlipStatusScheduled: {BankSlipStatusPending, BankSlipStatusCancelled},
+	BankSlipStatusPending: {
+		BankSlipStatusSuccess,
+		BankSlipStatusGenerateBillingError,
+		BankSlipStatusSendingEmailError,
+		BankSlipStatusCancelled,
+	},
+	// A failed billing is retried through the API once what made it fail, as
+	// the beneficiary of the bank slip, is fixed.
+	BankSlipStatusGenerateBillingError: {BankSlipStatusPending, BankSlipStatusCancelled},
+	// A bank slip whose email failed is issued all the same, and goes on as
+	// one whose email was sent.
+	BankSlipStatusSendingEmailError: {
+		BankSlipStatusSuccess,
+		BankSlipStatusPaid,
+		BankSlipStatusRejectedByBank,
+		BankSlipStatusSettled,
+		BankSlipStatusOverdue,
+		BankSlipStatusExpired,
+		BankSlipStatusCancelled,
+		BankSlipStatusReissued,
+	},
+	BankSlipStatusSuccess: {
+		BankSlipStatusPaid,
+		BankSlipStatusRejectedByBank,
+		BankSlipStatusSettled,
+		BankSlipStatusOverdue,
+		BankSlipStatusExpired,
+		BankSlipStatusCancelled,
+		BankSlipStatusReissued,
+	},
+	BankSlipStatusRejectedByBank: {BankSlipStatusCancelled, BankSlipStatusReissued},
+	// The bank credits a payment after reporting it.
+	BankSlipStatusPaid: {BankSlipStatusSettled},
+	BankSlipStatusOverdue: {
+		BankSlipStatusPaid,
+		BankSlipStatusSettled,
+		BankSlipStatusExpired,
+		BankSlipStatusCancelled,
+		BankSlipStatusReissued,
+	},
+	BankSlipStatusExpired: {
+		BankSlipStatusPaid,
+		BankSlipStatusSettled,
+		BankSlipStatusCancelled,
+		BankSlipStatusReissued,
+	},
+}
+
+func (status BankSlipStatus) CanTransitionTo(to BankSlipStatus) bool {
+	return slices.Contains(bankSlipStatusTransitions[status], to)
+}
+
+// BankSlipStatusesTo returns the statuses a bank slip can move to the status
+// from, in the order of BankSlipStatuses.
+func BankSlipStatusesTo(to BankSlipStatus) []BankSlipStatus {
+	from := []BankSlipStatus{}
+	for _, status := range BankSlipStatuses {
+		if status.CanTransitionTo(to) {
+			from = append(from, status)
+		}
+	}
+	return from
+}
+
+// TransitionTo moves the bank slip to the status, failing with
+// ErrUnexpectedBankSlipStatus when it can't get there from the current one. A
+// bank slip without status is a new one, as pending.
+func (bankSlip *BankSlip) TransitionTo(status BankSlipStatus) error {
+	current := bankSlip.Status
+	if current == "" {
+		current = BankSlipStatusPending
+	}
+	if !current.CanTransitionTo(status) {
+		return fmt.Errorf("%w: %s can't become %s", ErrUnexpectedBankSlipStatus, bankSlip.Status, status)
+	}
+	bankSlip.Status = status
+	return nil
+}
+
+type BankSlipStatusHistoryRepository interface {
+	ListByDebtId(debtId string) ([]*BankSlipStatusChange, error)
+}
+
+// BankSlipStatusChange is a step of the timeline of a bank slip, written with
+// every status change.
+type BankSlipStatusChange struct {
+	DebtId     string
+	FromStatus BankSlipStatus
+	ToStatus   BankSlipStatus
+	// Reason is the error message of failed statuses or the return file that
+	// reported the change, empty when there is nothing to add.
+	Reason    string
+	ChangedAt time.Time
+}
+
+func NewBankSlipStatusChange(debtId string, fromStatus, toStatus BankSlipStatus, reason string) *BankSlipStatusChange {
+	return &BankSlipStatusChange{
+		DebtId:     debtId,
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+		Reason:     reason,
+	}
+}
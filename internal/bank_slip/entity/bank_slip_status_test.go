@@ -0,0 +1,78 @@
+package bank_slip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBankSlip_TransitionToShouldFollowTheLifecycle(t *testing.T) {
+	bankSlip := &BankSlip{Status: BankSlipStatusPending}
+
+	assert.NoError(t, bankSlip.TransitionTo(BankSlipStatusSuccess))
+	assert.NoError(t, bankSlip.TransitionTo(BankSlipStatusExpired))
+	assert.NoError(t, bankSlip.TransitionTo(BankSlipStatusPaid))
+	assert.Equal(t, BankSlipStatusPaid, bankSlip.Status)
+}
+
+func TestBankSlip_TransitionToShouldRejectIllegalTransitions(t *testing.T) {
+	illegal := map[BankSlipStatus]BankSlipStatus{
+		BankSlipStatusPending:              BankSlipStatusPaid,
+		BankSlipStatusGenerateBillingError: BankSlipStatusSuccess,
+		BankSlipStatusSuccess:              BankSlipStatusPending,
+		BankSlipStatusPaid:                 BankSlipStatusCancelled,
+		BankSlipStatusSettled:              BankSlipStatusPaid,
+		BankSlipStatusCancelled:            BankSlipStatusSuccess,
+		BankSlipStatusReissued:             BankSlipStatusPaid,
+	}
+	for from, to := range illegal {
+		bankSlip := &BankSlip{Status: from}
+
+		err := bankSlip.TransitionTo(to)
+		assert.ErrorIs(t, err, ErrUnexpectedBankSlipStatus, "%s to %s", from, to)
+		assert.Equal(t, from, bankSlip.Status)
+	}
+}
+
+func TestBankSlip_ShouldTreatBankSlipWithoutStatusAsPending(t *testing.T) {
+	bankSlip := &BankSlip{}
+
+	assert.NoError(t, bankSlip.ErrorGeneratingBilling("billing error"))
+	assert.Equal(t, BankSlipStatusGenerateBillingError, bankSlip.Status)
+	assert.Equal(t, "billing error", *bankSlip.ErrorMessage)
+}
+
+func TestBankSlip_ErrorSendingEmailShouldKeepStatusOfIllegalTransition(t *testing.T) {
+	bankSlip := &BankSlip{Status: BankSlipStatusPaid}
+
+	assert.ErrorIs(t, bankSlip.ErrorSendingEmail("email error"), ErrUnexpectedBankSlipStatus)
+	assert.Equal(t, BankSlipStatusPaid, bankSlip.Status)
+	assert.Nil(t, bankSlip.ErrorMessage)
+}
+
+func TestBankSlip_RetryBillingShouldTakeFailedBillingBackToPending(t *testing.T) {
+	bankSlip := &BankSlip{}
+	assert.NoError(t, bankSlip.ErrorGeneratingBilling("billing error"))
+
+	assert.NoError(t, bankSlip.RetryBilling())
+	assert.Equal(t, BankSlipStatusPending, bankSlip.Status)
+	assert.Nil(t, bankSlip.ErrorMessage)
+}
+
+func TestBankSlip_RetryBillingShouldOnlyRetryFailedBillings(t *testing.T) {
+	for _, status := range []BankSlipStatus{BankSlipStatusScheduled, BankSlipStatusPending, BankSlipStatusSendingEmailError, BankSlipStatusCancelled} {
+		bankSlip := &BankSlip{Status: status}
+
+		assert.ErrorIs(t, bankSlip.RetryBilling(), ErrUnexpectedBankSlipStatus, status)
+		assert.Equal(t, status, bankSlip.Status)
+	}
+}
+
+func TestBankSlipStatusesTo(t *testing.T) {
+	assert.Equal(t, []BankSlipStatus{BankSlipStatusSuccess, BankSlipStatusSendingEmailError, BankSlipStatusOverdue, BankSlipStatusExpired}, BankSlipStatusesTo(BankSlipStatusPaid))
+	assert.Equal(t, []BankSlipStatus{BankSlipStatusSuccess, BankSlipStatusSendingEmailError, BankSlipStatusPaid, BankSlipStatusOverdue, BankSlipStatusExpired}, BankSlipStatusesTo(BankSlipStatusSettled))
+	assert.Equal(t, []BankSlipStatus{BankSlipStatusSuccess, BankSlipStatusSendingEmailError}, BankSlipStatusesTo(BankSlipStatusRejectedByBank))
+	assert.Equal(t, []BankSlipStatus{BankSlipStatusSuccess, BankSlipStatusSendingEmailError}, BankSlipStatusesTo(BankSlipStatusOverdue))
+	assert.Equal(t, []BankSlipStatus{BankSlipStatusScheduled, BankSlipStatusGenerateBillingError}, BankSlipStatusesTo(BankSlipStatusPending))
+	assert.Empty(t, BankSlipStatusesTo(BankSlipStatusScheduled))
+}
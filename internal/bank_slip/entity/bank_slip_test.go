@@ -11,10 +11,10 @@ import (
 
 func TestNewBankSlip(t *testing.T) {
 	debtDueDate, _ := time.Parse("2006-01-02", "2023-12-31")
-	bankSlip := newBankSlip(123, 1000.50, debtDueDate, "debt123", "John Doe", "john.doe@example.com", "file123", BankSlipStatusPending)
+	bankSlip := newBankSlip("12345678909", 100050, debtDueDate, "debt123", "John Doe", "john.doe@example.com", "file123", BankSlipStatusPending)
 
-	assert.Equal(t, 123, bankSlip.GovernmentId)
-	assert.Equal(t, 1000.50, bankSlip.DebtAmount)
+	assert.Equal(t, GovernmentId("12345678909"), bankSlip.GovernmentId)
+	assert.Equal(t, Money(100050), bankSlip.DebtAmount)
 	assert.Equal(t, debtDueDate, bankSlip.DebtDueDate)
 	assert.Equal(t, "debt123", bankSlip.DebtId)
 	assert.Equal(t, "John Doe", bankSlip.UserName)
@@ -25,15 +25,15 @@ func TestNewBankSlip(t *testing.T) {
 
 func TestNewBankSlipFromRow(t *testing.T) {
 	header := "name,governmentId,email,debtAmount,debtDueDate,debtId"
-	data := "John Doe,123,john.doe@example.com,1000.50,2023-12-31,debt123"
+	data := "John Doe,12345678909,john.doe@example.com,1000.50,2023-12-31,debt123"
 	fileMetadataId := "file123"
 
 	bankSlip, err := NewBankSlipFromRow(fileMetadataId, data, header)
 	assert.NoError(t, err)
 
 	debtDueDate, _ := time.Parse("2006-01-02", "2023-12-31")
-	assert.Equal(t, 123, bankSlip.GovernmentId)
-	assert.Equal(t, 1000.50, bankSlip.DebtAmount)
+	assert.Equal(t, GovernmentId("12345678909"), bankSlip.GovernmentId)
+	assert.Equal(t, Money(100050), bankSlip.DebtAmount)
 	assert.Equal(t, debtDueDate, bankSlip.DebtDueDate)
 	assert.Equal(t, "debt123", bankSlip.DebtId)
 	assert.Equal(t, "John Doe", bankSlip.UserName)
@@ -63,7 +63,8 @@ func TestNewBankSlipFromRow_FileMetadataIsEmpty(t *testing.T) {
 	bankSlip, err := NewBankSlipFromRow(fileMetadataId, data, header)
 	assert.Error(t, err)
 	assert.Nil(t, bankSlip)
-	assert.Equal(t, errors.New("error rowItems and headerItems length are different (file id: file123)"), err)
+	assert.EqualError(t, err, "error rowItems and headerItems length are different (file id: file123)")
+	assert.Equal(t, RejectionCodeColumnCountMismatch, RejectionCodeOf(err))
 }
 
 func TestNewBankSlipFromRow_RowIsEmpty(t *testing.T) {
@@ -101,34 +102,69 @@ func TestNewBankSlipFromRow_InvalidGovernmentIdError(t *testing.T) {
 	bankSlip, err := NewBankSlipFromRow(fileMetadataId, data, header)
 	assert.Error(t, err)
 	assert.Nil(t, bankSlip)
-	assert.Equal(t, errors.New("error converting governmentId to int abc Position: 1 (file id: file123)"), err)
+	assert.EqualError(t, err, "error converting governmentId to CPF or CNPJ abc Position: 1 (file id: file123): invalid government id: \"abc\" is neither a CPF nor a CNPJ")
+	assert.Equal(t, RejectionCodeInvalidGovernmentId, RejectionCodeOf(err))
 }
 
 func TestNewBankSlipFromRow_DebitAmountError(t *testing.T) {
 	header := "name,governmentId,email,debtAmount,debtDueDate,debtId"
-	data := "John Doe,123,john.doe@example.com,cde,2023-12-31,debt123"
+	data := "John Doe,12345678909,john.doe@example.com,cde,2023-12-31,debt123"
 	fileMetadataId := "file123"
 
 	bankSlip, err := NewBankSlipFromRow(fileMetadataId, data, header)
 	assert.Error(t, err)
 	assert.Nil(t, bankSlip)
-	assert.Equal(t, errors.New("error converting debtAmount to float64 cde Position: 3 (file id: file123)"), err)
+	assert.EqualError(t, err, "error converting debtAmount to money cde Position: 3 (file id: file123)")
+	assert.Equal(t, RejectionCodeInvalidAmount, RejectionCodeOf(err))
+}
+
+func TestNewBankSlipFromRow_DebitAmountWithMoreThanTwoDecimalPlaces(t *testing.T) {
+	header := "name,governmentId,email,debtAmount,debtDueDate,debtId"
+	data := "John Doe,12345678909,john.doe@example.com,1999.995,2023-12-31,debt123"
+
+	bankSlip, err := NewBankSlipFromRow("file123", data, header)
+	assert.Nil(t, bankSlip)
+	assert.ErrorIs(t, err, ErrMoneyTooManyDecimals)
+	assert.Equal(t, RejectionCodeInvalidAmount, RejectionCodeOf(err))
+}
+
+func TestNewBankSlipFromRow_DebitAmountNotPositive(t *testing.T) {
+	header := "name,governmentId,email,debtAmount,debtDueDate,debtId"
+
+	for _, amount := range []string{"0.00", "-10.00"} {
+		data := "John Doe,12345678909,john.doe@example.com," + amount + ",2023-12-31,debt123"
+
+		bankSlip, err := NewBankSlipFromRow("file123", data, header)
+		assert.Nil(t, bankSlip)
+		assert.ErrorIs(t, err, ErrMoneyNotPositive)
+		assert.Equal(t, RejectionCodeInvalidAmount, RejectionCodeOf(err))
+	}
+}
+
+func TestNewBankSlipFromRow_DebitAmountAboveNumericLimit(t *testing.T) {
+	header := "name,governmentId,email,debtAmount,debtDueDate,debtId"
+	data := "John Doe,12345678909,john.doe@example.com,123456789.99,2023-12-31,debt123"
+
+	bankSlip, err := NewBankSlipFromRow("file123", data, header)
+	assert.NoError(t, err)
+	assert.Equal(t, Money(12345678999), bankSlip.DebtAmount)
 }
 
 func TestNewBankSlipFromRow_DebitDueDateError(t *testing.T) {
 	header := "name,governmentId,email,debtAmount,debtDueDate,debtId"
-	data := "John Doe,123,john.doe@example.com,1500.5,2023-12-32,debt123"
+	data := "John Doe,12345678909,john.doe@example.com,1500.5,2023-12-32,debt123"
 	fileMetadataId := "file123"
 
 	bankSlip, err := NewBankSlipFromRow(fileMetadataId, data, header)
 	assert.Error(t, err)
 	assert.Nil(t, bankSlip)
-	assert.Equal(t, errors.New("error converting debtDueDate to time.Time 2023-12-32 Position: 4 (file id: file123)"), err)
+	assert.EqualError(t, err, "error converting debtDueDate to time.Time 2023-12-32 Position: 4 (file id: file123)")
+	assert.Equal(t, RejectionCodeInvalidDueDate, RejectionCodeOf(err))
 }
 
 func TestUpdateRowToErrorGeneratingBilling(t *testing.T) {
 	debtDueDate, _ := time.Parse("2006-01-02", "2023-12-31")
-	bankSlip := newBankSlip(123, 1000.50, debtDueDate, "debt123", "John Doe", "john.doe@example.com", "file123", BankSlipStatusPending)
+	bankSlip := newBankSlip("12345678909", 100050, debtDueDate, "debt123", "John Doe", "john.doe@example.com", "file123", BankSlipStatusPending)
 
 	errorMessage := "Any error"
 	bankSlip.ErrorGeneratingBilling(errorMessage)
@@ -139,7 +175,7 @@ func TestUpdateRowToErrorGeneratingBilling(t *testing.T) {
 
 func TestUpdateRowToErrorSendingEmail(t *testing.T) {
 	debtDueDate, _ := time.Parse("2006-01-02", "2023-12-31")
-	bankSlip := newBankSlip(123, 1000.50, debtDueDate, "debt123", "John Doe", "john.doe@example.com", "file123", BankSlipStatusPending)
+	bankSlip := newBankSlip("12345678909", 100050, debtDueDate, "debt123", "John Doe", "john.doe@example.com", "file123", BankSlipStatusPending)
 
 	errorMessage := "Any error"
 	bankSlip.ErrorSendingEmail(errorMessage)
@@ -150,9 +186,71 @@ func TestUpdateRowToErrorSendingEmail(t *testing.T) {
 
 func TestUpdateRowToSuccess(t *testing.T) {
 	debtDueDate, _ := time.Parse("2006-01-02", "2023-12-31")
-	bankSlip := newBankSlip(123, 1000.50, debtDueDate, "debt123", "John Doe", "john.doe@example.com", "file123", BankSlipStatusPending)
+	bankSlip := newBankSlip("12345678909", 100050, debtDueDate, "debt123", "John Doe", "john.doe@example.com", "file123", BankSlipStatusPending)
 
 	bankSlip.Success()
 
 	assert.Equal(t, bankSlip.Status, BankSlipStatusSuccess)
 }
+
+func TestNewBankSlipFromRow_ShouldParseQuotedFields(t *testing.T) {
+	header := "name,governmentId,email,debtAmount,debtDueDate,debtId"
+	data := "\"Santos, \"\"Elijah\"\"\nJr\",12345678909,john.doe@example.com,1000.50,2023-12-31,debt123"
+
+	bankSlip, err := NewBankSlipFromRow("file123", data, header)
+	assert.NoError(t, err)
+	assert.Equal(t, "Santos, \"Elijah\"\nJr", bankSlip.UserName)
+	assert.Equal(t, "debt123", bankSlip.DebtId)
+}
+
+func TestNewBankSlipFromRow_ShouldRejectMalformedQuotes(t *testing.T) {
+	header := "name,governmentId,email,debtAmount,debtDueDate,debtId"
+	data := "\"Santos\" Elijah,12345678909,john.doe@example.com,1000.50,2023-12-31,debt123"
+
+	bankSlip, err := NewBankSlipFromRow("file123", data, header)
+	assert.Error(t, err)
+	assert.Nil(t, bankSlip)
+}
+
+func TestNewBankSlipFromRow_GovernmentIdWithWrongCheckDigits(t *testing.T) {
+	header := "name,governmentId,email,debtAmount,debtDueDate,debtId"
+	data := "John Doe,123.456.789-10,john.doe@example.com,1000.50,2023-12-31,debt123"
+
+	bankSlip, err := NewBankSlipFromRow("file123", data, header)
+	assert.Nil(t, bankSlip)
+	assert.ErrorIs(t, err, ErrInvalidGovernmentIdCheckDigits)
+	assert.Equal(t, RejectionCodeInvalidGovernmentIdCheckDigits, RejectionCodeOf(err))
+}
+
+func TestAttachBoleto(t *testing.T) {
+	debtDueDate, _ := time.Parse("2006-01-02", "2025-12-31")
+	bankSlip := newBankSlip("12345678909", 100050, debtDueDate, "debt123", "John Doe", "john.doe@example.com", "file123", BankSlipStatusPending)
+
+	bankSlip.AttachBoleto("23792131200001000501234090000000004200123450", "23791234059000000000142001234501213120000100050")
+
+	assert.Equal(t, "23792131200001000501234090000000004200123450", bankSlip.Barcode)
+	assert.Equal(t, "23791234059000000000142001234501213120000100050", bankSlip.DigitableLine)
+}
+
+func TestIssued_ShouldHandBoletoOutUntilItIsPaidOrReplaced(t *testing.T) {
+	issued := map[BankSlipStatus]bool{
+		BankSlipStatusPending:              false,
+		BankSlipStatusGenerateBillingError: false,
+		BankSlipStatusSuccess:              true,
+		BankSlipStatusSendingEmailError:    true,
+		BankSlipStatusOverdue:              true,
+		BankSlipStatusExpired:              true,
+		BankSlipStatusRejectedByBank:       false,
+		BankSlipStatusPaid:                 false,
+		BankSlipStatusSettled:              false,
+		BankSlipStatusCancelled:            false,
+		BankSlipStatusReissued:             false,
+	}
+	for status, expected := range issued {
+		bankSlip := &BankSlip{Status: status, Barcode: "23792131200001000501234090000000004200123450"}
+		assert.Equal(t, expected, bankSlip.Issued(), status)
+	}
+
+	withoutBarcode := &BankSlip{Status: BankSlipStatusSuccess}
+	assert.False(t, withoutBarcode.Issued())
+}
@@ -0,0 +1,133 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"performatic-file-processor/internal/boleto"
+	"performatic-file-processor/internal/cnab"
+)
+
+const maxAgreementCodeLength = 20
+
+var (
+	ErrBeneficiaryNotFound      = errors.New("beneficiary not found")
+	ErrBeneficiaryAlreadyExists = errors.New("beneficiary already exists")
+	ErrInvalidBeneficiary       = errors.New("invalid beneficiary")
+)
+
+var agreementCodePattern = regexp.MustCompile(`^\d*$`)
+
+type BeneficiaryRepository interface {
+	Insert(beneficiary *Beneficiary) error
+	GetById(id string) (*Beneficiary, error)
+	List() ([]*Beneficiary, error)
+}
+
+// Beneficiary ("cedente") is the bank account the bank slips of an upload are
+// issued to. Each one numbers its bank slips ("nosso número") on its own.
+type Beneficiary struct {
+	ID       string
+	Name     string
+	Document GovernmentId
+	BankCode string
+	Agency   string
+	Account  string
+	Wallet   string
+	// AgreementCode ("código do convênio") identifies the beneficiary at the
+	// bank in the remittance files.
+	AgreementCode string
+	// ChargePolicy is the default of the bank slips issued to the beneficiary,
+	// uploads and rows can override it.
+	ChargePolicy ChargePolicy
+	CreatedAt    time.Time
+}
+
+// NewBeneficiary validates the account and pads agency, account and wallet
+// with leading zeros.
+func NewBeneficiary(name, document, bankCode, agency, account, wallet, agreementCode string) (*Beneficiary, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name must be not empty", ErrInvalidBeneficiary)
+	}
+
+	governmentId, err := ParseGovernmentId(document)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBeneficiary, err)
+	}
+
+	issuer, err := boleto.NewIssuer(strings.TrimSpace(bankCode), strings.TrimSpace(agency), strings.TrimSpace(wallet), strings.TrimSpace(account))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBeneficiary, err)
+	}
+
+	agreementCode = strings.TrimSpace(agreementCode)
+	if len(agreementCode) > maxAgreementCodeLength || !agreementCodePattern.MatchString(agreementCode) {
+		return nil, fmt.Errorf("%w: agreement code must have up to %d digits", ErrInvalidBeneficiary, maxAgreementCodeLength)
+	}
+
+	return &Beneficiary{
+		Name:          name,
+		Document:      governmentId,
+		BankCode:      issuer.BankCode,
+		Agency:        issuer.Agency,
+		Account:       issuer.Account,
+		Wallet:        issuer.Wallet,
+		AgreementCode: agreementCode,
+	}, nil
+}
+
+// Issuer is the account the boletos of the beneficiary are generated with.
+func (b *Beneficiary) Issuer() boleto.Issuer {
+	return boleto.Issuer{
+		BankCode: b.BankCode,
+		Agency:   b.Agency,
+		Wallet:   b.Wallet,
+		Account:  b.Account,
+		Beneficiary: boleto.Beneficiary{
+			Name:     b.Name,
+			Document: b.Document.Formatted(),
+		},
+	}
+}
+
+// BeneficiaryIssuers looks up the issuer of each beneficiary only once, the
+// bank slips of a file all share the same one. It's not safe for concurrent
+// use.
+type BeneficiaryIssuers struct {
+	repository BeneficiaryRepository
+	issuers    map[string]boleto.Issuer
+}
+
+func NewBeneficiaryIssuers(repository BeneficiaryRepository) *BeneficiaryIssuers {
+	return &BeneficiaryIssuers{repository: repository, issuers: map[string]boleto.Issuer{}}
+}
+
+func (i *BeneficiaryIssuers) Get(beneficiaryId string) (boleto.Issuer, error) {
+	if issuer, ok := i.issuers[beneficiaryId]; ok {
+		return issuer, nil
+	}
+	beneficiary, err := i.repository.GetById(beneficiaryId)
+	if err != nil {
+		return boleto.Issuer{}, err
+	}
+	issuer := beneficiary.Issuer()
+	i.issuers[beneficiaryId] = issuer
+	return issuer, nil
+}
+
+// CNAB is the account the remittances of the beneficiary are written for.
+func (b *Beneficiary) CNAB() cnab.Beneficiary {
+	return cnab.Beneficiary{
+		Name:          b.Name,
+		Document:      b.Document.String(),
+		BankCode:      b.BankCode,
+		Agency:        b.Agency,
+		Account:       b.Account,
+		Wallet:        b.Wallet,
+		AgreementCode: b.AgreementCode,
+	}
+}
@@ -0,0 +1,59 @@
+package bank_slip
+
+import (
+	"testing"
+
+	"performatic-file-processor/internal/boleto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBeneficiary_ShouldNormalizeAccount(t *testing.T) {
+	beneficiary, err := NewBeneficiary(" Performatic Cobranças Ltda ", "11.222.333/0001-81", "237", "123", "12345", "9", "000123")
+	assert.NoError(t, err)
+	assert.Equal(t, &Beneficiary{
+		Name:          "Performatic Cobranças Ltda",
+		Document:      "11222333000181",
+		BankCode:      "237",
+		Agency:        "0123",
+		Account:       "0012345",
+		Wallet:        "09",
+		AgreementCode: "000123",
+	}, beneficiary)
+}
+
+func TestNewBeneficiary_ShouldRejectInvalidFields(t *testing.T) {
+	cases := map[string][]string{
+		"empty name":             {" ", "11222333000181", "237", "1234", "12345", "09", ""},
+		"invalid document":       {"Performatic", "11222333000180", "237", "1234", "12345", "09", ""},
+		"invalid bank code":      {"Performatic", "11222333000181", "23", "1234", "12345", "09", ""},
+		"unsupported bank":       {"Performatic", "11222333000181", "001", "1234", "12345", "09", ""},
+		"agency too long":        {"Performatic", "11222333000181", "237", "12345", "12345", "09", ""},
+		"account with letters":   {"Performatic", "11222333000181", "237", "1234", "12A45", "09", ""},
+		"empty wallet":           {"Performatic", "11222333000181", "237", "1234", "12345", "", ""},
+		"agreement with letters": {"Performatic", "11222333000181", "237", "1234", "12345", "09", "12A"},
+		"agreement too long":     {"Performatic", "11222333000181", "237", "1234", "12345", "09", "123456789012345678901"},
+	}
+	for name, fields := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewBeneficiary(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6])
+			assert.ErrorIs(t, err, ErrInvalidBeneficiary)
+		})
+	}
+}
+
+func TestBeneficiary_Issuer(t *testing.T) {
+	beneficiary, err := NewBeneficiary("Performatic Cobranças Ltda", "11222333000181", "237", "1234", "12345", "09", "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, boleto.Issuer{
+		BankCode: "237",
+		Agency:   "1234",
+		Wallet:   "09",
+		Account:  "0012345",
+		Beneficiary: boleto.Beneficiary{
+			Name:     "Performatic Cobranças Ltda",
+			Document: "11.222.333/0001-81",
+		},
+	}, beneficiary.Issuer())
+}
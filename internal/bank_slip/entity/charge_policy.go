@@ -0,0 +1,344 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"performatic-file-processor/internal/cnab"
+)
+
+var ErrInvalidChargePolicy = errors.New("invalid charge policy")
+
+// Optional columns of an upload, a row overrides the charge policy of its
+// upload with the ones it fills.
+const (
+	BankSlipFieldLateFee      BankSlipField = "lateFee"
+	BankSlipFieldInterest     BankSlipField = "interest"
+	BankSlipFieldDiscount     BankSlipField = "discount"
+	BankSlipFieldDiscountDays BankSlipField = "discountDays"
+)
+
+var BankSlipChargePolicyFields = []BankSlipField{
+	BankSlipFieldLateFee,
+	BankSlipFieldInterest,
+	BankSlipFieldDiscount,
+	BankSlipFieldDiscountDays,
+}
+
+// hundredPercent is 100% in hundredths of a percent.
+const hundredPercent = 10000
+
+// interestMonthDays is the commercial month monthly interest rates are
+// prorated by.
+const interestMonthDays = 30
+
+type ChargeKind string
+
+const (
+	ChargeKindAmount  ChargeKind = "AMOUNT"
+	ChargeKindPercent ChargeKind = "PERCENT"
+)
+
+// Charge is a fixed amount in centavos or a percentage of the debt amount in
+// hundredths of a percent, as 200 for "2%". It's written as "10.00" or
+// "2.00%", a zero value charges nothing.
+type Charge struct {
+	Kind  ChargeKind
+	Value int64
+}
+
+// ParseCharge reads an amount, or a percentage when it ends with "%", written
+// with the decimal separator and at most two decimal places.
+func ParseCharge(value, decimalSeparator string) (Charge, error) {
+	value = strings.TrimSpace(value)
+	kind := ChargeKindAmount
+	if number, isPercent := strings.CutSuffix(value, "%"); isPercent {
+		kind, value = ChargeKindPercent, strings.TrimSpace(number)
+	}
+
+	normalized, err := (&BankSlipRowLayout{DecimalSeparator: decimalSeparator}).NormalizeDecimal(value)
+	if err != nil {
+		return Charge{}, fmt.Errorf("%w: %w", ErrInvalidChargePolicy, err)
+	}
+	hundredths, err := parseHundredths(normalized)
+	if err != nil {
+		return Charge{}, fmt.Errorf("%w: %w", ErrInvalidChargePolicy, err)
+	}
+	if kind == ChargeKindPercent && hundredths > hundredPercent {
+		return Charge{}, fmt.Errorf("%w: %s%% is more than 100%%", ErrInvalidChargePolicy, normalized)
+	}
+	return Charge{Kind: kind, Value: hundredths}, nil
+}
+
+// parseHundredths reads a non negative "1234.56" as 123456.
+func parseHundredths(value string) (int64, error) {
+	matches := moneyPattern.FindStringSubmatch(value)
+	if matches == nil || matches[1] == "-" {
+		return 0, fmt.Errorf("%q must be a non negative number", value)
+	}
+	whole, fraction := matches[2], matches[3]
+	if len(fraction) > 2 {
+		return 0, fmt.Errorf("%q has more than two decimal places", value)
+	}
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/100-1 {
+		return 0, fmt.Errorf("%q is too large", value)
+	}
+	hundredths, _ := strconv.ParseInt(fraction+strings.Repeat("0", 2-len(fraction)), 10, 64)
+	return units*100 + hundredths, nil
+}
+
+func (c Charge) IsZero() bool {
+	return c.Value == 0
+}
+
+func (c Charge) IsPercent() bool {
+	return c.Kind == ChargeKindPercent
+}
+
+func (c Charge) String() string {
+	value := Money(c.Value).String()
+	if c.IsPercent() {
+		return value + "%"
+	}
+	return value
+}
+
+// brazilian formats the charge for people, as "R$ 10,00" or "2,00%".
+func (c Charge) brazilian() string {
+	if c.IsPercent() {
+		return strings.Replace(Money(c.Value).String(), ".", ",", 1) + "%"
+	}
+	return Money(c.Value).BRL()
+}
+
+// of is the charge over amount for days, a percentage rounded half up to the
+// centavo. Percentages are divided by divisor, as monthly interest by 30.
+func (c Charge) of(amount Money, days, divisor int64) Money {
+	if c.IsZero() || days <= 0 {
+		return 0
+	}
+	if !c.IsPercent() {
+		return Money(c.Value * days)
+	}
+	numerator := new(big.Int).Mul(big.NewInt(amount.Centavos()), big.NewInt(c.Value))
+	numerator.Mul(numerator, big.NewInt(days))
+	denominator := big.NewInt(hundredPercent * divisor)
+
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(denominator) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return Money(quotient.Int64())
+}
+
+func (c Charge) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *Charge) UnmarshalText(text []byte) error {
+	charge, err := ParseCharge(string(text), ".")
+	if err != nil {
+		return err
+	}
+	*c = charge
+	return nil
+}
+
+// ChargePolicy is what a bank slip charges when paid late, the late fee
+// ("multa") once and the interest ("juros de mora") for each day after the due
+// date, and the discount it grants when paid until DiscountDays before it.
+// Percentages of interest are monthly. Nil fields are not set, so the policy of
+// a beneficiary is overridden only by what an upload or row sets.
+type ChargePolicy struct {
+	LateFee      *Charge `json:"lateFee,omitempty"`
+	Interest     *Charge `json:"interest,omitempty"`
+	Discount     *Charge `json:"discount,omitempty"`
+	DiscountDays *int    `json:"discountDays,omitempty"`
+}
+
+// ParseChargePolicy reads the policy from the values of the charge policy
+// fields, empty values are left unset.
+func ParseChargePolicy(values map[BankSlipField]string, decimalSeparator string) (ChargePolicy, error) {
+	policy := ChargePolicy{}
+	charges := map[BankSlipField]**Charge{
+		BankSlipFieldLateFee:  &policy.LateFee,
+		BankSlipFieldInterest: &policy.Interest,
+		BankSlipFieldDiscount: &policy.Discount,
+	}
+	for field, charge := range charges {
+		value := strings.TrimSpace(values[field])
+		if value == "" {
+			continue
+		}
+		parsed, err := ParseCharge(value, decimalSeparator)
+		if err != nil {
+			return ChargePolicy{}, fmt.Errorf("%w (%s)", err, field)
+		}
+		*charge = &parsed
+	}
+
+	if value := strings.TrimSpace(values[BankSlipFieldDiscountDays]); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil {
+			return ChargePolicy{}, fmt.Errorf("%w: discount days %q must be a number", ErrInvalidChargePolicy, value)
+		}
+		policy.DiscountDays = &days
+	}
+	return policy, policy.Validate()
+}
+
+func (p ChargePolicy) Validate() error {
+	if p.DiscountDays != nil && *p.DiscountDays < 0 {
+		return fmt.Errorf("%w: discount days must not be negative", ErrInvalidChargePolicy)
+	}
+	if p.Discount != nil && p.Discount.IsPercent() && p.Discount.Value >= hundredPercent {
+		return fmt.Errorf("%w: discount must be less than 100%%", ErrInvalidChargePolicy)
+	}
+	return nil
+}
+
+// ValidateFor checks the policy can be applied to a debt of amount, a discount
+// can't take all of it.
+func (p ChargePolicy) ValidateFor(amount Money) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if p.Discount != nil && !p.Discount.IsPercent() && p.Discount.Value >= amount.Centavos() {
+		return fmt.Errorf("%w: discount %s must be less than the amount %s", ErrInvalidChargePolicy, p.Discount, amount)
+	}
+	return nil
+}
+
+// Override returns the policy with the fields set in other replacing its own.
+func (p ChargePolicy) Override(other ChargePolicy) ChargePolicy {
+	if other.LateFee != nil {
+		p.LateFee = other.LateFee
+	}
+	if other.Interest != nil {
+		p.Interest = other.Interest
+	}
+	if other.Discount != nil {
+		p.Discount = other.Discount
+	}
+	if other.DiscountDays != nil {
+		p.DiscountDays = other.DiscountDays
+	}
+	return p
+}
+
+func (p ChargePolicy) IsZero() bool {
+	return p.lateFee().IsZero() && p.interest().IsZero() && p.discount().IsZero()
+}
+
+func (p ChargePolicy) lateFee() Charge {
+	if p.LateFee == nil {
+		return Charge{}
+	}
+	return *p.LateFee
+}
+
+func (p ChargePolicy) interest() Charge {
+	if p.Interest == nil {
+		return Charge{}
+	}
+	return *p.Interest
+}
+
+func (p ChargePolicy) discount() Charge {
+	if p.Discount == nil {
+		return Charge{}
+	}
+	return *p.Discount
+}
+
+// DiscountDate is the last day the discount is granted, zero without a
+// discount.
+func (p ChargePolicy) DiscountDate(dueDate time.Time) time.Time {
+	if p.discount().IsZero() {
+		return time.Time{}
+	}
+	days := 0
+	if p.DiscountDays != nil {
+		days = *p.DiscountDays
+	}
+	return dateOnly(dueDate).AddDate(0, 0, -days)
+}
+
+// AmountDue is what a debt is paid with on a date.
+type AmountDue struct {
+	Date     time.Time
+	Amount   Money
+	Discount Money
+	LateFee  Money
+	Interest Money
+	DaysLate int
+}
+
+func (a AmountDue) Total() Money {
+	return a.Amount - a.Discount + a.LateFee + a.Interest
+}
+
+// AmountDue computes the amount payable on date for a debt of amount due on
+// dueDate. Percentages are computed with integers and rounded half up to the
+// centavo only once per charge, so no precision is lost on the way.
+func (p ChargePolicy) AmountDue(amount Money, dueDate, date time.Time) AmountDue {
+	date, dueDate = dateOnly(date), dateOnly(dueDate)
+	amountDue := AmountDue{Date: date, Amount: amount}
+
+	if discountDate := p.DiscountDate(dueDate); !discountDate.IsZero() && !date.After(discountDate) {
+		amountDue.Discount = min(p.discount().of(amount, 1, 1), amount)
+	}
+
+	daysLate := int64(date.Sub(dueDate).Hours() / 24)
+	if daysLate > 0 {
+		amountDue.DaysLate = int(daysLate)
+		amountDue.LateFee = p.lateFee().of(amount, 1, 1)
+		amountDue.Interest = p.interest().of(amount, daysLate, interestMonthDays)
+	}
+	return amountDue
+}
+
+// Instructions are the lines printed on the boleto for the bank teller, in
+// Portuguese.
+func (p ChargePolicy) Instructions(dueDate time.Time) []string {
+	instructions := []string{}
+	if discount := p.discount(); !discount.IsZero() {
+		instructions = append(instructions, fmt.Sprintf("Até %s, conceder desconto de %s.", p.DiscountDate(dueDate).Format("02/01/2006"), discount.brazilian()))
+	}
+	if lateFee := p.lateFee(); !lateFee.IsZero() {
+		instructions = append(instructions, fmt.Sprintf("Após o vencimento, cobrar multa de %s.", lateFee.brazilian()))
+	}
+	if interest := p.interest(); !interest.IsZero() {
+		period := "ao dia"
+		if interest.IsPercent() {
+			period = "ao mês"
+		}
+		instructions = append(instructions, fmt.Sprintf("Após o vencimento, cobrar juros de mora de %s %s.", interest.brazilian(), period))
+	}
+	return instructions
+}
+
+// CNAB returns the title with the charges of the policy, registered at the
+// bank with the remittance files.
+func (p ChargePolicy) CNAB(title cnab.Title) cnab.Title {
+	title.LateFee = p.lateFee().cnab()
+	title.Interest = p.interest().cnab()
+	title.Discount = p.discount().cnab()
+	title.DiscountDate = p.DiscountDate(title.DueDate)
+	return title
+}
+
+func (c Charge) cnab() cnab.Charge {
+	return cnab.Charge{Percent: c.IsPercent(), Value: c.Value}
+}
+
+func dateOnly(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,195 @@
+package bank_slip
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCharge(t *testing.T, value string) *Charge {
+	charge, err := ParseCharge(value, ".")
+	assert.NoError(t, err)
+	return &charge
+}
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse("2006-01-02", value)
+	return parsed
+}
+
+func TestParseCharge(t *testing.T) {
+	valid := map[string]Charge{
+		"2%":    {Kind: ChargeKindPercent, Value: 200},
+		"0.5 %": {Kind: ChargeKindPercent, Value: 50},
+		"100%":  {Kind: ChargeKindPercent, Value: 10000},
+		"10":    {Kind: ChargeKindAmount, Value: 1000},
+		"0.33":  {Kind: ChargeKindAmount, Value: 33},
+		"0":     {Kind: ChargeKindAmount},
+	}
+	for value, expected := range valid {
+		charge, err := ParseCharge(value, ".")
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, charge, value)
+	}
+
+	for _, value := range []string{"", "abc", "-2%", "100.01%", "0.033%", "10.001", "R$ 10"} {
+		_, err := ParseCharge(value, ".")
+		assert.ErrorIs(t, err, ErrInvalidChargePolicy, value)
+	}
+}
+
+func TestParseCharge_ShouldUseDecimalSeparator(t *testing.T) {
+	charge, err := ParseCharge("1,5%", ",")
+	assert.NoError(t, err)
+	assert.Equal(t, Charge{Kind: ChargeKindPercent, Value: 150}, charge)
+
+	charge, err = ParseCharge("1.234,56", ",")
+	assert.NoError(t, err)
+	assert.Equal(t, Charge{Kind: ChargeKindAmount, Value: 123456}, charge)
+}
+
+func TestCharge_ShouldRoundTripThroughJSON(t *testing.T) {
+	policy := ChargePolicy{LateFee: newCharge(t, "2%"), Interest: newCharge(t, "0.33"), DiscountDays: new(int)}
+
+	encoded, err := json.Marshal(policy)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"lateFee":"2.00%","interest":"0.33","discountDays":0}`, string(encoded))
+
+	var decoded ChargePolicy
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, policy, decoded)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"discount":"150%"}`), &decoded), ErrInvalidChargePolicy)
+}
+
+func TestParseChargePolicy_ShouldLeaveEmptyValuesUnset(t *testing.T) {
+	policy, err := ParseChargePolicy(map[BankSlipField]string{
+		BankSlipFieldLateFee:      "2%",
+		BankSlipFieldInterest:     " ",
+		BankSlipFieldDiscountDays: "5",
+	}, ".")
+	assert.NoError(t, err)
+	assert.Equal(t, newCharge(t, "2%"), policy.LateFee)
+	assert.Nil(t, policy.Interest)
+	assert.Nil(t, policy.Discount)
+	assert.Equal(t, 5, *policy.DiscountDays)
+}
+
+func TestParseChargePolicy_ShouldRejectInvalidValues(t *testing.T) {
+	invalid := []map[BankSlipField]string{
+		{BankSlipFieldLateFee: "2x"},
+		{BankSlipFieldDiscountDays: "five"},
+		{BankSlipFieldDiscountDays: "-1"},
+		{BankSlipFieldDiscount: "100%"},
+	}
+	for _, values := range invalid {
+		_, err := ParseChargePolicy(values, ".")
+		assert.ErrorIs(t, err, ErrInvalidChargePolicy, values)
+	}
+}
+
+func TestChargePolicy_ValidateForShouldRejectDiscountOfTheWholeAmount(t *testing.T) {
+	policy := ChargePolicy{Discount: newCharge(t, "100.00")}
+
+	assert.ErrorIs(t, policy.ValidateFor(10000), ErrInvalidChargePolicy)
+	assert.NoError(t, policy.ValidateFor(10001))
+}
+
+func TestChargePolicy_OverrideShouldReplaceOnlyTheFieldsSet(t *testing.T) {
+	days := 3
+	beneficiary := ChargePolicy{LateFee: newCharge(t, "2%"), Interest: newCharge(t, "1%")}
+	upload := ChargePolicy{Interest: newCharge(t, "0.50"), DiscountDays: &days}
+
+	policy := beneficiary.Override(upload)
+	assert.Equal(t, newCharge(t, "2%"), policy.LateFee)
+	assert.Equal(t, newCharge(t, "0.50"), policy.Interest)
+	assert.Nil(t, policy.Discount)
+	assert.Equal(t, &days, policy.DiscountDays)
+}
+
+func TestChargePolicy_AmountDueShouldGrantDiscountUntilDiscountDate(t *testing.T) {
+	days := 5
+	policy := ChargePolicy{Discount: newCharge(t, "5%"), DiscountDays: &days, LateFee: newCharge(t, "2%")}
+
+	amountDue := policy.AmountDue(100050, date("2026-03-31"), date("2026-03-26"))
+	assert.Equal(t, Money(5003), amountDue.Discount)
+	assert.Equal(t, Money(95047), amountDue.Total())
+
+	amountDue = policy.AmountDue(100050, date("2026-03-31"), date("2026-03-27"))
+	assert.Equal(t, Money(0), amountDue.Discount)
+	assert.Equal(t, Money(100050), amountDue.Total())
+}
+
+func TestChargePolicy_AmountDueShouldChargeLateFeeAndInterestAfterDueDate(t *testing.T) {
+	policy := ChargePolicy{LateFee: newCharge(t, "2%"), Interest: newCharge(t, "1%")}
+
+	amountDue := policy.AmountDue(100050, date("2026-03-31"), date("2026-03-31").Add(20*time.Hour))
+	assert.Equal(t, 0, amountDue.DaysLate)
+	assert.Equal(t, Money(100050), amountDue.Total())
+
+	amountDue = policy.AmountDue(100050, date("2026-03-31"), date("2026-04-15"))
+	assert.Equal(t, 15, amountDue.DaysLate)
+	assert.Equal(t, Money(2001), amountDue.LateFee)
+	// 1% a month for 15 of 30 days of 1000.50 is 5.0025.
+	assert.Equal(t, Money(500), amountDue.Interest)
+	assert.Equal(t, Money(102551), amountDue.Total())
+}
+
+func TestChargePolicy_AmountDueShouldChargeInterestAmountPerDay(t *testing.T) {
+	policy := ChargePolicy{LateFee: newCharge(t, "10.00"), Interest: newCharge(t, "0.33")}
+
+	amountDue := policy.AmountDue(100050, date("2026-03-31"), date("2026-04-03"))
+	assert.Equal(t, Money(1000), amountDue.LateFee)
+	assert.Equal(t, Money(99), amountDue.Interest)
+	assert.Equal(t, Money(101149), amountDue.Total())
+}
+
+func TestChargePolicy_Instructions(t *testing.T) {
+	days := 5
+	policy := ChargePolicy{
+		LateFee:      newCharge(t, "2%"),
+		Interest:     newCharge(t, "0.33"),
+		Discount:     newCharge(t, "10.00"),
+		DiscountDays: &days,
+	}
+
+	assert.Equal(t, []string{
+		"Até 26/03/2026, conceder desconto de R$ 10,00.",
+		"Após o vencimento, cobrar multa de 2,00%.",
+		"Após o vencimento, cobrar juros de mora de R$ 0,33 ao dia.",
+	}, policy.Instructions(date("2026-03-31")))
+
+	policy = ChargePolicy{Interest: newCharge(t, "1%")}
+	assert.Equal(t, []string{"Após o vencimento, cobrar juros de mora de 1,00% ao mês."}, policy.Instructions(date("2026-03-31")))
+	assert.Empty(t, ChargePolicy{}.Instructions(date("2026-03-31")))
+}
+
+func TestBankSlipRowLayout_RowChargePolicyShouldOverrideUploadPolicy(t *testing.T) {
+	layout, err := newBrUploadProfile(t).ResolveHeader([]string{"nome", "cpf", "email", "valor", "vencimento", "id_divida", "lateFee", "discount"})
+	assert.NoError(t, err)
+	layout.ChargePolicy = ChargePolicy{LateFee: newCharge(t, "2%"), Interest: newCharge(t, "1%")}
+
+	bankSlip, err := NewBankSlipFromRecord("file123", []string{"John Doe", "123.456.789-09", "john.doe@example.com", "1.000,50", "31/12/2023", "debt123", "", "10,00"}, layout)
+	assert.NoError(t, err)
+	assert.Equal(t, newCharge(t, "2%"), bankSlip.ChargePolicy.LateFee)
+	assert.Equal(t, newCharge(t, "1%"), bankSlip.ChargePolicy.Interest)
+	assert.Equal(t, newCharge(t, "10.00"), bankSlip.ChargePolicy.Discount)
+
+	decoded, err := NewBankSlipRowLayoutFromMessage(layout.ToMessage())
+	assert.NoError(t, err)
+	assert.Equal(t, layout, decoded)
+}
+
+func TestNewBankSlipFromRecord_ShouldRejectInvalidChargePolicy(t *testing.T) {
+	layout, err := newBrUploadProfile(t).ResolveHeader([]string{"nome", "cpf", "email", "valor", "vencimento", "id_divida", "discount"})
+	assert.NoError(t, err)
+
+	for _, discount := range []string{"abc", "1.000,50"} {
+		bankSlip, err := NewBankSlipFromRecord("file123", []string{"John Doe", "123.456.789-09", "john.doe@example.com", "1.000,50", "31/12/2023", "debt123", discount}, layout)
+		assert.ErrorIs(t, err, ErrInvalidChargePolicy, discount)
+		assert.Equal(t, RejectionCodeInvalidChargePolicy, RejectionCodeOf(err), discount)
+		assert.Nil(t, bankSlip)
+	}
+}
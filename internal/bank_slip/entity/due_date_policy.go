@@ -0,0 +1,53 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"performatic-file-processor/internal/calendar"
+)
+
+// DueDatePolicy tells what is done with the due dates that are not business
+// days when the rows are read. Those boletos are paid without charges on the
+// next business day anyway, rolling them forward prints that day on them.
+type DueDatePolicy string
+
+const (
+	DueDatePolicyKeep        DueDatePolicy = "KEEP"
+	DueDatePolicyReject      DueDatePolicy = "REJECT"
+	DueDatePolicyRollForward DueDatePolicy = "ROLL_FORWARD"
+)
+
+var (
+	ErrUnsupportedDueDatePolicy = errors.New("unsupported due date policy")
+	ErrDueDateNotBusinessDay    = errors.New("due date is not a business day")
+)
+
+// ParseDueDatePolicy keeps the due dates when no policy is given.
+func ParseDueDatePolicy(value string) (DueDatePolicy, error) {
+	policy := DueDatePolicy(strings.ToUpper(strings.TrimSpace(value)))
+	switch policy {
+	case "":
+		return DueDatePolicyKeep, nil
+	case DueDatePolicyKeep, DueDatePolicyReject, DueDatePolicyRollForward:
+		return policy, nil
+	}
+	return "", fmt.Errorf("%w %s", ErrUnsupportedDueDatePolicy, value)
+}
+
+// Apply returns the due date the bank slip is issued with.
+func (p DueDatePolicy) Apply(dueDate time.Time, businessDays *calendar.Calendar) (time.Time, error) {
+	if p == "" || p == DueDatePolicyKeep || businessDays.IsBusinessDay(dueDate) {
+		return dueDate, nil
+	}
+	if p == DueDatePolicyReject {
+		holiday, ok := businessDays.Holiday(dueDate)
+		if !ok {
+			holiday = dueDate.Weekday().String()
+		}
+		return time.Time{}, fmt.Errorf("%w: %s (%s)", ErrDueDateNotBusinessDay, dueDate.Format(time.DateOnly), holiday)
+	}
+	return businessDays.NextBusinessDay(dueDate), nil
+}
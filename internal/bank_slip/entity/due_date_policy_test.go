@@ -0,0 +1,82 @@
+package bank_slip
+
+import (
+	"testing"
+	"time"
+
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDueDatePolicy(t *testing.T) {
+	policy, err := ParseDueDatePolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, DueDatePolicyKeep, policy)
+
+	policy, err = ParseDueDatePolicy(" roll_forward ")
+	assert.NoError(t, err)
+	assert.Equal(t, DueDatePolicyRollForward, policy)
+
+	_, err = ParseDueDatePolicy("NEXT_DAY")
+	assert.ErrorIs(t, err, ErrUnsupportedDueDatePolicy)
+}
+
+func TestDueDatePolicy_Apply(t *testing.T) {
+	businessDays := calendar.New()
+
+	dueDate, err := DueDatePolicyKeep.Apply(date("2026-02-16"), businessDays)
+	assert.NoError(t, err)
+	assert.Equal(t, date("2026-02-16"), dueDate)
+
+	dueDate, err = DueDatePolicyRollForward.Apply(date("2026-02-14"), businessDays)
+	assert.NoError(t, err)
+	assert.Equal(t, date("2026-02-18"), dueDate)
+
+	dueDate, err = DueDatePolicyReject.Apply(date("2026-02-18"), businessDays)
+	assert.NoError(t, err)
+	assert.Equal(t, date("2026-02-18"), dueDate)
+
+	_, err = DueDatePolicyReject.Apply(date("2026-02-16"), businessDays)
+	assert.ErrorIs(t, err, ErrDueDateNotBusinessDay)
+	assert.ErrorContains(t, err, "Carnaval")
+}
+
+func TestNewBankSlipFromRecord_ShouldApplyDueDatePolicyOfLayout(t *testing.T) {
+	layout, err := newBrUploadProfile(t).ResolveHeader([]string{"nome", "cpf", "email", "valor", "vencimento", "id_divida"})
+	assert.NoError(t, err)
+	row := []string{"John Doe", "123.456.789-09", "john.doe@example.com", "1.000,50", "25/01/2027", "debt123"}
+
+	bankSlip, err := NewBankSlipFromRecord("file123", row, layout)
+	assert.NoError(t, err)
+	assert.Equal(t, date("2027-01-25"), bankSlip.DebtDueDate)
+
+	layout.DueDatePolicy = DueDatePolicyRollForward
+	layout.BusinessDays = calendar.New(calendar.RegionalHoliday{Month: 1, Day: 25})
+	bankSlip, err = NewBankSlipFromRecord("file123", row, layout)
+	assert.NoError(t, err)
+	assert.Equal(t, date("2027-01-26"), bankSlip.DebtDueDate)
+
+	layout.DueDatePolicy = DueDatePolicyReject
+	bankSlip, err = NewBankSlipFromRecord("file123", row, layout)
+	assert.ErrorIs(t, err, ErrDueDateNotBusinessDay)
+	assert.Equal(t, RejectionCodeDueDateNotBusinessDay, RejectionCodeOf(err))
+	assert.Nil(t, bankSlip)
+}
+
+func TestBankSlip_AmountDueShouldHaveNoChargesUntilTheNextBusinessDay(t *testing.T) {
+	bankSlip := &BankSlip{
+		DebtAmount:   100050,
+		DebtDueDate:  date("2026-02-14"),
+		ChargePolicy: ChargePolicy{LateFee: newCharge(t, "2%")},
+	}
+
+	amountDue := bankSlip.AmountDue(date("2026-02-18").Add(15*time.Hour), calendar.New())
+	assert.Equal(t, date("2026-02-18"), amountDue.Date)
+	assert.Equal(t, 0, amountDue.DaysLate)
+	assert.Equal(t, Money(100050), amountDue.Total())
+
+	amountDue = bankSlip.AmountDue(date("2026-02-19"), calendar.New())
+	assert.Equal(t, 5, amountDue.DaysLate)
+	assert.Equal(t, Money(2001), amountDue.LateFee)
+}
@@ -0,0 +1,123 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDunningSchedule = errors.New("invalid dunning schedule")
+
+// DefaultDunningSchedule reminds the payer 3 days before the due date and
+// notices the payment is late 1 and 7 days after it.
+const DefaultDunningSchedule = "D-3,D+1,D+7"
+
+type DunningNoticeKind string
+
+const (
+	// DunningNoticeKindReminder is sent before the due date to bank slips not
+	// paid yet, DunningNoticeKindOverdue after it to the overdue ones.
+	DunningNoticeKindReminder DunningNoticeKind = "REMINDER"
+	DunningNoticeKindOverdue  DunningNoticeKind = "OVERDUE"
+)
+
+// DunningStep is an email sent Offset days from the due date of a bank slip,
+// before it when negative. It's written as "D-3" or "D+1", the name it's
+// recorded with once sent. A step missed is still sent until Until days from
+// the due date, exclusive, and only on its day when Until isn't after Offset.
+type DunningStep struct {
+	Offset int
+	Until  int
+}
+
+func ParseDunningStep(value string) (DunningStep, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	number, ok := strings.CutPrefix(value, "D")
+	if !ok || len(number) < 2 || (number[0] != '-' && number[0] != '+') {
+		return DunningStep{}, fmt.Errorf("%w: %q must be written as D-3 or D+1", ErrInvalidDunningSchedule, value)
+	}
+	offset, err := strconv.Atoi(number)
+	if err != nil || offset == 0 {
+		return DunningStep{}, fmt.Errorf("%w: %q must be at least a day away from the due date", ErrInvalidDunningSchedule, value)
+	}
+	return DunningStep{Offset: offset}, nil
+}
+
+// ParseDunningSchedule reads the steps separated by commas, in the order they
+// are sent. Each step is sent until the next one, so a payer late to get a
+// step isn't sent two at once. Reminders stop at the due date and the last
+// notice at gracePeriodDays, when the bank slip expires.
+func ParseDunningSchedule(value string, gracePeriodDays int) ([]DunningStep, error) {
+	steps := []DunningStep{}
+	for item := range strings.SplitSeq(value, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		step, err := ParseDunningStep(item)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(steps, step) {
+			return nil, fmt.Errorf("%w: %s is repeated", ErrInvalidDunningSchedule, step)
+		}
+		steps = append(steps, step)
+	}
+	slices.SortFunc(steps, func(a, b DunningStep) int { return a.Offset - b.Offset })
+	for i := range steps {
+		steps[i].Until = gracePeriodDays
+		if i < len(steps)-1 {
+			steps[i].Until = steps[i+1].Offset
+		}
+		if steps[i].Kind() == DunningNoticeKindReminder {
+			steps[i].Until = min(steps[i].Until, 0)
+		}
+	}
+	return steps, nil
+}
+
+func (s DunningStep) String() string {
+	return fmt.Sprintf("D%+d", s.Offset)
+}
+
+func (s DunningStep) Kind() DunningNoticeKind {
+	if s.Offset < 0 {
+		return DunningNoticeKindReminder
+	}
+	return DunningNoticeKindOverdue
+}
+
+// Status is the one a bank slip must be in to get the step: reminders go to
+// issued bank slips and notices to overdue ones, so paid bank slips get none.
+func (s DunningStep) Status() BankSlipStatus {
+	if s.Kind() == DunningNoticeKindReminder {
+		return BankSlipStatusSuccess
+	}
+	return BankSlipStatusOverdue
+}
+
+// DueDates are the due dates of the bank slips that get the step on date:
+// from the last one it's still meaningful for, before Until, to the one that
+// gets it today, so a step missed while the workers were down is sent late.
+func (s DunningStep) DueDates(date time.Time) (from, to time.Time) {
+	until := max(s.Until, s.Offset+1)
+	return dateOnly(date).AddDate(0, 0, 1-until), dateOnly(date).AddDate(0, 0, -s.Offset)
+}
+
+// DunningRepository moves bank slips past their due date and records the
+// dunning emails they were sent.
+type DunningRepository interface {
+	// MoveDue moves up to limit bank slips due before dueBefore from one of the
+	// statuses to status, with their status history, and returns how many it
+	// moved.
+	MoveDue(from []BankSlipStatus, status BankSlipStatus, dueBefore time.Time, reason string, limit int) (int, error)
+	// ClaimNotices records the step as sent to up to limit bank slips that
+	// should get it on date and returns them. A bank slip is claimed once, by
+	// one worker, so it never gets a step twice.
+	ClaimNotices(step DunningStep, date time.Time, limit int) ([]*BankSlip, error)
+	// ReleaseNotice forgets the step was sent to the bank slip, so it's sent
+	// again when the email failed.
+	ReleaseNotice(debtId string, step DunningStep) error
+}
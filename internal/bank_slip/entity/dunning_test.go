@@ -0,0 +1,54 @@
+package bank_slip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDunningSchedule(t *testing.T) {
+	schedule, err := ParseDunningSchedule(" d+7, D-3,D+1 ", 30)
+	assert.NoError(t, err)
+	assert.Equal(t, []DunningStep{{Offset: -3, Until: 0}, {Offset: 1, Until: 7}, {Offset: 7, Until: 30}}, schedule)
+	assert.Equal(t, "D-3", schedule[0].String())
+	assert.Equal(t, "D+7", schedule[2].String())
+
+	schedule, err = ParseDunningSchedule("D-5,D-2", 30)
+	assert.NoError(t, err)
+	assert.Equal(t, []DunningStep{{Offset: -5, Until: -2}, {Offset: -2, Until: 0}}, schedule)
+
+	schedule, err = ParseDunningSchedule("", 30)
+	assert.NoError(t, err)
+	assert.Empty(t, schedule)
+}
+
+func TestParseDunningSchedule_ShouldRejectInvalidSteps(t *testing.T) {
+	for _, value := range []string{"3", "D3", "D+", "D-x", "D+0", "D-3,D-3", "X+1"} {
+		_, err := ParseDunningSchedule(value, 30)
+		assert.ErrorIs(t, err, ErrInvalidDunningSchedule, value)
+	}
+}
+
+func TestDunningStep_ShouldRemindIssuedAndNoticeOverdueBankSlips(t *testing.T) {
+	reminder := DunningStep{Offset: -3}
+	assert.Equal(t, DunningNoticeKindReminder, reminder.Kind())
+	assert.Equal(t, BankSlipStatusSuccess, reminder.Status())
+
+	notice := DunningStep{Offset: 1}
+	assert.Equal(t, DunningNoticeKindOverdue, notice.Kind())
+	assert.Equal(t, BankSlipStatusOverdue, notice.Status())
+}
+
+func TestDunningStep_DueDates(t *testing.T) {
+	from, to := DunningStep{Offset: -3}.DueDates(date("2026-03-28"))
+	assert.Equal(t, date("2026-03-29"), from)
+	assert.Equal(t, date("2026-03-31"), to)
+
+	from, to = DunningStep{Offset: 7, Until: 30}.DueDates(date("2026-04-07"))
+	assert.Equal(t, date("2026-03-09"), from)
+	assert.Equal(t, date("2026-03-31"), to)
+
+	from, to = DunningStep{Offset: 1}.DueDates(date("2026-04-01"))
+	assert.Equal(t, date("2026-03-31"), from)
+	assert.Equal(t, date("2026-03-31"), to)
+}
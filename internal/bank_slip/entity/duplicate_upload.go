@@ -0,0 +1,46 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type DuplicateUploadPolicy string
+
+const (
+	DuplicateUploadPolicyReject         DuplicateUploadPolicy = "REJECT"
+	DuplicateUploadPolicyReturnExisting DuplicateUploadPolicy = "RETURN_EXISTING"
+	DuplicateUploadPolicyForce          DuplicateUploadPolicy = "FORCE"
+)
+
+const MaxIdempotencyKeyLength = 255
+
+var (
+	ErrUnsupportedDuplicateUploadPolicy = errors.New("unsupported duplicate upload policy")
+	ErrDuplicateUpload                  = errors.New("file already uploaded")
+	ErrIdempotencyKeyAlreadyUsed        = errors.New("idempotency key already used")
+	ErrIdempotencyKeyMismatch           = errors.New("idempotency key used with another file")
+)
+
+func ParseDuplicateUploadPolicy(value string) (DuplicateUploadPolicy, error) {
+	policy := DuplicateUploadPolicy(strings.ToUpper(strings.TrimSpace(value)))
+	switch policy {
+	case DuplicateUploadPolicyReject, DuplicateUploadPolicyReturnExisting, DuplicateUploadPolicyForce:
+		return policy, nil
+	}
+	return "", fmt.Errorf("%w %s", ErrUnsupportedDuplicateUploadPolicy, value)
+}
+
+// DuplicateUploadError tells which file already holds the same content.
+type DuplicateUploadError struct {
+	ExistingFileId string
+}
+
+func (e *DuplicateUploadError) Error() string {
+	return fmt.Sprintf("%s (file id: %s)", ErrDuplicateUpload.Error(), e.ExistingFileId)
+}
+
+func (e *DuplicateUploadError) Is(target error) bool {
+	return target == ErrDuplicateUpload
+}
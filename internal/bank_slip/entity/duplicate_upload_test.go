@@ -0,0 +1,29 @@
+package bank_slip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuplicateUploadPolicy(t *testing.T) {
+	for value, expected := range map[string]DuplicateUploadPolicy{
+		"reject":          DuplicateUploadPolicyReject,
+		"RETURN_EXISTING": DuplicateUploadPolicyReturnExisting,
+		" force ":         DuplicateUploadPolicyForce,
+	} {
+		policy, err := ParseDuplicateUploadPolicy(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, policy)
+	}
+
+	_, err := ParseDuplicateUploadPolicy("ignore")
+	assert.ErrorIs(t, err, ErrUnsupportedDuplicateUploadPolicy)
+}
+
+func TestDuplicateUploadError_ShouldMatchSentinel(t *testing.T) {
+	var err error = &DuplicateUploadError{ExistingFileId: "file_id"}
+
+	assert.ErrorIs(t, err, ErrDuplicateUpload)
+	assert.EqualError(t, err, "file already uploaded (file id: file_id)")
+}
@@ -0,0 +1,195 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrFixedWidthLayoutNotFound      = errors.New("fixed-width layout not found")
+	ErrFixedWidthLayoutAlreadyExists = errors.New("fixed-width layout already exists")
+	ErrInvalidFixedWidthLayout       = errors.New("invalid fixed-width layout")
+)
+
+const DefaultFixedWidthDateFormat = "DDMMYYYY"
+
+type FixedWidthLayoutRepository interface {
+	Insert(layout *FixedWidthLayout) error
+	GetByName(name string) (*FixedWidthLayout, error)
+	// GetByExtension returns the layout files named with the extension, as
+	// ".rem", are read with.
+	GetByExtension(extension string) (*FixedWidthLayout, error)
+	List() ([]*FixedWidthLayout, error)
+}
+
+type FixedWidthFieldType string
+
+const (
+	FixedWidthFieldTypeText FixedWidthFieldType = "TEXT"
+	// FixedWidthFieldTypeDecimal is a number written without separator, its
+	// last Decimals digits being the fraction.
+	FixedWidthFieldTypeDecimal FixedWidthFieldType = "DECIMAL"
+	FixedWidthFieldTypeDate    FixedWidthFieldType = "DATE"
+)
+
+// fixedWidthFieldTypes is the type every bank slip field is written with.
+var fixedWidthFieldTypes = map[BankSlipField]FixedWidthFieldType{
+	BankSlipFieldUserName:     FixedWidthFieldTypeText,
+	BankSlipFieldGovernmentId: FixedWidthFieldTypeText,
+	BankSlipFieldUserEmail:    FixedWidthFieldTypeText,
+	BankSlipFieldDebtAmount:   FixedWidthFieldTypeDecimal,
+	BankSlipFieldDebtDueDate:  FixedWidthFieldTypeDate,
+	BankSlipFieldDebtId:       FixedWidthFieldTypeText,
+	BankSlipFieldLateFee:      FixedWidthFieldTypeText,
+	BankSlipFieldInterest:     FixedWidthFieldTypeText,
+	BankSlipFieldDiscount:     FixedWidthFieldTypeText,
+	BankSlipFieldDiscountDays: FixedWidthFieldTypeText,
+}
+
+// FixedWidthField is where a bank slip field is in a record, Start is the
+// first position, starting at 1 as in the CNAB manuals.
+type FixedWidthField struct {
+	Name     BankSlipField       `json:"name"`
+	Start    int                 `json:"start"`
+	Length   int                 `json:"length"`
+	Type     FixedWidthFieldType `json:"type"`
+	Decimals int                 `json:"decimals,omitempty"`
+}
+
+// FixedWidthLayout describes the records of CNAB-like files, an alternative to
+// upload profiles for partners that can't export CSV. Only the records
+// starting with RecordType are bank slips, headers and trailers are skipped.
+type FixedWidthLayout struct {
+	ID         string
+	Name       string
+	Extension  string
+	RecordType string
+	Fields     []FixedWidthField
+	DateFormat string
+}
+
+func NewFixedWidthLayout(name, extension, recordType string, fields []FixedWidthField, dateFormat string) (*FixedWidthLayout, error) {
+	if dateFormat == "" {
+		dateFormat = DefaultFixedWidthDateFormat
+	}
+	layout := &FixedWidthLayout{
+		Name:       strings.TrimSpace(name),
+		Extension:  NormalizeFileExtension(extension),
+		RecordType: recordType,
+		Fields:     fields,
+		DateFormat: dateFormat,
+	}
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// NormalizeFileExtension writes an extension in lower case with its leading
+// dot, empty stays empty.
+func NormalizeFileExtension(extension string) string {
+	extension = strings.ToLower(strings.TrimSpace(extension))
+	if extension == "" || strings.HasPrefix(extension, ".") {
+		return extension
+	}
+	return "." + extension
+}
+
+func (l *FixedWidthLayout) validate() error {
+	if l.Name == "" {
+		return fmt.Errorf("%w: name must be not empty", ErrInvalidFixedWidthLayout)
+	}
+	if strings.ContainsAny(l.Extension[min(1, len(l.Extension)):], "./\\ ") {
+		return fmt.Errorf("%w: invalid extension %s", ErrInvalidFixedWidthLayout, l.Extension)
+	}
+	if GoDateLayout(l.DateFormat) == "" {
+		return fmt.Errorf("%w: date format must contain YYYY, MM and DD", ErrInvalidFixedWidthLayout)
+	}
+
+	for _, field := range l.Fields {
+		if !slices.Contains(bankSlipFields(), field.Name) {
+			return fmt.Errorf("%w: unknown field %s", ErrInvalidFixedWidthLayout, field.Name)
+		}
+		if field.Start < 1 || field.Length < 1 {
+			return fmt.Errorf("%w: %s must start at 1 or after and have a length", ErrInvalidFixedWidthLayout, field.Name)
+		}
+		if field.Type != fixedWidthFieldTypes[field.Name] {
+			return fmt.Errorf("%w: %s must be %s", ErrInvalidFixedWidthLayout, field.Name, fixedWidthFieldTypes[field.Name])
+		}
+		if field.Decimals < 0 || field.Decimals > 2 || (field.Decimals > 0 && field.Type != FixedWidthFieldTypeDecimal) {
+			return fmt.Errorf("%w: %s can't have %d decimals", ErrInvalidFixedWidthLayout, field.Name, field.Decimals)
+		}
+	}
+	for _, name := range bankSlipFields() {
+		count := 0
+		for _, field := range l.Fields {
+			if field.Name == name {
+				count++
+			}
+		}
+		if count > 1 || (count == 0 && slices.Contains(BankSlipRequiredFields, name)) {
+			return fmt.Errorf("%w: %s must be given once", ErrInvalidFixedWidthLayout, name)
+		}
+	}
+	return nil
+}
+
+// IsDetail tells if the record is a bank slip.
+func (l *FixedWidthLayout) IsDetail(record string) bool {
+	return strings.TrimSpace(record) != "" && strings.HasPrefix(record, l.RecordType)
+}
+
+// RowLayout is the layout workers read the records with, the fields being
+// the columns in the order they were given.
+func (l *FixedWidthLayout) RowLayout() *BankSlipRowLayout {
+	positions := map[BankSlipField]int{}
+	for i, field := range l.Fields {
+		positions[field.Name] = i
+	}
+	return &BankSlipRowLayout{
+		Positions:        positions,
+		Columns:          len(l.Fields),
+		DateLayout:       GoDateLayout(l.DateFormat),
+		DecimalSeparator: ".",
+		FixedWidth:       l.Fields,
+		DueDatePolicy:    DueDatePolicyKeep,
+	}
+}
+
+// splitFixedWidth cuts a record in the values of the fields, positions count
+// characters and not bytes so accented names don't shift the fields after them.
+func splitFixedWidth(record string, fields []FixedWidthField) ([]string, error) {
+	characters := []rune(record)
+	values := make([]string, 0, len(fields))
+	for _, field := range fields {
+		end := field.Start - 1 + field.Length
+		if end > len(characters) {
+			return nil, fmt.Errorf("record of %d characters ends before %s", len(characters), field.Name)
+		}
+		value := strings.TrimSpace(string(characters[field.Start-1 : end]))
+		if field.Type == FixedWidthFieldTypeDecimal {
+			value = impliedDecimal(value, field.Decimals)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// impliedDecimal writes the separator of a number whose last digits are the
+// fraction, as "0000100050" with 2 decimals being "1000.50".
+func impliedDecimal(value string, decimals int) string {
+	if value == "" || strings.ContainsFunc(value, func(r rune) bool { return !unicode.IsDigit(r) }) {
+		return value
+	}
+	value = strings.TrimLeft(value, "0")
+	if len(value) <= decimals {
+		value = strings.Repeat("0", decimals-len(value)+1) + value
+	}
+	if decimals == 0 {
+		return value
+	}
+	return value[:len(value)-decimals] + "." + value[len(value)-decimals:]
+}
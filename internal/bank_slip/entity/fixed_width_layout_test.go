@@ -0,0 +1,123 @@
+package bank_slip
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFixedWidthFields() []FixedWidthField {
+	return []FixedWidthField{
+		{Name: BankSlipFieldDebtId, Start: 2, Length: 10, Type: FixedWidthFieldTypeText},
+		{Name: BankSlipFieldGovernmentId, Start: 12, Length: 11, Type: FixedWidthFieldTypeText},
+		{Name: BankSlipFieldUserName, Start: 23, Length: 30, Type: FixedWidthFieldTypeText},
+		{Name: BankSlipFieldUserEmail, Start: 53, Length: 30, Type: FixedWidthFieldTypeText},
+		{Name: BankSlipFieldDebtAmount, Start: 83, Length: 10, Type: FixedWidthFieldTypeDecimal, Decimals: 2},
+		{Name: BankSlipFieldDebtDueDate, Start: 93, Length: 8, Type: FixedWidthFieldTypeDate},
+	}
+}
+
+func newTestFixedWidthRecord(debtId, name string, amount string) string {
+	return fmt.Sprintf("1%-10s%-11s%-30s%-30s%10s%s", debtId, "12345678909", name, "john.doe@example.com", amount, "31122023")
+}
+
+func TestNewFixedWidthLayout_ShouldNormalizeExtensionAndDefaultDateFormat(t *testing.T) {
+	layout, err := NewFixedWidthLayout(" partner ", "REM", "1", newTestFixedWidthFields(), "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "partner", layout.Name)
+	assert.Equal(t, ".rem", layout.Extension)
+	assert.Equal(t, DefaultFixedWidthDateFormat, layout.DateFormat)
+}
+
+func TestNewFixedWidthLayout_ShouldValidateLayout(t *testing.T) {
+	fields := newTestFixedWidthFields()
+
+	_, err := NewFixedWidthLayout(" ", "", "1", fields, "")
+	assert.ErrorIs(t, err, ErrInvalidFixedWidthLayout)
+
+	_, err = NewFixedWidthLayout("any", "tar.gz", "1", fields, "")
+	assert.ErrorIs(t, err, ErrInvalidFixedWidthLayout)
+
+	_, err = NewFixedWidthLayout("any", "", "1", fields, "MMYYYY")
+	assert.ErrorIs(t, err, ErrInvalidFixedWidthLayout)
+
+	_, err = NewFixedWidthLayout("any", "", "1", fields[1:], "")
+	assert.ErrorIs(t, err, ErrInvalidFixedWidthLayout)
+
+	_, err = NewFixedWidthLayout("any", "", "1", append(newTestFixedWidthFields(), fields[0]), "")
+	assert.ErrorIs(t, err, ErrInvalidFixedWidthLayout)
+
+	invalidFields := []func(field *FixedWidthField){
+		func(field *FixedWidthField) { field.Name = "unknown" },
+		func(field *FixedWidthField) { field.Start = 0 },
+		func(field *FixedWidthField) { field.Length = 0 },
+		func(field *FixedWidthField) { field.Type = FixedWidthFieldTypeText },
+		func(field *FixedWidthField) { field.Decimals = 3 },
+	}
+	for _, invalidate := range invalidFields {
+		fields := newTestFixedWidthFields()
+		invalidate(&fields[4])
+		_, err = NewFixedWidthLayout("any", "", "1", fields, "")
+		assert.ErrorIs(t, err, ErrInvalidFixedWidthLayout)
+	}
+
+	fields = newTestFixedWidthFields()
+	fields[0].Decimals = 2
+	_, err = NewFixedWidthLayout("any", "", "1", fields, "")
+	assert.ErrorIs(t, err, ErrInvalidFixedWidthLayout)
+}
+
+func TestFixedWidthLayout_IsDetailShouldMatchRecordType(t *testing.T) {
+	layout, err := NewFixedWidthLayout("partner", "", "1", newTestFixedWidthFields(), "")
+	assert.NoError(t, err)
+
+	assert.True(t, layout.IsDetail(newTestFixedWidthRecord("debt123", "John Doe", "0000100050")))
+	assert.False(t, layout.IsDetail("0HEADER"))
+	assert.False(t, layout.IsDetail("   "))
+}
+
+func TestNewBankSlipFromLayoutRow_ShouldReadFixedWidthRecord(t *testing.T) {
+	layout, err := NewFixedWidthLayout("partner", "", "1", newTestFixedWidthFields(), "")
+	assert.NoError(t, err)
+
+	bankSlip, err := NewBankSlipFromLayoutRow("file123", newTestFixedWidthRecord("debt123", "João Müller", "0000100050"), layout.RowLayout())
+	assert.NoError(t, err)
+
+	debtDueDate, _ := time.Parse("2006-01-02", "2023-12-31")
+	assert.Equal(t, GovernmentId("12345678909"), bankSlip.GovernmentId)
+	assert.Equal(t, Money(100050), bankSlip.DebtAmount)
+	assert.Equal(t, debtDueDate, bankSlip.DebtDueDate)
+	assert.Equal(t, "debt123", bankSlip.DebtId)
+	assert.Equal(t, "João Müller", bankSlip.UserName)
+	assert.Equal(t, "john.doe@example.com", bankSlip.UserEmail)
+}
+
+func TestNewBankSlipFromLayoutRow_ShouldRejectShortFixedWidthRecord(t *testing.T) {
+	layout, err := NewFixedWidthLayout("partner", "", "1", newTestFixedWidthFields(), "")
+	assert.NoError(t, err)
+
+	record := newTestFixedWidthRecord("debt123", "John Doe", "0000100050")
+	_, err = NewBankSlipFromLayoutRow("file123", record[:90], layout.RowLayout())
+	assert.Equal(t, RejectionCodeMalformedRow, RejectionCodeOf(err))
+}
+
+func TestFixedWidthRowLayout_ShouldSurviveMessage(t *testing.T) {
+	layout, err := NewFixedWidthLayout("partner", "", "1", newTestFixedWidthFields(), "")
+	assert.NoError(t, err)
+
+	rowLayout, err := NewBankSlipRowLayoutFromMessage(layout.RowLayout().ToMessage())
+	assert.NoError(t, err)
+	assert.True(t, rowLayout.IsFixedWidth())
+	assert.Equal(t, layout.Fields, rowLayout.FixedWidth)
+}
+
+func TestImpliedDecimal(t *testing.T) {
+	assert.Equal(t, "1000.50", impliedDecimal("0000100050", 2))
+	assert.Equal(t, "0.05", impliedDecimal("0000000005", 2))
+	assert.Equal(t, "0.0", impliedDecimal("0000000000", 1))
+	assert.Equal(t, "1000", impliedDecimal("0000001000", 0))
+	assert.Equal(t, "10,00", impliedDecimal("10,00", 2))
+}
@@ -0,0 +1,108 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type GovernmentIdType string
+
+const (
+	GovernmentIdTypeCPF  GovernmentIdType = "CPF"
+	GovernmentIdTypeCNPJ GovernmentIdType = "CNPJ"
+)
+
+var (
+	ErrInvalidGovernmentId            = errors.New("invalid government id")
+	ErrInvalidGovernmentIdCheckDigits = errors.New("invalid government id check digits")
+)
+
+var (
+	governmentIdSeparators = strings.NewReplacer(".", "", "-", "", "/", "", " ", "")
+	cpfPattern             = regexp.MustCompile(`^\d{11}$`)
+	// Since July 2026 the CNPJ root and branch may have letters, the check
+	// digits are still numeric.
+	cnpjPattern = regexp.MustCompile(`^[0-9A-Z]{12}\d{2}$`)
+)
+
+// GovernmentId is a CPF or CNPJ kept normalized, without formatting and with
+// letters in upper case, so leading zeros are never lost.
+type GovernmentId string
+
+// ParseGovernmentId accepts a formatted (123.456.789-09, 12.ABC.345/01DE-35) or
+// raw document and validates its modulo 11 check digits.
+func ParseGovernmentId(value string) (GovernmentId, error) {
+	normalized := strings.ToUpper(governmentIdSeparators.Replace(strings.TrimSpace(value)))
+
+	var weights [2][]int
+	switch {
+	case cpfPattern.MatchString(normalized):
+		if strings.Count(normalized, normalized[:1]) == len(normalized) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidGovernmentIdCheckDigits, value)
+		}
+		weights = cpfWeights
+	case cnpjPattern.MatchString(normalized):
+		if strings.Count(normalized, normalized[:1]) == len(normalized) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidGovernmentIdCheckDigits, value)
+		}
+		weights = cnpjWeights
+	default:
+		return "", fmt.Errorf("%w: %q is neither a CPF nor a CNPJ", ErrInvalidGovernmentId, value)
+	}
+
+	base := len(normalized) - 2
+	for i, digitWeights := range weights {
+		if checkDigit(normalized[:base+i], digitWeights) != normalized[base+i] {
+			return "", fmt.Errorf("%w: %q", ErrInvalidGovernmentIdCheckDigits, value)
+		}
+	}
+	return GovernmentId(normalized), nil
+}
+
+var (
+	cpfWeights = [2][]int{
+		{10, 9, 8, 7, 6, 5, 4, 3, 2},
+		{11, 10, 9, 8, 7, 6, 5, 4, 3, 2},
+	}
+	cnpjWeights = [2][]int{
+		{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2},
+		{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2},
+	}
+)
+
+// checkDigit computes a modulo 11 check digit. Each character is worth its
+// ASCII code minus 48, which keeps digits as they are and makes the letters of
+// the alphanumeric CNPJ worth 17 (A) to 42 (Z).
+func checkDigit(value string, weights []int) byte {
+	sum := 0
+	for i := range len(value) {
+		sum += int(value[i]-'0') * weights[i]
+	}
+	remainder := sum % 11
+	if remainder < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - remainder)
+}
+
+func (g GovernmentId) Type() GovernmentIdType {
+	if len(g) == 11 {
+		return GovernmentIdTypeCPF
+	}
+	return GovernmentIdTypeCNPJ
+}
+
+// Formatted writes the document as people expect to read it.
+func (g GovernmentId) Formatted() string {
+	value := string(g)
+	if g.Type() == GovernmentIdTypeCPF {
+		return value[0:3] + "." + value[3:6] + "." + value[6:9] + "-" + value[9:]
+	}
+	return value[0:2] + "." + value[2:5] + "." + value[5:8] + "/" + value[8:12] + "-" + value[12:]
+}
+
+func (g GovernmentId) String() string {
+	return string(g)
+}
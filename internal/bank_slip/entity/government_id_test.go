@@ -0,0 +1,46 @@
+package bank_slip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGovernmentId(t *testing.T) {
+	cases := map[string]GovernmentId{
+		"123.456.789-09":     "12345678909",
+		"52998224725":        "52998224725",
+		" 529.982.247-25 ":   "52998224725",
+		"11.222.333/0001-81": "11222333000181",
+		"11222333000181":     "11222333000181",
+		"12.ABC.345/01DE-35": "12ABC34501DE35",
+		"12abc34501de35":     "12ABC34501DE35",
+	}
+	for value, expected := range cases {
+		governmentId, err := ParseGovernmentId(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, governmentId, value)
+	}
+}
+
+func TestParseGovernmentId_ShouldRejectWrongCheckDigits(t *testing.T) {
+	for _, value := range []string{"123.456.789-10", "52998224726", "11.222.333/0001-82", "12ABC34501DE36", "111.111.111-11", "00000000000000"} {
+		_, err := ParseGovernmentId(value)
+		assert.ErrorIs(t, err, ErrInvalidGovernmentIdCheckDigits, value)
+	}
+}
+
+func TestParseGovernmentId_ShouldRejectValuesThatAreNotDocuments(t *testing.T) {
+	for _, value := range []string{"", "123", "abc", "1234567890", "123456789012", "12ABC34501DEXX", "529.982.247-2A"} {
+		_, err := ParseGovernmentId(value)
+		assert.ErrorIs(t, err, ErrInvalidGovernmentId, value)
+	}
+}
+
+func TestGovernmentId_Formatted(t *testing.T) {
+	assert.Equal(t, "123.456.789-09", GovernmentId("12345678909").Formatted())
+	assert.Equal(t, GovernmentIdTypeCPF, GovernmentId("12345678909").Type())
+	assert.Equal(t, "11.222.333/0001-81", GovernmentId("11222333000181").Formatted())
+	assert.Equal(t, "12.ABC.345/01DE-35", GovernmentId("12ABC34501DE35").Formatted())
+	assert.Equal(t, GovernmentIdTypeCNPJ, GovernmentId("12ABC34501DE35").Type())
+}
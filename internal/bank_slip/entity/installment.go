@@ -0,0 +1,209 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidInstallmentPlan       = errors.New("invalid installment plan")
+	ErrInstallmentPlanAlreadyExists = errors.New("installment plan already exists")
+)
+
+// Optional columns of an upload, a row filling installments is split in that
+// many bank slips, due monthly on installmentDay.
+const (
+	BankSlipFieldInstallments   BankSlipField = "installments"
+	BankSlipFieldInstallmentDay BankSlipField = "installmentDay"
+)
+
+var BankSlipInstallmentFields = []BankSlipField{
+	BankSlipFieldInstallments,
+	BankSlipFieldInstallmentDay,
+}
+
+type InstallmentRepository interface {
+	// IssueScheduled moves up to limit SCHEDULED installments due until
+	// dueUntil to PENDING, with their status history, and returns them. An
+	// installment is moved once, by one of the workers running it. It also
+	// returns the installments it moved that are still PENDING since before
+	// staleBefore, left behind by a worker that didn't finish billing them,
+	// and records they're retried so another worker leaves them alone.
+	IssueScheduled(dueUntil time.Time, staleBefore time.Time, limit int) ([]*BankSlip, error)
+	// ListByParentDebtId lists the installments of a debt in their order.
+	ListByParentDebtId(parentDebtId string) ([]*BankSlip, error)
+}
+
+// MaxInstallments caps a plan in ten years of monthly installments.
+const MaxInstallments = 120
+
+// InstallmentPlan splits a debt in Installments bank slips due monthly on Day,
+// or on the day of the first due date when Day is zero. The zero value doesn't
+// split the debt.
+type InstallmentPlan struct {
+	Installments int `json:"installments"`
+	Day          int `json:"installmentDay,omitempty"`
+}
+
+func NewInstallmentPlan(installments, day int) (InstallmentPlan, error) {
+	if installments < 1 || installments > MaxInstallments {
+		return InstallmentPlan{}, fmt.Errorf("%w: installments must be between 1 and %d", ErrInvalidInstallmentPlan, MaxInstallments)
+	}
+	if day < 0 || day > 31 {
+		return InstallmentPlan{}, fmt.Errorf("%w: installment day must be between 1 and 31", ErrInvalidInstallmentPlan)
+	}
+	return InstallmentPlan{Installments: installments, Day: day}, nil
+}
+
+// ParseInstallmentPlan reads the plan from the values of the installment
+// fields, a row without installments isn't split.
+func ParseInstallmentPlan(installments, day string) (InstallmentPlan, error) {
+	installments, day = strings.TrimSpace(installments), strings.TrimSpace(day)
+	if installments == "" {
+		if day != "" {
+			return InstallmentPlan{}, fmt.Errorf("%w: installment day %q without installments", ErrInvalidInstallmentPlan, day)
+		}
+		return InstallmentPlan{}, nil
+	}
+	count, err := strconv.Atoi(installments)
+	if err != nil {
+		return InstallmentPlan{}, fmt.Errorf("%w: installments %q must be a number", ErrInvalidInstallmentPlan, installments)
+	}
+	dayOfMonth := 0
+	if day != "" {
+		if dayOfMonth, err = strconv.Atoi(day); err != nil || dayOfMonth == 0 {
+			return InstallmentPlan{}, fmt.Errorf("%w: installment day %q must be between 1 and 31", ErrInvalidInstallmentPlan, day)
+		}
+	}
+	return NewInstallmentPlan(count, dayOfMonth)
+}
+
+// IsSplit tells if the plan splits the debt in more than one bank slip.
+func (p InstallmentPlan) IsSplit() bool {
+	return p.Installments > 1
+}
+
+// ValidateFor checks a debt can be split by the plan. The debt id must be a
+// UUID, the ones of the installments are derived from it, and every
+// installment must get at least a centavo and more than its discount.
+func (p InstallmentPlan) ValidateFor(debtId string, amount Money, chargePolicy ChargePolicy) error {
+	if !p.IsSplit() {
+		return nil
+	}
+	if _, err := uuid.Parse(debtId); err != nil {
+		return fmt.Errorf("%w: debt id %q must be a UUID to be split", ErrInvalidInstallmentPlan, debtId)
+	}
+	if amount.Centavos() < int64(p.Installments) {
+		return fmt.Errorf("%w: %s can't be split in %d installments", ErrInvalidInstallmentPlan, amount, p.Installments)
+	}
+	if err := chargePolicy.ValidateFor(p.Amounts(amount)[0]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInstallmentPlan, err)
+	}
+	return nil
+}
+
+// Amounts splits amount in equal installments, the centavos left over go on
+// the last one so they add up to amount exactly.
+func (p InstallmentPlan) Amounts(amount Money) []Money {
+	count := Money(p.Installments)
+	amounts := make([]Money, p.Installments)
+	for i := range amounts {
+		amounts[i] = amount / count
+	}
+	amounts[len(amounts)-1] += amount % count
+	return amounts
+}
+
+// DueDates are the due dates of the installments, the first one on
+// firstDueDate and the others monthly on the day of the plan, or on the last
+// day of the shorter months. Days that aren't business days move to the next
+// business day.
+func (p InstallmentPlan) DueDates(firstDueDate time.Time, businessDays *calendar.Calendar) []time.Time {
+	first := dateOnly(firstDueDate)
+	dayOfMonth := p.Day
+	if dayOfMonth == 0 {
+		dayOfMonth = first.Day()
+	}
+
+	dueDates := make([]time.Time, p.Installments)
+	dueDates[0] = businessDays.NextBusinessDay(first)
+	for i := 1; i < p.Installments; i++ {
+		month := time.Date(first.Year(), first.Month()+time.Month(i), 1, 0, 0, 0, 0, time.UTC)
+		lastDay := month.AddDate(0, 1, -1).Day()
+		dueDates[i] = businessDays.NextBusinessDay(month.AddDate(0, 0, min(dayOfMonth, lastDay)-1))
+	}
+	return dueDates
+}
+
+// InstallmentDebtId derives the debt id of an installment from the one of its
+// debt, splitting a debt again always gives the same ids so the installments
+// are never stored twice. The debt id must have been validated by ValidateFor.
+func InstallmentDebtId(parentDebtId string, installmentNumber int) string {
+	return uuid.NewSHA1(uuid.MustParse(parentDebtId), []byte(strconv.Itoa(installmentNumber))).String()
+}
+
+// IsInstallment tells if the bank slip is an installment of a split debt.
+func (bankSlip *BankSlip) IsInstallment() bool {
+	return bankSlip.ParentDebtId != ""
+}
+
+// SplitInstallments returns the installments plan splits the bank slip in, the
+// first one due on firstDueDate, or the bank slip itself when the plan doesn't
+// split it. Installments are PENDING and keep the charge policy, beneficiary
+// and origin, file or subscription, of the debt.
+func (bankSlip *BankSlip) SplitInstallments(plan InstallmentPlan, firstDueDate time.Time, businessDays *calendar.Calendar) []*BankSlip {
+	if !plan.IsSplit() {
+		return []*BankSlip{bankSlip}
+	}
+
+	amounts := plan.Amounts(bankSlip.DebtAmount)
+	dueDates := plan.DueDates(firstDueDate, businessDays)
+	installments := make([]*BankSlip, plan.Installments)
+	for i := range installments {
+		installment := newBankSlip(
+			bankSlip.GovernmentId,
+			amounts[i],
+			dueDates[i],
+			InstallmentDebtId(bankSlip.DebtId, i+1),
+			bankSlip.UserName,
+			bankSlip.UserEmail,
+			bankSlip.BankSlipFileMetadataId,
+			BankSlipStatusPending,
+		)
+		installment.ChargePolicy = bankSlip.ChargePolicy
+		installment.BeneficiaryId = bankSlip.BeneficiaryId
+		installment.SubscriptionId = bankSlip.SubscriptionId
+		installment.ParentDebtId = bankSlip.DebtId
+		installment.InstallmentNumber = i + 1
+		installment.InstallmentCount = plan.Installments
+		installments[i] = installment
+	}
+	return installments
+}
+
+// IssuanceDate is the day an installment is billed, windowDays before it's
+// due.
+func (bankSlip *BankSlip) IssuanceDate(windowDays int) time.Time {
+	return dateOnly(bankSlip.DebtDueDate).AddDate(0, 0, -windowDays)
+}
+
+// ScheduleIssuance leaves a PENDING installment SCHEDULED when its issuance
+// window isn't open on date yet. Bank slips that aren't installments are
+// always billed right away.
+func (bankSlip *BankSlip) ScheduleIssuance(date time.Time, windowDays int) error {
+	if !bankSlip.IsInstallment() || !bankSlip.IssuanceDate(windowDays).After(dateOnly(date)) {
+		return nil
+	}
+	if bankSlip.Status != BankSlipStatusPending {
+		return fmt.Errorf("%w: %s can't be scheduled", ErrUnexpectedBankSlipStatus, bankSlip.Status)
+	}
+	bankSlip.Status = BankSlipStatusScheduled
+	return nil
+}
@@ -0,0 +1,121 @@
+package bank_slip
+
+import (
+	"testing"
+	"time"
+
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testParentDebtId = "e1f4b1f2-7c1a-4b8e-9a3d-2f6c8d9e0a11"
+
+func TestParseInstallmentPlan(t *testing.T) {
+	plan, err := ParseInstallmentPlan("", "")
+	assert.NoError(t, err)
+	assert.False(t, plan.IsSplit())
+
+	plan, err = ParseInstallmentPlan(" 12 ", "10")
+	assert.NoError(t, err)
+	assert.Equal(t, InstallmentPlan{Installments: 12, Day: 10}, plan)
+
+	for _, values := range [][2]string{{"", "10"}, {"abc", ""}, {"0", ""}, {"121", ""}, {"3", "0"}, {"3", "32"}} {
+		_, err := ParseInstallmentPlan(values[0], values[1])
+		assert.ErrorIs(t, err, ErrInvalidInstallmentPlan, values)
+	}
+}
+
+func TestInstallmentPlan_AmountsShouldAddUpToTheTotal(t *testing.T) {
+	plan := InstallmentPlan{Installments: 3}
+
+	assert.Equal(t, []Money{33333, 33333, 33334}, plan.Amounts(100000))
+	assert.Equal(t, []Money{1, 1, 1}, plan.Amounts(3))
+}
+
+func TestInstallmentPlan_DueDatesShouldBeMonthlyBusinessDays(t *testing.T) {
+	businessDays := calendar.New()
+
+	plan := InstallmentPlan{Installments: 3, Day: 31}
+	assert.Equal(t, []time.Time{date("2027-01-29"), date("2027-03-01"), date("2027-03-31")}, plan.DueDates(date("2027-01-29"), businessDays))
+
+	// Without a day they keep the one of the first due date, after Carnaval
+	// and the weekend.
+	plan = InstallmentPlan{Installments: 3}
+	assert.Equal(t, []time.Time{date("2026-01-15"), date("2026-02-18"), date("2026-03-16")}, plan.DueDates(date("2026-01-15"), businessDays))
+}
+
+func TestInstallmentPlan_ValidateFor(t *testing.T) {
+	plan := InstallmentPlan{Installments: 3}
+
+	assert.NoError(t, plan.ValidateFor(testParentDebtId, 100000, ChargePolicy{}))
+	assert.NoError(t, InstallmentPlan{}.ValidateFor("debt1", 100000, ChargePolicy{}))
+	assert.ErrorIs(t, plan.ValidateFor("debt1", 100000, ChargePolicy{}), ErrInvalidInstallmentPlan)
+	assert.ErrorIs(t, plan.ValidateFor(testParentDebtId, 2, ChargePolicy{}), ErrInvalidInstallmentPlan)
+
+	err := plan.ValidateFor(testParentDebtId, 100000, ChargePolicy{Discount: newCharge(t, "400")})
+	assert.ErrorIs(t, err, ErrInvalidInstallmentPlan)
+	assert.ErrorIs(t, err, ErrInvalidChargePolicy)
+}
+
+func TestBankSlip_SplitInstallments(t *testing.T) {
+	bankSlip := newBankSlip("12345678909", 100000, date("2026-01-15"), testParentDebtId, "John Doe", "john.doe@example.com", "file1", BankSlipStatusPending)
+	bankSlip.ChargePolicy = ChargePolicy{LateFee: newCharge(t, "2%")}
+
+	installments := bankSlip.SplitInstallments(InstallmentPlan{Installments: 2}, bankSlip.DebtDueDate, calendar.New())
+	assert.Len(t, installments, 2)
+	assert.Equal(t, InstallmentDebtId(testParentDebtId, 2), installments[1].DebtId)
+	assert.NotEqual(t, installments[0].DebtId, installments[1].DebtId)
+	assert.Equal(t, testParentDebtId, installments[1].ParentDebtId)
+	assert.Equal(t, 2, installments[1].InstallmentNumber)
+	assert.Equal(t, 2, installments[1].InstallmentCount)
+	assert.Equal(t, Money(50000), installments[1].DebtAmount)
+	assert.Equal(t, date("2026-02-18"), installments[1].DebtDueDate)
+	assert.Equal(t, bankSlip.ChargePolicy, installments[1].ChargePolicy)
+	assert.Equal(t, "file1", installments[1].BankSlipFileMetadataId)
+
+	assert.Equal(t, []*BankSlip{bankSlip}, bankSlip.SplitInstallments(InstallmentPlan{}, bankSlip.DebtDueDate, calendar.New()))
+}
+
+func TestBankSlip_SplitInstallmentsShouldKeepTheSubscription(t *testing.T) {
+	subscription, _ := NewSubscription("beneficiary1", date("2026-11-01"), newTestSubscriptionTerms())
+	subscription.ID = testSubscriptionId
+	subscription.Start(date("2026-10-18"), calendar.New())
+	bankSlip := subscription.NextBankSlip()
+
+	installments := bankSlip.SplitInstallments(InstallmentPlan{Installments: 2}, bankSlip.DebtDueDate, calendar.New())
+	for _, installment := range installments {
+		assert.Equal(t, "beneficiary1", installment.BeneficiaryId)
+		assert.Equal(t, testSubscriptionId, installment.SubscriptionId)
+		assert.Empty(t, installment.BankSlipFileMetadataId)
+	}
+}
+
+func TestBankSlip_ScheduleIssuanceShouldWaitForTheWindow(t *testing.T) {
+	installment := &BankSlip{DebtDueDate: date("2026-05-01"), ParentDebtId: testParentDebtId, Status: BankSlipStatusPending}
+
+	assert.NoError(t, installment.ScheduleIssuance(date("2026-04-01"), 30))
+	assert.Equal(t, BankSlipStatusPending, installment.Status)
+
+	assert.NoError(t, installment.ScheduleIssuance(date("2026-03-31").Add(23*time.Hour), 30))
+	assert.Equal(t, BankSlipStatusScheduled, installment.Status)
+	assert.True(t, installment.Status.CanTransitionTo(BankSlipStatusPending))
+
+	debt := &BankSlip{DebtDueDate: date("2026-05-01"), Status: BankSlipStatusPending}
+	assert.NoError(t, debt.ScheduleIssuance(date("2026-01-01"), 30))
+	assert.Equal(t, BankSlipStatusPending, debt.Status)
+}
+
+func TestNewBankSlipFromRecord_ShouldReadInstallmentPlan(t *testing.T) {
+	layout, err := DefaultUploadProfile().ResolveHeader([]string{"name", "governmentId", "email", "debtAmount", "debtDueDate", "debtId", "installments", "installmentDay"})
+	assert.NoError(t, err)
+
+	bankSlip, err := NewBankSlipFromRecord("file1", []string{"John Doe", "12345678909", "john.doe@example.com", "1000.00", "2026-01-15", testParentDebtId, "3", "10"}, layout)
+	assert.NoError(t, err)
+	assert.Equal(t, InstallmentPlan{Installments: 3, Day: 10}, bankSlip.InstallmentPlan)
+
+	bankSlip, err = NewBankSlipFromRecord("file1", []string{"John Doe", "12345678909", "john.doe@example.com", "1000.00", "2026-01-15", "debt1", "3", ""}, layout)
+	assert.ErrorIs(t, err, ErrInvalidInstallmentPlan)
+	assert.Equal(t, RejectionCodeInvalidInstallmentPlan, RejectionCodeOf(err))
+	assert.Nil(t, bankSlip)
+}
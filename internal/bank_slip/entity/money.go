@@ -0,0 +1,84 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidMoney         = errors.New("invalid money amount")
+	ErrMoneyTooManyDecimals = errors.New("money amount with more than two decimal places")
+	ErrMoneyNotPositive     = errors.New("money amount must be greater than zero")
+)
+
+var moneyPattern = regexp.MustCompile(`^(-?)(\d+)(?:\.(\d+))?$`)
+
+// Money is an amount in centavos. It's kept as an integer all the way from the
+// uploaded file to the billing and email payloads, so it's never rounded.
+type Money int64
+
+func NewMoneyFromCentavos(centavos int64) Money {
+	return Money(centavos)
+}
+
+// ParseMoney reads an amount normalized as "1234.56". Amounts must be greater
+// than zero and have at most two decimal places.
+func ParseMoney(value string) (Money, error) {
+	matches := moneyPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+	negative, whole, fraction := matches[1] == "-", matches[2], matches[3]
+	if len(fraction) > 2 {
+		return 0, fmt.Errorf("%w: %q", ErrMoneyTooManyDecimals, value)
+	}
+
+	reais, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || reais > math.MaxInt64/100-1 {
+		return 0, fmt.Errorf("%w: %q is too large", ErrInvalidMoney, value)
+	}
+	centavos, _ := strconv.ParseInt(fraction+strings.Repeat("0", 2-len(fraction)), 10, 64)
+
+	money := Money(reais*100 + centavos)
+	if negative || money == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrMoneyNotPositive, value)
+	}
+	return money, nil
+}
+
+func (m Money) Centavos() int64 {
+	return int64(m)
+}
+
+// String formats the amount as "1234.56".
+func (m Money) String() string {
+	sign := ""
+	centavos := int64(m)
+	if centavos < 0 {
+		sign, centavos = "-", -centavos
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, centavos/100, centavos%100)
+}
+
+// BRL formats the amount for people, as in "R$ 1.234,56".
+func (m Money) BRL() string {
+	amount := m.String()
+	sign := ""
+	if strings.HasPrefix(amount, "-") {
+		sign, amount = "-", amount[1:]
+	}
+	whole, fraction, _ := strings.Cut(amount, ".")
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return fmt.Sprintf("%sR$ %s,%s", sign, grouped.String(), fraction)
+}
@@ -0,0 +1,68 @@
+package bank_slip
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := map[string]Money{
+		"1000.50":        100050,
+		"1000.5":         100050,
+		"1000":           100000,
+		"0.01":           1,
+		"1999.99":        199999,
+		"0.30":           30,
+		" 12.34 ":        1234,
+		"100000000.00":   10000000000,
+		"92233720368.54": 9223372036854,
+	}
+	for value, expected := range cases {
+		money, err := ParseMoney(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, money, value)
+	}
+}
+
+func TestParseMoney_ShouldRejectMoreThanTwoDecimalPlaces(t *testing.T) {
+	_, err := ParseMoney("1999.995")
+	assert.ErrorIs(t, err, ErrMoneyTooManyDecimals)
+}
+
+func TestParseMoney_ShouldRejectAmountsAtOrBelowZero(t *testing.T) {
+	for _, value := range []string{"0", "0.00", "-10.00", "-0.01"} {
+		_, err := ParseMoney(value)
+		assert.ErrorIs(t, err, ErrMoneyNotPositive, value)
+	}
+}
+
+func TestParseMoney_ShouldRejectInvalidAmounts(t *testing.T) {
+	for _, value := range []string{"", "abc", "1,000.00", "1e3", "10.", ".50", "99999999999999999999"} {
+		_, err := ParseMoney(value)
+		assert.ErrorIs(t, err, ErrInvalidMoney, value)
+	}
+}
+
+func TestMoney_ShouldAddWithoutRounding(t *testing.T) {
+	tenCentavos, _ := ParseMoney("0.1")
+	twentyCentavos, _ := ParseMoney("0.2")
+
+	assert.Equal(t, "0.30", (tenCentavos + twentyCentavos).String())
+	assert.Equal(t, int64(30), (tenCentavos + twentyCentavos).Centavos())
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "1000.50", NewMoneyFromCentavos(100050).String())
+	assert.Equal(t, "0.05", NewMoneyFromCentavos(5).String())
+	assert.Equal(t, "-1.50", NewMoneyFromCentavos(-150).String())
+	assert.Equal(t, "92233720368547758.07", NewMoneyFromCentavos(math.MaxInt64).String())
+}
+
+func TestMoney_BRL(t *testing.T) {
+	assert.Equal(t, "R$ 1.234.567,89", NewMoneyFromCentavos(123456789).BRL())
+	assert.Equal(t, "R$ 123,45", NewMoneyFromCentavos(12345).BRL())
+	assert.Equal(t, "R$ 0,05", NewMoneyFromCentavos(5).BRL())
+	assert.Equal(t, "-R$ 1.000,00", NewMoneyFromCentavos(-100000).BRL())
+}
@@ -0,0 +1,67 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"performatic-file-processor/internal/cnab"
+)
+
+var (
+	ErrRemittanceNotFound = errors.New("remittance not found")
+	ErrInvalidRemittance  = errors.New("invalid remittance")
+	ErrNothingToRemit     = errors.New("no issued bank slip waiting for a remittance")
+)
+
+type RemittanceRepository interface {
+	// Create numbers the remittance with the next sequence of its beneficiary
+	// and stores the file written by render for the issued bank slips not yet
+	// sent in another remittance.
+	Create(remittance *Remittance, render RemittanceRenderer) error
+	GetById(id string) (*Remittance, error)
+	List(limit, offset int) ([]*Remittance, error)
+}
+
+// RemittanceRenderer writes the file of a numbered remittance.
+type RemittanceRenderer func(remittance *Remittance, bankSlips []*BankSlip) ([]byte, error)
+
+// Remittance ("remessa") is a CNAB file sent to the bank to register the
+// boletos of a beneficiary. Each bank slip is sent in a single remittance.
+type Remittance struct {
+	ID            string
+	BeneficiaryId string
+	// BankSlipFileId limits the remittance to the bank slips of an upload;
+	// when empty, everything issued since the last remittance is sent.
+	BankSlipFileId string
+	Format         cnab.Format
+	Sequence       int64
+	TotalBankSlips int
+	TotalAmount    Money
+	Content        []byte
+	CreatedAt      time.Time
+}
+
+func NewRemittance(beneficiaryId, bankSlipFileId string, format cnab.Format) *Remittance {
+	return &Remittance{
+		BeneficiaryId:  beneficiaryId,
+		BankSlipFileId: bankSlipFileId,
+		Format:         format,
+	}
+}
+
+// Numbered records the sequence and totals of the bank slips in the
+// remittance.
+func (r *Remittance) Numbered(sequence int64, bankSlips []*BankSlip) {
+	r.Sequence = sequence
+	r.TotalBankSlips = len(bankSlips)
+	r.TotalAmount = 0
+	for _, bankSlip := range bankSlips {
+		r.TotalAmount += bankSlip.DebtAmount
+	}
+}
+
+func (r *Remittance) FileName() string {
+	return fmt.Sprintf("remessa-%s-%06d.rem", strings.ToLower(string(r.Format)), r.Sequence)
+}
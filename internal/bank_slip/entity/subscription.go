@@ -0,0 +1,241 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive    SubscriptionStatus = "ACTIVE"
+	SubscriptionStatusCancelled SubscriptionStatus = "CANCELLED"
+	// SubscriptionStatusEnded is a subscription whose bank slips due until its
+	// end date were all issued.
+	SubscriptionStatusEnded SubscriptionStatus = "ENDED"
+)
+
+var (
+	ErrSubscriptionNotFound  = errors.New("subscription not found")
+	ErrInvalidSubscription   = errors.New("invalid subscription")
+	ErrSubscriptionNotActive = errors.New("subscription not active")
+)
+
+type SubscriptionRepository interface {
+	Insert(subscription *Subscription) error
+	// Update saves the terms, status and next period of an ACTIVE
+	// subscription, ErrSubscriptionNotActive is returned when it isn't.
+	Update(subscription *Subscription) error
+	GetById(id string) (*Subscription, error)
+	// List lists the subscriptions of the beneficiary, of all of them when
+	// beneficiaryId is empty.
+	List(beneficiaryId string) ([]*Subscription, error)
+	// ListDue lists up to limit ACTIVE subscriptions whose next bank slip is
+	// due until dueUntil, the ones due first first.
+	ListDue(dueUntil time.Time, limit int) ([]*Subscription, error)
+	// Advance saves the next period of the subscription if it's still in
+	// fromPeriod, a subscription advanced by another worker is left as is.
+	Advance(subscription *Subscription, fromPeriod time.Time) error
+}
+
+// SubscriptionTerms are what a subscription bills every month. Changing them
+// only affects the bank slips not issued yet.
+type SubscriptionTerms struct {
+	UserName     string
+	GovernmentId string
+	UserEmail    string
+	// Amount is written as "1234.56".
+	Amount     string
+	DayOfMonth int
+	// EndDate is the last day a bank slip of the subscription can be due on,
+	// the zero value never ends it.
+	EndDate      time.Time
+	ChargePolicy ChargePolicy
+}
+
+// Subscription ("assinatura") bills the customer a bank slip a month, due on
+// DayOfMonth or on the last day of the shorter months, from StartDate until
+// EndDate.
+type Subscription struct {
+	ID            string
+	BeneficiaryId string
+	UserName      string
+	GovernmentId  GovernmentId
+	UserEmail     string
+	Amount        Money
+	DayOfMonth    int
+	StartDate     time.Time
+	EndDate       time.Time
+	// ChargePolicy is resolved with the one of the beneficiary, its bank slips
+	// keep the one they were issued with.
+	ChargePolicy ChargePolicy
+	Status       SubscriptionStatus
+	// NextPeriod is the first day of the month the next bank slip is billed
+	// for, due on NextDueDate. Both are zero once the subscription ends.
+	NextPeriod  time.Time
+	NextDueDate time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewSubscription(beneficiaryId string, startDate time.Time, terms SubscriptionTerms) (*Subscription, error) {
+	if strings.TrimSpace(beneficiaryId) == "" {
+		return nil, fmt.Errorf("%w: beneficiary must be not empty", ErrInvalidSubscription)
+	}
+	if startDate.IsZero() {
+		return nil, fmt.Errorf("%w: start date must be not empty", ErrInvalidSubscription)
+	}
+
+	subscription := &Subscription{
+		BeneficiaryId: beneficiaryId,
+		StartDate:     dateOnly(startDate),
+		Status:        SubscriptionStatusActive,
+	}
+	if err := subscription.setTerms(terms); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// setTerms validates all the terms before changing any, so a subscription is
+// never left half updated.
+func (s *Subscription) setTerms(terms SubscriptionTerms) error {
+	userName := strings.TrimSpace(terms.UserName)
+	if userName == "" {
+		return fmt.Errorf("%w: user name must be not empty", ErrInvalidSubscription)
+	}
+	userEmail := strings.TrimSpace(terms.UserEmail)
+	if userEmail == "" {
+		return fmt.Errorf("%w: user email must be not empty", ErrInvalidSubscription)
+	}
+	governmentId, err := ParseGovernmentId(terms.GovernmentId)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSubscription, err)
+	}
+	amount, err := ParseMoney(terms.Amount)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSubscription, err)
+	}
+	if terms.DayOfMonth < 1 || terms.DayOfMonth > 31 {
+		return fmt.Errorf("%w: day of month must be between 1 and 31", ErrInvalidSubscription)
+	}
+	endDate := time.Time{}
+	if !terms.EndDate.IsZero() {
+		endDate = dateOnly(terms.EndDate)
+		if endDate.Before(s.StartDate) {
+			return fmt.Errorf("%w: end date must not be before the start date", ErrInvalidSubscription)
+		}
+	}
+	if err := terms.ChargePolicy.ValidateFor(amount); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSubscription, err)
+	}
+
+	s.UserName = userName
+	s.UserEmail = userEmail
+	s.GovernmentId = governmentId
+	s.Amount = amount
+	s.DayOfMonth = terms.DayOfMonth
+	s.EndDate = endDate
+	s.ChargePolicy = terms.ChargePolicy
+	return nil
+}
+
+// Start schedules the first bank slip of the subscription, the first one due
+// from its start date on. Periods already gone by on today aren't billed.
+func (s *Subscription) Start(today time.Time, businessDays *calendar.Calendar) {
+	s.schedule(latest(s.StartDate, today), businessDays)
+}
+
+// ChangeTerms replaces the terms of an ACTIVE subscription and schedules its
+// next bank slip again, as the day of the month or the end date may change.
+func (s *Subscription) ChangeTerms(terms SubscriptionTerms, today time.Time, businessDays *calendar.Calendar) error {
+	if s.Status != SubscriptionStatusActive {
+		return fmt.Errorf("%w: %s subscription can't be changed", ErrSubscriptionNotActive, s.Status)
+	}
+	if err := s.setTerms(terms); err != nil {
+		return err
+	}
+	s.schedule(latest(s.NextPeriod, latest(s.StartDate, today)), businessDays)
+	return nil
+}
+
+// Cancel stops issuing bank slips, the ones already issued are kept.
+func (s *Subscription) Cancel() error {
+	if s.Status != SubscriptionStatusActive {
+		return fmt.Errorf("%w: %s subscription can't be cancelled", ErrSubscriptionNotActive, s.Status)
+	}
+	s.Status = SubscriptionStatusCancelled
+	return nil
+}
+
+// Advance moves the subscription to the period after its next one, once the
+// bank slip of that period is issued.
+func (s *Subscription) Advance(businessDays *calendar.Calendar) {
+	s.schedule(s.NextPeriod.AddDate(0, 1, 0), businessDays)
+}
+
+// NextBankSlip is the PENDING bank slip of the next period of the
+// subscription.
+func (s *Subscription) NextBankSlip() *BankSlip {
+	bankSlip := newBankSlip(
+		s.GovernmentId,
+		s.Amount,
+		s.NextDueDate,
+		SubscriptionDebtId(s.ID, s.NextPeriod),
+		s.UserName,
+		s.UserEmail,
+		"",
+		BankSlipStatusPending,
+	)
+	bankSlip.BeneficiaryId = s.BeneficiaryId
+	bankSlip.SubscriptionId = s.ID
+	bankSlip.ChargePolicy = s.ChargePolicy
+	return bankSlip
+}
+
+// schedule sets the next period to the first one due from date on, moved to
+// the next business day, and ends the subscription when it's due after the
+// end date.
+func (s *Subscription) schedule(date time.Time, businessDays *calendar.Calendar) {
+	date = dateOnly(date)
+	period := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	dueDate := s.periodDueDate(period)
+	if dueDate.Before(date) {
+		period = period.AddDate(0, 1, 0)
+		dueDate = s.periodDueDate(period)
+	}
+
+	if !s.EndDate.IsZero() && dueDate.After(s.EndDate) {
+		s.Status = SubscriptionStatusEnded
+		s.NextPeriod = time.Time{}
+		s.NextDueDate = time.Time{}
+		return
+	}
+	s.NextPeriod = period
+	s.NextDueDate = businessDays.NextBusinessDay(dueDate)
+}
+
+func (s *Subscription) periodDueDate(period time.Time) time.Time {
+	lastDay := period.AddDate(0, 1, -1).Day()
+	return period.AddDate(0, 0, min(s.DayOfMonth, lastDay)-1)
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// SubscriptionDebtId derives the debt id of the bank slip of a period from the
+// subscription id, issuing a period again always gives the same id so it's
+// never stored twice.
+func SubscriptionDebtId(subscriptionId string, period time.Time) string {
+	return uuid.NewSHA1(uuid.MustParse(subscriptionId), []byte(period.Format("2006-01"))).String()
+}
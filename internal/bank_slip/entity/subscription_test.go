@@ -0,0 +1,125 @@
+package bank_slip
+
+import (
+	"testing"
+	"time"
+
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSubscriptionId = "6b1f9c2e-3d4a-4f5b-8c6d-7e8f9a0b1c2d"
+
+func newTestSubscriptionTerms() SubscriptionTerms {
+	return SubscriptionTerms{
+		UserName:     " John Doe ",
+		GovernmentId: "529.982.247-25",
+		UserEmail:    "john.doe@example.com",
+		Amount:       "150.00",
+		DayOfMonth:   10,
+	}
+}
+
+func TestNewSubscription(t *testing.T) {
+	subscription, err := NewSubscription("beneficiary1", date("2026-11-01"), newTestSubscriptionTerms())
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe", subscription.UserName)
+	assert.Equal(t, GovernmentId("52998224725"), subscription.GovernmentId)
+	assert.Equal(t, Money(15000), subscription.Amount)
+	assert.Equal(t, SubscriptionStatusActive, subscription.Status)
+
+	invalid := []func(terms *SubscriptionTerms){
+		func(terms *SubscriptionTerms) { terms.UserName = " " },
+		func(terms *SubscriptionTerms) { terms.UserEmail = "" },
+		func(terms *SubscriptionTerms) { terms.GovernmentId = "123" },
+		func(terms *SubscriptionTerms) { terms.Amount = "0" },
+		func(terms *SubscriptionTerms) { terms.DayOfMonth = 32 },
+		func(terms *SubscriptionTerms) { terms.EndDate = date("2026-10-31") },
+		func(terms *SubscriptionTerms) {
+			terms.ChargePolicy = ChargePolicy{Discount: &Charge{Kind: ChargeKindAmount, Value: 15000}}
+		},
+	}
+	for i, change := range invalid {
+		terms := newTestSubscriptionTerms()
+		change(&terms)
+		_, err := NewSubscription("beneficiary1", date("2026-11-01"), terms)
+		assert.ErrorIs(t, err, ErrInvalidSubscription, i)
+	}
+
+	_, err = NewSubscription("beneficiary1", time.Time{}, newTestSubscriptionTerms())
+	assert.ErrorIs(t, err, ErrInvalidSubscription)
+}
+
+func TestSubscription_StartShouldSkipPeriodsGoneBy(t *testing.T) {
+	businessDays := calendar.New()
+	subscription, _ := NewSubscription("beneficiary1", date("2026-11-01"), newTestSubscriptionTerms())
+
+	subscription.Start(date("2026-10-18"), businessDays)
+	assert.Equal(t, date("2026-11-01"), subscription.NextPeriod)
+	assert.Equal(t, date("2026-11-10"), subscription.NextDueDate)
+
+	subscription.Start(date("2026-11-11"), businessDays)
+	assert.Equal(t, date("2026-12-01"), subscription.NextPeriod)
+	assert.Equal(t, date("2026-12-10"), subscription.NextDueDate)
+}
+
+func TestSubscription_AdvanceShouldEndAfterTheEndDate(t *testing.T) {
+	businessDays := calendar.New()
+	terms := newTestSubscriptionTerms()
+	terms.DayOfMonth = 31
+	terms.EndDate = date("2027-02-28")
+	subscription, _ := NewSubscription("beneficiary1", date("2027-01-01"), terms)
+
+	subscription.Start(date("2026-12-20"), businessDays)
+	assert.Equal(t, date("2027-01-01"), subscription.NextPeriod)
+	assert.Equal(t, date("2027-02-01"), subscription.NextDueDate)
+
+	subscription.Advance(businessDays)
+	assert.Equal(t, date("2027-02-01"), subscription.NextPeriod)
+	assert.Equal(t, date("2027-03-01"), subscription.NextDueDate)
+	assert.Equal(t, SubscriptionStatusActive, subscription.Status)
+
+	subscription.Advance(businessDays)
+	assert.Equal(t, SubscriptionStatusEnded, subscription.Status)
+	assert.True(t, subscription.NextPeriod.IsZero())
+	assert.True(t, subscription.NextDueDate.IsZero())
+}
+
+func TestSubscription_ChangeTerms(t *testing.T) {
+	businessDays := calendar.New()
+	subscription, _ := NewSubscription("beneficiary1", date("2026-11-01"), newTestSubscriptionTerms())
+	subscription.Start(date("2026-10-18"), businessDays)
+
+	terms := newTestSubscriptionTerms()
+	terms.Amount = "180.00"
+	terms.DayOfMonth = 5
+	assert.NoError(t, subscription.ChangeTerms(terms, date("2026-11-06"), businessDays))
+	assert.Equal(t, Money(18000), subscription.Amount)
+	assert.Equal(t, date("2026-12-01"), subscription.NextPeriod)
+	assert.Equal(t, date("2026-12-07"), subscription.NextDueDate)
+
+	terms.Amount = "-1"
+	assert.ErrorIs(t, subscription.ChangeTerms(terms, date("2026-11-06"), businessDays), ErrInvalidSubscription)
+	assert.Equal(t, Money(18000), subscription.Amount)
+
+	assert.NoError(t, subscription.Cancel())
+	assert.ErrorIs(t, subscription.ChangeTerms(newTestSubscriptionTerms(), date("2026-11-06"), businessDays), ErrSubscriptionNotActive)
+	assert.ErrorIs(t, subscription.Cancel(), ErrSubscriptionNotActive)
+}
+
+func TestSubscription_NextBankSlipShouldHaveTheIdOfItsPeriod(t *testing.T) {
+	subscription, _ := NewSubscription("beneficiary1", date("2026-11-01"), newTestSubscriptionTerms())
+	subscription.ID = testSubscriptionId
+	subscription.Start(date("2026-10-18"), calendar.New())
+
+	bankSlip := subscription.NextBankSlip()
+	assert.Equal(t, SubscriptionDebtId(testSubscriptionId, date("2026-11-01")), bankSlip.DebtId)
+	assert.NotEqual(t, SubscriptionDebtId(testSubscriptionId, date("2026-12-01")), bankSlip.DebtId)
+	assert.Equal(t, Money(15000), bankSlip.DebtAmount)
+	assert.Equal(t, date("2026-11-10"), bankSlip.DebtDueDate)
+	assert.Equal(t, "beneficiary1", bankSlip.BeneficiaryId)
+	assert.Equal(t, testSubscriptionId, bankSlip.SubscriptionId)
+	assert.Empty(t, bankSlip.BankSlipFileMetadataId)
+	assert.Equal(t, BankSlipStatusPending, bankSlip.Status)
+}
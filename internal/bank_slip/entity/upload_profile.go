@@ -0,0 +1,229 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type BankSlipField string
+
+const (
+	BankSlipFieldUserName     BankSlipField = "userName"
+	BankSlipFieldGovernmentId BankSlipField = "governmentId"
+	BankSlipFieldUserEmail    BankSlipField = "userEmail"
+	BankSlipFieldDebtAmount   BankSlipField = "debtAmount"
+	BankSlipFieldDebtDueDate  BankSlipField = "debtDueDate"
+	BankSlipFieldDebtId       BankSlipField = "debtId"
+)
+
+var BankSlipRequiredFields = []BankSlipField{
+	BankSlipFieldUserName,
+	BankSlipFieldGovernmentId,
+	BankSlipFieldUserEmail,
+	BankSlipFieldDebtAmount,
+	BankSlipFieldDebtDueDate,
+	BankSlipFieldDebtId,
+}
+
+const DefaultUploadProfileName = "default"
+
+var (
+	ErrUploadProfileNotFound      = errors.New("upload profile not found")
+	ErrUploadProfileAlreadyExists = errors.New("upload profile already exists")
+	ErrInvalidUploadProfile       = errors.New("invalid upload profile")
+	ErrInvalidHeader              = errors.New("invalid header")
+)
+
+type UploadProfileRepository interface {
+	Insert(uploadProfile *UploadProfile) error
+	GetByName(name string) (*UploadProfile, error)
+	List() ([]*UploadProfile, error)
+}
+
+type UploadOptions struct {
+	Profile          string
+	Encoding         string
+	Delimiter        string
+	DecimalSeparator string
+	DuplicatePolicy  string
+	IdempotencyKey   string
+	BeneficiaryId    string
+	// FixedWidthLayout reads the file as fixed-width records instead of CSV,
+	// also chosen by the file extension when not given.
+	FixedWidthLayout string
+	// LateFee, Interest, Discount and DiscountDays override the charge policy
+	// of the beneficiary for the whole upload.
+	LateFee      string
+	Interest     string
+	Discount     string
+	DiscountDays string
+}
+
+// ChargePolicy reads the charge policy the upload overrides, written with "."
+// as decimal separator.
+func (o UploadOptions) ChargePolicy() (ChargePolicy, error) {
+	return ParseChargePolicy(map[BankSlipField]string{
+		BankSlipFieldLateFee:      o.LateFee,
+		BankSlipFieldInterest:     o.Interest,
+		BankSlipFieldDiscount:     o.Discount,
+		BankSlipFieldDiscountDays: o.DiscountDays,
+	}, ".")
+}
+
+type UploadProfile struct {
+	ID               string
+	Name             string
+	Columns          map[BankSlipField][]string
+	DateFormat       string
+	DecimalSeparator string
+	DueDatePolicy    DueDatePolicy
+}
+
+func NewUploadProfile(name string, columns map[BankSlipField][]string, dateFormat, decimalSeparator, dueDatePolicy string) (*UploadProfile, error) {
+	policy, err := ParseDueDatePolicy(dueDatePolicy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUploadProfile, err.Error())
+	}
+	uploadProfile := &UploadProfile{
+		Name:             strings.TrimSpace(name),
+		Columns:          columns,
+		DateFormat:       dateFormat,
+		DecimalSeparator: decimalSeparator,
+		DueDatePolicy:    policy,
+	}
+	if err := uploadProfile.validate(); err != nil {
+		return nil, err
+	}
+	return uploadProfile, nil
+}
+
+func DefaultUploadProfile() *UploadProfile {
+	return &UploadProfile{
+		Name: DefaultUploadProfileName,
+		Columns: map[BankSlipField][]string{
+			BankSlipFieldUserName:     {"name"},
+			BankSlipFieldGovernmentId: {"governmentId"},
+			BankSlipFieldUserEmail:    {"email"},
+			BankSlipFieldDebtAmount:   {"debtAmount"},
+			BankSlipFieldDebtDueDate:  {"debtDueDate"},
+			BankSlipFieldDebtId:       {"debtId"},
+		},
+		DateFormat:       "YYYY-MM-DD",
+		DecimalSeparator: ".",
+		DueDatePolicy:    DueDatePolicyKeep,
+	}
+}
+
+func (p *UploadProfile) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: name must be not empty", ErrInvalidUploadProfile)
+	}
+	if p.DecimalSeparator != "." && p.DecimalSeparator != "," {
+		return fmt.Errorf("%w: decimal separator must be '.' or ','", ErrInvalidUploadProfile)
+	}
+	if GoDateLayout(p.DateFormat) == "" {
+		return fmt.Errorf("%w: date format must contain YYYY, MM and DD", ErrInvalidUploadProfile)
+	}
+
+	for _, field := range BankSlipRequiredFields {
+		if len(p.Columns[field]) == 0 {
+			return fmt.Errorf("%w: missing columns for %s", ErrInvalidUploadProfile, field)
+		}
+	}
+	seen := map[string]BankSlipField{}
+	for _, field := range bankSlipFields() {
+		for _, alias := range p.Columns[field] {
+			normalized := normalizeColumnName(alias)
+			if other, ok := seen[normalized]; ok && other != field {
+				return fmt.Errorf("%w: column %s is mapped to %s and %s", ErrInvalidUploadProfile, alias, other, field)
+			}
+			seen[normalized] = field
+		}
+	}
+	for field := range p.Columns {
+		if !slices.Contains(bankSlipFields(), field) {
+			return fmt.Errorf("%w: unknown field %s", ErrInvalidUploadProfile, field)
+		}
+	}
+	return nil
+}
+
+// bankSlipFields are the required fields followed by the optional charge
+// policy and installment ones.
+func bankSlipFields() []BankSlipField {
+	return slices.Concat(BankSlipRequiredFields, BankSlipChargePolicyFields, BankSlipInstallmentFields)
+}
+
+// ResolveHeader maps every bank slip field to its column position, columns are
+// matched by their exact name or alias, ignoring case and surrounding spaces.
+// Charge policy and installment columns are optional and named after their
+// fields unless the profile gives them aliases.
+func (p *UploadProfile) ResolveHeader(headerItems []string) (*BankSlipRowLayout, error) {
+	positions := map[BankSlipField]int{}
+	missing := []string{}
+
+	for _, field := range bankSlipFields() {
+		position := -1
+		for i, column := range headerItems {
+			if !slices.ContainsFunc(p.columns(field), func(alias string) bool {
+				return normalizeColumnName(alias) == normalizeColumnName(column)
+			}) {
+				continue
+			}
+			if position != -1 {
+				return nil, fmt.Errorf("%w: more than one column for %s", ErrInvalidHeader, field)
+			}
+			position = i
+		}
+		if position == -1 {
+			if slices.Contains(BankSlipRequiredFields, field) {
+				missing = append(missing, string(field))
+			}
+			continue
+		}
+		positions[field] = position
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: missing columns for %s", ErrInvalidHeader, strings.Join(missing, ", "))
+	}
+
+	return &BankSlipRowLayout{
+		Positions:        positions,
+		Columns:          len(headerItems),
+		DateLayout:       GoDateLayout(p.DateFormat),
+		DecimalSeparator: p.DecimalSeparator,
+		DueDatePolicy:    p.DueDatePolicy,
+	}, nil
+}
+
+func (p *UploadProfile) columns(field BankSlipField) []string {
+	if len(p.Columns[field]) == 0 && !slices.Contains(BankSlipRequiredFields, field) {
+		return []string{string(field)}
+	}
+	return p.Columns[field]
+}
+
+var dateFormatTokens = strings.NewReplacer("YYYY", "2006", "MM", "01", "DD", "02")
+var goDateLayoutPattern = regexp.MustCompile(`^[^0-9A-Za-z]*(2006|01|02)[^0-9A-Za-z]*(2006|01|02)[^0-9A-Za-z]*(2006|01|02)[^0-9A-Za-z]*$`)
+
+// GoDateLayout converts a YYYY/MM/DD based format into a Go time layout,
+// returning an empty string when the format is not supported.
+func GoDateLayout(dateFormat string) string {
+	layout := dateFormatTokens.Replace(dateFormat)
+	matches := goDateLayoutPattern.FindStringSubmatch(layout)
+	if matches == nil {
+		return ""
+	}
+	if matches[1] == matches[2] || matches[1] == matches[3] || matches[2] == matches[3] {
+		return ""
+	}
+	return layout
+}
+
+func normalizeColumnName(column string) string {
+	return strings.ToLower(strings.TrimSpace(column))
+}
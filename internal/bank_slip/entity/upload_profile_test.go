@@ -0,0 +1,179 @@
+package bank_slip
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newBrUploadProfile(t *testing.T) *UploadProfile {
+	uploadProfile, err := NewUploadProfile(
+		"br",
+		map[BankSlipField][]string{
+			BankSlipFieldUserName:     {"nome"},
+			BankSlipFieldGovernmentId: {"cpf", "cnpj"},
+			BankSlipFieldUserEmail:    {"email"},
+			BankSlipFieldDebtAmount:   {"valor"},
+			BankSlipFieldDebtDueDate:  {"vencimento"},
+			BankSlipFieldDebtId:       {"id_divida"},
+		},
+		"DD/MM/YYYY",
+		",",
+		"",
+	)
+	assert.NoError(t, err)
+	return uploadProfile
+}
+
+func TestNewUploadProfile_ShouldValidateProfile(t *testing.T) {
+	columns := DefaultUploadProfile().Columns
+
+	_, err := NewUploadProfile(" ", columns, "YYYY-MM-DD", ".", "")
+	assert.ErrorIs(t, err, ErrInvalidUploadProfile)
+
+	_, err = NewUploadProfile("any", columns, "YYYY-MM-DD", ";", "")
+	assert.ErrorIs(t, err, ErrInvalidUploadProfile)
+
+	_, err = NewUploadProfile("any", columns, "YYYY-MM", ".", "")
+	assert.ErrorIs(t, err, ErrInvalidUploadProfile)
+
+	_, err = NewUploadProfile("any", map[BankSlipField][]string{BankSlipFieldUserName: {"name"}}, "YYYY-MM-DD", ".", "")
+	assert.ErrorIs(t, err, ErrInvalidUploadProfile)
+
+	duplicated := map[BankSlipField][]string{}
+	for field, aliases := range columns {
+		duplicated[field] = aliases
+	}
+	duplicated[BankSlipFieldDebtId] = []string{"NAME"}
+	_, err = NewUploadProfile("any", duplicated, "YYYY-MM-DD", ".", "")
+	assert.ErrorIs(t, err, ErrInvalidUploadProfile)
+
+	_, err = NewUploadProfile("any", columns, "YYYY-MM-DD", ".", "NEXT_DAY")
+	assert.ErrorIs(t, err, ErrInvalidUploadProfile)
+}
+
+func TestNewUploadProfile_ShouldKeepDueDatesUnlessPolicyIsGiven(t *testing.T) {
+	columns := DefaultUploadProfile().Columns
+
+	uploadProfile, err := NewUploadProfile("any", columns, "YYYY-MM-DD", ".", "")
+	assert.NoError(t, err)
+	assert.Equal(t, DueDatePolicyKeep, uploadProfile.DueDatePolicy)
+
+	uploadProfile, err = NewUploadProfile("any", columns, "YYYY-MM-DD", ".", "roll_forward")
+	assert.NoError(t, err)
+	layout, err := uploadProfile.ResolveHeader([]string{"name", "governmentId", "email", "debtAmount", "debtDueDate", "debtId"})
+	assert.NoError(t, err)
+	assert.Equal(t, DueDatePolicyRollForward, layout.DueDatePolicy)
+}
+
+func TestUploadProfile_ResolveHeaderShouldMatchExactColumnsAndAliases(t *testing.T) {
+	layout, err := newBrUploadProfile(t).ResolveHeader([]string{"ID_DIVIDA", " Nome ", "cnpj", "email", "valor", "vencimento", "observacao"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[BankSlipField]int{
+		BankSlipFieldDebtId:       0,
+		BankSlipFieldUserName:     1,
+		BankSlipFieldGovernmentId: 2,
+		BankSlipFieldUserEmail:    3,
+		BankSlipFieldDebtAmount:   4,
+		BankSlipFieldDebtDueDate:  5,
+	}, layout.Positions)
+	assert.Equal(t, 7, layout.Columns)
+	assert.Equal(t, "02/01/2006", layout.DateLayout)
+	assert.Equal(t, ",", layout.DecimalSeparator)
+}
+
+func TestUploadProfile_ResolveHeaderShouldNotMatchSimilarColumns(t *testing.T) {
+	_, err := DefaultUploadProfile().ResolveHeader([]string{"username", "governmentId", "emailConfirmed", "debtAmount", "debtDueDate", "debtId"})
+
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+	assert.EqualError(t, err, "invalid header: missing columns for userName, userEmail")
+}
+
+func TestUploadProfile_ResolveHeaderShouldRejectAmbiguousColumns(t *testing.T) {
+	_, err := newBrUploadProfile(t).ResolveHeader([]string{"nome", "cpf", "cnpj", "email", "valor", "vencimento", "id_divida"})
+
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+func TestGoDateLayout(t *testing.T) {
+	assert.Equal(t, "2006-01-02", GoDateLayout("YYYY-MM-DD"))
+	assert.Equal(t, "02/01/2006", GoDateLayout("DD/MM/YYYY"))
+	assert.Equal(t, "20060102", GoDateLayout("YYYYMMDD"))
+	assert.Equal(t, "", GoDateLayout("DD/MM/DD"))
+	assert.Equal(t, "", GoDateLayout("DD/MM/YY"))
+	assert.Equal(t, "", GoDateLayout(""))
+}
+
+func TestNewBankSlipFromRecord_ShouldUseProfileFormats(t *testing.T) {
+	layout, err := newBrUploadProfile(t).ResolveHeader([]string{"nome", "cpf", "email", "valor", "vencimento", "id_divida"})
+	assert.NoError(t, err)
+
+	bankSlip, err := NewBankSlipFromRecord("file123", []string{"John Doe", "123.456.789-09", "john.doe@example.com", "1.000,50", "31/12/2023", "debt123"}, layout)
+	assert.NoError(t, err)
+
+	debtDueDate, _ := time.Parse("2006-01-02", "2023-12-31")
+	assert.Equal(t, Money(100050), bankSlip.DebtAmount)
+	assert.Equal(t, debtDueDate, bankSlip.DebtDueDate)
+	assert.Equal(t, GovernmentId("12345678909"), bankSlip.GovernmentId)
+	assert.Equal(t, "debt123", bankSlip.DebtId)
+}
+
+func TestBankSlipRowLayout_ShouldRoundTripThroughMessage(t *testing.T) {
+	layout, err := newBrUploadProfile(t).ResolveHeader([]string{"nome", "cpf", "email", "valor", "vencimento", "id_divida"})
+	assert.NoError(t, err)
+
+	decoded, err := NewBankSlipRowLayoutFromMessage(layout.ToMessage())
+	assert.NoError(t, err)
+	assert.Equal(t, layout, decoded)
+
+	_, err = NewBankSlipRowLayoutFromMessage(map[string]any{"positions": map[string]any{"userName": 0}})
+	assert.Error(t, err)
+}
+
+func TestBankSlipRowLayout_NormalizeDecimal(t *testing.T) {
+	dot := &BankSlipRowLayout{DecimalSeparator: "."}
+	comma := &BankSlipRowLayout{DecimalSeparator: ","}
+
+	for value, expected := range map[string]string{"1000.50": "1000.50", "1,000.50": "1000.50", "1,234,567": "1234567", "10": "10"} {
+		normalized, err := dot.NormalizeDecimal(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, normalized)
+	}
+	for value, expected := range map[string]string{"1000,50": "1000.50", "1.000,50": "1000.50", "1.234.567": "1234567"} {
+		normalized, err := comma.NormalizeDecimal(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, normalized)
+	}
+	for _, value := range []string{"1,00.50", "1.000,50", "abc", ""} {
+		_, err := dot.NormalizeDecimal(value)
+		assert.Error(t, err, value)
+	}
+	_, err := comma.NormalizeDecimal("1000.50")
+	assert.Error(t, err)
+}
+
+func TestSniffDecimalSeparator(t *testing.T) {
+	assert.Equal(t, ",", SniffDecimalSeparator([]string{"1.234,56"}))
+	assert.Equal(t, ".", SniffDecimalSeparator([]string{"1,234.56"}))
+	assert.Equal(t, ",", SniffDecimalSeparator([]string{"10", "1.234", "12,5"}))
+	assert.Equal(t, ".", SniffDecimalSeparator([]string{"1234.56"}))
+	assert.Equal(t, ".", SniffDecimalSeparator([]string{"1,234,567"}))
+	assert.Equal(t, "", SniffDecimalSeparator([]string{"10", "1.234", "1,234"}))
+	assert.Equal(t, "", SniffDecimalSeparator([]string{}))
+}
+
+func TestNewBankSlipFromRecord_ShouldUseLayoutDelimiter(t *testing.T) {
+	layout, err := DefaultUploadProfile().ResolveHeader([]string{"name", "governmentId", "email", "debtAmount", "debtDueDate", "debtId"})
+	assert.NoError(t, err)
+	layout.Delimiter = ";"
+
+	rowItems, err := NewCSVReader(strings.NewReader("John, Doe;12345678909;john.doe@example.com;1000.50;2023-12-31;debt123"), layout.Comma()).Read()
+	assert.NoError(t, err)
+
+	bankSlip, err := NewBankSlipFromRecord("file123", rowItems, layout)
+	assert.NoError(t, err)
+	assert.Equal(t, "John, Doe", bankSlip.UserName)
+}
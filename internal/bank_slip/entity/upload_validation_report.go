@@ -0,0 +1,71 @@
+package bank_slip
+
+import "fmt"
+
+const DefaultMaxReportedRowErrors = 100
+
+type UploadValidationRowError struct {
+	LineNumber int
+	ErrorCode  RejectionCode
+	Message    string
+}
+
+// UploadValidationReport summarizes what the pipeline would do with a file
+// without storing anything. Only the first errors and duplicates are listed,
+// the counters always cover the whole file.
+type UploadValidationReport struct {
+	FileName         string
+	TotalRows        int
+	ValidRows        int
+	ErrorsByCode     map[RejectionCode]int
+	Errors           []*UploadValidationRowError
+	DuplicateDebtIds []DebitId
+	maxErrors        int
+	debtIds          map[DebitId]int
+}
+
+func NewUploadValidationReport(fileName string, maxErrors int) *UploadValidationReport {
+	return &UploadValidationReport{
+		FileName:         fileName,
+		ErrorsByCode:     map[RejectionCode]int{},
+		Errors:           []*UploadValidationRowError{},
+		DuplicateDebtIds: []DebitId{},
+		maxErrors:        maxErrors,
+		debtIds:          map[DebitId]int{},
+	}
+}
+
+// AddRow accounts a parsed row, a repeated debtId is rejected the same way the
+// workers do: the first row wins.
+func (report *UploadValidationReport) AddRow(lineNumber int, bankSlip *BankSlip, err error) {
+	report.TotalRows++
+	if err != nil {
+		report.reject(lineNumber, RejectionCodeOf(err), err.Error())
+		return
+	}
+
+	report.debtIds[bankSlip.DebtId]++
+	if report.debtIds[bankSlip.DebtId] > 1 {
+		if report.debtIds[bankSlip.DebtId] == 2 && len(report.DuplicateDebtIds) < report.maxErrors {
+			report.DuplicateDebtIds = append(report.DuplicateDebtIds, bankSlip.DebtId)
+		}
+		report.reject(lineNumber, RejectionCodeDuplicateDebtId, fmt.Sprintf("duplicate debtId %s", bankSlip.DebtId))
+		return
+	}
+	report.ValidRows++
+}
+
+func (report *UploadValidationReport) RejectedRows() int {
+	return report.TotalRows - report.ValidRows
+}
+
+func (report *UploadValidationReport) reject(lineNumber int, code RejectionCode, message string) {
+	report.ErrorsByCode[code]++
+	if len(report.Errors) < report.maxErrors {
+		report.Errors = append(report.Errors, &UploadValidationRowError{
+			LineNumber: lineNumber,
+			ErrorCode:  code,
+			Message:    message,
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package bank_slip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadValidationReport_ShouldCountValidAndRejectedRows(t *testing.T) {
+	report := NewUploadValidationReport("file.csv", 10)
+
+	report.AddRow(2, &BankSlip{DebtId: "debt1"}, nil)
+	report.AddRow(3, nil, newBankSlipRowError(RejectionCodeInvalidAmount, "error converting debtAmount"))
+	report.AddRow(4, &BankSlip{DebtId: "debt2"}, nil)
+
+	assert.Equal(t, 3, report.TotalRows)
+	assert.Equal(t, 2, report.ValidRows)
+	assert.Equal(t, 1, report.RejectedRows())
+	assert.Equal(t, map[RejectionCode]int{RejectionCodeInvalidAmount: 1}, report.ErrorsByCode)
+	assert.Equal(t, []*UploadValidationRowError{
+		{LineNumber: 3, ErrorCode: RejectionCodeInvalidAmount, Message: "error converting debtAmount"},
+	}, report.Errors)
+}
+
+func TestUploadValidationReport_ShouldKeepFirstRowOfDuplicatedDebtIds(t *testing.T) {
+	report := NewUploadValidationReport("file.csv", 10)
+
+	report.AddRow(2, &BankSlip{DebtId: "debt1"}, nil)
+	report.AddRow(3, &BankSlip{DebtId: "debt1"}, nil)
+	report.AddRow(4, &BankSlip{DebtId: "debt1"}, nil)
+
+	assert.Equal(t, 1, report.ValidRows)
+	assert.Equal(t, 2, report.ErrorsByCode[RejectionCodeDuplicateDebtId])
+	assert.Equal(t, []DebitId{"debt1"}, report.DuplicateDebtIds)
+	assert.Equal(t, 3, report.Errors[0].LineNumber)
+	assert.Equal(t, 4, report.Errors[1].LineNumber)
+}
+
+func TestUploadValidationReport_ShouldListOnlyTheFirstErrors(t *testing.T) {
+	report := NewUploadValidationReport("file.csv", 2)
+
+	for line := 2; line < 7; line++ {
+		report.AddRow(line, nil, newBankSlipRowError(RejectionCodeInvalidDueDate, "error converting debtDueDate"))
+	}
+
+	assert.Equal(t, 5, report.ErrorsByCode[RejectionCodeInvalidDueDate])
+	assert.Len(t, report.Errors, 2)
+	assert.Equal(t, 3, report.Errors[1].LineNumber)
+}
@@ -3,6 +3,7 @@ package bank_slip
 import (
 	"maps"
 	entities "performatic-file-processor/internal/bank_slip/entity"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -19,6 +20,68 @@ func (m *BankSlipFileMetadataRepositoryMock) Insert(bankSlipFile *entities.BankS
 	return args.Error(0)
 }
 
+func (m *BankSlipFileMetadataRepositoryMock) UpdateStatus(bankSlipFile *entities.BankSlipFileMetadata) error {
+	args := m.Called(bankSlipFile)
+	return args.Error(0)
+}
+
+func (m *BankSlipFileMetadataRepositoryMock) UpdateHeader(bankSlipFile *entities.BankSlipFileMetadata) error {
+	args := m.Called(bankSlipFile)
+	return args.Error(0)
+}
+
+func (m *BankSlipFileMetadataRepositoryMock) UpdateContentHash(bankSlipFile *entities.BankSlipFileMetadata) error {
+	args := m.Called(bankSlipFile)
+	return args.Error(0)
+}
+
+func (m *BankSlipFileMetadataRepositoryMock) UpdateStoragePath(bankSlipFile *entities.BankSlipFileMetadata) error {
+	args := m.Called(bankSlipFile)
+	return args.Error(0)
+}
+
+func (m *BankSlipFileMetadataRepositoryMock) AddRejectedRows(fileId string, rejectedRows int) error {
+	args := m.Called(fileId, rejectedRows)
+	return args.Error(0)
+}
+
+func (m *BankSlipFileMetadataRepositoryMock) CompleteWhenAllRowsProcessed(fileId string) error {
+	args := m.Called(fileId)
+	return args.Error(0)
+}
+
+func (m *BankSlipFileMetadataRepositoryMock) GetById(fileId string) (*entities.BankSlipFileMetadata, error) {
+	args := m.Called(fileId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BankSlipFileMetadata), args.Error(1)
+}
+
+func (m *BankSlipFileMetadataRepositoryMock) GetByContentHash(contentHash string) (*entities.BankSlipFileMetadata, error) {
+	args := m.Called(contentHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BankSlipFileMetadata), args.Error(1)
+}
+
+func (m *BankSlipFileMetadataRepositoryMock) GetByIdempotencyKey(idempotencyKey string) (*entities.BankSlipFileMetadata, error) {
+	args := m.Called(idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BankSlipFileMetadata), args.Error(1)
+}
+
+func (m *BankSlipFileMetadataRepositoryMock) List(limit, offset int) ([]*entities.BankSlipFileMetadata, error) {
+	args := m.Called(limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.BankSlipFileMetadata), args.Error(1)
+}
+
 type BankSlipRepositoryMock struct {
 	mock.Mock
 }
@@ -42,3 +105,305 @@ func (m *BankSlipRepositoryMock) UpdateMany(bankSlips ...*entities.BankSlipMap)
 	args := m.Called(dynamicArgs...)
 	return args.Error(0)
 }
+
+func (m *BankSlipRepositoryMock) GetByDebtId(debtId string) (*entities.BankSlip, error) {
+	args := m.Called(debtId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BankSlip), args.Error(1)
+}
+
+// ForEachByFileId calls fn with each bank slip returned by the expectation.
+func (m *BankSlipRepositoryMock) ForEachByFileId(fileId string, statuses []entities.BankSlipStatus, fn func(bankSlip *entities.BankSlip) error) error {
+	args := m.Called(fileId, statuses)
+	if bankSlips, ok := args.Get(0).([]*entities.BankSlip); ok {
+		for _, bankSlip := range bankSlips {
+			if err := fn(bankSlip); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *BankSlipRepositoryMock) Reconcile(beneficiaryId string, returnRecord *entities.BankSlipReturnRecord) error {
+	args := m.Called(beneficiaryId, returnRecord)
+	return args.Error(0)
+}
+
+type UploadProfileRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *UploadProfileRepositoryMock) Insert(uploadProfile *entities.UploadProfile) error {
+	args := m.Called(uploadProfile)
+	return args.Error(0)
+}
+
+func (m *UploadProfileRepositoryMock) GetByName(name string) (*entities.UploadProfile, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.UploadProfile), args.Error(1)
+}
+
+func (m *UploadProfileRepositoryMock) List() ([]*entities.UploadProfile, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.UploadProfile), args.Error(1)
+}
+
+type FixedWidthLayoutRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *FixedWidthLayoutRepositoryMock) Insert(layout *entities.FixedWidthLayout) error {
+	args := m.Called(layout)
+	return args.Error(0)
+}
+
+func (m *FixedWidthLayoutRepositoryMock) GetByName(name string) (*entities.FixedWidthLayout, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FixedWidthLayout), args.Error(1)
+}
+
+func (m *FixedWidthLayoutRepositoryMock) GetByExtension(extension string) (*entities.FixedWidthLayout, error) {
+	args := m.Called(extension)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.FixedWidthLayout), args.Error(1)
+}
+
+func (m *FixedWidthLayoutRepositoryMock) List() ([]*entities.FixedWidthLayout, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.FixedWidthLayout), args.Error(1)
+}
+
+type BeneficiaryRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *BeneficiaryRepositoryMock) Insert(beneficiary *entities.Beneficiary) error {
+	args := m.Called(beneficiary)
+	return args.Error(0)
+}
+
+func (m *BeneficiaryRepositoryMock) GetById(id string) (*entities.Beneficiary, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Beneficiary), args.Error(1)
+}
+
+func (m *BeneficiaryRepositoryMock) List() ([]*entities.Beneficiary, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Beneficiary), args.Error(1)
+}
+
+type BankSlipRejectedRowRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *BankSlipRejectedRowRepositoryMock) InsertMany(rejectedRows []*entities.BankSlipRejectedRow) error {
+	args := m.Called(rejectedRows)
+	return args.Error(0)
+}
+
+func (m *BankSlipRejectedRowRepositoryMock) ListByFileId(fileId string) ([]*entities.BankSlipRejectedRow, error) {
+	args := m.Called(fileId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.BankSlipRejectedRow), args.Error(1)
+}
+
+type RemittanceRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *RemittanceRepositoryMock) Create(remittance *entities.Remittance, render entities.RemittanceRenderer) error {
+	args := m.Called(remittance, render)
+	return args.Error(0)
+}
+
+func (m *RemittanceRepositoryMock) GetById(id string) (*entities.Remittance, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Remittance), args.Error(1)
+}
+
+func (m *RemittanceRepositoryMock) List(limit, offset int) ([]*entities.Remittance, error) {
+	args := m.Called(limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Remittance), args.Error(1)
+}
+
+type BankSlipReturnFileRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *BankSlipReturnFileRepositoryMock) Insert(returnFile *entities.BankSlipReturnFile) error {
+	args := m.Called(returnFile)
+	return args.Error(0)
+}
+
+func (m *BankSlipReturnFileRepositoryMock) UpdateStatus(returnFile *entities.BankSlipReturnFile) error {
+	args := m.Called(returnFile)
+	return args.Error(0)
+}
+
+func (m *BankSlipReturnFileRepositoryMock) UpdateHeader(returnFile *entities.BankSlipReturnFile) error {
+	args := m.Called(returnFile)
+	return args.Error(0)
+}
+
+func (m *BankSlipReturnFileRepositoryMock) AddProcessedRecords(returnFileId string, processed, reconciled, unmatched int) error {
+	args := m.Called(returnFileId, processed, reconciled, unmatched)
+	return args.Error(0)
+}
+
+func (m *BankSlipReturnFileRepositoryMock) CompleteWhenAllRecordsProcessed(returnFileId string) error {
+	args := m.Called(returnFileId)
+	return args.Error(0)
+}
+
+func (m *BankSlipReturnFileRepositoryMock) GetById(returnFileId string) (*entities.BankSlipReturnFile, error) {
+	args := m.Called(returnFileId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.BankSlipReturnFile), args.Error(1)
+}
+
+type UnmatchedReturnRecordRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *UnmatchedReturnRecordRepositoryMock) InsertMany(unmatchedRecords []*entities.UnmatchedReturnRecord) error {
+	args := m.Called(unmatchedRecords)
+	return args.Error(0)
+}
+
+func (m *UnmatchedReturnRecordRepositoryMock) ListByReturnFileId(returnFileId string) ([]*entities.UnmatchedReturnRecord, error) {
+	args := m.Called(returnFileId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.UnmatchedReturnRecord), args.Error(1)
+}
+
+type BankSlipStatusHistoryRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *BankSlipStatusHistoryRepositoryMock) ListByDebtId(debtId string) ([]*entities.BankSlipStatusChange, error) {
+	args := m.Called(debtId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.BankSlipStatusChange), args.Error(1)
+}
+
+type DunningRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *DunningRepositoryMock) MoveDue(from []entities.BankSlipStatus, status entities.BankSlipStatus, dueBefore time.Time, reason string, limit int) (int, error) {
+	args := m.Called(from, status, dueBefore, reason, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *DunningRepositoryMock) ClaimNotices(step entities.DunningStep, date time.Time, limit int) ([]*entities.BankSlip, error) {
+	args := m.Called(step, date, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.BankSlip), args.Error(1)
+}
+
+func (m *DunningRepositoryMock) ReleaseNotice(debtId string, step entities.DunningStep) error {
+	args := m.Called(debtId, step)
+	return args.Error(0)
+}
+
+type InstallmentRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *InstallmentRepositoryMock) IssueScheduled(dueUntil time.Time, staleBefore time.Time, limit int) ([]*entities.BankSlip, error) {
+	args := m.Called(dueUntil, staleBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.BankSlip), args.Error(1)
+}
+
+func (m *InstallmentRepositoryMock) ListByParentDebtId(parentDebtId string) ([]*entities.BankSlip, error) {
+	args := m.Called(parentDebtId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.BankSlip), args.Error(1)
+}
+
+type SubscriptionRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *SubscriptionRepositoryMock) Insert(subscription *entities.Subscription) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *SubscriptionRepositoryMock) Update(subscription *entities.Subscription) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *SubscriptionRepositoryMock) GetById(id string) (*entities.Subscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) List(beneficiaryId string) ([]*entities.Subscription, error) {
+	args := m.Called(beneficiaryId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) ListDue(dueUntil time.Time, limit int) ([]*entities.Subscription, error) {
+	args := m.Called(dueUntil, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionRepositoryMock) Advance(subscription *entities.Subscription, fromPeriod time.Time) error {
+	args := m.Called(subscription, fromPeriod)
+	return args.Error(0)
+}
@@ -2,8 +2,12 @@ package bank_slip
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/handler"
 	"performatic-file-processor/internal/messaging"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -26,7 +30,394 @@ type ReceiveUploadServiceMock struct {
 func (s *ReceiveUploadServiceMock) Execute(
 	file multipart.File,
 	fileHeader *multipart.FileHeader,
-) error {
+	options bankSlipEntities.UploadOptions,
+) (*bankSlipEntities.BankSlipFileMetadata, error) {
+	args := s.Called(file, fileHeader, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlipFileMetadata), args.Error(1)
+}
+
+type ValidateUploadServiceMock struct {
+	mock.Mock
+}
+
+func (s *ValidateUploadServiceMock) Execute(
+	file multipart.File,
+	fileHeader *multipart.FileHeader,
+	options bankSlipEntities.UploadOptions,
+) (*bankSlipEntities.UploadValidationReport, error) {
+	args := s.Called(file, fileHeader, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.UploadValidationReport), args.Error(1)
+}
+
+type GetBankSlipFileServiceMock struct {
+	mock.Mock
+}
+
+func (s *GetBankSlipFileServiceMock) Execute(fileId string) (*bankSlipEntities.BankSlipFileMetadata, error) {
+	args := s.Called(fileId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlipFileMetadata), args.Error(1)
+}
+
+type GetBankSlipRejectedRowsServiceMock struct {
+	mock.Mock
+}
+
+func (s *GetBankSlipRejectedRowsServiceMock) Execute(fileId string) (*bankSlipEntities.BankSlipFileMetadata, []*bankSlipEntities.BankSlipRejectedRow, error) {
+	args := s.Called(fileId)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlipFileMetadata), args.Get(1).([]*bankSlipEntities.BankSlipRejectedRow), args.Error(2)
+}
+
+type GetBankSlipFileOriginalServiceMock struct {
+	mock.Mock
+}
+
+func (s *GetBankSlipFileOriginalServiceMock) Execute(fileId string) (*bankSlipEntities.BankSlipFileMetadata, handler.SavedFile, error) {
+	args := s.Called(fileId)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlipFileMetadata), args.Get(1).(handler.SavedFile), args.Error(2)
+}
+
+type ExportBankSlipFilePdfsServiceMock struct {
+	mock.Mock
+}
+
+func (s *ExportBankSlipFilePdfsServiceMock) Execute(fileId string, w io.Writer) (int, error) {
+	args := s.Called(fileId, w)
+	return args.Int(0), args.Error(1)
+}
+
+type GetBankSlipPdfServiceMock struct {
+	mock.Mock
+}
+
+func (s *GetBankSlipPdfServiceMock) Execute(debtId string) (*bankSlipEntities.BankSlip, []byte, error) {
+	args := s.Called(debtId)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlip), args.Get(1).([]byte), args.Error(2)
+}
+
+type RetryBankSlipBillingServiceMock struct {
+	mock.Mock
+}
+
+func (s *RetryBankSlipBillingServiceMock) Execute(debtId string) (*bankSlipEntities.BankSlip, error) {
+	args := s.Called(debtId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlip), args.Error(1)
+}
+
+type GetBankSlipStatusHistoryServiceMock struct {
+	mock.Mock
+}
+
+func (s *GetBankSlipStatusHistoryServiceMock) Execute(debtId string) (*bankSlipEntities.BankSlip, []*bankSlipEntities.BankSlipStatusChange, error) {
+	args := s.Called(debtId)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlip), args.Get(1).([]*bankSlipEntities.BankSlipStatusChange), args.Error(2)
+}
+
+type GetBankSlipAmountServiceMock struct {
+	mock.Mock
+}
+
+func (s *GetBankSlipAmountServiceMock) Execute(debtId string, date time.Time) (*bankSlipEntities.BankSlip, bankSlipEntities.AmountDue, error) {
+	args := s.Called(debtId, date)
+	if args.Get(0) == nil {
+		return nil, bankSlipEntities.AmountDue{}, args.Error(2)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlip), args.Get(1).(bankSlipEntities.AmountDue), args.Error(2)
+}
+
+type ListBankSlipFilesServiceMock struct {
+	mock.Mock
+}
+
+func (s *ListBankSlipFilesServiceMock) Execute(limit, offset int) ([]*bankSlipEntities.BankSlipFileMetadata, error) {
+	args := s.Called(limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*bankSlipEntities.BankSlipFileMetadata), args.Error(1)
+}
+
+type CreateUploadProfileServiceMock struct {
+	mock.Mock
+}
+
+func (s *CreateUploadProfileServiceMock) Execute(
+	name string,
+	columns map[bankSlipEntities.BankSlipField][]string,
+	dateFormat, decimalSeparator, dueDatePolicy string,
+) (*bankSlipEntities.UploadProfile, error) {
+	args := s.Called(name, columns, dateFormat, decimalSeparator, dueDatePolicy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.UploadProfile), args.Error(1)
+}
+
+type ListUploadProfilesServiceMock struct {
+	mock.Mock
+}
+
+func (s *ListUploadProfilesServiceMock) Execute() ([]*bankSlipEntities.UploadProfile, error) {
 	args := s.Called()
-	return args.Error(0)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*bankSlipEntities.UploadProfile), args.Error(1)
+}
+
+type CreateBeneficiaryServiceMock struct {
+	mock.Mock
+}
+
+func (s *CreateBeneficiaryServiceMock) Execute(
+	name, document, bankCode, agency, account, wallet, agreementCode string, chargePolicy bankSlipEntities.ChargePolicy,
+) (*bankSlipEntities.Beneficiary, error) {
+	args := s.Called(name, document, bankCode, agency, account, wallet, agreementCode, chargePolicy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.Beneficiary), args.Error(1)
+}
+
+type ListBeneficiariesServiceMock struct {
+	mock.Mock
+}
+
+func (s *ListBeneficiariesServiceMock) Execute() ([]*bankSlipEntities.Beneficiary, error) {
+	args := s.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*bankSlipEntities.Beneficiary), args.Error(1)
+}
+
+type StreamUploadServiceMock struct {
+	mock.Mock
+}
+
+func (s *StreamUploadServiceMock) Execute(
+	ctx context.Context,
+	content io.Reader,
+	fileName string,
+	options bankSlipEntities.UploadOptions,
+) (*bankSlipEntities.BankSlipFileMetadata, error) {
+	args := s.Called(ctx, content, fileName, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlipFileMetadata), args.Error(1)
+}
+
+type CreateRemittanceServiceMock struct {
+	mock.Mock
+}
+
+func (s *CreateRemittanceServiceMock) Execute(beneficiaryId, fileId, format string) (*bankSlipEntities.Remittance, error) {
+	args := s.Called(beneficiaryId, fileId, format)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.Remittance), args.Error(1)
+}
+
+type ListRemittancesServiceMock struct {
+	mock.Mock
+}
+
+func (s *ListRemittancesServiceMock) Execute(limit, offset int) ([]*bankSlipEntities.Remittance, error) {
+	args := s.Called(limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*bankSlipEntities.Remittance), args.Error(1)
+}
+
+type GetRemittanceServiceMock struct {
+	mock.Mock
+}
+
+func (s *GetRemittanceServiceMock) Execute(remittanceId string) (*bankSlipEntities.Remittance, error) {
+	args := s.Called(remittanceId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.Remittance), args.Error(1)
+}
+
+type ReceiveReturnFileServiceMock struct {
+	mock.Mock
+}
+
+func (s *ReceiveReturnFileServiceMock) Execute(file multipart.File, fileHeader *multipart.FileHeader, beneficiaryId string) (*bankSlipEntities.BankSlipReturnFile, error) {
+	args := s.Called(file, fileHeader, beneficiaryId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlipReturnFile), args.Error(1)
+}
+
+type GetReturnFileServiceMock struct {
+	mock.Mock
+}
+
+func (s *GetReturnFileServiceMock) Execute(returnFileId string) (*bankSlipEntities.BankSlipReturnFile, error) {
+	args := s.Called(returnFileId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlipReturnFile), args.Error(1)
+}
+
+type GetUnmatchedReturnRecordsServiceMock struct {
+	mock.Mock
+}
+
+func (s *GetUnmatchedReturnRecordsServiceMock) Execute(returnFileId string) (*bankSlipEntities.BankSlipReturnFile, []*bankSlipEntities.UnmatchedReturnRecord, error) {
+	args := s.Called(returnFileId)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*bankSlipEntities.BankSlipReturnFile), args.Get(1).([]*bankSlipEntities.UnmatchedReturnRecord), args.Error(2)
+}
+
+type ProcessReturnRecordsServiceMock struct {
+	mock.Mock
+}
+
+func (s *ProcessReturnRecordsServiceMock) Execute(
+	context context.Context,
+	messagesChannel chan messaging.Message,
+) {
+	s.Called()
+}
+
+type CreateFixedWidthLayoutServiceMock struct {
+	mock.Mock
+}
+
+func (s *CreateFixedWidthLayoutServiceMock) Execute(name, extension, recordType string, fields []bankSlipEntities.FixedWidthField, dateFormat string) (*bankSlipEntities.FixedWidthLayout, error) {
+	args := s.Called(name, extension, recordType, fields, dateFormat)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.FixedWidthLayout), args.Error(1)
+}
+
+type ListFixedWidthLayoutsServiceMock struct {
+	mock.Mock
+}
+
+func (s *ListFixedWidthLayoutsServiceMock) Execute() ([]*bankSlipEntities.FixedWidthLayout, error) {
+	args := s.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*bankSlipEntities.FixedWidthLayout), args.Error(1)
+}
+
+type CreateInstallmentsServiceMock struct {
+	mock.Mock
+}
+
+func (s *CreateInstallmentsServiceMock) Execute(debtId string, plan bankSlipEntities.InstallmentPlan, firstDueDate time.Time) ([]*bankSlipEntities.BankSlip, error) {
+	args := s.Called(debtId, plan, firstDueDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*bankSlipEntities.BankSlip), args.Error(1)
+}
+
+type ListInstallmentsServiceMock struct {
+	mock.Mock
+}
+
+func (s *ListInstallmentsServiceMock) Execute(parentDebtId string) ([]*bankSlipEntities.BankSlip, error) {
+	args := s.Called(parentDebtId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*bankSlipEntities.BankSlip), args.Error(1)
+}
+
+type CreateSubscriptionServiceMock struct {
+	mock.Mock
+}
+
+func (s *CreateSubscriptionServiceMock) Execute(beneficiaryId string, startDate time.Time, terms bankSlipEntities.SubscriptionTerms) (*bankSlipEntities.Subscription, error) {
+	args := s.Called(beneficiaryId, startDate, terms)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.Subscription), args.Error(1)
+}
+
+type UpdateSubscriptionServiceMock struct {
+	mock.Mock
+}
+
+func (s *UpdateSubscriptionServiceMock) Execute(id string, terms bankSlipEntities.SubscriptionTerms) (*bankSlipEntities.Subscription, error) {
+	args := s.Called(id, terms)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.Subscription), args.Error(1)
+}
+
+type CancelSubscriptionServiceMock struct {
+	mock.Mock
+}
+
+func (s *CancelSubscriptionServiceMock) Execute(id string) (*bankSlipEntities.Subscription, error) {
+	args := s.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.Subscription), args.Error(1)
+}
+
+type GetSubscriptionServiceMock struct {
+	mock.Mock
+}
+
+func (s *GetSubscriptionServiceMock) Execute(id string) (*bankSlipEntities.Subscription, error) {
+	args := s.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bankSlipEntities.Subscription), args.Error(1)
+}
+
+type ListSubscriptionsServiceMock struct {
+	mock.Mock
+}
+
+func (s *ListSubscriptionsServiceMock) Execute(beneficiaryId string) ([]*bankSlipEntities.Subscription, error) {
+	args := s.Called(beneficiaryId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*bankSlipEntities.Subscription), args.Error(1)
 }
@@ -1,6 +1,8 @@
 package bank_slip
 
 import (
+	"log"
+
 	bsEntities "performatic-file-processor/internal/bank_slip/entity"
 	"performatic-file-processor/internal/infra/billing"
 	emailService "performatic-file-processor/internal/infra/email"
@@ -36,8 +38,8 @@ func (p *GenerateBillingAndSentEmailProviderImpl) GenerateBillingAndSentEmail(
 	errorsGeneratingBilling := *p.billingService.GenerateBiling(&successBankSlips)
 	for debtId := range errorsGeneratingBilling {
 		bankSlipWithError := successBankSlips[debtId]
-		bankSlipWithError.ErrorGeneratingBilling(errorsGeneratingBilling[debtId].Error())
 		if bankSlipWithError != nil {
+			logTransitionError(bankSlipWithError.ErrorGeneratingBilling(errorsGeneratingBilling[debtId].Error()))
 			bankSlipsWithError[debtId] = bankSlipWithError
 			delete(successBankSlips, debtId)
 		}
@@ -46,15 +48,23 @@ func (p *GenerateBillingAndSentEmailProviderImpl) GenerateBillingAndSentEmail(
 	errorsSendingEmail := *p.emailService.SendBankSlipWaitingPaymentEmail(&successBankSlips)
 	for debtId := range errorsSendingEmail {
 		bankSlipWithError := successBankSlips[debtId]
-		bankSlipWithError.ErrorSendingEmail(errorsSendingEmail[debtId].Error())
 		if bankSlipWithError != nil {
+			logTransitionError(bankSlipWithError.ErrorSendingEmail(errorsSendingEmail[debtId].Error()))
 			bankSlipsWithError[debtId] = bankSlipWithError
 			delete(successBankSlips, debtId)
 		}
 	}
 
 	for _, bankSlip := range successBankSlips {
-		bankSlip.Success()
+		logTransitionError(bankSlip.Success())
 	}
 	return &bankSlipsWithError
 }
+
+// logTransitionError reports a bank slip that was not pending anymore, it
+// keeps its status and is saved as it is.
+func logTransitionError(err error) {
+	if err != nil {
+		log.Printf("Error changing bank slip status: %v\n", err)
+	}
+}
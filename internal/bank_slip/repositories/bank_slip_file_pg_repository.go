@@ -16,12 +16,199 @@ func NewBankSlipFilePgRepository(db *sql.DB) *BankSlipFilePgRepository {
 }
 
 func (r *BankSlipFilePgRepository) Insert(bankSlipFile *entities.BankSlipFileMetadata) error {
-	query := "INSERT INTO bank_slip_file (name) VALUES ($1) returning id"
+	query := `
+		INSERT INTO bank_slip_file (name, content_hash, idempotency_key, beneficiary_id) VALUES ($1, $2, NULLIF($3, ''), $4)
+		ON CONFLICT (idempotency_key) DO NOTHING
+		returning id
+	`
 
-	err := r.db.QueryRow(query, bankSlipFile.FileName).Scan(&bankSlipFile.ID)
+	err := r.db.QueryRow(query, bankSlipFile.FileName, bankSlipFile.ContentHash, bankSlipFile.IdempotencyKey, bankSlipFile.BeneficiaryId).Scan(&bankSlipFile.ID)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrIdempotencyKeyAlreadyUsed
+	}
 	if err != nil {
 		return errors.New("erro ao inserir arquivo no banco")
 	}
 	return nil
 }
+
+func (r *BankSlipFilePgRepository) UpdateStatus(bankSlipFile *entities.BankSlipFileMetadata) error {
+	query := "UPDATE bank_slip_file SET status = $2, total_rows = $3, updated_at = NOW() WHERE id = $1"
+
+	_, err := r.db.Exec(query, bankSlipFile.ID, bankSlipFile.Status, bankSlipFile.TotalRows)
+	return err
+}
+
+func (r *BankSlipFilePgRepository) UpdateHeader(bankSlipFile *entities.BankSlipFileMetadata) error {
+	query := "UPDATE bank_slip_file SET header = $2, delimiter = $3, updated_at = NOW() WHERE id = $1"
+
+	_, err := r.db.Exec(query, bankSlipFile.ID, bankSlipFile.Header, bankSlipFile.Delimiter)
+	return err
+}
+
+func (r *BankSlipFilePgRepository) UpdateContentHash(bankSlipFile *entities.BankSlipFileMetadata) error {
+	query := "UPDATE bank_slip_file SET content_hash = $2, updated_at = NOW() WHERE id = $1"
+
+	_, err := r.db.Exec(query, bankSlipFile.ID, bankSlipFile.ContentHash)
+	return err
+}
+
+func (r *BankSlipFilePgRepository) UpdateStoragePath(bankSlipFile *entities.BankSlipFileMetadata) error {
+	query := "UPDATE bank_slip_file SET storage_path = $2, updated_at = NOW() WHERE id = $1"
+
+	_, err := r.db.Exec(query, bankSlipFile.ID, bankSlipFile.StoragePath)
+	return err
+}
+
+func (r *BankSlipFilePgRepository) AddRejectedRows(fileId string, rejectedRows int) error {
+	query := "UPDATE bank_slip_file SET rejected_rows = rejected_rows + $2, updated_at = NOW() WHERE id = $1"
+
+	_, err := r.db.Exec(query, fileId, rejectedRows)
+	return err
+}
+
+// CompleteWhenAllRowsProcessed counts the installments of a row as one row,
+// processed once none of them is PENDING.
+func (r *BankSlipFilePgRepository) CompleteWhenAllRowsProcessed(fileId string) error {
+	query := `
+		UPDATE bank_slip_file bsf
+		SET status = $2, updated_at = NOW()
+		WHERE bsf.id = $1
+			AND bsf.status = $3
+			AND bsf.rejected_rows + (
+				SELECT count(*) FROM (
+					SELECT 1 FROM bank_slip bs
+					WHERE bs.bank_slip_file_id = bsf.id
+					GROUP BY coalesce(bs.parent_debt_id, bs.debt_id)
+					HAVING bool_and(bs.status <> $4)
+				) processed_rows
+			) >= bsf.total_rows
+	`
+
+	_, err := r.db.Exec(
+		query,
+		fileId,
+		entities.BankSlipFileStatusCompleted,
+		entities.BankSlipFileStatusProcessing,
+		entities.BankSlipStatusPending,
+	)
+	return err
+}
+
+func (r *BankSlipFilePgRepository) GetById(fileId string) (*entities.BankSlipFileMetadata, error) {
+	query := `
+		SELECT bsf.id, bsf.name, bsf.status, bsf.total_rows, bsf.rejected_rows, bsf.header, bsf.delimiter, bsf.content_hash, bsf.storage_path, bsf.beneficiary_id, bsf.created_at, bs.status, count(bs.debt_id)
+		FROM bank_slip_file bsf
+		LEFT JOIN bank_slip bs ON bs.bank_slip_file_id = bsf.id
+		WHERE bsf.id = $1
+		GROUP BY bsf.id, bs.status
+	`
+
+	queryResult, err := r.db.Query(query, fileId)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	files, err := r.scanFilesWithStatusCount(queryResult)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, entities.ErrBankSlipFileNotFound
+	}
+	return files[0], nil
+}
+
+// GetByContentHash returns the latest file with the same content that did not fail.
+func (r *BankSlipFilePgRepository) GetByContentHash(contentHash string) (*entities.BankSlipFileMetadata, error) {
+	query := `
+		SELECT id FROM bank_slip_file
+		WHERE content_hash = $1 AND status <> $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	return r.getByIdFrom(r.db.QueryRow(query, contentHash, entities.BankSlipFileStatusFailed))
+}
+
+func (r *BankSlipFilePgRepository) GetByIdempotencyKey(idempotencyKey string) (*entities.BankSlipFileMetadata, error) {
+	query := "SELECT id FROM bank_slip_file WHERE idempotency_key = $1"
+
+	return r.getByIdFrom(r.db.QueryRow(query, idempotencyKey))
+}
+
+func (r *BankSlipFilePgRepository) getByIdFrom(row *sql.Row) (*entities.BankSlipFileMetadata, error) {
+	var fileId string
+	err := row.Scan(&fileId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entities.ErrBankSlipFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetById(fileId)
+}
+
+func (r *BankSlipFilePgRepository) List(limit, offset int) ([]*entities.BankSlipFileMetadata, error) {
+	query := `
+		SELECT bsf.id, bsf.name, bsf.status, bsf.total_rows, bsf.rejected_rows, bsf.header, bsf.delimiter, bsf.content_hash, bsf.storage_path, bsf.beneficiary_id, bsf.created_at, bs.status, count(bs.debt_id)
+		FROM (
+			SELECT * FROM bank_slip_file ORDER BY created_at DESC, id LIMIT $1 OFFSET $2
+		) bsf
+		LEFT JOIN bank_slip bs ON bs.bank_slip_file_id = bsf.id
+		GROUP BY bsf.id, bsf.name, bsf.status, bsf.total_rows, bsf.rejected_rows, bsf.header, bsf.delimiter, bsf.content_hash, bsf.storage_path, bsf.beneficiary_id, bsf.created_at, bs.status
+		ORDER BY bsf.created_at DESC, bsf.id
+	`
+
+	queryResult, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	return r.scanFilesWithStatusCount(queryResult)
+}
+
+func (r *BankSlipFilePgRepository) scanFilesWithStatusCount(queryResult *sql.Rows) ([]*entities.BankSlipFileMetadata, error) {
+	files := []*entities.BankSlipFileMetadata{}
+	filesById := map[string]*entities.BankSlipFileMetadata{}
+
+	for queryResult.Next() {
+		var file entities.BankSlipFileMetadata
+		var bankSlipStatus sql.NullString
+		var count int
+		err := queryResult.Scan(
+			&file.ID,
+			&file.FileName,
+			&file.Status,
+			&file.TotalRows,
+			&file.RejectedRows,
+			&file.Header,
+			&file.Delimiter,
+			&file.ContentHash,
+			&file.StoragePath,
+			&file.BeneficiaryId,
+			&file.CreatedAt,
+			&bankSlipStatus,
+			&count,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		existing, ok := filesById[file.ID]
+		if !ok {
+			file.RowsByStatus = entities.NewBankSlipStatusCount()
+			existing = &file
+			filesById[file.ID] = existing
+			files = append(files, existing)
+		}
+		if bankSlipStatus.Valid {
+			existing.RowsByStatus[entities.BankSlipStatus(bankSlipStatus.String)] = count
+		}
+	}
+
+	return files, queryResult.Err()
+}
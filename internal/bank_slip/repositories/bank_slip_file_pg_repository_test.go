@@ -5,6 +5,7 @@ import (
 	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -32,11 +33,14 @@ func TestBankSlipFilePgRepository(t *testing.T) {
 
 func (suite *BankSlipFilePgRepositoryTestSuite) TestInsertAndGetBankSlipFileMetadata() {
 	fileMetadata := &bankSlipEntities.BankSlipFileMetadata{
-		FileName: "test_file.txt",
+		FileName:       "test_file.txt",
+		ContentHash:    "hash",
+		IdempotencyKey: "key",
+		BeneficiaryId:  "beneficiary_id",
 	}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO bank_slip_file (name) VALUES ($1) returning id")).
-		WithArgs(fileMetadata.FileName).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO bank_slip_file (name, content_hash, idempotency_key, beneficiary_id) VALUES ($1, $2, NULLIF($3, ''), $4)")).
+		WithArgs(fileMetadata.FileName, fileMetadata.ContentHash, fileMetadata.IdempotencyKey, fileMetadata.BeneficiaryId).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 	err := suite.repository.Insert(fileMetadata)
@@ -49,14 +53,205 @@ func (suite *BankSlipFilePgRepositoryTestSuite) TestInsertAndGetBankSlipFileMeta
 
 func (suite *BankSlipFilePgRepositoryTestSuite) TestInsertAndGetBankSlipFileMetadataError() {
 	fileMetadata := &bankSlipEntities.BankSlipFileMetadata{
-		FileName: "test_file.txt",
+		FileName:       "test_file.txt",
+		ContentHash:    "hash",
+		IdempotencyKey: "key",
 	}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO bank_slip_file (name) VALUES ($1) returning id")).
-		WithArgs(fileMetadata.FileName).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO bank_slip_file (name, content_hash, idempotency_key, beneficiary_id) VALUES ($1, $2, NULLIF($3, ''), $4)")).
+		WithArgs(fileMetadata.FileName, fileMetadata.ContentHash, fileMetadata.IdempotencyKey, fileMetadata.BeneficiaryId).
 		WillReturnError(sql.ErrNoRows)
 
 	err := suite.repository.Insert(fileMetadata)
 	assert.Error(suite.T(), err)
 
 }
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestInsertWithUsedIdempotencyKey() {
+	fileMetadata := &bankSlipEntities.BankSlipFileMetadata{
+		FileName:       "test_file.txt",
+		ContentHash:    "hash",
+		IdempotencyKey: "key",
+	}
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("ON CONFLICT (idempotency_key) DO NOTHING")).
+		WithArgs("test_file.txt", "hash", "key", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := suite.repository.Insert(fileMetadata)
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrIdempotencyKeyAlreadyUsed)
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestUpdateStatus() {
+	fileMetadata := &bankSlipEntities.BankSlipFileMetadata{
+		ID:        "file_id",
+		Status:    bankSlipEntities.BankSlipFileStatusProcessing,
+		TotalRows: 10,
+	}
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE bank_slip_file SET status = $2, total_rows = $3")).
+		WithArgs("file_id", bankSlipEntities.BankSlipFileStatusProcessing, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repository.UpdateStatus(fileMetadata)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestUpdateHeader() {
+	fileMetadata := &bankSlipEntities.BankSlipFileMetadata{ID: "file_id"}
+	fileMetadata.HeaderRead("name;debtId", ";")
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE bank_slip_file SET header = $2, delimiter = $3")).
+		WithArgs("file_id", "name;debtId", ";").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repository.UpdateHeader(fileMetadata)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestUpdateContentHash() {
+	fileMetadata := &bankSlipEntities.BankSlipFileMetadata{ID: "file_id", ContentHash: "hash"}
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE bank_slip_file SET content_hash = $2")).
+		WithArgs("file_id", "hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repository.UpdateContentHash(fileMetadata)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestUpdateStoragePath() {
+	fileMetadata := &bankSlipEntities.BankSlipFileMetadata{ID: "file_id", StoragePath: "uploads/test.csv"}
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE bank_slip_file SET storage_path = $2")).
+		WithArgs("file_id", "uploads/test.csv").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repository.UpdateStoragePath(fileMetadata)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestAddRejectedRows() {
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE bank_slip_file SET rejected_rows = rejected_rows + $2")).
+		WithArgs("file_id", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repository.AddRejectedRows("file_id", 3)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestCompleteWhenAllRowsProcessed() {
+	suite.mock.ExpectExec("UPDATE bank_slip_file bsf(.+)GROUP BY coalesce\\(bs.parent_debt_id, bs.debt_id\\)").
+		WithArgs(
+			"file_id",
+			bankSlipEntities.BankSlipFileStatusCompleted,
+			bankSlipEntities.BankSlipFileStatusProcessing,
+			bankSlipEntities.BankSlipStatusPending,
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repository.CompleteWhenAllRowsProcessed("file_id")
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestGetById() {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "name", "status", "total_rows", "rejected_rows", "header", "delimiter", "content_hash", "storage_path", "beneficiary_id", "created_at", "status", "count"}
+
+	suite.mock.ExpectQuery("SELECT bsf.id, bsf.name").
+		WithArgs("file_id").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("file_id", "test.csv", "PROCESSING", 10, 1, "name,debtId", ",", "hash", "uploads/test.csv", "beneficiary_id", createdAt, "SUCCESS", 6).
+			AddRow("file_id", "test.csv", "PROCESSING", 10, 1, "name,debtId", ",", "hash", "uploads/test.csv", "beneficiary_id", createdAt, "PENDING", 3))
+
+	file, err := suite.repository.GetById("file_id")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "test.csv", file.FileName)
+	assert.Equal(suite.T(), bankSlipEntities.BankSlipFileStatusProcessing, file.Status)
+	assert.Equal(suite.T(), 10, file.TotalRows)
+	assert.Equal(suite.T(), 1, file.RejectedRows)
+	assert.Equal(suite.T(), "name,debtId", file.Header)
+	assert.Equal(suite.T(), ",", file.Delimiter)
+	assert.Equal(suite.T(), "hash", file.ContentHash)
+	assert.Equal(suite.T(), "uploads/test.csv", file.StoragePath)
+	assert.Equal(suite.T(), "beneficiary_id", file.BeneficiaryId)
+	assert.Equal(suite.T(), createdAt, file.CreatedAt)
+	assert.Equal(suite.T(), 6, file.RowsByStatus[bankSlipEntities.BankSlipStatusSuccess])
+	assert.Equal(suite.T(), 3, file.RowsByStatus[bankSlipEntities.BankSlipStatusPending])
+	assert.Equal(suite.T(), 0, file.RowsByStatus[bankSlipEntities.BankSlipStatusGenerateBillingError])
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestGetByIdNotFound() {
+	columns := []string{"id", "name", "status", "total_rows", "rejected_rows", "header", "delimiter", "content_hash", "storage_path", "beneficiary_id", "created_at", "status", "count"}
+
+	suite.mock.ExpectQuery("SELECT bsf.id, bsf.name").
+		WithArgs("file_id").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	file, err := suite.repository.GetById("file_id")
+	assert.Nil(suite.T(), file)
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrBankSlipFileNotFound)
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestListKeepsOrderAndGroupsStatus() {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "name", "status", "total_rows", "rejected_rows", "header", "delimiter", "content_hash", "storage_path", "beneficiary_id", "created_at", "status", "count"}
+
+	suite.mock.ExpectQuery("SELECT bsf.id, bsf.name").
+		WithArgs(20, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("file_2", "second.csv", "RECEIVED", 0, 0, "name,debtId", ",", "hash", "uploads/test.csv", "beneficiary_id", createdAt, nil, 0).
+			AddRow("file_1", "first.csv", "COMPLETED", 2, 0, "name,debtId", ",", "hash", "uploads/test.csv", "beneficiary_id", createdAt, "SUCCESS", 1).
+			AddRow("file_1", "first.csv", "COMPLETED", 2, 0, "name,debtId", ",", "hash", "uploads/test.csv", "beneficiary_id", createdAt, "SENT_EMAIL_WITH_ERROR", 1))
+
+	files, err := suite.repository.List(20, 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), files, 2)
+	assert.Equal(suite.T(), "file_2", files[0].ID)
+	assert.Equal(suite.T(), rowsByStatus(0, 0, 0, 0), files[0].RowsByStatus)
+	assert.Equal(suite.T(), "file_1", files[1].ID)
+	assert.Equal(suite.T(), rowsByStatus(0, 1, 0, 1), files[1].RowsByStatus)
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestGetByContentHashIgnoresFailedFiles() {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "name", "status", "total_rows", "rejected_rows", "header", "delimiter", "content_hash", "storage_path", "beneficiary_id", "created_at", "status", "count"}
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM bank_slip_file")).
+		WithArgs("hash", bankSlipEntities.BankSlipFileStatusFailed).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("file_id"))
+	suite.mock.ExpectQuery("SELECT bsf.id, bsf.name").
+		WithArgs("file_id").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("file_id", "test.csv", "COMPLETED", 1, 0, "name,debtId", ",", "hash", "uploads/test.csv", "beneficiary_id", createdAt, "SUCCESS", 1))
+
+	file, err := suite.repository.GetByContentHash("hash")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "file_id", file.ID)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BankSlipFilePgRepositoryTestSuite) TestGetByIdempotencyKeyNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM bank_slip_file WHERE idempotency_key = $1")).
+		WithArgs("key").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	file, err := suite.repository.GetByIdempotencyKey("key")
+	assert.Nil(suite.T(), file)
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrBankSlipFileNotFound)
+}
+
+func rowsByStatus(pending, success, billingError, emailError int) map[bankSlipEntities.BankSlipStatus]int {
+	return map[bankSlipEntities.BankSlipStatus]int{
+		bankSlipEntities.BankSlipStatusPending:              pending,
+		bankSlipEntities.BankSlipStatusSuccess:              success,
+		bankSlipEntities.BankSlipStatusGenerateBillingError: billingError,
+		bankSlipEntities.BankSlipStatusSendingEmailError:    emailError,
+	}
+}
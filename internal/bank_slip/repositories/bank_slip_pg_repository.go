@@ -1,9 +1,16 @@
 package bank_slip
 
 import (
+	"cmp"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	entities "performatic-file-processor/internal/bank_slip/entity"
 )
@@ -16,69 +23,480 @@ func NewBankSlipPgRepository(db *sql.DB) *BankSlipPgRepository {
 	return &BankSlipPgRepository{db: db}
 }
 
+// UpdateMany saves the bank slips and, in the same transaction, the status
+// history of the ones whose status changed. The rows are locked first so the
+// status they are changed from is the one they were in. A bank slip that can't
+// move from its stored status, as one paid since it was read, is left as it is
+// and reported with ErrUnexpectedBankSlipStatus once the others are saved.
 func (r *BankSlipPgRepository) UpdateMany(bankSlipList ...*entities.BankSlipMap) error {
+	bankSlips := []*entities.BankSlip{}
+	for _, bankSlipP := range bankSlipList {
+		for _, slip := range *bankSlipP {
+			bankSlips = append(bankSlips, slip)
+		}
+	}
+	if len(bankSlips) == 0 {
+		return nil
+	}
+	slices.SortFunc(bankSlips, func(a, b *entities.BankSlip) int {
+		return strings.Compare(a.DebtId, b.DebtId)
+	})
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	previousStatuses, err := lockStatuses(tx, bankSlips)
+	if err != nil {
+		return err
+	}
+
+	unexpected := []string{}
+	bankSlips = slices.DeleteFunc(bankSlips, func(slip *entities.BankSlip) bool {
+		previousStatus, exists := previousStatuses[slip.DebtId]
+		if !exists || previousStatus == slip.Status || previousStatus.CanTransitionTo(slip.Status) {
+			return false
+		}
+		unexpected = append(unexpected, fmt.Sprintf("%s is %s and can't become %s", slip.DebtId, previousStatus, slip.Status))
+		return true
+	})
+	if err := updateBankSlips(tx, bankSlips, previousStatuses); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if len(unexpected) > 0 {
+		return fmt.Errorf("%w: %s", entities.ErrUnexpectedBankSlipStatus, strings.Join(unexpected, ", "))
+	}
+	return nil
+}
+
+// updateBankSlips writes the bank slips over their locked rows, and the
+// history of the ones whose status changed.
+func updateBankSlips(tx *sql.Tx, bankSlips []*entities.BankSlip, previousStatuses map[entities.DebitId]entities.BankSlipStatus) error {
+	if len(bankSlips) == 0 {
+		return nil
+	}
+
 	fields := []any{}
 	queryValues := ""
-	i := 0
-	for _, bankSlipP := range bankSlipList {
-		bankSlip := *bankSlipP
-		for _, slip := range bankSlip {
-			fields = append(fields, slip.DebtId, slip.Status, slip.ErrorMessage)
-			queryValues += fmt.Sprintf("(cast($%d AS uuid), $%d, $%d)", i*3+1, i*3+2, i*3+3)
-			if i < len(bankSlip)-1 {
-				queryValues += ", "
-			}
-			i++
+	for i, slip := range bankSlips {
+		fields = append(fields, slip.DebtId, slip.Status, slip.ErrorMessage, slip.Barcode, slip.DigitableLine, slip.PixPayload)
+		queryValues += fmt.Sprintf("(cast($%d AS uuid), $%d, $%d, $%d, $%d, $%d)", i*6+1, i*6+2, i*6+3, i*6+4, i*6+5, i*6+6)
+		if i < len(bankSlips)-1 {
+			queryValues += ", "
 		}
 	}
 	query := fmt.Sprintf(`
 		UPDATE bank_slip bs 
 		SET
 			status = tmp.status,
-			error_message = tmp.error_message
+			error_message = tmp.error_message,
+			barcode = tmp.barcode,
+			digitable_line = tmp.digitable_line,
+			pix_payload = tmp.pix_payload
 		FROM (
 			VALUES
 				%s
-		) AS tmp(debt_id, status, error_message)
+		) AS tmp(debt_id, status, error_message, barcode, digitable_line, pix_payload)
 		WHERE bs.debt_id = tmp.debt_id
 	`, queryValues)
-	_, err := r.db.Exec(query, fields...)
-	if err != nil {
+	if _, err := tx.Exec(query, fields...); err != nil {
 		return err
 	}
-	return nil
+
+	statusChanges := []*entities.BankSlipStatusChange{}
+	for _, slip := range bankSlips {
+		previousStatus, exists := previousStatuses[slip.DebtId]
+		if !exists || previousStatus == slip.Status {
+			continue
+		}
+		reason := ""
+		if slip.ErrorMessage != nil {
+			reason = *slip.ErrorMessage
+		}
+		statusChanges = append(statusChanges, entities.NewBankSlipStatusChange(slip.DebtId, previousStatus, slip.Status, reason))
+	}
+	return insertStatusHistory(tx, statusChanges)
 }
 
-func (r *BankSlipPgRepository) InsertMany(bankSlipsP *entities.BankSlipMap) (map[entities.DebitId]entities.Success, error) {
-	bankSlips := *bankSlipsP
+// lockStatuses locks the rows of the bank slips, in the order they are given
+// so concurrent updates don't deadlock, and returns their current status.
+func lockStatuses(tx *sql.Tx, bankSlips []*entities.BankSlip) (map[entities.DebitId]entities.BankSlipStatus, error) {
 	fields := []any{}
-	queryValues := ""
-	i := 0
-	insertedDebtIds := map[entities.DebitId]entities.Success{}
+	placeholders := []string{}
 	for _, slip := range bankSlips {
+		fields = append(fields, slip.DebtId)
+		placeholders = append(placeholders, fmt.Sprintf("cast($%d AS uuid)", len(fields)))
+	}
+
+	query := fmt.Sprintf("SELECT debt_id, status FROM bank_slip WHERE debt_id IN (%s) ORDER BY debt_id FOR UPDATE", strings.Join(placeholders, ", "))
+	queryResult, err := tx.Query(query, fields...)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	statuses := map[entities.DebitId]entities.BankSlipStatus{}
+	for queryResult.Next() {
+		var debtId string
+		var status entities.BankSlipStatus
+		if err := queryResult.Scan(&debtId, &status); err != nil {
+			return nil, err
+		}
+		statuses[debtId] = status
+	}
+	return statuses, queryResult.Err()
+}
+
+// InsertMany stores the bank slips of each file, and the ones of subscriptions
+// of each beneficiary, in a transaction that also allocates their "nosso
+// número". The row of the beneficiary stays locked until the commit, so
+// workers of every replica number its bank slips one after the other, and a
+// rolled back transaction gives its numbers back. The groups committed before
+// one that fails stay stored, and are reported along with the error.
+func (r *BankSlipPgRepository) InsertMany(bankSlipsP *entities.BankSlipMap) (map[entities.DebitId]entities.Success, error) {
+	insertedDebtIds := map[entities.DebitId]entities.Success{}
+	bankSlipsByGroup := map[insertGroup][]*entities.BankSlip{}
+	for _, slip := range *bankSlipsP {
 		insertedDebtIds[slip.DebtId] = false
-		fields = append(fields, slip.UserName, slip.GovernmentId, slip.UserEmail, slip.DebtAmount, slip.DebtDueDate, slip.DebtId, slip.BankSlipFileMetadataId, slip.Status, slip.ErrorMessage)
-		queryValues += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", i*9+1, i*9+2, i*9+3, i*9+4, i*9+5, i*9+6, i*9+7, i*9+8, i*9+9)
+		group := insertGroup{fileId: slip.BankSlipFileMetadataId}
+		if group.fileId == "" {
+			group.beneficiaryId = slip.BeneficiaryId
+		}
+		bankSlipsByGroup[group] = append(bankSlipsByGroup[group], slip)
+	}
+
+	groups := slices.SortedFunc(maps.Keys(bankSlipsByGroup), func(a, b insertGroup) int {
+		return cmp.Or(strings.Compare(a.fileId, b.fileId), strings.Compare(a.beneficiaryId, b.beneficiaryId))
+	})
+	for _, group := range groups {
+		err := r.insertGroupBankSlips(group, bankSlipsByGroup[group], insertedDebtIds)
+		if err != nil {
+			return insertedDebtIds, err
+		}
+	}
+	return insertedDebtIds, nil
+}
+
+// insertGroup is the file the bank slips came from, or the beneficiary of the
+// ones without a file.
+type insertGroup struct {
+	fileId        string
+	beneficiaryId string
+}
+
+func (r *BankSlipPgRepository) insertGroupBankSlips(group insertGroup, bankSlips []*entities.BankSlip, insertedDebtIds map[entities.DebitId]entities.Success) error {
+	slices.SortFunc(bankSlips, func(a, b *entities.BankSlip) int {
+		return strings.Compare(a.DebtId, b.DebtId)
+	})
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	beneficiaryId, lastOurNumber, err := lockBeneficiary(tx, group)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrBeneficiaryNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	fields := []any{}
+	queryValues := ""
+	for i, slip := range bankSlips {
+		chargePolicy, err := json.Marshal(slip.ChargePolicy)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, slip.UserName, slip.GovernmentId.String(), slip.UserEmail, slip.DebtAmount.Centavos(), slip.DebtDueDate, slip.DebtId, nullIfEmpty(slip.BankSlipFileMetadataId), slip.Status, slip.ErrorMessage, beneficiaryId, chargePolicy, nullIfEmpty(slip.ParentDebtId), slip.InstallmentNumber, slip.InstallmentCount, nullIfEmpty(slip.SubscriptionId))
+		queryValues += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", i*15+1, i*15+2, i*15+3, i*15+4, i*15+5, i*15+6, i*15+7, i*15+8, i*15+9, i*15+10, i*15+11, i*15+12, i*15+13, i*15+14, i*15+15)
 		if i < len(bankSlips)-1 {
 			queryValues += ", "
 		}
-		i++
 	}
 
-	query := fmt.Sprintf("INSERT INTO bank_slip (user_name, government_id, user_email, debt_amount, debt_due_date, debt_id, bank_slip_file_id, status, error_message) VALUES %s ON CONFLICT DO NOTHING RETURNING debt_id", queryValues)
-	queryResult, err := r.db.Query(query, fields...)
+	query := fmt.Sprintf("INSERT INTO bank_slip (user_name, government_id, user_email, debt_amount_cents, debt_due_date, debt_id, bank_slip_file_id, status, error_message, beneficiary_id, charge_policy, parent_debt_id, installment_number, installment_count, subscription_id) VALUES %s ON CONFLICT DO NOTHING RETURNING debt_id", queryValues)
+	queryResult, err := tx.Query(query, fields...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
+	// A bank slip left without a number would open a gap, so the whole group
+	// is rolled back when an inserted row can't be read.
+	inserted := []string{}
 	for queryResult.Next() {
 		var debtId string
 		if err := queryResult.Scan(&debtId); err != nil {
 			log.Printf("Failed to scan row: %v", err)
-			continue
+			queryResult.Close()
+			return err
 		}
-		insertedDebtIds[debtId] = true
+		inserted = append(inserted, debtId)
+	}
+	queryResult.Close()
+	if err := queryResult.Err(); err != nil {
+		return err
+	}
+	if len(inserted) == 0 {
+		return tx.Commit()
 	}
 
-	return insertedDebtIds, nil
+	// Debts already stored by another group keep their number, only the
+	// inserted ones are numbered, in the order of their ids.
+	slices.Sort(inserted)
+	ourNumbers := map[entities.DebitId]int64{}
+	fields = []any{}
+	queryValues = ""
+	for i, debtId := range inserted {
+		ourNumbers[debtId] = lastOurNumber + int64(i) + 1
+		fields = append(fields, debtId, ourNumbers[debtId])
+		queryValues += fmt.Sprintf("(cast($%d AS uuid), cast($%d AS bigint))", i*2+1, i*2+2)
+		if i < len(inserted)-1 {
+			queryValues += ", "
+		}
+	}
+	query = fmt.Sprintf(`
+		UPDATE bank_slip bs
+		SET our_number = tmp.our_number
+		FROM (
+			VALUES
+				%s
+		) AS tmp(debt_id, our_number)
+		WHERE bs.debt_id = tmp.debt_id
+	`, queryValues)
+	if _, err := tx.Exec(query, fields...); err != nil {
+		return err
+	}
+
+	lastOurNumber += int64(len(inserted))
+	if _, err := tx.Exec("UPDATE beneficiary SET last_our_number = $2 WHERE id = $1", beneficiaryId, lastOurNumber); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, slip := range bankSlips {
+		if ourNumber, ok := ourNumbers[slip.DebtId]; ok {
+			slip.OurNumber = ourNumber
+			slip.BeneficiaryId = beneficiaryId
+			insertedDebtIds[slip.DebtId] = true
+		}
+	}
+	return nil
+}
+
+// lockBeneficiary locks the beneficiary of the file, or the one of the group
+// when it has no file, and returns its last "nosso número".
+func lockBeneficiary(tx *sql.Tx, group insertGroup) (string, int64, error) {
+	var beneficiaryId string
+	var lastOurNumber int64
+	if group.fileId == "" {
+		err := tx.QueryRow(
+			"SELECT id, last_our_number FROM beneficiary WHERE id = $1 FOR UPDATE",
+			group.beneficiaryId,
+		).Scan(&beneficiaryId, &lastOurNumber)
+		return beneficiaryId, lastOurNumber, err
+	}
+	err := tx.QueryRow(`
+		SELECT b.id, b.last_our_number FROM beneficiary b
+		JOIN bank_slip_file bsf ON bsf.beneficiary_id = b.id
+		WHERE bsf.id = $1
+		FOR UPDATE OF b
+	`, group.fileId).Scan(&beneficiaryId, &lastOurNumber)
+	return beneficiaryId, lastOurNumber, err
+}
+
+const bankSlipColumns = `
+	debt_id, debt_amount_cents, debt_due_date, government_id, user_name, user_email,
+	bank_slip_file_id, error_message, status, beneficiary_id, our_number, barcode,
+	digitable_line, pix_payload, charge_policy, parent_debt_id, installment_number,
+	installment_count, subscription_id
+`
+
+func (r *BankSlipPgRepository) scanBankSlip(row rowScanner) (*entities.BankSlip, error) {
+	var slip entities.BankSlip
+	var chargePolicy []byte
+	var fileId, parentDebtId, subscriptionId sql.NullString
+	err := row.Scan(
+		&slip.DebtId,
+		&slip.DebtAmount,
+		&slip.DebtDueDate,
+		&slip.GovernmentId,
+		&slip.UserName,
+		&slip.UserEmail,
+		&fileId,
+		&slip.ErrorMessage,
+		&slip.Status,
+		&slip.BeneficiaryId,
+		&slip.OurNumber,
+		&slip.Barcode,
+		&slip.DigitableLine,
+		&slip.PixPayload,
+		&chargePolicy,
+		&parentDebtId,
+		&slip.InstallmentNumber,
+		&slip.InstallmentCount,
+		&subscriptionId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	slip.BankSlipFileMetadataId = fileId.String
+	slip.ParentDebtId = parentDebtId.String
+	slip.SubscriptionId = subscriptionId.String
+	if err := json.Unmarshal(chargePolicy, &slip.ChargePolicy); err != nil {
+		return nil, err
+	}
+	return &slip, nil
+}
+
+func (r *BankSlipPgRepository) GetByDebtId(debtId string) (*entities.BankSlip, error) {
+	query := fmt.Sprintf("SELECT %s FROM bank_slip WHERE debt_id = $1", bankSlipColumns)
+
+	slip, err := r.scanBankSlip(r.db.QueryRow(query, debtId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entities.ErrBankSlipNotFound
+	}
+	return slip, err
+}
+
+func (r *BankSlipPgRepository) ForEachByFileId(fileId string, statuses []entities.BankSlipStatus, fn func(bankSlip *entities.BankSlip) error) error {
+	fields := []any{fileId}
+	placeholders := []string{}
+	for _, status := range statuses {
+		fields = append(fields, status)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(fields)))
+	}
+	query := fmt.Sprintf("SELECT %s FROM bank_slip WHERE bank_slip_file_id = $1 AND status IN (%s) ORDER BY our_number", bankSlipColumns, strings.Join(placeholders, ", "))
+
+	queryResult, err := r.db.Query(query, fields...)
+	if err != nil {
+		return err
+	}
+	defer queryResult.Close()
+
+	for queryResult.Next() {
+		slip, err := r.scanBankSlip(queryResult)
+		if err != nil {
+			return err
+		}
+		if err := fn(slip); err != nil {
+			return err
+		}
+	}
+	return queryResult.Err()
+}
+
+// Reconcile finds the bank slip by our number, or by the debt id in the
+// control number when the our number matches none. Applying a record again
+// changes nothing, so redelivered messages are harmless.
+func (r *BankSlipPgRepository) Reconcile(beneficiaryId string, returnRecord *entities.BankSlipReturnRecord) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	debtId, status, err := r.findReturned(tx, beneficiaryId, returnRecord)
+	if err != nil {
+		return err
+	}
+	if returnRecord.AppliedTo(status) {
+		return nil
+	}
+
+	fields := []any{debtId, returnRecord.Status, nil, nil, nil, nullIfZero(returnRecord.BankFee.Centavos())}
+	if returnRecord.Status == entities.BankSlipStatusPaid || returnRecord.Status == entities.BankSlipStatusSettled {
+		fields[2] = returnRecord.PaidAmount.Centavos()
+		fields[3] = nullIfZeroTime(returnRecord.PaidAt)
+		fields[4] = nullIfZeroTime(returnRecord.CreditedAt)
+	}
+	fromStatuses := []string{}
+	for _, fromStatus := range returnRecord.FromStatuses() {
+		fields = append(fields, fromStatus)
+		fromStatuses = append(fromStatuses, fmt.Sprintf("$%d", len(fields)))
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE bank_slip
+		SET status = $2, paid_amount_cents = $3, paid_at = $4, credited_at = $5, bank_fee_cents = $6
+		WHERE debt_id = $1 AND status IN (%s)
+	`, strings.Join(fromStatuses, ", "))
+	result, err := tx.Exec(query, fields...)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("%w: %s can't become %s", entities.ErrUnexpectedBankSlipStatus, status, returnRecord.Status)
+	}
+
+	err = insertStatusHistory(tx, []*entities.BankSlipStatusChange{
+		entities.NewBankSlipStatusChange(debtId, status, returnRecord.Status, returnRecord.StatusReason()),
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// findReturned locks the bank slip the record is about, so its status can't
+// change before the transaction ends.
+func (r *BankSlipPgRepository) findReturned(tx *sql.Tx, beneficiaryId string, returnRecord *entities.BankSlipReturnRecord) (string, entities.BankSlipStatus, error) {
+	var debtId string
+	var status entities.BankSlipStatus
+	if returnRecord.OurNumber > 0 {
+		err := tx.QueryRow(
+			"SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = $1 AND our_number = $2 FOR UPDATE",
+			beneficiaryId, returnRecord.OurNumber,
+		).Scan(&debtId, &status)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return debtId, status, err
+		}
+	}
+
+	debtIdPrefix := returnRecord.DebtIdPrefix()
+	if debtIdPrefix == "" {
+		return "", "", entities.ErrBankSlipNotFound
+	}
+	err := tx.QueryRow(
+		"SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = $1 AND upper(left(replace(debt_id::text, '-', ''), 25)) = $2 FOR UPDATE",
+		beneficiaryId, debtIdPrefix,
+	).Scan(&debtId, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", entities.ErrBankSlipNotFound
+	}
+	return debtId, status, err
+}
+
+func nullIfZero(value int64) any {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+func nullIfEmpty(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func nullIfZeroTime(value time.Time) any {
+	if value.IsZero() {
+		return nil
+	}
+	return value
 }
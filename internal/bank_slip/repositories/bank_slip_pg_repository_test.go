@@ -35,13 +35,20 @@ func TestBankSlipPgRepository(t *testing.T) {
 	suite.Run(t, new(TestSuitBankSlipPgRepository))
 }
 
+func (s *TestSuitBankSlipPgRepository) expectBeneficiaryLock(fileId, beneficiaryId string, lastOurNumber int64) {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT b.id, b.last_our_number FROM beneficiary b (.+) FOR UPDATE OF b").
+		WithArgs(fileId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_our_number"}).AddRow(beneficiaryId, lastOurNumber))
+}
+
 func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany() {
 	bankSlips := map[bankSlipEntities.DebitId]*bankSlipEntities.BankSlip{
 		"1": {
 			UserName:               "John Doe",
-			GovernmentId:           5321,
+			GovernmentId:           "52998224725",
 			UserEmail:              "johndoe@example.com",
-			DebtAmount:             1000.00,
+			DebtAmount:             100000,
 			DebtDueDate:            time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
 			DebtId:                 "1",
 			BankSlipFileMetadataId: "file_123",
@@ -50,15 +57,26 @@ func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany() {
 		},
 	}
 
+	s.expectBeneficiaryLock("file_123", "beneficiary1", 41)
 	s.mock.ExpectQuery("INSERT INTO bank_slip").
 		WithArgs(
-			"John Doe", 5321, "johndoe@example.com", 1000.00, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "1", "file_123", "pending", nil,
+			"John Doe", "52998224725", "johndoe@example.com", int64(100000), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "1", "file_123", "pending", nil, "beneficiary1", []byte("{}"), nil, 0, 0, nil,
 		).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id"}).AddRow("1"))
+	s.mock.ExpectExec("UPDATE bank_slip bs").
+		WithArgs("1", int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE beneficiary SET last_our_number = \\$2 WHERE id = \\$1").
+		WithArgs("beneficiary1", int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
 
 	data, err := s.repository.InsertMany(&bankSlips)
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), map[entities.DebitId]entities.Success{"1": true}, data)
+	assert.Equal(s.T(), int64(42), bankSlips["1"].OurNumber)
+	assert.Equal(s.T(), "beneficiary1", bankSlips["1"].BeneficiaryId)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
 }
 
 func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_LastWithoutComa() {
@@ -66,9 +84,9 @@ func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_LastW
 	bankSlips := map[entities.DebitId]*entities.BankSlip{
 		"1": &bankSlipEntities.BankSlip{
 			UserName:               "John Doe",
-			GovernmentId:           5421,
+			GovernmentId:           "12345678909",
 			UserEmail:              "john.doe@example.com",
-			DebtAmount:             1000.50,
+			DebtAmount:             100050,
 			DebtDueDate:            time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
 			DebtId:                 "1",
 			BankSlipFileMetadataId: "file1",
@@ -77,23 +95,31 @@ func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_LastW
 		},
 		"2": &bankSlipEntities.BankSlip{
 			UserName:               "Jane Doe",
-			GovernmentId:           7632,
+			GovernmentId:           "11222333000181",
 			UserEmail:              "jane.doe@example.com",
-			DebtAmount:             2000.75,
+			DebtAmount:             200075,
 			DebtDueDate:            time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC),
 			DebtId:                 "2",
-			BankSlipFileMetadataId: "file2",
+			BankSlipFileMetadataId: "file1",
 			Status:                 "paid",
 			ErrorMessage:           &errorMsg,
 		},
 	}
 
 	// Configura a expectativa para a query no mock do banco de dados
+	s.expectBeneficiaryLock("file1", "beneficiary1", 42)
 	s.mock.ExpectQuery("INSERT INTO bank_slip").WithArgs(
-		"John Doe", 5421, "john.doe@example.com", 1000.50, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "1", "file1", "pending", nil,
-		"Jane Doe", 7632, "jane.doe@example.com", 2000.75, time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC), "2", "file2", "paid", &errorMsg,
+		"John Doe", "12345678909", "john.doe@example.com", int64(100050), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "1", "file1", "pending", nil, "beneficiary1", []byte("{}"), nil, 0, 0, nil,
+		"Jane Doe", "11222333000181", "jane.doe@example.com", int64(200075), time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC), "2", "file1", "paid", &errorMsg, "beneficiary1", []byte("{}"), nil, 0, 0, nil,
 	).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id"}).AddRow("2"))
+	s.mock.ExpectExec("UPDATE bank_slip bs").
+		WithArgs("2", int64(43)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE beneficiary").
+		WithArgs("beneficiary1", int64(43)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
 
 	// Chama o método InsertMany
 	data, err := s.repository.InsertMany(&bankSlips)
@@ -106,15 +132,168 @@ func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_LastW
 		"1": false,
 		"2": true,
 	}, data)
+	assert.Equal(s.T(), int64(0), bankSlips["1"].OurNumber)
+	assert.Equal(s.T(), int64(43), bankSlips["2"].OurNumber)
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_ShouldNumberEachFileWithItsBeneficiary() {
+	bankSlips := map[entities.DebitId]*entities.BankSlip{
+		"3": {DebtId: "3", BankSlipFileMetadataId: "file2", GovernmentId: "52998224725", DebtAmount: 100},
+		"2": {DebtId: "2", BankSlipFileMetadataId: "file1", GovernmentId: "52998224725", DebtAmount: 100},
+		"1": {DebtId: "1", BankSlipFileMetadataId: "file1", GovernmentId: "52998224725", DebtAmount: 100},
+	}
+
+	s.expectBeneficiaryLock("file1", "beneficiary1", 10)
+	s.mock.ExpectQuery("INSERT INTO bank_slip").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id"}).AddRow("2").AddRow("1"))
+	s.mock.ExpectExec("UPDATE bank_slip bs").
+		WithArgs("1", int64(11), "2", int64(12)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec("UPDATE beneficiary").
+		WithArgs("beneficiary1", int64(12)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.expectBeneficiaryLock("file2", "beneficiary2", 0)
+	s.mock.ExpectQuery("INSERT INTO bank_slip").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id"}).AddRow("3"))
+	s.mock.ExpectExec("UPDATE bank_slip bs").
+		WithArgs("3", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE beneficiary").
+		WithArgs("beneficiary2", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	data, err := s.repository.InsertMany(&bankSlips)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), map[entities.DebitId]entities.Success{"1": true, "2": true, "3": true}, data)
+	assert.Equal(s.T(), int64(11), bankSlips["1"].OurNumber)
+	assert.Equal(s.T(), int64(12), bankSlips["2"].OurNumber)
+	assert.Equal(s.T(), int64(1), bankSlips["3"].OurNumber)
+	assert.Equal(s.T(), "beneficiary2", bankSlips["3"].BeneficiaryId)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_ShouldReportGroupsStoredBeforeAFailure() {
+	bankSlips := map[entities.DebitId]*entities.BankSlip{
+		"2": {DebtId: "2", BankSlipFileMetadataId: "file2", GovernmentId: "52998224725", DebtAmount: 100},
+		"1": {DebtId: "1", BankSlipFileMetadataId: "file1", GovernmentId: "52998224725", DebtAmount: 100},
+	}
+
+	s.expectBeneficiaryLock("file1", "beneficiary1", 10)
+	s.mock.ExpectQuery("INSERT INTO bank_slip").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id"}).AddRow("1"))
+	s.mock.ExpectExec("UPDATE bank_slip bs").
+		WithArgs("1", int64(11)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE beneficiary").
+		WithArgs("beneficiary1", int64(11)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	s.expectBeneficiaryLock("file2", "beneficiary2", 0)
+	s.mock.ExpectQuery("INSERT INTO bank_slip").
+		WillReturnError(fmt.Errorf("insert error"))
+	s.mock.ExpectRollback()
+
+	data, err := s.repository.InsertMany(&bankSlips)
+	assert.EqualError(s.T(), err, "insert error")
+	assert.Equal(s.T(), map[entities.DebitId]entities.Success{"1": true, "2": false}, data)
+	assert.Equal(s.T(), int64(11), bankSlips["1"].OurNumber)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_ShouldNumberSubscriptionBankSlipsWithTheirBeneficiary() {
+	dueDate := time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC)
+	bankSlips := map[entities.DebitId]*entities.BankSlip{
+		"1": {DebtId: "1", BeneficiaryId: "beneficiary1", SubscriptionId: "subscription1", UserName: "John Doe", GovernmentId: "52998224725", UserEmail: "john.doe@example.com", DebtAmount: 15000, DebtDueDate: dueDate, Status: entities.BankSlipStatusPending},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT id, last_our_number FROM beneficiary WHERE id = \\$1 FOR UPDATE").
+		WithArgs("beneficiary1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_our_number"}).AddRow("beneficiary1", int64(7)))
+	s.mock.ExpectQuery("INSERT INTO bank_slip").
+		WithArgs(
+			"John Doe", "52998224725", "john.doe@example.com", int64(15000), dueDate, "1", nil, entities.BankSlipStatusPending, nil, "beneficiary1", []byte("{}"), nil, 0, 0, "subscription1",
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id"}).AddRow("1"))
+	s.mock.ExpectExec("UPDATE bank_slip bs").
+		WithArgs("1", int64(8)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("UPDATE beneficiary").
+		WithArgs("beneficiary1", int64(8)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	data, err := s.repository.InsertMany(&bankSlips)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), map[entities.DebitId]entities.Success{"1": true}, data)
+	assert.Equal(s.T(), int64(8), bankSlips["1"].OurNumber)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_ShouldNotAllocateNumbersWhenNothingIsInserted() {
+	bankSlips := map[entities.DebitId]*entities.BankSlip{
+		"1": {DebtId: "1", BankSlipFileMetadataId: "file1", GovernmentId: "52998224725", DebtAmount: 100},
+	}
+
+	s.expectBeneficiaryLock("file1", "beneficiary1", 10)
+	s.mock.ExpectQuery("INSERT INTO bank_slip").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id"}))
+	s.mock.ExpectCommit()
+
+	data, err := s.repository.InsertMany(&bankSlips)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), map[entities.DebitId]entities.Success{"1": false}, data)
+	assert.Equal(s.T(), int64(0), bankSlips["1"].OurNumber)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_ShouldFailWhenFileHasNoBeneficiary() {
+	bankSlips := map[entities.DebitId]*entities.BankSlip{
+		"1": {DebtId: "1", BankSlipFileMetadataId: "file1", GovernmentId: "52998224725", DebtAmount: 100},
+	}
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("FOR UPDATE OF b").
+		WithArgs("file1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_our_number"}))
+	s.mock.ExpectRollback()
+
+	data, err := s.repository.InsertMany(&bankSlips)
+	assert.Equal(s.T(), map[entities.DebitId]entities.Success{"1": false}, data)
+	assert.ErrorIs(s.T(), err, bankSlipEntities.ErrBeneficiaryNotFound)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_ShouldRollbackWhenNumberingFails() {
+	bankSlips := map[entities.DebitId]*entities.BankSlip{
+		"1": {DebtId: "1", BankSlipFileMetadataId: "file1", GovernmentId: "52998224725", DebtAmount: 100},
+	}
+
+	s.expectBeneficiaryLock("file1", "beneficiary1", 10)
+	s.mock.ExpectQuery("INSERT INTO bank_slip").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id"}).AddRow("1"))
+	s.mock.ExpectExec("UPDATE bank_slip bs").
+		WillReturnError(fmt.Errorf("update error"))
+	s.mock.ExpectRollback()
+
+	data, err := s.repository.InsertMany(&bankSlips)
+	assert.Equal(s.T(), map[entities.DebitId]entities.Success{"1": false}, data)
+	assert.EqualError(s.T(), err, "update error")
+	assert.Equal(s.T(), int64(0), bankSlips["1"].OurNumber)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
 }
 
 func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_Error() {
 	bankSlips := map[bankSlipEntities.DebitId]*bankSlipEntities.BankSlip{
 		"1": {
 			UserName:               "John Doe",
-			GovernmentId:           5321,
+			GovernmentId:           "52998224725",
 			UserEmail:              "johndoe@example.com",
-			DebtAmount:             1000.00,
+			DebtAmount:             100000,
 			DebtDueDate:            time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
 			DebtId:                 "1",
 			BankSlipFileMetadataId: "file_123",
@@ -123,11 +302,13 @@ func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_Error
 		},
 	}
 
+	s.expectBeneficiaryLock("file_123", "beneficiary1", 0)
 	s.mock.ExpectQuery("INSERT INTO bank_slip").
 		WithArgs(
-			"John Doe", 5321, "johndoe@example.com", 1000.00, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "1", "file_123", "pending", nil,
+			"John Doe", "52998224725", "johndoe@example.com", int64(100000), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "1", "file_123", "pending", nil, "beneficiary1", []byte("{}"), nil, 0, 0, nil,
 		).
 		WillReturnError(fmt.Errorf("insert error"))
+	s.mock.ExpectRollback()
 
 	_, err := s.repository.InsertMany(&bankSlips)
 	assert.Error(s.T(), err)
@@ -142,26 +323,40 @@ func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_UpdateMany() {
 	bankSlips := []*bankSlipEntities.BankSlipMap{
 		{
 			"1": &bankSlipEntities.BankSlip{
-				DebtId:       "1",
-				Status:       "paid",
-				ErrorMessage: nil,
+				DebtId:        "1",
+				Status:        bankSlipEntities.BankSlipStatusSuccess,
+				ErrorMessage:  nil,
+				Barcode:       "23792131200001000501234090000000004200123450",
+				DigitableLine: "23791234059000000000142001234501213120000100050",
+				PixPayload:    "00020126360014br.gov.bcb.pix",
 			},
 		},
 		{
 			"2": &bankSlipEntities.BankSlip{
 				DebtId:       "2",
-				Status:       "failed",
+				Status:       bankSlipEntities.BankSlipStatusGenerateBillingError,
 				ErrorMessage: &errorMessage,
 			},
 		},
 	}
 
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE debt_id IN (.+) FOR UPDATE").
+		WithArgs("1", "2").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("1", "PENDING").AddRow("2", "PENDING"))
 	s.mock.ExpectExec("UPDATE bank_slip").
 		WithArgs(
-			"1", "paid", nil,
-			"2", "failed", "error message",
+			"1", bankSlipEntities.BankSlipStatusSuccess, nil, "23792131200001000501234090000000004200123450", "23791234059000000000142001234501213120000100050", "00020126360014br.gov.bcb.pix",
+			"2", bankSlipEntities.BankSlipStatusGenerateBillingError, "error message", "", "", "",
 		).
 		WillReturnResult(sqlmock.NewResult(1, 2))
+	s.mock.ExpectExec("INSERT INTO bank_slip_status_history").
+		WithArgs(
+			"1", bankSlipEntities.BankSlipStatusPending, bankSlipEntities.BankSlipStatusSuccess, "",
+			"2", bankSlipEntities.BankSlipStatusPending, bankSlipEntities.BankSlipStatusGenerateBillingError, "error message",
+		).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectCommit()
 
 	err := s.repository.UpdateMany(bankSlips...)
 	assert.NoError(s.T(), err)
@@ -175,17 +370,22 @@ func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_UpdateMany_Error
 		{
 			"1": &bankSlipEntities.BankSlip{
 				DebtId:       "1",
-				Status:       "paid",
+				Status:       bankSlipEntities.BankSlipStatusPaid,
 				ErrorMessage: nil,
 			},
 		},
 	}
 
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE debt_id IN (.+) FOR UPDATE").
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("1", "SUCCESS"))
 	s.mock.ExpectExec("UPDATE bank_slip").
 		WithArgs(
-			"1", "paid", nil,
+			"1", bankSlipEntities.BankSlipStatusPaid, nil, "", "", "",
 		).
 		WillReturnError(fmt.Errorf("update error"))
+	s.mock.ExpectRollback()
 
 	err := s.repository.UpdateMany(bankSlips...)
 	assert.Error(s.T(), err)
@@ -195,13 +395,50 @@ func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_UpdateMany_Error
 	assert.NoError(s.T(), err)
 }
 
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_UpdateMany_ShouldNotWriteHistoryWhenStatusIsKept() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE debt_id IN (.+) FOR UPDATE").
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("1", "SUCCESS"))
+	s.mock.ExpectExec("UPDATE bank_slip").WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repository.UpdateMany(&bankSlipEntities.BankSlipMap{
+		"1": &bankSlipEntities.BankSlip{DebtId: "1", Status: bankSlipEntities.BankSlipStatusSuccess},
+	})
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_UpdateMany_ShouldKeepBankSlipChangedMeanwhile() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE debt_id IN (.+) FOR UPDATE").
+		WithArgs("1", "2").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("1", "PENDING").AddRow("2", "PAID"))
+	s.mock.ExpectExec("UPDATE bank_slip").
+		WithArgs("1", bankSlipEntities.BankSlipStatusSuccess, nil, "", "", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO bank_slip_status_history").
+		WithArgs("1", bankSlipEntities.BankSlipStatusPending, bankSlipEntities.BankSlipStatusSuccess, "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repository.UpdateMany(&bankSlipEntities.BankSlipMap{
+		"1": &bankSlipEntities.BankSlip{DebtId: "1", Status: bankSlipEntities.BankSlipStatusSuccess},
+		"2": &bankSlipEntities.BankSlip{DebtId: "2", Status: bankSlipEntities.BankSlipStatusSuccess},
+	})
+	assert.ErrorIs(s.T(), err, bankSlipEntities.ErrUnexpectedBankSlipStatus)
+	assert.Contains(s.T(), err.Error(), "2 is PAID and can't become SUCCESS")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
 func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_ScanError() {
 	bankSlips := map[bankSlipEntities.DebitId]*bankSlipEntities.BankSlip{
 		"1": {
 			UserName:               "John Doe",
-			GovernmentId:           5321,
+			GovernmentId:           "52998224725",
 			UserEmail:              "johndoe@example.com",
-			DebtAmount:             1000.00,
+			DebtAmount:             100000,
 			DebtDueDate:            time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
 			DebtId:                 "1",
 			BankSlipFileMetadataId: "file_123",
@@ -210,18 +447,262 @@ func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_InsertMany_ScanE
 		},
 	}
 
+	s.expectBeneficiaryLock("file_123", "beneficiary1", 0)
 	s.mock.ExpectQuery("INSERT INTO bank_slip").
 		WithArgs(
-			"John Doe", 5321, "johndoe@example.com", 1000.00, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "1", "file_123", "pending", nil,
+			"John Doe", "52998224725", "johndoe@example.com", int64(100000), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "1", "file_123", "pending", nil, "beneficiary1", []byte("{}"), nil, 0, 0, nil,
 		).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(nil))
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id"}).AddRow(nil))
+	s.mock.ExpectRollback()
 
 	logOutput := new(bytes.Buffer)
 	log.SetOutput(logOutput)
 	defer log.SetOutput(nil)
 
 	data, err := s.repository.InsertMany(&bankSlips)
-	assert.NoError(s.T(), err)
-	assert.Equal(s.T(), map[entities.DebitId]entities.Success{"1": false}, data)
+	assert.Error(s.T(), err)
+	assert.Equal(s.T(), map[bankSlipEntities.DebitId]bankSlipEntities.Success{"1": false}, data)
 	assert.Contains(s.T(), logOutput.String(), "Failed to scan row")
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+var bankSlipColumnNames = []string{
+	"debt_id", "debt_amount_cents", "debt_due_date", "government_id", "user_name", "user_email",
+	"bank_slip_file_id", "error_message", "status", "beneficiary_id", "our_number", "barcode",
+	"digitable_line", "pix_payload", "charge_policy", "parent_debt_id", "installment_number",
+	"installment_count", "subscription_id",
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_GetByDebtId() {
+	dueDate := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	s.mock.ExpectQuery("SELECT (.+) FROM bank_slip WHERE debt_id = \\$1").
+		WithArgs("debt1").
+		WillReturnRows(sqlmock.NewRows(bankSlipColumnNames).AddRow(
+			"debt1", int64(100050), dueDate, "52998224725", "John Doe", "john.doe@example.com",
+			"file1", nil, "SUCCESS", "beneficiary1", int64(42), "23792131200001000501234090000000004200123450", "23791234059000000000142001234501213120000100050",
+			"00020126360014br.gov.bcb.pix", `{"lateFee":"2.00%","interest":"0.50"}`, "parent1", 2, 3, nil,
+		))
+
+	bankSlip, err := s.repository.GetByDebtId("debt1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &bankSlipEntities.BankSlip{
+		DebtId:                 "debt1",
+		DebtAmount:             100050,
+		DebtDueDate:            dueDate,
+		GovernmentId:           "52998224725",
+		UserName:               "John Doe",
+		UserEmail:              "john.doe@example.com",
+		BankSlipFileMetadataId: "file1",
+		Status:                 bankSlipEntities.BankSlipStatusSuccess,
+		BeneficiaryId:          "beneficiary1",
+		OurNumber:              42,
+		Barcode:                "23792131200001000501234090000000004200123450",
+		DigitableLine:          "23791234059000000000142001234501213120000100050",
+		PixPayload:             "00020126360014br.gov.bcb.pix",
+		ChargePolicy: bankSlipEntities.ChargePolicy{
+			LateFee:  &bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindPercent, Value: 200},
+			Interest: &bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindAmount, Value: 50},
+		},
+		ParentDebtId:      "parent1",
+		InstallmentNumber: 2,
+		InstallmentCount:  3,
+	}, bankSlip)
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_GetByDebtId_NotFound() {
+	s.mock.ExpectQuery("SELECT (.+) FROM bank_slip WHERE debt_id = \\$1").
+		WithArgs("debt1").
+		WillReturnRows(sqlmock.NewRows(bankSlipColumnNames))
+
+	bankSlip, err := s.repository.GetByDebtId("debt1")
+	assert.Nil(s.T(), bankSlip)
+	assert.ErrorIs(s.T(), err, bankSlipEntities.ErrBankSlipNotFound)
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_ForEachByFileId() {
+	dueDate := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	s.mock.ExpectQuery("SELECT (.+) FROM bank_slip WHERE bank_slip_file_id = \\$1 AND status IN \\(\\$2, \\$3\\) ORDER BY our_number").
+		WithArgs("file1", bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusOverdue).
+		WillReturnRows(sqlmock.NewRows(bankSlipColumnNames).
+			AddRow("debt1", int64(100050), dueDate, "52998224725", "John Doe", "john.doe@example.com", "file1", nil, "SUCCESS", "beneficiary1", int64(42), "barcode1", "line1", "", "{}", nil, 0, 0, nil).
+			AddRow("debt2", int64(200075), dueDate, "11222333000181", "Jane Doe", "jane.doe@example.com", "file1", nil, "SUCCESS", "beneficiary1", int64(43), "barcode2", "line2", "", "{}", nil, 0, 0, nil))
+
+	debtIds := []string{}
+	err := s.repository.ForEachByFileId("file1", []bankSlipEntities.BankSlipStatus{bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusOverdue}, func(bankSlip *bankSlipEntities.BankSlip) error {
+		debtIds = append(debtIds, bankSlip.DebtId)
+		return nil
+	})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"debt1", "debt2"}, debtIds)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_ForEachByFileId_ShouldStopOnCallbackError() {
+	dueDate := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	s.mock.ExpectQuery("SELECT (.+) FROM bank_slip WHERE bank_slip_file_id").
+		WithArgs("file1", bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusOverdue).
+		WillReturnRows(sqlmock.NewRows(bankSlipColumnNames).
+			AddRow("debt1", int64(100050), dueDate, "52998224725", "John Doe", "john.doe@example.com", "file1", nil, "SUCCESS", "beneficiary1", int64(42), "barcode1", "line1", "", "{}", nil, 0, 0, nil).
+			AddRow("debt2", int64(200075), dueDate, "11222333000181", "Jane Doe", "jane.doe@example.com", "file1", nil, "SUCCESS", "beneficiary1", int64(43), "barcode2", "line2", "", "{}", nil, 0, 0, nil))
+
+	calls := 0
+	err := s.repository.ForEachByFileId("file1", []bankSlipEntities.BankSlipStatus{bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusOverdue}, func(bankSlip *bankSlipEntities.BankSlip) error {
+		calls++
+		return assert.AnError
+	})
+	assert.ErrorIs(s.T(), err, assert.AnError)
+	assert.Equal(s.T(), 1, calls)
+}
+
+func newPaidReturnRecord() *bankSlipEntities.BankSlipReturnRecord {
+	return &bankSlipEntities.BankSlipReturnRecord{
+		ReturnFileId:   "return1",
+		OccurrenceCode: "06",
+		OurNumber:      42,
+		ControlNumber:  "EA23F2CA663A4266A7429DA4C",
+		Status:         bankSlipEntities.BankSlipStatusPaid,
+		PaidAmount:     100050,
+		PaidAt:         time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		CreditedAt:     time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC),
+		BankFee:        250,
+	}
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_Reconcile_ShouldPayBankSlipFoundByOurNumber() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = \\$1 AND our_number = \\$2 FOR UPDATE").
+		WithArgs("beneficiary1", int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("debt1", "SUCCESS"))
+	s.mock.ExpectExec("UPDATE bank_slip SET status = \\$2, (.+) WHERE debt_id = \\$1 AND status IN \\(\\$7, \\$8, \\$9, \\$10\\)").
+		WithArgs("debt1", bankSlipEntities.BankSlipStatusPaid, int64(100050), time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), int64(250),
+			bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusSendingEmailError, bankSlipEntities.BankSlipStatusOverdue, bankSlipEntities.BankSlipStatusExpired).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO bank_slip_status_history").
+		WithArgs("debt1", bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusPaid, "occurrence 06 of return file return1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repository.Reconcile("beneficiary1", newPaidReturnRecord())
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_Reconcile_ShouldSettlePaidBankSlipWithThePayment() {
+	returnRecord := newPaidReturnRecord()
+	returnRecord.Status = bankSlipEntities.BankSlipStatusSettled
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = \\$1 AND our_number = \\$2 FOR UPDATE").
+		WithArgs("beneficiary1", int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("debt1", "PAID"))
+	s.mock.ExpectExec("UPDATE bank_slip SET status = \\$2, (.+) WHERE debt_id = \\$1 AND status IN \\(\\$7, \\$8, \\$9, \\$10, \\$11\\)").
+		WithArgs("debt1", bankSlipEntities.BankSlipStatusSettled, int64(100050), time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), int64(250),
+			bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusSendingEmailError, bankSlipEntities.BankSlipStatusPaid, bankSlipEntities.BankSlipStatusOverdue, bankSlipEntities.BankSlipStatusExpired).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO bank_slip_status_history").
+		WithArgs("debt1", bankSlipEntities.BankSlipStatusPaid, bankSlipEntities.BankSlipStatusSettled, "occurrence 06 of return file return1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repository.Reconcile("beneficiary1", returnRecord)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_Reconcile_ShouldIgnorePaymentOfSettledBankSlip() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = \\$1 AND our_number = \\$2 FOR UPDATE").
+		WithArgs("beneficiary1", int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("debt1", "SETTLED"))
+	s.mock.ExpectRollback()
+
+	err := s.repository.Reconcile("beneficiary1", newPaidReturnRecord())
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_Reconcile_ShouldCancelWrittenOffBankSlip() {
+	returnRecord := newPaidReturnRecord()
+	returnRecord.Status = bankSlipEntities.BankSlipStatusCancelled
+	returnRecord.OccurrenceCode = "09"
+	returnRecord.BankFee = 0
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = \\$1 AND our_number = \\$2 FOR UPDATE").
+		WithArgs("beneficiary1", int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("debt1", "OVERDUE"))
+	s.mock.ExpectExec("UPDATE bank_slip (.+) WHERE debt_id = \\$1 AND status IN \\(\\$7, \\$8, \\$9, \\$10, \\$11\\)").
+		WithArgs("debt1", bankSlipEntities.BankSlipStatusCancelled, nil, nil, nil, nil,
+			bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusSendingEmailError, bankSlipEntities.BankSlipStatusRejectedByBank, bankSlipEntities.BankSlipStatusOverdue, bankSlipEntities.BankSlipStatusExpired).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO bank_slip_status_history").
+		WithArgs("debt1", bankSlipEntities.BankSlipStatusOverdue, bankSlipEntities.BankSlipStatusCancelled, "written off by the bank, occurrence 09 of return file return1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repository.Reconcile("beneficiary1", returnRecord)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_Reconcile_ShouldFallBackToTheDebtId() {
+	returnRecord := newPaidReturnRecord()
+	returnRecord.Status = bankSlipEntities.BankSlipStatusRejectedByBank
+	returnRecord.OccurrenceCode = "03"
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = \\$1 AND our_number = \\$2 FOR UPDATE").
+		WithArgs("beneficiary1", int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}))
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = \\$1 AND upper\\(left\\(replace\\(debt_id::text, '-', ''\\), 25\\)\\) = \\$2").
+		WithArgs("beneficiary1", "EA23F2CA663A4266A7429DA4C").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("ea23f2ca-663a-4266-a742-9da4c0000000", "SUCCESS"))
+	s.mock.ExpectExec("UPDATE bank_slip (.+) WHERE debt_id = \\$1 AND status IN \\(\\$7, \\$8\\)").
+		WithArgs("ea23f2ca-663a-4266-a742-9da4c0000000", bankSlipEntities.BankSlipStatusRejectedByBank, nil, nil, nil, int64(250), bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusSendingEmailError).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectExec("INSERT INTO bank_slip_status_history").
+		WithArgs("ea23f2ca-663a-4266-a742-9da4c0000000", bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusRejectedByBank, "occurrence 03 of return file return1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	err := s.repository.Reconcile("beneficiary1", returnRecord)
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_Reconcile_ShouldReturnNotFound() {
+	returnRecord := newPaidReturnRecord()
+	returnRecord.ControlNumber = ""
+
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = \\$1 AND our_number = \\$2").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}))
+	s.mock.ExpectRollback()
+
+	err := s.repository.Reconcile("beneficiary1", returnRecord)
+	assert.ErrorIs(s.T(), err, bankSlipEntities.ErrBankSlipNotFound)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_Reconcile_ShouldIgnoreRecordAlreadyApplied() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = \\$1 AND our_number = \\$2").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("debt1", "PAID"))
+	s.mock.ExpectRollback()
+
+	err := s.repository.Reconcile("beneficiary1", newPaidReturnRecord())
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *TestSuitBankSlipPgRepository) TestBankSlipPgRepository_Reconcile_ShouldRejectUnexpectedStatus() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery("SELECT debt_id, status FROM bank_slip WHERE beneficiary_id = \\$1 AND our_number = \\$2").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "status"}).AddRow("debt1", "REJECTED_BY_BANK"))
+	s.mock.ExpectExec("UPDATE bank_slip").WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectRollback()
+
+	err := s.repository.Reconcile("beneficiary1", newPaidReturnRecord())
+	assert.ErrorIs(s.T(), err, bankSlipEntities.ErrUnexpectedBankSlipStatus)
+	assert.NoError(s.T(), s.mock.ExpectationsWereMet())
 }
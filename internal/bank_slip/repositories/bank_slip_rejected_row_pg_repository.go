@@ -0,0 +1,67 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"fmt"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type BankSlipRejectedRowPgRepository struct {
+	db *sql.DB
+}
+
+func NewBankSlipRejectedRowPgRepository(db *sql.DB) *BankSlipRejectedRowPgRepository {
+	return &BankSlipRejectedRowPgRepository{db: db}
+}
+
+func (r *BankSlipRejectedRowPgRepository) InsertMany(rejectedRows []*entities.BankSlipRejectedRow) error {
+	if len(rejectedRows) == 0 {
+		return nil
+	}
+
+	fields := []any{}
+	queryValues := ""
+	for i, rejectedRow := range rejectedRows {
+		fields = append(fields, rejectedRow.BankSlipFileMetadataId, rejectedRow.LineNumber, rejectedRow.RawRow, rejectedRow.ErrorCode)
+		queryValues += fmt.Sprintf("($%d, $%d, $%d, $%d)", i*4+1, i*4+2, i*4+3, i*4+4)
+		if i < len(rejectedRows)-1 {
+			queryValues += ", "
+		}
+	}
+
+	query := fmt.Sprintf("INSERT INTO bank_slip_rejected_row (bank_slip_file_id, line_number, raw_row, error_code) VALUES %s", queryValues)
+	_, err := r.db.Exec(query, fields...)
+	return err
+}
+
+func (r *BankSlipRejectedRowPgRepository) ListByFileId(fileId string) ([]*entities.BankSlipRejectedRow, error) {
+	query := `
+		SELECT bank_slip_file_id, line_number, raw_row, error_code
+		FROM bank_slip_rejected_row
+		WHERE bank_slip_file_id = $1
+		ORDER BY line_number
+	`
+
+	queryResult, err := r.db.Query(query, fileId)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	rejectedRows := []*entities.BankSlipRejectedRow{}
+	for queryResult.Next() {
+		var rejectedRow entities.BankSlipRejectedRow
+		err := queryResult.Scan(
+			&rejectedRow.BankSlipFileMetadataId,
+			&rejectedRow.LineNumber,
+			&rejectedRow.RawRow,
+			&rejectedRow.ErrorCode,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rejectedRows = append(rejectedRows, &rejectedRow)
+	}
+	return rejectedRows, queryResult.Err()
+}
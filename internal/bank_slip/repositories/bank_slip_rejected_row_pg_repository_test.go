@@ -0,0 +1,72 @@
+package bank_slip
+
+import (
+	"database/sql"
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BankSlipRejectedRowPgRepositoryTestSuite struct {
+	suite.Suite
+	repository *BankSlipRejectedRowPgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *BankSlipRejectedRowPgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewBankSlipRejectedRowPgRepository(db)
+}
+
+func TestBankSlipRejectedRowPgRepository(t *testing.T) {
+	suite.Run(t, new(BankSlipRejectedRowPgRepositoryTestSuite))
+}
+
+func (suite *BankSlipRejectedRowPgRepositoryTestSuite) TestInsertMany() {
+	rejectedRows := []*bankSlipEntities.BankSlipRejectedRow{
+		bankSlipEntities.NewBankSlipRejectedRow("file_id", 2, "John,abc", bankSlipEntities.RejectionCodeInvalidGovernmentId),
+		bankSlipEntities.NewBankSlipRejectedRow("file_id", 5, "Mary,123", bankSlipEntities.RejectionCodeDuplicateDebtId),
+	}
+
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO bank_slip_rejected_row (bank_slip_file_id, line_number, raw_row, error_code) VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)")).
+		WithArgs(
+			"file_id", 2, "John,abc", bankSlipEntities.RejectionCodeInvalidGovernmentId,
+			"file_id", 5, "Mary,123", bankSlipEntities.RejectionCodeDuplicateDebtId,
+		).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := suite.repository.InsertMany(rejectedRows)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BankSlipRejectedRowPgRepositoryTestSuite) TestInsertManyWithoutRows() {
+	err := suite.repository.InsertMany([]*bankSlipEntities.BankSlipRejectedRow{})
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BankSlipRejectedRowPgRepositoryTestSuite) TestListByFileId() {
+	columns := []string{"bank_slip_file_id", "line_number", "raw_row", "error_code"}
+
+	suite.mock.ExpectQuery("SELECT bank_slip_file_id, line_number, raw_row, error_code").
+		WithArgs("file_id").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("file_id", 2, "John,abc", "INVALID_GOVERNMENT_ID").
+			AddRow("file_id", 5, "Mary,123", "DUPLICATE_DEBT_ID"))
+
+	rejectedRows, err := suite.repository.ListByFileId("file_id")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []*bankSlipEntities.BankSlipRejectedRow{
+		bankSlipEntities.NewBankSlipRejectedRow("file_id", 2, "John,abc", bankSlipEntities.RejectionCodeInvalidGovernmentId),
+		bankSlipEntities.NewBankSlipRejectedRow("file_id", 5, "Mary,123", bankSlipEntities.RejectionCodeDuplicateDebtId),
+	}, rejectedRows)
+}
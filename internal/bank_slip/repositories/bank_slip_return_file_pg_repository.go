@@ -0,0 +1,92 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"errors"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type BankSlipReturnFilePgRepository struct {
+	db *sql.DB
+}
+
+func NewBankSlipReturnFilePgRepository(db *sql.DB) *BankSlipReturnFilePgRepository {
+	return &BankSlipReturnFilePgRepository{db: db}
+}
+
+func (r *BankSlipReturnFilePgRepository) Insert(returnFile *entities.BankSlipReturnFile) error {
+	query := "INSERT INTO bank_slip_return_file (name, beneficiary_id) VALUES ($1, $2) RETURNING id, created_at"
+
+	return r.db.QueryRow(query, returnFile.FileName, returnFile.BeneficiaryId).Scan(&returnFile.ID, &returnFile.CreatedAt)
+}
+
+func (r *BankSlipReturnFilePgRepository) UpdateStatus(returnFile *entities.BankSlipReturnFile) error {
+	query := "UPDATE bank_slip_return_file SET status = $2, total_records = $3, updated_at = NOW() WHERE id = $1"
+
+	_, err := r.db.Exec(query, returnFile.ID, returnFile.Status, returnFile.TotalRecords)
+	return err
+}
+
+func (r *BankSlipReturnFilePgRepository) UpdateHeader(returnFile *entities.BankSlipReturnFile) error {
+	query := "UPDATE bank_slip_return_file SET format = $2, bank_code = $3, updated_at = NOW() WHERE id = $1"
+
+	_, err := r.db.Exec(query, returnFile.ID, returnFile.Format, returnFile.BankCode)
+	return err
+}
+
+func (r *BankSlipReturnFilePgRepository) AddProcessedRecords(returnFileId string, processed, reconciled, unmatched int) error {
+	query := `
+		UPDATE bank_slip_return_file
+		SET processed_records = processed_records + $2,
+			reconciled_records = reconciled_records + $3,
+			unmatched_records = unmatched_records + $4,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(query, returnFileId, processed, reconciled, unmatched)
+	return err
+}
+
+func (r *BankSlipReturnFilePgRepository) CompleteWhenAllRecordsProcessed(returnFileId string) error {
+	query := `
+		UPDATE bank_slip_return_file
+		SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3 AND processed_records >= total_records
+	`
+
+	_, err := r.db.Exec(query, returnFileId, entities.BankSlipFileStatusCompleted, entities.BankSlipFileStatusProcessing)
+	return err
+}
+
+func (r *BankSlipReturnFilePgRepository) GetById(returnFileId string) (*entities.BankSlipReturnFile, error) {
+	query := `
+		SELECT id, name, beneficiary_id, format, bank_code, status, total_records, processed_records,
+			reconciled_records, unmatched_records, created_at
+		FROM bank_slip_return_file
+		WHERE id = $1
+	`
+
+	var returnFile entities.BankSlipReturnFile
+	err := r.db.QueryRow(query, returnFileId).Scan(
+		&returnFile.ID,
+		&returnFile.FileName,
+		&returnFile.BeneficiaryId,
+		&returnFile.Format,
+		&returnFile.BankCode,
+		&returnFile.Status,
+		&returnFile.TotalRecords,
+		&returnFile.ProcessedRecords,
+		&returnFile.ReconciledRecords,
+		&returnFile.UnmatchedRecords,
+		&returnFile.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entities.ErrBankSlipReturnFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &returnFile, nil
+}
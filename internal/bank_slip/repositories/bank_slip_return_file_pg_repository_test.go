@@ -0,0 +1,95 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/cnab"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BankSlipReturnFilePgRepositoryTestSuite struct {
+	suite.Suite
+	repository *BankSlipReturnFilePgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *BankSlipReturnFilePgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewBankSlipReturnFilePgRepository(db)
+}
+
+func TestBankSlipReturnFilePgRepository(t *testing.T) {
+	suite.Run(t, new(BankSlipReturnFilePgRepositoryTestSuite))
+}
+
+func (suite *BankSlipReturnFilePgRepositoryTestSuite) TestInsert() {
+	createdAt := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	suite.mock.ExpectQuery("INSERT INTO bank_slip_return_file \\(name, beneficiary_id\\) VALUES \\(\\$1, \\$2\\) RETURNING id, created_at").
+		WithArgs("retorno.ret", "beneficiary1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("return1", createdAt))
+
+	returnFile := bankSlipEntities.NewBankSlipReturnFile("retorno.ret", "beneficiary1")
+	err := suite.repository.Insert(returnFile)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "return1", returnFile.ID)
+	assert.Equal(suite.T(), createdAt, returnFile.CreatedAt)
+}
+
+func (suite *BankSlipReturnFilePgRepositoryTestSuite) TestAddProcessedRecordsAndComplete() {
+	suite.mock.ExpectExec("UPDATE bank_slip_return_file SET processed_records = processed_records \\+ \\$2, (.+) WHERE id = \\$1").
+		WithArgs("return1", 10, 7, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("UPDATE bank_slip_return_file SET status = \\$2, (.+) WHERE id = \\$1 AND status = \\$3 AND processed_records >= total_records").
+		WithArgs("return1", bankSlipEntities.BankSlipFileStatusCompleted, bankSlipEntities.BankSlipFileStatusProcessing).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(suite.T(), suite.repository.AddProcessedRecords("return1", 10, 7, 2))
+	assert.NoError(suite.T(), suite.repository.CompleteWhenAllRecordsProcessed("return1"))
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BankSlipReturnFilePgRepositoryTestSuite) TestGetById() {
+	createdAt := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	suite.mock.ExpectQuery("SELECT (.+) FROM bank_slip_return_file WHERE id = \\$1").
+		WithArgs("return1").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "beneficiary_id", "format", "bank_code", "status", "total_records", "processed_records",
+			"reconciled_records", "unmatched_records", "created_at",
+		}).AddRow("return1", "retorno.ret", "beneficiary1", "CNAB400", "237", "COMPLETED", 10, 10, 7, 2, createdAt))
+
+	returnFile, err := suite.repository.GetById("return1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &bankSlipEntities.BankSlipReturnFile{
+		ID:                "return1",
+		FileName:          "retorno.ret",
+		BeneficiaryId:     "beneficiary1",
+		Format:            cnab.CNAB400,
+		BankCode:          "237",
+		Status:            bankSlipEntities.BankSlipFileStatusCompleted,
+		TotalRecords:      10,
+		ProcessedRecords:  10,
+		ReconciledRecords: 7,
+		UnmatchedRecords:  2,
+		CreatedAt:         createdAt,
+	}, returnFile)
+}
+
+func (suite *BankSlipReturnFilePgRepositoryTestSuite) TestGetById_NotFound() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM bank_slip_return_file WHERE id = \\$1").
+		WithArgs("return1").
+		WillReturnError(sql.ErrNoRows)
+
+	returnFile, err := suite.repository.GetById("return1")
+	assert.Nil(suite.T(), returnFile)
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrBankSlipReturnFileNotFound)
+}
@@ -0,0 +1,72 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"fmt"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type BankSlipStatusHistoryPgRepository struct {
+	db *sql.DB
+}
+
+func NewBankSlipStatusHistoryPgRepository(db *sql.DB) *BankSlipStatusHistoryPgRepository {
+	return &BankSlipStatusHistoryPgRepository{db: db}
+}
+
+// ListByDebtId returns the status changes of the bank slip in the order they
+// were written.
+func (r *BankSlipStatusHistoryPgRepository) ListByDebtId(debtId string) ([]*entities.BankSlipStatusChange, error) {
+	query := `
+		SELECT debt_id, from_status, to_status, reason, changed_at
+		FROM bank_slip_status_history
+		WHERE debt_id = $1
+		ORDER BY changed_at, position
+	`
+
+	queryResult, err := r.db.Query(query, debtId)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	statusChanges := []*entities.BankSlipStatusChange{}
+	for queryResult.Next() {
+		var statusChange entities.BankSlipStatusChange
+		err := queryResult.Scan(
+			&statusChange.DebtId,
+			&statusChange.FromStatus,
+			&statusChange.ToStatus,
+			&statusChange.Reason,
+			&statusChange.ChangedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		statusChanges = append(statusChanges, &statusChange)
+	}
+	return statusChanges, queryResult.Err()
+}
+
+// insertStatusHistory writes the status changes in the transaction that
+// changed the statuses, so the history never misses nor invents a change.
+func insertStatusHistory(tx *sql.Tx, statusChanges []*entities.BankSlipStatusChange) error {
+	if len(statusChanges) == 0 {
+		return nil
+	}
+
+	fields := []any{}
+	queryValues := ""
+	for i, statusChange := range statusChanges {
+		fields = append(fields, statusChange.DebtId, statusChange.FromStatus, statusChange.ToStatus, statusChange.Reason)
+		queryValues += fmt.Sprintf("($%d, $%d, $%d, $%d)", i*4+1, i*4+2, i*4+3, i*4+4)
+		if i < len(statusChanges)-1 {
+			queryValues += ", "
+		}
+	}
+
+	query := fmt.Sprintf("INSERT INTO bank_slip_status_history (debt_id, from_status, to_status, reason) VALUES %s", queryValues)
+	_, err := tx.Exec(query, fields...)
+	return err
+}
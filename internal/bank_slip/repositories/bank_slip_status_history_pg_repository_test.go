@@ -0,0 +1,55 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BankSlipStatusHistoryPgRepositoryTestSuite struct {
+	suite.Suite
+	repository *BankSlipStatusHistoryPgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *BankSlipStatusHistoryPgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewBankSlipStatusHistoryPgRepository(db)
+}
+
+func TestBankSlipStatusHistoryPgRepository(t *testing.T) {
+	suite.Run(t, new(BankSlipStatusHistoryPgRepositoryTestSuite))
+}
+
+func (suite *BankSlipStatusHistoryPgRepositoryTestSuite) TestListByDebtId() {
+	changedAt := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	suite.mock.ExpectQuery("SELECT (.+) FROM bank_slip_status_history WHERE debt_id = \\$1 ORDER BY changed_at, position").
+		WithArgs("debt1").
+		WillReturnRows(sqlmock.NewRows([]string{"debt_id", "from_status", "to_status", "reason", "changed_at"}).
+			AddRow("debt1", "PENDING", "SUCCESS", "", changedAt).
+			AddRow("debt1", "SUCCESS", "PAID", "occurrence 06 of return file return1", changedAt))
+
+	history, err := suite.repository.ListByDebtId("debt1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []*bankSlipEntities.BankSlipStatusChange{
+		{DebtId: "debt1", FromStatus: bankSlipEntities.BankSlipStatusPending, ToStatus: bankSlipEntities.BankSlipStatusSuccess, ChangedAt: changedAt},
+		{DebtId: "debt1", FromStatus: bankSlipEntities.BankSlipStatusSuccess, ToStatus: bankSlipEntities.BankSlipStatusPaid, Reason: "occurrence 06 of return file return1", ChangedAt: changedAt},
+	}, history)
+}
+
+func (suite *BankSlipStatusHistoryPgRepositoryTestSuite) TestListByDebtId_ShouldReturnQueryError() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM bank_slip_status_history").WillReturnError(assert.AnError)
+
+	_, err := suite.repository.ListByDebtId("debt1")
+	assert.ErrorIs(suite.T(), err, assert.AnError)
+}
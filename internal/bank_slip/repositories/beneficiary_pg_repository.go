@@ -0,0 +1,103 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type BeneficiaryPgRepository struct {
+	db *sql.DB
+}
+
+func NewBeneficiaryPgRepository(db *sql.DB) *BeneficiaryPgRepository {
+	return &BeneficiaryPgRepository{db: db}
+}
+
+const beneficiaryColumns = "id, name, document, bank_code, agency, account, wallet, agreement_code, charge_policy, created_at"
+
+func (r *BeneficiaryPgRepository) Insert(beneficiary *entities.Beneficiary) error {
+	query := `
+		INSERT INTO beneficiary (name, document, bank_code, agency, account, wallet, agreement_code, charge_policy) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (bank_code, agency, account, wallet) DO NOTHING
+		returning id, created_at
+	`
+
+	chargePolicy, err := json.Marshal(beneficiary.ChargePolicy)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRow(
+		query,
+		beneficiary.Name,
+		beneficiary.Document.String(),
+		beneficiary.BankCode,
+		beneficiary.Agency,
+		beneficiary.Account,
+		beneficiary.Wallet,
+		beneficiary.AgreementCode,
+		chargePolicy,
+	).Scan(&beneficiary.ID, &beneficiary.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrBeneficiaryAlreadyExists
+	}
+	return err
+}
+
+func (r *BeneficiaryPgRepository) GetById(id string) (*entities.Beneficiary, error) {
+	query := fmt.Sprintf("SELECT %s FROM beneficiary WHERE id = $1", beneficiaryColumns)
+
+	beneficiary, err := r.scanBeneficiary(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entities.ErrBeneficiaryNotFound
+	}
+	return beneficiary, err
+}
+
+func (r *BeneficiaryPgRepository) List() ([]*entities.Beneficiary, error) {
+	query := fmt.Sprintf("SELECT %s FROM beneficiary ORDER BY name, id", beneficiaryColumns)
+
+	queryResult, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	beneficiaries := []*entities.Beneficiary{}
+	for queryResult.Next() {
+		beneficiary, err := r.scanBeneficiary(queryResult)
+		if err != nil {
+			return nil, err
+		}
+		beneficiaries = append(beneficiaries, beneficiary)
+	}
+	return beneficiaries, queryResult.Err()
+}
+
+func (r *BeneficiaryPgRepository) scanBeneficiary(row rowScanner) (*entities.Beneficiary, error) {
+	var beneficiary entities.Beneficiary
+	var chargePolicy []byte
+	err := row.Scan(
+		&beneficiary.ID,
+		&beneficiary.Name,
+		&beneficiary.Document,
+		&beneficiary.BankCode,
+		&beneficiary.Agency,
+		&beneficiary.Account,
+		&beneficiary.Wallet,
+		&beneficiary.AgreementCode,
+		&chargePolicy,
+		&beneficiary.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(chargePolicy, &beneficiary.ChargePolicy); err != nil {
+		return nil, err
+	}
+	return &beneficiary, nil
+}
@@ -0,0 +1,114 @@
+package bank_slip
+
+import (
+	"database/sql"
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BeneficiaryPgRepositoryTestSuite struct {
+	suite.Suite
+	repository *BeneficiaryPgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *BeneficiaryPgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewBeneficiaryPgRepository(db)
+}
+
+func TestBeneficiaryPgRepository(t *testing.T) {
+	suite.Run(t, new(BeneficiaryPgRepositoryTestSuite))
+}
+
+var beneficiaryColumnNames = []string{"id", "name", "document", "bank_code", "agency", "account", "wallet", "agreement_code", "charge_policy", "created_at"}
+
+func (suite *BeneficiaryPgRepositoryTestSuite) TestInsert() {
+	beneficiary, err := bankSlipEntities.NewBeneficiary("Performatic", "11222333000181", "237", "1234", "12345", "09", "123")
+	assert.NoError(suite.T(), err)
+	beneficiary.ChargePolicy = bankSlipEntities.ChargePolicy{LateFee: &bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindPercent, Value: 200}}
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO beneficiary (name, document, bank_code, agency, account, wallet, agreement_code, charge_policy) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)")).
+		WithArgs("Performatic", "11222333000181", "237", "1234", "0012345", "09", "123", []byte(`{"lateFee":"2.00%"}`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("beneficiary_id", createdAt))
+
+	err = suite.repository.Insert(beneficiary)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "beneficiary_id", beneficiary.ID)
+	assert.Equal(suite.T(), createdAt, beneficiary.CreatedAt)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *BeneficiaryPgRepositoryTestSuite) TestInsertShouldReturnAlreadyExistsOnConflict() {
+	beneficiary, err := bankSlipEntities.NewBeneficiary("Performatic", "11222333000181", "237", "1234", "12345", "09", "")
+	assert.NoError(suite.T(), err)
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO beneficiary")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+
+	err = suite.repository.Insert(beneficiary)
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrBeneficiaryAlreadyExists)
+}
+
+func (suite *BeneficiaryPgRepositoryTestSuite) TestGetById() {
+	discountDays := 5
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM beneficiary WHERE id = $1")).
+		WithArgs("beneficiary_id").
+		WillReturnRows(sqlmock.NewRows(beneficiaryColumnNames).
+			AddRow("beneficiary_id", "Performatic", "11222333000181", "237", "1234", "0012345", "09", "123", `{"interest":"1.00%","discount":"10.00","discountDays":5}`, createdAt))
+
+	beneficiary, err := suite.repository.GetById("beneficiary_id")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &bankSlipEntities.Beneficiary{
+		ID:            "beneficiary_id",
+		Name:          "Performatic",
+		Document:      "11222333000181",
+		BankCode:      "237",
+		Agency:        "1234",
+		Account:       "0012345",
+		Wallet:        "09",
+		AgreementCode: "123",
+		ChargePolicy: bankSlipEntities.ChargePolicy{
+			Interest:     &bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindPercent, Value: 100},
+			Discount:     &bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindAmount, Value: 1000},
+			DiscountDays: &discountDays,
+		},
+		CreatedAt: createdAt,
+	}, beneficiary)
+}
+
+func (suite *BeneficiaryPgRepositoryTestSuite) TestGetByIdShouldReturnNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM beneficiary WHERE id = $1")).
+		WithArgs("unknown").
+		WillReturnRows(sqlmock.NewRows(beneficiaryColumnNames))
+
+	beneficiary, err := suite.repository.GetById("unknown")
+	assert.Nil(suite.T(), beneficiary)
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrBeneficiaryNotFound)
+}
+
+func (suite *BeneficiaryPgRepositoryTestSuite) TestList() {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM beneficiary ORDER BY name, id")).
+		WillReturnRows(sqlmock.NewRows(beneficiaryColumnNames).
+			AddRow("first_id", "Cobranças A", "11222333000181", "237", "1234", "0012345", "09", "", "{}", createdAt).
+			AddRow("second_id", "Cobranças B", "52998224725", "001", "4321", "0054321", "17", "987", "{}", createdAt))
+
+	beneficiaries, err := suite.repository.List()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), beneficiaries, 2)
+	assert.Equal(suite.T(), "first_id", beneficiaries[0].ID)
+	assert.Equal(suite.T(), "001", beneficiaries[1].BankCode)
+}
@@ -0,0 +1,97 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type DunningPgRepository struct {
+	db        *sql.DB
+	bankSlips *BankSlipPgRepository
+}
+
+func NewDunningPgRepository(db *sql.DB) *DunningPgRepository {
+	return &DunningPgRepository{db: db, bankSlips: NewBankSlipPgRepository(db)}
+}
+
+// MoveDue skips the rows another worker has locked, so replicas running it
+// at the same time move different bank slips, and records the history in the
+// same statement.
+func (r *DunningPgRepository) MoveDue(from []entities.BankSlipStatus, status entities.BankSlipStatus, dueBefore time.Time, reason string, limit int) (int, error) {
+	fields := []any{status, dueBefore, reason, limit}
+	fromStatuses := []string{}
+	for _, fromStatus := range from {
+		fields = append(fields, fromStatus)
+		fromStatuses = append(fromStatuses, fmt.Sprintf("$%d", len(fields)))
+	}
+
+	query := fmt.Sprintf(`
+		WITH due AS (
+			SELECT debt_id, status FROM bank_slip
+			WHERE status IN (%s) AND debt_due_date < $2
+			ORDER BY debt_id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		), moved AS (
+			UPDATE bank_slip bs SET status = $1
+			FROM due
+			WHERE bs.debt_id = due.debt_id
+			RETURNING bs.debt_id, due.status AS from_status
+		)
+		INSERT INTO bank_slip_status_history (debt_id, from_status, to_status, reason)
+		SELECT debt_id, from_status, $1, $3 FROM moved
+	`, strings.Join(fromStatuses, ", "))
+	result, err := r.db.Exec(query, fields...)
+	if err != nil {
+		return 0, err
+	}
+	moved, err := result.RowsAffected()
+	return int(moved), err
+}
+
+// ClaimNotices inserts the notices of the bank slips in the same statement
+// that finds them, the primary key keeps a bank slip from being claimed by
+// two workers.
+func (r *DunningPgRepository) ClaimNotices(step entities.DunningStep, date time.Time, limit int) ([]*entities.BankSlip, error) {
+	from, to := step.DueDates(date)
+	query := fmt.Sprintf(`
+		WITH due AS (
+			SELECT debt_id FROM bank_slip bs
+			WHERE status = $2 AND debt_due_date <= $3 AND debt_due_date >= $4
+				AND NOT EXISTS (SELECT 1 FROM bank_slip_dunning_notice n WHERE n.debt_id = bs.debt_id AND n.step = $1)
+			ORDER BY debt_id
+			LIMIT $5
+		), claimed AS (
+			INSERT INTO bank_slip_dunning_notice (debt_id, step)
+			SELECT debt_id, $1 FROM due
+			ON CONFLICT DO NOTHING
+			RETURNING debt_id
+		)
+		SELECT %s FROM bank_slip WHERE debt_id IN (SELECT debt_id FROM claimed) ORDER BY debt_id
+	`, bankSlipColumns)
+
+	queryResult, err := r.db.Query(query, step.String(), step.Status(), to, from, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	bankSlips := []*entities.BankSlip{}
+	for queryResult.Next() {
+		slip, err := r.bankSlips.scanBankSlip(queryResult)
+		if err != nil {
+			return nil, err
+		}
+		bankSlips = append(bankSlips, slip)
+	}
+	return bankSlips, queryResult.Err()
+}
+
+func (r *DunningPgRepository) ReleaseNotice(debtId string, step entities.DunningStep) error {
+	_, err := r.db.Exec("DELETE FROM bank_slip_dunning_notice WHERE debt_id = $1 AND step = $2", debtId, step.String())
+	return err
+}
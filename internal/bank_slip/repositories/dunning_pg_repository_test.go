@@ -0,0 +1,79 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DunningPgRepositoryTestSuite struct {
+	suite.Suite
+	repository *DunningPgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *DunningPgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewDunningPgRepository(db)
+}
+
+func TestDunningPgRepository(t *testing.T) {
+	suite.Run(t, new(DunningPgRepositoryTestSuite))
+}
+
+func (suite *DunningPgRepositoryTestSuite) TestMoveDue() {
+	dueBefore := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	suite.mock.ExpectExec("FOR UPDATE SKIP LOCKED(.+)UPDATE bank_slip bs SET status = \\$1(.+)INSERT INTO bank_slip_status_history").
+		WithArgs(bankSlipEntities.BankSlipStatusExpired, dueBefore, "grace period of 30 days passed", 100,
+			bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusOverdue).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	moved, err := suite.repository.MoveDue(
+		[]bankSlipEntities.BankSlipStatus{bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusOverdue},
+		bankSlipEntities.BankSlipStatusExpired, dueBefore, "grace period of 30 days passed", 100,
+	)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, moved)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *DunningPgRepositoryTestSuite) TestClaimNotices_ShouldReturnBankSlipsClaimed() {
+	suite.mock.ExpectQuery("INSERT INTO bank_slip_dunning_notice (.+) ON CONFLICT DO NOTHING").
+		WithArgs("D-3", bankSlipEntities.BankSlipStatusSuccess, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 29, 0, 0, 0, 0, time.UTC), 100).
+		WillReturnRows(issuedBankSlipRow(sqlmock.NewRows(bankSlipColumnNames), "debt1", 1000, 41))
+
+	bankSlips, err := suite.repository.ClaimNotices(bankSlipEntities.DunningStep{Offset: -3}, time.Date(2026, 3, 28, 0, 0, 0, 0, time.UTC), 100)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), bankSlips, 1)
+	assert.Equal(suite.T(), "debt1", bankSlips[0].DebtId)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *DunningPgRepositoryTestSuite) TestClaimNotices_ShouldLimitOverdueNoticesToTheNextStep() {
+	suite.mock.ExpectQuery("INSERT INTO bank_slip_dunning_notice").
+		WithArgs("D+1", bankSlipEntities.BankSlipStatusOverdue, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 26, 0, 0, 0, 0, time.UTC), 100).
+		WillReturnRows(sqlmock.NewRows(bankSlipColumnNames))
+
+	bankSlips, err := suite.repository.ClaimNotices(bankSlipEntities.DunningStep{Offset: 1, Until: 7}, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), 100)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), bankSlips)
+}
+
+func (suite *DunningPgRepositoryTestSuite) TestReleaseNotice() {
+	suite.mock.ExpectExec("DELETE FROM bank_slip_dunning_notice WHERE debt_id = \\$1 AND step = \\$2").
+		WithArgs("debt1", "D+7").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repository.ReleaseNotice("debt1", bankSlipEntities.DunningStep{Offset: 7})
+	assert.NoError(suite.T(), err)
+}
@@ -0,0 +1,100 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type FixedWidthLayoutPgRepository struct {
+	db *sql.DB
+}
+
+func NewFixedWidthLayoutPgRepository(db *sql.DB) *FixedWidthLayoutPgRepository {
+	return &FixedWidthLayoutPgRepository{db: db}
+}
+
+func (r *FixedWidthLayoutPgRepository) Insert(layout *entities.FixedWidthLayout) error {
+	query := "INSERT INTO fixed_width_layout (name, extension, record_type, fields, date_format) VALUES ($1, NULLIF($2, ''), $3, $4, $5) ON CONFLICT DO NOTHING returning id"
+
+	fields, err := json.Marshal(layout.Fields)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRow(
+		query,
+		layout.Name,
+		layout.Extension,
+		layout.RecordType,
+		fields,
+		layout.DateFormat,
+	).Scan(&layout.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrFixedWidthLayoutAlreadyExists
+	}
+	return err
+}
+
+func (r *FixedWidthLayoutPgRepository) GetByName(name string) (*entities.FixedWidthLayout, error) {
+	query := "SELECT id, name, COALESCE(extension, ''), record_type, fields, date_format FROM fixed_width_layout WHERE name = $1"
+
+	layout, err := r.scanFixedWidthLayout(r.db.QueryRow(query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entities.ErrFixedWidthLayoutNotFound
+	}
+	return layout, err
+}
+
+func (r *FixedWidthLayoutPgRepository) GetByExtension(extension string) (*entities.FixedWidthLayout, error) {
+	query := "SELECT id, name, COALESCE(extension, ''), record_type, fields, date_format FROM fixed_width_layout WHERE extension = $1"
+
+	layout, err := r.scanFixedWidthLayout(r.db.QueryRow(query, extension))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entities.ErrFixedWidthLayoutNotFound
+	}
+	return layout, err
+}
+
+func (r *FixedWidthLayoutPgRepository) List() ([]*entities.FixedWidthLayout, error) {
+	query := "SELECT id, name, COALESCE(extension, ''), record_type, fields, date_format FROM fixed_width_layout ORDER BY name"
+
+	queryResult, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	layouts := []*entities.FixedWidthLayout{}
+	for queryResult.Next() {
+		layout, err := r.scanFixedWidthLayout(queryResult)
+		if err != nil {
+			return nil, err
+		}
+		layouts = append(layouts, layout)
+	}
+	return layouts, queryResult.Err()
+}
+
+func (r *FixedWidthLayoutPgRepository) scanFixedWidthLayout(row rowScanner) (*entities.FixedWidthLayout, error) {
+	var layout entities.FixedWidthLayout
+	var fields []byte
+
+	err := row.Scan(
+		&layout.ID,
+		&layout.Name,
+		&layout.Extension,
+		&layout.RecordType,
+		&fields,
+		&layout.DateFormat,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(fields, &layout.Fields); err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
@@ -0,0 +1,92 @@
+package bank_slip
+
+import (
+	"database/sql"
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FixedWidthLayoutPgRepositoryTestSuite struct {
+	suite.Suite
+	repository *FixedWidthLayoutPgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *FixedWidthLayoutPgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewFixedWidthLayoutPgRepository(db)
+}
+
+func TestFixedWidthLayoutPgRepository(t *testing.T) {
+	suite.Run(t, new(FixedWidthLayoutPgRepositoryTestSuite))
+}
+
+const testFixedWidthFields = `[{"name":"debtAmount","start":2,"length":10,"type":"DECIMAL","decimals":2}]`
+
+func (suite *FixedWidthLayoutPgRepositoryTestSuite) TestInsert() {
+	layout := &bankSlipEntities.FixedWidthLayout{
+		Name:       "partner",
+		Extension:  ".rem",
+		RecordType: "1",
+		Fields:     []bankSlipEntities.FixedWidthField{{Name: bankSlipEntities.BankSlipFieldDebtAmount, Start: 2, Length: 10, Type: bankSlipEntities.FixedWidthFieldTypeDecimal, Decimals: 2}},
+		DateFormat: "DDMMYYYY",
+	}
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO fixed_width_layout (name, extension, record_type, fields, date_format) VALUES ($1, NULLIF($2, ''), $3, $4, $5) ON CONFLICT DO NOTHING returning id")).
+		WithArgs("partner", ".rem", "1", []byte(testFixedWidthFields), "DDMMYYYY").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("layout_id"))
+
+	err := suite.repository.Insert(layout)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "layout_id", layout.ID)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FixedWidthLayoutPgRepositoryTestSuite) TestInsertShouldReturnAlreadyExistsOnConflict() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO fixed_width_layout")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := suite.repository.Insert(&bankSlipEntities.FixedWidthLayout{Name: "partner"})
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrFixedWidthLayoutAlreadyExists)
+}
+
+func (suite *FixedWidthLayoutPgRepositoryTestSuite) TestGetByExtension() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, COALESCE(extension, ''), record_type, fields, date_format FROM fixed_width_layout WHERE extension = $1")).
+		WithArgs(".rem").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "extension", "record_type", "fields", "date_format"}).
+			AddRow("layout_id", "partner", ".rem", "1", []byte(testFixedWidthFields), "DDMMYYYY"))
+
+	layout, err := suite.repository.GetByExtension(".rem")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "partner", layout.Name)
+	assert.Equal(suite.T(), []bankSlipEntities.FixedWidthField{{Name: bankSlipEntities.BankSlipFieldDebtAmount, Start: 2, Length: 10, Type: bankSlipEntities.FixedWidthFieldTypeDecimal, Decimals: 2}}, layout.Fields)
+}
+
+func (suite *FixedWidthLayoutPgRepositoryTestSuite) TestGetByNameShouldReturnNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM fixed_width_layout WHERE name = $1")).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := suite.repository.GetByName("missing")
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrFixedWidthLayoutNotFound)
+}
+
+func (suite *FixedWidthLayoutPgRepositoryTestSuite) TestList() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM fixed_width_layout ORDER BY name")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "extension", "record_type", "fields", "date_format"}).
+			AddRow("layout_id", "partner", "", "1", []byte(testFixedWidthFields), "DDMMYYYY"))
+
+	layouts, err := suite.repository.List()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), layouts, 1)
+	assert.Equal(suite.T(), "", layouts[0].Extension)
+}
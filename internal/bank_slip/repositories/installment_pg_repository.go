@@ -0,0 +1,87 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+const (
+	installmentIssuedReason  = "issuance window opened"
+	installmentRetriedReason = "billing retried"
+)
+
+type InstallmentPgRepository struct {
+	db        *sql.DB
+	bankSlips *BankSlipPgRepository
+}
+
+func NewInstallmentPgRepository(db *sql.DB) *InstallmentPgRepository {
+	return &InstallmentPgRepository{db: db, bankSlips: NewBankSlipPgRepository(db)}
+}
+
+// IssueScheduled skips the rows another worker has locked, so replicas
+// running it at the same time issue different installments, and records the
+// history in the same statement. The installments are read from the update, as
+// the rest of the statement sees them before it. An installment left PENDING
+// is told apart by its history: moved by this query, and not changed since
+// staleBefore. The history of its retry renews it.
+func (r *InstallmentPgRepository) IssueScheduled(dueUntil time.Time, staleBefore time.Time, limit int) ([]*entities.BankSlip, error) {
+	query := fmt.Sprintf(`
+		WITH due AS (
+			SELECT debt_id, status FROM bank_slip bs
+			WHERE debt_due_date <= $3 AND (status = $1 OR (status = $2
+				AND EXISTS (
+					SELECT 1 FROM bank_slip_status_history
+					WHERE debt_id = bs.debt_id AND to_status = $2 AND reason = $5
+				)
+				AND NOT EXISTS (
+					SELECT 1 FROM bank_slip_status_history
+					WHERE debt_id = bs.debt_id AND changed_at >= $6
+				)))
+			ORDER BY debt_id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		), issued AS (
+			UPDATE bank_slip SET status = $2
+			WHERE debt_id IN (SELECT debt_id FROM due)
+			RETURNING %s
+		), history AS (
+			INSERT INTO bank_slip_status_history (debt_id, from_status, to_status, reason)
+			SELECT debt_id, status, $2, CASE WHEN status = $1 THEN $5 ELSE $7 END FROM due
+		)
+		SELECT * FROM issued ORDER BY debt_id
+	`, bankSlipColumns)
+
+	queryResult, err := r.db.Query(query, entities.BankSlipStatusScheduled, entities.BankSlipStatusPending, dueUntil, limit, installmentIssuedReason, staleBefore, installmentRetriedReason)
+	if err != nil {
+		return nil, err
+	}
+	return r.scanAll(queryResult)
+}
+
+func (r *InstallmentPgRepository) ListByParentDebtId(parentDebtId string) ([]*entities.BankSlip, error) {
+	query := fmt.Sprintf("SELECT %s FROM bank_slip WHERE parent_debt_id = $1 ORDER BY installment_number", bankSlipColumns)
+
+	queryResult, err := r.db.Query(query, parentDebtId)
+	if err != nil {
+		return nil, err
+	}
+	return r.scanAll(queryResult)
+}
+
+func (r *InstallmentPgRepository) scanAll(queryResult *sql.Rows) ([]*entities.BankSlip, error) {
+	defer queryResult.Close()
+
+	bankSlips := []*entities.BankSlip{}
+	for queryResult.Next() {
+		slip, err := r.bankSlips.scanBankSlip(queryResult)
+		if err != nil {
+			return nil, err
+		}
+		bankSlips = append(bankSlips, slip)
+	}
+	return bankSlips, queryResult.Err()
+}
@@ -0,0 +1,72 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type InstallmentPgRepositoryTestSuite struct {
+	suite.Suite
+	repository *InstallmentPgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *InstallmentPgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewInstallmentPgRepository(db)
+}
+
+func TestInstallmentPgRepository(t *testing.T) {
+	suite.Run(t, new(InstallmentPgRepositoryTestSuite))
+}
+
+func (suite *InstallmentPgRepositoryTestSuite) TestIssueScheduled_ShouldReturnInstallmentsMovedToPending() {
+	dueUntil := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	staleBefore := time.Date(2026, 3, 1, 9, 45, 0, 0, time.UTC)
+	suite.mock.ExpectQuery("status = \\$2(.+)changed_at >= \\$6(.+)FOR UPDATE SKIP LOCKED(.+)UPDATE bank_slip SET status = \\$2(.+)INSERT INTO bank_slip_status_history(.+)SELECT \\* FROM issued").
+		WithArgs(bankSlipEntities.BankSlipStatusScheduled, bankSlipEntities.BankSlipStatusPending, dueUntil, 100, "issuance window opened", staleBefore, "billing retried").
+		WillReturnRows(sqlmock.NewRows(bankSlipColumnNames).AddRow(
+			"debt1", int64(1000), dueUntil, "52998224725", "John Doe", "john.doe@example.com",
+			"file1", nil, "PENDING", "beneficiary1", int64(41), "", "", "", "{}", "parent1", 2, 3, nil,
+		))
+
+	bankSlips, err := suite.repository.IssueScheduled(dueUntil, staleBefore, 100)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), bankSlips, 1)
+	assert.Equal(suite.T(), bankSlipEntities.BankSlipStatusPending, bankSlips[0].Status)
+	assert.Equal(suite.T(), "parent1", bankSlips[0].ParentDebtId)
+	assert.Equal(suite.T(), 2, bankSlips[0].InstallmentNumber)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *InstallmentPgRepositoryTestSuite) TestIssueScheduled_ShouldFailWhenQueryFails() {
+	suite.mock.ExpectQuery("UPDATE bank_slip SET status").WillReturnError(errors.New("connection lost"))
+
+	bankSlips, err := suite.repository.IssueScheduled(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 9, 45, 0, 0, time.UTC), 100)
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), bankSlips)
+}
+
+func (suite *InstallmentPgRepositoryTestSuite) TestListByParentDebtId() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM bank_slip WHERE parent_debt_id = \\$1 ORDER BY installment_number").
+		WithArgs("parent1").
+		WillReturnRows(issuedBankSlipRow(issuedBankSlipRow(sqlmock.NewRows(bankSlipColumnNames), "debt1", 1000, 41), "debt2", 1001, 42))
+
+	bankSlips, err := suite.repository.ListByParentDebtId("parent1")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), bankSlips, 2)
+	assert.Equal(suite.T(), "debt2", bankSlips[1].DebtId)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,200 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+// remittanceBankSlipsPerInsert keeps the statements linking the bank slips
+// to a remittance under the Postgres limit of parameters.
+const remittanceBankSlipsPerInsert = 10000
+
+type RemittancePgRepository struct {
+	db        *sql.DB
+	bankSlips *BankSlipPgRepository
+}
+
+func NewRemittancePgRepository(db *sql.DB) *RemittancePgRepository {
+	return &RemittancePgRepository{db: db, bankSlips: NewBankSlipPgRepository(db)}
+}
+
+const remittanceColumns = "id, beneficiary_id, bank_slip_file_id, format, sequence, total_bank_slips, total_amount_cents, created_at"
+
+// Create holds the beneficiary locked until the remittance is stored, so
+// concurrent remittances get consecutive sequences and never share a bank
+// slip.
+func (r *RemittancePgRepository) Create(remittance *entities.Remittance, render entities.RemittanceRenderer) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var lastSequence int64
+	err = tx.QueryRow("SELECT last_remittance_sequence FROM beneficiary WHERE id = $1 FOR UPDATE", remittance.BeneficiaryId).Scan(&lastSequence)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrBeneficiaryNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	bankSlips, err := r.listNotRemitted(tx, remittance)
+	if err != nil {
+		return err
+	}
+	if len(bankSlips) == 0 {
+		return entities.ErrNothingToRemit
+	}
+
+	remittance.Numbered(lastSequence+1, bankSlips)
+	content, err := render(remittance, bankSlips)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO remittance (beneficiary_id, bank_slip_file_id, format, sequence, total_bank_slips, total_amount_cents, content)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`,
+		remittance.BeneficiaryId,
+		remittance.BankSlipFileId,
+		remittance.Format,
+		remittance.Sequence,
+		remittance.TotalBankSlips,
+		remittance.TotalAmount.Centavos(),
+		string(content),
+	).Scan(&remittance.ID, &remittance.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(bankSlips); start += remittanceBankSlipsPerInsert {
+		end := min(start+remittanceBankSlipsPerInsert, len(bankSlips))
+		if err := r.insertBankSlips(tx, remittance.ID, bankSlips[start:end]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE beneficiary SET last_remittance_sequence = $2 WHERE id = $1", remittance.BeneficiaryId, remittance.Sequence); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	remittance.Content = content
+	return nil
+}
+
+// listNotRemitted returns the issued bank slips of the remittance that no
+// other remittance has sent, in the order of their our numbers. A bank slip
+// whose email failed or that is already overdue is still billed and goes to
+// the bank all the same.
+func (r *RemittancePgRepository) listNotRemitted(tx *sql.Tx, remittance *entities.Remittance) ([]*entities.BankSlip, error) {
+	args := []any{remittance.BeneficiaryId}
+	statuses := []string{}
+	for _, status := range entities.BankSlipIssuedStatuses {
+		args = append(args, status)
+		statuses = append(statuses, fmt.Sprintf("$%d", len(args)))
+	}
+	query := fmt.Sprintf(`
+		SELECT %s FROM bank_slip bs
+		WHERE bs.beneficiary_id = $1 AND bs.status IN (%s) AND bs.barcode <> ''
+		AND NOT EXISTS (SELECT 1 FROM remittance_bank_slip rbs WHERE rbs.debt_id = bs.debt_id)
+	`, bankSlipColumns, strings.Join(statuses, ", "))
+	if remittance.BankSlipFileId != "" {
+		args = append(args, remittance.BankSlipFileId)
+		query += fmt.Sprintf(" AND bs.bank_slip_file_id = $%d", len(args))
+	}
+	query += " ORDER BY bs.our_number"
+
+	queryResult, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	bankSlips := []*entities.BankSlip{}
+	for queryResult.Next() {
+		bankSlip, err := r.bankSlips.scanBankSlip(queryResult)
+		if err != nil {
+			return nil, err
+		}
+		bankSlips = append(bankSlips, bankSlip)
+	}
+	return bankSlips, queryResult.Err()
+}
+
+func (r *RemittancePgRepository) insertBankSlips(tx *sql.Tx, remittanceId string, bankSlips []*entities.BankSlip) error {
+	fields := []any{remittanceId}
+	queryValues := make([]string, 0, len(bankSlips))
+	for i, bankSlip := range bankSlips {
+		fields = append(fields, bankSlip.DebtId)
+		queryValues = append(queryValues, fmt.Sprintf("($%d, $1)", i+2))
+	}
+
+	query := fmt.Sprintf("INSERT INTO remittance_bank_slip (debt_id, remittance_id) VALUES %s", strings.Join(queryValues, ", "))
+	_, err := tx.Exec(query, fields...)
+	return err
+}
+
+func (r *RemittancePgRepository) GetById(id string) (*entities.Remittance, error) {
+	query := fmt.Sprintf("SELECT %s, content FROM remittance WHERE id = $1", remittanceColumns)
+
+	var content string
+	remittance, err := r.scanRemittance(r.db.QueryRow(query, id), &content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entities.ErrRemittanceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	remittance.Content = []byte(content)
+	return remittance, nil
+}
+
+// List leaves out the content of the remittances.
+func (r *RemittancePgRepository) List(limit, offset int) ([]*entities.Remittance, error) {
+	query := fmt.Sprintf("SELECT %s FROM remittance ORDER BY created_at DESC, id LIMIT $1 OFFSET $2", remittanceColumns)
+
+	queryResult, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	remittances := []*entities.Remittance{}
+	for queryResult.Next() {
+		remittance, err := r.scanRemittance(queryResult)
+		if err != nil {
+			return nil, err
+		}
+		remittances = append(remittances, remittance)
+	}
+	return remittances, queryResult.Err()
+}
+
+func (r *RemittancePgRepository) scanRemittance(row rowScanner, extra ...any) (*entities.Remittance, error) {
+	var remittance entities.Remittance
+	var bankSlipFileId sql.NullString
+	err := row.Scan(append([]any{
+		&remittance.ID,
+		&remittance.BeneficiaryId,
+		&bankSlipFileId,
+		&remittance.Format,
+		&remittance.Sequence,
+		&remittance.TotalBankSlips,
+		&remittance.TotalAmount,
+		&remittance.CreatedAt,
+	}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	remittance.BankSlipFileId = bankSlipFileId.String
+	return &remittance, nil
+}
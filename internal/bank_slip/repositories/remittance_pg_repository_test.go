@@ -0,0 +1,209 @@
+package bank_slip
+
+import (
+	"database/sql"
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/cnab"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RemittancePgRepositoryTestSuite struct {
+	suite.Suite
+	repository *RemittancePgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *RemittancePgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewRemittancePgRepository(db)
+}
+
+func TestRemittancePgRepository(t *testing.T) {
+	suite.Run(t, new(RemittancePgRepositoryTestSuite))
+}
+
+var remittanceColumnNames = []string{"id", "beneficiary_id", "bank_slip_file_id", "format", "sequence", "total_bank_slips", "total_amount_cents", "created_at"}
+
+func (suite *RemittancePgRepositoryTestSuite) expectBeneficiaryLock(lastSequence int64) {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT last_remittance_sequence FROM beneficiary WHERE id = $1 FOR UPDATE")).
+		WithArgs("beneficiary1").
+		WillReturnRows(sqlmock.NewRows([]string{"last_remittance_sequence"}).AddRow(lastSequence))
+}
+
+func issuedBankSlipRow(rows *sqlmock.Rows, debtId string, amount int64, ourNumber int64) *sqlmock.Rows {
+	return rows.AddRow(
+		debtId, amount, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "52998224725", "John Doe", "john.doe@example.com",
+		"file1", nil, "SUCCESS", "beneficiary1", ourNumber, "23792131200001000501234090000000004200123450", "23791234059000000000142001234501213120000100050",
+		"", "{}", nil, 0, 0, nil,
+	)
+}
+
+func (suite *RemittancePgRepositoryTestSuite) TestCreate() {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	suite.expectBeneficiaryLock(6)
+	suite.mock.ExpectQuery("SELECT (.+) FROM bank_slip bs WHERE bs.beneficiary_id = \\$1 AND bs.status IN \\(\\$2, \\$3, \\$4, \\$5\\) AND bs.barcode <> '' (.+) AND bs.bank_slip_file_id = \\$6 ORDER BY bs.our_number").
+		WithArgs(
+			"beneficiary1",
+			bankSlipEntities.BankSlipStatusSuccess,
+			bankSlipEntities.BankSlipStatusSendingEmailError,
+			bankSlipEntities.BankSlipStatusOverdue,
+			bankSlipEntities.BankSlipStatusExpired,
+			"file1",
+		).
+		WillReturnRows(issuedBankSlipRow(issuedBankSlipRow(sqlmock.NewRows(bankSlipColumnNames), "debt1", 1000, 41), "debt2", 2050, 42))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO remittance")).
+		WithArgs("beneficiary1", "file1", cnab.CNAB240, int64(7), 2, int64(3050), "content").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("remittance1", createdAt))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO remittance_bank_slip (debt_id, remittance_id) VALUES ($2, $1), ($3, $1)")).
+		WithArgs("remittance1", "debt1", "debt2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE beneficiary SET last_remittance_sequence = $2 WHERE id = $1")).
+		WithArgs("beneficiary1", int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	remittance := bankSlipEntities.NewRemittance("beneficiary1", "file1", cnab.CNAB240)
+	var rendered []string
+	err := suite.repository.Create(remittance, func(remittance *bankSlipEntities.Remittance, bankSlips []*bankSlipEntities.BankSlip) ([]byte, error) {
+		assert.Equal(suite.T(), int64(7), remittance.Sequence)
+		for _, bankSlip := range bankSlips {
+			rendered = append(rendered, bankSlip.DebtId)
+		}
+		return []byte("content"), nil
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"debt1", "debt2"}, rendered)
+	assert.Equal(suite.T(), "remittance1", remittance.ID)
+	assert.Equal(suite.T(), 2, remittance.TotalBankSlips)
+	assert.Equal(suite.T(), bankSlipEntities.Money(3050), remittance.TotalAmount)
+	assert.Equal(suite.T(), []byte("content"), remittance.Content)
+	assert.Equal(suite.T(), createdAt, remittance.CreatedAt)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RemittancePgRepositoryTestSuite) TestCreateShouldSendEverythingNotRemittedWithoutFile() {
+	suite.expectBeneficiaryLock(0)
+	suite.mock.ExpectQuery("NOT EXISTS (.+) ORDER BY bs.our_number").
+		WithArgs(
+			"beneficiary1",
+			bankSlipEntities.BankSlipStatusSuccess,
+			bankSlipEntities.BankSlipStatusSendingEmailError,
+			bankSlipEntities.BankSlipStatusOverdue,
+			bankSlipEntities.BankSlipStatusExpired,
+		).
+		WillReturnRows(issuedBankSlipRow(sqlmock.NewRows(bankSlipColumnNames), "debt1", 1000, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO remittance")).
+		WithArgs("beneficiary1", "", cnab.CNAB400, int64(1), 1, int64(1000), "content").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("remittance1", time.Now()))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO remittance_bank_slip")).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE beneficiary SET last_remittance_sequence")).
+		WithArgs("beneficiary1", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	remittance := bankSlipEntities.NewRemittance("beneficiary1", "", cnab.CNAB400)
+	err := suite.repository.Create(remittance, func(*bankSlipEntities.Remittance, []*bankSlipEntities.BankSlip) ([]byte, error) {
+		return []byte("content"), nil
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RemittancePgRepositoryTestSuite) TestCreateShouldRollbackWhenNothingToRemit() {
+	suite.expectBeneficiaryLock(3)
+	suite.mock.ExpectQuery("FROM bank_slip bs").WillReturnRows(sqlmock.NewRows(bankSlipColumnNames))
+	suite.mock.ExpectRollback()
+
+	remittance := bankSlipEntities.NewRemittance("beneficiary1", "", cnab.CNAB240)
+	err := suite.repository.Create(remittance, func(*bankSlipEntities.Remittance, []*bankSlipEntities.BankSlip) ([]byte, error) {
+		suite.Fail("nothing should be rendered")
+		return nil, nil
+	})
+
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrNothingToRemit)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RemittancePgRepositoryTestSuite) TestCreateShouldRollbackWhenRenderFails() {
+	suite.expectBeneficiaryLock(3)
+	suite.mock.ExpectQuery("FROM bank_slip bs").
+		WillReturnRows(issuedBankSlipRow(sqlmock.NewRows(bankSlipColumnNames), "debt1", 1000, 1))
+	suite.mock.ExpectRollback()
+
+	remittance := bankSlipEntities.NewRemittance("beneficiary1", "", cnab.CNAB240)
+	err := suite.repository.Create(remittance, func(*bankSlipEntities.Remittance, []*bankSlipEntities.BankSlip) ([]byte, error) {
+		return nil, cnab.ErrFieldOverflow
+	})
+
+	assert.ErrorIs(suite.T(), err, cnab.ErrFieldOverflow)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RemittancePgRepositoryTestSuite) TestCreateShouldFailWhenBeneficiaryDoesNotExist() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT last_remittance_sequence FROM beneficiary")).
+		WillReturnRows(sqlmock.NewRows([]string{"last_remittance_sequence"}))
+	suite.mock.ExpectRollback()
+
+	err := suite.repository.Create(bankSlipEntities.NewRemittance("beneficiary1", "", cnab.CNAB240), nil)
+
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrBeneficiaryNotFound)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RemittancePgRepositoryTestSuite) TestGetById() {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	suite.mock.ExpectQuery(regexp.QuoteMeta("content FROM remittance WHERE id = $1")).
+		WithArgs("remittance1").
+		WillReturnRows(sqlmock.NewRows(append(remittanceColumnNames, "content")).
+			AddRow("remittance1", "beneficiary1", nil, "CNAB400", int64(3), 2, int64(3050), createdAt, "content"))
+
+	remittance, err := suite.repository.GetById("remittance1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &bankSlipEntities.Remittance{
+		ID:             "remittance1",
+		BeneficiaryId:  "beneficiary1",
+		Format:         cnab.CNAB400,
+		Sequence:       3,
+		TotalBankSlips: 2,
+		TotalAmount:    3050,
+		Content:        []byte("content"),
+		CreatedAt:      createdAt,
+	}, remittance)
+}
+
+func (suite *RemittancePgRepositoryTestSuite) TestGetByIdNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM remittance WHERE id = $1")).
+		WillReturnRows(sqlmock.NewRows(append(remittanceColumnNames, "content")))
+
+	remittance, err := suite.repository.GetById("remittance1")
+	assert.Nil(suite.T(), remittance)
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrRemittanceNotFound)
+}
+
+func (suite *RemittancePgRepositoryTestSuite) TestList() {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM remittance ORDER BY created_at DESC, id LIMIT $1 OFFSET $2")).
+		WithArgs(20, 0).
+		WillReturnRows(sqlmock.NewRows(remittanceColumnNames).
+			AddRow("remittance1", "beneficiary1", "file1", "CNAB240", int64(1), 1, int64(1000), createdAt))
+
+	remittances, err := suite.repository.List(20, 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), remittances, 1)
+	assert.Equal(suite.T(), "file1", remittances[0].BankSlipFileId)
+	assert.Nil(suite.T(), remittances[0].Content)
+}
@@ -0,0 +1,191 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type SubscriptionPgRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriptionPgRepository(db *sql.DB) *SubscriptionPgRepository {
+	return &SubscriptionPgRepository{db: db}
+}
+
+const subscriptionColumns = `
+	id, beneficiary_id, user_name, government_id, user_email, amount_cents,
+	day_of_month, start_date, end_date, charge_policy, status, next_period,
+	next_due_date, created_at, updated_at
+`
+
+func (r *SubscriptionPgRepository) Insert(subscription *entities.Subscription) error {
+	query := `
+		INSERT INTO subscription (beneficiary_id, user_name, government_id, user_email, amount_cents, day_of_month, start_date, end_date, charge_policy, status, next_period, next_due_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at, updated_at
+	`
+
+	chargePolicy, err := json.Marshal(subscription.ChargePolicy)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRow(
+		query,
+		subscription.BeneficiaryId,
+		subscription.UserName,
+		subscription.GovernmentId.String(),
+		subscription.UserEmail,
+		subscription.Amount.Centavos(),
+		subscription.DayOfMonth,
+		subscription.StartDate,
+		nullIfZeroTime(subscription.EndDate),
+		chargePolicy,
+		subscription.Status,
+		nullIfZeroTime(subscription.NextPeriod),
+		nullIfZeroTime(subscription.NextDueDate),
+	).Scan(&subscription.ID, &subscription.CreatedAt, &subscription.UpdatedAt)
+}
+
+func (r *SubscriptionPgRepository) Update(subscription *entities.Subscription) error {
+	query := `
+		UPDATE subscription
+		SET user_name = $2, government_id = $3, user_email = $4, amount_cents = $5, day_of_month = $6,
+			end_date = $7, charge_policy = $8, status = $9, next_period = $10, next_due_date = $11, updated_at = NOW()
+		WHERE id = $1 AND status = $12
+		RETURNING updated_at
+	`
+
+	chargePolicy, err := json.Marshal(subscription.ChargePolicy)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRow(
+		query,
+		subscription.ID,
+		subscription.UserName,
+		subscription.GovernmentId.String(),
+		subscription.UserEmail,
+		subscription.Amount.Centavos(),
+		subscription.DayOfMonth,
+		nullIfZeroTime(subscription.EndDate),
+		chargePolicy,
+		subscription.Status,
+		nullIfZeroTime(subscription.NextPeriod),
+		nullIfZeroTime(subscription.NextDueDate),
+		entities.SubscriptionStatusActive,
+	).Scan(&subscription.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrSubscriptionNotActive
+	}
+	return err
+}
+
+func (r *SubscriptionPgRepository) GetById(id string) (*entities.Subscription, error) {
+	query := fmt.Sprintf("SELECT %s FROM subscription WHERE id = $1", subscriptionColumns)
+
+	subscription, err := r.scanSubscription(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entities.ErrSubscriptionNotFound
+	}
+	return subscription, err
+}
+
+func (r *SubscriptionPgRepository) List(beneficiaryId string) ([]*entities.Subscription, error) {
+	query := fmt.Sprintf("SELECT %s FROM subscription", subscriptionColumns)
+	args := []any{}
+	if beneficiaryId != "" {
+		query += " WHERE beneficiary_id = $1"
+		args = append(args, beneficiaryId)
+	}
+	query += " ORDER BY created_at, id"
+
+	return r.querySubscriptions(query, args...)
+}
+
+func (r *SubscriptionPgRepository) ListDue(dueUntil time.Time, limit int) ([]*entities.Subscription, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM subscription
+		WHERE status = $1 AND next_due_date <= $2
+		ORDER BY next_due_date, id
+		LIMIT $3
+	`, subscriptionColumns)
+
+	return r.querySubscriptions(query, entities.SubscriptionStatusActive, dueUntil, limit)
+}
+
+// Advance only moves a subscription still ACTIVE and in fromPeriod, so a
+// subscription cancelled or advanced in the meantime keeps what was saved.
+func (r *SubscriptionPgRepository) Advance(subscription *entities.Subscription, fromPeriod time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE subscription
+		SET status = $3, next_period = $4, next_due_date = $5, updated_at = NOW()
+		WHERE id = $1 AND next_period = $2 AND status = $6
+	`,
+		subscription.ID,
+		fromPeriod,
+		subscription.Status,
+		nullIfZeroTime(subscription.NextPeriod),
+		nullIfZeroTime(subscription.NextDueDate),
+		entities.SubscriptionStatusActive,
+	)
+	return err
+}
+
+func (r *SubscriptionPgRepository) querySubscriptions(query string, args ...any) ([]*entities.Subscription, error) {
+	queryResult, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	subscriptions := []*entities.Subscription{}
+	for queryResult.Next() {
+		subscription, err := r.scanSubscription(queryResult)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, queryResult.Err()
+}
+
+func (r *SubscriptionPgRepository) scanSubscription(row rowScanner) (*entities.Subscription, error) {
+	var subscription entities.Subscription
+	var chargePolicy []byte
+	var endDate, nextPeriod, nextDueDate sql.NullTime
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.BeneficiaryId,
+		&subscription.UserName,
+		&subscription.GovernmentId,
+		&subscription.UserEmail,
+		&subscription.Amount,
+		&subscription.DayOfMonth,
+		&subscription.StartDate,
+		&endDate,
+		&chargePolicy,
+		&subscription.Status,
+		&nextPeriod,
+		&nextDueDate,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	subscription.EndDate = endDate.Time
+	subscription.NextPeriod = nextPeriod.Time
+	subscription.NextDueDate = nextDueDate.Time
+	if err := json.Unmarshal(chargePolicy, &subscription.ChargePolicy); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
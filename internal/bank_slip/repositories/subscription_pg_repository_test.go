@@ -0,0 +1,153 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SubscriptionPgRepositoryTestSuite struct {
+	suite.Suite
+	repository *SubscriptionPgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *SubscriptionPgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewSubscriptionPgRepository(db)
+}
+
+func TestSubscriptionPgRepository(t *testing.T) {
+	suite.Run(t, new(SubscriptionPgRepositoryTestSuite))
+}
+
+var subscriptionColumnNames = []string{
+	"id", "beneficiary_id", "user_name", "government_id", "user_email", "amount_cents",
+	"day_of_month", "start_date", "end_date", "charge_policy", "status", "next_period",
+	"next_due_date", "created_at", "updated_at",
+}
+
+var (
+	testSubscriptionStart   = time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	testSubscriptionDueDate = time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC)
+	testSubscriptionCreated = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+)
+
+func newTestSubscription() *bankSlipEntities.Subscription {
+	return &bankSlipEntities.Subscription{
+		ID:            "subscription1",
+		BeneficiaryId: "beneficiary1",
+		UserName:      "John Doe",
+		GovernmentId:  "52998224725",
+		UserEmail:     "john.doe@example.com",
+		Amount:        15000,
+		DayOfMonth:    10,
+		StartDate:     testSubscriptionStart,
+		Status:        bankSlipEntities.SubscriptionStatusActive,
+		NextPeriod:    testSubscriptionStart,
+		NextDueDate:   testSubscriptionDueDate,
+	}
+}
+
+func subscriptionRow(rows *sqlmock.Rows, id string) *sqlmock.Rows {
+	return rows.AddRow(
+		id, "beneficiary1", "John Doe", "52998224725", "john.doe@example.com", int64(15000),
+		10, testSubscriptionStart, nil, `{"lateFee":"2.00%"}`, "ACTIVE", testSubscriptionStart,
+		testSubscriptionDueDate, testSubscriptionCreated, testSubscriptionCreated,
+	)
+}
+
+func (suite *SubscriptionPgRepositoryTestSuite) TestInsert() {
+	subscription := newTestSubscription()
+	subscription.ID = ""
+	suite.mock.ExpectQuery("INSERT INTO subscription").
+		WithArgs("beneficiary1", "John Doe", "52998224725", "john.doe@example.com", int64(15000), 10, testSubscriptionStart, nil, []byte("{}"), bankSlipEntities.SubscriptionStatusActive, testSubscriptionStart, testSubscriptionDueDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow("subscription1", testSubscriptionCreated, testSubscriptionCreated))
+
+	err := suite.repository.Insert(subscription)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "subscription1", subscription.ID)
+	assert.Equal(suite.T(), testSubscriptionCreated, subscription.CreatedAt)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SubscriptionPgRepositoryTestSuite) TestUpdate_ShouldFailWhenSubscriptionIsNotActive() {
+	suite.mock.ExpectQuery("UPDATE subscription(.+)WHERE id = \\$1 AND status = \\$12").
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}))
+
+	err := suite.repository.Update(newTestSubscription())
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrSubscriptionNotActive)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SubscriptionPgRepositoryTestSuite) TestGetById() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM subscription WHERE id = \\$1").
+		WithArgs("subscription1").
+		WillReturnRows(subscriptionRow(sqlmock.NewRows(subscriptionColumnNames), "subscription1"))
+
+	subscription, err := suite.repository.GetById("subscription1")
+	assert.NoError(suite.T(), err)
+	expected := newTestSubscription()
+	expected.ChargePolicy = bankSlipEntities.ChargePolicy{LateFee: &bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindPercent, Value: 200}}
+	expected.CreatedAt = testSubscriptionCreated
+	expected.UpdatedAt = testSubscriptionCreated
+	assert.Equal(suite.T(), expected, subscription)
+}
+
+func (suite *SubscriptionPgRepositoryTestSuite) TestGetById_NotFound() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM subscription WHERE id = \\$1").
+		WithArgs("subscription1").
+		WillReturnRows(sqlmock.NewRows(subscriptionColumnNames))
+
+	subscription, err := suite.repository.GetById("subscription1")
+	assert.Nil(suite.T(), subscription)
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrSubscriptionNotFound)
+}
+
+func (suite *SubscriptionPgRepositoryTestSuite) TestList_ShouldFilterByBeneficiary() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM subscription WHERE beneficiary_id = \\$1 ORDER BY created_at, id").
+		WithArgs("beneficiary1").
+		WillReturnRows(subscriptionRow(subscriptionRow(sqlmock.NewRows(subscriptionColumnNames), "subscription1"), "subscription2"))
+
+	subscriptions, err := suite.repository.List("beneficiary1")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), subscriptions, 2)
+	assert.Equal(suite.T(), "subscription2", subscriptions[1].ID)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SubscriptionPgRepositoryTestSuite) TestListDue() {
+	dueUntil := time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC)
+	suite.mock.ExpectQuery("FROM subscription(.+)WHERE status = \\$1 AND next_due_date <= \\$2(.+)LIMIT \\$3").
+		WithArgs(bankSlipEntities.SubscriptionStatusActive, dueUntil, 100).
+		WillReturnRows(subscriptionRow(sqlmock.NewRows(subscriptionColumnNames), "subscription1"))
+
+	subscriptions, err := suite.repository.ListDue(dueUntil, 100)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), subscriptions, 1)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SubscriptionPgRepositoryTestSuite) TestAdvance_ShouldOnlyMoveSubscriptionInThePeriod() {
+	subscription := newTestSubscription()
+	subscription.Status = bankSlipEntities.SubscriptionStatusEnded
+	subscription.NextPeriod = time.Time{}
+	subscription.NextDueDate = time.Time{}
+	suite.mock.ExpectExec("UPDATE subscription(.+)WHERE id = \\$1 AND next_period = \\$2 AND status = \\$6").
+		WithArgs("subscription1", testSubscriptionStart, bankSlipEntities.SubscriptionStatusEnded, nil, nil, bankSlipEntities.SubscriptionStatusActive).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.repository.Advance(subscription, testSubscriptionStart)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
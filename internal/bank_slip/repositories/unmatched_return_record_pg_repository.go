@@ -0,0 +1,74 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"fmt"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type UnmatchedReturnRecordPgRepository struct {
+	db *sql.DB
+}
+
+func NewUnmatchedReturnRecordPgRepository(db *sql.DB) *UnmatchedReturnRecordPgRepository {
+	return &UnmatchedReturnRecordPgRepository{db: db}
+}
+
+func (r *UnmatchedReturnRecordPgRepository) InsertMany(unmatchedRecords []*entities.UnmatchedReturnRecord) error {
+	if len(unmatchedRecords) == 0 {
+		return nil
+	}
+
+	fields := []any{}
+	queryValues := ""
+	for i, record := range unmatchedRecords {
+		fields = append(fields, record.ReturnFileId, record.LineNumber, record.RawRecord, record.OccurrenceCode, record.OurNumber, record.ControlNumber, record.Reason)
+		queryValues += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)", i*7+1, i*7+2, i*7+3, i*7+4, i*7+5, i*7+6, i*7+7)
+		if i < len(unmatchedRecords)-1 {
+			queryValues += ", "
+		}
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO bank_slip_unmatched_return_record
+			(bank_slip_return_file_id, line_number, raw_record, occurrence_code, our_number, control_number, reason)
+		VALUES %s
+	`, queryValues)
+	_, err := r.db.Exec(query, fields...)
+	return err
+}
+
+func (r *UnmatchedReturnRecordPgRepository) ListByReturnFileId(returnFileId string) ([]*entities.UnmatchedReturnRecord, error) {
+	query := `
+		SELECT bank_slip_return_file_id, line_number, raw_record, occurrence_code, our_number, control_number, reason
+		FROM bank_slip_unmatched_return_record
+		WHERE bank_slip_return_file_id = $1
+		ORDER BY line_number
+	`
+
+	queryResult, err := r.db.Query(query, returnFileId)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	unmatchedRecords := []*entities.UnmatchedReturnRecord{}
+	for queryResult.Next() {
+		var record entities.UnmatchedReturnRecord
+		err := queryResult.Scan(
+			&record.ReturnFileId,
+			&record.LineNumber,
+			&record.RawRecord,
+			&record.OccurrenceCode,
+			&record.OurNumber,
+			&record.ControlNumber,
+			&record.Reason,
+		)
+		if err != nil {
+			return nil, err
+		}
+		unmatchedRecords = append(unmatchedRecords, &record)
+	}
+	return unmatchedRecords, queryResult.Err()
+}
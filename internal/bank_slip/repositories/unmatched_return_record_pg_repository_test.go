@@ -0,0 +1,78 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type UnmatchedReturnRecordPgRepositoryTestSuite struct {
+	suite.Suite
+	repository *UnmatchedReturnRecordPgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *UnmatchedReturnRecordPgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewUnmatchedReturnRecordPgRepository(db)
+}
+
+func TestUnmatchedReturnRecordPgRepository(t *testing.T) {
+	suite.Run(t, new(UnmatchedReturnRecordPgRepositoryTestSuite))
+}
+
+func newTestUnmatchedReturnRecord(lineNumber int, reason bankSlipEntities.UnmatchedReason) *bankSlipEntities.UnmatchedReturnRecord {
+	return &bankSlipEntities.UnmatchedReturnRecord{
+		ReturnFileId:   "return1",
+		LineNumber:     lineNumber,
+		RawRecord:      "1...",
+		OccurrenceCode: "06",
+		OurNumber:      42,
+		ControlNumber:  "EA23F2CA663A4266A7429DA4C",
+		Reason:         reason,
+	}
+}
+
+func (suite *UnmatchedReturnRecordPgRepositoryTestSuite) TestInsertMany() {
+	suite.mock.ExpectExec("INSERT INTO bank_slip_unmatched_return_record (.+) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7\\), \\(\\$8, (.+)\\)").
+		WithArgs(
+			"return1", 2, "1...", "06", int64(42), "EA23F2CA663A4266A7429DA4C", bankSlipEntities.UnmatchedReasonBankSlipNotFound,
+			"return1", 5, "1...", "06", int64(42), "EA23F2CA663A4266A7429DA4C", bankSlipEntities.UnmatchedReasonUnexpectedStatus,
+		).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := suite.repository.InsertMany([]*bankSlipEntities.UnmatchedReturnRecord{
+		newTestUnmatchedReturnRecord(2, bankSlipEntities.UnmatchedReasonBankSlipNotFound),
+		newTestUnmatchedReturnRecord(5, bankSlipEntities.UnmatchedReasonUnexpectedStatus),
+	})
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UnmatchedReturnRecordPgRepositoryTestSuite) TestInsertMany_ShouldSkipEmptyList() {
+	err := suite.repository.InsertMany([]*bankSlipEntities.UnmatchedReturnRecord{})
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UnmatchedReturnRecordPgRepositoryTestSuite) TestListByReturnFileId() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM bank_slip_unmatched_return_record WHERE bank_slip_return_file_id = \\$1 ORDER BY line_number").
+		WithArgs("return1").
+		WillReturnRows(sqlmock.NewRows([]string{"bank_slip_return_file_id", "line_number", "raw_record", "occurrence_code", "our_number", "control_number", "reason"}).
+			AddRow("return1", 2, "1...", "06", int64(42), "EA23F2CA663A4266A7429DA4C", "BANK_SLIP_NOT_FOUND"))
+
+	records, err := suite.repository.ListByReturnFileId("return1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []*bankSlipEntities.UnmatchedReturnRecord{
+		newTestUnmatchedReturnRecord(2, bankSlipEntities.UnmatchedReasonBankSlipNotFound),
+	}, records)
+}
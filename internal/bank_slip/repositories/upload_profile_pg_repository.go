@@ -0,0 +1,94 @@
+package bank_slip
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	entities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type UploadProfilePgRepository struct {
+	db *sql.DB
+}
+
+func NewUploadProfilePgRepository(db *sql.DB) *UploadProfilePgRepository {
+	return &UploadProfilePgRepository{db: db}
+}
+
+func (r *UploadProfilePgRepository) Insert(uploadProfile *entities.UploadProfile) error {
+	query := "INSERT INTO upload_profile (name, columns, date_format, decimal_separator, due_date_policy) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (name) DO NOTHING returning id"
+
+	columns, err := json.Marshal(uploadProfile.Columns)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRow(
+		query,
+		uploadProfile.Name,
+		columns,
+		uploadProfile.DateFormat,
+		uploadProfile.DecimalSeparator,
+		uploadProfile.DueDatePolicy,
+	).Scan(&uploadProfile.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.ErrUploadProfileAlreadyExists
+	}
+	return err
+}
+
+func (r *UploadProfilePgRepository) GetByName(name string) (*entities.UploadProfile, error) {
+	query := "SELECT id, name, columns, date_format, decimal_separator, due_date_policy FROM upload_profile WHERE name = $1"
+
+	uploadProfile, err := r.scanUploadProfile(r.db.QueryRow(query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entities.ErrUploadProfileNotFound
+	}
+	return uploadProfile, err
+}
+
+func (r *UploadProfilePgRepository) List() ([]*entities.UploadProfile, error) {
+	query := "SELECT id, name, columns, date_format, decimal_separator, due_date_policy FROM upload_profile ORDER BY name"
+
+	queryResult, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer queryResult.Close()
+
+	uploadProfiles := []*entities.UploadProfile{}
+	for queryResult.Next() {
+		uploadProfile, err := r.scanUploadProfile(queryResult)
+		if err != nil {
+			return nil, err
+		}
+		uploadProfiles = append(uploadProfiles, uploadProfile)
+	}
+	return uploadProfiles, queryResult.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *UploadProfilePgRepository) scanUploadProfile(row rowScanner) (*entities.UploadProfile, error) {
+	var uploadProfile entities.UploadProfile
+	var columns []byte
+
+	err := row.Scan(
+		&uploadProfile.ID,
+		&uploadProfile.Name,
+		&columns,
+		&uploadProfile.DateFormat,
+		&uploadProfile.DecimalSeparator,
+		&uploadProfile.DueDatePolicy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(columns, &uploadProfile.Columns); err != nil {
+		return nil, err
+	}
+	return &uploadProfile, nil
+}
@@ -0,0 +1,92 @@
+package bank_slip
+
+import (
+	"database/sql"
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type UploadProfilePgRepositoryTestSuite struct {
+	suite.Suite
+	repository *UploadProfilePgRepository
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+}
+
+func (testSuit *UploadProfilePgRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(testSuit.T(), err)
+	testSuit.db = db
+	testSuit.mock = mock
+	testSuit.repository = NewUploadProfilePgRepository(db)
+}
+
+func TestUploadProfilePgRepository(t *testing.T) {
+	suite.Run(t, new(UploadProfilePgRepositoryTestSuite))
+}
+
+const defaultProfileColumns = `{"debtAmount":["debtAmount"],"debtDueDate":["debtDueDate"],"debtId":["debtId"],"governmentId":["governmentId"],"userEmail":["email"],"userName":["name"]}`
+
+func (suite *UploadProfilePgRepositoryTestSuite) TestInsert() {
+	uploadProfile := bankSlipEntities.DefaultUploadProfile()
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO upload_profile (name, columns, date_format, decimal_separator, due_date_policy) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (name) DO NOTHING returning id")).
+		WithArgs("default", []byte(defaultProfileColumns), "YYYY-MM-DD", ".", bankSlipEntities.DueDatePolicyKeep).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("profile_id"))
+
+	err := suite.repository.Insert(uploadProfile)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "profile_id", uploadProfile.ID)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UploadProfilePgRepositoryTestSuite) TestInsertShouldReturnAlreadyExistsOnConflict() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO upload_profile")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := suite.repository.Insert(bankSlipEntities.DefaultUploadProfile())
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrUploadProfileAlreadyExists)
+}
+
+func (suite *UploadProfilePgRepositoryTestSuite) TestGetByName() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, columns, date_format, decimal_separator, due_date_policy FROM upload_profile WHERE name = $1")).
+		WithArgs("default").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "columns", "date_format", "decimal_separator", "due_date_policy"}).
+			AddRow("profile_id", "default", []byte(defaultProfileColumns), "YYYY-MM-DD", ".", "KEEP"))
+
+	uploadProfile, err := suite.repository.GetByName("default")
+	assert.NoError(suite.T(), err)
+
+	expected := bankSlipEntities.DefaultUploadProfile()
+	expected.ID = "profile_id"
+	assert.Equal(suite.T(), expected, uploadProfile)
+}
+
+func (suite *UploadProfilePgRepositoryTestSuite) TestGetByNameShouldReturnNotFound() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM upload_profile WHERE name = $1")).
+		WithArgs("unknown").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "columns", "date_format", "decimal_separator", "due_date_policy"}))
+
+	uploadProfile, err := suite.repository.GetByName("unknown")
+	assert.Nil(suite.T(), uploadProfile)
+	assert.ErrorIs(suite.T(), err, bankSlipEntities.ErrUploadProfileNotFound)
+}
+
+func (suite *UploadProfilePgRepositoryTestSuite) TestList() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM upload_profile ORDER BY name")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "columns", "date_format", "decimal_separator", "due_date_policy"}).
+			AddRow("first_id", "br", []byte(`{"userName":["nome"]}`), "DD/MM/YYYY", ",", "REJECT").
+			AddRow("second_id", "default", []byte(defaultProfileColumns), "YYYY-MM-DD", ".", "KEEP"))
+
+	uploadProfiles, err := suite.repository.List()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), uploadProfiles, 2)
+	assert.Equal(suite.T(), []string{"nome"}, uploadProfiles[0].Columns[bankSlipEntities.BankSlipFieldUserName])
+	assert.Equal(suite.T(), bankSlipEntities.DueDatePolicyReject, uploadProfiles[0].DueDatePolicy)
+	assert.Equal(suite.T(), "default", uploadProfiles[1].Name)
+}
@@ -13,6 +13,17 @@ func RegisterRoutes(r *httprouter.Router) {
 	factory := NewBankSlipFactory()
 
 	receiveUploadServiceFactory := factory.MakeReceiveUploadController()
+	streamUploadController := factory.MakeStreamUploadController()
+	validateUploadController := factory.MakeValidateUploadController()
+	bankSlipFileController := factory.MakeBankSlipFileController()
+	uploadProfileController := factory.MakeUploadProfileController()
+	fixedWidthLayoutController := factory.MakeFixedWidthLayoutController()
+	bankSlipController := factory.MakeBankSlipController()
+	installmentController := factory.MakeInstallmentController()
+	subscriptionController := factory.MakeSubscriptionController()
+	beneficiaryController := factory.MakeBeneficiaryController()
+	remittanceController := factory.MakeRemittanceController()
+	returnFileController := factory.MakeReturnFileController()
 
 	// Wrap all routes with CORS middleware
 	r.HandlerFunc(
@@ -20,4 +31,154 @@ func RegisterRoutes(r *httprouter.Router) {
 		"/upload/bank-slip/file",
 		receiveUploadServiceFactory.UploadBankSlipFileHandler,
 	)
+	r.HandlerFunc(
+		http.MethodPost,
+		"/upload/bank-slip/file/stream",
+		streamUploadController.StreamBankSlipFileHandler,
+	)
+	r.HandlerFunc(
+		http.MethodPost,
+		"/upload/bank-slip/file/validate",
+		validateUploadController.ValidateBankSlipFileHandler,
+	)
+	r.HandlerFunc(
+		http.MethodPost,
+		"/upload/bank-slip/return",
+		returnFileController.UploadReturnFileHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/upload/bank-slip/return/:id",
+		returnFileController.GetReturnFileHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/upload/bank-slip/return/:id/unmatched",
+		returnFileController.ListUnmatchedRecordsHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/upload/bank-slip/file",
+		bankSlipFileController.ListBankSlipFilesHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/upload/bank-slip/file/:id",
+		bankSlipFileController.GetBankSlipFileHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/upload/bank-slip/file/:id/rejected-rows",
+		bankSlipFileController.DownloadRejectedRowsHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/upload/bank-slip/file/:id/original",
+		bankSlipFileController.DownloadOriginalHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/upload/bank-slip/file/:id/pdfs",
+		bankSlipFileController.DownloadPdfsHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/bank-slips/:debtId/pdf",
+		bankSlipController.DownloadPdfHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/bank-slips/:debtId/history",
+		bankSlipController.GetHistoryHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/bank-slips/:debtId/amount",
+		bankSlipController.GetAmountHandler,
+	)
+	r.HandlerFunc(
+		http.MethodPost,
+		"/bank-slips/:debtId/billing",
+		bankSlipController.RetryBillingHandler,
+	)
+	r.HandlerFunc(
+		http.MethodPost,
+		"/bank-slips/:debtId/installments",
+		installmentController.CreateInstallmentsHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/bank-slips/:debtId/installments",
+		installmentController.ListInstallmentsHandler,
+	)
+	r.HandlerFunc(
+		http.MethodPost,
+		"/subscriptions",
+		subscriptionController.CreateSubscriptionHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/subscriptions",
+		subscriptionController.ListSubscriptionsHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/subscriptions/:id",
+		subscriptionController.GetSubscriptionHandler,
+	)
+	r.HandlerFunc(
+		http.MethodPut,
+		"/subscriptions/:id",
+		subscriptionController.UpdateSubscriptionHandler,
+	)
+	r.HandlerFunc(
+		http.MethodDelete,
+		"/subscriptions/:id",
+		subscriptionController.CancelSubscriptionHandler,
+	)
+	r.HandlerFunc(
+		http.MethodPost,
+		"/upload/profiles",
+		uploadProfileController.CreateUploadProfileHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/upload/profiles",
+		uploadProfileController.ListUploadProfilesHandler,
+	)
+	r.HandlerFunc(
+		http.MethodPost,
+		"/upload/fixed-width-layouts",
+		fixedWidthLayoutController.CreateFixedWidthLayoutHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/upload/fixed-width-layouts",
+		fixedWidthLayoutController.ListFixedWidthLayoutsHandler,
+	)
+	r.HandlerFunc(
+		http.MethodPost,
+		"/beneficiaries",
+		beneficiaryController.CreateBeneficiaryHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/beneficiaries",
+		beneficiaryController.ListBeneficiariesHandler,
+	)
+	r.HandlerFunc(
+		http.MethodPost,
+		"/remittances",
+		remittanceController.CreateRemittanceHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/remittances",
+		remittanceController.ListRemittancesHandler,
+	)
+	r.HandlerFunc(
+		http.MethodGet,
+		"/remittances/:id/file",
+		remittanceController.DownloadRemittanceHandler,
+	)
 }
@@ -1,16 +1,42 @@
 package bank_slip
 
 import (
+	"log"
+	"os"
 	bankSlipConsumer "performatic-file-processor/internal/bank_slip/consumers"
 	bankSlipControllers "performatic-file-processor/internal/bank_slip/controllers"
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
 	bankSlipProvider "performatic-file-processor/internal/bank_slip/providers"
 	bankSlipRepositories "performatic-file-processor/internal/bank_slip/repositories"
 	bankSlipServices "performatic-file-processor/internal/bank_slip/services"
+	"performatic-file-processor/internal/calendar"
 	database "performatic-file-processor/internal/database"
-	"performatic-file-processor/internal/handler"
 	"performatic-file-processor/internal/infra/billing"
 	"performatic-file-processor/internal/infra/email"
+	"performatic-file-processor/internal/jobs"
 	"performatic-file-processor/internal/kafka"
+	"performatic-file-processor/internal/pix"
+	"performatic-file-processor/internal/storage"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxUploadSize      = 10 << 30
+	expiredUploadsPurgePeriod = time.Hour
+	dunningPeriod             = time.Hour
+	dunningBatchSize          = 500
+	defaultDunningGraceDays   = 30
+	installmentIssuancePeriod = time.Hour
+	installmentIssuanceBatch  = 500
+	// defaultInstallmentIssuanceDays bills installments a month before they are
+	// due.
+	defaultInstallmentIssuanceDays = 30
+	subscriptionIssuancePeriod     = time.Hour
+	subscriptionIssuanceBatch      = 500
+	// defaultSubscriptionIssuanceDays bills the bank slip of a subscription
+	// ten days before it's due.
+	defaultSubscriptionIssuanceDays = 10
 )
 
 type BankSlipFactory struct{}
@@ -24,43 +50,258 @@ func (f *BankSlipFactory) MakeReceiveUploadController() *bankSlipControllers.Rec
 
 	bankSlipFileRepository := bankSlipRepositories.NewBankSlipFilePgRepository(db)
 	bankSlipRepository := bankSlipRepositories.NewBankSlipPgRepository(db)
-	multipartFileHandler := handler.NewMultipartFileHandler()
+	uploadProfileRepository := bankSlipRepositories.NewUploadProfilePgRepository(db)
+	fixedWidthLayoutRepository := bankSlipRepositories.NewFixedWidthLayoutPgRepository(db)
+	beneficiaryRepository := bankSlipRepositories.NewBeneficiaryPgRepository(db)
+	fileStorage := storage.GetInstance()
 
 	kafkaProducer := kafka.NewKafkaProducer()
 
 	receiveUploadService := bankSlipServices.NewReceiveUploadService(
 		bankSlipRepository,
 		bankSlipFileRepository,
-		multipartFileHandler,
+		uploadProfileRepository,
+		fixedWidthLayoutRepository,
+		beneficiaryRepository,
+		fileStorage,
 		kafkaProducer,
+		jobs.GetInstance(),
+		duplicateUploadPolicy(),
 		1024*64,
 		20,
 	)
-	receiveUploadController := bankSlipControllers.NewReceiveUploadController(receiveUploadService)
+	receiveUploadController := bankSlipControllers.NewReceiveUploadController(receiveUploadService, maxUploadSize())
 	return receiveUploadController
 }
 
-func (f *BankSlipFactory) MakeBankSlipRowsConsumer(processors int) *bankSlipConsumer.BankSlipRowsConsumer {
+func (f *BankSlipFactory) MakeStreamUploadController() *bankSlipControllers.StreamUploadController {
+	db := database.GetInstance()
+
+	bankSlipFileRepository := bankSlipRepositories.NewBankSlipFilePgRepository(db)
+	uploadProfileRepository := bankSlipRepositories.NewUploadProfilePgRepository(db)
+	fixedWidthLayoutRepository := bankSlipRepositories.NewFixedWidthLayoutPgRepository(db)
+	beneficiaryRepository := bankSlipRepositories.NewBeneficiaryPgRepository(db)
+	fileStorage := storage.GetInstance()
+
+	kafkaProducer := kafka.NewKafkaProducer()
+
+	streamUploadService := bankSlipServices.NewStreamUploadService(
+		bankSlipFileRepository,
+		uploadProfileRepository,
+		fixedWidthLayoutRepository,
+		beneficiaryRepository,
+		fileStorage,
+		kafkaProducer,
+		duplicateUploadPolicy(),
+		os.Getenv("UPLOAD_ARCHIVE_ENABLED") == "true",
+		1024*64,
+		20,
+	)
+	return bankSlipControllers.NewStreamUploadController(streamUploadService, maxUploadSize())
+}
+
+func (f *BankSlipFactory) MakeValidateUploadController() *bankSlipControllers.ValidateUploadController {
+	db := database.GetInstance()
+
+	uploadProfileRepository := bankSlipRepositories.NewUploadProfilePgRepository(db)
+	fixedWidthLayoutRepository := bankSlipRepositories.NewFixedWidthLayoutPgRepository(db)
+
+	validateUploadService := bankSlipServices.NewValidateUploadService(
+		uploadProfileRepository,
+		fixedWidthLayoutRepository,
+		calendar.GetInstance(),
+		1024*64,
+		bankSlipEntities.DefaultMaxReportedRowErrors,
+	)
+	return bankSlipControllers.NewValidateUploadController(validateUploadService)
+}
 
+func (f *BankSlipFactory) MakeBankSlipFileController() *bankSlipControllers.BankSlipFileController {
 	db := database.GetInstance()
 
 	bankSlipFileRepository := bankSlipRepositories.NewBankSlipFilePgRepository(db)
 	bankSlipRepository := bankSlipRepositories.NewBankSlipPgRepository(db)
+	bankSlipRejectedRowRepository := bankSlipRepositories.NewBankSlipRejectedRowPgRepository(db)
+	beneficiaryRepository := bankSlipRepositories.NewBeneficiaryPgRepository(db)
 
-	emailService := email.NewFooSendMailService()
-	billingService := billing.NewFooBillingService()
+	getBankSlipFileService := bankSlipServices.NewGetBankSlipFileService(bankSlipFileRepository)
+	listBankSlipFilesService := bankSlipServices.NewListBankSlipFilesService(bankSlipFileRepository)
+	getBankSlipRejectedRowsService := bankSlipServices.NewGetBankSlipRejectedRowsService(bankSlipFileRepository, bankSlipRejectedRowRepository)
+	getBankSlipFileOriginalService := bankSlipServices.NewGetBankSlipFileOriginalService(bankSlipFileRepository, storage.GetInstance())
+	exportBankSlipFilePdfsService := bankSlipServices.NewExportBankSlipFilePdfsService(bankSlipRepository, beneficiaryRepository)
 
-	generateBillingAndSentEmailProvider := bankSlipProvider.NewGenerateBillingAndSentEmailProvider(
-		emailService,
-		billingService,
+	return bankSlipControllers.NewBankSlipFileController(
+		getBankSlipFileService,
+		listBankSlipFilesService,
+		getBankSlipRejectedRowsService,
+		getBankSlipFileOriginalService,
+		exportBankSlipFilePdfsService,
 	)
+}
+
+func (f *BankSlipFactory) MakeBankSlipController() *bankSlipControllers.BankSlipController {
+	db := database.GetInstance()
+
+	bankSlipRepository := bankSlipRepositories.NewBankSlipPgRepository(db)
+	beneficiaryRepository := bankSlipRepositories.NewBeneficiaryPgRepository(db)
+	bankSlipStatusHistoryRepository := bankSlipRepositories.NewBankSlipStatusHistoryPgRepository(db)
+
+	getBankSlipPdfService := bankSlipServices.NewGetBankSlipPdfService(bankSlipRepository, beneficiaryRepository)
+	getBankSlipStatusHistoryService := bankSlipServices.NewGetBankSlipStatusHistoryService(bankSlipRepository, bankSlipStatusHistoryRepository)
+	getBankSlipAmountService := bankSlipServices.NewGetBankSlipAmountService(bankSlipRepository, calendar.GetInstance())
+	retryBankSlipBillingService := bankSlipServices.NewRetryBankSlipBillingService(bankSlipRepository, f.makeGenerateBillingAndSentEmailProvider())
+
+	return bankSlipControllers.NewBankSlipController(
+		getBankSlipPdfService,
+		getBankSlipStatusHistoryService,
+		getBankSlipAmountService,
+		retryBankSlipBillingService,
+	)
+}
+
+func (f *BankSlipFactory) MakeInstallmentController() *bankSlipControllers.InstallmentController {
+	db := database.GetInstance()
+
+	bankSlipRepository := bankSlipRepositories.NewBankSlipPgRepository(db)
+	installmentRepository := bankSlipRepositories.NewInstallmentPgRepository(db)
+
+	createInstallmentsService := bankSlipServices.NewCreateInstallmentsService(
+		bankSlipRepository,
+		installmentRepository,
+		f.makeGenerateBillingAndSentEmailProvider(),
+		calendar.GetInstance(),
+		installmentIssuanceDays(),
+	)
+	listInstallmentsService := bankSlipServices.NewListInstallmentsService(installmentRepository)
+
+	return bankSlipControllers.NewInstallmentController(
+		createInstallmentsService,
+		listInstallmentsService,
+	)
+}
+
+func (f *BankSlipFactory) MakeSubscriptionController() *bankSlipControllers.SubscriptionController {
+	db := database.GetInstance()
+
+	subscriptionRepository := bankSlipRepositories.NewSubscriptionPgRepository(db)
+	beneficiaryRepository := bankSlipRepositories.NewBeneficiaryPgRepository(db)
+
+	return bankSlipControllers.NewSubscriptionController(
+		bankSlipServices.NewCreateSubscriptionService(subscriptionRepository, beneficiaryRepository, calendar.GetInstance()),
+		bankSlipServices.NewListSubscriptionsService(subscriptionRepository),
+		bankSlipServices.NewGetSubscriptionService(subscriptionRepository),
+		bankSlipServices.NewUpdateSubscriptionService(subscriptionRepository, beneficiaryRepository, calendar.GetInstance()),
+		bankSlipServices.NewCancelSubscriptionService(subscriptionRepository),
+	)
+}
+
+func (f *BankSlipFactory) MakeUploadProfileController() *bankSlipControllers.UploadProfileController {
+	db := database.GetInstance()
+
+	uploadProfileRepository := bankSlipRepositories.NewUploadProfilePgRepository(db)
+
+	createUploadProfileService := bankSlipServices.NewCreateUploadProfileService(uploadProfileRepository)
+	listUploadProfilesService := bankSlipServices.NewListUploadProfilesService(uploadProfileRepository)
+
+	return bankSlipControllers.NewUploadProfileController(
+		createUploadProfileService,
+		listUploadProfilesService,
+	)
+}
+
+func (f *BankSlipFactory) MakeFixedWidthLayoutController() *bankSlipControllers.FixedWidthLayoutController {
+	db := database.GetInstance()
+
+	fixedWidthLayoutRepository := bankSlipRepositories.NewFixedWidthLayoutPgRepository(db)
+
+	createFixedWidthLayoutService := bankSlipServices.NewCreateFixedWidthLayoutService(fixedWidthLayoutRepository)
+	listFixedWidthLayoutsService := bankSlipServices.NewListFixedWidthLayoutsService(fixedWidthLayoutRepository)
+
+	return bankSlipControllers.NewFixedWidthLayoutController(
+		createFixedWidthLayoutService,
+		listFixedWidthLayoutsService,
+	)
+}
+
+func (f *BankSlipFactory) MakeBeneficiaryController() *bankSlipControllers.BeneficiaryController {
+	db := database.GetInstance()
+
+	beneficiaryRepository := bankSlipRepositories.NewBeneficiaryPgRepository(db)
+
+	createBeneficiaryService := bankSlipServices.NewCreateBeneficiaryService(beneficiaryRepository)
+	listBeneficiariesService := bankSlipServices.NewListBeneficiariesService(beneficiaryRepository)
+
+	return bankSlipControllers.NewBeneficiaryController(
+		createBeneficiaryService,
+		listBeneficiariesService,
+	)
+}
+
+func (f *BankSlipFactory) MakeRemittanceController() *bankSlipControllers.RemittanceController {
+	db := database.GetInstance()
+
+	beneficiaryRepository := bankSlipRepositories.NewBeneficiaryPgRepository(db)
+	bankSlipFileRepository := bankSlipRepositories.NewBankSlipFilePgRepository(db)
+	remittanceRepository := bankSlipRepositories.NewRemittancePgRepository(db)
+
+	createRemittanceService := bankSlipServices.NewCreateRemittanceService(beneficiaryRepository, bankSlipFileRepository, remittanceRepository)
+	listRemittancesService := bankSlipServices.NewListRemittancesService(remittanceRepository)
+	getRemittanceService := bankSlipServices.NewGetRemittanceService(remittanceRepository)
+
+	return bankSlipControllers.NewRemittanceController(
+		createRemittanceService,
+		listRemittancesService,
+		getRemittanceService,
+	)
+}
+
+func (f *BankSlipFactory) MakeReturnFileController() *bankSlipControllers.ReturnFileController {
+	db := database.GetInstance()
+
+	returnFileRepository := bankSlipRepositories.NewBankSlipReturnFilePgRepository(db)
+	unmatchedReturnRecordRepository := bankSlipRepositories.NewUnmatchedReturnRecordPgRepository(db)
+	beneficiaryRepository := bankSlipRepositories.NewBeneficiaryPgRepository(db)
+
+	kafkaProducer := kafka.NewKafkaProducer()
+
+	receiveReturnFileService := bankSlipServices.NewReceiveReturnFileService(
+		returnFileRepository,
+		beneficiaryRepository,
+		storage.GetInstance(),
+		kafkaProducer,
+		jobs.GetInstance(),
+		1024*64,
+		20,
+	)
+	getReturnFileService := bankSlipServices.NewGetReturnFileService(returnFileRepository)
+	getUnmatchedReturnRecordsService := bankSlipServices.NewGetUnmatchedReturnRecordsService(returnFileRepository, unmatchedReturnRecordRepository)
+
+	return bankSlipControllers.NewReturnFileController(
+		receiveReturnFileService,
+		getReturnFileService,
+		getUnmatchedReturnRecordsService,
+		maxUploadSize(),
+	)
+}
+
+func (f *BankSlipFactory) MakeBankSlipRowsConsumer(processors int) *bankSlipConsumer.BankSlipRowsConsumer {
+
+	db := database.GetInstance()
+
+	bankSlipFileRepository := bankSlipRepositories.NewBankSlipFilePgRepository(db)
+	bankSlipRepository := bankSlipRepositories.NewBankSlipPgRepository(db)
+	bankSlipRejectedRowRepository := bankSlipRepositories.NewBankSlipRejectedRowPgRepository(db)
+	generateBillingAndSentEmailProvider := f.makeGenerateBillingAndSentEmailProvider()
 
 	kafkaConsumer := kafka.NewKafkaConsumer()
 
 	bankSlipRowsProcessor := bankSlipServices.NewProcessBankSlipRowsService(
 		bankSlipFileRepository,
 		bankSlipRepository,
+		bankSlipRejectedRowRepository,
 		generateBillingAndSentEmailProvider,
+		calendar.GetInstance(),
+		installmentIssuanceDays(),
 	)
 
 	consumer := bankSlipConsumer.NewBankSlipRowsConsumer(
@@ -70,3 +311,182 @@ func (f *BankSlipFactory) MakeBankSlipRowsConsumer(processors int) *bankSlipCons
 	)
 	return consumer
 }
+
+func (f *BankSlipFactory) makeGenerateBillingAndSentEmailProvider() *bankSlipProvider.GenerateBillingAndSentEmailProviderImpl {
+	beneficiaryRepository := bankSlipRepositories.NewBeneficiaryPgRepository(database.GetInstance())
+
+	emailService := email.NewFooSendMailService(calendar.GetInstance())
+	billingService := billing.NewBoletoBillingService(beneficiaryRepository, pixMerchant())
+
+	return bankSlipProvider.NewGenerateBillingAndSentEmailProvider(
+		emailService,
+		billingService,
+	)
+}
+
+func (f *BankSlipFactory) MakeReturnRecordsConsumer(processors int) *bankSlipConsumer.ReturnRecordsConsumer {
+	db := database.GetInstance()
+
+	returnFileRepository := bankSlipRepositories.NewBankSlipReturnFilePgRepository(db)
+	bankSlipRepository := bankSlipRepositories.NewBankSlipPgRepository(db)
+	unmatchedReturnRecordRepository := bankSlipRepositories.NewUnmatchedReturnRecordPgRepository(db)
+
+	returnRecordsProcessor := bankSlipServices.NewProcessReturnRecordsService(
+		returnFileRepository,
+		bankSlipRepository,
+		unmatchedReturnRecordRepository,
+	)
+
+	return bankSlipConsumer.NewReturnRecordsConsumer(
+		returnRecordsProcessor,
+		kafka.NewKafkaConsumer(),
+		processors,
+	)
+}
+
+func (f *BankSlipFactory) MakePurgeExpiredUploadsService() *bankSlipServices.PurgeExpiredUploadsService {
+	return bankSlipServices.NewPurgeExpiredUploadsService(storage.GetInstance(), expiredUploadsPurgePeriod)
+}
+
+func (f *BankSlipFactory) MakeDunningService() *bankSlipServices.DunningService {
+	db := database.GetInstance()
+
+	dunningRepository := bankSlipRepositories.NewDunningPgRepository(db)
+	emailService := email.NewFooSendMailService(calendar.GetInstance())
+
+	return bankSlipServices.NewDunningService(
+		dunningRepository,
+		emailService,
+		calendar.GetInstance(),
+		dunningSchedule(dunningGracePeriodDays()),
+		dunningGracePeriodDays(),
+		dunningPeriod,
+		dunningBatchSize,
+	)
+}
+
+func (f *BankSlipFactory) MakeIssueScheduledInstallmentsService() *bankSlipServices.IssueScheduledInstallmentsService {
+	db := database.GetInstance()
+
+	return bankSlipServices.NewIssueScheduledInstallmentsService(
+		bankSlipRepositories.NewInstallmentPgRepository(db),
+		bankSlipRepositories.NewBankSlipPgRepository(db),
+		f.makeGenerateBillingAndSentEmailProvider(),
+		installmentIssuanceDays(),
+		installmentIssuancePeriod,
+		installmentIssuanceBatch,
+	)
+}
+
+func (f *BankSlipFactory) MakeIssueSubscriptionBankSlipsService() *bankSlipServices.IssueSubscriptionBankSlipsService {
+	db := database.GetInstance()
+
+	return bankSlipServices.NewIssueSubscriptionBankSlipsService(
+		bankSlipRepositories.NewSubscriptionPgRepository(db),
+		bankSlipRepositories.NewBankSlipPgRepository(db),
+		f.makeGenerateBillingAndSentEmailProvider(),
+		calendar.GetInstance(),
+		subscriptionIssuanceDays(),
+		subscriptionIssuancePeriod,
+		subscriptionIssuanceBatch,
+	)
+}
+
+// installmentIssuanceDays reads INSTALLMENT_ISSUANCE_DAYS, the days before
+// its due date an installment is billed on.
+func installmentIssuanceDays() int {
+	value := os.Getenv("INSTALLMENT_ISSUANCE_DAYS")
+	if value == "" {
+		return defaultInstallmentIssuanceDays
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		log.Fatalf("Invalid INSTALLMENT_ISSUANCE_DAYS: %s", value)
+	}
+	return days
+}
+
+// subscriptionIssuanceDays reads SUBSCRIPTION_ISSUANCE_DAYS, the days before
+// its due date the bank slip of a subscription is issued on.
+func subscriptionIssuanceDays() int {
+	value := os.Getenv("SUBSCRIPTION_ISSUANCE_DAYS")
+	if value == "" {
+		return defaultSubscriptionIssuanceDays
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		log.Fatalf("Invalid SUBSCRIPTION_ISSUANCE_DAYS: %s", value)
+	}
+	return days
+}
+
+// dunningSchedule reads DUNNING_SCHEDULE, the days from the due date the
+// payers are emailed on, as "D-3,D+1,D+7". An empty schedule sends no email.
+func dunningSchedule(gracePeriodDays int) []bankSlipEntities.DunningStep {
+	value, ok := os.LookupEnv("DUNNING_SCHEDULE")
+	if !ok {
+		value = bankSlipEntities.DefaultDunningSchedule
+	}
+	schedule, err := bankSlipEntities.ParseDunningSchedule(value, gracePeriodDays)
+	if err != nil {
+		log.Fatalf("Invalid DUNNING_SCHEDULE: %v", err)
+	}
+	return schedule
+}
+
+// dunningGracePeriodDays reads DUNNING_GRACE_PERIOD_DAYS, the days overdue
+// bank slips wait to expire.
+func dunningGracePeriodDays() int {
+	value := os.Getenv("DUNNING_GRACE_PERIOD_DAYS")
+	if value == "" {
+		return defaultDunningGraceDays
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		log.Fatalf("Invalid DUNNING_GRACE_PERIOD_DAYS: %s", value)
+	}
+	return days
+}
+
+// duplicateUploadPolicy reads UPLOAD_DUPLICATE_POLICY, re-uploads are rejected
+// unless another policy is configured.
+func duplicateUploadPolicy() bankSlipEntities.DuplicateUploadPolicy {
+	value := os.Getenv("UPLOAD_DUPLICATE_POLICY")
+	if value == "" {
+		return bankSlipEntities.DuplicateUploadPolicyReject
+	}
+	policy, err := bankSlipEntities.ParseDuplicateUploadPolicy(value)
+	if err != nil {
+		log.Fatalf("Invalid UPLOAD_DUPLICATE_POLICY: %v", err)
+	}
+	return policy
+}
+
+// pixMerchant reads who receives Pix payments from PIX_KEY or
+// PIX_LOCATION_URL, PIX_MERCHANT_NAME and PIX_MERCHANT_CITY. Bank slips get no
+// Pix BR Code when neither a key nor a location URL is configured.
+func pixMerchant() *pix.Merchant {
+	key := os.Getenv("PIX_KEY")
+	locationURL := os.Getenv("PIX_LOCATION_URL")
+	if key == "" && locationURL == "" {
+		return nil
+	}
+	merchant, err := pix.NewMerchant(key, locationURL, os.Getenv("PIX_MERCHANT_NAME"), os.Getenv("PIX_MERCHANT_CITY"))
+	if err != nil {
+		log.Fatalf("Invalid Pix merchant: %v", err)
+	}
+	return &merchant
+}
+
+// maxUploadSize reads UPLOAD_MAX_SIZE_BYTES, 0 disables the limit.
+func maxUploadSize() int64 {
+	value := os.Getenv("UPLOAD_MAX_SIZE_BYTES")
+	if value == "" {
+		return defaultMaxUploadSize
+	}
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		log.Fatalf("Invalid UPLOAD_MAX_SIZE_BYTES: %s", value)
+	}
+	return size
+}
@@ -0,0 +1,33 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type CancelSubscriptionServiceInterface interface {
+	Execute(id string) (*bankSlipEntities.Subscription, error)
+}
+
+type CancelSubscriptionService struct {
+	subscriptionRepository bankSlipEntities.SubscriptionRepository
+}
+
+func NewCancelSubscriptionService(subscriptionRepo bankSlipEntities.SubscriptionRepository) *CancelSubscriptionService {
+	return &CancelSubscriptionService{subscriptionRepository: subscriptionRepo}
+}
+
+// Execute stops the subscription from issuing bank slips, the ones already
+// issued are still due.
+func (s *CancelSubscriptionService) Execute(id string) (*bankSlipEntities.Subscription, error) {
+	subscription, err := s.subscriptionRepository.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := subscription.Cancel(); err != nil {
+		return nil, err
+	}
+	if err := s.subscriptionRepository.Update(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
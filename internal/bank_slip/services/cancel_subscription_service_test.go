@@ -0,0 +1,38 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCancelSubscriptionService_ShouldCancelActiveSubscription(t *testing.T) {
+	subscriptionRepository := new(bankSlipMocks.SubscriptionRepositoryMock)
+	service := NewCancelSubscriptionService(subscriptionRepository)
+
+	subscription := newDueSubscription("6b1f9c2e-3d4a-4f5b-8c6d-7e8f9a0b1c2d")
+	subscriptionRepository.On("GetById", subscription.ID).Return(subscription, nil).Once()
+	subscriptionRepository.On("Update", subscription).Return(nil).Once()
+
+	cancelled, err := service.Execute(subscription.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, bankSlipEntities.SubscriptionStatusCancelled, cancelled.Status)
+	subscriptionRepository.AssertExpectations(t)
+}
+
+func TestCancelSubscriptionService_ShouldFailWhenSubscriptionEnded(t *testing.T) {
+	subscriptionRepository := new(bankSlipMocks.SubscriptionRepositoryMock)
+	service := NewCancelSubscriptionService(subscriptionRepository)
+
+	subscription := newDueSubscription("6b1f9c2e-3d4a-4f5b-8c6d-7e8f9a0b1c2d")
+	subscription.Status = bankSlipEntities.SubscriptionStatusEnded
+	subscriptionRepository.On("GetById", subscription.ID).Return(subscription, nil).Once()
+
+	_, err := service.Execute(subscription.ID)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrSubscriptionNotActive)
+	subscriptionRepository.AssertNotCalled(t, "Update", mock.Anything)
+}
@@ -0,0 +1,40 @@
+package bank_slip
+
+import (
+	"fmt"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type CreateBeneficiaryServiceInterface interface {
+	Execute(name, document, bankCode, agency, account, wallet, agreementCode string, chargePolicy bankSlipEntities.ChargePolicy) (*bankSlipEntities.Beneficiary, error)
+}
+
+type CreateBeneficiaryService struct {
+	beneficiaryRepository bankSlipEntities.BeneficiaryRepository
+}
+
+func NewCreateBeneficiaryService(
+	beneficiaryRepo bankSlipEntities.BeneficiaryRepository,
+) *CreateBeneficiaryService {
+	return &CreateBeneficiaryService{
+		beneficiaryRepository: beneficiaryRepo,
+	}
+}
+
+func (s *CreateBeneficiaryService) Execute(name, document, bankCode, agency, account, wallet, agreementCode string, chargePolicy bankSlipEntities.ChargePolicy) (*bankSlipEntities.Beneficiary, error) {
+	beneficiary, err := bankSlipEntities.NewBeneficiary(name, document, bankCode, agency, account, wallet, agreementCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := chargePolicy.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", bankSlipEntities.ErrInvalidBeneficiary, err)
+	}
+	beneficiary.ChargePolicy = chargePolicy
+
+	err = s.beneficiaryRepository.Insert(beneficiary)
+	if err != nil {
+		return nil, err
+	}
+	return beneficiary, nil
+}
@@ -0,0 +1,67 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateBeneficiaryService_ShouldInsertValidBeneficiary(t *testing.T) {
+	repository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewCreateBeneficiaryService(repository)
+
+	repository.On("Insert", mock.Anything).Return(nil).Once()
+
+	beneficiary, err := service.Execute("Performatic", "11.222.333/0001-81", "237", "1234", "12345", "9", "", bankSlipEntities.ChargePolicy{})
+	assert.NoError(t, err)
+	assert.Equal(t, bankSlipEntities.GovernmentId("11222333000181"), beneficiary.Document)
+	assert.Equal(t, "0012345", beneficiary.Account)
+	repository.AssertCalled(t, "Insert", beneficiary)
+}
+
+func TestCreateBeneficiaryService_ShouldNotInsertInvalidBeneficiary(t *testing.T) {
+	repository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewCreateBeneficiaryService(repository)
+
+	_, err := service.Execute("Performatic", "11.222.333/0001-81", "2370", "1234", "12345", "09", "", bankSlipEntities.ChargePolicy{})
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInvalidBeneficiary)
+	repository.AssertNotCalled(t, "Insert", mock.Anything)
+}
+
+func TestCreateBeneficiaryService_ShouldReturnRepositoryError(t *testing.T) {
+	repository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewCreateBeneficiaryService(repository)
+
+	repository.On("Insert", mock.Anything).Return(bankSlipEntities.ErrBeneficiaryAlreadyExists).Once()
+
+	beneficiary, err := service.Execute("Performatic", "11222333000181", "237", "1234", "12345", "09", "", bankSlipEntities.ChargePolicy{})
+	assert.Nil(t, beneficiary)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrBeneficiaryAlreadyExists)
+}
+
+func TestCreateBeneficiaryService_ShouldKeepChargePolicy(t *testing.T) {
+	repository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewCreateBeneficiaryService(repository)
+	chargePolicy := bankSlipEntities.ChargePolicy{LateFee: &bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindPercent, Value: 200}}
+
+	repository.On("Insert", mock.Anything).Return(nil).Once()
+
+	beneficiary, err := service.Execute("Performatic", "11222333000181", "237", "1234", "12345", "09", "", chargePolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, chargePolicy, beneficiary.ChargePolicy)
+}
+
+func TestCreateBeneficiaryService_ShouldNotInsertInvalidChargePolicy(t *testing.T) {
+	repository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewCreateBeneficiaryService(repository)
+	discountDays := -1
+
+	_, err := service.Execute("Performatic", "11222333000181", "237", "1234", "12345", "09", "", bankSlipEntities.ChargePolicy{DiscountDays: &discountDays})
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInvalidBeneficiary)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInvalidChargePolicy)
+	repository.AssertNotCalled(t, "Insert", mock.Anything)
+}
@@ -0,0 +1,34 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type CreateFixedWidthLayoutServiceInterface interface {
+	Execute(name, extension, recordType string, fields []bankSlipEntities.FixedWidthField, dateFormat string) (*bankSlipEntities.FixedWidthLayout, error)
+}
+
+type CreateFixedWidthLayoutService struct {
+	fixedWidthLayoutRepository bankSlipEntities.FixedWidthLayoutRepository
+}
+
+func NewCreateFixedWidthLayoutService(
+	fixedWidthLayoutRepo bankSlipEntities.FixedWidthLayoutRepository,
+) *CreateFixedWidthLayoutService {
+	return &CreateFixedWidthLayoutService{
+		fixedWidthLayoutRepository: fixedWidthLayoutRepo,
+	}
+}
+
+func (s *CreateFixedWidthLayoutService) Execute(name, extension, recordType string, fields []bankSlipEntities.FixedWidthField, dateFormat string) (*bankSlipEntities.FixedWidthLayout, error) {
+	layout, err := bankSlipEntities.NewFixedWidthLayout(name, extension, recordType, fields, dateFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.fixedWidthLayoutRepository.Insert(layout)
+	if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
@@ -0,0 +1,34 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateFixedWidthLayoutService_ShouldInsertValidLayout(t *testing.T) {
+	repository := new(bankSlipMocks.FixedWidthLayoutRepositoryMock)
+	service := NewCreateFixedWidthLayoutService(repository)
+
+	repository.On("Insert", mock.Anything).Return(nil).Once()
+
+	layout, err := service.Execute(" partner ", "REM", "1", newTestFixedWidthLayout().Fields, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "partner", layout.Name)
+	assert.Equal(t, ".rem", layout.Extension)
+	assert.Equal(t, bankSlipEntities.DefaultFixedWidthDateFormat, layout.DateFormat)
+	repository.AssertCalled(t, "Insert", layout)
+}
+
+func TestCreateFixedWidthLayoutService_ShouldNotInsertInvalidLayout(t *testing.T) {
+	repository := new(bankSlipMocks.FixedWidthLayoutRepositoryMock)
+	service := NewCreateFixedWidthLayoutService(repository)
+
+	_, err := service.Execute("partner", "rem", "1", []bankSlipEntities.FixedWidthField{}, "")
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInvalidFixedWidthLayout)
+	repository.AssertNotCalled(t, "Insert", mock.Anything)
+}
@@ -0,0 +1,143 @@
+package bank_slip
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipProviders "performatic-file-processor/internal/bank_slip/providers"
+	"performatic-file-processor/internal/calendar"
+)
+
+type CreateInstallmentsServiceInterface interface {
+	Execute(debtId string, plan bankSlipEntities.InstallmentPlan, firstDueDate time.Time) ([]*bankSlipEntities.BankSlip, error)
+}
+
+// CreateInstallmentsService splits a stored bank slip in installments. The
+// bank slip is REISSUED by them, and the ones whose issuance window is open
+// are billed right away. Splitting a debt again with the same plan finishes a
+// split that stopped halfway.
+type CreateInstallmentsService struct {
+	bankSlipRepository          bankSlipEntities.BankSlipRepository
+	installmentRepository       bankSlipEntities.InstallmentRepository
+	generateBillingAndSentEmail bankSlipProviders.GenerateBillingAndSentEmailProvider
+	businessDays                *calendar.Calendar
+	issuanceWindowDays          int
+}
+
+func NewCreateInstallmentsService(
+	bankSlipRepo bankSlipEntities.BankSlipRepository,
+	installmentRepository bankSlipEntities.InstallmentRepository,
+	generateBillingAndSentEmail bankSlipProviders.GenerateBillingAndSentEmailProvider,
+	businessDays *calendar.Calendar,
+	issuanceWindowDays int,
+) *CreateInstallmentsService {
+	return &CreateInstallmentsService{
+		bankSlipRepository:          bankSlipRepo,
+		installmentRepository:       installmentRepository,
+		generateBillingAndSentEmail: generateBillingAndSentEmail,
+		businessDays:                businessDays,
+		issuanceWindowDays:          issuanceWindowDays,
+	}
+}
+
+// Execute splits the debt with the first installment due on firstDueDate, or
+// on the due date of the debt when it's zero.
+func (s *CreateInstallmentsService) Execute(debtId string, plan bankSlipEntities.InstallmentPlan, firstDueDate time.Time) ([]*bankSlipEntities.BankSlip, error) {
+	parent, err := s.bankSlipRepository.GetByDebtId(debtId)
+	if err != nil {
+		return nil, err
+	}
+	if parent.IsInstallment() {
+		return nil, fmt.Errorf("%w: %s is already an installment", bankSlipEntities.ErrInvalidInstallmentPlan, debtId)
+	}
+	if !plan.IsSplit() {
+		return nil, fmt.Errorf("%w: a debt must be split in at least 2 installments", bankSlipEntities.ErrInvalidInstallmentPlan)
+	}
+	if err := plan.ValidateFor(parent.DebtId, parent.DebtAmount, parent.ChargePolicy); err != nil {
+		return nil, err
+	}
+	wasReissued := parent.Status == bankSlipEntities.BankSlipStatusReissued
+	if !wasReissued {
+		if err := parent.TransitionTo(bankSlipEntities.BankSlipStatusReissued); err != nil {
+			return nil, err
+		}
+	}
+	if firstDueDate.IsZero() {
+		firstDueDate = parent.DebtDueDate
+	}
+
+	installments := parent.SplitInstallments(plan, firstDueDate, s.businessDays)
+	stored, err := s.installmentRepository.ListByParentDebtId(parent.DebtId)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(stored) > 0:
+		// The split stopped before the debt was reissued, or before its
+		// installments were billed, and the same plan picks it up.
+		if !isSamePlan(stored, installments) {
+			return nil, fmt.Errorf("%w: %s has %d installments", bankSlipEntities.ErrInstallmentPlanAlreadyExists, parent.DebtId, len(stored))
+		}
+		installments = stored
+	case wasReissued:
+		// A debt reissued some other way can't be split.
+		return nil, parent.TransitionTo(bankSlipEntities.BankSlipStatusReissued)
+	default:
+		if err := s.insert(parent, installments); err != nil {
+			return nil, err
+		}
+	}
+
+	if !wasReissued {
+		if err := s.bankSlipRepository.UpdateMany(&bankSlipEntities.BankSlipMap{parent.DebtId: parent}); err != nil {
+			return nil, err
+		}
+	}
+
+	pending := bankSlipEntities.BankSlipMap{}
+	for _, installment := range installments {
+		if installment.Status == bankSlipEntities.BankSlipStatusPending {
+			pending[installment.DebtId] = installment
+		}
+	}
+	if len(pending) > 0 {
+		debitsWithErrors := s.generateBillingAndSentEmail.GenerateBillingAndSentEmail(&pending)
+		if err := s.bankSlipRepository.UpdateMany(&pending, debitsWithErrors); err != nil {
+			return nil, err
+		}
+	}
+	return installments, nil
+}
+
+func (s *CreateInstallmentsService) insert(parent *bankSlipEntities.BankSlip, installments []*bankSlipEntities.BankSlip) error {
+	bankSlips := bankSlipEntities.BankSlipMap{}
+	for _, installment := range installments {
+		if err := installment.ScheduleIssuance(time.Now(), s.issuanceWindowDays); err != nil {
+			return err
+		}
+		bankSlips[installment.DebtId] = installment
+	}
+
+	// The ids of the installments are derived from the debt, a debt split
+	// meanwhile has them stored already.
+	insertedDebtIds, err := s.bankSlipRepository.InsertMany(&bankSlips)
+	if err != nil {
+		return err
+	}
+	for debtId, inserted := range insertedDebtIds {
+		if !inserted {
+			return fmt.Errorf("%w: installment %s of %s", bankSlipEntities.ErrInstallmentPlanAlreadyExists, debtId, parent.DebtId)
+		}
+	}
+	return nil
+}
+
+// isSamePlan tells if the stored installments are the ones the plan splits the
+// debt in.
+func isSamePlan(stored, installments []*bankSlipEntities.BankSlip) bool {
+	return slices.EqualFunc(stored, installments, func(a, b *bankSlipEntities.BankSlip) bool {
+		return a.DebtId == b.DebtId && a.DebtAmount == b.DebtAmount && a.DebtDueDate.Equal(b.DebtDueDate)
+	})
+}
@@ -0,0 +1,133 @@
+package bank_slip
+
+import (
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testInstallmentsParentDebtId = "e1f4b1f2-7c1a-4b8e-9a3d-2f6c8d9e0a11"
+
+func newSplittableBankSlip() *bankSlipEntities.BankSlip {
+	bankSlip := newIssuedBankSlip(testInstallmentsParentDebtId)
+	bankSlip.DebtAmount = 100000
+	bankSlip.BankSlipFileMetadataId = "file1"
+	return bankSlip
+}
+
+func TestCreateInstallmentsService_ShouldReissueDebtAndBillInstallmentsInWindow(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	installmentRepository := new(bankSlipMocks.InstallmentRepositoryMock)
+	provider := new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	service := NewCreateInstallmentsService(bankSlipRepository, installmentRepository, provider, calendar.New(), 30)
+
+	parent := newSplittableBankSlip()
+	first := bankSlipEntities.InstallmentDebtId(testInstallmentsParentDebtId, 1)
+	second := bankSlipEntities.InstallmentDebtId(testInstallmentsParentDebtId, 2)
+	bankSlipRepository.On("GetByDebtId", testInstallmentsParentDebtId).Return(parent, nil).Once()
+	installmentRepository.On("ListByParentDebtId", testInstallmentsParentDebtId).Return([]*bankSlipEntities.BankSlip{}, nil).Once()
+	bankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{first: true, second: true}, nil).Once()
+	bankSlipRepository.On("UpdateMany", &bankSlipEntities.BankSlipMap{testInstallmentsParentDebtId: parent}).Return(nil).Once()
+	provider.On("GenerateBillingAndSentEmail", mock.Anything).Return(&bankSlipEntities.BankSlipMap{}).Once()
+	bankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+
+	firstDueDate := time.Now().AddDate(0, 0, 5)
+	plan := bankSlipEntities.InstallmentPlan{Installments: 2}
+	installments, err := service.Execute(testInstallmentsParentDebtId, plan, firstDueDate)
+	assert.NoError(t, err)
+	assert.Len(t, installments, 2)
+	assert.Equal(t, bankSlipEntities.BankSlipStatusReissued, parent.Status)
+	assert.Equal(t, bankSlipEntities.Money(50000), installments[0].DebtAmount)
+	assert.Equal(t, bankSlipEntities.BankSlipStatusScheduled, installments[1].Status)
+	provider.AssertCalled(t, "GenerateBillingAndSentEmail", mock.MatchedBy(func(m *bankSlipEntities.BankSlipMap) bool {
+		_, exists := (*m)[first]
+		return len(*m) == 1 && exists
+	}))
+}
+
+func TestCreateInstallmentsService_ShouldFailWhenDebtIsSplitMeanwhile(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	installmentRepository := new(bankSlipMocks.InstallmentRepositoryMock)
+	service := NewCreateInstallmentsService(bankSlipRepository, installmentRepository, new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock), calendar.New(), 30)
+
+	bankSlipRepository.On("GetByDebtId", testInstallmentsParentDebtId).Return(newSplittableBankSlip(), nil).Once()
+	installmentRepository.On("ListByParentDebtId", testInstallmentsParentDebtId).Return([]*bankSlipEntities.BankSlip{}, nil).Once()
+	bankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{
+		bankSlipEntities.InstallmentDebtId(testInstallmentsParentDebtId, 1): false,
+		bankSlipEntities.InstallmentDebtId(testInstallmentsParentDebtId, 2): false,
+	}, nil).Once()
+
+	installments, err := service.Execute(testInstallmentsParentDebtId, bankSlipEntities.InstallmentPlan{Installments: 2}, time.Time{})
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInstallmentPlanAlreadyExists)
+	assert.Nil(t, installments)
+	bankSlipRepository.AssertNotCalled(t, "UpdateMany", mock.Anything)
+}
+
+func TestCreateInstallmentsService_ShouldFailWhenDebtWasSplitInAnotherPlan(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	installmentRepository := new(bankSlipMocks.InstallmentRepositoryMock)
+	service := NewCreateInstallmentsService(bankSlipRepository, installmentRepository, new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock), calendar.New(), 30)
+
+	parent := newSplittableBankSlip()
+	parent.Status = bankSlipEntities.BankSlipStatusReissued
+	stored := parent.SplitInstallments(bankSlipEntities.InstallmentPlan{Installments: 3}, parent.DebtDueDate, calendar.New())
+	bankSlipRepository.On("GetByDebtId", testInstallmentsParentDebtId).Return(parent, nil).Once()
+	installmentRepository.On("ListByParentDebtId", testInstallmentsParentDebtId).Return(stored, nil).Once()
+
+	installments, err := service.Execute(testInstallmentsParentDebtId, bankSlipEntities.InstallmentPlan{Installments: 2}, time.Time{})
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInstallmentPlanAlreadyExists)
+	assert.Nil(t, installments)
+	bankSlipRepository.AssertNotCalled(t, "InsertMany", mock.Anything)
+	bankSlipRepository.AssertNotCalled(t, "UpdateMany", mock.Anything)
+}
+
+func TestCreateInstallmentsService_ShouldFinishSplitStoppedBeforeReissuingTheDebt(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	installmentRepository := new(bankSlipMocks.InstallmentRepositoryMock)
+	provider := new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	service := NewCreateInstallmentsService(bankSlipRepository, installmentRepository, provider, calendar.New(), 30)
+
+	parent := newSplittableBankSlip()
+	plan := bankSlipEntities.InstallmentPlan{Installments: 2}
+	stored := parent.SplitInstallments(plan, parent.DebtDueDate, calendar.New())
+	stored[1].Status = bankSlipEntities.BankSlipStatusScheduled
+	bankSlipRepository.On("GetByDebtId", testInstallmentsParentDebtId).Return(parent, nil).Once()
+	installmentRepository.On("ListByParentDebtId", testInstallmentsParentDebtId).Return(stored, nil).Once()
+	bankSlipRepository.On("UpdateMany", &bankSlipEntities.BankSlipMap{testInstallmentsParentDebtId: parent}).Return(nil).Once()
+	provider.On("GenerateBillingAndSentEmail", &bankSlipEntities.BankSlipMap{stored[0].DebtId: stored[0]}).Return(&bankSlipEntities.BankSlipMap{}).Once()
+	bankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+
+	installments, err := service.Execute(testInstallmentsParentDebtId, plan, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, stored, installments)
+	assert.Equal(t, bankSlipEntities.BankSlipStatusReissued, parent.Status)
+	bankSlipRepository.AssertNotCalled(t, "InsertMany", mock.Anything)
+	bankSlipRepository.AssertExpectations(t)
+	provider.AssertExpectations(t)
+}
+
+func TestCreateInstallmentsService_ShouldNotSplitInstallmentsOrClosedBankSlips(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	installmentRepository := new(bankSlipMocks.InstallmentRepositoryMock)
+	service := NewCreateInstallmentsService(bankSlipRepository, installmentRepository, new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock), calendar.New(), 30)
+
+	installment := newSplittableBankSlip()
+	installment.ParentDebtId = "c0c9a1d2-5b7e-4f3a-8d2c-1e0f9a8b7c6d"
+	bankSlipRepository.On("GetByDebtId", testInstallmentsParentDebtId).Return(installment, nil).Once()
+	_, err := service.Execute(testInstallmentsParentDebtId, bankSlipEntities.InstallmentPlan{Installments: 2}, time.Time{})
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInvalidInstallmentPlan)
+
+	paid := newSplittableBankSlip()
+	paid.Status = bankSlipEntities.BankSlipStatusPaid
+	bankSlipRepository.On("GetByDebtId", testInstallmentsParentDebtId).Return(paid, nil).Once()
+	_, err = service.Execute(testInstallmentsParentDebtId, bankSlipEntities.InstallmentPlan{Installments: 2}, time.Time{})
+	assert.ErrorIs(t, err, bankSlipEntities.ErrUnexpectedBankSlipStatus)
+
+	bankSlipRepository.AssertNotCalled(t, "InsertMany", mock.Anything)
+}
@@ -0,0 +1,116 @@
+package bank_slip
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/cnab"
+
+	"github.com/google/uuid"
+)
+
+type CreateRemittanceServiceInterface interface {
+	Execute(beneficiaryId, fileId, format string) (*bankSlipEntities.Remittance, error)
+}
+
+// CreateRemittanceService writes the CNAB remittance of the issued bank slips
+// of a beneficiary not sent to the bank yet, limited to an upload when
+// fileId is set.
+type CreateRemittanceService struct {
+	beneficiaryRepository          bankSlipEntities.BeneficiaryRepository
+	bankSlipFileMetadataRepository bankSlipEntities.BankSlipFileMetadataRepository
+	remittanceRepository           bankSlipEntities.RemittanceRepository
+}
+
+func NewCreateRemittanceService(
+	beneficiaryRepo bankSlipEntities.BeneficiaryRepository,
+	bankSlipFileRepo bankSlipEntities.BankSlipFileMetadataRepository,
+	remittanceRepo bankSlipEntities.RemittanceRepository,
+) *CreateRemittanceService {
+	return &CreateRemittanceService{
+		beneficiaryRepository:          beneficiaryRepo,
+		bankSlipFileMetadataRepository: bankSlipFileRepo,
+		remittanceRepository:           remittanceRepo,
+	}
+}
+
+// Execute takes the beneficiary of the upload when only fileId is set.
+func (s *CreateRemittanceService) Execute(beneficiaryId, fileId, format string) (*bankSlipEntities.Remittance, error) {
+	cnabFormat, err := cnab.ParseFormat(format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", bankSlipEntities.ErrInvalidRemittance, err)
+	}
+
+	for _, id := range []string{beneficiaryId, fileId} {
+		if _, err := uuid.Parse(id); id != "" && err != nil {
+			return nil, fmt.Errorf("%w: invalid id %q", bankSlipEntities.ErrInvalidRemittance, id)
+		}
+	}
+
+	if fileId != "" {
+		file, err := s.bankSlipFileMetadataRepository.GetById(fileId)
+		if err != nil {
+			return nil, err
+		}
+		if beneficiaryId != "" && beneficiaryId != file.BeneficiaryId {
+			return nil, fmt.Errorf("%w: file %s belongs to another beneficiary", bankSlipEntities.ErrInvalidRemittance, fileId)
+		}
+		beneficiaryId = file.BeneficiaryId
+	}
+	if beneficiaryId == "" {
+		return nil, fmt.Errorf("%w: beneficiary or file is required", bankSlipEntities.ErrInvalidRemittance)
+	}
+
+	beneficiary, err := s.beneficiaryRepository.GetById(beneficiaryId)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := cnab.LayoutFor(beneficiary.BankCode, cnabFormat)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", bankSlipEntities.ErrInvalidRemittance, err)
+	}
+
+	remittance := bankSlipEntities.NewRemittance(beneficiary.ID, fileId, cnabFormat)
+	err = s.remittanceRepository.Create(remittance, func(remittance *bankSlipEntities.Remittance, bankSlips []*bankSlipEntities.BankSlip) ([]byte, error) {
+		cnabRemittance := cnab.Remittance{
+			Beneficiary: beneficiary.CNAB(),
+			Sequence:    remittance.Sequence,
+			GeneratedAt: time.Now(),
+		}
+		for _, bankSlip := range bankSlips {
+			cnabRemittance.Titles = append(cnabRemittance.Titles, newCNABTitle(bankSlip, cnabRemittance.GeneratedAt))
+		}
+
+		var content bytes.Buffer
+		err := layout.Write(&content, cnabRemittance)
+		if errors.Is(err, cnab.ErrUnsupportedCharge) {
+			return nil, fmt.Errorf("%w: %w", bankSlipEntities.ErrInvalidRemittance, err)
+		}
+		return content.Bytes(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return remittance, nil
+}
+
+// newCNABTitle numbers the title after the our number and identifies it by
+// the debt id, which comes back in the "retorno" files. The bank charges the
+// late fee, interest and discount of the bank slip.
+func newCNABTitle(bankSlip *bankSlipEntities.BankSlip, issueDate time.Time) cnab.Title {
+	return bankSlip.ChargePolicy.CNAB(cnab.Title{
+		OurNumber:      bankSlip.OurNumber,
+		DocumentNumber: strconv.FormatInt(bankSlip.OurNumber, 10),
+		ControlNumber:  strings.ReplaceAll(bankSlip.DebtId, "-", ""),
+		IssueDate:      issueDate,
+		DueDate:        bankSlip.DebtDueDate,
+		AmountInCents:  bankSlip.DebtAmount.Centavos(),
+		PayerName:      bankSlip.UserName,
+		PayerDocument:  bankSlip.GovernmentId.String(),
+	})
+}
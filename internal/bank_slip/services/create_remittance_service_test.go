@@ -0,0 +1,151 @@
+package bank_slip
+
+import (
+	"strings"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/cnab"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testRemittanceFileId = "a1b2c3d4-0000-4000-8000-000000000002"
+
+// renderRemittance makes the repository mock number the remittance and call
+// the renderer with the given bank slips, as the database would.
+func renderRemittance(bankSlips ...*bankSlipEntities.BankSlip) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		remittance := args.Get(0).(*bankSlipEntities.Remittance)
+		render := args.Get(1).(bankSlipEntities.RemittanceRenderer)
+		remittance.Numbered(7, bankSlips)
+		remittance.Content, _ = render(remittance, bankSlips)
+	}
+}
+
+func TestCreateRemittanceService_ShouldRenderBankSlipsNotRemitted(t *testing.T) {
+	fileRepository := new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	remittanceRepository := new(bankSlipMocks.RemittanceRepositoryMock)
+	service := NewCreateRemittanceService(newTestBeneficiaryRepository(), fileRepository, remittanceRepository)
+
+	remittanceRepository.On("Create", mock.Anything, mock.Anything).
+		Run(renderRemittance(newIssuedBankSlip("debt1"), newIssuedBankSlip("debt2"))).
+		Return(nil).Once()
+
+	remittance, err := service.Execute(testPdfBeneficiaryId, "", "cnab400")
+	assert.NoError(t, err)
+	assert.Equal(t, testPdfBeneficiaryId, remittance.BeneficiaryId)
+	assert.Equal(t, cnab.CNAB400, remittance.Format)
+	assert.Equal(t, int64(7), remittance.Sequence)
+	assert.Equal(t, 2, remittance.TotalBankSlips)
+	assert.Equal(t, bankSlipEntities.Money(200100), remittance.TotalAmount)
+
+	lines := strings.Split(strings.TrimSuffix(string(remittance.Content), "\r\n"), "\r\n")
+	assert.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[0], "01REMESSA"))
+	assert.Contains(t, lines[1], "DEBT1")
+	assert.Contains(t, lines[2], "JOHN DOE")
+	fileRepository.AssertNotCalled(t, "GetById", mock.Anything)
+}
+
+func TestCreateRemittanceService_ShouldTakeBeneficiaryFromFile(t *testing.T) {
+	fileRepository := new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	remittanceRepository := new(bankSlipMocks.RemittanceRepositoryMock)
+	service := NewCreateRemittanceService(newTestBeneficiaryRepository(), fileRepository, remittanceRepository)
+
+	file := bankSlipEntities.NewBankSlipFileMetadata("file.csv")
+	file.ID = testRemittanceFileId
+	file.BeneficiaryId = testPdfBeneficiaryId
+	fileRepository.On("GetById", testRemittanceFileId).Return(file, nil).Once()
+	remittanceRepository.On("Create", mock.Anything, mock.Anything).
+		Run(renderRemittance(newIssuedBankSlip("debt1"))).
+		Return(nil).Once()
+
+	remittance, err := service.Execute("", testRemittanceFileId, "CNAB240")
+	assert.NoError(t, err)
+	assert.Equal(t, testPdfBeneficiaryId, remittance.BeneficiaryId)
+	assert.Equal(t, testRemittanceFileId, remittance.BankSlipFileId)
+	// File header, lot header, segments P, Q and R, lot and file trailers.
+	assert.Equal(t, 7, strings.Count(string(remittance.Content), "\r\n"))
+}
+
+func TestCreateRemittanceService_ShouldRejectInvalidRequest(t *testing.T) {
+	fileRepository := new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	remittanceRepository := new(bankSlipMocks.RemittanceRepositoryMock)
+	service := NewCreateRemittanceService(newTestBeneficiaryRepository(), fileRepository, remittanceRepository)
+
+	file := bankSlipEntities.NewBankSlipFileMetadata("file.csv")
+	file.BeneficiaryId = "a1b2c3d4-0000-4000-8000-000000000003"
+	fileRepository.On("GetById", testRemittanceFileId).Return(file, nil)
+
+	for _, request := range [][3]string{
+		{testPdfBeneficiaryId, "", "CNAB500"},
+		{"", "", "CNAB240"},
+		{"beneficiary", "", "CNAB240"},
+		{testPdfBeneficiaryId, testRemittanceFileId, "CNAB240"},
+	} {
+		_, err := service.Execute(request[0], request[1], request[2])
+		assert.ErrorIs(t, err, bankSlipEntities.ErrInvalidRemittance, request)
+	}
+	remittanceRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateRemittanceService_ShouldRejectLayoutNotAvailableForBank(t *testing.T) {
+	beneficiary := &bankSlipEntities.Beneficiary{ID: testPdfBeneficiaryId, Name: "Cobranças Ltda", Document: "11222333000181", BankCode: "001", Agency: "1234", Account: "0012345", Wallet: "17"}
+	beneficiaryRepository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	beneficiaryRepository.On("GetById", testPdfBeneficiaryId).Return(beneficiary, nil)
+	remittanceRepository := new(bankSlipMocks.RemittanceRepositoryMock)
+	service := NewCreateRemittanceService(beneficiaryRepository, new(bankSlipMocks.BankSlipFileMetadataRepositoryMock), remittanceRepository)
+
+	_, err := service.Execute(testPdfBeneficiaryId, "", "CNAB400")
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInvalidRemittance)
+	assert.ErrorIs(t, err, cnab.ErrUnsupportedLayout)
+	remittanceRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateRemittanceService_ShouldReturnRepositoryError(t *testing.T) {
+	remittanceRepository := new(bankSlipMocks.RemittanceRepositoryMock)
+	service := NewCreateRemittanceService(newTestBeneficiaryRepository(), new(bankSlipMocks.BankSlipFileMetadataRepositoryMock), remittanceRepository)
+
+	remittanceRepository.On("Create", mock.Anything, mock.Anything).Return(bankSlipEntities.ErrNothingToRemit).Once()
+
+	remittance, err := service.Execute(testPdfBeneficiaryId, "", "CNAB240")
+	assert.Nil(t, remittance)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrNothingToRemit)
+}
+
+func TestCreateRemittanceService_ShouldRegisterChargePolicyOfBankSlips(t *testing.T) {
+	remittanceRepository := new(bankSlipMocks.RemittanceRepositoryMock)
+	service := NewCreateRemittanceService(newTestBeneficiaryRepository(), new(bankSlipMocks.BankSlipFileMetadataRepositoryMock), remittanceRepository)
+
+	lateFee, _ := bankSlipEntities.ParseCharge("2%", ".")
+	bankSlip := newIssuedBankSlip("debt1")
+	bankSlip.ChargePolicy = bankSlipEntities.ChargePolicy{LateFee: &lateFee}
+	remittanceRepository.On("Create", mock.Anything, mock.Anything).Run(renderRemittance(bankSlip)).Return(nil).Once()
+
+	remittance, err := service.Execute(testPdfBeneficiaryId, "", "CNAB400")
+	assert.NoError(t, err)
+	lines := strings.Split(string(remittance.Content), "\r\n")
+	assert.Equal(t, "20200", lines[1][65:70])
+}
+
+func TestCreateRemittanceService_ShouldRejectChargeNotSupportedByLayout(t *testing.T) {
+	remittanceRepository := new(bankSlipMocks.RemittanceRepositoryMock)
+	service := NewCreateRemittanceService(newTestBeneficiaryRepository(), new(bankSlipMocks.BankSlipFileMetadataRepositoryMock), remittanceRepository)
+
+	var render bankSlipEntities.RemittanceRenderer
+	remittanceRepository.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { render = args.Get(1).(bankSlipEntities.RemittanceRenderer) }).
+		Return(nil).Once()
+	remittance, err := service.Execute(testPdfBeneficiaryId, "", "CNAB400")
+	assert.NoError(t, err)
+
+	lateFee, _ := bankSlipEntities.ParseCharge("10.00", ".")
+	bankSlip := newIssuedBankSlip("debt1")
+	bankSlip.ChargePolicy = bankSlipEntities.ChargePolicy{LateFee: &lateFee}
+	_, err = render(remittance, []*bankSlipEntities.BankSlip{bankSlip})
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInvalidRemittance)
+	assert.ErrorIs(t, err, cnab.ErrUnsupportedCharge)
+}
@@ -0,0 +1,61 @@
+package bank_slip
+
+import (
+	"fmt"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/google/uuid"
+)
+
+type CreateSubscriptionServiceInterface interface {
+	Execute(beneficiaryId string, startDate time.Time, terms bankSlipEntities.SubscriptionTerms) (*bankSlipEntities.Subscription, error)
+}
+
+type CreateSubscriptionService struct {
+	subscriptionRepository bankSlipEntities.SubscriptionRepository
+	beneficiaryRepository  bankSlipEntities.BeneficiaryRepository
+	businessDays           *calendar.Calendar
+}
+
+func NewCreateSubscriptionService(
+	subscriptionRepo bankSlipEntities.SubscriptionRepository,
+	beneficiaryRepo bankSlipEntities.BeneficiaryRepository,
+	businessDays *calendar.Calendar,
+) *CreateSubscriptionService {
+	return &CreateSubscriptionService{
+		subscriptionRepository: subscriptionRepo,
+		beneficiaryRepository:  beneficiaryRepo,
+		businessDays:           businessDays,
+	}
+}
+
+// Execute stores the subscription with its first bank slip scheduled, the
+// scheduler issues it when its issuance window opens.
+func (s *CreateSubscriptionService) Execute(beneficiaryId string, startDate time.Time, terms bankSlipEntities.SubscriptionTerms) (*bankSlipEntities.Subscription, error) {
+	beneficiary, err := getSubscriptionBeneficiary(s.beneficiaryRepository, beneficiaryId)
+	if err != nil {
+		return nil, err
+	}
+	terms.ChargePolicy = beneficiary.ChargePolicy.Override(terms.ChargePolicy)
+
+	subscription, err := bankSlipEntities.NewSubscription(beneficiary.ID, startDate, terms)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Start(time.Now(), s.businessDays)
+
+	if err := s.subscriptionRepository.Insert(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func getSubscriptionBeneficiary(beneficiaryRepository bankSlipEntities.BeneficiaryRepository, beneficiaryId string) (*bankSlipEntities.Beneficiary, error) {
+	if _, err := uuid.Parse(beneficiaryId); err != nil {
+		return nil, fmt.Errorf("%w: invalid beneficiary id %q", bankSlipEntities.ErrInvalidSubscription, beneficiaryId)
+	}
+	return beneficiaryRepository.GetById(beneficiaryId)
+}
@@ -0,0 +1,70 @@
+package bank_slip
+
+import (
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testSubscriptionBeneficiaryId = "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+
+func newTestSubscriptionTerms() bankSlipEntities.SubscriptionTerms {
+	return bankSlipEntities.SubscriptionTerms{
+		UserName:     "John Doe",
+		GovernmentId: "52998224725",
+		UserEmail:    "john.doe@example.com",
+		Amount:       "150.00",
+		DayOfMonth:   10,
+	}
+}
+
+func TestCreateSubscriptionService_ShouldScheduleFirstBankSlipWithBeneficiaryPolicy(t *testing.T) {
+	subscriptionRepository := new(bankSlipMocks.SubscriptionRepositoryMock)
+	beneficiaryRepository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewCreateSubscriptionService(subscriptionRepository, beneficiaryRepository, calendar.New())
+
+	lateFee := bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindPercent, Value: 200}
+	interest := bankSlipEntities.Charge{Kind: bankSlipEntities.ChargeKindPercent, Value: 100}
+	beneficiaryRepository.On("GetById", testSubscriptionBeneficiaryId).Return(&bankSlipEntities.Beneficiary{
+		ID:           testSubscriptionBeneficiaryId,
+		ChargePolicy: bankSlipEntities.ChargePolicy{LateFee: &lateFee},
+	}, nil).Once()
+	subscriptionRepository.On("Insert", mock.Anything).Return(nil).Once()
+
+	terms := newTestSubscriptionTerms()
+	terms.ChargePolicy = bankSlipEntities.ChargePolicy{Interest: &interest}
+	startDate := time.Now().AddDate(0, 1, 0)
+	subscription, err := service.Execute(testSubscriptionBeneficiaryId, startDate, terms)
+	assert.NoError(t, err)
+	assert.Equal(t, bankSlipEntities.ChargePolicy{LateFee: &lateFee, Interest: &interest}, subscription.ChargePolicy)
+	assert.Equal(t, bankSlipEntities.SubscriptionStatusActive, subscription.Status)
+	assert.False(t, subscription.NextDueDate.Before(startDate.Truncate(24*time.Hour)))
+	subscriptionRepository.AssertExpectations(t)
+}
+
+func TestCreateSubscriptionService_ShouldRejectInvalidBeneficiaryId(t *testing.T) {
+	beneficiaryRepository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewCreateSubscriptionService(new(bankSlipMocks.SubscriptionRepositoryMock), beneficiaryRepository, calendar.New())
+
+	_, err := service.Execute("beneficiary1", time.Now(), newTestSubscriptionTerms())
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInvalidSubscription)
+	beneficiaryRepository.AssertNotCalled(t, "GetById", mock.Anything)
+}
+
+func TestCreateSubscriptionService_ShouldFailWhenBeneficiaryDoesNotExist(t *testing.T) {
+	subscriptionRepository := new(bankSlipMocks.SubscriptionRepositoryMock)
+	beneficiaryRepository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewCreateSubscriptionService(subscriptionRepository, beneficiaryRepository, calendar.New())
+
+	beneficiaryRepository.On("GetById", testSubscriptionBeneficiaryId).Return(nil, bankSlipEntities.ErrBeneficiaryNotFound).Once()
+
+	_, err := service.Execute(testSubscriptionBeneficiaryId, time.Now(), newTestSubscriptionTerms())
+	assert.ErrorIs(t, err, bankSlipEntities.ErrBeneficiaryNotFound)
+	subscriptionRepository.AssertNotCalled(t, "Insert", mock.Anything)
+}
@@ -0,0 +1,34 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type CreateUploadProfileServiceInterface interface {
+	Execute(name string, columns map[bankSlipEntities.BankSlipField][]string, dateFormat, decimalSeparator, dueDatePolicy string) (*bankSlipEntities.UploadProfile, error)
+}
+
+type CreateUploadProfileService struct {
+	uploadProfileRepository bankSlipEntities.UploadProfileRepository
+}
+
+func NewCreateUploadProfileService(
+	uploadProfileRepo bankSlipEntities.UploadProfileRepository,
+) *CreateUploadProfileService {
+	return &CreateUploadProfileService{
+		uploadProfileRepository: uploadProfileRepo,
+	}
+}
+
+func (s *CreateUploadProfileService) Execute(name string, columns map[bankSlipEntities.BankSlipField][]string, dateFormat, decimalSeparator, dueDatePolicy string) (*bankSlipEntities.UploadProfile, error) {
+	uploadProfile, err := bankSlipEntities.NewUploadProfile(name, columns, dateFormat, decimalSeparator, dueDatePolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.uploadProfileRepository.Insert(uploadProfile)
+	if err != nil {
+		return nil, err
+	}
+	return uploadProfile, nil
+}
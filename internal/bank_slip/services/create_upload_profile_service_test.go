@@ -0,0 +1,33 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateUploadProfileService_ShouldInsertValidProfile(t *testing.T) {
+	repository := new(bankSlipMocks.UploadProfileRepositoryMock)
+	service := NewCreateUploadProfileService(repository)
+
+	repository.On("Insert", mock.Anything).Return(nil).Once()
+
+	uploadProfile, err := service.Execute(" br ", bankSlipEntities.DefaultUploadProfile().Columns, "DD/MM/YYYY", ",", "ROLL_FORWARD")
+	assert.NoError(t, err)
+	assert.Equal(t, "br", uploadProfile.Name)
+	assert.Equal(t, bankSlipEntities.DueDatePolicyRollForward, uploadProfile.DueDatePolicy)
+	repository.AssertCalled(t, "Insert", uploadProfile)
+}
+
+func TestCreateUploadProfileService_ShouldNotInsertInvalidProfile(t *testing.T) {
+	repository := new(bankSlipMocks.UploadProfileRepositoryMock)
+	service := NewCreateUploadProfileService(repository)
+
+	_, err := service.Execute("br", map[bankSlipEntities.BankSlipField][]string{}, "DD/MM/YYYY", ",", "")
+	assert.ErrorIs(t, err, bankSlipEntities.ErrInvalidUploadProfile)
+	repository.AssertNotCalled(t, "Insert", mock.Anything)
+}
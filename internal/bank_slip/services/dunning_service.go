@@ -0,0 +1,177 @@
+package bank_slip
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/calendar"
+	emailService "performatic-file-processor/internal/infra/email"
+)
+
+const (
+	overdueReason = "due date passed"
+	expiredReason = "grace period of %d days passed"
+)
+
+var dunningTemplates = map[bankSlipEntities.DunningNoticeKind]emailService.EmailTemplate{
+	bankSlipEntities.DunningNoticeKindReminder: emailService.BILLING_DUE_REMINDER,
+	bankSlipEntities.DunningNoticeKindOverdue:  emailService.BILLING_OVERDUE_NOTICE,
+}
+
+// dunningTemplate is the template of the step, the one of its kind with the
+// days from the due date, as billing_overdue_notice_7 for D+7, so each step
+// of the schedule has its own email.
+func dunningTemplate(step bankSlipEntities.DunningStep) emailService.EmailTemplate {
+	return fmt.Sprintf("%s_%d", dunningTemplates[step.Kind()], max(step.Offset, -step.Offset))
+}
+
+type DunningServiceInterface interface {
+	Execute(ctx context.Context)
+}
+
+// DunningService moves the bank slips not paid by their due date to OVERDUE,
+// and to EXPIRED after the grace period, and emails the payers on the steps of
+// the schedule. Every worker may run it: bank slips are moved and notices are
+// claimed in the database, so none is moved or emailed twice. Bank slips due
+// on a day that is not a business day are only late after the next one.
+type DunningService struct {
+	dunningRepository bankSlipEntities.DunningRepository
+	emailService      emailService.EmailService
+	businessDays      *calendar.Calendar
+	schedule          []bankSlipEntities.DunningStep
+	gracePeriodDays   int
+	interval          time.Duration
+	batchSize         int
+}
+
+func NewDunningService(
+	dunningRepo bankSlipEntities.DunningRepository,
+	emailService emailService.EmailService,
+	businessDays *calendar.Calendar,
+	schedule []bankSlipEntities.DunningStep,
+	gracePeriodDays int,
+	interval time.Duration,
+	batchSize int,
+) *DunningService {
+	return &DunningService{
+		dunningRepository: dunningRepo,
+		emailService:      emailService,
+		businessDays:      businessDays,
+		schedule:          schedule,
+		gracePeriodDays:   gracePeriodDays,
+		interval:          interval,
+		batchSize:         batchSize,
+	}
+}
+
+func (s *DunningService) Execute(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Run(time.Now()); err != nil {
+			log.Printf("Error running dunning: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run does the dunning of date: bank slips are moved before the notices are
+// sent, so overdue notices go only to the bank slips still within the grace
+// period.
+func (s *DunningService) Run(date time.Time) error {
+	today := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	overdue, err := s.moveDue(bankSlipEntities.BankSlipStatusOverdue, s.lateBefore(today), overdueReason)
+	if err != nil {
+		return fmt.Errorf("error moving bank slips to overdue: %w", err)
+	}
+	expiredBefore := s.lateBefore(today.AddDate(0, 0, -s.gracePeriodDays))
+	expired, err := s.moveDue(bankSlipEntities.BankSlipStatusExpired, expiredBefore, fmt.Sprintf(expiredReason, s.gracePeriodDays))
+	if err != nil {
+		return fmt.Errorf("error moving bank slips to expired: %w", err)
+	}
+	if overdue > 0 || expired > 0 {
+		log.Printf("Dunning moved %d bank slips to overdue and %d to expired", overdue, expired)
+	}
+
+	for _, step := range s.schedule {
+		sent, err := s.sendNotices(step, today)
+		if err != nil {
+			return fmt.Errorf("error sending dunning step %s: %w", step, err)
+		}
+		if sent > 0 {
+			log.Printf("Dunning step %s sent to %d bank slips", step, sent)
+		}
+	}
+	return nil
+}
+
+// lateBefore is the due date the bank slips due before were not paid by date:
+// the ones due up to the last business day before it, and the days that are
+// not business days right after that one.
+func (s *DunningService) lateBefore(date time.Time) time.Time {
+	return s.businessDays.PreviousBusinessDay(date).AddDate(0, 0, 1)
+}
+
+// moveDue moves the bank slips due before dueBefore in batches, so each
+// transaction locks few rows.
+func (s *DunningService) moveDue(status bankSlipEntities.BankSlipStatus, dueBefore time.Time, reason string) (int, error) {
+	from := bankSlipEntities.BankSlipStatusesTo(status)
+	total := 0
+	for {
+		moved, err := s.dunningRepository.MoveDue(from, status, dueBefore, reason, s.batchSize)
+		total += moved
+		if err != nil || moved < s.batchSize {
+			return total, err
+		}
+	}
+}
+
+// sendNotices claims the bank slips of the step in batches and emails them.
+// The claims of the emails that failed are released when the step is done, so
+// they are sent on the next run and not claimed again by this one.
+func (s *DunningService) sendNotices(step bankSlipEntities.DunningStep, today time.Time) (int, error) {
+	total := 0
+	failed := []bankSlipEntities.DebitId{}
+	defer func() {
+		for _, debtId := range failed {
+			if err := s.dunningRepository.ReleaseNotice(debtId, step); err != nil {
+				log.Printf("Error releasing dunning step %s (debt id: %s): %v", step, debtId, err)
+			}
+		}
+	}()
+
+	for {
+		bankSlips, err := s.dunningRepository.ClaimNotices(step, today, s.batchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(bankSlips) == 0 {
+			return total, nil
+		}
+
+		bankSlipMap := bankSlipEntities.BankSlipMap{}
+		for _, bankSlip := range bankSlips {
+			bankSlipMap[bankSlip.DebtId] = bankSlip
+		}
+		emailErrors := *s.emailService.SendBankSlipDunningEmail(&bankSlipMap, dunningTemplate(step), today)
+		for debtId, emailError := range emailErrors {
+			log.Printf("Error sending dunning step %s (debt id: %s): %v", step, debtId, emailError)
+			failed = append(failed, debtId)
+		}
+		total += len(bankSlips) - len(emailErrors)
+
+		if len(bankSlips) < s.batchSize {
+			return total, nil
+		}
+	}
+}
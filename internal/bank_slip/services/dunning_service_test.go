@@ -0,0 +1,117 @@
+package bank_slip
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/calendar"
+	sharedMocks "performatic-file-processor/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	dunningToday    = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	dunningReminder = bankSlipEntities.DunningStep{Offset: -3}
+	dunningNotice   = bankSlipEntities.DunningStep{Offset: 1}
+)
+
+func newTestDunningService(dunningRepository *bankSlipMocks.DunningRepositoryMock, emailService *sharedMocks.EmailServicesMock) *DunningService {
+	schedule := []bankSlipEntities.DunningStep{dunningReminder, dunningNotice}
+	return NewDunningService(dunningRepository, emailService, calendar.New(), schedule, 30, time.Hour, 2)
+}
+
+func expectNothingDue(dunningRepository *bankSlipMocks.DunningRepositoryMock) {
+	dunningRepository.On("MoveDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil).Maybe()
+	dunningRepository.On("ClaimNotices", mock.Anything, mock.Anything, mock.Anything).Return([]*bankSlipEntities.BankSlip{}, nil).Maybe()
+}
+
+func TestDunningService_ShouldMoveOverdueAndExpiredBankSlipsInBatches(t *testing.T) {
+	dunningRepository := new(bankSlipMocks.DunningRepositoryMock)
+	service := newTestDunningService(dunningRepository, new(sharedMocks.EmailServicesMock))
+
+	toOverdue := []bankSlipEntities.BankSlipStatus{bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusSendingEmailError}
+	toExpired := []bankSlipEntities.BankSlipStatus{bankSlipEntities.BankSlipStatusSuccess, bankSlipEntities.BankSlipStatusSendingEmailError, bankSlipEntities.BankSlipStatusOverdue}
+	dunningRepository.On("MoveDue", toOverdue, bankSlipEntities.BankSlipStatusOverdue, dunningToday, "due date passed", 2).Return(2, nil).Once()
+	dunningRepository.On("MoveDue", toOverdue, bankSlipEntities.BankSlipStatusOverdue, dunningToday, "due date passed", 2).Return(1, nil).Once()
+	// 30 days before is a Monday, bank slips due on the weekend before it are
+	// within the grace period.
+	dunningRepository.On("MoveDue", toExpired, bankSlipEntities.BankSlipStatusExpired, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), "grace period of 30 days passed", 2).Return(0, nil).Once()
+	expectNothingDue(dunningRepository)
+
+	err := service.Run(dunningToday.Add(15 * time.Hour))
+	assert.NoError(t, err)
+	dunningRepository.AssertNumberOfCalls(t, "MoveDue", 3)
+}
+
+func TestDunningService_ShouldNotMoveBankSlipsDueOnHolidaysBeforeNextBusinessDay(t *testing.T) {
+	dunningRepository := new(bankSlipMocks.DunningRepositoryMock)
+	service := newTestDunningService(dunningRepository, new(sharedMocks.EmailServicesMock))
+
+	// On the Wednesday after Carnaval, bank slips due from Saturday on are
+	// still paid without charges.
+	dunningRepository.On("MoveDue", mock.Anything, bankSlipEntities.BankSlipStatusOverdue, time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC), mock.Anything, mock.Anything).Return(0, nil).Once()
+	expectNothingDue(dunningRepository)
+
+	err := service.Run(time.Date(2026, 2, 18, 9, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	dunningRepository.AssertExpectations(t)
+}
+
+func TestDunningService_ShouldEmailClaimedBankSlipsWithTemplateOfStep(t *testing.T) {
+	dunningRepository := new(bankSlipMocks.DunningRepositoryMock)
+	emailService := new(sharedMocks.EmailServicesMock)
+	service := newTestDunningService(dunningRepository, emailService)
+
+	reminded := newIssuedBankSlip("debt1")
+	noticed := newIssuedBankSlip("debt2")
+	dunningRepository.On("ClaimNotices", dunningReminder, dunningToday, 2).Return([]*bankSlipEntities.BankSlip{reminded}, nil).Once()
+	dunningRepository.On("ClaimNotices", dunningNotice, dunningToday, 2).Return([]*bankSlipEntities.BankSlip{noticed}, nil).Once()
+	expectNothingDue(dunningRepository)
+	emailService.On("SendBankSlipDunningEmail", &bankSlipEntities.BankSlipMap{"debt1": reminded}, "billing_due_reminder_3", dunningToday).
+		Return(&map[bankSlipEntities.DebitId]error{}).Once()
+	emailService.On("SendBankSlipDunningEmail", &bankSlipEntities.BankSlipMap{"debt2": noticed}, "billing_overdue_notice_1", dunningToday).
+		Return(&map[bankSlipEntities.DebitId]error{}).Once()
+
+	err := service.Run(dunningToday)
+	assert.NoError(t, err)
+	emailService.AssertExpectations(t)
+	dunningRepository.AssertNotCalled(t, "ReleaseNotice", mock.Anything, mock.Anything)
+}
+
+func TestDunningService_ShouldReleaseNoticesOfFailedEmailsAfterTheStep(t *testing.T) {
+	dunningRepository := new(bankSlipMocks.DunningRepositoryMock)
+	emailService := new(sharedMocks.EmailServicesMock)
+	service := newTestDunningService(dunningRepository, emailService)
+
+	first, second, third := newIssuedBankSlip("debt1"), newIssuedBankSlip("debt2"), newIssuedBankSlip("debt3")
+	dunningRepository.On("ClaimNotices", dunningReminder, dunningToday, 2).Return([]*bankSlipEntities.BankSlip{first, second}, nil).Once()
+	dunningRepository.On("ClaimNotices", dunningReminder, dunningToday, 2).Return([]*bankSlipEntities.BankSlip{third}, nil).Once()
+	expectNothingDue(dunningRepository)
+	emailService.On("SendBankSlipDunningEmail", &bankSlipEntities.BankSlipMap{"debt1": first, "debt2": second}, "billing_due_reminder_3", dunningToday).
+		Return(&map[bankSlipEntities.DebitId]error{"debt2": errors.New("mailbox full")}).Once()
+	emailService.On("SendBankSlipDunningEmail", &bankSlipEntities.BankSlipMap{"debt3": third}, "billing_due_reminder_3", dunningToday).
+		Return(&map[bankSlipEntities.DebitId]error{}).Once()
+	dunningRepository.On("ReleaseNotice", "debt2", dunningReminder).Return(nil).Once()
+
+	err := service.Run(dunningToday)
+	assert.NoError(t, err)
+	dunningRepository.AssertExpectations(t)
+}
+
+func TestDunningService_ShouldStopWhenRepositoryFails(t *testing.T) {
+	dunningRepository := new(bankSlipMocks.DunningRepositoryMock)
+	emailService := new(sharedMocks.EmailServicesMock)
+	service := newTestDunningService(dunningRepository, emailService)
+
+	dunningRepository.On("MoveDue", mock.Anything, bankSlipEntities.BankSlipStatusOverdue, mock.Anything, mock.Anything, mock.Anything).Return(0, assert.AnError).Once()
+
+	err := service.Run(dunningToday)
+	assert.ErrorIs(t, err, assert.AnError)
+	dunningRepository.AssertNotCalled(t, "ClaimNotices", mock.Anything, mock.Anything, mock.Anything)
+	emailService.AssertNotCalled(t, "SendBankSlipDunningEmail", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,61 @@
+package bank_slip
+
+import (
+	"archive/zip"
+	"io"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type ExportBankSlipFilePdfsServiceInterface interface {
+	Execute(fileId string, w io.Writer) (int, error)
+}
+
+// ExportBankSlipFilePdfsService writes a ZIP with the boleto of every issued
+// bank slip of a file. Each PDF is rendered straight into the archive, so the
+// ZIP is streamed as it's built.
+type ExportBankSlipFilePdfsService struct {
+	bankSlipRepository    bankSlipEntities.BankSlipRepository
+	beneficiaryRepository bankSlipEntities.BeneficiaryRepository
+}
+
+func NewExportBankSlipFilePdfsService(
+	bankSlipRepository bankSlipEntities.BankSlipRepository,
+	beneficiaryRepository bankSlipEntities.BeneficiaryRepository,
+) *ExportBankSlipFilePdfsService {
+	return &ExportBankSlipFilePdfsService{
+		bankSlipRepository:    bankSlipRepository,
+		beneficiaryRepository: beneficiaryRepository,
+	}
+}
+
+// Execute returns how many PDFs were written.
+func (s *ExportBankSlipFilePdfsService) Execute(fileId string, w io.Writer) (int, error) {
+	archive := zip.NewWriter(w)
+	exported := 0
+	issuers := bankSlipEntities.NewBeneficiaryIssuers(s.beneficiaryRepository)
+
+	err := s.bankSlipRepository.ForEachByFileId(fileId, bankSlipEntities.BankSlipIssuedStatuses, func(bankSlip *bankSlipEntities.BankSlip) error {
+		if !bankSlip.Issued() {
+			return nil
+		}
+		issuer, err := issuers.Get(bankSlip.BeneficiaryId)
+		if err != nil {
+			return err
+		}
+		entry, err := archive.Create(BankSlipPdfFileName(bankSlip.DebtId))
+		if err != nil {
+			return err
+		}
+		err = newBoletoDocument(issuer, bankSlip).WritePDF(entry)
+		if err != nil {
+			return err
+		}
+		exported++
+		return nil
+	})
+	if err != nil {
+		return exported, err
+	}
+	return exported, archive.Close()
+}
@@ -0,0 +1,71 @@
+package bank_slip
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportBankSlipFilePdfsService_ShouldWriteOnePdfPerIssuedBankSlip(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	beneficiaryRepository := newTestBeneficiaryRepository()
+	service := NewExportBankSlipFilePdfsService(bankSlipRepository, beneficiaryRepository)
+
+	withoutBoleto := newIssuedBankSlip("debt3")
+	withoutBoleto.Barcode = ""
+	bankSlipRepository.On("ForEachByFileId", "file1", bankSlipEntities.BankSlipIssuedStatuses).
+		Return([]*bankSlipEntities.BankSlip{newIssuedBankSlip("debt1"), newIssuedBankSlip("debt2"), withoutBoleto}, nil).
+		Once()
+
+	var output bytes.Buffer
+	exported, err := service.Execute("file1", &output)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, exported)
+
+	archive, err := zip.NewReader(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	assert.NoError(t, err)
+	assert.Len(t, archive.File, 2)
+	assert.Equal(t, "boleto-debt1.pdf", archive.File[0].Name)
+	assert.Equal(t, "boleto-debt2.pdf", archive.File[1].Name)
+
+	entry, err := archive.File[0].Open()
+	assert.NoError(t, err)
+	pdf, _ := io.ReadAll(entry)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	beneficiaryRepository.AssertNumberOfCalls(t, "GetById", 1)
+}
+
+func TestExportBankSlipFilePdfsService_ShouldWriteEmptyZipWhenNothingWasIssued(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	service := NewExportBankSlipFilePdfsService(bankSlipRepository, newTestBeneficiaryRepository())
+
+	bankSlipRepository.On("ForEachByFileId", "file1", bankSlipEntities.BankSlipIssuedStatuses).Return(nil, nil).Once()
+
+	var output bytes.Buffer
+	exported, err := service.Execute("file1", &output)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, exported)
+
+	archive, err := zip.NewReader(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	assert.NoError(t, err)
+	assert.Empty(t, archive.File)
+}
+
+func TestExportBankSlipFilePdfsService_ShouldStopWhenReadingFails(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	service := NewExportBankSlipFilePdfsService(bankSlipRepository, newTestBeneficiaryRepository())
+
+	bankSlipRepository.On("ForEachByFileId", "file1", bankSlipEntities.BankSlipIssuedStatuses).
+		Return([]*bankSlipEntities.BankSlip{newIssuedBankSlip("debt1")}, assert.AnError).
+		Once()
+
+	exported, err := service.Execute("file1", &bytes.Buffer{})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, exported)
+}
@@ -0,0 +1,31 @@
+package bank_slip
+
+import (
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/calendar"
+)
+
+type GetBankSlipAmountServiceInterface interface {
+	Execute(debtId string, date time.Time) (*bankSlipEntities.BankSlip, bankSlipEntities.AmountDue, error)
+}
+
+type GetBankSlipAmountService struct {
+	bankSlipRepository bankSlipEntities.BankSlipRepository
+	businessDays       *calendar.Calendar
+}
+
+func NewGetBankSlipAmountService(bankSlipRepo bankSlipEntities.BankSlipRepository, businessDays *calendar.Calendar) *GetBankSlipAmountService {
+	return &GetBankSlipAmountService{bankSlipRepository: bankSlipRepo, businessDays: businessDays}
+}
+
+// Execute computes what the bank slip is paid with on date, with the discount,
+// late fee and interest of its charge policy.
+func (s *GetBankSlipAmountService) Execute(debtId string, date time.Time) (*bankSlipEntities.BankSlip, bankSlipEntities.AmountDue, error) {
+	bankSlip, err := s.bankSlipRepository.GetByDebtId(debtId)
+	if err != nil {
+		return nil, bankSlipEntities.AmountDue{}, err
+	}
+	return bankSlip, bankSlip.AmountDue(date, s.businessDays), nil
+}
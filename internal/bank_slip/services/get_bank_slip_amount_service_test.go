@@ -0,0 +1,45 @@
+package bank_slip
+
+import (
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBankSlipAmountService_ShouldFailWhenBankSlipDoesNotExist(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	service := NewGetBankSlipAmountService(bankSlipRepository, calendar.New())
+
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(nil, bankSlipEntities.ErrBankSlipNotFound).Once()
+
+	bankSlip, amountDue, err := service.Execute("debt1", time.Now())
+	assert.Nil(t, bankSlip)
+	assert.Equal(t, bankSlipEntities.AmountDue{}, amountDue)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrBankSlipNotFound)
+}
+
+func TestGetBankSlipAmountService_ShouldApplyChargePolicyOfBankSlip(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	service := NewGetBankSlipAmountService(bankSlipRepository, calendar.New())
+
+	lateFee, _ := bankSlipEntities.ParseCharge("2%", ".")
+	expectedBankSlip := &bankSlipEntities.BankSlip{
+		DebtId:       "debt1",
+		DebtAmount:   100050,
+		DebtDueDate:  time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		ChargePolicy: bankSlipEntities.ChargePolicy{LateFee: &lateFee},
+	}
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(expectedBankSlip, nil).Once()
+
+	bankSlip, amountDue, err := service.Execute("debt1", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedBankSlip, bankSlip)
+	assert.Equal(t, 1, amountDue.DaysLate)
+	assert.Equal(t, bankSlipEntities.Money(2001), amountDue.LateFee)
+	assert.Equal(t, bankSlipEntities.Money(102051), amountDue.Total())
+}
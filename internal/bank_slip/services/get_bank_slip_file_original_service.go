@@ -0,0 +1,48 @@
+package bank_slip
+
+import (
+	"errors"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/handler"
+)
+
+type GetBankSlipFileOriginalServiceInterface interface {
+	Execute(fileId string) (*bankSlipEntities.BankSlipFileMetadata, handler.SavedFile, error)
+}
+
+// GetBankSlipFileOriginalService opens the uploaded file from the storage,
+// while it's still retained.
+type GetBankSlipFileOriginalService struct {
+	bankSlipFileMetadataRepository bankSlipEntities.BankSlipFileMetadataRepository
+	fileHandler                    handler.FileHandler
+}
+
+func NewGetBankSlipFileOriginalService(
+	bankSlipFileRepo bankSlipEntities.BankSlipFileMetadataRepository,
+	fileHandler handler.FileHandler,
+) *GetBankSlipFileOriginalService {
+	return &GetBankSlipFileOriginalService{
+		bankSlipFileMetadataRepository: bankSlipFileRepo,
+		fileHandler:                    fileHandler,
+	}
+}
+
+func (s *GetBankSlipFileOriginalService) Execute(fileId string) (*bankSlipEntities.BankSlipFileMetadata, handler.SavedFile, error) {
+	file, err := s.bankSlipFileMetadataRepository.GetById(fileId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if file.StoragePath == "" {
+		return nil, nil, bankSlipEntities.ErrOriginalFileNotAvailable
+	}
+
+	savedFile, err := s.fileHandler.OpenFile(file.StoragePath)
+	if errors.Is(err, handler.ErrSavedFileNotFound) {
+		return nil, nil, bankSlipEntities.ErrOriginalFileNotAvailable
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, savedFile, nil
+}
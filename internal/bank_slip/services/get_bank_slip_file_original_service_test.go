@@ -0,0 +1,70 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/handler"
+	sharedMocks "performatic-file-processor/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetBankSlipFileOriginalService_ShouldOpenStoredFile(t *testing.T) {
+	fileRepository := new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	fileHandler := new(sharedMocks.FileHandlerMock)
+	service := NewGetBankSlipFileOriginalService(fileRepository, fileHandler)
+
+	expectedFile := bankSlipEntities.NewBankSlipFileMetadata("file.csv")
+	expectedFile.Stored("uploads/file_1.csv")
+	savedFile := sharedMocks.NewSavedFileMock()
+	fileRepository.On("GetById", "any_id").Return(expectedFile, nil).Once()
+	fileHandler.On("OpenFile", "uploads/file_1.csv").Return(savedFile, nil).Once()
+
+	file, original, err := service.Execute("any_id")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedFile, file)
+	assert.Equal(t, savedFile, original)
+}
+
+func TestGetBankSlipFileOriginalService_ShouldFailWhenFileWasNeverStored(t *testing.T) {
+	fileRepository := new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	fileHandler := new(sharedMocks.FileHandlerMock)
+	service := NewGetBankSlipFileOriginalService(fileRepository, fileHandler)
+
+	fileRepository.On("GetById", "any_id").Return(bankSlipEntities.NewBankSlipFileMetadata("file.csv"), nil).Once()
+
+	file, original, err := service.Execute("any_id")
+	assert.Nil(t, file)
+	assert.Nil(t, original)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrOriginalFileNotAvailable)
+	fileHandler.AssertNotCalled(t, "OpenFile", mock.Anything)
+}
+
+func TestGetBankSlipFileOriginalService_ShouldFailWhenFileIsNoLongerRetained(t *testing.T) {
+	fileRepository := new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	fileHandler := new(sharedMocks.FileHandlerMock)
+	service := NewGetBankSlipFileOriginalService(fileRepository, fileHandler)
+
+	storedFile := bankSlipEntities.NewBankSlipFileMetadata("file.csv")
+	storedFile.Stored("uploads/file_1.csv")
+	fileRepository.On("GetById", "any_id").Return(storedFile, nil).Once()
+	fileHandler.On("OpenFile", "uploads/file_1.csv").Return(nil, handler.ErrSavedFileNotFound).Once()
+
+	_, _, err := service.Execute("any_id")
+	assert.ErrorIs(t, err, bankSlipEntities.ErrOriginalFileNotAvailable)
+}
+
+func TestGetBankSlipFileOriginalService_ShouldNotOpenAnythingWhenFileDoesNotExist(t *testing.T) {
+	fileRepository := new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	fileHandler := new(sharedMocks.FileHandlerMock)
+	service := NewGetBankSlipFileOriginalService(fileRepository, fileHandler)
+
+	fileRepository.On("GetById", "any_id").Return(nil, bankSlipEntities.ErrBankSlipFileNotFound).Once()
+
+	_, _, err := service.Execute("any_id")
+	assert.ErrorIs(t, err, bankSlipEntities.ErrBankSlipFileNotFound)
+	fileHandler.AssertNotCalled(t, "OpenFile", mock.Anything)
+}
@@ -0,0 +1,25 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type GetBankSlipFileServiceInterface interface {
+	Execute(fileId string) (*bankSlipEntities.BankSlipFileMetadata, error)
+}
+
+type GetBankSlipFileService struct {
+	bankSlipFileMetadataRepository bankSlipEntities.BankSlipFileMetadataRepository
+}
+
+func NewGetBankSlipFileService(
+	bankSlipFileRepo bankSlipEntities.BankSlipFileMetadataRepository,
+) *GetBankSlipFileService {
+	return &GetBankSlipFileService{
+		bankSlipFileMetadataRepository: bankSlipFileRepo,
+	}
+}
+
+func (s *GetBankSlipFileService) Execute(fileId string) (*bankSlipEntities.BankSlipFileMetadata, error) {
+	return s.bankSlipFileMetadataRepository.GetById(fileId)
+}
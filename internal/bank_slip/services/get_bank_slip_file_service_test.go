@@ -0,0 +1,21 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBankSlipFileService_ShouldReturnRepositoryResult(t *testing.T) {
+	repository := new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	service := NewGetBankSlipFileService(repository)
+
+	repository.On("GetById", "any_id").Return(nil, bankSlipEntities.ErrBankSlipFileNotFound).Once()
+
+	file, err := service.Execute("any_id")
+	assert.Nil(t, file)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrBankSlipFileNotFound)
+}
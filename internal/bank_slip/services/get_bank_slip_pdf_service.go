@@ -0,0 +1,73 @@
+package bank_slip
+
+import (
+	"bytes"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/boleto"
+)
+
+type GetBankSlipPdfServiceInterface interface {
+	Execute(debtId string) (*bankSlipEntities.BankSlip, []byte, error)
+}
+
+// GetBankSlipPdfService renders the printable boleto of an issued bank slip.
+type GetBankSlipPdfService struct {
+	bankSlipRepository    bankSlipEntities.BankSlipRepository
+	beneficiaryRepository bankSlipEntities.BeneficiaryRepository
+}
+
+func NewGetBankSlipPdfService(
+	bankSlipRepository bankSlipEntities.BankSlipRepository,
+	beneficiaryRepository bankSlipEntities.BeneficiaryRepository,
+) *GetBankSlipPdfService {
+	return &GetBankSlipPdfService{
+		bankSlipRepository:    bankSlipRepository,
+		beneficiaryRepository: beneficiaryRepository,
+	}
+}
+
+func (s *GetBankSlipPdfService) Execute(debtId string) (*bankSlipEntities.BankSlip, []byte, error) {
+	bankSlip, err := s.bankSlipRepository.GetByDebtId(debtId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bankSlip.Issued() {
+		return nil, nil, bankSlipEntities.ErrBankSlipNotIssued
+	}
+
+	beneficiary, err := s.beneficiaryRepository.GetById(bankSlip.BeneficiaryId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pdf bytes.Buffer
+	err = newBoletoDocument(beneficiary.Issuer(), bankSlip).WritePDF(&pdf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bankSlip, pdf.Bytes(), nil
+}
+
+func newBoletoDocument(issuer boleto.Issuer, bankSlip *bankSlipEntities.BankSlip) boleto.Document {
+	return boleto.Document{
+		BankCode:         issuer.BankCode,
+		Beneficiary:      issuer.Beneficiary,
+		AgencyAndAccount: issuer.AgencyAndAccount(),
+		Wallet:           issuer.Wallet,
+		OurNumber:        issuer.FormatOurNumber(bankSlip.OurNumber),
+		DocumentNumber:   bankSlip.DebtId,
+		PayerName:        bankSlip.UserName,
+		PayerDocument:    bankSlip.GovernmentId.Formatted(),
+		Amount:           bankSlip.DebtAmount.BRL(),
+		DueDate:          bankSlip.DebtDueDate,
+		DigitableLine:    bankSlip.DigitableLine,
+		Barcode:          bankSlip.Barcode,
+		Instructions:     bankSlip.ChargePolicy.Instructions(bankSlip.DebtDueDate),
+	}
+}
+
+// BankSlipPdfFileName names the PDF of a bank slip after its debt id.
+func BankSlipPdfFileName(debtId string) string {
+	return "boleto-" + debtId + ".pdf"
+}
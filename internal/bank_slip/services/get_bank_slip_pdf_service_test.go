@@ -0,0 +1,107 @@
+package bank_slip
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPdfBeneficiaryId = "a1b2c3d4-0000-4000-8000-000000000001"
+
+// newTestBeneficiaryRepository finds the beneficiary the bank slips of
+// newIssuedBankSlip are issued to.
+func newTestBeneficiaryRepository() *bankSlipMocks.BeneficiaryRepositoryMock {
+	beneficiary, _ := bankSlipEntities.NewBeneficiary("Cobranças Ltda", "11.222.333/0001-81", "237", "1234", "12345", "09", "")
+	beneficiary.ID = testPdfBeneficiaryId
+
+	beneficiaryRepository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	beneficiaryRepository.On("GetById", testPdfBeneficiaryId).Return(beneficiary, nil)
+	return beneficiaryRepository
+}
+
+func newIssuedBankSlip(debtId string) *bankSlipEntities.BankSlip {
+	return &bankSlipEntities.BankSlip{
+		DebtId:        debtId,
+		DebtAmount:    100050,
+		DebtDueDate:   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		GovernmentId:  "52998224725",
+		UserName:      "John Doe",
+		Status:        bankSlipEntities.BankSlipStatusSuccess,
+		BeneficiaryId: testPdfBeneficiaryId,
+		OurNumber:     42,
+		Barcode:       "23792131200001000501234090000000004200123450",
+		DigitableLine: "23791234059000000000142001234501213120000100050",
+	}
+}
+
+func TestGetBankSlipPdfService_ShouldRenderIssuedBankSlip(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	service := NewGetBankSlipPdfService(bankSlipRepository, newTestBeneficiaryRepository())
+
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(newIssuedBankSlip("debt1"), nil).Once()
+
+	bankSlip, pdf, err := service.Execute("debt1")
+	assert.NoError(t, err)
+	assert.Equal(t, "debt1", bankSlip.DebtId)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	assert.Contains(t, string(pdf), "(23791.23405 90000.000001 42001.234501 2 13120000100050) Tj")
+	assert.Contains(t, string(pdf), "(John Doe - CPF/CNPJ: 529.982.247-25) Tj")
+	assert.Contains(t, string(pdf), "(09/00000000042-9) Tj")
+	assert.Contains(t, string(pdf), "(R$ 1.000,50) Tj")
+}
+
+func TestGetBankSlipPdfService_ShouldRenderOverdueBankSlip(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	service := NewGetBankSlipPdfService(bankSlipRepository, newTestBeneficiaryRepository())
+
+	overdue := newIssuedBankSlip("debt1")
+	overdue.Status = bankSlipEntities.BankSlipStatusOverdue
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(overdue, nil).Once()
+
+	_, pdf, err := service.Execute("debt1")
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+}
+
+func TestGetBankSlipPdfService_ShouldRejectBankSlipNotIssued(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	service := NewGetBankSlipPdfService(bankSlipRepository, newTestBeneficiaryRepository())
+
+	pending := newIssuedBankSlip("debt1")
+	pending.Status = bankSlipEntities.BankSlipStatusPending
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(pending, nil).Once()
+
+	bankSlip, pdf, err := service.Execute("debt1")
+	assert.Nil(t, bankSlip)
+	assert.Nil(t, pdf)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrBankSlipNotIssued)
+}
+
+func TestGetBankSlipPdfService_ShouldFailWhenBankSlipDoesNotExist(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	service := NewGetBankSlipPdfService(bankSlipRepository, newTestBeneficiaryRepository())
+
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(nil, bankSlipEntities.ErrBankSlipNotFound).Once()
+
+	_, _, err := service.Execute("debt1")
+	assert.ErrorIs(t, err, bankSlipEntities.ErrBankSlipNotFound)
+}
+
+func TestGetBankSlipPdfService_ShouldFailWhenBeneficiaryDoesNotExist(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	beneficiaryRepository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewGetBankSlipPdfService(bankSlipRepository, beneficiaryRepository)
+
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(newIssuedBankSlip("debt1"), nil).Once()
+	beneficiaryRepository.On("GetById", testPdfBeneficiaryId).Return(nil, bankSlipEntities.ErrBeneficiaryNotFound).Once()
+
+	bankSlip, pdf, err := service.Execute("debt1")
+	assert.Nil(t, bankSlip)
+	assert.Nil(t, pdf)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrBeneficiaryNotFound)
+}
@@ -0,0 +1,37 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type GetBankSlipRejectedRowsServiceInterface interface {
+	Execute(fileId string) (*bankSlipEntities.BankSlipFileMetadata, []*bankSlipEntities.BankSlipRejectedRow, error)
+}
+
+type GetBankSlipRejectedRowsService struct {
+	bankSlipFileMetadataRepository bankSlipEntities.BankSlipFileMetadataRepository
+	bankSlipRejectedRowRepository  bankSlipEntities.BankSlipRejectedRowRepository
+}
+
+func NewGetBankSlipRejectedRowsService(
+	bankSlipFileRepo bankSlipEntities.BankSlipFileMetadataRepository,
+	bankSlipRejectedRowRepo bankSlipEntities.BankSlipRejectedRowRepository,
+) *GetBankSlipRejectedRowsService {
+	return &GetBankSlipRejectedRowsService{
+		bankSlipFileMetadataRepository: bankSlipFileRepo,
+		bankSlipRejectedRowRepository:  bankSlipRejectedRowRepo,
+	}
+}
+
+func (s *GetBankSlipRejectedRowsService) Execute(fileId string) (*bankSlipEntities.BankSlipFileMetadata, []*bankSlipEntities.BankSlipRejectedRow, error) {
+	file, err := s.bankSlipFileMetadataRepository.GetById(fileId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rejectedRows, err := s.bankSlipRejectedRowRepository.ListByFileId(fileId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, rejectedRows, nil
+}
@@ -0,0 +1,42 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBankSlipRejectedRowsService_ShouldNotListRowsWhenFileDoesNotExist(t *testing.T) {
+	fileRepository := new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	rejectedRowRepository := new(bankSlipMocks.BankSlipRejectedRowRepositoryMock)
+	service := NewGetBankSlipRejectedRowsService(fileRepository, rejectedRowRepository)
+
+	fileRepository.On("GetById", "any_id").Return(nil, bankSlipEntities.ErrBankSlipFileNotFound).Once()
+
+	file, rejectedRows, err := service.Execute("any_id")
+	assert.Nil(t, file)
+	assert.Nil(t, rejectedRows)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrBankSlipFileNotFound)
+	rejectedRowRepository.AssertNotCalled(t, "ListByFileId", "any_id")
+}
+
+func TestGetBankSlipRejectedRowsService_ShouldReturnFileAndRejectedRows(t *testing.T) {
+	fileRepository := new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	rejectedRowRepository := new(bankSlipMocks.BankSlipRejectedRowRepositoryMock)
+	service := NewGetBankSlipRejectedRowsService(fileRepository, rejectedRowRepository)
+
+	expectedFile := bankSlipEntities.NewBankSlipFileMetadata("file.csv")
+	expectedRows := []*bankSlipEntities.BankSlipRejectedRow{
+		bankSlipEntities.NewBankSlipRejectedRow("any_id", 2, "John,abc", bankSlipEntities.RejectionCodeInvalidGovernmentId),
+	}
+	fileRepository.On("GetById", "any_id").Return(expectedFile, nil).Once()
+	rejectedRowRepository.On("ListByFileId", "any_id").Return(expectedRows, nil).Once()
+
+	file, rejectedRows, err := service.Execute("any_id")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedFile, file)
+	assert.Equal(t, expectedRows, rejectedRows)
+}
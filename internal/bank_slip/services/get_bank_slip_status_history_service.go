@@ -0,0 +1,37 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type GetBankSlipStatusHistoryServiceInterface interface {
+	Execute(debtId string) (*bankSlipEntities.BankSlip, []*bankSlipEntities.BankSlipStatusChange, error)
+}
+
+type GetBankSlipStatusHistoryService struct {
+	bankSlipRepository              bankSlipEntities.BankSlipRepository
+	bankSlipStatusHistoryRepository bankSlipEntities.BankSlipStatusHistoryRepository
+}
+
+func NewGetBankSlipStatusHistoryService(
+	bankSlipRepo bankSlipEntities.BankSlipRepository,
+	bankSlipStatusHistoryRepo bankSlipEntities.BankSlipStatusHistoryRepository,
+) *GetBankSlipStatusHistoryService {
+	return &GetBankSlipStatusHistoryService{
+		bankSlipRepository:              bankSlipRepo,
+		bankSlipStatusHistoryRepository: bankSlipStatusHistoryRepo,
+	}
+}
+
+func (s *GetBankSlipStatusHistoryService) Execute(debtId string) (*bankSlipEntities.BankSlip, []*bankSlipEntities.BankSlipStatusChange, error) {
+	bankSlip, err := s.bankSlipRepository.GetByDebtId(debtId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	history, err := s.bankSlipStatusHistoryRepository.ListByDebtId(debtId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bankSlip, history, nil
+}
@@ -0,0 +1,42 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBankSlipStatusHistoryService_ShouldNotListHistoryWhenBankSlipDoesNotExist(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	historyRepository := new(bankSlipMocks.BankSlipStatusHistoryRepositoryMock)
+	service := NewGetBankSlipStatusHistoryService(bankSlipRepository, historyRepository)
+
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(nil, bankSlipEntities.ErrBankSlipNotFound).Once()
+
+	bankSlip, history, err := service.Execute("debt1")
+	assert.Nil(t, bankSlip)
+	assert.Nil(t, history)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrBankSlipNotFound)
+	historyRepository.AssertNotCalled(t, "ListByDebtId", "debt1")
+}
+
+func TestGetBankSlipStatusHistoryService_ShouldReturnBankSlipAndHistory(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	historyRepository := new(bankSlipMocks.BankSlipStatusHistoryRepositoryMock)
+	service := NewGetBankSlipStatusHistoryService(bankSlipRepository, historyRepository)
+
+	expectedBankSlip := &bankSlipEntities.BankSlip{DebtId: "debt1", Status: bankSlipEntities.BankSlipStatusSuccess}
+	expectedHistory := []*bankSlipEntities.BankSlipStatusChange{
+		bankSlipEntities.NewBankSlipStatusChange("debt1", bankSlipEntities.BankSlipStatusPending, bankSlipEntities.BankSlipStatusSuccess, ""),
+	}
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(expectedBankSlip, nil).Once()
+	historyRepository.On("ListByDebtId", "debt1").Return(expectedHistory, nil).Once()
+
+	bankSlip, history, err := service.Execute("debt1")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedBankSlip, bankSlip)
+	assert.Equal(t, expectedHistory, history)
+}
@@ -0,0 +1,25 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type GetRemittanceServiceInterface interface {
+	Execute(remittanceId string) (*bankSlipEntities.Remittance, error)
+}
+
+type GetRemittanceService struct {
+	remittanceRepository bankSlipEntities.RemittanceRepository
+}
+
+func NewGetRemittanceService(
+	remittanceRepo bankSlipEntities.RemittanceRepository,
+) *GetRemittanceService {
+	return &GetRemittanceService{
+		remittanceRepository: remittanceRepo,
+	}
+}
+
+func (s *GetRemittanceService) Execute(remittanceId string) (*bankSlipEntities.Remittance, error) {
+	return s.remittanceRepository.GetById(remittanceId)
+}
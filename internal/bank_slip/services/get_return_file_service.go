@@ -0,0 +1,25 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type GetReturnFileServiceInterface interface {
+	Execute(returnFileId string) (*bankSlipEntities.BankSlipReturnFile, error)
+}
+
+type GetReturnFileService struct {
+	returnFileRepository bankSlipEntities.BankSlipReturnFileRepository
+}
+
+func NewGetReturnFileService(
+	returnFileRepo bankSlipEntities.BankSlipReturnFileRepository,
+) *GetReturnFileService {
+	return &GetReturnFileService{
+		returnFileRepository: returnFileRepo,
+	}
+}
+
+func (s *GetReturnFileService) Execute(returnFileId string) (*bankSlipEntities.BankSlipReturnFile, error) {
+	return s.returnFileRepository.GetById(returnFileId)
+}
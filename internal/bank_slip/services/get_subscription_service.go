@@ -0,0 +1,21 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type GetSubscriptionServiceInterface interface {
+	Execute(id string) (*bankSlipEntities.Subscription, error)
+}
+
+type GetSubscriptionService struct {
+	subscriptionRepository bankSlipEntities.SubscriptionRepository
+}
+
+func NewGetSubscriptionService(subscriptionRepo bankSlipEntities.SubscriptionRepository) *GetSubscriptionService {
+	return &GetSubscriptionService{subscriptionRepository: subscriptionRepo}
+}
+
+func (s *GetSubscriptionService) Execute(id string) (*bankSlipEntities.Subscription, error) {
+	return s.subscriptionRepository.GetById(id)
+}
@@ -0,0 +1,37 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type GetUnmatchedReturnRecordsServiceInterface interface {
+	Execute(returnFileId string) (*bankSlipEntities.BankSlipReturnFile, []*bankSlipEntities.UnmatchedReturnRecord, error)
+}
+
+type GetUnmatchedReturnRecordsService struct {
+	returnFileRepository            bankSlipEntities.BankSlipReturnFileRepository
+	unmatchedReturnRecordRepository bankSlipEntities.UnmatchedReturnRecordRepository
+}
+
+func NewGetUnmatchedReturnRecordsService(
+	returnFileRepo bankSlipEntities.BankSlipReturnFileRepository,
+	unmatchedReturnRecordRepo bankSlipEntities.UnmatchedReturnRecordRepository,
+) *GetUnmatchedReturnRecordsService {
+	return &GetUnmatchedReturnRecordsService{
+		returnFileRepository:            returnFileRepo,
+		unmatchedReturnRecordRepository: unmatchedReturnRecordRepo,
+	}
+}
+
+func (s *GetUnmatchedReturnRecordsService) Execute(returnFileId string) (*bankSlipEntities.BankSlipReturnFile, []*bankSlipEntities.UnmatchedReturnRecord, error) {
+	returnFile, err := s.returnFileRepository.GetById(returnFileId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unmatchedRecords, err := s.unmatchedReturnRecordRepository.ListByReturnFileId(returnFileId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return returnFile, unmatchedRecords, nil
+}
@@ -0,0 +1,106 @@
+package bank_slip
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipProviders "performatic-file-processor/internal/bank_slip/providers"
+)
+
+type IssueScheduledInstallmentsServiceInterface interface {
+	Execute(ctx context.Context)
+}
+
+// installmentBillingTimeout is how long an installment moved to PENDING waits
+// for its billing before another run takes it for left behind and bills it.
+const installmentBillingTimeout = 15 * time.Minute
+
+// IssueScheduledInstallmentsService bills the SCHEDULED installments whose
+// issuance window opened, issuanceWindowDays before their due date. Every
+// worker may run it: installments are moved to PENDING in the database before
+// they are billed, so none is billed twice. The ones a worker moved but didn't
+// finish billing, as when it stopped or the update failed, are billed again
+// installmentBillingTimeout later.
+type IssueScheduledInstallmentsService struct {
+	installmentRepository       bankSlipEntities.InstallmentRepository
+	bankSlipRepository          bankSlipEntities.BankSlipRepository
+	generateBillingAndSentEmail bankSlipProviders.GenerateBillingAndSentEmailProvider
+	issuanceWindowDays          int
+	interval                    time.Duration
+	batchSize                   int
+}
+
+func NewIssueScheduledInstallmentsService(
+	installmentRepository bankSlipEntities.InstallmentRepository,
+	bankSlipRepository bankSlipEntities.BankSlipRepository,
+	generateBillingAndSentEmail bankSlipProviders.GenerateBillingAndSentEmailProvider,
+	issuanceWindowDays int,
+	interval time.Duration,
+	batchSize int,
+) *IssueScheduledInstallmentsService {
+	return &IssueScheduledInstallmentsService{
+		installmentRepository:       installmentRepository,
+		bankSlipRepository:          bankSlipRepository,
+		generateBillingAndSentEmail: generateBillingAndSentEmail,
+		issuanceWindowDays:          issuanceWindowDays,
+		interval:                    interval,
+		batchSize:                   batchSize,
+	}
+}
+
+func (s *IssueScheduledInstallmentsService) Execute(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Run(time.Now()); err != nil {
+			log.Printf("Error issuing scheduled installments: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run bills the installments due until issuanceWindowDays after date, in
+// batches so each transaction locks few rows.
+func (s *IssueScheduledInstallmentsService) Run(date time.Time) error {
+	today := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	dueUntil := today.AddDate(0, 0, s.issuanceWindowDays)
+
+	total := 0
+	defer func() {
+		if total > 0 {
+			log.Printf("Issued %d scheduled installments", total)
+		}
+	}()
+	for {
+		installments, err := s.installmentRepository.IssueScheduled(dueUntil, date.Add(-installmentBillingTimeout), s.batchSize)
+		if err != nil {
+			return fmt.Errorf("error moving scheduled installments to pending: %w", err)
+		}
+		if len(installments) == 0 {
+			return nil
+		}
+
+		bankSlips := bankSlipEntities.BankSlipMap{}
+		for _, installment := range installments {
+			bankSlips[installment.DebtId] = installment
+		}
+		debitsWithErrors := s.generateBillingAndSentEmail.GenerateBillingAndSentEmail(&bankSlips)
+		if err := s.bankSlipRepository.UpdateMany(&bankSlips, debitsWithErrors); err != nil {
+			return fmt.Errorf("error updating issued installments: %w", err)
+		}
+		total += len(installments)
+
+		if len(installments) < s.batchSize {
+			return nil
+		}
+	}
+}
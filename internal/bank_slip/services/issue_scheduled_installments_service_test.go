@@ -0,0 +1,70 @@
+package bank_slip
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newScheduledInstallment(debtId string) *bankSlipEntities.BankSlip {
+	return &bankSlipEntities.BankSlip{
+		DebtId:            debtId,
+		DebtAmount:        33350,
+		DebtDueDate:       time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+		Status:            bankSlipEntities.BankSlipStatusPending,
+		ParentDebtId:      "e1f4b1f2-7c1a-4b8e-9a3d-2f6c8d9e0a11",
+		InstallmentNumber: 2,
+		InstallmentCount:  3,
+	}
+}
+
+func TestIssueScheduledInstallmentsService_ShouldBillInstallmentsInWindowInBatches(t *testing.T) {
+	installmentRepository := new(bankSlipMocks.InstallmentRepositoryMock)
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	provider := new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	service := NewIssueScheduledInstallmentsService(installmentRepository, bankSlipRepository, provider, 30, time.Hour, 2)
+
+	first, second, third := newScheduledInstallment("debt1"), newScheduledInstallment("debt2"), newScheduledInstallment("debt3")
+	dueUntil := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	staleBefore := time.Date(2026, 4, 1, 9, 45, 0, 0, time.UTC)
+	installmentRepository.On("IssueScheduled", dueUntil, staleBefore, 2).Return([]*bankSlipEntities.BankSlip{first, second}, nil).Once()
+	installmentRepository.On("IssueScheduled", dueUntil, staleBefore, 2).Return([]*bankSlipEntities.BankSlip{third}, nil).Once()
+	withErrors := &bankSlipEntities.BankSlipMap{}
+	provider.On("GenerateBillingAndSentEmail", &bankSlipEntities.BankSlipMap{"debt1": first, "debt2": second}).Return(withErrors).Once()
+	provider.On("GenerateBillingAndSentEmail", &bankSlipEntities.BankSlipMap{"debt3": third}).Return(withErrors).Once()
+	bankSlipRepository.On("UpdateMany", mock.Anything, withErrors).Return(nil).Twice()
+
+	err := service.Run(time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	installmentRepository.AssertExpectations(t)
+	provider.AssertExpectations(t)
+	bankSlipRepository.AssertExpectations(t)
+}
+
+func TestIssueScheduledInstallmentsService_ShouldStopWhenNoInstallmentIsDue(t *testing.T) {
+	installmentRepository := new(bankSlipMocks.InstallmentRepositoryMock)
+	provider := new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	service := NewIssueScheduledInstallmentsService(installmentRepository, new(bankSlipMocks.BankSlipRepositoryMock), provider, 30, time.Hour, 2)
+
+	installmentRepository.On("IssueScheduled", mock.Anything, mock.Anything, 2).Return([]*bankSlipEntities.BankSlip{}, nil).Once()
+
+	err := service.Run(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	provider.AssertNotCalled(t, "GenerateBillingAndSentEmail", mock.Anything)
+}
+
+func TestIssueScheduledInstallmentsService_ShouldFailWhenInstallmentsCantBeIssued(t *testing.T) {
+	installmentRepository := new(bankSlipMocks.InstallmentRepositoryMock)
+	service := NewIssueScheduledInstallmentsService(installmentRepository, new(bankSlipMocks.BankSlipRepositoryMock), new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock), 30, time.Hour, 2)
+
+	installmentRepository.On("IssueScheduled", mock.Anything, mock.Anything, 2).Return(nil, errors.New("connection lost")).Once()
+
+	err := service.Run(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorContains(t, err, "connection lost")
+}
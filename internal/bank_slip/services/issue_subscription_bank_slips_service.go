@@ -0,0 +1,154 @@
+package bank_slip
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipProviders "performatic-file-processor/internal/bank_slip/providers"
+	"performatic-file-processor/internal/calendar"
+)
+
+type IssueSubscriptionBankSlipsServiceInterface interface {
+	Execute(ctx context.Context)
+}
+
+// IssueSubscriptionBankSlipsService issues the bank slip of the next period of
+// the subscriptions, issuanceWindowDays before it's due, and bills it as the
+// rows of an upload. Every worker may run it: the debt id of a bank slip is
+// derived from its subscription and period, so a period issued twice is only
+// stored once, and a subscription only moves to its next period once the bank
+// slip of the current one is billed.
+type IssueSubscriptionBankSlipsService struct {
+	subscriptionRepository      bankSlipEntities.SubscriptionRepository
+	bankSlipRepository          bankSlipEntities.BankSlipRepository
+	generateBillingAndSentEmail bankSlipProviders.GenerateBillingAndSentEmailProvider
+	businessDays                *calendar.Calendar
+	issuanceWindowDays          int
+	interval                    time.Duration
+	batchSize                   int
+}
+
+func NewIssueSubscriptionBankSlipsService(
+	subscriptionRepository bankSlipEntities.SubscriptionRepository,
+	bankSlipRepository bankSlipEntities.BankSlipRepository,
+	generateBillingAndSentEmail bankSlipProviders.GenerateBillingAndSentEmailProvider,
+	businessDays *calendar.Calendar,
+	issuanceWindowDays int,
+	interval time.Duration,
+	batchSize int,
+) *IssueSubscriptionBankSlipsService {
+	return &IssueSubscriptionBankSlipsService{
+		subscriptionRepository:      subscriptionRepository,
+		bankSlipRepository:          bankSlipRepository,
+		generateBillingAndSentEmail: generateBillingAndSentEmail,
+		businessDays:                businessDays,
+		issuanceWindowDays:          issuanceWindowDays,
+		interval:                    interval,
+		batchSize:                   batchSize,
+	}
+}
+
+func (s *IssueSubscriptionBankSlipsService) Execute(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Run(time.Now()); err != nil {
+			log.Printf("Error issuing subscription bank slips: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run issues the bank slips of the subscriptions due until issuanceWindowDays
+// after date. A subscription behind by more than a period, as after the
+// workers were stopped, gets a bank slip per batch until it catches up.
+func (s *IssueSubscriptionBankSlipsService) Run(date time.Time) error {
+	today := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	dueUntil := today.AddDate(0, 0, s.issuanceWindowDays)
+
+	total := 0
+	defer func() {
+		if total > 0 {
+			log.Printf("Issued %d subscription bank slips", total)
+		}
+	}()
+	for {
+		subscriptions, err := s.subscriptionRepository.ListDue(dueUntil, s.batchSize)
+		if err != nil {
+			return fmt.Errorf("error listing due subscriptions: %w", err)
+		}
+		if len(subscriptions) == 0 {
+			return nil
+		}
+
+		issued, err := s.issue(subscriptions)
+		if err != nil {
+			return err
+		}
+		total += issued
+
+		// Every bank slip of the batch is billed by now, a batch that failed
+		// is issued again by the next run.
+		for _, subscription := range subscriptions {
+			fromPeriod := subscription.NextPeriod
+			subscription.Advance(s.businessDays)
+			if err := s.subscriptionRepository.Advance(subscription, fromPeriod); err != nil {
+				return fmt.Errorf("error advancing subscription %s: %w", subscription.ID, err)
+			}
+		}
+
+		if len(subscriptions) < s.batchSize {
+			return nil
+		}
+	}
+}
+
+// issue stores the bank slips of the next period of the subscriptions and
+// bills the ones still PENDING: the ones it stored and the ones a run that
+// stopped before billing them left behind. Bank slips billed before, by
+// another worker or by a run that stopped before advancing the subscription,
+// are left as they are.
+func (s *IssueSubscriptionBankSlipsService) issue(subscriptions []*bankSlipEntities.Subscription) (int, error) {
+	bankSlips := bankSlipEntities.BankSlipMap{}
+	for _, subscription := range subscriptions {
+		bankSlip := subscription.NextBankSlip()
+		bankSlips[bankSlip.DebtId] = bankSlip
+	}
+
+	inserted, err := s.bankSlipRepository.InsertMany(&bankSlips)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting subscription bank slips: %w", err)
+	}
+	for debtId, success := range inserted {
+		if success {
+			continue
+		}
+		stored, err := s.bankSlipRepository.GetByDebtId(debtId)
+		if err != nil {
+			return 0, fmt.Errorf("error reading subscription bank slip %s: %w", debtId, err)
+		}
+		if stored.Status == bankSlipEntities.BankSlipStatusPending {
+			bankSlips[debtId] = stored
+		} else {
+			delete(bankSlips, debtId)
+		}
+	}
+	if len(bankSlips) == 0 {
+		return 0, nil
+	}
+
+	debitsWithErrors := s.generateBillingAndSentEmail.GenerateBillingAndSentEmail(&bankSlips)
+	if err := s.bankSlipRepository.UpdateMany(&bankSlips, debitsWithErrors); err != nil {
+		return 0, fmt.Errorf("error updating subscription bank slips: %w", err)
+	}
+	return len(bankSlips), nil
+}
@@ -0,0 +1,111 @@
+package bank_slip
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	testSubscriptionPeriod  = time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	testSubscriptionDueDate = time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC)
+)
+
+func newDueSubscription(id string) *bankSlipEntities.Subscription {
+	return &bankSlipEntities.Subscription{
+		ID:            id,
+		BeneficiaryId: "beneficiary1",
+		UserName:      "John Doe",
+		GovernmentId:  "52998224725",
+		UserEmail:     "john.doe@example.com",
+		Amount:        15000,
+		DayOfMonth:    10,
+		StartDate:     testSubscriptionPeriod,
+		Status:        bankSlipEntities.SubscriptionStatusActive,
+		NextPeriod:    testSubscriptionPeriod,
+		NextDueDate:   testSubscriptionDueDate,
+	}
+}
+
+func TestIssueSubscriptionBankSlipsService_ShouldBillBankSlipsStillPending(t *testing.T) {
+	subscriptionRepository := new(bankSlipMocks.SubscriptionRepositoryMock)
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	provider := new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	service := NewIssueSubscriptionBankSlipsService(subscriptionRepository, bankSlipRepository, provider, calendar.New(), 10, time.Hour, 3)
+
+	first := newDueSubscription("6b1f9c2e-3d4a-4f5b-8c6d-7e8f9a0b1c2d")
+	second := newDueSubscription("0c9d8e7f-6a5b-4c3d-9e2f-1a0b9c8d7e6f")
+	third := newDueSubscription("3e4f5a6b-7c8d-4e9f-8a0b-1c2d3e4f5a6b")
+	firstDebtId := bankSlipEntities.SubscriptionDebtId(first.ID, testSubscriptionPeriod)
+	secondDebtId := bankSlipEntities.SubscriptionDebtId(second.ID, testSubscriptionPeriod)
+	thirdDebtId := bankSlipEntities.SubscriptionDebtId(third.ID, testSubscriptionPeriod)
+	firstBankSlip := first.NextBankSlip()
+	billed := second.NextBankSlip()
+	billed.Status = bankSlipEntities.BankSlipStatusSuccess
+	leftPending := third.NextBankSlip()
+	leftPending.OurNumber = 7
+
+	dueUntil := time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC)
+	subscriptionRepository.On("ListDue", dueUntil, 3).Return([]*bankSlipEntities.Subscription{first, second, third}, nil).Once()
+	subscriptionRepository.On("ListDue", dueUntil, 3).Return([]*bankSlipEntities.Subscription{}, nil).Once()
+	bankSlipRepository.On("InsertMany", &bankSlipEntities.BankSlipMap{firstDebtId: firstBankSlip, secondDebtId: second.NextBankSlip(), thirdDebtId: third.NextBankSlip()}).
+		Return(map[string]bool{firstDebtId: true, secondDebtId: false, thirdDebtId: false}, nil).Once()
+	bankSlipRepository.On("GetByDebtId", secondDebtId).Return(billed, nil).Once()
+	bankSlipRepository.On("GetByDebtId", thirdDebtId).Return(leftPending, nil).Once()
+	toBill := &bankSlipEntities.BankSlipMap{firstDebtId: firstBankSlip, thirdDebtId: leftPending}
+	withErrors := &bankSlipEntities.BankSlipMap{}
+	provider.On("GenerateBillingAndSentEmail", toBill).Return(withErrors).Once()
+	bankSlipRepository.On("UpdateMany", toBill, withErrors).Return(nil).Once()
+	subscriptionRepository.On("Advance", first, testSubscriptionPeriod).Return(nil).Once()
+	subscriptionRepository.On("Advance", second, testSubscriptionPeriod).Return(nil).Once()
+	subscriptionRepository.On("Advance", third, testSubscriptionPeriod).Return(nil).Once()
+
+	err := service.Run(time.Date(2026, 10, 31, 9, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), first.NextPeriod)
+	assert.Equal(t, time.Date(2026, 12, 10, 0, 0, 0, 0, time.UTC), first.NextDueDate)
+	subscriptionRepository.AssertExpectations(t)
+	bankSlipRepository.AssertExpectations(t)
+	provider.AssertExpectations(t)
+}
+
+func TestIssueSubscriptionBankSlipsService_ShouldNotAdvanceWhenBankSlipsCantBeStored(t *testing.T) {
+	subscriptionRepository := new(bankSlipMocks.SubscriptionRepositoryMock)
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	provider := new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	service := NewIssueSubscriptionBankSlipsService(subscriptionRepository, bankSlipRepository, provider, calendar.New(), 10, time.Hour, 2)
+
+	subscriptionRepository.On("ListDue", mock.Anything, 2).Return([]*bankSlipEntities.Subscription{newDueSubscription("6b1f9c2e-3d4a-4f5b-8c6d-7e8f9a0b1c2d")}, nil).Once()
+	bankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{}, errors.New("connection lost")).Once()
+
+	err := service.Run(time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC))
+	assert.ErrorContains(t, err, "connection lost")
+	subscriptionRepository.AssertNotCalled(t, "Advance", mock.Anything, mock.Anything)
+	provider.AssertNotCalled(t, "GenerateBillingAndSentEmail", mock.Anything)
+}
+
+func TestIssueSubscriptionBankSlipsService_ShouldNotAdvanceWhenBankSlipsCantBeUpdated(t *testing.T) {
+	subscriptionRepository := new(bankSlipMocks.SubscriptionRepositoryMock)
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	provider := new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	service := NewIssueSubscriptionBankSlipsService(subscriptionRepository, bankSlipRepository, provider, calendar.New(), 10, time.Hour, 2)
+
+	subscription := newDueSubscription("6b1f9c2e-3d4a-4f5b-8c6d-7e8f9a0b1c2d")
+	debtId := bankSlipEntities.SubscriptionDebtId(subscription.ID, testSubscriptionPeriod)
+	subscriptionRepository.On("ListDue", mock.Anything, 2).Return([]*bankSlipEntities.Subscription{subscription}, nil).Once()
+	bankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{debtId: true}, nil).Once()
+	provider.On("GenerateBillingAndSentEmail", mock.Anything).Return(&bankSlipEntities.BankSlipMap{}).Once()
+	bankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(errors.New("connection lost")).Once()
+
+	err := service.Run(time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC))
+	assert.ErrorContains(t, err, "connection lost")
+	assert.Equal(t, testSubscriptionPeriod, subscription.NextPeriod)
+	subscriptionRepository.AssertNotCalled(t, "Advance", mock.Anything, mock.Anything)
+}
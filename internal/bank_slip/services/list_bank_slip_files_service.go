@@ -0,0 +1,39 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+const (
+	DefaultBankSlipFilesPageSize = 20
+	MaxBankSlipFilesPageSize     = 100
+)
+
+type ListBankSlipFilesServiceInterface interface {
+	Execute(limit, offset int) ([]*bankSlipEntities.BankSlipFileMetadata, error)
+}
+
+type ListBankSlipFilesService struct {
+	bankSlipFileMetadataRepository bankSlipEntities.BankSlipFileMetadataRepository
+}
+
+func NewListBankSlipFilesService(
+	bankSlipFileRepo bankSlipEntities.BankSlipFileMetadataRepository,
+) *ListBankSlipFilesService {
+	return &ListBankSlipFilesService{
+		bankSlipFileMetadataRepository: bankSlipFileRepo,
+	}
+}
+
+func (s *ListBankSlipFilesService) Execute(limit, offset int) ([]*bankSlipEntities.BankSlipFileMetadata, error) {
+	if limit <= 0 {
+		limit = DefaultBankSlipFilesPageSize
+	}
+	if limit > MaxBankSlipFilesPageSize {
+		limit = MaxBankSlipFilesPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.bankSlipFileMetadataRepository.List(limit, offset)
+}
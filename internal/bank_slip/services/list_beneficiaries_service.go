@@ -0,0 +1,25 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type ListBeneficiariesServiceInterface interface {
+	Execute() ([]*bankSlipEntities.Beneficiary, error)
+}
+
+type ListBeneficiariesService struct {
+	beneficiaryRepository bankSlipEntities.BeneficiaryRepository
+}
+
+func NewListBeneficiariesService(
+	beneficiaryRepo bankSlipEntities.BeneficiaryRepository,
+) *ListBeneficiariesService {
+	return &ListBeneficiariesService{
+		beneficiaryRepository: beneficiaryRepo,
+	}
+}
+
+func (s *ListBeneficiariesService) Execute() ([]*bankSlipEntities.Beneficiary, error) {
+	return s.beneficiaryRepository.List()
+}
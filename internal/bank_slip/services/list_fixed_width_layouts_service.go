@@ -0,0 +1,25 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type ListFixedWidthLayoutsServiceInterface interface {
+	Execute() ([]*bankSlipEntities.FixedWidthLayout, error)
+}
+
+type ListFixedWidthLayoutsService struct {
+	fixedWidthLayoutRepository bankSlipEntities.FixedWidthLayoutRepository
+}
+
+func NewListFixedWidthLayoutsService(
+	fixedWidthLayoutRepo bankSlipEntities.FixedWidthLayoutRepository,
+) *ListFixedWidthLayoutsService {
+	return &ListFixedWidthLayoutsService{
+		fixedWidthLayoutRepository: fixedWidthLayoutRepo,
+	}
+}
+
+func (s *ListFixedWidthLayoutsService) Execute() ([]*bankSlipEntities.FixedWidthLayout, error) {
+	return s.fixedWidthLayoutRepository.List()
+}
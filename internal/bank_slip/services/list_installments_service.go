@@ -0,0 +1,23 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type ListInstallmentsServiceInterface interface {
+	Execute(parentDebtId string) ([]*bankSlipEntities.BankSlip, error)
+}
+
+type ListInstallmentsService struct {
+	installmentRepository bankSlipEntities.InstallmentRepository
+}
+
+func NewListInstallmentsService(installmentRepo bankSlipEntities.InstallmentRepository) *ListInstallmentsService {
+	return &ListInstallmentsService{installmentRepository: installmentRepo}
+}
+
+// Execute lists the installments of a debt, split on upload or by the API, in
+// their order.
+func (s *ListInstallmentsService) Execute(parentDebtId string) ([]*bankSlipEntities.BankSlip, error) {
+	return s.installmentRepository.ListByParentDebtId(parentDebtId)
+}
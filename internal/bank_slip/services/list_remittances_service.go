@@ -0,0 +1,35 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type ListRemittancesServiceInterface interface {
+	Execute(limit, offset int) ([]*bankSlipEntities.Remittance, error)
+}
+
+type ListRemittancesService struct {
+	remittanceRepository bankSlipEntities.RemittanceRepository
+}
+
+func NewListRemittancesService(
+	remittanceRepo bankSlipEntities.RemittanceRepository,
+) *ListRemittancesService {
+	return &ListRemittancesService{
+		remittanceRepository: remittanceRepo,
+	}
+}
+
+// Execute pages like the list of uploads.
+func (s *ListRemittancesService) Execute(limit, offset int) ([]*bankSlipEntities.Remittance, error) {
+	if limit <= 0 {
+		limit = DefaultBankSlipFilesPageSize
+	}
+	if limit > MaxBankSlipFilesPageSize {
+		limit = MaxBankSlipFilesPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.remittanceRepository.List(limit, offset)
+}
@@ -0,0 +1,25 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListRemittancesService_ShouldApplyDefaultAndMaxPageSize(t *testing.T) {
+	repository := new(bankSlipMocks.RemittanceRepositoryMock)
+	service := NewListRemittancesService(repository)
+
+	repository.On("List", DefaultBankSlipFilesPageSize, 0).Return([]*bankSlipEntities.Remittance{}, nil).Once()
+	repository.On("List", MaxBankSlipFilesPageSize, 10).Return([]*bankSlipEntities.Remittance{}, nil).Once()
+
+	_, err := service.Execute(0, -1)
+	assert.NoError(t, err)
+	_, err = service.Execute(1000, 10)
+	assert.NoError(t, err)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,23 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type ListSubscriptionsServiceInterface interface {
+	Execute(beneficiaryId string) ([]*bankSlipEntities.Subscription, error)
+}
+
+type ListSubscriptionsService struct {
+	subscriptionRepository bankSlipEntities.SubscriptionRepository
+}
+
+func NewListSubscriptionsService(subscriptionRepo bankSlipEntities.SubscriptionRepository) *ListSubscriptionsService {
+	return &ListSubscriptionsService{subscriptionRepository: subscriptionRepo}
+}
+
+// Execute lists the subscriptions of the beneficiary, or all of them when
+// beneficiaryId is empty.
+func (s *ListSubscriptionsService) Execute(beneficiaryId string) ([]*bankSlipEntities.Subscription, error) {
+	return s.subscriptionRepository.List(beneficiaryId)
+}
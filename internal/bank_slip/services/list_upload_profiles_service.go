@@ -0,0 +1,25 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+)
+
+type ListUploadProfilesServiceInterface interface {
+	Execute() ([]*bankSlipEntities.UploadProfile, error)
+}
+
+type ListUploadProfilesService struct {
+	uploadProfileRepository bankSlipEntities.UploadProfileRepository
+}
+
+func NewListUploadProfilesService(
+	uploadProfileRepo bankSlipEntities.UploadProfileRepository,
+) *ListUploadProfilesService {
+	return &ListUploadProfilesService{
+		uploadProfileRepository: uploadProfileRepo,
+	}
+}
+
+func (s *ListUploadProfilesService) Execute() ([]*bankSlipEntities.UploadProfile, error) {
+	return s.uploadProfileRepository.List()
+}
@@ -2,32 +2,48 @@ package bank_slip
 
 import (
 	"context"
+	"io"
 	"log"
 	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
 	bankSlipProviders "performatic-file-processor/internal/bank_slip/providers"
+	"performatic-file-processor/internal/calendar"
+	"performatic-file-processor/internal/handler"
 	"performatic-file-processor/internal/messaging"
+	"slices"
 	"strings"
+	"time"
 )
 
+const rowsBufferSize = 4096
+
 type ProcessBankSlipRowsServiceInterface interface {
 	Execute(context context.Context, messagesChannel chan messaging.Message)
 }
 
 type ProcessBankSlipRowsService struct {
-	bankSlipFileRepository      bankSlipEntities.BankSlipFileMetadataRepository
-	bankSlipRepository          bankSlipEntities.BankSlipRepository
-	generateBillingAndSentEmail bankSlipProviders.GenerateBillingAndSentEmailProvider
+	bankSlipFileRepository        bankSlipEntities.BankSlipFileMetadataRepository
+	bankSlipRepository            bankSlipEntities.BankSlipRepository
+	bankSlipRejectedRowRepository bankSlipEntities.BankSlipRejectedRowRepository
+	generateBillingAndSentEmail   bankSlipProviders.GenerateBillingAndSentEmailProvider
+	businessDays                  *calendar.Calendar
+	issuanceWindowDays            int
 }
 
 func NewProcessBankSlipRowsService(
 	bankSlipFileRepository bankSlipEntities.BankSlipFileMetadataRepository,
 	bankSlipRepository bankSlipEntities.BankSlipRepository,
+	bankSlipRejectedRowRepository bankSlipEntities.BankSlipRejectedRowRepository,
 	generateBillingAndSentEmail bankSlipProviders.GenerateBillingAndSentEmailProvider,
+	businessDays *calendar.Calendar,
+	issuanceWindowDays int,
 ) *ProcessBankSlipRowsService {
 	return &ProcessBankSlipRowsService{
-		bankSlipFileRepository:      bankSlipFileRepository,
-		bankSlipRepository:          bankSlipRepository,
-		generateBillingAndSentEmail: generateBillingAndSentEmail,
+		bankSlipFileRepository:        bankSlipFileRepository,
+		bankSlipRepository:            bankSlipRepository,
+		bankSlipRejectedRowRepository: bankSlipRejectedRowRepository,
+		generateBillingAndSentEmail:   generateBillingAndSentEmail,
+		businessDays:                  businessDays,
+		issuanceWindowDays:            issuanceWindowDays,
 	}
 }
 
@@ -40,51 +56,106 @@ func (s *ProcessBankSlipRowsService) Execute(context context.Context, messagesCh
 	default:
 		for message := range messagesChannel {
 
-			fileData, fileHeader, fileId, err := s.getFieldsFromMessage(message)
+			fileData, layout, fileId, lineOffset, err := s.getFieldsFromMessage(message)
 
 			if err != nil {
 				log.Printf("Error getting fields from message (file id: %s): %v\n", fileId, err)
 				continue
 			}
+			layout.BusinessDays = s.businessDays
+
+			// A file fails while its chunks are still being published, as
+			// when its stream breaks or turns out to be a duplicate. Its
+			// chunks not processed yet are dropped, the ones processed
+			// before stay billed.
+			bankSlipFile, err := s.bankSlipFileRepository.GetById(fileId)
+			if err != nil {
+				log.Printf("Error getting bank slip file (file id: %s): %v\n", fileId, err)
+				continue
+			}
+			if bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusFailed {
+				log.Printf("Dropping rows of failed file (file id: %s)\n", fileId)
+				message.Commit()
+				continue
+			}
 
 			bankSlips := map[bankSlipEntities.DebitId]*bankSlipEntities.BankSlip{}
+			sourceRows := map[bankSlipEntities.DebitId]*bankSlipEntities.BankSlipRejectedRow{}
+			rejectedRows := []*bankSlipEntities.BankSlipRejectedRow{}
 
 			totalExpected := 0
-			for row := range strings.SplitSeq(fileData, "\n") {
-				if row == "" {
-					log.Printf("Empty row for file %s\n", fileId)
+			records := newRowsReader(fileData, layout)
+			for {
+				record, err := records.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					log.Printf("Error reading rows (file id: %s): %v\n", fileId, err)
+					break
+				}
+				if record == "" {
 					continue
 				}
 
 				totalExpected++
-				bankSlip, err := bankSlipEntities.NewBankSlipFromRow(fileId, row, fileHeader)
+				line := rowLine(lineOffset, records.Line())
+				bankSlip, err := bankSlipEntities.NewBankSlipFromLayoutRow(fileId, record, layout)
 				if err != nil {
-					log.Printf("Error creating Bank Slip Data (file id: %s): %v\n", fileId, err)
+					log.Printf("Error creating Bank Slip Data at line %d (file id: %s): %v\n", line, fileId, err)
+					rejectedRows = append(rejectedRows, bankSlipEntities.NewBankSlipRejectedRow(fileId, line, record, bankSlipEntities.RejectionCodeOf(err)))
 					continue
 				}
-				bankSlips[bankSlip.DebtId] = bankSlip
+				// A row split in installments stores them in place of its debt.
+				rowBankSlips := bankSlip.SplitInstallments(bankSlip.InstallmentPlan, bankSlip.DebtDueDate, s.businessDays)
+				if slices.ContainsFunc(rowBankSlips, func(rowBankSlip *bankSlipEntities.BankSlip) bool {
+					_, exists := bankSlips[rowBankSlip.DebtId]
+					return exists
+				}) {
+					rejectedRows = append(rejectedRows, bankSlipEntities.NewBankSlipRejectedRow(fileId, line, record, bankSlipEntities.RejectionCodeDuplicateDebtId))
+					continue
+				}
+				sourceRow := bankSlipEntities.NewBankSlipRejectedRow(fileId, line, record, bankSlipEntities.RejectionCodeDuplicateDebtId)
+				for _, rowBankSlip := range rowBankSlips {
+					if err := rowBankSlip.ScheduleIssuance(time.Now(), s.issuanceWindowDays); err != nil {
+						log.Printf("Error scheduling installment %s (file id: %s): %v\n", rowBankSlip.DebtId, fileId, err)
+					}
+					bankSlips[rowBankSlip.DebtId] = rowBankSlip
+					sourceRows[rowBankSlip.DebtId] = sourceRow
+				}
 			}
 
 			if len(bankSlips) <= 0 {
 				log.Printf("No new debts to insert %s\n", fileId)
+				s.updateFileProgress(fileId, rejectedRows)
 				continue
 			}
 
 			insertedDebtIds, err := s.bankSlipRepository.InsertMany(&bankSlips)
-
-			for debitId, success := range insertedDebtIds {
-				if !success {
-					delete(bankSlips, debitId)
-				}
+			if err != nil {
+				log.Printf("Error inserting new debts (file id: %s): %v\n", fileId, err.Error())
+				continue
 			}
 
+			duplicateRows, err := s.takeNotInserted(bankSlips, insertedDebtIds, sourceRows)
 			if err != nil {
-				log.Printf("Error inserting new debts (file id: %s): %v\n", fileId, err.Error())
+				log.Printf("Error reading debts already stored (file id: %s): %v\n", fileId, err)
 				continue
 			}
+			rejectedRows = append(rejectedRows, duplicateRows...)
+
+			// Scheduled installments are billed when their issuance window opens.
+			scheduled := 0
+			for debitId, bankSlip := range bankSlips {
+				if bankSlip.Status == bankSlipEntities.BankSlipStatusScheduled {
+					delete(bankSlips, debitId)
+					scheduled++
+				}
+			}
 
 			if (len(bankSlips)) <= 0 {
-				log.Printf("No new debts inserted %s\n", fileId)
+				log.Printf("No new debts inserted to bill %s (%d scheduled)\n", fileId, scheduled)
+				s.updateFileProgress(fileId, rejectedRows)
 				message.Commit()
 				continue
 			}
@@ -98,21 +169,126 @@ func (s *ProcessBankSlipRowsService) Execute(context context.Context, messagesCh
 				continue
 			}
 
+			s.updateFileProgress(fileId, rejectedRows)
+
 			message.Commit()
-			log.Printf("From %d inserted %d new debts (file id: %s)\n", totalExpected, len(bankSlips), fileId)
+			log.Printf("From %d inserted %d new debts and %d scheduled installments (file id: %s)\n", totalExpected, len(bankSlips), scheduled, fileId)
+		}
+	}
+}
+
+// takeNotInserted removes from bankSlips the debts InsertMany didn't store.
+// Debts stored by another file or chunk are returned as duplicates, once per
+// row for the installments of a row. Debts stored by an earlier delivery of
+// this chunk, one that failed before its commit, are not duplicates: the
+// ones still PENDING are put back to be billed, the others were billed or
+// scheduled by that delivery. A row repeated with the same debt in another
+// chunk of the file can't be told from a redelivery, and isn't rejected.
+func (s *ProcessBankSlipRowsService) takeNotInserted(
+	bankSlips bankSlipEntities.BankSlipMap,
+	insertedDebtIds map[bankSlipEntities.DebitId]bankSlipEntities.Success,
+	sourceRows map[bankSlipEntities.DebitId]*bankSlipEntities.BankSlipRejectedRow,
+) ([]*bankSlipEntities.BankSlipRejectedRow, error) {
+	duplicateRows := []*bankSlipEntities.BankSlipRejectedRow{}
+	isDuplicateRow := map[*bankSlipEntities.BankSlipRejectedRow]bool{}
+	for debitId, success := range insertedDebtIds {
+		if success {
+			continue
+		}
+		stored, err := s.bankSlipRepository.GetByDebtId(debitId)
+		if err != nil {
+			return nil, err
+		}
+		if isSameRowDebt(stored, bankSlips[debitId]) {
+			if stored.Status == bankSlipEntities.BankSlipStatusPending {
+				bankSlips[debitId] = stored
+			} else {
+				delete(bankSlips, debitId)
+			}
+			continue
+		}
+
+		delete(bankSlips, debitId)
+		if !isDuplicateRow[sourceRows[debitId]] {
+			duplicateRows = append(duplicateRows, sourceRows[debitId])
+			isDuplicateRow[sourceRows[debitId]] = true
 		}
 	}
+	return duplicateRows, nil
+}
+
+// isSameRowDebt tells whether stored was stored from the same row of the same
+// file as bankSlip.
+func isSameRowDebt(stored, bankSlip *bankSlipEntities.BankSlip) bool {
+	return stored.BankSlipFileMetadataId == bankSlip.BankSlipFileMetadataId &&
+		stored.GovernmentId == bankSlip.GovernmentId &&
+		stored.DebtAmount == bankSlip.DebtAmount &&
+		stored.DebtDueDate.Equal(bankSlip.DebtDueDate)
 }
 
-func (s *ProcessBankSlipRowsService) getFieldsFromMessage(message messaging.Message) (fileData, fileHeader, fileId string, err error) {
+func (s *ProcessBankSlipRowsService) updateFileProgress(fileId string, rejectedRows []*bankSlipEntities.BankSlipRejectedRow) {
+	if len(rejectedRows) > 0 {
+		err := s.bankSlipRejectedRowRepository.InsertMany(rejectedRows)
+		if err != nil {
+			log.Printf("Error saving rejected rows (file id: %s): %v\n", fileId, err)
+		}
+
+		err = s.bankSlipFileRepository.AddRejectedRows(fileId, len(rejectedRows))
+		if err != nil {
+			log.Printf("Error registering rejected rows (file id: %s): %v\n", fileId, err)
+		}
+	}
+
+	err := s.bankSlipFileRepository.CompleteWhenAllRowsProcessed(fileId)
+	if err != nil {
+		log.Printf("Error completing bank slip file (file id: %s): %v\n", fileId, err)
+	}
+}
+
+func (s *ProcessBankSlipRowsService) getFieldsFromMessage(message messaging.Message) (fileData string, layout *bankSlipEntities.BankSlipRowLayout, fileId string, lineOffset int, err error) {
 	messageData, err := message.Data()
 	if err != nil {
-		return "", "", "", err
+		return "", nil, "", 0, err
 	}
 
-	fileHeader = messageData["header"].(string)
 	fileData = messageData["data"].(string)
 	fileId = messageData["fileId"].(string)
 
-	return fileData, fileHeader, fileId, nil
+	switch offset := messageData["lineOffset"].(type) {
+	case float64:
+		lineOffset = int(offset)
+	case int:
+		lineOffset = offset
+	}
+
+	// Messages published before upload profiles existed only carry the header.
+	if messageLayout, ok := messageData["layout"]; ok {
+		layout, err = bankSlipEntities.NewBankSlipRowLayoutFromMessage(messageLayout)
+		return fileData, layout, fileId, lineOffset, err
+	}
+
+	headerItems, err := bankSlipEntities.ParseCSVRecord(messageData["header"].(string), bankSlipEntities.DefaultDelimiter)
+	if err != nil {
+		return "", nil, fileId, lineOffset, err
+	}
+	layout, err = bankSlipEntities.DefaultUploadProfile().ResolveHeader(headerItems)
+	return fileData, layout, fileId, lineOffset, err
+}
+
+// rowLine returns the line of a row in the uploaded file, or 0 when the
+// message does not tell where its chunk starts.
+func rowLine(lineOffset int, chunkLine int) int {
+	if lineOffset <= 0 {
+		return 0
+	}
+	return lineOffset + chunkLine - 1
+}
+
+// newRowsReader reads the rows of a chunk by line when the layout is fixed
+// width, as quotes have no special meaning in those records.
+func newRowsReader(fileData string, layout *bankSlipEntities.BankSlipRowLayout) recordReader {
+	if layout.IsFixedWidth() {
+		return handler.NewLineRecordReader(strings.NewReader(fileData), rowsBufferSize)
+	}
+	return handler.NewCSVRecordReader(strings.NewReader(fileData), rowsBufferSize)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
 	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/calendar"
 	"performatic-file-processor/internal/messaging"
 	sharedMocks "performatic-file-processor/internal/mocks"
 	"sync"
@@ -19,6 +20,7 @@ type TestSuit struct {
 	suite.Suite
 	mockBankSlipFileRepository *bankSlipMocks.BankSlipFileMetadataRepositoryMock
 	mockBankSlipRepository     *bankSlipMocks.BankSlipRepositoryMock
+	mockRejectedRowRepository  *bankSlipMocks.BankSlipRejectedRowRepositoryMock
 	mockBankSlipProvider       *bankSlipMocks.GenerateBillingAndSentEmailProviderMock
 	service                    *ProcessBankSlipRowsService
 }
@@ -27,10 +29,19 @@ func (s *TestSuit) SetupTest() {
 	s.mockBankSlipFileRepository = new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
 	s.mockBankSlipRepository = new(bankSlipMocks.BankSlipRepositoryMock)
 	s.mockBankSlipProvider = new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	s.mockRejectedRowRepository = new(bankSlipMocks.BankSlipRejectedRowRepositoryMock)
+	s.mockRejectedRowRepository.On("InsertMany", mock.Anything).Return(nil).Maybe()
+	s.mockBankSlipFileRepository.On("AddRejectedRows", mock.Anything, mock.Anything).Return(nil).Maybe()
+	s.mockBankSlipFileRepository.On("CompleteWhenAllRowsProcessed", mock.Anything).Return(nil).Maybe()
+	s.mockBankSlipRepository.On("GetByDebtId", mock.Anything).Return(&bankSlipEntities.BankSlip{BankSlipFileMetadataId: "otherFileId", Status: bankSlipEntities.BankSlipStatusSuccess}, nil).Maybe()
+	s.mockBankSlipFileRepository.On("GetById", mock.Anything).Return(&bankSlipEntities.BankSlipFileMetadata{Status: bankSlipEntities.BankSlipFileStatusProcessing}, nil).Maybe()
 	s.service = NewProcessBankSlipRowsService(
 		s.mockBankSlipFileRepository,
 		s.mockBankSlipRepository,
+		s.mockRejectedRowRepository,
 		s.mockBankSlipProvider,
+		calendar.New(),
+		30,
 	)
 }
 
@@ -170,7 +181,7 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldntCallGenerateBillingAnd
 
 	messageWithHeaderAndDataWithDiferentLength := map[string]any{
 		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
-		"data":   "John Doe,555,john.doe@example.com,1000.50,2023-12-31,debt123",
+		"data":   "John Doe,52998224725,john.doe@example.com,1000.50,2023-12-31,debt123",
 		"fileId": "fileId",
 	}
 	message.On("Data").Return(messageWithHeaderAndDataWithDiferentLength, nil).Once()
@@ -206,12 +217,12 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldntCallGenerateBillingAnd
 
 		expected := &bankSlipEntities.BankSlip{
 			UserName:               "John Doe",
-			GovernmentId:           555,
+			GovernmentId:           "52998224725",
 			UserEmail:              "john.doe@example.com",
 			BankSlipFileMetadataId: "fileId",
 			ErrorMessage:           nil,
 			Status:                 bankSlipEntities.BankSlipStatusPending,
-			DebtAmount:             1000.50,
+			DebtAmount:             100050,
 			DebtDueDate:            time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
 			DebtId:                 "debt123",
 		}
@@ -226,7 +237,7 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldNotCommitMessageWhenInse
 
 	message.On("Data").Return(map[string]any{
 		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
-		"data":   "John Doe,123,john.doe@example.com,1000.50,2023-12-31,debt123",
+		"data":   "John Doe,12345678909,john.doe@example.com,1000.50,2023-12-31,debt123",
 		"fileId": "fileId",
 	}, nil).Once()
 	message.On("Commit")
@@ -255,12 +266,12 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldNotCommitMessageWhenInse
 
 		expected := &bankSlipEntities.BankSlip{
 			UserName:               "John Doe",
-			GovernmentId:           123,
+			GovernmentId:           "12345678909",
 			UserEmail:              "john.doe@example.com",
 			BankSlipFileMetadataId: "fileId",
 			ErrorMessage:           nil,
 			Status:                 bankSlipEntities.BankSlipStatusPending,
-			DebtAmount:             1000.50,
+			DebtAmount:             100050,
 			DebtDueDate:            time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
 			DebtId:                 "debt123",
 		}
@@ -270,12 +281,59 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldNotCommitMessageWhenInse
 	s.mockBankSlipProvider.AssertNotCalled(s.T(), "GenerateBillingAndSentEmail")
 }
 
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldBillDebtsStoredByAnEarlierDeliveryOfTheChunk() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	message.On("Data").Return(map[string]any{
+		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
+		"data":   "John Doe,12345678909,john.doe@example.com,1000.50,2023-12-31,debt123\nJane Doe,12345678909,jane.doe@example.com,20.00,2023-12-31,debt456",
+		"fileId": "fileId",
+	}, nil).Once()
+	message.On("Commit")
+
+	stored := &bankSlipEntities.BankSlip{
+		DebtId:                 "debt123",
+		GovernmentId:           "12345678909",
+		BankSlipFileMetadataId: "fileId",
+		Status:                 bankSlipEntities.BankSlipStatusPending,
+		DebtAmount:             100050,
+		DebtDueDate:            time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+		BeneficiaryId:          "beneficiary_id",
+		OurNumber:              7,
+	}
+	s.mockBankSlipRepository.ExpectedCalls = nil
+	s.mockBankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{
+		"debt123": false,
+		"debt456": false,
+	}, nil).Once()
+	s.mockBankSlipRepository.On("GetByDebtId", "debt123").Return(stored, nil).Once()
+	s.mockBankSlipRepository.On("GetByDebtId", "debt456").Return(&bankSlipEntities.BankSlip{
+		DebtId:                 "debt456",
+		GovernmentId:           "12345678909",
+		BankSlipFileMetadataId: "fileId",
+		Status:                 bankSlipEntities.BankSlipStatusSuccess,
+		DebtAmount:             2000,
+		DebtDueDate:            time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+	}, nil).Once()
+	s.mockBankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockBankSlipProvider.On("GenerateBillingAndSentEmail", mock.Anything).Return(&bankSlipEntities.BankSlipMap{}).Once()
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	close(messagesChannel)
+	s.service.Execute(context.Background(), messagesChannel)
+
+	s.mockBankSlipProvider.AssertCalled(s.T(), "GenerateBillingAndSentEmail", &bankSlipEntities.BankSlipMap{"debt123": stored})
+	s.mockRejectedRowRepository.AssertNotCalled(s.T(), "InsertMany", mock.Anything)
+	message.AssertCalled(s.T(), "Commit")
+}
+
 func (s *TestSuit) TestProcessBankSlipRowsService_ShouldProcessSuccessfullyBankSlipRows() {
 	message := sharedMocks.NewKafkaMessageMock()
 
 	message.On("Data").Return(map[string]any{
 		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
-		"data":   "John Doe,123,john.doe@example.com,1000.50,2023-12-31,debt123",
+		"data":   "John Doe,12345678909,john.doe@example.com,1000.50,2023-12-31,debt123",
 		"fileId": "fileId",
 	}, nil).Once()
 	message.On("Commit")
@@ -302,12 +360,12 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldProcessSuccessfullyBankS
 
 	expected := &bankSlipEntities.BankSlip{
 		UserName:               "John Doe",
-		GovernmentId:           123,
+		GovernmentId:           "12345678909",
 		UserEmail:              "john.doe@example.com",
 		BankSlipFileMetadataId: "fileId",
 		ErrorMessage:           nil,
 		Status:                 bankSlipEntities.BankSlipStatusPending,
-		DebtAmount:             1000.50,
+		DebtAmount:             100050,
 		DebtDueDate:            time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
 		DebtId:                 "debt123",
 	}
@@ -323,12 +381,35 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldProcessSuccessfullyBankS
 	message.AssertCalled(s.T(), "Commit")
 }
 
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldDropRowsOfFailedFile() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	message.On("Data").Return(map[string]any{
+		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
+		"data":   "John Doe,12345678909,john.doe@example.com,1000.50,2023-12-31,debt123",
+		"fileId": "failedFileId",
+	}, nil).Once()
+	message.On("Commit")
+	s.mockBankSlipFileRepository.ExpectedCalls = nil
+	s.mockBankSlipFileRepository.On("GetById", "failedFileId").Return(&bankSlipEntities.BankSlipFileMetadata{Status: bankSlipEntities.BankSlipFileStatusFailed}, nil).Once()
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	close(messagesChannel)
+	s.service.Execute(context.Background(), messagesChannel)
+
+	message.AssertCalled(s.T(), "Commit")
+	s.mockBankSlipRepository.AssertNotCalled(s.T(), "InsertMany", mock.Anything)
+	s.mockBankSlipProvider.AssertNotCalled(s.T(), "GenerateBillingAndSentEmail", mock.Anything)
+	s.mockBankSlipFileRepository.AssertNotCalled(s.T(), "CompleteWhenAllRowsProcessed", mock.Anything)
+}
+
 func (s *TestSuit) TestProcessBankSlipRowsService_ShouldProcessSuccessfullyWhenFirstReceivedLineIsBanlkBankSlipRows() {
 	message := sharedMocks.NewKafkaMessageMock()
 
 	message.On("Data").Return(map[string]any{
 		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
-		"data":   "\nJohn Doe,123,john.doe@example.com,1000.50,2023-12-31,debt123",
+		"data":   "\nJohn Doe,12345678909,john.doe@example.com,1000.50,2023-12-31,debt123",
 		"fileId": "fileId",
 	}, nil).Once()
 	message.On("Commit")
@@ -354,12 +435,12 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldProcessSuccessfullyWhenF
 
 	expected := &bankSlipEntities.BankSlip{
 		UserName:               "John Doe",
-		GovernmentId:           123,
+		GovernmentId:           "12345678909",
 		UserEmail:              "john.doe@example.com",
 		BankSlipFileMetadataId: "fileId",
 		ErrorMessage:           nil,
 		Status:                 bankSlipEntities.BankSlipStatusPending,
-		DebtAmount:             1000.50,
+		DebtAmount:             100050,
 		DebtDueDate:            time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
 		DebtId:                 "debt123",
 	}
@@ -380,7 +461,7 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldProcessOnlyValidMessages
 
 	message.On("Data").Return(map[string]any{
 		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
-		"data":   "Mary Doe,987,mary.doe@example.com,5021.50,2023-12-31,debt543\nJohn Doe,abc,john.doe@example.com,1000.50,2023-12-31,debt123\n",
+		"data":   "Mary Doe,11222333000181,mary.doe@example.com,5021.50,2023-12-31,debt543\nJohn Doe,abc,john.doe@example.com,1000.50,2023-12-31,debt123\n",
 		"fileId": "fileId",
 	}, nil).Once()
 	message.On("Commit")
@@ -410,12 +491,12 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldProcessOnlyValidMessages
 
 	expected := &bankSlipEntities.BankSlip{
 		UserName:               "Mary Doe",
-		GovernmentId:           987,
+		GovernmentId:           "11222333000181",
 		UserEmail:              "mary.doe@example.com",
 		BankSlipFileMetadataId: "fileId",
 		ErrorMessage:           nil,
 		Status:                 bankSlipEntities.BankSlipStatusPending,
-		DebtAmount:             5021.50,
+		DebtAmount:             502150,
 		DebtDueDate:            time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
 		DebtId:                 "debt543",
 	}
@@ -458,3 +539,367 @@ func (s *TestSuit) TestProcessBankSlipRowsService_ShouldExitWhenContextIsDone()
 	cancel()
 	wg.Wait()
 }
+
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldRegisterRejectedRowsAndCompleteFile() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	message.On("Data").Return(map[string]any{
+		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
+		"data":   "Mary Doe,11222333000181,mary.doe@example.com,5021.50,2023-12-31,debt543\nJohn Doe,abc,john.doe@example.com,1000.50,2023-12-31,debt123\nJane Doe,11144477735,jane.doe@example.com,10.00,2023-12-31,debt987",
+		"fileId": "fileId",
+	}, nil).Once()
+	message.On("Commit")
+
+	s.mockBankSlipProvider.On("GenerateBillingAndSentEmail", mock.Anything).
+		Return(&bankSlipEntities.BankSlipMap{}).Once()
+	s.mockBankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{
+		"debt543": true,
+		"debt987": false,
+	}, nil).Once()
+	s.mockBankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.service.Execute(context.Background(), messagesChannel)
+	}()
+	close(messagesChannel)
+	wg.Wait()
+
+	s.mockBankSlipFileRepository.AssertCalled(s.T(), "AddRejectedRows", "fileId", 2)
+	s.mockBankSlipFileRepository.AssertCalled(s.T(), "CompleteWhenAllRowsProcessed", "fileId")
+	message.AssertCalled(s.T(), "Commit")
+}
+
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldParseQuotedRowsWithCRLF() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	message.On("Data").Return(map[string]any{
+		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
+		"data":   "\"Santos, Elijah\",12345678909,elijah@example.com,10.50,2023-12-31,debt123\r\n\"Doe,\r\nMary\",11222333000181,mary.doe@example.com,20.00,2023-12-31,debt543\r\n",
+		"fileId": "fileId",
+	}, nil).Once()
+	message.On("Commit")
+
+	s.mockBankSlipProvider.On("GenerateBillingAndSentEmail", mock.Anything).
+		Return(&bankSlipEntities.BankSlipMap{}).Once()
+	s.mockBankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{
+		"debt123": true,
+		"debt543": true,
+	}, nil).Once()
+	s.mockBankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.service.Execute(context.Background(), messagesChannel)
+	}()
+	close(messagesChannel)
+	wg.Wait()
+
+	s.mockBankSlipRepository.AssertCalled(s.T(), "InsertMany", mock.MatchedBy(func(m *bankSlipEntities.BankSlipMap) bool {
+		return len(*m) == 2 &&
+			(*m)["debt123"].UserName == "Santos, Elijah" &&
+			(*m)["debt543"].UserName == "Doe,\nMary"
+	}))
+	s.mockBankSlipFileRepository.AssertNotCalled(s.T(), "AddRejectedRows", mock.Anything, mock.Anything)
+	message.AssertCalled(s.T(), "Commit")
+}
+
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldParseRowsWithMessageLayout() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	message.On("Data").Return(map[string]any{
+		"header": "id_divida;nome;cpf;email;valor;vencimento",
+		"data":   "debt123,John Doe,12345678909,john.doe@example.com,\"1.000,50\",31/12/2023",
+		"fileId": "fileId",
+		"layout": map[string]any{
+			"positions": map[string]any{
+				"debtId":       float64(0),
+				"userName":     float64(1),
+				"governmentId": float64(2),
+				"userEmail":    float64(3),
+				"debtAmount":   float64(4),
+				"debtDueDate":  float64(5),
+			},
+			"columns":          float64(6),
+			"dateLayout":       "02/01/2006",
+			"decimalSeparator": ",",
+		},
+	}, nil).Once()
+	message.On("Commit")
+
+	s.mockBankSlipProvider.On("GenerateBillingAndSentEmail", mock.Anything).
+		Return(&bankSlipEntities.BankSlipMap{}).
+		Once()
+	s.mockBankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{
+		"debt123": true,
+	}, nil).Once()
+	s.mockBankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	close(messagesChannel)
+	s.service.Execute(context.Background(), messagesChannel)
+
+	s.mockBankSlipRepository.AssertCalled(s.T(), "InsertMany", mock.MatchedBy(func(m *bankSlipEntities.BankSlipMap) bool {
+		actual, exists := (*m)["debt123"]
+		return exists &&
+			assert.Equal(s.T(), "John Doe", actual.UserName) &&
+			assert.Equal(s.T(), bankSlipEntities.Money(100050), actual.DebtAmount) &&
+			assert.Equal(s.T(), time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), actual.DebtDueDate)
+	}))
+	message.AssertCalled(s.T(), "Commit")
+}
+
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldSplitRowsWithLayoutDelimiter() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	layout, _ := bankSlipEntities.DefaultUploadProfile().ResolveHeader([]string{"name", "governmentId", "email", "debtAmount", "debtDueDate", "debtId"})
+	layout.Delimiter = ";"
+	layout.DecimalSeparator = ","
+	message.On("Data").Return(map[string]any{
+		"header": "name;governmentId;email;debtAmount;debtDueDate;debtId",
+		"data":   "Doe, John;12345678909;john.doe@example.com;1.000,50;2023-12-31;debt123",
+		"fileId": "fileId",
+		"layout": layout.ToMessage(),
+	}, nil).Once()
+	message.On("Commit")
+
+	s.mockBankSlipProvider.On("GenerateBillingAndSentEmail", mock.Anything).
+		Return(&bankSlipEntities.BankSlipMap{}).
+		Once()
+	s.mockBankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{
+		"debt123": true,
+	}, nil).Once()
+	s.mockBankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	close(messagesChannel)
+	s.service.Execute(context.Background(), messagesChannel)
+
+	s.mockBankSlipRepository.AssertCalled(s.T(), "InsertMany", mock.MatchedBy(func(m *bankSlipEntities.BankSlipMap) bool {
+		actual, exists := (*m)["debt123"]
+		return exists &&
+			assert.Equal(s.T(), "Doe, John", actual.UserName) &&
+			assert.Equal(s.T(), bankSlipEntities.Money(100050), actual.DebtAmount)
+	}))
+}
+
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldRollDueDatesForwardWithItsCalendar() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	layout, _ := bankSlipEntities.DefaultUploadProfile().ResolveHeader([]string{"name", "governmentId", "email", "debtAmount", "debtDueDate", "debtId"})
+	layout.DueDatePolicy = bankSlipEntities.DueDatePolicyRollForward
+	message.On("Data").Return(map[string]any{
+		"data":   "John Doe,12345678909,john.doe@example.com,1000.50,2023-12-31,debt123",
+		"fileId": "fileId",
+		"layout": layout.ToMessage(),
+	}, nil).Once()
+	message.On("Commit")
+
+	s.mockBankSlipProvider.On("GenerateBillingAndSentEmail", mock.Anything).Return(&bankSlipEntities.BankSlipMap{}).Once()
+	s.mockBankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{"debt123": true}, nil).Once()
+	s.mockBankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+
+	service := NewProcessBankSlipRowsService(
+		s.mockBankSlipFileRepository,
+		s.mockBankSlipRepository,
+		s.mockRejectedRowRepository,
+		s.mockBankSlipProvider,
+		calendar.New(calendar.RegionalHoliday{Month: time.January, Day: 2}),
+		30,
+	)
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	close(messagesChannel)
+	service.Execute(context.Background(), messagesChannel)
+
+	s.mockBankSlipRepository.AssertCalled(s.T(), "InsertMany", mock.MatchedBy(func(m *bankSlipEntities.BankSlipMap) bool {
+		actual, exists := (*m)["debt123"]
+		return exists && assert.Equal(s.T(), time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), actual.DebtDueDate)
+	}))
+}
+
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldSplitInstallmentsAndBillOnlyTheOnesInWindow() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	parentDebtId := "e1f4b1f2-7c1a-4b8e-9a3d-2f6c8d9e0a11"
+	firstDueDate := time.Now().AddDate(0, 0, 10).Format(time.DateOnly)
+	layout, _ := bankSlipEntities.DefaultUploadProfile().ResolveHeader([]string{"name", "governmentId", "email", "debtAmount", "debtDueDate", "debtId", "installments"})
+	message.On("Data").Return(map[string]any{
+		"data":   "John Doe,12345678909,john.doe@example.com,1000.00," + firstDueDate + "," + parentDebtId + ",3",
+		"fileId": "fileId",
+		"layout": layout.ToMessage(),
+	}, nil).Once()
+	message.On("Commit")
+
+	first := bankSlipEntities.InstallmentDebtId(parentDebtId, 1)
+	second := bankSlipEntities.InstallmentDebtId(parentDebtId, 2)
+	third := bankSlipEntities.InstallmentDebtId(parentDebtId, 3)
+	s.mockBankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{first: true, second: true, third: true}, nil).Once()
+	s.mockBankSlipProvider.On("GenerateBillingAndSentEmail", mock.Anything).Return(&bankSlipEntities.BankSlipMap{}).Once()
+	s.mockBankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	close(messagesChannel)
+	s.service.Execute(context.Background(), messagesChannel)
+
+	s.mockBankSlipRepository.AssertCalled(s.T(), "InsertMany", mock.MatchedBy(func(m *bankSlipEntities.BankSlipMap) bool {
+		_, parentExists := (*m)[parentDebtId]
+		return len(*m) == 3 && !parentExists &&
+			assert.Equal(s.T(), bankSlipEntities.Money(33333), (*m)[first].DebtAmount) &&
+			assert.Equal(s.T(), bankSlipEntities.Money(33334), (*m)[third].DebtAmount) &&
+			assert.Equal(s.T(), parentDebtId, (*m)[second].ParentDebtId) &&
+			assert.Equal(s.T(), bankSlipEntities.BankSlipStatusPending, (*m)[first].Status) &&
+			assert.Equal(s.T(), bankSlipEntities.BankSlipStatusScheduled, (*m)[second].Status) &&
+			assert.Equal(s.T(), bankSlipEntities.BankSlipStatusScheduled, (*m)[third].Status)
+	}))
+	s.mockBankSlipProvider.AssertCalled(s.T(), "GenerateBillingAndSentEmail", mock.MatchedBy(func(m *bankSlipEntities.BankSlipMap) bool {
+		_, exists := (*m)[first]
+		return len(*m) == 1 && exists
+	}))
+	message.AssertCalled(s.T(), "Commit")
+}
+
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldRejectRowOnceWhenItsInstallmentsExist() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	parentDebtId := "e1f4b1f2-7c1a-4b8e-9a3d-2f6c8d9e0a11"
+	layout, _ := bankSlipEntities.DefaultUploadProfile().ResolveHeader([]string{"name", "governmentId", "email", "debtAmount", "debtDueDate", "debtId", "installments"})
+	message.On("Data").Return(map[string]any{
+		"data":       "John Doe,12345678909,john.doe@example.com,1000.00,2023-12-31," + parentDebtId + ",2",
+		"fileId":     "fileId",
+		"lineOffset": 2,
+		"layout":     layout.ToMessage(),
+	}, nil).Once()
+	message.On("Commit")
+
+	s.mockBankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{
+		bankSlipEntities.InstallmentDebtId(parentDebtId, 1): false,
+		bankSlipEntities.InstallmentDebtId(parentDebtId, 2): false,
+	}, nil).Once()
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	close(messagesChannel)
+	s.service.Execute(context.Background(), messagesChannel)
+
+	s.mockRejectedRowRepository.AssertCalled(s.T(), "InsertMany", mock.MatchedBy(func(rows []*bankSlipEntities.BankSlipRejectedRow) bool {
+		return len(rows) == 1 && rows[0].LineNumber == 2 && rows[0].ErrorCode == bankSlipEntities.RejectionCodeDuplicateDebtId
+	}))
+	s.mockBankSlipProvider.AssertNotCalled(s.T(), "GenerateBillingAndSentEmail", mock.Anything)
+	message.AssertCalled(s.T(), "Commit")
+}
+
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldReadFixedWidthRowsByLine() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	message.On("Data").Return(map[string]any{
+		"header": "",
+		"data":   "1debt112345678909John \"JD  john@example.com000010005031122023\n1debt212345678909Mary      mary@example.com000000050031122023",
+		"fileId": "fileId",
+		"layout": newTestFixedWidthLayout().RowLayout().ToMessage(),
+	}, nil).Once()
+	message.On("Commit")
+
+	s.mockBankSlipProvider.On("GenerateBillingAndSentEmail", mock.Anything).
+		Return(&bankSlipEntities.BankSlipMap{}).
+		Once()
+	s.mockBankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{
+		"debt1": true,
+		"debt2": true,
+	}, nil).Once()
+	s.mockBankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	close(messagesChannel)
+	s.service.Execute(context.Background(), messagesChannel)
+
+	s.mockBankSlipRepository.AssertCalled(s.T(), "InsertMany", mock.MatchedBy(func(m *bankSlipEntities.BankSlipMap) bool {
+		first, second := (*m)["debt1"], (*m)["debt2"]
+		return len(*m) == 2 &&
+			assert.Equal(s.T(), `John "JD`, first.UserName) &&
+			assert.Equal(s.T(), bankSlipEntities.Money(100050), first.DebtAmount) &&
+			assert.Equal(s.T(), bankSlipEntities.Money(500), second.DebtAmount) &&
+			assert.Equal(s.T(), time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), second.DebtDueDate)
+	}))
+	message.AssertCalled(s.T(), "Commit")
+}
+
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldPersistRejectedRowsWithLineAndCode() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	message.On("Data").Return(map[string]any{
+		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
+		"data": "Mary Doe,11222333000181,mary.doe@example.com,5021.50,2023-12-31,debt543\n" +
+			"John Doe,abc,john.doe@example.com,1000.50,2023-12-31,debt123\n" +
+			"\n" +
+			"Jane Doe,11144477735,jane.doe@example.com,10.00,2023-12-31,debt987\n" +
+			"Jane Roe,45317828791,jane.roe@example.com,11.00,2023-12-31,debt543\n" +
+			"Jim Doe,39053344705,jim.doe@example.com,abc,2023-12-31,debt111\n" +
+			"Joe Doe,93541134780,joe.doe@example.com,12.00,2023-13-31,debt222\n" +
+			"Ann Doe,658,ann.doe@example.com",
+		"fileId":     "fileId",
+		"lineOffset": float64(10),
+	}, nil).Once()
+	message.On("Commit")
+
+	s.mockBankSlipProvider.On("GenerateBillingAndSentEmail", mock.Anything).
+		Return(&bankSlipEntities.BankSlipMap{}).Once()
+	s.mockBankSlipRepository.On("InsertMany", mock.Anything).Return(map[string]bool{
+		"debt543": true,
+		"debt987": false,
+	}, nil).Once()
+	s.mockBankSlipRepository.On("UpdateMany", mock.Anything, mock.Anything).Return(nil).Once()
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	close(messagesChannel)
+	s.service.Execute(context.Background(), messagesChannel)
+
+	s.mockRejectedRowRepository.AssertCalled(s.T(), "InsertMany", mock.MatchedBy(func(rejectedRows []*bankSlipEntities.BankSlipRejectedRow) bool {
+		return assert.ElementsMatch(s.T(), []*bankSlipEntities.BankSlipRejectedRow{
+			bankSlipEntities.NewBankSlipRejectedRow("fileId", 11, "John Doe,abc,john.doe@example.com,1000.50,2023-12-31,debt123", bankSlipEntities.RejectionCodeInvalidGovernmentId),
+			bankSlipEntities.NewBankSlipRejectedRow("fileId", 14, "Jane Roe,45317828791,jane.roe@example.com,11.00,2023-12-31,debt543", bankSlipEntities.RejectionCodeDuplicateDebtId),
+			bankSlipEntities.NewBankSlipRejectedRow("fileId", 15, "Jim Doe,39053344705,jim.doe@example.com,abc,2023-12-31,debt111", bankSlipEntities.RejectionCodeInvalidAmount),
+			bankSlipEntities.NewBankSlipRejectedRow("fileId", 16, "Joe Doe,93541134780,joe.doe@example.com,12.00,2023-13-31,debt222", bankSlipEntities.RejectionCodeInvalidDueDate),
+			bankSlipEntities.NewBankSlipRejectedRow("fileId", 17, "Ann Doe,658,ann.doe@example.com", bankSlipEntities.RejectionCodeColumnCountMismatch),
+			bankSlipEntities.NewBankSlipRejectedRow("fileId", 13, "Jane Doe,11144477735,jane.doe@example.com,10.00,2023-12-31,debt987", bankSlipEntities.RejectionCodeDuplicateDebtId),
+		}, rejectedRows)
+	}))
+	s.mockBankSlipFileRepository.AssertCalled(s.T(), "AddRejectedRows", "fileId", 6)
+	message.AssertCalled(s.T(), "Commit")
+}
+
+func (s *TestSuit) TestProcessBankSlipRowsService_ShouldRejectRowsWithUnknownLineWhenOffsetIsMissing() {
+	message := sharedMocks.NewKafkaMessageMock()
+
+	message.On("Data").Return(map[string]any{
+		"header": "name,governmentId,email,debtAmount,debtDueDate,debtId",
+		"data":   "John Doe,abc,john.doe@example.com,1000.50,2023-12-31,debt123",
+		"fileId": "fileId",
+	}, nil).Once()
+	message.On("Commit")
+
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+	close(messagesChannel)
+	s.service.Execute(context.Background(), messagesChannel)
+
+	s.mockRejectedRowRepository.AssertCalled(s.T(), "InsertMany", []*bankSlipEntities.BankSlipRejectedRow{
+		bankSlipEntities.NewBankSlipRejectedRow("fileId", 0, "John Doe,abc,john.doe@example.com,1000.50,2023-12-31,debt123", bankSlipEntities.RejectionCodeInvalidGovernmentId),
+	})
+	s.mockBankSlipRepository.AssertNotCalled(s.T(), "InsertMany", mock.Anything)
+}
@@ -0,0 +1,160 @@
+package bank_slip
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/cnab"
+	"performatic-file-processor/internal/messaging"
+)
+
+type ProcessReturnRecordsServiceInterface interface {
+	Execute(context context.Context, messagesChannel chan messaging.Message)
+}
+
+// ProcessReturnRecordsService moves the bank slips to what the bank reported
+// in a chunk of a return file, and keeps the records matching no bank slip.
+type ProcessReturnRecordsService struct {
+	returnFileRepository            bankSlipEntities.BankSlipReturnFileRepository
+	bankSlipRepository              bankSlipEntities.BankSlipRepository
+	unmatchedReturnRecordRepository bankSlipEntities.UnmatchedReturnRecordRepository
+}
+
+func NewProcessReturnRecordsService(
+	returnFileRepository bankSlipEntities.BankSlipReturnFileRepository,
+	bankSlipRepository bankSlipEntities.BankSlipRepository,
+	unmatchedReturnRecordRepository bankSlipEntities.UnmatchedReturnRecordRepository,
+) *ProcessReturnRecordsService {
+	return &ProcessReturnRecordsService{
+		returnFileRepository:            returnFileRepository,
+		bankSlipRepository:              bankSlipRepository,
+		unmatchedReturnRecordRepository: unmatchedReturnRecordRepository,
+	}
+}
+
+type returnRecordsMessage struct {
+	data          string
+	returnFileId  string
+	beneficiaryId string
+	bankCode      string
+	format        cnab.Format
+	lineOffset    int
+	records       int
+}
+
+func (s *ProcessReturnRecordsService) Execute(context context.Context, messagesChannel chan messaging.Message) {
+	select {
+	case <-context.Done():
+		log.Printf("Exiting ProcessReturnRecordsService...\n")
+		return
+
+	default:
+		for message := range messagesChannel {
+			fields, err := s.getFieldsFromMessage(message)
+			if err != nil {
+				log.Printf("Error getting fields from message (return file id: %s): %v\n", fields.returnFileId, err)
+				continue
+			}
+
+			layout, err := cnab.ReturnLayoutFor(fields.bankCode, fields.format)
+			if err != nil {
+				log.Printf("Error getting return layout (return file id: %s): %v\n", fields.returnFileId, err)
+				continue
+			}
+
+			reconciled, unmatchedRecords, err := s.reconcile(fields, layout)
+			if err != nil {
+				log.Printf("Error reconciling bank slips (return file id: %s): %v\n", fields.returnFileId, err)
+				continue
+			}
+
+			if len(unmatchedRecords) > 0 {
+				err = s.unmatchedReturnRecordRepository.InsertMany(unmatchedRecords)
+				if err != nil {
+					log.Printf("Error saving unmatched return records (return file id: %s): %v\n", fields.returnFileId, err)
+					continue
+				}
+			}
+
+			err = s.returnFileRepository.AddProcessedRecords(fields.returnFileId, fields.records, reconciled, len(unmatchedRecords))
+			if err != nil {
+				log.Printf("Error registering processed return records (return file id: %s): %v\n", fields.returnFileId, err)
+			}
+			err = s.returnFileRepository.CompleteWhenAllRecordsProcessed(fields.returnFileId)
+			if err != nil {
+				log.Printf("Error completing return file (return file id: %s): %v\n", fields.returnFileId, err)
+			}
+
+			message.Commit()
+			log.Printf("From %d records reconciled %d bank slips, %d unmatched (return file id: %s)\n", fields.records, reconciled, len(unmatchedRecords), fields.returnFileId)
+		}
+	}
+}
+
+// reconcile applies the records of the chunk, a record is reconciled again
+// with no effect when the message is delivered twice.
+func (s *ProcessReturnRecordsService) reconcile(fields returnRecordsMessage, layout cnab.ReturnLayout) (int, []*bankSlipEntities.UnmatchedReturnRecord, error) {
+	lines := strings.Split(fields.data, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	reconciled := 0
+	unmatchedRecords := []*bankSlipEntities.UnmatchedReturnRecord{}
+	for _, record := range layout.Read(lines, fields.lineOffset) {
+		returnRecord := bankSlipEntities.NewBankSlipReturnRecord(fields.returnFileId, record)
+		if record.Err != nil {
+			log.Printf("Error reading return record at line %d (return file id: %s): %v\n", record.Line, fields.returnFileId, record.Err)
+			unmatchedRecords = append(unmatchedRecords, bankSlipEntities.NewUnmatchedReturnRecord(returnRecord, bankSlipEntities.UnmatchedReasonInvalidRecord))
+			continue
+		}
+		if !returnRecord.Reconciles() {
+			continue
+		}
+
+		err := s.bankSlipRepository.Reconcile(fields.beneficiaryId, returnRecord)
+		switch {
+		case errors.Is(err, bankSlipEntities.ErrBankSlipNotFound):
+			unmatchedRecords = append(unmatchedRecords, bankSlipEntities.NewUnmatchedReturnRecord(returnRecord, bankSlipEntities.UnmatchedReasonBankSlipNotFound))
+		case errors.Is(err, bankSlipEntities.ErrUnexpectedBankSlipStatus):
+			unmatchedRecords = append(unmatchedRecords, bankSlipEntities.NewUnmatchedReturnRecord(returnRecord, bankSlipEntities.UnmatchedReasonUnexpectedStatus))
+		case err != nil:
+			return 0, nil, err
+		default:
+			reconciled++
+		}
+	}
+	return reconciled, unmatchedRecords, nil
+}
+
+func (s *ProcessReturnRecordsService) getFieldsFromMessage(message messaging.Message) (returnRecordsMessage, error) {
+	messageData, err := message.Data()
+	if err != nil {
+		return returnRecordsMessage{}, err
+	}
+
+	fields := returnRecordsMessage{}
+	fields.data, _ = messageData["data"].(string)
+	fields.returnFileId, _ = messageData["returnFileId"].(string)
+	fields.beneficiaryId, _ = messageData["beneficiaryId"].(string)
+	fields.bankCode, _ = messageData["bankCode"].(string)
+	format, _ := messageData["format"].(string)
+	fields.format = cnab.Format(format)
+	fields.lineOffset = messageInt(messageData["lineOffset"])
+	fields.records = messageInt(messageData["records"])
+	return fields, nil
+}
+
+// messageInt reads a number of a message, decoded as float64 from JSON.
+func messageInt(value any) int {
+	switch number := value.(type) {
+	case float64:
+		return int(number)
+	case int:
+		return number
+	}
+	return 0
+}
@@ -0,0 +1,146 @@
+package bank_slip
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/messaging"
+	sharedMocks "performatic-file-processor/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitProcessReturnRecordsService struct {
+	suite.Suite
+	mockReturnFileRepository      *bankSlipMocks.BankSlipReturnFileRepositoryMock
+	mockBankSlipRepository        *bankSlipMocks.BankSlipRepositoryMock
+	mockUnmatchedRecordRepository *bankSlipMocks.UnmatchedReturnRecordRepositoryMock
+	service                       *ProcessReturnRecordsService
+}
+
+func (s *TestSuitProcessReturnRecordsService) SetupTest() {
+	s.mockReturnFileRepository = new(bankSlipMocks.BankSlipReturnFileRepositoryMock)
+	s.mockBankSlipRepository = new(bankSlipMocks.BankSlipRepositoryMock)
+	s.mockUnmatchedRecordRepository = new(bankSlipMocks.UnmatchedReturnRecordRepositoryMock)
+	s.mockReturnFileRepository.On("AddProcessedRecords", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	s.mockReturnFileRepository.On("CompleteWhenAllRecordsProcessed", mock.Anything).Return(nil).Maybe()
+	s.mockUnmatchedRecordRepository.On("InsertMany", mock.Anything).Return(nil).Maybe()
+	s.service = NewProcessReturnRecordsService(
+		s.mockReturnFileRepository,
+		s.mockBankSlipRepository,
+		s.mockUnmatchedRecordRepository,
+	)
+}
+
+func TestProcessReturnRecordsService(t *testing.T) {
+	suite.Run(t, new(TestSuitProcessReturnRecordsService))
+}
+
+func (s *TestSuitProcessReturnRecordsService) process(message messaging.Message) {
+	messagesChannel := make(chan messaging.Message, 1)
+	messagesChannel <- message
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.service.Execute(context.Background(), messagesChannel)
+	}()
+	close(messagesChannel)
+	wg.Wait()
+}
+
+func newReturnRecordsMessage(data string, records int) map[string]any {
+	return map[string]any{
+		"data":          data,
+		"returnFileId":  "return_file_id",
+		"beneficiaryId": testPdfBeneficiaryId,
+		"bankCode":      "237",
+		"format":        "CNAB400",
+		"lineOffset":    float64(2),
+		"records":       float64(records),
+	}
+}
+
+func (s *TestSuitProcessReturnRecordsService) TestProcessReturnRecordsService_ShouldReconcileRecordsAndKeepUnmatched() {
+	data := fmt.Sprintf("%s\n%s\n\n%s\n%s",
+		bradesco400ReturnTitle("06", "42", "100050"),
+		bradesco400ReturnTitle("02", "43", ""),
+		bradesco400ReturnTitle("06", "44", "100050"),
+		bradesco400ReturnTitle("09", "45", "ABC"),
+	)
+	message := sharedMocks.NewKafkaMessageMock()
+	message.On("Data").Return(newReturnRecordsMessage(data, 4), nil).Once()
+	message.On("Commit").Once()
+
+	s.mockBankSlipRepository.On("Reconcile", testPdfBeneficiaryId, mock.MatchedBy(func(record *bankSlipEntities.BankSlipReturnRecord) bool {
+		return record.OurNumber == 42
+	})).Return(nil).Once()
+	s.mockBankSlipRepository.On("Reconcile", testPdfBeneficiaryId, mock.MatchedBy(func(record *bankSlipEntities.BankSlipReturnRecord) bool {
+		return record.OurNumber == 44
+	})).Return(bankSlipEntities.ErrBankSlipNotFound).Once()
+
+	s.process(message)
+
+	s.mockBankSlipRepository.AssertNumberOfCalls(s.T(), "Reconcile", 2)
+	s.mockBankSlipRepository.AssertCalled(s.T(), "Reconcile", testPdfBeneficiaryId, mock.MatchedBy(func(record *bankSlipEntities.BankSlipReturnRecord) bool {
+		return record.OurNumber == 42 &&
+			record.LineNumber == 2 &&
+			record.Status == bankSlipEntities.BankSlipStatusPaid &&
+			record.PaidAmount == bankSlipEntities.Money(100050)
+	}))
+	s.mockUnmatchedRecordRepository.AssertCalled(s.T(), "InsertMany", mock.MatchedBy(func(unmatchedRecords []*bankSlipEntities.UnmatchedReturnRecord) bool {
+		return len(unmatchedRecords) == 2 &&
+			unmatchedRecords[0].LineNumber == 5 &&
+			unmatchedRecords[0].Reason == bankSlipEntities.UnmatchedReasonBankSlipNotFound &&
+			unmatchedRecords[1].LineNumber == 6 &&
+			unmatchedRecords[1].Reason == bankSlipEntities.UnmatchedReasonInvalidRecord
+	}))
+	s.mockReturnFileRepository.AssertCalled(s.T(), "AddProcessedRecords", "return_file_id", 4, 1, 2)
+	s.mockReturnFileRepository.AssertCalled(s.T(), "CompleteWhenAllRecordsProcessed", "return_file_id")
+	message.AssertCalled(s.T(), "Commit")
+}
+
+func (s *TestSuitProcessReturnRecordsService) TestProcessReturnRecordsService_ShouldReportBankSlipInUnexpectedStatus() {
+	message := sharedMocks.NewKafkaMessageMock()
+	message.On("Data").Return(newReturnRecordsMessage(bradesco400ReturnTitle("03", "42", ""), 1), nil).Once()
+	message.On("Commit").Once()
+
+	s.mockBankSlipRepository.On("Reconcile", testPdfBeneficiaryId, mock.Anything).Return(fmt.Errorf("%w: PAID", bankSlipEntities.ErrUnexpectedBankSlipStatus)).Once()
+
+	s.process(message)
+
+	s.mockUnmatchedRecordRepository.AssertCalled(s.T(), "InsertMany", mock.MatchedBy(func(unmatchedRecords []*bankSlipEntities.UnmatchedReturnRecord) bool {
+		return len(unmatchedRecords) == 1 && unmatchedRecords[0].Reason == bankSlipEntities.UnmatchedReasonUnexpectedStatus
+	}))
+	s.mockReturnFileRepository.AssertCalled(s.T(), "AddProcessedRecords", "return_file_id", 1, 0, 1)
+	message.AssertCalled(s.T(), "Commit")
+}
+
+func (s *TestSuitProcessReturnRecordsService) TestProcessReturnRecordsService_ShouldNotCommitWhenReconcileFails() {
+	message := sharedMocks.NewKafkaMessageMock()
+	message.On("Data").Return(newReturnRecordsMessage(bradesco400ReturnTitle("06", "42", "100050"), 1), nil).Once()
+
+	s.mockBankSlipRepository.On("Reconcile", testPdfBeneficiaryId, mock.Anything).Return(assert.AnError).Once()
+
+	s.process(message)
+
+	message.AssertNotCalled(s.T(), "Commit")
+	s.mockReturnFileRepository.AssertNotCalled(s.T(), "AddProcessedRecords", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TestSuitProcessReturnRecordsService) TestProcessReturnRecordsService_ShouldDoNothingWhenFailToConvertMessageData() {
+	message := sharedMocks.NewKafkaMessageMock()
+	message.On("Data").Return(nil, assert.AnError).Once()
+
+	s.process(message)
+
+	message.AssertNotCalled(s.T(), "Commit")
+	s.mockBankSlipRepository.AssertNotCalled(s.T(), "Reconcile", mock.Anything, mock.Anything)
+}
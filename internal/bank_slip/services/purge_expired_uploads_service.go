@@ -0,0 +1,47 @@
+package bank_slip
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"performatic-file-processor/internal/handler"
+)
+
+type PurgeExpiredUploadsServiceInterface interface {
+	Execute(ctx context.Context)
+}
+
+// PurgeExpiredUploadsService removes the uploaded files past their retention
+// period. Removing a file twice is harmless, so every worker may run it.
+type PurgeExpiredUploadsService struct {
+	fileHandler handler.FileHandler
+	interval    time.Duration
+}
+
+func NewPurgeExpiredUploadsService(fileHandler handler.FileHandler, interval time.Duration) *PurgeExpiredUploadsService {
+	return &PurgeExpiredUploadsService{
+		fileHandler: fileHandler,
+		interval:    interval,
+	}
+}
+
+func (s *PurgeExpiredUploadsService) Execute(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.fileHandler.PurgeExpired(time.Now())
+		if err != nil {
+			log.Printf("Error purging expired uploads: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d expired uploads", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
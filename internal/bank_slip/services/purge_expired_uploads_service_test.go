@@ -0,0 +1,43 @@
+package bank_slip
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sharedMocks "performatic-file-processor/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPurgeExpiredUploadsService_ShouldPurgeUntilCanceled(t *testing.T) {
+	fileHandler := new(sharedMocks.FileHandlerMock)
+	service := NewPurgeExpiredUploadsService(fileHandler, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	fileHandler.On("PurgeExpired", mock.Anything).Run(func(mock.Arguments) {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+	}).Return(1, nil)
+
+	service.Execute(ctx)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestPurgeExpiredUploadsService_ShouldKeepRunningWhenPurgeFails(t *testing.T) {
+	fileHandler := new(sharedMocks.FileHandlerMock)
+	service := NewPurgeExpiredUploadsService(fileHandler, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fileHandler.On("PurgeExpired", mock.Anything).Return(0, assert.AnError).Once()
+	fileHandler.On("PurgeExpired", mock.Anything).Run(func(mock.Arguments) { cancel() }).Return(0, nil).Once()
+
+	service.Execute(ctx)
+
+	fileHandler.AssertNumberOfCalls(t, "PurgeExpired", 2)
+}
@@ -0,0 +1,178 @@
+package bank_slip
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/cnab"
+	"performatic-file-processor/internal/handler"
+	"performatic-file-processor/internal/jobs"
+	"performatic-file-processor/internal/messaging"
+)
+
+const ReturnRecordsTopic = "return-records-to-process"
+
+type ReceiveReturnFileServiceInterface interface {
+	Execute(file multipart.File, fileHeader *multipart.FileHeader, beneficiaryId string) (*bankSlipEntities.BankSlipReturnFile, error)
+}
+
+// ReceiveReturnFileService stores a CNAB "retorno" file of a beneficiary and
+// publishes its records in chunks for the workers to reconcile, as uploads
+// are published for the bank slips to be issued.
+type ReceiveReturnFileService struct {
+	returnFileRepository  bankSlipEntities.BankSlipReturnFileRepository
+	beneficiaryRepository bankSlipEntities.BeneficiaryRepository
+	fileHandler           handler.FileHandler
+	backgroundJobs        jobs.Runner
+	bufferSize            int
+	chunkPublisher        *chunkPublisher
+}
+
+func NewReceiveReturnFileService(
+	returnFileRepo bankSlipEntities.BankSlipReturnFileRepository,
+	beneficiaryRepo bankSlipEntities.BeneficiaryRepository,
+	multipartFileHandler handler.FileHandler,
+	messageProducer messaging.MessageProducer,
+	backgroundJobs jobs.Runner,
+	bufferSize int,
+	workers int,
+) *ReceiveReturnFileService {
+	return &ReceiveReturnFileService{
+		returnFileRepository:  returnFileRepo,
+		beneficiaryRepository: beneficiaryRepo,
+		fileHandler:           multipartFileHandler,
+		backgroundJobs:        backgroundJobs,
+		bufferSize:            bufferSize,
+		chunkPublisher: &chunkPublisher{
+			messageProducer: messageProducer,
+			topic:           ReturnRecordsTopic,
+			workers:         workers,
+			bufferSize:      bufferSize,
+		},
+	}
+}
+
+func (s *ReceiveReturnFileService) Execute(file multipart.File, fileHeader *multipart.FileHeader, beneficiaryId string) (*bankSlipEntities.BankSlipReturnFile, error) {
+	beneficiary, err := getBeneficiary(s.beneficiaryRepository, beneficiaryId)
+	if err != nil {
+		log.Printf("Error getting beneficiary %s: %v", beneficiaryId, err)
+		return nil, err
+	}
+
+	returnFile := bankSlipEntities.NewBankSlipReturnFile(fileHeader.Filename, beneficiary.ID)
+	err = s.returnFileRepository.Insert(returnFile)
+	if err != nil {
+		log.Println("Error inserting return file", err)
+		return nil, err
+	}
+	log.Printf("Receiving return file (id: %s)...", returnFile.ID)
+
+	savedFile, err := s.fileHandler.SaveFile(handler.NewMultipartFile(file, fileHeader))
+	if err != nil {
+		s.markAsFailed(returnFile)
+		return nil, err
+	}
+
+	records := cnab.NewReturnReader(savedFile.Open(), s.bufferSize)
+	header, err := s.readHeader(records, beneficiary)
+	if err != nil {
+		log.Printf("Error reading return file header (id: %s): %v", returnFile.ID, err)
+		savedFile.Release()
+		s.markAsFailed(returnFile)
+		return nil, err
+	}
+
+	returnFile.HeaderRead(header)
+	err = s.returnFileRepository.UpdateHeader(returnFile)
+	if err != nil {
+		log.Printf("Error saving return file header (id: %s): %v", returnFile.ID, err)
+		savedFile.Release()
+		s.markAsFailed(returnFile)
+		return nil, err
+	}
+
+	err = s.backgroundJobs.Go(func(ctx context.Context) {
+		defer savedFile.Release()
+		s.publish(ctx, returnFile, records)
+	}, func() {
+		s.markAsFailed(returnFile)
+	})
+	if err != nil {
+		savedFile.Release()
+		s.markAsFailed(returnFile)
+		return nil, err
+	}
+
+	return returnFile, nil
+}
+
+// readHeader checks the file is a return of the bank of the beneficiary, in a
+// layout the workers can read.
+func (s *ReceiveReturnFileService) readHeader(records *cnab.ReturnReader, beneficiary *bankSlipEntities.Beneficiary) (cnab.ReturnHeader, error) {
+	line, err := records.Next()
+	if err == io.EOF {
+		return cnab.ReturnHeader{}, fmt.Errorf("%w: empty file", bankSlipEntities.ErrInvalidReturnFile)
+	}
+	if err != nil {
+		return cnab.ReturnHeader{}, err
+	}
+
+	header, err := cnab.ReadReturnHeader(line)
+	if err != nil {
+		return cnab.ReturnHeader{}, fmt.Errorf("%w: %w", bankSlipEntities.ErrInvalidReturnFile, err)
+	}
+	if header.BankCode != beneficiary.BankCode {
+		return cnab.ReturnHeader{}, fmt.Errorf("%w: file of bank %s for a beneficiary of bank %s", bankSlipEntities.ErrInvalidReturnFile, header.BankCode, beneficiary.BankCode)
+	}
+	if _, err := cnab.ReturnLayoutFor(header.BankCode, header.Format); err != nil {
+		return cnab.ReturnHeader{}, fmt.Errorf("%w: %w", bankSlipEntities.ErrInvalidReturnFile, err)
+	}
+	return header, nil
+}
+
+func (s *ReceiveReturnFileService) publish(ctx context.Context, returnFile *bankSlipEntities.BankSlipReturnFile, records *cnab.ReturnReader) {
+	start := time.Now()
+
+	totalRecords, err := s.chunkPublisher.publish(ctx, returnFile.ID, records, func(chunk recordsChunk) map[string]any {
+		return map[string]any{
+			"data":          string(chunk.data),
+			"returnFileId":  returnFile.ID,
+			"beneficiaryId": returnFile.BeneficiaryId,
+			"bankCode":      returnFile.BankCode,
+			"format":        string(returnFile.Format),
+			"lineOffset":    chunk.line,
+			"records":       chunk.records,
+		}
+	})
+	if err != nil {
+		log.Printf("Error publishing return file content (id: %s): %v", returnFile.ID, err)
+		s.markAsFailed(returnFile)
+		return
+	}
+
+	returnFile.Processing(totalRecords)
+	err = s.returnFileRepository.UpdateStatus(returnFile)
+	if err != nil {
+		log.Printf("Error updating return file status (id: %s): %v", returnFile.ID, err)
+		return
+	}
+	err = s.returnFileRepository.CompleteWhenAllRecordsProcessed(returnFile.ID)
+	if err != nil {
+		log.Printf("Error completing return file (id: %s): %v", returnFile.ID, err)
+	}
+
+	log.Printf("Time taken: %s (return file id: %s)\n", time.Since(start), returnFile.ID)
+}
+
+func (s *ReceiveReturnFileService) markAsFailed(returnFile *bankSlipEntities.BankSlipReturnFile) {
+	returnFile.Failed()
+	err := s.returnFileRepository.UpdateStatus(returnFile)
+	if err != nil {
+		log.Printf("Error updating return file status (id: %s): %v", returnFile.ID, err)
+	}
+}
@@ -0,0 +1,155 @@
+package bank_slip
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/cnab"
+	"performatic-file-processor/internal/jobs"
+	sharedMocks "performatic-file-processor/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// bradesco400Line writes values at their 1-based positions of a CNAB 400
+// line.
+func bradesco400Line(values map[int]string) string {
+	line := []byte(strings.Repeat(" ", 400))
+	for position, value := range values {
+		copy(line[position-1:], value)
+	}
+	return string(line)
+}
+
+func bradesco400ReturnHeader(bankCode string) string {
+	return bradesco400Line(map[int]string{1: "02RETORNO", 77: bankCode})
+}
+
+func bradesco400ReturnTitle(code string, ourNumber string, paid string) string {
+	return bradesco400Line(map[int]string{1: "1", 38: "DEBT1", 71: ourNumber, 109: code, 111: "100325", 254: paid})
+}
+
+type TestSuitReceiveReturnFileService struct {
+	suite.Suite
+	mockReturnFileRepo       *bankSlipMocks.BankSlipReturnFileRepositoryMock
+	mockMultipartFileHandler *sharedMocks.FileHandlerMock
+	mockMessageProducer      *sharedMocks.MessageProducerMock
+	backgroundJobs           *jobs.BackgroundJobs
+	service                  *ReceiveReturnFileService
+}
+
+func (testSuit *TestSuitReceiveReturnFileService) SetupTest() {
+	testSuit.mockReturnFileRepo = new(bankSlipMocks.BankSlipReturnFileRepositoryMock)
+	testSuit.mockMultipartFileHandler = new(sharedMocks.FileHandlerMock)
+	testSuit.mockMessageProducer = new(sharedMocks.MessageProducerMock)
+	testSuit.backgroundJobs = jobs.NewBackgroundJobs()
+	testSuit.mockReturnFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipReturnFile).ID = "return_file_id"
+	}).Return(nil).Maybe()
+	testSuit.mockReturnFileRepo.On("UpdateHeader", mock.Anything).Return(nil).Maybe()
+	testSuit.mockReturnFileRepo.On("UpdateStatus", mock.Anything).Return(nil).Maybe()
+	testSuit.mockReturnFileRepo.On("CompleteWhenAllRecordsProcessed", mock.Anything).Return(nil).Maybe()
+
+	testSuit.service = NewReceiveReturnFileService(
+		testSuit.mockReturnFileRepo,
+		newTestBeneficiaryRepository(),
+		testSuit.mockMultipartFileHandler,
+		testSuit.mockMessageProducer,
+		testSuit.backgroundJobs,
+		4096,
+		2,
+	)
+}
+
+func TestReceiveReturnFileService(t *testing.T) {
+	suite.Run(t, new(TestSuitReceiveReturnFileService))
+}
+
+func (suit *TestSuitReceiveReturnFileService) execute(fileContent []byte) (*bankSlipEntities.BankSlipReturnFile, *sharedMocks.SavedFileMock, error) {
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("retorno.ret", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+
+	returnFile, err := suit.service.Execute(file, fileHeaders, testPdfBeneficiaryId)
+	suit.backgroundJobs.Shutdown(context.Background())
+	return returnFile, mockSavedFile, err
+}
+
+func (suit *TestSuitReceiveReturnFileService) TestReceiveReturnFileService_ShouldPublishRecordsAfterHeader() {
+	titles := bradesco400ReturnTitle("06", "42", "100050") + "\r\n" + bradesco400ReturnTitle("02", "43", "")
+	trailer := bradesco400Line(map[int]string{1: "9"})
+	fileContent := []byte(bradesco400ReturnHeader("237") + "\r\n" + titles + "\r\n" + trailer + "\r\n")
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	returnFile, mockSavedFile, err := suit.execute(fileContent)
+	assert.NoError(suit.T(), err)
+
+	assert.Equal(suit.T(), "return_file_id", returnFile.ID)
+	assert.Equal(suit.T(), cnab.CNAB400, returnFile.Format)
+	assert.Equal(suit.T(), "237", returnFile.BankCode)
+	assert.Equal(suit.T(), bankSlipEntities.BankSlipFileStatusProcessing, returnFile.Status)
+	assert.Equal(suit.T(), 3, returnFile.TotalRecords)
+	suit.mockReturnFileRepo.AssertCalled(suit.T(), "UpdateHeader", returnFile)
+	suit.mockReturnFileRepo.AssertCalled(suit.T(), "CompleteWhenAllRecordsProcessed", "return_file_id")
+	mockSavedFile.AssertCalled(suit.T(), "Release")
+	suit.mockMessageProducer.AssertNumberOfCalls(suit.T(), "Publish", 1)
+	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "return-records-to-process", mock.MatchedBy(func(message map[string]any) bool {
+		return reflect.DeepEqual(message, map[string]any{
+			"data":          strings.ReplaceAll(titles, "\r", "") + "\n" + trailer,
+			"returnFileId":  "return_file_id",
+			"beneficiaryId": testPdfBeneficiaryId,
+			"bankCode":      "237",
+			"format":        "CNAB400",
+			"lineOffset":    2,
+			"records":       3,
+		})
+	}))
+}
+
+func (suit *TestSuitReceiveReturnFileService) TestReceiveReturnFileService_ShouldRejectReturnOfAnotherBank() {
+	fileContent := []byte(bradesco400ReturnHeader("341") + "\n" + bradesco400ReturnTitle("06", "42", "100050") + "\n")
+
+	_, mockSavedFile, err := suit.execute(fileContent)
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrInvalidReturnFile)
+
+	mockSavedFile.AssertCalled(suit.T(), "Release")
+	suit.mockReturnFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(returnFile *bankSlipEntities.BankSlipReturnFile) bool {
+		return returnFile.Status == bankSlipEntities.BankSlipFileStatusFailed
+	}))
+	suit.mockMessageProducer.AssertNotCalled(suit.T(), "Publish")
+}
+
+func (suit *TestSuitReceiveReturnFileService) TestReceiveReturnFileService_ShouldRejectFileThatIsNotAReturn() {
+	_, _, err := suit.execute([]byte(testHeader + "\nrow1\n"))
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrInvalidReturnFile)
+	suit.mockMessageProducer.AssertNotCalled(suit.T(), "Publish")
+}
+
+func (suit *TestSuitReceiveReturnFileService) TestReceiveReturnFileService_ShouldRejectEmptyFile() {
+	_, _, err := suit.execute([]byte{})
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrInvalidReturnFile)
+}
+
+func (suit *TestSuitReceiveReturnFileService) TestReceiveReturnFileService_ShouldRequireBeneficiary() {
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("retorno.ret", []byte(bradesco400ReturnHeader("237")))
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = suit.service.Execute(file, fileHeaders, "")
+	assert.ErrorIs(suit.T(), err, ErrInvalidUploadOptions)
+	suit.mockReturnFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+}
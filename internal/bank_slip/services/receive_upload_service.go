@@ -3,190 +3,268 @@ package bank_slip
 import (
 	"context"
 	"errors"
-	"io"
+	"fmt"
 	"log"
 	"mime/multipart"
-	"strings"
-	"sync"
-	"time"
 
 	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
 	"performatic-file-processor/internal/handler"
+	"performatic-file-processor/internal/jobs"
 	"performatic-file-processor/internal/messaging"
+
+	"github.com/google/uuid"
 )
 
-type ReceiveUploadServiceInterface interface {
-	Execute(file multipart.File, fileHeader *multipart.FileHeader) error
-}
+var ErrHeaderNotFound = errors.New("header not found")
 
-type Row struct {
-	data   []byte
-	header string
+type ReceiveUploadServiceInterface interface {
+	Execute(file multipart.File, fileHeader *multipart.FileHeader, options bankSlipEntities.UploadOptions) (*bankSlipEntities.BankSlipFileMetadata, error)
 }
 
 type ReceiveUploadService struct {
 	bankSlipRepository             bankSlipEntities.BankSlipRepository
 	bankSlipFileMetadataRepository bankSlipEntities.BankSlipFileMetadataRepository
+	uploadProfileRepository        bankSlipEntities.UploadProfileRepository
+	fixedWidthLayoutRepository     bankSlipEntities.FixedWidthLayoutRepository
+	beneficiaryRepository          bankSlipEntities.BeneficiaryRepository
 	fileHandler                    handler.FileHandler
-	messageProducer                messaging.MessageProducer
-	workers                        int
+	backgroundJobs                 jobs.Runner
+	duplicatePolicy                bankSlipEntities.DuplicateUploadPolicy
 	bufferSize                     int
+	rowsPublisher                  *rowsPublisher
 }
 
 func NewReceiveUploadService(
 	bankSlipRepo bankSlipEntities.BankSlipRepository,
 	bankSlipFileRepo bankSlipEntities.BankSlipFileMetadataRepository,
+	uploadProfileRepo bankSlipEntities.UploadProfileRepository,
+	fixedWidthLayoutRepo bankSlipEntities.FixedWidthLayoutRepository,
+	beneficiaryRepo bankSlipEntities.BeneficiaryRepository,
 	multipartFileHandler handler.FileHandler,
 	messageProducer messaging.MessageProducer,
+	backgroundJobs jobs.Runner,
+	duplicatePolicy bankSlipEntities.DuplicateUploadPolicy,
 	bufferSize int,
 	workers int,
 ) *ReceiveUploadService {
 	return &ReceiveUploadService{
 		bankSlipRepository:             bankSlipRepo,
 		bankSlipFileMetadataRepository: bankSlipFileRepo,
+		uploadProfileRepository:        uploadProfileRepo,
+		fixedWidthLayoutRepository:     fixedWidthLayoutRepo,
+		beneficiaryRepository:          beneficiaryRepo,
 		fileHandler:                    multipartFileHandler,
-		messageProducer:                messageProducer,
-		workers:                        workers,
+		backgroundJobs:                 backgroundJobs,
+		duplicatePolicy:                duplicatePolicy,
 		bufferSize:                     bufferSize,
+		rowsPublisher: &rowsPublisher{
+			bankSlipFileMetadataRepository: bankSlipFileRepo,
+			messageProducer:                messageProducer,
+			workers:                        workers,
+			bufferSize:                     bufferSize,
+		},
 	}
 }
 
-func (s *ReceiveUploadService) Execute(file multipart.File, fileHeader *multipart.FileHeader) error {
-	start := time.Now()
-
-	bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata(fileHeader.Filename)
+func (s *ReceiveUploadService) Execute(file multipart.File, fileHeader *multipart.FileHeader, options bankSlipEntities.UploadOptions) (*bankSlipEntities.BankSlipFileMetadata, error) {
+	dialect, err := newUploadDialect(options)
+	if err != nil {
+		return nil, err
+	}
 
-	err := s.bankSlipFileMetadataRepository.Insert(bankSlipFile)
+	duplicatePolicy, err := getDuplicatePolicy(s.duplicatePolicy, options)
 	if err != nil {
-		log.Println("Error inserting bank slip file metadata", err)
-		return err
+		return nil, err
 	}
-	log.Printf("Receiving file (id: %s)...", bankSlipFile.ID)
 
-	savedFile, err := s.fileHandler.SaveFile(handler.NewMultipartFile(file, fileHeader))
+	format, err := getUploadFormat(s.uploadProfileRepository, s.fixedWidthLayoutRepository, options, fileHeader.Filename)
 	if err != nil {
-		return err
+		log.Printf("Error getting upload format of %s: %v", fileHeader.Filename, err)
+		return nil, err
 	}
-	defer savedFile.Delete()
 
-	buffer := make([]byte, s.bufferSize)
-	fileChannel := make(chan Row, s.workers)
+	beneficiary, err := getBeneficiary(s.beneficiaryRepository, options.BeneficiaryId)
+	if err != nil {
+		log.Printf("Error getting beneficiary %s: %v", options.BeneficiaryId, err)
+		return nil, err
+	}
+	chargePolicy, err := getChargePolicy(beneficiary, options)
+	if err != nil {
+		return nil, err
+	}
 
-	var wg sync.WaitGroup
-	wg.Add(s.workers)
+	contentHash, err := handler.ContentHash(file)
+	if err != nil {
+		log.Println("Error hashing uploaded file", err)
+		return nil, err
+	}
 
-	for i := range s.workers {
-		go s.processFile(i, fileChannel, bankSlipFile.ID, &wg)
+	if options.IdempotencyKey != "" {
+		existingFile, err := s.getIdempotentUpload(options.IdempotencyKey, contentHash)
+		if existingFile != nil || err != nil {
+			return existingFile, err
+		}
 	}
 
-	locallyFile := savedFile.Open()
+	if duplicatePolicy != bankSlipEntities.DuplicateUploadPolicyForce {
+		existingFile, err := s.bankSlipFileMetadataRepository.GetByContentHash(contentHash)
+		if err == nil && duplicatePolicy == bankSlipEntities.DuplicateUploadPolicyReject {
+			return nil, &bankSlipEntities.DuplicateUploadError{ExistingFileId: existingFile.ID}
+		}
+		if err == nil {
+			log.Printf("File already uploaded, returning existing file (id: %s)", existingFile.ID)
+			return existingFile, nil
+		}
+		if !errors.Is(err, bankSlipEntities.ErrBankSlipFileNotFound) {
+			log.Println("Error looking for an upload with the same content", err)
+			return nil, err
+		}
+	}
 
-	header, remainder := s.readFileHeader(locallyFile, buffer)
+	bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata(fileHeader.Filename)
+	bankSlipFile.ContentHash = contentHash
+	bankSlipFile.IdempotencyKey = options.IdempotencyKey
+	bankSlipFile.BeneficiaryId = beneficiary.ID
 
-	if header == "" {
-		close(fileChannel)
-		elapsed := time.Since(start)
-		log.Printf("Time taken: %s\n", elapsed)
-		return errors.New("header not found")
+	err = s.bankSlipFileMetadataRepository.Insert(bankSlipFile)
+	if errors.Is(err, bankSlipEntities.ErrIdempotencyKeyAlreadyUsed) {
+		// A concurrent retry with the same key got there first.
+		return s.getIdempotentUpload(options.IdempotencyKey, contentHash)
 	}
+	if err != nil {
+		log.Println("Error inserting bank slip file metadata", err)
+		return nil, err
+	}
+	log.Printf("Receiving file (id: %s)...", bankSlipFile.ID)
 
-	s.readFileContentAndSendToProcess(
-		locallyFile,
-		buffer,
-		remainder,
-		fileChannel,
-		header,
-	)
+	savedFile, err := s.fileHandler.SaveFile(handler.NewMultipartFile(file, fileHeader))
+	if err != nil {
+		s.rowsPublisher.markAsFailed(bankSlipFile)
+		return nil, err
+	}
 
-	close(fileChannel)
+	bankSlipFile.Stored(savedFile.Filepath())
+	err = s.bankSlipFileMetadataRepository.UpdateStoragePath(bankSlipFile)
+	if err != nil {
+		log.Printf("Error saving file storage path (id: %s): %v", bankSlipFile.ID, err)
+		savedFile.Delete()
+		s.rowsPublisher.markAsFailed(bankSlipFile)
+		return nil, err
+	}
 
-	wg.Wait()
+	content, sample, err := dialect.open(savedFile.Open())
+	if err != nil {
+		log.Printf("Error sniffing file dialect (id: %s): %v", bankSlipFile.ID, err)
+		savedFile.Release()
+		s.rowsPublisher.markAsFailed(bankSlipFile)
+		return nil, err
+	}
 
-	elapsed := time.Since(start)
-	log.Printf("Time taken: %s\n", elapsed)
-	return nil
-}
+	records, header, layout, err := format.open(content, sample, dialect, s.bufferSize)
+	if err != nil {
+		log.Printf("Error reading file header with %s (id: %s): %v", format, bankSlipFile.ID, err)
+		savedFile.Release()
+		s.rowsPublisher.markAsFailed(bankSlipFile)
+		return nil, err
+	}
+	log.Printf("Reading file as %s with %s (id: %s)", dialect.encoding, format, bankSlipFile.ID)
+	layout.ChargePolicy = chargePolicy
 
-func (*ReceiveUploadService) readFileContentAndSendToProcess(locallyFile io.Reader, buffer []byte, headerRemaining string, fileChannel chan Row, header string) {
-	remainder := headerRemaining
-	for {
-		bytesRead, err := locallyFile.Read(buffer)
-		if bytesRead == 0 {
-			if remainder != "" {
-				fileChannel <- Row{data: []byte(remainder), header: header}
-			}
-			break
-		}
-		if err != nil && err != io.EOF {
-			log.Printf("Error Reading the File: %v", err)
-			break
-		}
+	bankSlipFile.HeaderRead(header, string(layout.Comma()))
+	err = s.bankSlipFileMetadataRepository.UpdateHeader(bankSlipFile)
+	if err != nil {
+		log.Printf("Error saving file header (id: %s): %v", bankSlipFile.ID, err)
+		savedFile.Release()
+		s.rowsPublisher.markAsFailed(bankSlipFile)
+		return nil, err
+	}
 
-		str := string(buffer[:bytesRead])
+	err = s.backgroundJobs.Go(func(ctx context.Context) {
+		defer savedFile.Release()
+		s.rowsPublisher.publish(ctx, bankSlipFile, records, header, layout)
+	}, func() {
+		s.rowsPublisher.markAsFailed(bankSlipFile)
+	})
+	if err != nil {
+		savedFile.Release()
+		s.rowsPublisher.markAsFailed(bankSlipFile)
+		return nil, err
+	}
 
-		lastItemIndex := strings.LastIndex(str, "\n")
-		lastRemainingIndex := strings.LastIndex(remainder, "\n")
-		if lastItemIndex == -1 && lastRemainingIndex == -1 {
-			remainder = remainder + str
-			continue
-		}
+	return bankSlipFile, nil
+}
 
-		fullRowsValid := remainder + str[:lastItemIndex]
-		if lastItemIndex == 0 {
-			fullRowsValid := remainder + str
-			fileChannel <- Row{data: []byte(fullRowsValid), header: header}
-			break
-		}
-		fileChannel <- Row{data: []byte(fullRowsValid), header: header}
-		remainder = str[lastItemIndex+1:]
+// getDuplicatePolicy returns the policy of the upload options, or
+// defaultPolicy when they have none.
+func getDuplicatePolicy(defaultPolicy bankSlipEntities.DuplicateUploadPolicy, options bankSlipEntities.UploadOptions) (bankSlipEntities.DuplicateUploadPolicy, error) {
+	if len(options.IdempotencyKey) > bankSlipEntities.MaxIdempotencyKeyLength {
+		return "", fmt.Errorf("%w: idempotency key longer than %d characters", ErrInvalidUploadOptions, bankSlipEntities.MaxIdempotencyKeyLength)
+	}
+	if options.DuplicatePolicy == "" {
+		return defaultPolicy, nil
+	}
+	duplicatePolicy, err := bankSlipEntities.ParseDuplicateUploadPolicy(options.DuplicatePolicy)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidUploadOptions, err.Error())
 	}
+	return duplicatePolicy, nil
 }
 
-func (*ReceiveUploadService) readFileHeader(locallyFile io.Reader, buffer []byte) (string, string) {
-	var header string
-	var remainder string
-	for {
-		bytesRead, err := locallyFile.Read(buffer)
-		if bytesRead == 0 {
-			break
-		}
-		if err != nil {
-			log.Printf("Error Reading the File: %v", err)
-			break
-		}
-		str := string(buffer[:bytesRead])
-
-		finalHeaderIdx := strings.Index(str, "\n")
-		if finalHeaderIdx == -1 {
-			header += str
-			continue
-		}
-		header += str[:finalHeaderIdx]
-		if finalHeaderIdx != -1 {
-			remainder = str[finalHeaderIdx+1:]
-			break
-		}
+// getIdempotentUpload returns the file created by a previous request with the
+// same key, or nil when the key was never used.
+func (s *ReceiveUploadService) getIdempotentUpload(idempotencyKey string, contentHash string) (*bankSlipEntities.BankSlipFileMetadata, error) {
+	existingFile, err := s.bankSlipFileMetadataRepository.GetByIdempotencyKey(idempotencyKey)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipFileNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error getting upload by idempotency key %s: %v", idempotencyKey, err)
+		return nil, err
+	}
+	if existingFile.ContentHash != contentHash {
+		return nil, bankSlipEntities.ErrIdempotencyKeyMismatch
 	}
-	return header, remainder
+	log.Printf("Upload retried with idempotency key %s, returning existing file (id: %s)", idempotencyKey, existingFile.ID)
+	return existingFile, nil
 }
 
-func (f *ReceiveUploadService) processFile(worker int, fileChannel chan Row, fileId string, wg *sync.WaitGroup) {
-	for {
-		row, ok := <-fileChannel
-		if len(row.data) == 0 && !ok {
-			log.Printf("Worker %d finished processing\n", worker)
-			break
-		}
+func getUploadProfile(uploadProfileRepository bankSlipEntities.UploadProfileRepository, name string) (*bankSlipEntities.UploadProfile, error) {
+	if name == "" {
+		name = bankSlipEntities.DefaultUploadProfileName
+	}
+	uploadProfile, err := uploadProfileRepository.GetByName(name)
+	if errors.Is(err, bankSlipEntities.ErrUploadProfileNotFound) && name == bankSlipEntities.DefaultUploadProfileName {
+		return bankSlipEntities.DefaultUploadProfile(), nil
+	}
+	return uploadProfile, err
+}
 
-		message := map[string]any{"data": string(row.data), "header": row.header, "fileId": fileId}
+// getChargePolicy is the charge policy of the bank slips of an upload, the one
+// of the beneficiary with what the upload options override.
+func getChargePolicy(beneficiary *bankSlipEntities.Beneficiary, options bankSlipEntities.UploadOptions) (bankSlipEntities.ChargePolicy, error) {
+	uploadPolicy, err := options.ChargePolicy()
+	if err != nil {
+		return bankSlipEntities.ChargePolicy{}, fmt.Errorf("%w: %s", ErrInvalidUploadOptions, err.Error())
+	}
+	return beneficiary.ChargePolicy.Override(uploadPolicy), nil
+}
 
-		log.Printf("Posting message to kafka for file %s (%d bytes)", fileId, len(row.data))
-		err := f.messageProducer.Publish(context.TODO(), "rows-to-process", message)
-		if err != nil {
-			log.Print("Error posting message", err)
-			continue
-		}
+// getBeneficiary returns the account the bank slips of an upload are issued to,
+// every upload must choose one.
+func getBeneficiary(beneficiaryRepository bankSlipEntities.BeneficiaryRepository, beneficiaryId string) (*bankSlipEntities.Beneficiary, error) {
+	if beneficiaryId == "" {
+		return nil, fmt.Errorf("%w: beneficiary is required", ErrInvalidUploadOptions)
 	}
+	if _, err := uuid.Parse(beneficiaryId); err != nil {
+		return nil, fmt.Errorf("%w: invalid beneficiary id %q", ErrInvalidUploadOptions, beneficiaryId)
+	}
+	return beneficiaryRepository.GetById(beneficiaryId)
+}
 
-	wg.Done()
+func resolveHeader(uploadProfile *bankSlipEntities.UploadProfile, header string, delimiter rune) (*bankSlipEntities.BankSlipRowLayout, error) {
+	headerItems, err := bankSlipEntities.ParseCSVRecord(header, delimiter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", bankSlipEntities.ErrInvalidHeader, err.Error())
+	}
+	return uploadProfile.ResolveHeader(headerItems)
 }
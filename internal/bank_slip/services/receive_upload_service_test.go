@@ -2,13 +2,16 @@ package bank_slip
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"reflect"
+	"strings"
 	"testing"
 
 	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
 	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
 	"performatic-file-processor/internal/handler"
+	"performatic-file-processor/internal/jobs"
 	sharedMocks "performatic-file-processor/internal/mocks"
 
 	"github.com/stretchr/testify/assert"
@@ -16,26 +19,52 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
+const (
+	testHeader        = "name,governmentId,email,debtAmount,debtDueDate,debtId"
+	testBeneficiaryId = "a1b2c3d4-0000-4000-8000-000000000002"
+)
+
 type TestSuitReceiveUploadService struct {
 	suite.Suite
 	mockBankSlipRepo         *bankSlipMocks.BankSlipRepositoryMock
 	mockBankSlipFileRepo     *bankSlipMocks.BankSlipFileMetadataRepositoryMock
+	mockUploadProfileRepo    *bankSlipMocks.UploadProfileRepositoryMock
+	mockFixedWidthLayoutRepo *bankSlipMocks.FixedWidthLayoutRepositoryMock
+	mockBeneficiaryRepo      *bankSlipMocks.BeneficiaryRepositoryMock
 	mockMultipartFileHandler *sharedMocks.FileHandlerMock
 	mockMessageProducer      *sharedMocks.MessageProducerMock
+	backgroundJobs           *jobs.BackgroundJobs
 	service                  *ReceiveUploadService
 }
 
 func (testSuit *TestSuitReceiveUploadService) SetupTest() {
 	testSuit.mockBankSlipRepo = new(bankSlipMocks.BankSlipRepositoryMock)
 	testSuit.mockBankSlipFileRepo = new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	testSuit.mockUploadProfileRepo = new(bankSlipMocks.UploadProfileRepositoryMock)
+	testSuit.mockFixedWidthLayoutRepo = new(bankSlipMocks.FixedWidthLayoutRepositoryMock)
+	testSuit.mockBeneficiaryRepo = new(bankSlipMocks.BeneficiaryRepositoryMock)
 	testSuit.mockMultipartFileHandler = new(sharedMocks.FileHandlerMock)
 	testSuit.mockMessageProducer = new(sharedMocks.MessageProducerMock)
+	testSuit.backgroundJobs = jobs.NewBackgroundJobs()
+	testSuit.mockBankSlipFileRepo.On("UpdateHeader", mock.Anything).Return(nil).Maybe()
+	testSuit.mockBankSlipFileRepo.On("UpdateStoragePath", mock.Anything).Return(nil).Maybe()
+	testSuit.mockBankSlipFileRepo.On("GetByContentHash", mock.Anything).Return(nil, bankSlipEntities.ErrBankSlipFileNotFound).Maybe()
+	testSuit.mockBankSlipFileRepo.On("UpdateStatus", mock.Anything).Return(nil).Maybe()
+	testSuit.mockBankSlipFileRepo.On("CompleteWhenAllRowsProcessed", mock.Anything).Return(nil).Maybe()
+	testSuit.mockUploadProfileRepo.On("GetByName", bankSlipEntities.DefaultUploadProfileName).Return(bankSlipEntities.DefaultUploadProfile(), nil).Maybe()
+	testSuit.mockFixedWidthLayoutRepo.On("GetByExtension", mock.Anything).Return(nil, bankSlipEntities.ErrFixedWidthLayoutNotFound).Maybe()
+	testSuit.mockBeneficiaryRepo.On("GetById", testBeneficiaryId).Return(&bankSlipEntities.Beneficiary{ID: testBeneficiaryId}, nil).Maybe()
 
 	testSuit.service = NewReceiveUploadService(
 		testSuit.mockBankSlipRepo,
 		testSuit.mockBankSlipFileRepo,
+		testSuit.mockUploadProfileRepo,
+		testSuit.mockFixedWidthLayoutRepo,
+		testSuit.mockBeneficiaryRepo,
 		testSuit.mockMultipartFileHandler,
 		testSuit.mockMessageProducer,
+		testSuit.backgroundJobs,
+		bankSlipEntities.DuplicateUploadPolicyReject,
 		len("headerData"),
 		2,
 	)
@@ -45,8 +74,18 @@ func TestReceiveUploadService(t *testing.T) {
 	suite.Run(t, new(TestSuitReceiveUploadService))
 }
 
+func testLayoutMessage() map[string]any {
+	layout, _ := bankSlipEntities.DefaultUploadProfile().ResolveHeader(strings.Split(testHeader, ","))
+	layout.Delimiter = ","
+	return layout.ToMessage()
+}
+
+func readerAfterHeader(reader io.Reader) io.Reader {
+	return io.MultiReader(strings.NewReader(testHeader+"\n"), reader)
+}
+
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldReturnErrorIfInsertMetadataFails() {
-	fileContent := bytes.NewBufferString("headerData\nrow1\nrow2\n").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1\nrow2\n").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -55,7 +94,8 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldReturnE
 
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Return(assert.AnError).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.Error(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
@@ -64,7 +104,7 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldReturnE
 }
 
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldReturnErrorIfSaveFileFails() {
-	fileContent := bytes.NewBufferString("headerData\nrow1\nrow2\n").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1\nrow2\n").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -74,17 +114,21 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldReturnE
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Return(nil).Once()
 	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(nil, assert.AnError).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.Error(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
 		return assert.Equal(suit.T(), fileName, bankSlipFile.FileName)
 	}))
 	suit.mockMultipartFileHandler.AssertCalled(suit.T(), "SaveFile", handler.NewMultipartFile(file, fileHeaders))
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusFailed
+	}))
 }
 
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreWhenReadHeaderThrowsNotEOF() {
-	fileContent := bytes.NewBufferString("headerData\nrow1,row1\nrow2,row2").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1\nrow2,row2").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -92,6 +136,7 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 	}
 
 	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
 	mockedReader := sharedMocks.NewReaderMock()
 
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
@@ -101,9 +146,10 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockedReader.On("Read", mock.Anything).Return(0, assert.AnError).Once()
 	mockSavedFile.On("Open").Return(mockedReader).Once()
-	mockSavedFile.On("Delete").Return(nil).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.Error(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
@@ -114,7 +160,7 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 }
 
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreWhenFailsReadingHeader() {
-	fileContent := bytes.NewBufferString("headerData\nrow1,row1\nrow2,row2").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1\nrow2,row2").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -122,6 +168,7 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 	}
 
 	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
 	mockedReader := sharedMocks.NewReaderMock()
 
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
@@ -131,9 +178,10 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockedReader.On("Read", mock.Anything).Return(5, assert.AnError).Once()
 	mockSavedFile.On("Open").Return(mockedReader).Once()
-	mockSavedFile.On("Delete").Return(nil).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.Error(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
@@ -144,7 +192,7 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 }
 
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreWhenReadContentThrowsNotEOF() {
-	fileContent := bytes.NewBufferString("headerData\nrow1,row1\nrow2,row2").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1\nrow2,row2").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -152,6 +200,7 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 	}
 
 	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
 	mockedReader := sharedMocks.NewReaderMock()
 
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
@@ -160,16 +209,13 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
 	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	mockedReader.On("Read", mock.Anything).
-		Return(9, nil).Once()
-	mockedReader.On("Read", mock.Anything).
-		Return(0, io.EOF).Once()
 	mockedReader.On("Read", mock.Anything).
 		Return(0, assert.AnError).Once()
-	mockSavedFile.On("Open").Return(mockedReader).Once()
-	mockSavedFile.On("Delete").Return(nil).Once()
+	mockSavedFile.On("Open").Return(readerAfterHeader(mockedReader)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.NoError(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
@@ -180,7 +226,7 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 }
 
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreWhenFailsReadingContent() {
-	fileContent := bytes.NewBufferString("headerData\nrow1,row1\nrow2,row2").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1\nrow2,row2").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -188,6 +234,7 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 	}
 
 	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
 	mockedReader := sharedMocks.NewReaderMock()
 
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
@@ -196,16 +243,13 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
 	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	mockedReader.On("Read", mock.Anything).
-		Return(9, nil).Once()
-	mockedReader.On("Read", mock.Anything).
-		Return(0, io.EOF).Once()
 	mockedReader.On("Read", mock.Anything).
 		Return(5, assert.AnError).Once()
-	mockSavedFile.On("Open").Return(mockedReader).Once()
-	mockSavedFile.On("Delete").Return(nil).Once()
+	mockSavedFile.On("Open").Return(readerAfterHeader(mockedReader)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.NoError(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
@@ -216,7 +260,7 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 }
 
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFileToManyWorkers() {
-	fileContent := bytes.NewBufferString("headerData\nrow1,row1\nrow2,row2").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1\nrow2,row2").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -224,15 +268,17 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFi
 	}
 
 	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
 		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
 	}).Return(nil).Once()
 	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
 	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
-	mockSavedFile.On("Delete").Return(nil).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.NoError(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
@@ -242,15 +288,17 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFi
 	suit.mockMessageProducer.AssertNumberOfCalls(suit.T(), "Publish", 1)
 	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.MatchedBy(func(message map[string]any) bool {
 		return reflect.DeepEqual(message, map[string]any{
-			"data":   "row1,row1\nrow2,row2",
-			"fileId": "any_id",
-			"header": "headerData",
+			"data":       "row1,row1\nrow2,row2",
+			"fileId":     "any_id",
+			"lineOffset": 2,
+			"header":     testHeader,
+			"layout":     testLayoutMessage(),
 		})
 	}))
 }
 
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreWhenPublishFails() {
-	fileContent := bytes.NewBufferString("headerData\nrow1,row1\nrow2,row2").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1\nrow2,row2").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -258,15 +306,17 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 	}
 
 	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
 		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
 	}).Return(nil).Once()
 	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
 	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(assert.AnError)
 	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
-	mockSavedFile.On("Delete").Return(nil).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.NoError(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
@@ -276,15 +326,17 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIgnoreW
 	suit.mockMessageProducer.AssertNumberOfCalls(suit.T(), "Publish", 1)
 	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.MatchedBy(func(message map[string]any) bool {
 		return reflect.DeepEqual(message, map[string]any{
-			"data":   "row1,row1\nrow2,row2",
-			"fileId": "any_id",
-			"header": "headerData",
+			"data":       "row1,row1\nrow2,row2",
+			"fileId":     "any_id",
+			"lineOffset": 2,
+			"header":     testHeader,
+			"layout":     testLayoutMessage(),
 		})
 	}))
 }
 
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFileToManyWorkersWhenHeadersIsLong() {
-	fileContent := bytes.NewBufferString("headerDataheaderData\nrow1,row1\nrow2,row2").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1\nrow2,row2").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -292,15 +344,17 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFi
 	}
 
 	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
 		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
 	}).Return(nil).Once()
 	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
 	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
-	mockSavedFile.On("Delete").Return(nil).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.NoError(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
@@ -310,15 +364,17 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFi
 	suit.mockMessageProducer.AssertNumberOfCalls(suit.T(), "Publish", 1)
 	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.MatchedBy(func(message map[string]any) bool {
 		return reflect.DeepEqual(message, map[string]any{
-			"data":   "row1,row1\nrow2,row2",
-			"fileId": "any_id",
-			"header": "headerDataheaderData",
+			"data":       "row1,row1\nrow2,row2",
+			"fileId":     "any_id",
+			"lineOffset": 2,
+			"header":     testHeader,
+			"layout":     testLayoutMessage(),
 		})
 	}))
 }
 
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFileToManyWorkersWhenHeadersIsLongAndRowContentIsLogger() {
-	fileContent := bytes.NewBufferString("headerData,headerData\nrow1,row1,row1,row1,row1,row1\nrow2,row2").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1,row1,row1,row1,row1\nrow2,row2").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -326,15 +382,17 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFi
 	}
 
 	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
 		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
 	}).Return(nil).Once()
 	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
 	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
-	mockSavedFile.On("Delete").Return(nil).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.NoError(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
@@ -344,22 +402,26 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFi
 	suit.mockMessageProducer.AssertNumberOfCalls(suit.T(), "Publish", 2)
 	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.MatchedBy(func(message map[string]any) bool {
 		return reflect.DeepEqual(message, map[string]any{
-			"data":   "row1,row1,row1,row1,row1,row1",
-			"fileId": "any_id",
-			"header": "headerData,headerData",
+			"data":       "row1,row1,row1,row1,row1,row1",
+			"fileId":     "any_id",
+			"lineOffset": 2,
+			"header":     testHeader,
+			"layout":     testLayoutMessage(),
 		})
 	}))
 	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.MatchedBy(func(message map[string]any) bool {
 		return reflect.DeepEqual(message, map[string]any{
-			"data":   "row2,row2",
-			"fileId": "any_id",
-			"header": "headerData,headerData",
+			"data":       "row2,row2",
+			"fileId":     "any_id",
+			"lineOffset": 3,
+			"header":     testHeader,
+			"layout":     testLayoutMessage(),
 		})
 	}))
 }
 
 func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFileToManyWorkersWhenHeadersIsLongAndRowContentIsLoggerAnotherCase() {
-	fileContent := bytes.NewBufferString("headerData,headerData\nrow1,row1,row1,row1,row1,row1\nrow2,row2,row2,row2\nrow3\nrow4,row4,row4").Bytes()
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1,row1,row1,row1,row1\nrow2,row2,row2,row2\nrow3\nrow4,row4,row4").Bytes()
 	fileName := "testfile.txt"
 	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
 	if err != nil {
@@ -367,41 +429,747 @@ func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSplitFi
 	}
 
 	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
 	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
 		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
 	}).Return(nil).Once()
 	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
 	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
-	mockSavedFile.On("Delete").Return(nil).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
 
-	err = suit.service.Execute(file, fileHeaders)
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
 	assert.NoError(suit.T(), err)
 
 	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "Insert", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
 		return assert.Equal(suit.T(), fileName, bankSlipFile.FileName)
 	}))
 	suit.mockMultipartFileHandler.AssertCalled(suit.T(), "SaveFile", handler.NewMultipartFile(file, fileHeaders))
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusProcessing && bankSlipFile.TotalRows == 4
+	}))
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "CompleteWhenAllRowsProcessed", "any_id")
 	suit.mockMessageProducer.AssertNumberOfCalls(suit.T(), "Publish", 3)
 	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.MatchedBy(func(message map[string]any) bool {
 		return reflect.DeepEqual(message, map[string]any{
-			"data":   "row1,row1,row1,row1,row1,row1",
-			"fileId": "any_id",
-			"header": "headerData,headerData",
+			"data":       "row1,row1,row1,row1,row1,row1",
+			"fileId":     "any_id",
+			"lineOffset": 2,
+			"header":     testHeader,
+			"layout":     testLayoutMessage(),
 		})
 	}))
 	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.MatchedBy(func(message map[string]any) bool {
 		return reflect.DeepEqual(message, map[string]any{
-			"data":   "row2,row2,row2,row2\nrow3",
-			"fileId": "any_id",
-			"header": "headerData,headerData",
+			"data":       "row2,row2,row2,row2",
+			"fileId":     "any_id",
+			"lineOffset": 3,
+			"header":     testHeader,
+			"layout":     testLayoutMessage(),
 		})
 	}))
 	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.MatchedBy(func(message map[string]any) bool {
 		return reflect.DeepEqual(message, map[string]any{
-			"data":   "row4,row4,row4",
-			"fileId": "any_id",
-			"header": "headerData,headerData",
+			"data":       "row3\nrow4,row4,row4",
+			"fileId":     "any_id",
+			"lineOffset": 4,
+			"header":     testHeader,
+			"layout":     testLayoutMessage(),
+		})
+	}))
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldReturnFileBeforeSendingRowsToProcess() {
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1\nrow2,row2").Bytes()
+	fileName := "testfile.txt"
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	published := make(chan struct{})
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-published }).
+		Return(nil)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), "any_id", bankSlipFile.ID)
+	mockSavedFile.AssertNotCalled(suit.T(), "Release")
+
+	close(published)
+	suit.backgroundJobs.Shutdown(context.Background())
+
+	suit.mockMessageProducer.AssertNumberOfCalls(suit.T(), "Publish", 1)
+	mockSavedFile.AssertCalled(suit.T(), "Release")
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRefuseFilesWhenShuttingDown() {
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1\nrow2,row2").Bytes()
+	fileName := "testfile.txt"
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	suit.backgroundJobs.Shutdown(context.Background())
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, jobs.ErrShuttingDown)
+	mockSavedFile.AssertCalled(suit.T(), "Release")
+	suit.mockMessageProducer.AssertNotCalled(suit.T(), "Publish")
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldNeverSplitQuotedRecordsBetweenChunks() {
+	fileContent := bytes.NewBufferString("\ufeff" + testHeader + "\r\n\"Santos, Elijah\",\"Rua A,\r\n123\"\r\n\"Doe, \"\"John\"\"\",B\r\n").Bytes()
+	fileName := "testfile.txt"
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock(fileName, fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
+	assert.NoError(suit.T(), err)
+
+	suit.mockMessageProducer.AssertNumberOfCalls(suit.T(), "Publish", 2)
+	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", map[string]any{
+		"data":       "\"Santos, Elijah\",\"Rua A,\r\n123\"",
+		"fileId":     "any_id",
+		"lineOffset": 2,
+		"header":     testHeader,
+		"layout":     testLayoutMessage(),
+	})
+	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", map[string]any{
+		"data":       "\"Doe, \"\"John\"\"\",B",
+		"fileId":     "any_id",
+		"lineOffset": 4,
+		"header":     testHeader,
+		"layout":     testLayoutMessage(),
+	})
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusProcessing && bankSlipFile.TotalRows == 2
+	}))
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRejectUnknownProfileBeforeStoringFile() {
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1").Bytes()
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	suit.mockUploadProfileRepo.On("GetByName", "unknown").Return(nil, bankSlipEntities.ErrUploadProfileNotFound).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{Profile: "unknown", BeneficiaryId: testBeneficiaryId})
+
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrUploadProfileNotFound)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+	suit.mockMultipartFileHandler.AssertNotCalled(suit.T(), "SaveFile", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRequireBeneficiary() {
+	for _, beneficiaryId := range []string{"", "not-an-uuid"} {
+		file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", []byte(testHeader+"\nrow1,row1"))
+		if err != nil {
+			panic(err)
+		}
+
+		bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: beneficiaryId})
+
+		assert.Nil(suit.T(), bankSlipFile)
+		assert.ErrorIs(suit.T(), err, ErrInvalidUploadOptions)
+	}
+	suit.mockBeneficiaryRepo.AssertNotCalled(suit.T(), "GetById", mock.Anything)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRejectUnknownBeneficiaryBeforeStoringFile() {
+	const unknownBeneficiaryId = "a1b2c3d4-0000-4000-8000-0000000000ff"
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", []byte(testHeader+"\nrow1,row1"))
+	if err != nil {
+		panic(err)
+	}
+
+	suit.mockBeneficiaryRepo.On("GetById", unknownBeneficiaryId).Return(nil, bankSlipEntities.ErrBeneficiaryNotFound).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: unknownBeneficiaryId})
+
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrBeneficiaryNotFound)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+	suit.mockMultipartFileHandler.AssertNotCalled(suit.T(), "SaveFile", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldFailWhenHeaderDoesNotMatchProfile() {
+	fileContent := bytes.NewBufferString("username,governmentId,emailConfirmed,debtAmount,debtDueDate,debtId\nrow1,row1").Bytes()
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
+
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrInvalidHeader)
+	mockSavedFile.AssertCalled(suit.T(), "Release")
+	suit.mockMessageProducer.AssertNotCalled(suit.T(), "Publish")
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusFailed
+	}))
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSendProfileLayoutWithRows() {
+	fileContent := bytes.NewBufferString("id_divida,nome,cpf,email,valor,vencimento\nrow1").Bytes()
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	brProfile, _ := bankSlipEntities.NewUploadProfile("br", map[bankSlipEntities.BankSlipField][]string{
+		bankSlipEntities.BankSlipFieldUserName:     {"nome"},
+		bankSlipEntities.BankSlipFieldGovernmentId: {"cpf"},
+		bankSlipEntities.BankSlipFieldUserEmail:    {"email"},
+		bankSlipEntities.BankSlipFieldDebtAmount:   {"valor"},
+		bankSlipEntities.BankSlipFieldDebtDueDate:  {"vencimento"},
+		bankSlipEntities.BankSlipFieldDebtId:       {"id_divida"},
+	}, "DD/MM/YYYY", ",", "ROLL_FORWARD")
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+	suit.mockUploadProfileRepo.On("GetByName", "br").Return(brProfile, nil).Once()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{Profile: "br", BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
+	assert.NoError(suit.T(), err)
+
+	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", map[string]any{
+		"data":       "row1",
+		"fileId":     "any_id",
+		"lineOffset": 2,
+		"header":     "id_divida,nome,cpf,email,valor,vencimento",
+		"layout": map[string]any{
+			"positions": map[string]any{
+				"debtId":       0,
+				"userName":     1,
+				"governmentId": 2,
+				"userEmail":    3,
+				"debtAmount":   4,
+				"debtDueDate":  5,
+			},
+			"columns":          6,
+			"dateLayout":       "02/01/2006",
+			"decimalSeparator": ",",
+			"delimiter":        ",",
+			"dueDatePolicy":    bankSlipEntities.DueDatePolicyRollForward,
+		},
+	})
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSniffDialectAndTranscodeToUTF8() {
+	fileContent := []byte("name;governmentId;email;debtAmount;debtDueDate;debtId\nJo\xe3o Concei\xe7\xe3o;123;joao@example.com;1.234,56;2024-01-19;debt1\n")
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
+	assert.NoError(suit.T(), err)
+
+	expectedLayout := testLayoutMessage()
+	expectedLayout["delimiter"] = ";"
+	expectedLayout["decimalSeparator"] = ","
+	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", map[string]any{
+		"data":       "João Conceição;123;joao@example.com;1.234,56;2024-01-19;debt1",
+		"fileId":     "any_id",
+		"lineOffset": 2,
+		"header":     "name;governmentId;email;debtAmount;debtDueDate;debtId",
+		"layout":     expectedLayout,
+	})
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldPreferExplicitDialectOptions() {
+	fileContent := []byte("name|governmentId|email|debtAmount|debtDueDate|debtId\n\x93Jos\xe9\x94|123|jose@example.com|1.234|2024-01-19|debt1\n")
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	options := bankSlipEntities.UploadOptions{Encoding: "windows-1252", Delimiter: "|", DecimalSeparator: ",", BeneficiaryId: testBeneficiaryId}
+	_, err = suit.service.Execute(file, fileHeaders, options)
+	suit.backgroundJobs.Shutdown(context.Background())
+	assert.NoError(suit.T(), err)
+
+	expectedLayout := testLayoutMessage()
+	expectedLayout["delimiter"] = "|"
+	expectedLayout["decimalSeparator"] = ","
+	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", map[string]any{
+		"data":       "“José”|123|jose@example.com|1.234|2024-01-19|debt1",
+		"fileId":     "any_id",
+		"lineOffset": 2,
+		"header":     "name|governmentId|email|debtAmount|debtDueDate|debtId",
+		"layout":     expectedLayout,
+	})
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRejectInvalidDialectOptions() {
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", []byte(testHeader+"\nrow1"))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, options := range []bankSlipEntities.UploadOptions{
+		{Encoding: "utf-16"},
+		{Delimiter: ":"},
+		{DecimalSeparator: "'"},
+	} {
+		bankSlipFile, err := suit.service.Execute(file, fileHeaders, options)
+		assert.Nil(suit.T(), bankSlipFile)
+		assert.ErrorIs(suit.T(), err, ErrInvalidUploadOptions)
+	}
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldSendChargePolicyOfBeneficiaryOverriddenByOptions() {
+	const chargingBeneficiaryId = "a1b2c3d4-0000-4000-8000-000000000003"
+	fileContent := []byte(testHeader + "\nrow1")
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	lateFee, _ := bankSlipEntities.ParseCharge("2%", ".")
+	beneficiaryInterest, _ := bankSlipEntities.ParseCharge("1%", ".")
+	uploadInterest, _ := bankSlipEntities.ParseCharge("0.33", ".")
+	beneficiary := &bankSlipEntities.Beneficiary{
+		ID:           chargingBeneficiaryId,
+		ChargePolicy: bankSlipEntities.ChargePolicy{LateFee: &lateFee, Interest: &beneficiaryInterest},
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+	suit.mockBeneficiaryRepo.On("GetById", chargingBeneficiaryId).Return(beneficiary, nil).Once()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: chargingBeneficiaryId, Interest: "0.33"})
+	suit.backgroundJobs.Shutdown(context.Background())
+	assert.NoError(suit.T(), err)
+
+	expectedLayout := testLayoutMessage()
+	expectedLayout["chargePolicy"] = bankSlipEntities.ChargePolicy{LateFee: &lateFee, Interest: &uploadInterest}
+	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", map[string]any{
+		"data":       "row1",
+		"fileId":     "any_id",
+		"lineOffset": 2,
+		"header":     testHeader,
+		"layout":     expectedLayout,
+	})
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRejectInvalidChargeOptions() {
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", []byte(testHeader+"\nrow1"))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, options := range []bankSlipEntities.UploadOptions{
+		{BeneficiaryId: testBeneficiaryId, LateFee: "2x"},
+		{BeneficiaryId: testBeneficiaryId, Discount: "100%"},
+		{BeneficiaryId: testBeneficiaryId, DiscountDays: "-1"},
+	} {
+		bankSlipFile, err := suit.service.Execute(file, fileHeaders, options)
+		assert.Nil(suit.T(), bankSlipFile)
+		assert.ErrorIs(suit.T(), err, ErrInvalidUploadOptions)
+	}
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldKeepBlankLinesSoWorkersKnowRowLines() {
+	fileContent := bytes.NewBufferString(testHeader + "\n\n\nrow1,row1\n\nrow2,row2\n").Bytes()
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
+	assert.NoError(suit.T(), err)
+
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateHeader", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.Header == testHeader && bankSlipFile.Delimiter == ","
+	}))
+	suit.mockMessageProducer.AssertNumberOfCalls(suit.T(), "Publish", 1)
+	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.MatchedBy(func(message map[string]any) bool {
+		return reflect.DeepEqual(message, map[string]any{
+			"data":       "row1,row1\n\nrow2,row2",
+			"fileId":     "any_id",
+			"lineOffset": 4,
+			"header":     testHeader,
+			"layout":     testLayoutMessage(),
 		})
 	}))
 }
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRejectFileAlreadyUploaded() {
+	fileContent := []byte(testHeader + "\nrow1,row1\n")
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+	contentHash, _ := handler.ContentHash(bytes.NewReader(fileContent))
+
+	existingFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.txt")
+	existingFile.ID = "existing_id"
+	suit.mockBankSlipFileRepo.On("GetByContentHash", mock.Anything).Unset()
+	suit.mockBankSlipFileRepo.On("GetByContentHash", contentHash).Return(existingFile, nil).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrDuplicateUpload)
+	assert.Equal(suit.T(), &bankSlipEntities.DuplicateUploadError{ExistingFileId: "existing_id"}, err)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+	suit.mockMultipartFileHandler.AssertNotCalled(suit.T(), "SaveFile", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldReturnExistingFileWhenPolicyAsks() {
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", []byte(testHeader+"\nrow1,row1\n"))
+	if err != nil {
+		panic(err)
+	}
+
+	existingFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.txt")
+	existingFile.ID = "existing_id"
+	suit.mockBankSlipFileRepo.On("GetByContentHash", mock.Anything).Unset()
+	suit.mockBankSlipFileRepo.On("GetByContentHash", mock.Anything).Return(existingFile, nil).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{DuplicatePolicy: "return_existing", BeneficiaryId: testBeneficiaryId})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), existingFile, bankSlipFile)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldIngestDuplicatedFileWhenForced() {
+	fileContent := []byte(testHeader + "\nrow1,row1\n")
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+	contentHash, _ := handler.ContentHash(bytes.NewReader(fileContent))
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{DuplicatePolicy: "force", BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), "any_id", bankSlipFile.ID)
+	assert.Equal(suit.T(), contentHash, bankSlipFile.ContentHash)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "GetByContentHash", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldReturnOriginalFileWhenIdempotencyKeyIsRetried() {
+	fileContent := []byte(testHeader + "\nrow1,row1\n")
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	originalFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.txt")
+	originalFile.ID = "original_id"
+	originalFile.ContentHash, _ = handler.ContentHash(bytes.NewReader(fileContent))
+	suit.mockBankSlipFileRepo.On("GetByIdempotencyKey", "retry-key").Return(originalFile, nil).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{IdempotencyKey: "retry-key", BeneficiaryId: testBeneficiaryId})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), originalFile, bankSlipFile)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "GetByContentHash", mock.Anything)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRefuseIdempotencyKeyUsedWithAnotherFile() {
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", []byte(testHeader+"\nrow1,row1\n"))
+	if err != nil {
+		panic(err)
+	}
+
+	originalFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.txt")
+	originalFile.ID = "original_id"
+	originalFile.ContentHash = "another_hash"
+	suit.mockBankSlipFileRepo.On("GetByIdempotencyKey", "retry-key").Return(originalFile, nil).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{IdempotencyKey: "retry-key", BeneficiaryId: testBeneficiaryId})
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrIdempotencyKeyMismatch)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldReturnConcurrentUploadWithSameIdempotencyKey() {
+	fileContent := []byte(testHeader + "\nrow1,row1\n")
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	concurrentFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.txt")
+	concurrentFile.ID = "concurrent_id"
+	concurrentFile.ContentHash, _ = handler.ContentHash(bytes.NewReader(fileContent))
+	suit.mockBankSlipFileRepo.On("GetByIdempotencyKey", "retry-key").Return(nil, bankSlipEntities.ErrBankSlipFileNotFound).Once()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Return(bankSlipEntities.ErrIdempotencyKeyAlreadyUsed).Once()
+	suit.mockBankSlipFileRepo.On("GetByIdempotencyKey", "retry-key").Return(concurrentFile, nil).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{IdempotencyKey: "retry-key", BeneficiaryId: testBeneficiaryId})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), concurrentFile, bankSlipFile)
+	suit.mockMultipartFileHandler.AssertNotCalled(suit.T(), "SaveFile", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRejectUnknownDuplicatePolicy() {
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", []byte(testHeader+"\nrow1,row1\n"))
+	if err != nil {
+		panic(err)
+	}
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{DuplicatePolicy: "ignore", BeneficiaryId: testBeneficiaryId})
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, ErrInvalidUploadOptions)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRecordWhereTheFileWasStored() {
+	fileContent := []byte(testHeader + "\nrow1,row1\n")
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile_1.txt")
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), "uploads/testfile_1.txt", bankSlipFile.StoragePath)
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStoragePath", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.StoragePath == "uploads/testfile_1.txt"
+	}))
+	mockSavedFile.AssertExpectations(suit.T())
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldDeleteStoredFileWhenItsPathCannotBeRecorded() {
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", []byte(testHeader+"\nrow1,row1\n"))
+	if err != nil {
+		panic(err)
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile_1.txt")
+	mockSavedFile.On("Delete").Return().Once()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockBankSlipFileRepo.On("UpdateStoragePath", mock.Anything).Unset()
+	suit.mockBankSlipFileRepo.On("UpdateStoragePath", mock.Anything).Return(assert.AnError).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, assert.AnError)
+	mockSavedFile.AssertExpectations(suit.T())
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusFailed
+	}))
+}
+
+func newTestFixedWidthLayout() *bankSlipEntities.FixedWidthLayout {
+	fixedWidthLayout, _ := bankSlipEntities.NewFixedWidthLayout("partner", "rem", "1", []bankSlipEntities.FixedWidthField{
+		{Name: bankSlipEntities.BankSlipFieldDebtId, Start: 2, Length: 5, Type: bankSlipEntities.FixedWidthFieldTypeText},
+		{Name: bankSlipEntities.BankSlipFieldGovernmentId, Start: 7, Length: 11, Type: bankSlipEntities.FixedWidthFieldTypeText},
+		{Name: bankSlipEntities.BankSlipFieldUserName, Start: 18, Length: 10, Type: bankSlipEntities.FixedWidthFieldTypeText},
+		{Name: bankSlipEntities.BankSlipFieldUserEmail, Start: 28, Length: 16, Type: bankSlipEntities.FixedWidthFieldTypeText},
+		{Name: bankSlipEntities.BankSlipFieldDebtAmount, Start: 44, Length: 10, Type: bankSlipEntities.FixedWidthFieldTypeDecimal, Decimals: 2},
+		{Name: bankSlipEntities.BankSlipFieldDebtDueDate, Start: 54, Length: 8, Type: bankSlipEntities.FixedWidthFieldTypeDate},
+	}, "")
+	return fixedWidthLayout
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldReadFixedWidthFileChosenByExtension() {
+	const row1 = `1debt112345678909John "JD" john@example.com000010005031122023`
+	const row2 = "1debt212345678909Mary      mary@example.com000000050031122023"
+	fileContent := bytes.NewBufferString("0HEADER\n" + row1 + "\n" + row2 + "\n9TRAILER\n").Bytes()
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("partner.REM", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	suit.mockFixedWidthLayoutRepo.ExpectedCalls = nil
+	suit.mockFixedWidthLayoutRepo.On("GetByExtension", ".rem").Return(newTestFixedWidthLayout(), nil).Once()
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/partner.REM").Maybe()
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
+	assert.NoError(suit.T(), err)
+
+	suit.mockUploadProfileRepo.AssertNotCalled(suit.T(), "GetByName", mock.Anything)
+	suit.mockMessageProducer.AssertNumberOfCalls(suit.T(), "Publish", 2)
+	for line, row := range map[int]string{2: row1, 3: row2} {
+		suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.MatchedBy(func(message map[string]any) bool {
+			layout := message["layout"].(map[string]any)
+			return message["data"] == row && message["lineOffset"] == line && message["header"] == "" && layout["fixedWidth"] != nil
+		}))
+	}
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusProcessing && bankSlipFile.TotalRows == 2
+	}))
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRejectProfileAndFixedWidthLayoutTogether() {
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("partner.rem", []byte("1row"))
+	if err != nil {
+		panic(err)
+	}
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{Profile: "br", FixedWidthLayout: "partner", BeneficiaryId: testBeneficiaryId})
+
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, ErrInvalidUploadOptions)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldRejectUnknownFixedWidthLayoutBeforeStoringFile() {
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("partner.txt", []byte("1row"))
+	if err != nil {
+		panic(err)
+	}
+
+	suit.mockFixedWidthLayoutRepo.On("GetByName", "unknown").Return(nil, bankSlipEntities.ErrFixedWidthLayoutNotFound).Once()
+
+	bankSlipFile, err := suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{FixedWidthLayout: "unknown", BeneficiaryId: testBeneficiaryId})
+
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrFixedWidthLayoutNotFound)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+}
+
+func (suit *TestSuitReceiveUploadService) TestReceiveUploadService_ShouldFailFileWhenChunkCantBePublished() {
+	fileContent := bytes.NewBufferString(testHeader + "\nrow1,row1\nrow2,row2").Bytes()
+	file, fileHeaders, err := sharedMocks.CreateMultipartFileMock("testfile.txt", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Filepath").Return("uploads/testfile.txt").Maybe()
+
+	suit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Once()
+	suit.mockMultipartFileHandler.On("SaveFile", mock.Anything).Return(mockSavedFile, nil).Once()
+	suit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(assert.AnError)
+	mockSavedFile.On("Open").Return(bytes.NewReader(fileContent)).Once()
+	mockSavedFile.On("Release").Return(nil).Once()
+
+	_, err = suit.service.Execute(file, fileHeaders, bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	suit.backgroundJobs.Shutdown(context.Background())
+	assert.NoError(suit.T(), err)
+
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusFailed
+	}))
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "CompleteWhenAllRowsProcessed", mock.Anything)
+}
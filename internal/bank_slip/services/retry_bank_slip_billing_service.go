@@ -0,0 +1,52 @@
+package bank_slip
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipProviders "performatic-file-processor/internal/bank_slip/providers"
+)
+
+type RetryBankSlipBillingServiceInterface interface {
+	Execute(debtId string) (*bankSlipEntities.BankSlip, error)
+}
+
+// RetryBankSlipBillingService bills again a bank slip left in
+// GENERATING_BILLING_ERROR. Nothing retries it on its own: most billing errors,
+// as a due date or an amount the barcode can't hold, fail the same way every
+// time, so a retry is asked for once their cause is fixed.
+type RetryBankSlipBillingService struct {
+	bankSlipRepository          bankSlipEntities.BankSlipRepository
+	generateBillingAndSentEmail bankSlipProviders.GenerateBillingAndSentEmailProvider
+}
+
+func NewRetryBankSlipBillingService(
+	bankSlipRepository bankSlipEntities.BankSlipRepository,
+	generateBillingAndSentEmail bankSlipProviders.GenerateBillingAndSentEmailProvider,
+) *RetryBankSlipBillingService {
+	return &RetryBankSlipBillingService{
+		bankSlipRepository:          bankSlipRepository,
+		generateBillingAndSentEmail: generateBillingAndSentEmail,
+	}
+}
+
+// Execute moves the bank slip back to PENDING before billing it, so the retry
+// is in its history even when the billing fails again, and returns it with the
+// status the billing left it in.
+func (s *RetryBankSlipBillingService) Execute(debtId string) (*bankSlipEntities.BankSlip, error) {
+	bankSlip, err := s.bankSlipRepository.GetByDebtId(debtId)
+	if err != nil {
+		return nil, err
+	}
+	if err := bankSlip.RetryBilling(); err != nil {
+		return nil, err
+	}
+	if err := s.bankSlipRepository.UpdateMany(&bankSlipEntities.BankSlipMap{bankSlip.DebtId: bankSlip}); err != nil {
+		return nil, err
+	}
+
+	pending := bankSlipEntities.BankSlipMap{bankSlip.DebtId: bankSlip}
+	debitsWithErrors := s.generateBillingAndSentEmail.GenerateBillingAndSentEmail(&pending)
+	if err := s.bankSlipRepository.UpdateMany(&pending, debitsWithErrors); err != nil {
+		return nil, err
+	}
+	return bankSlip, nil
+}
@@ -0,0 +1,67 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newBankSlipWithBillingError(debtId string) *bankSlipEntities.BankSlip {
+	bankSlip := newIssuedBankSlip(debtId)
+	errorMessage := "beneficiary not found"
+	bankSlip.Status = bankSlipEntities.BankSlipStatusGenerateBillingError
+	bankSlip.ErrorMessage = &errorMessage
+	bankSlip.Barcode = ""
+	bankSlip.DigitableLine = ""
+	return bankSlip
+}
+
+func TestRetryBankSlipBillingService_ShouldMoveBankSlipToPendingAndBillIt(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	provider := new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	service := NewRetryBankSlipBillingService(bankSlipRepository, provider)
+
+	bankSlip := newBankSlipWithBillingError("debt1")
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(bankSlip, nil).Once()
+	bankSlipRepository.On("UpdateMany", &bankSlipEntities.BankSlipMap{"debt1": bankSlip}).Return(nil).Once()
+	provider.On("GenerateBillingAndSentEmail", &bankSlipEntities.BankSlipMap{"debt1": bankSlip}).Return(&bankSlipEntities.BankSlipMap{}).Once()
+	bankSlipRepository.On("UpdateMany", mock.Anything, &bankSlipEntities.BankSlipMap{}).Return(nil).Once()
+
+	retried, err := service.Execute("debt1")
+	assert.NoError(t, err)
+	assert.Same(t, bankSlip, retried)
+	assert.Equal(t, bankSlipEntities.BankSlipStatusPending, retried.Status)
+	assert.Nil(t, retried.ErrorMessage)
+	bankSlipRepository.AssertNumberOfCalls(t, "UpdateMany", 2)
+}
+
+func TestRetryBankSlipBillingService_ShouldOnlyRetryFailedBillings(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	provider := new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	service := NewRetryBankSlipBillingService(bankSlipRepository, provider)
+
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(newIssuedBankSlip("debt1"), nil).Once()
+
+	retried, err := service.Execute("debt1")
+	assert.Nil(t, retried)
+	assert.ErrorIs(t, err, bankSlipEntities.ErrUnexpectedBankSlipStatus)
+	bankSlipRepository.AssertNotCalled(t, "UpdateMany", mock.Anything)
+	provider.AssertNotCalled(t, "GenerateBillingAndSentEmail", mock.Anything)
+}
+
+func TestRetryBankSlipBillingService_ShouldNotBillWhenRetryIsNotSaved(t *testing.T) {
+	bankSlipRepository := new(bankSlipMocks.BankSlipRepositoryMock)
+	provider := new(bankSlipMocks.GenerateBillingAndSentEmailProviderMock)
+	service := NewRetryBankSlipBillingService(bankSlipRepository, provider)
+
+	bankSlipRepository.On("GetByDebtId", "debt1").Return(newBankSlipWithBillingError("debt1"), nil).Once()
+	bankSlipRepository.On("UpdateMany", mock.Anything).Return(bankSlipEntities.ErrUnexpectedBankSlipStatus).Once()
+
+	_, err := service.Execute("debt1")
+	assert.ErrorIs(t, err, bankSlipEntities.ErrUnexpectedBankSlipStatus)
+	provider.AssertNotCalled(t, "GenerateBillingAndSentEmail", mock.Anything)
+}
@@ -0,0 +1,182 @@
+package bank_slip
+
+import (
+	"context"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/messaging"
+)
+
+// rowsPublisher splits the records of an upload into chunks and publishes
+// them to the workers, keeping the file status up to date.
+type rowsPublisher struct {
+	bankSlipFileMetadataRepository bankSlipEntities.BankSlipFileMetadataRepository
+	messageProducer                messaging.MessageProducer
+	workers                        int
+	bufferSize                     int
+}
+
+func (p *rowsPublisher) publish(ctx context.Context, bankSlipFile *bankSlipEntities.BankSlipFileMetadata, records recordReader, header string, layout *bankSlipEntities.BankSlipRowLayout) error {
+	start := time.Now()
+
+	chunks := &chunkPublisher{
+		messageProducer: p.messageProducer,
+		topic:           "rows-to-process",
+		workers:         p.workers,
+		bufferSize:      p.bufferSize,
+	}
+	totalRows, err := chunks.publish(ctx, bankSlipFile.ID, records, func(chunk recordsChunk) map[string]any {
+		return map[string]any{"data": string(chunk.data), "header": header, "fileId": bankSlipFile.ID, "layout": layout.ToMessage(), "lineOffset": chunk.line}
+	})
+	if err != nil {
+		log.Printf("Error publishing file content (id: %s): %v", bankSlipFile.ID, err)
+		p.markAsFailed(bankSlipFile)
+		return err
+	}
+
+	bankSlipFile.Processing(totalRows)
+	err = p.bankSlipFileMetadataRepository.UpdateStatus(bankSlipFile)
+	if err != nil {
+		log.Printf("Error updating bank slip file status (id: %s): %v", bankSlipFile.ID, err)
+		return err
+	}
+	err = p.bankSlipFileMetadataRepository.CompleteWhenAllRowsProcessed(bankSlipFile.ID)
+	if err != nil {
+		log.Printf("Error completing bank slip file (id: %s): %v", bankSlipFile.ID, err)
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("Time taken: %s (file id: %s)\n", elapsed, bankSlipFile.ID)
+	return nil
+}
+
+func (p *rowsPublisher) markAsFailed(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) {
+	bankSlipFile.Failed()
+	err := p.bankSlipFileMetadataRepository.UpdateStatus(bankSlipFile)
+	if err != nil {
+		log.Printf("Error updating bank slip file status (id: %s): %v", bankSlipFile.ID, err)
+	}
+}
+
+// recordReader returns the records of a file one at a time, with the line
+// each of them begins at.
+type recordReader interface {
+	Next() (string, error)
+	Line() int
+}
+
+// recordsChunk is a group of records published in a single message, starting
+// at line of the file.
+type recordsChunk struct {
+	data    []byte
+	line    int
+	records int
+}
+
+// chunkPublisher splits the records of a file into chunks of about bufferSize
+// bytes and publishes them to topic from a pool of workers.
+type chunkPublisher struct {
+	messageProducer messaging.MessageProducer
+	topic           string
+	workers         int
+	bufferSize      int
+}
+
+// publish returns how many records were read, message builds what is
+// published for each chunk. A chunk that can't be published fails the file:
+// the file stops being read and the chunks left are dropped.
+func (p *chunkPublisher) publish(ctx context.Context, fileId string, records recordReader, message func(chunk recordsChunk) map[string]any) (int, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	chunks := make(chan recordsChunk, p.workers)
+
+	var wg sync.WaitGroup
+	wg.Add(p.workers)
+
+	for i := range p.workers {
+		go p.publishChunks(ctx, cancel, i, chunks, fileId, message, &wg)
+	}
+
+	totalRecords, err := p.readChunks(ctx, records, chunks)
+
+	close(chunks)
+
+	wg.Wait()
+
+	if err == nil {
+		err = context.Cause(ctx)
+	}
+	return totalRecords, err
+}
+
+func (p *chunkPublisher) readChunks(ctx context.Context, records recordReader, chunks chan recordsChunk) (totalRecords int, err error) {
+	var chunk strings.Builder
+	chunkLine, chunkRecords := 0, 0
+	for {
+		if ctx.Err() != nil {
+			return totalRecords, context.Cause(ctx)
+		}
+		record, err := records.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return totalRecords, err
+		}
+		// Blank lines are kept inside a chunk so workers can still tell the
+		// original line of every record from the chunk starting line.
+		if record == "" {
+			if chunk.Len() > 0 {
+				chunk.WriteByte('\n')
+			}
+			continue
+		}
+
+		if chunk.Len() > 0 {
+			chunk.WriteByte('\n')
+		} else {
+			chunkLine = records.Line()
+		}
+		chunk.WriteString(record)
+		chunkRecords++
+		totalRecords++
+
+		if chunk.Len() >= p.bufferSize {
+			chunks <- recordsChunk{data: []byte(chunk.String()), line: chunkLine, records: chunkRecords}
+			chunk.Reset()
+			chunkRecords = 0
+		}
+	}
+
+	if chunk.Len() > 0 {
+		chunks <- recordsChunk{data: []byte(chunk.String()), line: chunkLine, records: chunkRecords}
+	}
+	return totalRecords, nil
+}
+
+// publishChunks publishes the chunks until they run out, cancelling ctx with
+// the error of the first one that can't be published. Chunks read after ctx
+// is done are dropped, so the reader is never left blocked.
+func (p *chunkPublisher) publishChunks(ctx context.Context, cancel context.CancelCauseFunc, worker int, chunks chan recordsChunk, fileId string, message func(chunk recordsChunk) map[string]any, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for chunk := range chunks {
+		if ctx.Err() != nil {
+			continue
+		}
+
+		log.Printf("Posting message to kafka for file %s (%d bytes)", fileId, len(chunk.data))
+		err := p.messageProducer.Publish(ctx, p.topic, message(chunk))
+		if err != nil {
+			log.Printf("Error posting message for file %s: %v", fileId, err)
+			cancel(err)
+		}
+	}
+	log.Printf("Worker %d finished processing\n", worker)
+}
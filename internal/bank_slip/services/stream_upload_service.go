@@ -0,0 +1,227 @@
+package bank_slip
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/handler"
+	"performatic-file-processor/internal/messaging"
+)
+
+type StreamUploadServiceInterface interface {
+	Execute(ctx context.Context, content io.Reader, fileName string, options bankSlipEntities.UploadOptions) (*bankSlipEntities.BankSlipFileMetadata, error)
+}
+
+// StreamUploadService chunks and publishes an upload while its bytes arrive,
+// without staging the whole file first. The content hash is only known at the
+// end, so the duplicate policy applies once every row is published: a
+// duplicate upload is marked as FAILED and the workers drop the chunks of it
+// they haven't processed yet. The rows they already processed have the debt
+// ids of the first upload and are rejected as duplicates.
+type StreamUploadService struct {
+	bankSlipFileMetadataRepository bankSlipEntities.BankSlipFileMetadataRepository
+	uploadProfileRepository        bankSlipEntities.UploadProfileRepository
+	fixedWidthLayoutRepository     bankSlipEntities.FixedWidthLayoutRepository
+	beneficiaryRepository          bankSlipEntities.BeneficiaryRepository
+	fileHandler                    handler.FileHandler
+	duplicatePolicy                bankSlipEntities.DuplicateUploadPolicy
+	archive                        bool
+	bufferSize                     int
+	rowsPublisher                  *rowsPublisher
+}
+
+func NewStreamUploadService(
+	bankSlipFileRepo bankSlipEntities.BankSlipFileMetadataRepository,
+	uploadProfileRepo bankSlipEntities.UploadProfileRepository,
+	fixedWidthLayoutRepo bankSlipEntities.FixedWidthLayoutRepository,
+	beneficiaryRepo bankSlipEntities.BeneficiaryRepository,
+	fileHandler handler.FileHandler,
+	messageProducer messaging.MessageProducer,
+	duplicatePolicy bankSlipEntities.DuplicateUploadPolicy,
+	archive bool,
+	bufferSize int,
+	workers int,
+) *StreamUploadService {
+	return &StreamUploadService{
+		bankSlipFileMetadataRepository: bankSlipFileRepo,
+		uploadProfileRepository:        uploadProfileRepo,
+		fixedWidthLayoutRepository:     fixedWidthLayoutRepo,
+		beneficiaryRepository:          beneficiaryRepo,
+		fileHandler:                    fileHandler,
+		duplicatePolicy:                duplicatePolicy,
+		archive:                        archive,
+		bufferSize:                     bufferSize,
+		rowsPublisher: &rowsPublisher{
+			bankSlipFileMetadataRepository: bankSlipFileRepo,
+			messageProducer:                messageProducer,
+			workers:                        workers,
+			bufferSize:                     bufferSize,
+		},
+	}
+}
+
+func (s *StreamUploadService) Execute(ctx context.Context, content io.Reader, fileName string, options bankSlipEntities.UploadOptions) (*bankSlipEntities.BankSlipFileMetadata, error) {
+	dialect, err := newUploadDialect(options)
+	if err != nil {
+		return nil, err
+	}
+	duplicatePolicy, err := getDuplicatePolicy(s.duplicatePolicy, options)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := getUploadFormat(s.uploadProfileRepository, s.fixedWidthLayoutRepository, options, fileName)
+	if err != nil {
+		log.Printf("Error getting upload format of %s: %v", fileName, err)
+		return nil, err
+	}
+
+	beneficiary, err := getBeneficiary(s.beneficiaryRepository, options.BeneficiaryId)
+	if err != nil {
+		log.Printf("Error getting beneficiary %s: %v", options.BeneficiaryId, err)
+		return nil, err
+	}
+	chargePolicy, err := getChargePolicy(beneficiary, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.IdempotencyKey != "" {
+		existingFile, err := s.getIdempotentUpload(options.IdempotencyKey)
+		if existingFile != nil || err != nil {
+			return existingFile, err
+		}
+	}
+
+	bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata(fileName)
+	bankSlipFile.IdempotencyKey = options.IdempotencyKey
+	bankSlipFile.BeneficiaryId = beneficiary.ID
+
+	err = s.bankSlipFileMetadataRepository.Insert(bankSlipFile)
+	if errors.Is(err, bankSlipEntities.ErrIdempotencyKeyAlreadyUsed) {
+		return s.getIdempotentUpload(options.IdempotencyKey)
+	}
+	if err != nil {
+		log.Println("Error inserting bank slip file metadata", err)
+		return nil, err
+	}
+	log.Printf("Streaming file (id: %s)...", bankSlipFile.ID)
+
+	contentHash := sha256.New()
+	reader := io.TeeReader(content, contentHash)
+
+	var archive *uploadArchive
+	if s.archive {
+		archive = startUploadArchive(s.fileHandler, fileName)
+		reader = io.TeeReader(reader, archive)
+	}
+
+	err = s.publishRows(ctx, bankSlipFile, format, dialect, chargePolicy, reader)
+	if archive != nil {
+		s.closeArchive(archive, bankSlipFile, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bankSlipFile.ContentHash = hex.EncodeToString(contentHash.Sum(nil))
+	if duplicatePolicy != bankSlipEntities.DuplicateUploadPolicyForce {
+		existingFile, err := s.getDuplicateUpload(bankSlipFile)
+		if existingFile != nil || err != nil {
+			s.rowsPublisher.markAsFailed(bankSlipFile)
+		}
+		if existingFile != nil && duplicatePolicy == bankSlipEntities.DuplicateUploadPolicyReject {
+			return nil, &bankSlipEntities.DuplicateUploadError{ExistingFileId: existingFile.ID}
+		}
+		if existingFile != nil || err != nil {
+			return existingFile, err
+		}
+	}
+	err = s.bankSlipFileMetadataRepository.UpdateContentHash(bankSlipFile)
+	if err != nil {
+		log.Printf("Error saving file content hash (id: %s): %v", bankSlipFile.ID, err)
+	}
+	return bankSlipFile, nil
+}
+
+// closeArchive waits for the archival copy and records where it was stored.
+// The rows are already published, so failures are only logged.
+func (s *StreamUploadService) closeArchive(archive *uploadArchive, bankSlipFile *bankSlipEntities.BankSlipFileMetadata, cause error) {
+	savedFile, err := archive.close(cause)
+	if cause != nil {
+		return
+	}
+	if err != nil {
+		log.Printf("Error archiving file (id: %s): %v", bankSlipFile.ID, err)
+		return
+	}
+
+	bankSlipFile.Stored(savedFile.Filepath())
+	err = s.bankSlipFileMetadataRepository.UpdateStoragePath(bankSlipFile)
+	if err != nil {
+		log.Printf("Error saving file storage path (id: %s): %v", bankSlipFile.ID, err)
+	}
+}
+
+func (s *StreamUploadService) publishRows(ctx context.Context, bankSlipFile *bankSlipEntities.BankSlipFileMetadata, format *uploadFormat, dialect *uploadDialect, chargePolicy bankSlipEntities.ChargePolicy, reader io.Reader) error {
+	content, sample, err := dialect.open(reader)
+	if err != nil {
+		log.Printf("Error sniffing file dialect (id: %s): %v", bankSlipFile.ID, err)
+		s.rowsPublisher.markAsFailed(bankSlipFile)
+		return err
+	}
+
+	records, header, layout, err := format.open(content, sample, dialect, s.bufferSize)
+	if err != nil {
+		log.Printf("Error reading file header with %s (id: %s): %v", format, bankSlipFile.ID, err)
+		s.rowsPublisher.markAsFailed(bankSlipFile)
+		return err
+	}
+	layout.ChargePolicy = chargePolicy
+
+	bankSlipFile.HeaderRead(header, string(layout.Comma()))
+	err = s.bankSlipFileMetadataRepository.UpdateHeader(bankSlipFile)
+	if err != nil {
+		log.Printf("Error saving file header (id: %s): %v", bankSlipFile.ID, err)
+		s.rowsPublisher.markAsFailed(bankSlipFile)
+		return err
+	}
+
+	return s.rowsPublisher.publish(ctx, bankSlipFile, records, header, layout)
+}
+
+// getDuplicateUpload returns the file uploaded before with the content of
+// bankSlipFile, or nil when there's none. The hash of bankSlipFile isn't
+// recorded yet, so it can't find itself.
+func (s *StreamUploadService) getDuplicateUpload(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) (*bankSlipEntities.BankSlipFileMetadata, error) {
+	existingFile, err := s.bankSlipFileMetadataRepository.GetByContentHash(bankSlipFile.ContentHash)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipFileNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error looking for an upload with the same content (id: %s): %v", bankSlipFile.ID, err)
+		return nil, err
+	}
+	log.Printf("File already uploaded as %s, dropping its stream (id: %s)", existingFile.ID, bankSlipFile.ID)
+	return existingFile, nil
+}
+
+// getIdempotentUpload returns the file created by a previous request with the
+// same key. The content of a streamed retry can't be compared before reading it.
+func (s *StreamUploadService) getIdempotentUpload(idempotencyKey string) (*bankSlipEntities.BankSlipFileMetadata, error) {
+	existingFile, err := s.bankSlipFileMetadataRepository.GetByIdempotencyKey(idempotencyKey)
+	if errors.Is(err, bankSlipEntities.ErrBankSlipFileNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error getting upload by idempotency key %s: %v", idempotencyKey, err)
+		return nil, err
+	}
+	log.Printf("Upload retried with idempotency key %s, returning existing file (id: %s)", idempotencyKey, existingFile.ID)
+	return existingFile, nil
+}
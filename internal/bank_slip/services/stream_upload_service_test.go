@@ -0,0 +1,202 @@
+package bank_slip
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/handler"
+	sharedMocks "performatic-file-processor/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitStreamUploadService struct {
+	suite.Suite
+	mockBankSlipFileRepo     *bankSlipMocks.BankSlipFileMetadataRepositoryMock
+	mockUploadProfileRepo    *bankSlipMocks.UploadProfileRepositoryMock
+	mockFixedWidthLayoutRepo *bankSlipMocks.FixedWidthLayoutRepositoryMock
+	mockBeneficiaryRepo      *bankSlipMocks.BeneficiaryRepositoryMock
+	mockFileHandler          *sharedMocks.FileHandlerMock
+	mockMessageProducer      *sharedMocks.MessageProducerMock
+	service                  *StreamUploadService
+}
+
+func (testSuit *TestSuitStreamUploadService) SetupTest() {
+	testSuit.mockBankSlipFileRepo = new(bankSlipMocks.BankSlipFileMetadataRepositoryMock)
+	testSuit.mockUploadProfileRepo = new(bankSlipMocks.UploadProfileRepositoryMock)
+	testSuit.mockFixedWidthLayoutRepo = new(bankSlipMocks.FixedWidthLayoutRepositoryMock)
+	testSuit.mockBeneficiaryRepo = new(bankSlipMocks.BeneficiaryRepositoryMock)
+	testSuit.mockFileHandler = new(sharedMocks.FileHandlerMock)
+	testSuit.mockMessageProducer = new(sharedMocks.MessageProducerMock)
+	testSuit.mockBankSlipFileRepo.On("Insert", mock.Anything).Run(func(arg mock.Arguments) {
+		arg.Get(0).(*bankSlipEntities.BankSlipFileMetadata).ID = "any_id"
+	}).Return(nil).Maybe()
+	testSuit.mockBankSlipFileRepo.On("UpdateHeader", mock.Anything).Return(nil).Maybe()
+	testSuit.mockBankSlipFileRepo.On("UpdateContentHash", mock.Anything).Return(nil).Maybe()
+	testSuit.mockBankSlipFileRepo.On("GetByContentHash", mock.Anything).Return(nil, bankSlipEntities.ErrBankSlipFileNotFound).Maybe()
+	testSuit.mockBankSlipFileRepo.On("UpdateStoragePath", mock.Anything).Return(nil).Maybe()
+	testSuit.mockBankSlipFileRepo.On("UpdateStatus", mock.Anything).Return(nil).Maybe()
+	testSuit.mockBankSlipFileRepo.On("CompleteWhenAllRowsProcessed", mock.Anything).Return(nil).Maybe()
+	testSuit.mockMessageProducer.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	testSuit.mockUploadProfileRepo.On("GetByName", bankSlipEntities.DefaultUploadProfileName).Return(bankSlipEntities.DefaultUploadProfile(), nil).Maybe()
+	testSuit.mockFixedWidthLayoutRepo.On("GetByExtension", mock.Anything).Return(nil, bankSlipEntities.ErrFixedWidthLayoutNotFound).Maybe()
+	testSuit.mockBeneficiaryRepo.On("GetById", testBeneficiaryId).Return(&bankSlipEntities.Beneficiary{ID: testBeneficiaryId}, nil).Maybe()
+
+	testSuit.service = testSuit.newService(false)
+}
+
+func (testSuit *TestSuitStreamUploadService) newService(archive bool) *StreamUploadService {
+	return NewStreamUploadService(
+		testSuit.mockBankSlipFileRepo,
+		testSuit.mockUploadProfileRepo,
+		testSuit.mockFixedWidthLayoutRepo,
+		testSuit.mockBeneficiaryRepo,
+		testSuit.mockFileHandler,
+		testSuit.mockMessageProducer,
+		bankSlipEntities.DuplicateUploadPolicyReject,
+		archive,
+		len("headerData"),
+		2,
+	)
+}
+
+func TestStreamUploadService(t *testing.T) {
+	suite.Run(t, new(TestSuitStreamUploadService))
+}
+
+func (suit *TestSuitStreamUploadService) TestStreamUploadService_ShouldPublishRowsWhileReading() {
+	fileContent := testHeader + "\nrow1\nrow2\n"
+
+	bankSlipFile, err := suit.service.Execute(context.Background(), strings.NewReader(fileContent), "testfile.csv", bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), "any_id", bankSlipFile.ID)
+	assert.Equal(suit.T(), testHeader, bankSlipFile.Header)
+
+	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", map[string]any{
+		"data":       "row1\nrow2",
+		"fileId":     "any_id",
+		"lineOffset": 2,
+		"header":     testHeader,
+		"layout":     testLayoutMessage(),
+	})
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusProcessing && bankSlipFile.TotalRows == 2
+	}))
+	suit.mockFileHandler.AssertNotCalled(suit.T(), "SaveFile", mock.Anything)
+}
+
+func (suit *TestSuitStreamUploadService) TestStreamUploadService_ShouldRecordContentHashAfterReading() {
+	fileContent := []byte(testHeader + "\nrow1\n")
+	contentHash, _ := handler.ContentHash(bytes.NewReader(fileContent))
+
+	bankSlipFile, err := suit.service.Execute(context.Background(), bytes.NewReader(fileContent), "testfile.csv", bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), contentHash, bankSlipFile.ContentHash)
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateContentHash", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.ContentHash == contentHash
+	}))
+}
+
+func (suit *TestSuitStreamUploadService) TestStreamUploadService_ShouldArchiveCopyWhenEnabled() {
+	service := suit.newService(true)
+	fileContent := testHeader + "\nrow1\nrow2\n"
+
+	var archived []byte
+	mockSavedFile := sharedMocks.NewSavedFileMock()
+	mockSavedFile.On("Close").Return().Once()
+	mockSavedFile.On("Filepath").Return("uploads/testfile_1.csv")
+	suit.mockFileHandler.On("SaveFile", mock.Anything).Run(func(arg mock.Arguments) {
+		file := arg.Get(0).(handler.File)
+		assert.Equal(suit.T(), "testfile.csv", file.FileName())
+		archived, _ = io.ReadAll(file.Reader())
+	}).Return(mockSavedFile, nil).Once()
+
+	bankSlipFile, err := service.Execute(context.Background(), strings.NewReader(fileContent), "testfile.csv", bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), fileContent, string(archived))
+	assert.Equal(suit.T(), "uploads/testfile_1.csv", bankSlipFile.StoragePath)
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStoragePath", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.StoragePath == "uploads/testfile_1.csv"
+	}))
+	mockSavedFile.AssertExpectations(suit.T())
+}
+
+func (suit *TestSuitStreamUploadService) TestStreamUploadService_ShouldKeepIngestingWhenArchiveFails() {
+	service := suit.newService(true)
+	suit.mockFileHandler.On("SaveFile", mock.Anything).Return(nil, assert.AnError).Once()
+
+	bankSlipFile, err := service.Execute(context.Background(), strings.NewReader(testHeader+"\nrow1\n"), "testfile.csv", bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), "any_id", bankSlipFile.ID)
+	suit.mockMessageProducer.AssertCalled(suit.T(), "Publish", mock.Anything, "rows-to-process", mock.Anything)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "UpdateStoragePath", mock.Anything)
+}
+
+func (suit *TestSuitStreamUploadService) TestStreamUploadService_ShouldMarkFileAsFailedWhenReadingFails() {
+	mockedReader := sharedMocks.NewReaderMock()
+	mockedReader.On("Read", mock.Anything).Return(0, assert.AnError).Once()
+
+	bankSlipFile, err := suit.service.Execute(context.Background(), mockedReader, "testfile.csv", bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, assert.AnError)
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusFailed
+	}))
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "UpdateContentHash", mock.Anything)
+}
+
+func (suit *TestSuitStreamUploadService) TestStreamUploadService_ShouldFailWhenHeaderDoesNotMatchProfile() {
+	bankSlipFile, err := suit.service.Execute(context.Background(), strings.NewReader("a,b\nrow1\n"), "testfile.csv", bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrInvalidHeader)
+	suit.mockMessageProducer.AssertNotCalled(suit.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suit *TestSuitStreamUploadService) TestStreamUploadService_ShouldReturnOriginalFileWhenIdempotencyKeyIsRetried() {
+	originalFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.csv")
+	originalFile.ID = "original_id"
+	suit.mockBankSlipFileRepo.On("GetByIdempotencyKey", "retry-key").Return(originalFile, nil).Once()
+
+	bankSlipFile, err := suit.service.Execute(context.Background(), strings.NewReader(testHeader+"\nrow1\n"), "testfile.csv", bankSlipEntities.UploadOptions{IdempotencyKey: "retry-key", BeneficiaryId: testBeneficiaryId})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), originalFile, bankSlipFile)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+}
+
+func (suit *TestSuitStreamUploadService) TestStreamUploadService_ShouldRejectInvalidDialectOptions() {
+	bankSlipFile, err := suit.service.Execute(context.Background(), strings.NewReader(testHeader+"\nrow1\n"), "testfile.csv", bankSlipEntities.UploadOptions{Encoding: "EBCDIC", BeneficiaryId: testBeneficiaryId})
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, ErrInvalidUploadOptions)
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "Insert", mock.Anything)
+}
+
+func (suit *TestSuitStreamUploadService) TestStreamUploadService_ShouldFailDuplicateUploadOnceContentIsRead() {
+	fileContent := []byte(testHeader + "\nrow1\n")
+	contentHash, _ := handler.ContentHash(bytes.NewReader(fileContent))
+	existingFile := bankSlipEntities.NewBankSlipFileMetadata("testfile.csv")
+	existingFile.ID = "existing_id"
+	suit.mockBankSlipFileRepo.ExpectedCalls = slices.DeleteFunc(suit.mockBankSlipFileRepo.ExpectedCalls, func(call *mock.Call) bool {
+		return call.Method == "GetByContentHash"
+	})
+	suit.mockBankSlipFileRepo.On("GetByContentHash", contentHash).Return(existingFile, nil)
+
+	bankSlipFile, err := suit.service.Execute(context.Background(), bytes.NewReader(fileContent), "testfile.csv", bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId})
+	assert.Nil(suit.T(), bankSlipFile)
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrDuplicateUpload)
+	suit.mockBankSlipFileRepo.AssertCalled(suit.T(), "UpdateStatus", mock.MatchedBy(func(bankSlipFile *bankSlipEntities.BankSlipFileMetadata) bool {
+		return bankSlipFile.ID == "any_id" && bankSlipFile.Status == bankSlipEntities.BankSlipFileStatusFailed
+	}))
+	suit.mockBankSlipFileRepo.AssertNotCalled(suit.T(), "UpdateContentHash", mock.Anything)
+
+	bankSlipFile, err = suit.service.Execute(context.Background(), bytes.NewReader(fileContent), "testfile.csv", bankSlipEntities.UploadOptions{BeneficiaryId: testBeneficiaryId, DuplicatePolicy: "return_existing"})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), existingFile, bankSlipFile)
+}
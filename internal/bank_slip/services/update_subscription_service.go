@@ -0,0 +1,53 @@
+package bank_slip
+
+import (
+	"time"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/calendar"
+)
+
+type UpdateSubscriptionServiceInterface interface {
+	Execute(id string, terms bankSlipEntities.SubscriptionTerms) (*bankSlipEntities.Subscription, error)
+}
+
+type UpdateSubscriptionService struct {
+	subscriptionRepository bankSlipEntities.SubscriptionRepository
+	beneficiaryRepository  bankSlipEntities.BeneficiaryRepository
+	businessDays           *calendar.Calendar
+}
+
+func NewUpdateSubscriptionService(
+	subscriptionRepo bankSlipEntities.SubscriptionRepository,
+	beneficiaryRepo bankSlipEntities.BeneficiaryRepository,
+	businessDays *calendar.Calendar,
+) *UpdateSubscriptionService {
+	return &UpdateSubscriptionService{
+		subscriptionRepository: subscriptionRepo,
+		beneficiaryRepository:  beneficiaryRepo,
+		businessDays:           businessDays,
+	}
+}
+
+// Execute replaces the terms of an ACTIVE subscription, the charge policy
+// being resolved again with the one the beneficiary has now. Bank slips
+// already issued keep the terms they were issued with.
+func (s *UpdateSubscriptionService) Execute(id string, terms bankSlipEntities.SubscriptionTerms) (*bankSlipEntities.Subscription, error) {
+	subscription, err := s.subscriptionRepository.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	beneficiary, err := s.beneficiaryRepository.GetById(subscription.BeneficiaryId)
+	if err != nil {
+		return nil, err
+	}
+	terms.ChargePolicy = beneficiary.ChargePolicy.Override(terms.ChargePolicy)
+
+	if err := subscription.ChangeTerms(terms, time.Now(), s.businessDays); err != nil {
+		return nil, err
+	}
+	if err := s.subscriptionRepository.Update(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
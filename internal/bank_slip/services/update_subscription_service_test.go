@@ -0,0 +1,45 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/calendar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateSubscriptionService_ShouldChangeTermsOfActiveSubscription(t *testing.T) {
+	subscriptionRepository := new(bankSlipMocks.SubscriptionRepositoryMock)
+	beneficiaryRepository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewUpdateSubscriptionService(subscriptionRepository, beneficiaryRepository, calendar.New())
+
+	subscription := newDueSubscription("6b1f9c2e-3d4a-4f5b-8c6d-7e8f9a0b1c2d")
+	subscriptionRepository.On("GetById", subscription.ID).Return(subscription, nil).Once()
+	beneficiaryRepository.On("GetById", "beneficiary1").Return(&bankSlipEntities.Beneficiary{ID: "beneficiary1"}, nil).Once()
+	subscriptionRepository.On("Update", subscription).Return(nil).Once()
+
+	terms := newTestSubscriptionTerms()
+	terms.Amount = "180.00"
+	updated, err := service.Execute(subscription.ID, terms)
+	assert.NoError(t, err)
+	assert.Equal(t, bankSlipEntities.Money(18000), updated.Amount)
+	subscriptionRepository.AssertExpectations(t)
+}
+
+func TestUpdateSubscriptionService_ShouldNotSaveCancelledSubscription(t *testing.T) {
+	subscriptionRepository := new(bankSlipMocks.SubscriptionRepositoryMock)
+	beneficiaryRepository := new(bankSlipMocks.BeneficiaryRepositoryMock)
+	service := NewUpdateSubscriptionService(subscriptionRepository, beneficiaryRepository, calendar.New())
+
+	subscription := newDueSubscription("6b1f9c2e-3d4a-4f5b-8c6d-7e8f9a0b1c2d")
+	subscription.Status = bankSlipEntities.SubscriptionStatusCancelled
+	subscriptionRepository.On("GetById", subscription.ID).Return(subscription, nil).Once()
+	beneficiaryRepository.On("GetById", "beneficiary1").Return(&bankSlipEntities.Beneficiary{ID: "beneficiary1"}, nil).Once()
+
+	_, err := service.Execute(subscription.ID, newTestSubscriptionTerms())
+	assert.ErrorIs(t, err, bankSlipEntities.ErrSubscriptionNotActive)
+	subscriptionRepository.AssertNotCalled(t, "Update", mock.Anything)
+}
@@ -0,0 +1,53 @@
+package bank_slip
+
+import (
+	"io"
+
+	"performatic-file-processor/internal/handler"
+)
+
+// uploadArchive copies what is read from a streamed upload into the file
+// handler while the rows are published. A failing copy never holds the
+// ingestion back, it is only reported when the archive is closed.
+type uploadArchive struct {
+	writer    *io.PipeWriter
+	failed    bool
+	savedFile handler.SavedFile
+	done      chan error
+}
+
+func startUploadArchive(fileHandler handler.FileHandler, fileName string) *uploadArchive {
+	reader, writer := io.Pipe()
+	archive := &uploadArchive{writer: writer, done: make(chan error, 1)}
+
+	go func() {
+		savedFile, err := fileHandler.SaveFile(handler.NewReaderFile(fileName, reader))
+		if err != nil {
+			reader.CloseWithError(err)
+			archive.done <- err
+			return
+		}
+		savedFile.Close()
+		archive.savedFile = savedFile
+		archive.done <- nil
+	}()
+	return archive
+}
+
+func (a *uploadArchive) Write(p []byte) (int, error) {
+	if !a.failed {
+		if _, err := a.writer.Write(p); err != nil {
+			a.failed = true
+		}
+	}
+	return len(p), nil
+}
+
+// close ends the copy, a non nil cause aborts it, and waits for it to be saved.
+func (a *uploadArchive) close(cause error) (handler.SavedFile, error) {
+	a.writer.CloseWithError(cause)
+	if err := <-a.done; err != nil {
+		return nil, err
+	}
+	return a.savedFile, nil
+}
@@ -0,0 +1,103 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/handler"
+)
+
+var ErrInvalidUploadOptions = errors.New("invalid upload options")
+
+const dialectSampleSize = 64 * 1024
+
+// uploadDialect describes how the uploaded bytes must be read. Zero values
+// are sniffed from the beginning of the file.
+type uploadDialect struct {
+	encoding         handler.Encoding
+	delimiter        rune
+	decimalSeparator string
+}
+
+func newUploadDialect(options bankSlipEntities.UploadOptions) (*uploadDialect, error) {
+	dialect := &uploadDialect{}
+
+	if options.Encoding != "" {
+		encoding, err := handler.ParseEncoding(options.Encoding)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %s", ErrInvalidUploadOptions, err.Error(), options.Encoding)
+		}
+		dialect.encoding = encoding
+	}
+	if options.Delimiter != "" {
+		delimiter, err := handler.ParseDelimiter(options.Delimiter)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %s", ErrInvalidUploadOptions, err.Error(), options.Delimiter)
+		}
+		dialect.delimiter = delimiter
+	}
+	if options.DecimalSeparator != "" {
+		if options.DecimalSeparator != "." && options.DecimalSeparator != "," {
+			return nil, fmt.Errorf("%w: unsupported decimal separator %s", ErrInvalidUploadOptions, options.DecimalSeparator)
+		}
+		dialect.decimalSeparator = options.DecimalSeparator
+	}
+	return dialect, nil
+}
+
+// open sniffs whatever was not given explicitly and returns the file content
+// transcoded to UTF-8 along with a decoded sample of its beginning.
+func (d *uploadDialect) open(reader io.Reader) (io.Reader, string, error) {
+	sample, reader := handler.PeekSample(reader, dialectSampleSize)
+	if d.encoding == "" {
+		d.encoding = handler.DetectEncoding(sample)
+	}
+
+	decodedSample, err := io.ReadAll(handler.NewUTF8Reader(strings.NewReader(string(sample)), d.encoding))
+	if err != nil {
+		return nil, "", err
+	}
+	if d.delimiter == 0 {
+		d.delimiter = handler.SniffDelimiter(string(decodedSample))
+	}
+	return handler.NewUTF8Reader(reader, d.encoding), string(decodedSample), nil
+}
+
+// applyTo sets the delimiter on the layout and, unless it was given, replaces
+// the profile decimal separator by the one the sampled amounts clearly use.
+func (d *uploadDialect) applyTo(layout *bankSlipEntities.BankSlipRowLayout, sample string) {
+	layout.Delimiter = string(d.delimiter)
+
+	decimalSeparator := d.decimalSeparator
+	if decimalSeparator == "" {
+		decimalSeparator = bankSlipEntities.SniffDecimalSeparator(sampleAmounts(sample, layout))
+	}
+	if decimalSeparator != "" {
+		layout.DecimalSeparator = decimalSeparator
+	}
+}
+
+func sampleAmounts(sample string, layout *bankSlipEntities.BankSlipRowLayout) []string {
+	if i := strings.LastIndexByte(sample, '\n'); i > 0 {
+		sample = sample[:i+1]
+	}
+	rows := bankSlipEntities.NewCSVReader(strings.NewReader(strings.TrimPrefix(sample, "\ufeff")), layout.Comma())
+	rows.LazyQuotes = true
+
+	amounts := []string{}
+	if _, err := rows.Read(); err != nil {
+		return amounts
+	}
+	for {
+		rowItems, err := rows.Read()
+		if err != nil {
+			return amounts
+		}
+		if len(rowItems) == layout.Columns {
+			amounts = append(amounts, layout.Value(rowItems, bankSlipEntities.BankSlipFieldDebtAmount))
+		}
+	}
+}
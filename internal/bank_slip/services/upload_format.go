@@ -0,0 +1,120 @@
+package bank_slip
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/handler"
+)
+
+// uploadFormat is how the rows of an upload are read: a CSV file whose header
+// is resolved with an upload profile, or fixed-width records when a layout is
+// chosen.
+type uploadFormat struct {
+	uploadProfile    *bankSlipEntities.UploadProfile
+	fixedWidthLayout *bankSlipEntities.FixedWidthLayout
+}
+
+// getUploadFormat picks the fixed-width layout named in the options or, when
+// no upload profile is named either, the one of the file extension.
+func getUploadFormat(
+	uploadProfileRepository bankSlipEntities.UploadProfileRepository,
+	fixedWidthLayoutRepository bankSlipEntities.FixedWidthLayoutRepository,
+	options bankSlipEntities.UploadOptions,
+	fileName string,
+) (*uploadFormat, error) {
+	if options.FixedWidthLayout != "" && options.Profile != "" {
+		return nil, fmt.Errorf("%w: profile and fixed-width layout can't be used together", ErrInvalidUploadOptions)
+	}
+	if options.FixedWidthLayout != "" {
+		fixedWidthLayout, err := fixedWidthLayoutRepository.GetByName(options.FixedWidthLayout)
+		if err != nil {
+			return nil, err
+		}
+		return &uploadFormat{fixedWidthLayout: fixedWidthLayout}, nil
+	}
+
+	if options.Profile == "" {
+		fixedWidthLayout, err := getFixedWidthLayoutByExtension(fixedWidthLayoutRepository, fileName)
+		if err != nil || fixedWidthLayout != nil {
+			return &uploadFormat{fixedWidthLayout: fixedWidthLayout}, err
+		}
+	}
+
+	uploadProfile, err := getUploadProfile(uploadProfileRepository, options.Profile)
+	if err != nil {
+		return nil, err
+	}
+	return &uploadFormat{uploadProfile: uploadProfile}, nil
+}
+
+func getFixedWidthLayoutByExtension(fixedWidthLayoutRepository bankSlipEntities.FixedWidthLayoutRepository, fileName string) (*bankSlipEntities.FixedWidthLayout, error) {
+	extension := bankSlipEntities.NormalizeFileExtension(filepath.Ext(fileName))
+	if extension == "" || extension == ".csv" {
+		return nil, nil
+	}
+	fixedWidthLayout, err := fixedWidthLayoutRepository.GetByExtension(extension)
+	if errors.Is(err, bankSlipEntities.ErrFixedWidthLayoutNotFound) {
+		return nil, nil
+	}
+	return fixedWidthLayout, err
+}
+
+func (f *uploadFormat) String() string {
+	if f.fixedWidthLayout != nil {
+		return "fixed-width layout " + f.fixedWidthLayout.Name
+	}
+	return "profile " + f.uploadProfile.Name
+}
+
+// open returns the rows of the content with the layout they are read with.
+// The header of a CSV file is read and returned, fixed-width files have none.
+func (f *uploadFormat) open(content io.Reader, sample string, dialect *uploadDialect, bufferSize int) (recordReader, string, *bankSlipEntities.BankSlipRowLayout, error) {
+	if f.fixedWidthLayout != nil {
+		records := &fixedWidthRecordReader{
+			lines:  handler.NewLineRecordReader(content, bufferSize),
+			layout: f.fixedWidthLayout,
+		}
+		return records, "", f.fixedWidthLayout.RowLayout(), nil
+	}
+
+	records := handler.NewCSVRecordReader(content, bufferSize)
+
+	header, err := records.Next()
+	if err != nil && err != io.EOF {
+		return nil, "", nil, err
+	}
+	if header == "" {
+		return nil, "", nil, ErrHeaderNotFound
+	}
+
+	layout, err := resolveHeader(f.uploadProfile, header, dialect.delimiter)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	dialect.applyTo(layout, sample)
+	return records, header, layout, nil
+}
+
+// fixedWidthRecordReader returns headers, trailers and any other record that
+// is not a bank slip as blank lines, so they are neither published nor
+// counted but the lines of the records after them are kept.
+type fixedWidthRecordReader struct {
+	lines  *handler.LineRecordReader
+	layout *bankSlipEntities.FixedWidthLayout
+}
+
+func (r *fixedWidthRecordReader) Next() (string, error) {
+	record, err := r.lines.Next()
+	if err != nil || !r.layout.IsDetail(record) {
+		return "", err
+	}
+	return record, nil
+}
+
+func (r *fixedWidthRecordReader) Line() int {
+	return r.lines.Line()
+}
@@ -0,0 +1,92 @@
+package bank_slip
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/calendar"
+)
+
+type ValidateUploadServiceInterface interface {
+	Execute(file multipart.File, fileHeader *multipart.FileHeader, options bankSlipEntities.UploadOptions) (*bankSlipEntities.UploadValidationReport, error)
+}
+
+// ValidateUploadService runs the header and row parsing of the upload pipeline
+// over a file without storing it, inserting rows or publishing messages.
+type ValidateUploadService struct {
+	uploadProfileRepository    bankSlipEntities.UploadProfileRepository
+	fixedWidthLayoutRepository bankSlipEntities.FixedWidthLayoutRepository
+	businessDays               *calendar.Calendar
+	bufferSize                 int
+	maxErrors                  int
+}
+
+func NewValidateUploadService(
+	uploadProfileRepo bankSlipEntities.UploadProfileRepository,
+	fixedWidthLayoutRepo bankSlipEntities.FixedWidthLayoutRepository,
+	businessDays *calendar.Calendar,
+	bufferSize int,
+	maxErrors int,
+) *ValidateUploadService {
+	return &ValidateUploadService{
+		uploadProfileRepository:    uploadProfileRepo,
+		fixedWidthLayoutRepository: fixedWidthLayoutRepo,
+		businessDays:               businessDays,
+		bufferSize:                 bufferSize,
+		maxErrors:                  maxErrors,
+	}
+}
+
+func (s *ValidateUploadService) Execute(file multipart.File, fileHeader *multipart.FileHeader, options bankSlipEntities.UploadOptions) (*bankSlipEntities.UploadValidationReport, error) {
+	dialect, err := newUploadDialect(options)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := getUploadFormat(s.uploadProfileRepository, s.fixedWidthLayoutRepository, options, fileHeader.Filename)
+	if err != nil {
+		log.Printf("Error getting upload format of %s: %v", fileHeader.Filename, err)
+		return nil, err
+	}
+
+	// Uploads are validated without their beneficiary, only the charge policy
+	// the options override is checked against the rows.
+	chargePolicy, err := options.ChargePolicy()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUploadOptions, err.Error())
+	}
+
+	content, sample, err := dialect.open(file)
+	if err != nil {
+		return nil, err
+	}
+	records, _, layout, err := format.open(content, sample, dialect, s.bufferSize)
+	if err != nil {
+		return nil, err
+	}
+	layout.ChargePolicy = chargePolicy
+	layout.BusinessDays = s.businessDays
+
+	report := bankSlipEntities.NewUploadValidationReport(fileHeader.Filename, s.maxErrors)
+	for {
+		record, err := records.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if record == "" {
+			continue
+		}
+
+		bankSlip, err := bankSlipEntities.NewBankSlipFromLayoutRow(fileHeader.Filename, record, layout)
+		report.AddRow(records.Line(), bankSlip, err)
+	}
+
+	log.Printf("Validated file %s: %d of %d rows are valid", fileHeader.Filename, report.ValidRows, report.TotalRows)
+	return report, nil
+}
@@ -0,0 +1,131 @@
+package bank_slip
+
+import (
+	"testing"
+
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	bankSlipMocks "performatic-file-processor/internal/bank_slip/mocks"
+	"performatic-file-processor/internal/calendar"
+	sharedMocks "performatic-file-processor/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSuitValidateUploadService struct {
+	suite.Suite
+	mockUploadProfileRepo    *bankSlipMocks.UploadProfileRepositoryMock
+	mockFixedWidthLayoutRepo *bankSlipMocks.FixedWidthLayoutRepositoryMock
+	service                  *ValidateUploadService
+}
+
+func (testSuit *TestSuitValidateUploadService) SetupTest() {
+	testSuit.mockUploadProfileRepo = new(bankSlipMocks.UploadProfileRepositoryMock)
+	testSuit.mockFixedWidthLayoutRepo = new(bankSlipMocks.FixedWidthLayoutRepositoryMock)
+	testSuit.mockUploadProfileRepo.On("GetByName", bankSlipEntities.DefaultUploadProfileName).Return(bankSlipEntities.DefaultUploadProfile(), nil).Maybe()
+	testSuit.mockFixedWidthLayoutRepo.On("GetByExtension", mock.Anything).Return(nil, bankSlipEntities.ErrFixedWidthLayoutNotFound).Maybe()
+
+	testSuit.service = NewValidateUploadService(testSuit.mockUploadProfileRepo, testSuit.mockFixedWidthLayoutRepo, calendar.New(), 4096, 2)
+}
+
+func TestValidateUploadService(t *testing.T) {
+	suite.Run(t, new(TestSuitValidateUploadService))
+}
+
+func (suit *TestSuitValidateUploadService) TestValidateUploadService_ShouldReportRowsWithLineNumbers() {
+	fileContent := []byte(testHeader + "\n" +
+		"John Doe,12345678909,john.doe@example.com,10.00,2023-12-31,debt1\n" +
+		"Mary Doe,abc,mary.doe@example.com,10.00,2023-12-31,debt2\n" +
+		"\n" +
+		"Jane Doe,11144477735,jane.doe@example.com,10.00,2023-12-31,debt1\n" +
+		"Jim Doe,39053344705,jim.doe@example.com,abc,2023-12-31,debt3\n" +
+		"Ann Doe,93541134780,ann.doe@example.com,10.00,2023-12-31,debt4\n")
+	file, fileHeader, err := sharedMocks.CreateMultipartFileMock("boletos.csv", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	report, err := suit.service.Execute(file, fileHeader, bankSlipEntities.UploadOptions{})
+	assert.NoError(suit.T(), err)
+
+	assert.Equal(suit.T(), "boletos.csv", report.FileName)
+	assert.Equal(suit.T(), 5, report.TotalRows)
+	assert.Equal(suit.T(), 2, report.ValidRows)
+	assert.Equal(suit.T(), map[bankSlipEntities.RejectionCode]int{
+		bankSlipEntities.RejectionCodeInvalidGovernmentId: 1,
+		bankSlipEntities.RejectionCodeDuplicateDebtId:     1,
+		bankSlipEntities.RejectionCodeInvalidAmount:       1,
+	}, report.ErrorsByCode)
+	assert.Len(suit.T(), report.Errors, 2)
+	assert.Equal(suit.T(), 3, report.Errors[0].LineNumber)
+	assert.Equal(suit.T(), bankSlipEntities.RejectionCodeInvalidGovernmentId, report.Errors[0].ErrorCode)
+	assert.Equal(suit.T(), 5, report.Errors[1].LineNumber)
+	assert.Equal(suit.T(), bankSlipEntities.RejectionCodeDuplicateDebtId, report.Errors[1].ErrorCode)
+	assert.Equal(suit.T(), []string{"debt1"}, report.DuplicateDebtIds)
+}
+
+func (suit *TestSuitValidateUploadService) TestValidateUploadService_ShouldUseProfileAndDialect() {
+	profile, _ := bankSlipEntities.NewUploadProfile("br", map[bankSlipEntities.BankSlipField][]string{
+		bankSlipEntities.BankSlipFieldUserName:     {"nome"},
+		bankSlipEntities.BankSlipFieldGovernmentId: {"cpf"},
+		bankSlipEntities.BankSlipFieldUserEmail:    {"email"},
+		bankSlipEntities.BankSlipFieldDebtAmount:   {"valor"},
+		bankSlipEntities.BankSlipFieldDebtDueDate:  {"vencimento"},
+		bankSlipEntities.BankSlipFieldDebtId:       {"id_divida"},
+	}, "DD/MM/YYYY", ",", "")
+	suit.mockUploadProfileRepo.On("GetByName", "br").Return(profile, nil).Once()
+
+	fileContent := []byte("nome;cpf;email;valor;vencimento;id_divida\nJohn Doe;12345678909;john.doe@example.com;1.000,50;31/12/2023;debt1\n")
+	file, fileHeader, err := sharedMocks.CreateMultipartFileMock("boletos.csv", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	report, err := suit.service.Execute(file, fileHeader, bankSlipEntities.UploadOptions{Profile: "br"})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), 1, report.TotalRows)
+	assert.Equal(suit.T(), 1, report.ValidRows)
+}
+
+func (suit *TestSuitValidateUploadService) TestValidateUploadService_ShouldReportDueDatesProfileRejects() {
+	profile, _ := bankSlipEntities.NewUploadProfile("strict", bankSlipEntities.DefaultUploadProfile().Columns, "YYYY-MM-DD", ".", "REJECT")
+	suit.mockUploadProfileRepo.On("GetByName", "strict").Return(profile, nil).Once()
+
+	fileContent := []byte("name,governmentId,email,debtAmount,debtDueDate,debtId\n" +
+		"John Doe,12345678909,john.doe@example.com,1000.50,2026-02-17,debt1\n" +
+		"John Doe,12345678909,john.doe@example.com,1000.50,2026-02-18,debt2\n")
+	file, fileHeader, err := sharedMocks.CreateMultipartFileMock("boletos.csv", fileContent)
+	if err != nil {
+		panic(err)
+	}
+
+	report, err := suit.service.Execute(file, fileHeader, bankSlipEntities.UploadOptions{Profile: "strict"})
+	assert.NoError(suit.T(), err)
+	assert.Equal(suit.T(), 2, report.TotalRows)
+	assert.Equal(suit.T(), 1, report.ValidRows)
+	assert.Equal(suit.T(), 2, report.Errors[0].LineNumber)
+	assert.Equal(suit.T(), bankSlipEntities.RejectionCodeDueDateNotBusinessDay, report.Errors[0].ErrorCode)
+}
+
+func (suit *TestSuitValidateUploadService) TestValidateUploadService_ShouldFailWhenHeaderIsMissing() {
+	file, fileHeader, err := sharedMocks.CreateMultipartFileMock("boletos.csv", []byte{})
+	if err != nil {
+		panic(err)
+	}
+
+	report, err := suit.service.Execute(file, fileHeader, bankSlipEntities.UploadOptions{})
+	assert.Nil(suit.T(), report)
+	assert.ErrorIs(suit.T(), err, ErrHeaderNotFound)
+}
+
+func (suit *TestSuitValidateUploadService) TestValidateUploadService_ShouldFailWhenHeaderDoesNotMatchProfile() {
+	file, fileHeader, err := sharedMocks.CreateMultipartFileMock("boletos.csv", []byte("name,email\nJohn,john@example.com\n"))
+	if err != nil {
+		panic(err)
+	}
+
+	report, err := suit.service.Execute(file, fileHeader, bankSlipEntities.UploadOptions{})
+	assert.Nil(suit.T(), report)
+	assert.ErrorIs(suit.T(), err, bankSlipEntities.ErrInvalidHeader)
+}
@@ -0,0 +1,145 @@
+package boleto
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CurrencyReal is the currency code of the barcode for amounts in reais.
+const CurrencyReal = "9"
+
+const (
+	BarcodeLength       = 44
+	DigitableLineLength = 47
+	FreeFieldLength     = 25
+
+	maxAmountInCents = 9999999999
+)
+
+var (
+	ErrInvalidBankCode   = errors.New("bank code must have 3 digits")
+	ErrInvalidFreeField  = errors.New("free field must have 25 digits")
+	ErrDueDateOutOfRange = errors.New("due date is out of the due date factor range")
+	ErrAmountOutOfRange  = errors.New("amount does not fit in the barcode")
+)
+
+// dueDateFactorBase is the day before factor 1, the factor counts the days
+// since it.
+var dueDateFactorBase = time.Date(1997, time.October, 7, 0, 0, 0, 0, time.UTC)
+
+const (
+	minDueDateFactor = 1000
+	maxDueDateFactor = 9999
+)
+
+// Boleto keeps the barcode and the typeable line ("linha digitável") of a bank
+// slip, both only with digits.
+type Boleto struct {
+	Barcode       string
+	DigitableLine string
+}
+
+// New builds the FEBRABAN barcode: bank code, currency, check digit, due date
+// factor, amount and the bank defined free field.
+func New(bankCode string, dueDate time.Time, amountInCents int64, freeField string) (*Boleto, error) {
+	if len(bankCode) != 3 || !isDigits(bankCode) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidBankCode, bankCode)
+	}
+	if len(freeField) != FreeFieldLength || !isDigits(freeField) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFreeField, freeField)
+	}
+	factor, err := DueDateFactor(dueDate)
+	if err != nil {
+		return nil, err
+	}
+	if amountInCents < 0 || amountInCents > maxAmountInCents {
+		return nil, fmt.Errorf("%w: %d", ErrAmountOutOfRange, amountInCents)
+	}
+
+	withoutCheckDigit := bankCode + CurrencyReal + fmt.Sprintf("%04d%010d", factor, amountInCents) + freeField
+	checkDigit := Mod11(withoutCheckDigit)
+	barcode := withoutCheckDigit[:4] + strconv.Itoa(checkDigit) + withoutCheckDigit[4:]
+
+	return &Boleto{Barcode: barcode, DigitableLine: DigitableLine(barcode)}, nil
+}
+
+// DueDateFactor returns the days between the due date and 1997-10-07. The
+// factor only has four digits, so after reaching 9999 on 2025-02-21 it went
+// back to 1000 on 2025-02-22, and it does so every 9000 days.
+func DueDateFactor(dueDate time.Time) (int, error) {
+	year, month, day := dueDate.Date()
+	days := int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Sub(dueDateFactorBase).Hours() / 24)
+	if days < minDueDateFactor {
+		return 0, fmt.Errorf("%w: %s", ErrDueDateOutOfRange, dueDate.Format(time.DateOnly))
+	}
+	return (days-minDueDateFactor)%(maxDueDateFactor-minDueDateFactor+1) + minDueDateFactor, nil
+}
+
+// DigitableLine rearranges a barcode in the five fields of the typeable line,
+// the first three with their own modulo 10 check digit.
+func DigitableLine(barcode string) string {
+	field1 := barcode[0:4] + barcode[19:24]
+	field2 := barcode[24:34]
+	field3 := barcode[34:44]
+	return field1 + strconv.Itoa(Mod10(field1)) +
+		field2 + strconv.Itoa(Mod10(field2)) +
+		field3 + strconv.Itoa(Mod10(field3)) +
+		barcode[4:5] +
+		barcode[5:19]
+}
+
+// FormatDigitableLine writes the typeable line the way it is printed on the
+// bank slip, e.g. 00190.50095 40144.816069 06809.350314 3 37370000000100.
+func FormatDigitableLine(digitableLine string) string {
+	if len(digitableLine) != DigitableLineLength {
+		return digitableLine
+	}
+	return digitableLine[0:5] + "." + digitableLine[5:10] + " " +
+		digitableLine[10:15] + "." + digitableLine[15:21] + " " +
+		digitableLine[21:26] + "." + digitableLine[26:32] + " " +
+		digitableLine[32:33] + " " +
+		digitableLine[33:]
+}
+
+// Mod10 weights the digits 2, 1, 2, ... from the right, summing the digits of
+// each product.
+func Mod10(digits string) int {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		product := int(digits[i]-'0') * weight
+		sum += product/10 + product%10
+		weight = 3 - weight
+	}
+	return (10 - sum%10) % 10
+}
+
+// Mod11 weights the digits 2 to 9 from the right. The barcode check digit can't
+// be 0, so the results 0, 10 and 11 become 1.
+func Mod11(digits string) int {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	checkDigit := 11 - sum%11
+	if checkDigit == 0 || checkDigit == 10 || checkDigit == 11 {
+		return 1
+	}
+	return checkDigit
+}
+
+func isDigits(value string) bool {
+	for i := range len(value) {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
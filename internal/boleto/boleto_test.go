@@ -0,0 +1,126 @@
+package boleto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Example of the Banco do Brasil barcode specification.
+func TestNew(t *testing.T) {
+	boleto, err := New("001", time.Date(2007, 12, 31, 0, 0, 0, 0, time.UTC), 100, "0500940144816060680935031")
+	assert.NoError(t, err)
+	assert.Equal(t, "00193373700000001000500940144816060680935031", boleto.Barcode)
+	assert.Equal(t, "00190500954014481606906809350314337370000000100", boleto.DigitableLine)
+	assert.Equal(t, "00190.50095 40144.816069 06809.350314 3 37370000000100", FormatDigitableLine(boleto.DigitableLine))
+}
+
+func TestNew_ShouldRejectInvalidValues(t *testing.T) {
+	dueDate := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	_, err := New("01", dueDate, 100, "0500940144816060680935031")
+	assert.ErrorIs(t, err, ErrInvalidBankCode)
+	_, err = New("001", dueDate, 100, "050094014481606068093503")
+	assert.ErrorIs(t, err, ErrInvalidFreeField)
+	_, err = New("001", dueDate, 100, "05009401448160606809350AB")
+	assert.ErrorIs(t, err, ErrInvalidFreeField)
+	_, err = New("001", dueDate, 10000000000, "0500940144816060680935031")
+	assert.ErrorIs(t, err, ErrAmountOutOfRange)
+	_, err = New("001", time.Date(2000, 7, 2, 0, 0, 0, 0, time.UTC), 100, "0500940144816060680935031")
+	assert.ErrorIs(t, err, ErrDueDateOutOfRange)
+}
+
+func TestDueDateFactor(t *testing.T) {
+	cases := map[string]int{
+		"2000-07-03": 1000,
+		"2007-12-31": 3737,
+		"2024-01-19": 9600,
+		"2025-02-21": 9999,
+		"2025-02-22": 1000,
+		"2025-12-31": 1312,
+		"2049-10-13": 9999,
+		"2049-10-14": 1000,
+	}
+	for value, expected := range cases {
+		dueDate, _ := time.Parse(time.DateOnly, value)
+		factor, err := DueDateFactor(dueDate)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, factor, value)
+	}
+}
+
+func TestDueDateFactor_ShouldIgnoreTimeAndLocation(t *testing.T) {
+	saoPaulo := time.FixedZone("America/Sao_Paulo", -3*60*60)
+
+	factor, err := DueDateFactor(time.Date(2025, 2, 22, 23, 30, 0, 0, saoPaulo))
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, factor)
+}
+
+func TestMod10(t *testing.T) {
+	assert.Equal(t, 5, Mod10("001905009"))
+	assert.Equal(t, 9, Mod10("4014481606"))
+	assert.Equal(t, 4, Mod10("0680935031"))
+	assert.Equal(t, 0, Mod10("0000000000"))
+}
+
+func TestMod11(t *testing.T) {
+	assert.Equal(t, 3, Mod11("0019373700000001000500940144816060680935031"))
+	assert.Equal(t, 1, Mod11("0000000000000000000000000000000000000000000"))
+}
+
+func TestIssuer_Issue(t *testing.T) {
+	issuer, err := NewIssuer("237", "1234", "9", "12345")
+	assert.NoError(t, err)
+	assert.Equal(t, Issuer{BankCode: "237", Agency: "1234", Wallet: "09", Account: "0012345"}, issuer)
+
+	boleto, err := issuer.Issue(42, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), 100050)
+	assert.NoError(t, err)
+	assert.Equal(t, "23792131200001000501234090000000004200123450", boleto.Barcode)
+	assert.Equal(t, "23791234059000000000142001234501213120000100050", boleto.DigitableLine)
+}
+
+func TestIssuer_ShouldRejectInvalidValues(t *testing.T) {
+	_, err := NewIssuer("2370", "1234", "09", "12345")
+	assert.ErrorIs(t, err, ErrInvalidIssuer)
+	_, err = NewIssuer("237", "12345", "09", "12345")
+	assert.ErrorIs(t, err, ErrInvalidIssuer)
+	_, err = NewIssuer("237", "1234", "", "12345")
+	assert.ErrorIs(t, err, ErrInvalidIssuer)
+	_, err = NewIssuer("237", "1234", "09", "12A45")
+	assert.ErrorIs(t, err, ErrInvalidIssuer)
+	_, err = NewIssuer("001", "1234", "09", "12345")
+	assert.ErrorIs(t, err, ErrInvalidIssuer)
+	assert.ErrorIs(t, err, ErrUnsupportedBank)
+
+	issuer, _ := NewIssuer("237", "1234", "09", "12345")
+	_, err = issuer.Issue(0, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), 100)
+	assert.ErrorIs(t, err, ErrOurNumberOutOfRange)
+	_, err = issuer.Issue(100000000000, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), 100)
+	assert.ErrorIs(t, err, ErrOurNumberOutOfRange)
+}
+
+func TestIssuer_FormatOurNumberShouldEndWithCheckDigit(t *testing.T) {
+	cases := map[string]struct {
+		wallet    string
+		ourNumber int64
+	}{
+		"19/00000000002-8": {"19", 2},
+		"09/00000000042-9": {"09", 42},
+		"09/00000000002-P": {"09", 2},
+		"09/00000000007-0": {"09", 7},
+	}
+	for formatted, c := range cases {
+		issuer, _ := NewIssuer("237", "1234", c.wallet, "12345")
+		assert.Equal(t, formatted, issuer.FormatOurNumber(c.ourNumber))
+	}
+}
+
+func TestIssuer_ShouldNotIssueBoletosOfBankWithoutFreeFieldLayout(t *testing.T) {
+	issuer := Issuer{BankCode: "001", Agency: "1234", Wallet: "17", Account: "0012345"}
+
+	_, err := issuer.Issue(42, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), 100)
+	assert.ErrorIs(t, err, ErrUnsupportedBank)
+	assert.Equal(t, "42", issuer.FormatOurNumber(42))
+}
@@ -0,0 +1,46 @@
+package boleto
+
+import "fmt"
+
+const bradescoMaxOurNumber = 99999999999
+
+// Bradesco lays out the free field as agency (4), wallet (2), our number (11),
+// account (7) and a zero.
+type Bradesco struct{}
+
+func (Bradesco) FreeField(issuer Issuer, ourNumber int64) (string, error) {
+	if ourNumber <= 0 || ourNumber > bradescoMaxOurNumber {
+		return "", fmt.Errorf("%w: %d", ErrOurNumberOutOfRange, ourNumber)
+	}
+	return fmt.Sprintf("%s%s%011d%s0", issuer.Agency, issuer.Wallet, ourNumber, issuer.Account), nil
+}
+
+// FormatOurNumber writes the wallet, the our number and its check digit, as
+// 09/00000000042-9.
+func (Bradesco) FormatOurNumber(issuer Issuer, ourNumber int64) string {
+	number := fmt.Sprintf("%011d", ourNumber)
+	return fmt.Sprintf("%s/%s-%s", issuer.Wallet, number, bradescoCheckDigit(issuer.Wallet+number))
+}
+
+// bradescoCheckDigit is the modulo 11 of the wallet and the our number,
+// weighted 2 to 7 from the right. A remainder of 1 is written P and one of 0
+// is 0.
+func bradescoCheckDigit(digits string) string {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 7 {
+			weight = 2
+		}
+	}
+	switch remainder := sum % 11; remainder {
+	case 0:
+		return "0"
+	case 1:
+		return "P"
+	default:
+		return fmt.Sprint(11 - remainder)
+	}
+}
@@ -0,0 +1,145 @@
+package boleto
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// Beneficiary is who receives the payment of the bank slips.
+type Beneficiary struct {
+	Name     string
+	Document string
+}
+
+// Document has everything printed on a boleto, already formatted for reading.
+type Document struct {
+	BankCode         string
+	Beneficiary      Beneficiary
+	AgencyAndAccount string
+	Wallet           string
+	OurNumber        string
+	DocumentNumber   string
+	PayerName        string
+	PayerDocument    string
+	Amount           string
+	DueDate          time.Time
+	DigitableLine    string
+	Barcode          string
+	// Instructions are the lines for the bank teller, as the late fee and
+	// interest charged after the due date.
+	Instructions []string
+}
+
+// defaultInstruction is printed after the instructions of every boleto.
+const defaultInstruction = "Não receber após 60 dias do vencimento."
+
+const (
+	pdfMargin       = 30.0
+	pdfContentWidth = pdfPageWidth - 2*pdfMargin
+	// FEBRABAN asks for a 103mm x 13mm barcode, 405 narrow modules at 1:3.
+	barcodeNarrowBar = 0.72
+	barcodeWideBar   = 3 * barcodeNarrowBar
+	barcodeHeight    = 36.85
+)
+
+// WritePDF renders the payer receipt and the "ficha de compensação" with the
+// Interleaved 2 of 5 barcode drawn as vector bars.
+func (d Document) WritePDF(w io.Writer) error {
+	bars, err := Interleaved2of5(d.Barcode, barcodeNarrowBar, barcodeWideBar)
+	if err != nil {
+		return err
+	}
+
+	page := &pdfPage{}
+	dueDate := d.DueDate.Format("02/01/2006")
+	beneficiary := d.Beneficiary.Name
+	if d.Beneficiary.Document != "" {
+		beneficiary += " - " + d.Beneficiary.Document
+	}
+	payer := fmt.Sprintf("%s - CPF/CNPJ: %s", d.PayerName, d.PayerDocument)
+	digitableLine := FormatDigitableLine(d.DigitableLine)
+
+	// Payer receipt.
+	top := pdfPageHeight - pdfMargin
+	d.header(page, top, "Recibo do Pagador")
+	top -= 20
+	page.box(pdfMargin, top, 335, 24, "Beneficiário", beneficiary)
+	page.box(pdfMargin+335, top, 100, 24, "Agência/Código do beneficiário", d.AgencyAndAccount)
+	page.box(pdfMargin+435, top, 100, 24, "Vencimento", dueDate)
+	top -= 24
+	page.box(pdfMargin, top, 335, 24, "Pagador", payer)
+	page.box(pdfMargin+335, top, 100, 24, "Nosso número", d.OurNumber)
+	page.box(pdfMargin+435, top, 100, 24, "(=) Valor do documento", d.Amount)
+	top -= 24
+	page.box(pdfMargin, top, 335, 24, "Número do documento", d.DocumentNumber)
+	page.box(pdfMargin+335, top, 200, 24, "Linha digitável", digitableLine)
+	top -= 24
+	page.text(pdfMargin+pdfContentWidth-90, top-10, 6, fontRegular, "Autenticação mecânica")
+
+	top -= 40
+	page.dashedLine(pdfMargin, top, pdfMargin+pdfContentWidth, top)
+	page.text(pdfMargin+pdfContentWidth-90, top+3, 6, fontRegular, "Corte na linha pontilhada")
+
+	// Ficha de compensação.
+	top -= 30
+	d.header(page, top, digitableLine)
+	top -= 20
+	page.box(pdfMargin, top, 400, 24, "Local de pagamento", "Pagável em qualquer banco até o vencimento")
+	page.box(pdfMargin+400, top, 135, 24, "Vencimento", dueDate)
+	top -= 24
+	page.box(pdfMargin, top, 400, 24, "Beneficiário", beneficiary)
+	page.box(pdfMargin+400, top, 135, 24, "Agência/Código do beneficiário", d.AgencyAndAccount)
+	top -= 24
+	page.box(pdfMargin, top, 200, 24, "Número do documento", d.DocumentNumber)
+	page.box(pdfMargin+200, top, 100, 24, "Carteira", d.Wallet)
+	page.box(pdfMargin+300, top, 100, 24, "Espécie", "R$")
+	page.box(pdfMargin+400, top, 135, 24, "Nosso número", d.OurNumber)
+	top -= 24
+	page.box(pdfMargin, top, 400, 72, "Instruções (texto de responsabilidade do beneficiário)", "")
+	for i, instruction := range append(slices.Clone(d.Instructions), defaultInstruction) {
+		page.text(pdfMargin+2, top-18-float64(i)*11, 8, fontRegular, instruction)
+	}
+	page.box(pdfMargin+400, top, 135, 24, "(=) Valor do documento", d.Amount)
+	page.box(pdfMargin+400, top-24, 135, 24, "(-) Desconto / Abatimento", "")
+	page.box(pdfMargin+400, top-48, 135, 24, "(+) Mora / Multa", "")
+	top -= 72
+	page.box(pdfMargin, top, pdfContentWidth, 30, "Pagador", payer)
+	top -= 30
+
+	x := pdfMargin
+	top -= 10
+	for i, width := range bars {
+		if i%2 == 0 {
+			page.rect(x, top-barcodeHeight, width, barcodeHeight)
+		}
+		x += width
+	}
+	page.text(pdfMargin+pdfContentWidth-150, top-10, 6, fontRegular, "Autenticação mecânica - Ficha de Compensação")
+
+	return writePDF(w, []*pdfPage{page})
+}
+
+func (d Document) header(page *pdfPage, top float64, title string) {
+	page.text(pdfMargin, top-16, 14, fontBold, d.BankCode)
+	page.line(pdfMargin+40, top-20, pdfMargin+40, top, 1)
+	page.text(pdfMargin+50, top-16, 11, fontBold, title)
+	page.line(pdfMargin, top-20, pdfMargin+pdfContentWidth, top-20, 1)
+}
+
+// box draws a bordered field with its label on the top and the value on the
+// bottom.
+func (p *pdfPage) box(x, top, width, height float64, label, value string) {
+	fmt.Fprintf(&p.content, "0.5 w %.2f %.2f %.2f %.2f re S\n", x, top-height, width, height)
+	p.text(x+2, top-7, 6, fontRegular, label)
+	if value != "" {
+		p.text(x+2, top-height+5, 9, fontRegular, value)
+	}
+}
+
+func (p *pdfPage) dashedLine(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "[3 3] 0 d ")
+	p.line(x1, y1, x2, y2, 0.5)
+	fmt.Fprintf(&p.content, "[] 0 d\n")
+}
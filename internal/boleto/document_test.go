@@ -0,0 +1,94 @@
+package boleto
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDocument() Document {
+	return Document{
+		BankCode:         "237",
+		Beneficiary:      Beneficiary{Name: "Cobranças Ação Ltda", Document: "11.222.333/0001-81"},
+		AgencyAndAccount: "1234/0012345",
+		Wallet:           "09",
+		OurNumber:        "09/00000000042",
+		DocumentNumber:   "ea23f2ca-663a-4266-a742-9da4c9f4fcb3",
+		PayerName:        "João (Santos)",
+		PayerDocument:    "529.982.247-25",
+		Amount:           "R$ 1.000,50",
+		DueDate:          time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		DigitableLine:    "23791234059000000000142001234501213120000100050",
+		Barcode:          "23792131200001000501234090000000004200123450",
+	}
+}
+
+func TestDocument_WritePDF(t *testing.T) {
+	var pdf bytes.Buffer
+	err := newTestDocument().WritePDF(&pdf)
+	assert.NoError(t, err)
+
+	content := pdf.String()
+	assert.True(t, strings.HasPrefix(content, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(content, "%%EOF\n"))
+	assert.Contains(t, content, "(23791.23405 90000.000001 42001.234501 2 13120000100050) Tj")
+	assert.Contains(t, content, "(R$ 1.000,50) Tj")
+	assert.Contains(t, content, "(31/12/2025) Tj")
+	// Accents are written in WinAnsiEncoding and parentheses are escaped.
+	assert.Contains(t, content, "(Jo\xe3o \\(Santos\\) - CPF/CNPJ: 529.982.247-25) Tj")
+	assert.Contains(t, content, "(Cobran\xe7as A\xe7\xe3o Ltda - 11.222.333/0001-81) Tj")
+}
+
+func TestDocument_WritePDF_ShouldWriteInstructionsBeforeDefaultOne(t *testing.T) {
+	document := newTestDocument()
+	document.Instructions = []string{"Após o vencimento, cobrar multa de 2,00%."}
+
+	var pdf bytes.Buffer
+	assert.NoError(t, document.WritePDF(&pdf))
+
+	content := pdf.String()
+	instruction := strings.Index(content, "(Ap\xf3s o vencimento, cobrar multa de 2,00%.) Tj")
+	defaultInstruction := strings.Index(content, "(N\xe3o receber ap\xf3s 60 dias do vencimento.) Tj")
+	assert.NotEqual(t, -1, instruction)
+	assert.Greater(t, defaultInstruction, instruction)
+}
+
+func TestDocument_WritePDF_ShouldPointXrefToEveryObject(t *testing.T) {
+	var pdf bytes.Buffer
+	assert.NoError(t, newTestDocument().WritePDF(&pdf))
+	content := pdf.String()
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(content)
+	assert.NotNil(t, startxref)
+	xrefOffset, _ := strconv.Atoi(startxref[1])
+	assert.True(t, strings.HasPrefix(content[xrefOffset:], "xref\n0 7\n"))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(content[xrefOffset:], -1)
+	assert.Len(t, entries, 6)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		assert.True(t, strings.HasPrefix(content[offset:], fmt.Sprintf("%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+}
+
+func TestDocument_WritePDF_ShouldDrawOneRectanglePerBar(t *testing.T) {
+	var pdf bytes.Buffer
+	assert.NoError(t, newTestDocument().WritePDF(&pdf))
+
+	// Start (2 bars), 22 digit pairs (5 bars each) and stop (2 bars).
+	assert.Equal(t, 2+22*5+2, strings.Count(pdf.String(), " re f\n"))
+}
+
+func TestDocument_WritePDF_ShouldRejectInvalidBarcode(t *testing.T) {
+	document := newTestDocument()
+	document.Barcode = "123"
+
+	err := document.WritePDF(&bytes.Buffer{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package boleto
+
+import "fmt"
+
+// Interleaved 2 of 5 encodes digit pairs, the first digit in the bars and the
+// second in the spaces between them. N is a narrow element and W a wide one.
+var interleaved2of5Patterns = [10]string{
+	"NNWWN", "WNNNW", "NWNNW", "WWNNN", "NNWNW",
+	"WNWNN", "NWWNN", "NNNWW", "WNNWN", "NWNWN",
+}
+
+const (
+	interleaved2of5Start = "NNNN"
+	interleaved2of5Stop  = "WNN"
+)
+
+// Interleaved2of5 returns the width of each element of the barcode, starting
+// with a bar and alternating with spaces. FEBRABAN uses a wide element three
+// times as wide as the narrow one.
+func Interleaved2of5(digits string, narrow, wide float64) ([]float64, error) {
+	if len(digits)%2 != 0 || !isDigits(digits) {
+		return nil, fmt.Errorf("interleaved 2 of 5 needs an even number of digits: %q", digits)
+	}
+
+	pattern := interleaved2of5Start
+	for i := 0; i < len(digits); i += 2 {
+		bars := interleaved2of5Patterns[digits[i]-'0']
+		spaces := interleaved2of5Patterns[digits[i+1]-'0']
+		for j := range len(bars) {
+			pattern += string(bars[j]) + string(spaces[j])
+		}
+	}
+	pattern += interleaved2of5Stop
+
+	widths := make([]float64, len(pattern))
+	for i := range len(pattern) {
+		widths[i] = narrow
+		if pattern[i] == 'W' {
+			widths[i] = wide
+		}
+	}
+	return widths, nil
+}
@@ -0,0 +1,62 @@
+package boleto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterleaved2of5(t *testing.T) {
+	widths, err := Interleaved2of5("12", 1, 3)
+	assert.NoError(t, err)
+	// Start NNNN, 1 (WNNNW) in the bars and 2 (NWNNW) in the spaces, stop WNN.
+	assert.Equal(t, []float64{1, 1, 1, 1, 3, 1, 1, 3, 1, 1, 1, 1, 3, 3, 3, 1, 1}, widths)
+}
+
+func TestInterleaved2of5_ShouldDecodeBackToTheBarcode(t *testing.T) {
+	barcode := "23792131200001000501234090000000004200123450"
+	widths, err := Interleaved2of5(barcode, 1, 3)
+	assert.NoError(t, err)
+
+	total := 0.0
+	for _, width := range widths {
+		total += width
+	}
+	assert.Equal(t, 405.0, total)
+
+	decoded := ""
+	data := widths[4 : len(widths)-3]
+	for i := 0; i < len(data); i += 10 {
+		bars, spaces := "", ""
+		for j := 0; j < 10; j += 2 {
+			bars += element(data[i+j])
+			spaces += element(data[i+j+1])
+		}
+		decoded += digitOf(t, bars) + digitOf(t, spaces)
+	}
+	assert.Equal(t, barcode, decoded)
+}
+
+func TestInterleaved2of5_ShouldRejectOddOrNonNumericInput(t *testing.T) {
+	_, err := Interleaved2of5("123", 1, 3)
+	assert.Error(t, err)
+	_, err = Interleaved2of5("1A", 1, 3)
+	assert.Error(t, err)
+}
+
+func element(width float64) string {
+	if width > 1 {
+		return "W"
+	}
+	return "N"
+}
+
+func digitOf(t *testing.T, pattern string) string {
+	for digit, digitPattern := range interleaved2of5Patterns {
+		if digitPattern == pattern {
+			return string(rune('0' + digit))
+		}
+	}
+	t.Fatalf("unknown pattern %s", pattern)
+	return ""
+}
@@ -0,0 +1,118 @@
+package boleto
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidIssuer       = errors.New("invalid boleto issuer")
+	ErrUnsupportedBank     = errors.New("no boleto free field layout for the bank")
+	ErrOurNumberOutOfRange = errors.New("our number does not fit in the free field")
+)
+
+// FreeFieldLayout is how a bank lays out the free field of its boletos, and
+// prints the our number ("nosso número") with its check digit.
+type FreeFieldLayout interface {
+	FreeField(issuer Issuer, ourNumber int64) (string, error)
+	FormatOurNumber(issuer Issuer, ourNumber int64) string
+}
+
+var (
+	freeFieldLayoutsMutex sync.RWMutex
+	freeFieldLayouts      = map[string]FreeFieldLayout{
+		"237": Bradesco{},
+	}
+)
+
+// RegisterFreeField makes layout the one used to issue the boletos of the
+// bank, replacing any layout registered before.
+func RegisterFreeField(bankCode string, layout FreeFieldLayout) {
+	freeFieldLayoutsMutex.Lock()
+	defer freeFieldLayoutsMutex.Unlock()
+	freeFieldLayouts[bankCode] = layout
+}
+
+// FreeFieldLayoutFor returns the layout registered for the bank.
+func FreeFieldLayoutFor(bankCode string) (FreeFieldLayout, error) {
+	freeFieldLayoutsMutex.RLock()
+	defer freeFieldLayoutsMutex.RUnlock()
+	if layout, ok := freeFieldLayouts[bankCode]; ok {
+		return layout, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedBank, bankCode)
+}
+
+// Issuer is the account the bank slips are issued to. Its free field follows
+// the layout registered for its bank.
+type Issuer struct {
+	BankCode    string
+	Agency      string
+	Wallet      string
+	Account     string
+	Beneficiary Beneficiary
+}
+
+// NewIssuer validates the account and pads it with leading zeros. Banks
+// without a free field layout are rejected, their boletos couldn't be issued.
+func NewIssuer(bankCode, agency, wallet, account string) (Issuer, error) {
+	if len(bankCode) != 3 || !isDigits(bankCode) {
+		return Issuer{}, fmt.Errorf("%w: %w: %q", ErrInvalidIssuer, ErrInvalidBankCode, bankCode)
+	}
+	if _, err := FreeFieldLayoutFor(bankCode); err != nil {
+		return Issuer{}, fmt.Errorf("%w: %w", ErrInvalidIssuer, err)
+	}
+	agency, err := padDigits("agency", agency, 4)
+	if err != nil {
+		return Issuer{}, err
+	}
+	wallet, err = padDigits("wallet", wallet, 2)
+	if err != nil {
+		return Issuer{}, err
+	}
+	account, err = padDigits("account", account, 7)
+	if err != nil {
+		return Issuer{}, err
+	}
+	return Issuer{BankCode: bankCode, Agency: agency, Wallet: wallet, Account: account}, nil
+}
+
+func padDigits(name, value string, length int) (string, error) {
+	if len(value) == 0 || len(value) > length || !isDigits(value) {
+		return "", fmt.Errorf("%w: %s must have up to %d digits: %q", ErrInvalidIssuer, name, length, value)
+	}
+	return fmt.Sprintf("%0*s", length, value), nil
+}
+
+func (i Issuer) FreeField(ourNumber int64) (string, error) {
+	layout, err := FreeFieldLayoutFor(i.BankCode)
+	if err != nil {
+		return "", err
+	}
+	return layout.FreeField(i, ourNumber)
+}
+
+// FormatOurNumber writes the our number as printed on the boleto, only the
+// number for a bank without a free field layout.
+func (i Issuer) FormatOurNumber(ourNumber int64) string {
+	layout, err := FreeFieldLayoutFor(i.BankCode)
+	if err != nil {
+		return fmt.Sprintf("%d", ourNumber)
+	}
+	return layout.FormatOurNumber(i, ourNumber)
+}
+
+func (i Issuer) AgencyAndAccount() string {
+	return i.Agency + "/" + i.Account
+}
+
+// Issue builds the boleto of a bank slip with its our number.
+func (i Issuer) Issue(ourNumber int64, dueDate time.Time, amountInCents int64) (*Boleto, error) {
+	freeField, err := i.FreeField(ourNumber)
+	if err != nil {
+		return nil, err
+	}
+	return New(i.BankCode, dueDate, amountInCents, freeField)
+}
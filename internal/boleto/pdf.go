@@ -0,0 +1,118 @@
+package boleto
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// pdfPage keeps the drawing operators of an A4 page. Coordinates are in points
+// from the bottom left corner, as in PDF.
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+)
+
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+var pdfTextEscaper = strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", " ", "\n", " ")
+
+// text writes with the standard Helvetica fonts, which every PDF reader has,
+// so accents are converted to WinAnsiEncoding.
+func (p *pdfPage) text(x, y, size float64, font, value string) {
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfTextEscaper.Replace(toWinAnsi(value)))
+}
+
+func (p *pdfPage) rect(x, y, width, height float64) {
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f %.3f re f\n", x, y, width, height)
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+func toWinAnsi(value string) string {
+	encoded := make([]byte, 0, len(value))
+	for _, r := range value {
+		b, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		encoded = append(encoded, b)
+	}
+	return string(encoded)
+}
+
+// countingWriter tracks the byte offset of every object for the xref table.
+type countingWriter struct {
+	writer io.Writer
+	offset int
+	err    error
+}
+
+func (w *countingWriter) printf(format string, args ...any) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.writer, format, args...)
+	w.offset += n
+	w.err = err
+}
+
+// writePDF writes a PDF 1.4 document with one page per pdfPage.
+func writePDF(writer io.Writer, pages []*pdfPage) error {
+	w := &countingWriter{writer: writer}
+	// catalog, page tree, two fonts, then a page and its content per page.
+	objects := 4 + 2*len(pages)
+	offsets := make([]int, objects+1)
+
+	beginObject := func(id int) {
+		offsets[id] = w.offset
+		w.printf("%d 0 obj\n", id)
+	}
+
+	w.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	beginObject(1)
+	w.printf("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	beginObject(2)
+	w.printf("<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(pages))
+
+	beginObject(3)
+	w.printf("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>\nendobj\n")
+	beginObject(4)
+	w.printf("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>\nendobj\n")
+
+	for i, page := range pages {
+		pageId := 5 + 2*i
+		beginObject(pageId)
+		w.printf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>\nendobj\n",
+			pdfPageWidth, pdfPageHeight, fontRegular, fontBold, pageId+1)
+
+		beginObject(pageId + 1)
+		w.printf("<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", page.content.Len(), page.content.String())
+	}
+
+	xrefOffset := w.offset
+	w.printf("xref\n0 %d\n0000000000 65535 f \n", objects+1)
+	for id := 1; id <= objects; id++ {
+		w.printf("%010d 00000 n \n", offsets[id])
+	}
+	w.printf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", objects+1, xrefOffset)
+	return w.err
+}
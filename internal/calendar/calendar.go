@@ -0,0 +1,177 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidHoliday = errors.New("invalid holiday")
+
+var (
+	instance     *Calendar
+	instanceOnce sync.Once
+)
+
+// GetInstance returns the calendar with the national holidays and the
+// regional ones configured by BANK_HOLIDAYS.
+func GetInstance() *Calendar {
+	instanceOnce.Do(func() {
+		regional, err := ParseRegionalHolidays(os.Getenv("BANK_HOLIDAYS"))
+		if err != nil {
+			log.Fatalf("Invalid BANK_HOLIDAYS: %v", err)
+		}
+		instance = New(regional...)
+	})
+	return instance
+}
+
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// RegionalHoliday is a holiday of the city or state the bank slips are paid
+// in, on the same day every year unless Year is given.
+type RegionalHoliday struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// ParseRegionalHolidays reads holidays separated by commas, as "MM-DD" for
+// the ones of every year or "YYYY-MM-DD" for a single one.
+func ParseRegionalHolidays(value string) ([]RegionalHoliday, error) {
+	holidays := []RegionalHoliday{}
+	if strings.TrimSpace(value) == "" {
+		return holidays, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		holiday := RegionalHoliday{}
+		if date, err := time.Parse(time.DateOnly, item); err == nil {
+			holiday.Year, holiday.Month, holiday.Day = date.Date()
+		} else if date, err := time.Parse("01-02", item); err == nil {
+			holiday.Month, holiday.Day = date.Month(), date.Day()
+		} else {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidHoliday, item)
+		}
+		holidays = append(holidays, holiday)
+	}
+	return holidays, nil
+}
+
+// Calendar tells the business days of the banks: the weekdays that are not
+// national holidays, including the ones moving with Easter, or regional ones.
+// A boleto due on a day that is not a business day is paid without charges
+// on the next one.
+type Calendar struct {
+	regional []RegionalHoliday
+}
+
+func New(regional ...RegionalHoliday) *Calendar {
+	return &Calendar{regional: regional}
+}
+
+// Holidays returns the holidays of year in date order, the ones on weekends
+// included.
+func (c *Calendar) Holidays(year int) []Holiday {
+	easter := Easter(year)
+	holidays := []Holiday{
+		{Date: day(year, time.January, 1), Name: "Confraternização Universal"},
+		{Date: easter.AddDate(0, 0, -48), Name: "Carnaval"},
+		{Date: easter.AddDate(0, 0, -47), Name: "Carnaval"},
+		{Date: easter.AddDate(0, 0, -2), Name: "Sexta-feira Santa"},
+		{Date: day(year, time.April, 21), Name: "Tiradentes"},
+		{Date: day(year, time.May, 1), Name: "Dia do Trabalho"},
+		{Date: easter.AddDate(0, 0, 60), Name: "Corpus Christi"},
+		{Date: day(year, time.September, 7), Name: "Independência do Brasil"},
+		{Date: day(year, time.October, 12), Name: "Nossa Senhora Aparecida"},
+		{Date: day(year, time.November, 2), Name: "Finados"},
+		{Date: day(year, time.November, 15), Name: "Proclamação da República"},
+		{Date: day(year, time.December, 25), Name: "Natal"},
+	}
+	// Dia da Consciência Negra is a national holiday since 2024 (Lei 14.759/2023).
+	if year >= 2024 {
+		holidays = append(holidays, Holiday{Date: day(year, time.November, 20), Name: "Dia Nacional de Zumbi e da Consciência Negra"})
+	}
+	for _, regional := range c.regional {
+		if regional.Year == 0 || regional.Year == year {
+			holidays = append(holidays, Holiday{Date: day(year, regional.Month, regional.Day), Name: "Feriado regional"})
+		}
+	}
+
+	slices.SortStableFunc(holidays, func(a, b Holiday) int {
+		return a.Date.Compare(b.Date)
+	})
+	return holidays
+}
+
+// Holiday returns the name of the holiday on date, if any.
+func (c *Calendar) Holiday(date time.Time) (string, bool) {
+	date = dateOnly(date)
+	for _, holiday := range c.Holidays(date.Year()) {
+		if holiday.Date.Equal(date) {
+			return holiday.Name, true
+		}
+	}
+	return "", false
+}
+
+func (c *Calendar) IsBusinessDay(date time.Time) bool {
+	if weekday := date.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		return false
+	}
+	_, holiday := c.Holiday(date)
+	return !holiday
+}
+
+// NextBusinessDay returns date itself when it is a business day.
+func (c *Calendar) NextBusinessDay(date time.Time) time.Time {
+	date = dateOnly(date)
+	for !c.IsBusinessDay(date) {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// PreviousBusinessDay returns the last business day before date.
+func (c *Calendar) PreviousBusinessDay(date time.Time) time.Time {
+	date = dateOnly(date).AddDate(0, 0, -1)
+	for !c.IsBusinessDay(date) {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+// Easter returns the Easter Sunday of year in the Gregorian calendar, by the
+// anonymous algorithm (Meeus/Jones/Butcher).
+func Easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	dayOfMonth := (h+l-7*m+114)%31 + 1
+	return day(year, time.Month(month), dayOfMonth)
+}
+
+func day(year int, month time.Month, dayOfMonth int) time.Time {
+	return time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC)
+}
+
+func dateOnly(date time.Time) time.Time {
+	year, month, dayOfMonth := date.Date()
+	return day(year, month, dayOfMonth)
+}
@@ -0,0 +1,75 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse(time.DateOnly, value)
+	return parsed
+}
+
+func TestEaster(t *testing.T) {
+	assert.Equal(t, date("2024-03-31"), Easter(2024))
+	assert.Equal(t, date("2025-04-20"), Easter(2025))
+	assert.Equal(t, date("2026-04-05"), Easter(2026))
+	assert.Equal(t, date("2038-04-25"), Easter(2038))
+}
+
+func TestCalendar_HolidaysShouldIncludeTheOnesMovingWithEaster(t *testing.T) {
+	calendar := New()
+
+	for value, name := range map[string]string{
+		"2026-02-16": "Carnaval",
+		"2026-02-17": "Carnaval",
+		"2026-04-03": "Sexta-feira Santa",
+		"2026-06-04": "Corpus Christi",
+		"2026-04-21": "Tiradentes",
+		"2026-11-20": "Dia Nacional de Zumbi e da Consciência Negra",
+	} {
+		holiday, ok := calendar.Holiday(date(value))
+		assert.True(t, ok, value)
+		assert.Equal(t, name, holiday, value)
+	}
+
+	_, ok := calendar.Holiday(date("2023-11-20"))
+	assert.False(t, ok)
+	assert.Len(t, calendar.Holidays(2026), 13)
+}
+
+func TestCalendar_ShouldShiftToBusinessDays(t *testing.T) {
+	calendar := New()
+
+	assert.True(t, calendar.IsBusinessDay(date("2026-02-18")))
+	assert.False(t, calendar.IsBusinessDay(date("2026-02-14")))
+	assert.Equal(t, date("2026-02-18"), calendar.NextBusinessDay(date("2026-02-14")))
+	assert.Equal(t, date("2026-02-18"), calendar.NextBusinessDay(date("2026-02-18").Add(10*time.Hour)))
+	assert.Equal(t, date("2026-02-13"), calendar.PreviousBusinessDay(date("2026-02-18")))
+	assert.Equal(t, date("2026-02-18"), calendar.PreviousBusinessDay(date("2026-02-19")))
+}
+
+func TestCalendar_ShouldIncludeRegionalHolidays(t *testing.T) {
+	regional, err := ParseRegionalHolidays(" 01-25, 2026-07-09 ")
+	assert.NoError(t, err)
+	assert.Equal(t, []RegionalHoliday{{Month: time.January, Day: 25}, {Year: 2026, Month: time.July, Day: 9}}, regional)
+
+	calendar := New(regional...)
+	assert.False(t, calendar.IsBusinessDay(date("2027-01-25")))
+	assert.False(t, calendar.IsBusinessDay(date("2026-07-09")))
+	assert.True(t, calendar.IsBusinessDay(date("2027-07-09")))
+	assert.True(t, New().IsBusinessDay(date("2027-01-25")))
+}
+
+func TestParseRegionalHolidays_ShouldRejectInvalidDates(t *testing.T) {
+	holidays, err := ParseRegionalHolidays("")
+	assert.NoError(t, err)
+	assert.Empty(t, holidays)
+
+	for _, value := range []string{"25/01", "13-01", "2026-02-30", "01-25,"} {
+		_, err := ParseRegionalHolidays(value)
+		assert.ErrorIs(t, err, ErrInvalidHoliday, value)
+	}
+}
@@ -0,0 +1,147 @@
+package cnab
+
+import (
+	"fmt"
+	"io"
+)
+
+const bradesco400Length = 400
+
+// Bradesco400 writes the CNAB 400 layout of Bradesco's "Cobrança Bradesco"
+// manual: a header, one type 1 record per title and a trailer.
+type Bradesco400 struct{}
+
+func (Bradesco400) Write(w io.Writer, remittance Remittance) error {
+	records := &recordWriter{w: w}
+
+	if err := records.write(bradesco400Header(remittance)); err != nil {
+		return err
+	}
+	for _, title := range remittance.Titles {
+		if err := records.write(bradesco400Title(remittance, title, records.count+1)); err != nil {
+			return err
+		}
+	}
+	trailer := newRecord(bradesco400Length)
+	trailer.digits(1, 1, "9")
+	trailer.number(395, 400, records.count+1)
+	return records.write(trailer)
+}
+
+func bradesco400Header(remittance Remittance) *record {
+	beneficiary := remittance.Beneficiary
+	r := newRecord(bradesco400Length)
+	r.digits(1, 2, "01")
+	r.alpha(3, 9, "REMESSA")
+	r.digits(10, 11, "01")
+	r.alpha(12, 26, "COBRANCA")
+	r.digits(27, 46, beneficiary.AgreementCode)
+	r.alpha(47, 76, beneficiary.Name)
+	r.digits(77, 79, "237")
+	r.alpha(80, 94, "BRADESCO")
+	r.date(95, 100, remittance.GeneratedAt)
+	r.alpha(109, 110, "MX")
+	r.number(111, 117, remittance.Sequence)
+	r.number(395, 400, 1)
+	return r
+}
+
+func bradesco400Title(remittance Remittance, title Title, sequence int64) *record {
+	beneficiary := remittance.Beneficiary
+	r := newRecord(bradesco400Length)
+	r.digits(1, 1, "1")
+	r.digits(2, 20, "")
+	r.digits(21, 37, fmt.Sprintf("0%03s%05s%07s0", beneficiary.Wallet, beneficiary.Agency, beneficiary.Account))
+	r.alpha(38, 62, title.ControlNumber)
+	r.digits(63, 65, "")
+	// Late fee: only percentages, 2 to charge it and 0 none.
+	switch {
+	case title.LateFee.Value == 0:
+		r.digits(66, 70, "")
+	case title.LateFee.Percent:
+		r.digits(66, 66, "2")
+		r.number(67, 70, title.LateFee.Value)
+	default:
+		r.unsupported("late fee as an amount in title %d", title.OurNumber)
+	}
+	r.number(71, 81, title.OurNumber)
+	r.alpha(82, 82, Bradesco400OurNumberDigit(beneficiary.Wallet, title.OurNumber))
+	r.digits(83, 92, "")
+	r.digits(93, 93, "2")
+	r.alpha(94, 94, "N")
+	r.alpha(106, 106, "2")
+	r.digits(109, 110, "01")
+	r.alpha(111, 120, title.DocumentNumber)
+	r.date(121, 126, title.DueDate)
+	r.number(127, 139, title.AmountInCents)
+	r.digits(140, 147, "")
+	r.digits(148, 149, "01")
+	r.alpha(150, 150, "N")
+	r.date(151, 156, title.IssueDate)
+	r.digits(157, 160, "")
+	r.number(161, 173, dailyInterest(title))
+	if title.Discount.Value == 0 {
+		r.digits(174, 192, "")
+	} else {
+		r.date(174, 179, title.DiscountDate)
+		r.number(180, 192, discountAmount(title))
+	}
+	r.digits(193, 218, "")
+	r.number(219, 220, payerType(title.PayerDocument))
+	r.digits(221, 234, title.PayerDocument)
+	r.alpha(235, 274, title.PayerName)
+	r.digits(327, 334, "")
+	r.number(395, 400, sequence)
+	return r
+}
+
+// dailyInterest is the interest of a day late, monthly rates are divided by
+// 30 and rounded half up to the cent.
+func dailyInterest(title Title) int64 {
+	if !title.Interest.Percent {
+		return title.Interest.Value
+	}
+	return percentOf(title.AmountInCents, title.Interest.Value, 30)
+}
+
+// discountAmount is the discount in cents, percentages rounded half up.
+func discountAmount(title Title) int64 {
+	if !title.Discount.Percent {
+		return title.Discount.Value
+	}
+	return percentOf(title.AmountInCents, title.Discount.Value, 1)
+}
+
+// percentOf is the hundredths of a percent of amount divided by divisor,
+// rounded half up. Amounts fit in the 10 digits of a boleto, so it doesn't
+// overflow.
+func percentOf(amount, hundredths, divisor int64) int64 {
+	denominator := 10000 * divisor
+	return (2*amount*hundredths + denominator) / (2 * denominator)
+}
+
+// Bradesco400OurNumberDigit is the check digit of the our number: modulo 11
+// of the wallet (2) and the our number (11) weighted from 2 to 7, "P" when
+// the remainder is 1.
+func Bradesco400OurNumberDigit(wallet string, ourNumber int64) string {
+	value := fmt.Sprintf("%02s%011d", wallet, ourNumber)
+	if len(value) > 13 {
+		value = value[len(value)-13:]
+	}
+	sum, weight := 0, 2
+	for i := len(value) - 1; i >= 0; i-- {
+		sum += int(value[i]-'0') * weight
+		weight++
+		if weight > 7 {
+			weight = 2
+		}
+	}
+	switch remainder := sum % 11; remainder {
+	case 0:
+		return "0"
+	case 1:
+		return "P"
+	default:
+		return fmt.Sprint(11 - remainder)
+	}
+}
@@ -0,0 +1,40 @@
+package cnab
+
+// bradesco400Occurrences are the occurrence codes of the Bradesco return
+// records.
+var bradesco400Occurrences = map[string]Occurrence{
+	"02": OccurrenceConfirmed,
+	"03": OccurrenceRejected,
+	"06": OccurrencePaid,
+	"09": OccurrenceWrittenOff,
+	"10": OccurrenceWrittenOff,
+	"15": OccurrencePaid,
+	"17": OccurrencePaid,
+}
+
+// Read parses the type 1 records, one per title.
+func (Bradesco400) Read(lines []string, firstLine int) []ReturnRecord {
+	records := []ReturnRecord{}
+	for i, line := range lines {
+		if len(line) != bradesco400Length || line[0] != '1' {
+			continue
+		}
+		f := &fields{line: line}
+		record := ReturnRecord{
+			Line:              firstLine + i,
+			Raw:               line,
+			ControlNumber:     f.text(38, 62),
+			OurNumber:         f.number(71, 81),
+			Code:              f.text(109, 110),
+			OccurredAt:        f.date(111, 116),
+			FeeInCents:        f.number(176, 188),
+			PaidAmountInCents: f.number(254, 266),
+			CreditedAt:        f.date(296, 301),
+			Reasons:           f.text(319, 328),
+		}
+		record.Occurrence = occurrenceOf(bradesco400Occurrences, record.Code)
+		record.Err = f.err
+		records = append(records, record)
+	}
+	return records
+}
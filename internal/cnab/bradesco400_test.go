@@ -0,0 +1,81 @@
+package cnab
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBradesco400_ShouldWriteHeaderTitlesAndTrailer(t *testing.T) {
+	records := writeRecords(t, Bradesco400{}, newTestRemittance())
+
+	assert.Len(t, records, 4)
+	for i, record := range records {
+		assert.Len(t, record, 400)
+		assert.Equal(t, "00000"+string(rune('1'+i)), field(record, 395, 400))
+	}
+
+	header := records[0]
+	assert.Equal(t, "01REMESSA01COBRANCA       ", field(header, 1, 26))
+	assert.Equal(t, "00000000000000123456", field(header, 27, 46))
+	assert.Equal(t, "237BRADESCO       ", field(header, 77, 94))
+	assert.Equal(t, "040325", field(header, 95, 100))
+	assert.Equal(t, "MX0000007", field(header, 109, 117))
+
+	title := records[1]
+	assert.Equal(t, "1", field(title, 1, 1))
+	assert.Equal(t, "00090123400123450", field(title, 21, 37))
+	assert.Equal(t, "EA23F2CA663A4266A7429DA4C", field(title, 38, 62))
+	assert.Equal(t, "00000000042", field(title, 71, 81))
+	assert.Equal(t, Bradesco400OurNumberDigit("09", 42), field(title, 82, 82))
+	assert.Equal(t, "01", field(title, 109, 110))
+	assert.Equal(t, "42        ", field(title, 111, 120))
+	assert.Equal(t, "310325", field(title, 121, 126))
+	assert.Equal(t, "0000000100050", field(title, 127, 139))
+	assert.Equal(t, "040325", field(title, 151, 156))
+	assert.Equal(t, "0100052998224725", field(title, 219, 234))
+	assert.Equal(t, "JOAO DA SILVA", field(title, 235, 247))
+	assert.Equal(t, "0211222333000181", field(records[2], 219, 234))
+
+	assert.Equal(t, "9", field(records[3], 1, 1))
+}
+
+func TestBradesco400_ShouldWriteLateFeeInterestAndDiscount(t *testing.T) {
+	remittance := newTestRemittance()
+	remittance.Titles[0].LateFee = Charge{Percent: true, Value: 200}
+	remittance.Titles[0].Interest = Charge{Percent: true, Value: 100}
+	remittance.Titles[0].Discount = Charge{Percent: true, Value: 500}
+	remittance.Titles[0].DiscountDate = time.Date(2025, 3, 26, 0, 0, 0, 0, time.UTC)
+	remittance.Titles[1].Interest = Charge{Value: 7}
+
+	records := writeRecords(t, Bradesco400{}, remittance)
+
+	title := records[1]
+	assert.Equal(t, "20200", field(title, 66, 70))
+	// 1% a month of R$ 1.000,50 is R$ 0,3335 a day.
+	assert.Equal(t, "0000000000033", field(title, 161, 173))
+	assert.Equal(t, "260325", field(title, 174, 179))
+	// 5% of R$ 1.000,50 is R$ 50,025, rounded half up.
+	assert.Equal(t, "0000000005003", field(title, 180, 192))
+
+	assert.Equal(t, "00000", field(records[2], 66, 70))
+	assert.Equal(t, "0000000000007", field(records[2], 161, 173))
+	assert.Equal(t, "0000000000000000000", field(records[2], 174, 192))
+}
+
+func TestBradesco400_ShouldRejectLateFeeAsAnAmount(t *testing.T) {
+	remittance := newTestRemittance()
+	remittance.Titles[1].LateFee = Charge{Value: 500}
+
+	err := Bradesco400{}.Write(&bytes.Buffer{}, remittance)
+	assert.ErrorIs(t, err, ErrUnsupportedCharge)
+}
+
+// Example of the Bradesco manual: wallet 19, our number 00000000002.
+func TestBradesco400OurNumberDigit(t *testing.T) {
+	assert.Equal(t, "8", Bradesco400OurNumberDigit("19", 2))
+	assert.Equal(t, "P", Bradesco400OurNumberDigit("19", 1))
+	assert.Equal(t, "0", Bradesco400OurNumberDigit("19", 6))
+}
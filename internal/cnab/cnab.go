@@ -0,0 +1,138 @@
+// Package cnab writes the FEBRABAN CNAB "remessa" files banks read to register
+// boletos, and reads the "retorno" files they answer with.
+package cnab
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported CNAB format")
+	ErrUnsupportedLayout = errors.New("no CNAB layout for the bank")
+	ErrFieldOverflow     = errors.New("value does not fit in the CNAB field")
+	ErrInvalidField      = errors.New("invalid CNAB field")
+	ErrUnsupportedCharge = errors.New("charge not supported by the CNAB layout")
+)
+
+type Format string
+
+const (
+	CNAB240 Format = "CNAB240"
+	CNAB400 Format = "CNAB400"
+)
+
+// AnyBank registers a layout followed by every bank that has none of its own.
+const AnyBank = ""
+
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToUpper(strings.TrimSpace(value))); format {
+	case CNAB240, CNAB400:
+		return format, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, value)
+}
+
+// Beneficiary is the account the titles are registered to.
+type Beneficiary struct {
+	Name          string
+	Document      string
+	BankCode      string
+	Agency        string
+	Account       string
+	Wallet        string
+	AgreementCode string
+}
+
+// Title is a boleto to be registered at the bank.
+type Title struct {
+	OurNumber int64
+	// DocumentNumber ("seu número") is the title number printed on the boleto.
+	DocumentNumber string
+	// ControlNumber is returned as is by the bank in the "retorno" files.
+	ControlNumber string
+	IssueDate     time.Time
+	DueDate       time.Time
+	AmountInCents int64
+	PayerName     string
+	PayerDocument string
+	// LateFee ("multa") and Interest ("juros de mora") are charged from the
+	// day after the due date, Discount when paid until DiscountDate.
+	LateFee      Charge
+	Interest     Charge
+	Discount     Charge
+	DiscountDate time.Time
+}
+
+// Charge is an amount in cents, or a percentage in hundredths of a percent
+// when Percent. Interest percentages are monthly and amounts daily. A zero
+// Value is no charge.
+type Charge struct {
+	Percent bool
+	Value   int64
+}
+
+// chargeFrom is the date late fees and interest start being charged.
+func (t Title) chargeFrom() time.Time {
+	return t.DueDate.AddDate(0, 0, 1)
+}
+
+// Remittance is a "remessa" file, numbered in sequence per beneficiary.
+type Remittance struct {
+	Beneficiary Beneficiary
+	Sequence    int64
+	GeneratedAt time.Time
+	Titles      []Title
+}
+
+type Layout interface {
+	Write(w io.Writer, remittance Remittance) error
+}
+
+type layoutKey struct {
+	bankCode string
+	format   Format
+}
+
+var (
+	layoutsMutex sync.RWMutex
+	layouts      = map[layoutKey]Layout{
+		{AnyBank, CNAB240}: Febraban240{},
+		{"237", CNAB400}:   Bradesco400{},
+	}
+)
+
+// Register makes layout the one used to write the format for the bank,
+// replacing any layout registered before.
+func Register(bankCode string, format Format, layout Layout) {
+	layoutsMutex.Lock()
+	defer layoutsMutex.Unlock()
+	layouts[layoutKey{bankCode, format}] = layout
+}
+
+// LayoutFor returns the layout registered for the bank, or the one registered
+// for AnyBank.
+func LayoutFor(bankCode string, format Format) (Layout, error) {
+	layoutsMutex.RLock()
+	defer layoutsMutex.RUnlock()
+	if layout, ok := layouts[layoutKey{bankCode, format}]; ok {
+		return layout, nil
+	}
+	if layout, ok := layouts[layoutKey{AnyBank, format}]; ok {
+		return layout, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrUnsupportedLayout, bankCode, format)
+}
+
+// payerType is the registration type of a document, 1 for a CPF and 2 for a
+// CNPJ.
+func payerType(document string) int64 {
+	if len(document) == 14 {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,107 @@
+package cnab
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRemittance() Remittance {
+	return Remittance{
+		Beneficiary: Beneficiary{
+			Name:          "Cobranças Ltda",
+			Document:      "11222333000181",
+			BankCode:      "237",
+			Agency:        "1234",
+			Account:       "0012345",
+			Wallet:        "09",
+			AgreementCode: "123456",
+		},
+		Sequence:    7,
+		GeneratedAt: time.Date(2025, 3, 4, 10, 20, 30, 0, time.UTC),
+		Titles: []Title{
+			{
+				OurNumber:      42,
+				DocumentNumber: "42",
+				ControlNumber:  "ea23f2ca663a4266a7429da4c",
+				IssueDate:      time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+				DueDate:        time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+				AmountInCents:  100050,
+				PayerName:      "João da Silva",
+				PayerDocument:  "52998224725",
+			},
+			{
+				OurNumber:      43,
+				DocumentNumber: "43",
+				IssueDate:      time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+				DueDate:        time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC),
+				AmountInCents:  2000,
+				PayerName:      "Empresa Pagadora",
+				PayerDocument:  "11222333000181",
+			},
+		},
+	}
+}
+
+// writeRecords writes the remittance and splits it in records, checking every
+// one of them ends with CRLF.
+func writeRecords(t *testing.T, layout Layout, remittance Remittance) []string {
+	var output bytes.Buffer
+	err := layout.Write(&output, remittance)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(output.String(), "\r\n"))
+	return strings.Split(strings.TrimSuffix(output.String(), "\r\n"), "\r\n")
+}
+
+// field reads the positions of a record, counted from 1 as in the manuals.
+func field(record string, first, last int) string {
+	return record[first-1 : last]
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("cnab240")
+	assert.NoError(t, err)
+	assert.Equal(t, CNAB240, format)
+	format, err = ParseFormat(" CNAB400 ")
+	assert.NoError(t, err)
+	assert.Equal(t, CNAB400, format)
+
+	_, err = ParseFormat("CNAB500")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+type testLayout struct{}
+
+func (testLayout) Write(w io.Writer, remittance Remittance) error { return nil }
+
+func TestLayoutFor(t *testing.T) {
+	layout, err := LayoutFor("237", CNAB400)
+	assert.NoError(t, err)
+	assert.Equal(t, Bradesco400{}, layout)
+
+	layout, err = LayoutFor("001", CNAB240)
+	assert.NoError(t, err)
+	assert.Equal(t, Febraban240{}, layout)
+
+	_, err = LayoutFor("001", CNAB400)
+	assert.ErrorIs(t, err, ErrUnsupportedLayout)
+
+	Register("999", CNAB240, testLayout{})
+	defer Register("999", CNAB240, Febraban240{})
+	layout, err = LayoutFor("999", CNAB240)
+	assert.NoError(t, err)
+	assert.Equal(t, testLayout{}, layout)
+}
+
+func TestRecord_ShouldRejectValuesLongerThanNumericFields(t *testing.T) {
+	r := newRecord(10)
+	r.alpha(1, 3, "ação longa")
+	r.number(4, 5, 123)
+
+	assert.Equal(t, "ACA", string(r.line[:3]))
+	assert.ErrorIs(t, r.err, ErrFieldOverflow)
+}
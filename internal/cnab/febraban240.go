@@ -0,0 +1,213 @@
+package cnab
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	febraban240Length     = 240
+	febraban240FileLayout = "103"
+	febraban240LotLayout  = "060"
+)
+
+// Febraban240 writes the CNAB 240 layout of the FEBRABAN manual (version
+// 10.7): a file header, one lot with the P, Q and R segments of each title, and
+// the lot and file trailers.
+type Febraban240 struct{}
+
+func (Febraban240) Write(w io.Writer, remittance Remittance) error {
+	records := &recordWriter{w: w}
+
+	if err := records.write(febraban240FileHeader(remittance)); err != nil {
+		return err
+	}
+	if err := records.write(febraban240LotHeader(remittance)); err != nil {
+		return err
+	}
+
+	var lotRecords, total int64 = 1, 0
+	for _, title := range remittance.Titles {
+		segments := []*record{
+			febraban240SegmentP(remittance, title, lotRecords),
+			febraban240SegmentQ(remittance, title, lotRecords+1),
+			febraban240SegmentR(remittance, title, lotRecords+2),
+		}
+		for _, segment := range segments {
+			if err := records.write(segment); err != nil {
+				return err
+			}
+		}
+		lotRecords += int64(len(segments))
+		total += title.AmountInCents
+	}
+
+	if err := records.write(febraban240LotTrailer(remittance, lotRecords+1, total)); err != nil {
+		return err
+	}
+	return records.write(febraban240FileTrailer(remittance, records.count+1))
+}
+
+// febraban240Control fills the bank, lot and record type every record starts
+// with.
+func febraban240Control(remittance Remittance, lot string, recordType string) *record {
+	r := newRecord(febraban240Length)
+	r.digits(1, 3, remittance.Beneficiary.BankCode)
+	r.digits(4, 7, lot)
+	r.digits(8, 8, recordType)
+	return r
+}
+
+// febraban240Account fills the agency and account, leaving their check digits
+// blank.
+func febraban240Account(r *record, first int, beneficiary Beneficiary) {
+	r.digits(first, first+4, beneficiary.Agency)
+	r.digits(first+6, first+17, beneficiary.Account)
+}
+
+func febraban240FileHeader(remittance Remittance) *record {
+	beneficiary := remittance.Beneficiary
+	r := febraban240Control(remittance, "0000", "0")
+	r.number(18, 18, payerType(beneficiary.Document))
+	r.digits(19, 32, beneficiary.Document)
+	r.alpha(33, 52, beneficiary.AgreementCode)
+	febraban240Account(r, 53, beneficiary)
+	r.alpha(73, 102, beneficiary.Name)
+	r.digits(143, 143, "1")
+	r.date(144, 151, remittance.GeneratedAt)
+	r.digits(152, 157, remittance.GeneratedAt.Format("150405"))
+	r.number(158, 163, remittance.Sequence)
+	r.digits(164, 166, febraban240FileLayout)
+	r.digits(167, 171, "")
+	return r
+}
+
+func febraban240LotHeader(remittance Remittance) *record {
+	beneficiary := remittance.Beneficiary
+	r := febraban240Control(remittance, "0001", "1")
+	r.alpha(9, 9, "R")
+	r.digits(10, 11, "01")
+	r.digits(14, 16, febraban240LotLayout)
+	r.number(18, 18, payerType(beneficiary.Document))
+	r.digits(19, 33, beneficiary.Document)
+	r.alpha(34, 53, beneficiary.AgreementCode)
+	febraban240Account(r, 54, beneficiary)
+	r.alpha(74, 103, beneficiary.Name)
+	r.number(184, 191, remittance.Sequence)
+	r.date(192, 199, remittance.GeneratedAt)
+	r.digits(200, 207, "")
+	return r
+}
+
+// febraban240Segment fills the start shared by the P, Q and R segments of a
+// new title ("entrada de títulos").
+func febraban240Segment(remittance Remittance, sequence int64, segment string) *record {
+	r := febraban240Control(remittance, "0001", "3")
+	r.number(9, 13, sequence)
+	r.alpha(14, 14, segment)
+	r.digits(16, 17, "01")
+	return r
+}
+
+func febraban240SegmentP(remittance Remittance, title Title, sequence int64) *record {
+	beneficiary := remittance.Beneficiary
+	r := febraban240Segment(remittance, sequence, "P")
+	febraban240Account(r, 18, beneficiary)
+	r.alpha(38, 57, fmt.Sprintf("%03s%011d", beneficiary.Wallet, title.OurNumber))
+	r.digits(58, 58, "1")
+	r.digits(59, 59, "1")
+	r.alpha(60, 60, "1")
+	r.digits(61, 61, "2")
+	r.alpha(62, 62, "2")
+	r.alpha(63, 77, title.DocumentNumber)
+	r.date(78, 85, title.DueDate)
+	r.number(86, 100, title.AmountInCents)
+	r.digits(101, 105, "")
+	r.digits(107, 108, "02")
+	r.alpha(109, 109, "N")
+	r.date(110, 117, title.IssueDate)
+	// Interest: 1 is an amount a day, 2 a monthly rate and 3 exempt.
+	switch {
+	case title.Interest.Value == 0:
+		r.digits(118, 118, "3")
+		r.digits(119, 141, "")
+	case title.Interest.Percent:
+		febraban240Charge(r, 118, "2", title.chargeFrom(), title.Interest)
+	default:
+		febraban240Charge(r, 118, "1", title.chargeFrom(), title.Interest)
+	}
+	// Discount: 1 is an amount and 2 a percentage until the date, 0 none.
+	switch {
+	case title.Discount.Value == 0:
+		r.digits(142, 165, "")
+	case title.Discount.Percent:
+		febraban240Charge(r, 142, "2", title.DiscountDate, title.Discount)
+	default:
+		febraban240Charge(r, 142, "1", title.DiscountDate, title.Discount)
+	}
+	r.digits(166, 195, "")
+	r.alpha(196, 220, title.ControlNumber)
+	r.digits(221, 221, "3")
+	r.digits(222, 223, "")
+	r.digits(224, 224, "1")
+	r.digits(225, 227, "060")
+	r.digits(228, 229, "09")
+	r.digits(230, 239, "")
+	return r
+}
+
+func febraban240SegmentQ(remittance Remittance, title Title, sequence int64) *record {
+	r := febraban240Segment(remittance, sequence, "Q")
+	r.number(18, 18, payerType(title.PayerDocument))
+	r.digits(19, 33, title.PayerDocument)
+	r.alpha(34, 73, title.PayerName)
+	r.digits(129, 136, "")
+	r.digits(154, 169, "")
+	r.digits(210, 212, "")
+	return r
+}
+
+// febraban240SegmentR has the late fee, the second and third discounts are
+// not used.
+func febraban240SegmentR(remittance Remittance, title Title, sequence int64) *record {
+	r := febraban240Segment(remittance, sequence, "R")
+	r.digits(18, 65, "")
+	// Late fee: 1 is an amount and 2 a percentage, 0 none.
+	switch {
+	case title.LateFee.Value == 0:
+		r.digits(66, 89, "")
+	case title.LateFee.Percent:
+		febraban240Charge(r, 66, "2", title.chargeFrom(), title.LateFee)
+	default:
+		febraban240Charge(r, 66, "1", title.chargeFrom(), title.LateFee)
+	}
+	r.digits(200, 215, "")
+	r.digits(217, 228, "")
+	return r
+}
+
+func febraban240LotTrailer(remittance Remittance, lotRecords int64, total int64) *record {
+	r := febraban240Control(remittance, "0001", "5")
+	r.number(18, 23, lotRecords)
+	r.number(24, 29, int64(len(remittance.Titles)))
+	r.number(30, 46, total)
+	r.digits(47, 115, "")
+	return r
+}
+
+func febraban240FileTrailer(remittance Remittance, fileRecords int64) *record {
+	r := febraban240Control(remittance, "9999", "9")
+	r.digits(18, 23, "1")
+	r.number(24, 29, fileRecords)
+	r.digits(30, 35, "")
+	return r
+}
+
+// febraban240Charge writes the code, date and value of a charge in the 24
+// positions starting at first, values having two decimal places.
+func febraban240Charge(r *record, first int, code string, date time.Time, charge Charge) {
+	r.digits(first, first, code)
+	r.date(first+1, first+8, date)
+	r.number(first+9, first+23, charge.Value)
+}
@@ -0,0 +1,86 @@
+package cnab
+
+import (
+	"errors"
+	"fmt"
+)
+
+// febraban240Occurrences are the "código de movimento" of the return
+// segments.
+var febraban240Occurrences = map[string]Occurrence{
+	"02": OccurrenceConfirmed,
+	"03": OccurrenceRejected,
+	"06": OccurrencePaid,
+	"09": OccurrenceWrittenOff,
+	"17": OccurrencePaid,
+}
+
+// Read pairs the T segment of each title with the U segment after it, which
+// carries the amounts and dates of the payment.
+func (Febraban240) Read(lines []string, firstLine int) []ReturnRecord {
+	records := []ReturnRecord{}
+	var title *ReturnRecord
+	for i, line := range lines {
+		switch {
+		case isFebraban240Segment(line, 'T'):
+			if title != nil {
+				records = append(records, withError(*title, errors.New("segment T without segment U")))
+			}
+			record := febraban240SegmentT(line, firstLine+i)
+			title = &record
+		case isFebraban240Segment(line, 'U'):
+			if title == nil {
+				records = append(records, ReturnRecord{Line: firstLine + i, Raw: line, Err: fmt.Errorf("%w: segment U without segment T", ErrInvalidReturn)})
+				continue
+			}
+			records = append(records, febraban240SegmentU(*title, line))
+			title = nil
+		}
+	}
+	if title != nil {
+		records = append(records, withError(*title, errors.New("segment T without segment U")))
+	}
+	return records
+}
+
+func febraban240SegmentT(line string, lineNumber int) ReturnRecord {
+	f := &fields{line: line}
+	record := ReturnRecord{
+		Line:          lineNumber,
+		Raw:           line,
+		Code:          f.text(16, 17),
+		OurNumber:     f.number(41, 51),
+		ControlNumber: f.text(106, 130),
+		FeeInCents:    f.number(199, 213),
+		Reasons:       f.text(214, 223),
+	}
+	record.Occurrence = occurrenceOf(febraban240Occurrences, record.Code)
+	record.Err = f.err
+	return record
+}
+
+func febraban240SegmentU(record ReturnRecord, line string) ReturnRecord {
+	f := &fields{line: line}
+	record.Raw += "\n" + line
+	record.PaidAmountInCents = f.number(78, 92)
+	record.OccurredAt = f.date(138, 145)
+	record.CreditedAt = f.date(146, 153)
+	if record.Err == nil {
+		record.Err = f.err
+	}
+	return record
+}
+
+func occurrenceOf(occurrences map[string]Occurrence, code string) Occurrence {
+	if occurrence, ok := occurrences[code]; ok {
+		return occurrence
+	}
+	return OccurrenceOther
+}
+
+func withError(record ReturnRecord, err error) ReturnRecord {
+	if record.Err == nil {
+		record.Err = fmt.Errorf("%w: %w", ErrInvalidReturn, err)
+	}
+	return record
+}
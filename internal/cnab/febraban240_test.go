@@ -0,0 +1,111 @@
+package cnab
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFebraban240_ShouldWriteHeaderSegmentsAndTrailers(t *testing.T) {
+	records := writeRecords(t, Febraban240{}, newTestRemittance())
+
+	assert.Len(t, records, 10)
+	types := ""
+	for _, record := range records {
+		assert.Len(t, record, 240)
+		assert.Equal(t, "237", field(record, 1, 3))
+		types += field(record, 8, 8)
+		if field(record, 8, 8) == "3" {
+			types += field(record, 14, 14)
+		}
+	}
+	assert.Equal(t, "013P3Q3R3P3Q3R59", types)
+
+	fileHeader := records[0]
+	assert.Equal(t, "0000", field(fileHeader, 4, 7))
+	assert.Equal(t, "2", field(fileHeader, 18, 18))
+	assert.Equal(t, "11222333000181", field(fileHeader, 19, 32))
+	assert.Equal(t, "123456              ", field(fileHeader, 33, 52))
+	assert.Equal(t, "01234", field(fileHeader, 53, 57))
+	assert.Equal(t, "000000012345", field(fileHeader, 59, 70))
+	assert.Equal(t, "COBRANCAS LTDA                ", field(fileHeader, 73, 102))
+	assert.Equal(t, "1", field(fileHeader, 143, 143))
+	assert.Equal(t, "04032025102030", field(fileHeader, 144, 157))
+	assert.Equal(t, "000007", field(fileHeader, 158, 163))
+
+	lotHeader := records[1]
+	assert.Equal(t, "0001", field(lotHeader, 4, 7))
+	assert.Equal(t, "R01", field(lotHeader, 9, 11))
+	assert.Equal(t, "00000007", field(lotHeader, 184, 191))
+	assert.Equal(t, "04032025", field(lotHeader, 192, 199))
+
+	segmentP := records[2]
+	assert.Equal(t, "00001", field(segmentP, 9, 13))
+	assert.Equal(t, "01", field(segmentP, 16, 17))
+	assert.Equal(t, "00900000000042      ", field(segmentP, 38, 57))
+	assert.Equal(t, "42             ", field(segmentP, 63, 77))
+	assert.Equal(t, "31032025", field(segmentP, 78, 85))
+	assert.Equal(t, "000000000100050", field(segmentP, 86, 100))
+	assert.Equal(t, "04032025", field(segmentP, 110, 117))
+	assert.Equal(t, "EA23F2CA663A4266A7429DA4C", field(segmentP, 196, 220))
+	assert.Equal(t, "09", field(segmentP, 228, 229))
+
+	segmentQ := records[3]
+	assert.Equal(t, "00002", field(segmentQ, 9, 13))
+	assert.Equal(t, "1", field(segmentQ, 18, 18))
+	assert.Equal(t, "000052998224725", field(segmentQ, 19, 33))
+	assert.Equal(t, "JOAO DA SILVA", field(segmentQ, 34, 46))
+	assert.Equal(t, "2", field(records[6], 18, 18))
+
+	lotTrailer := records[8]
+	assert.Equal(t, "000008", field(lotTrailer, 18, 23))
+	assert.Equal(t, "000002", field(lotTrailer, 24, 29))
+	assert.Equal(t, "00000000000102050", field(lotTrailer, 30, 46))
+
+	fileTrailer := records[9]
+	assert.Equal(t, "9999", field(fileTrailer, 4, 7))
+	assert.Equal(t, "000001", field(fileTrailer, 18, 23))
+	assert.Equal(t, "000010", field(fileTrailer, 24, 29))
+}
+
+func TestFebraban240_ShouldFailWhenAValueDoesNotFit(t *testing.T) {
+	remittance := newTestRemittance()
+	remittance.Titles[1].AmountInCents = -1
+
+	err := Febraban240{}.Write(&bytes.Buffer{}, remittance)
+	assert.ErrorIs(t, err, ErrFieldOverflow)
+}
+
+func TestFebraban240_ShouldWriteInterestDiscountAndLateFee(t *testing.T) {
+	remittance := newTestRemittance()
+	remittance.Titles[0].Interest = Charge{Percent: true, Value: 100}
+	remittance.Titles[0].Discount = Charge{Value: 1000}
+	remittance.Titles[0].DiscountDate = time.Date(2025, 3, 26, 0, 0, 0, 0, time.UTC)
+	remittance.Titles[0].LateFee = Charge{Percent: true, Value: 200}
+	remittance.Titles[1].Interest = Charge{Value: 7}
+	remittance.Titles[1].LateFee = Charge{Value: 500}
+
+	records := writeRecords(t, Febraban240{}, remittance)
+
+	segmentP := records[2]
+	assert.Equal(t, "2"+"01042025"+"000000000000100", field(segmentP, 118, 141))
+	assert.Equal(t, "1"+"26032025"+"000000000001000", field(segmentP, 142, 165))
+	segmentR := records[4]
+	assert.Equal(t, "R", field(segmentR, 14, 14))
+	assert.Equal(t, "2"+"01042025"+"000000000000200", field(segmentR, 66, 89))
+
+	assert.Equal(t, "1"+"01052025"+"000000000000007", field(records[5], 118, 141))
+	assert.Equal(t, "0"+strings.Repeat("0", 23), field(records[5], 142, 165))
+	assert.Equal(t, "1"+"01052025"+"000000000000500", field(records[7], 66, 89))
+}
+
+func TestFebraban240_ShouldWriteExemptTitlesWithoutCharges(t *testing.T) {
+	records := writeRecords(t, Febraban240{}, newTestRemittance())
+
+	assert.Equal(t, "3"+strings.Repeat("0", 23), field(records[2], 118, 141))
+	assert.Equal(t, strings.Repeat("0", 24), field(records[2], 142, 165))
+	assert.Equal(t, strings.Repeat("0", 24), field(records[4], 66, 89))
+}
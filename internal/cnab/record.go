@@ -0,0 +1,150 @@
+package cnab
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"performatic-file-processor/internal/ascii"
+)
+
+// record is a fixed-width line. Its fields are addressed by their first and
+// last positions, counted from 1 as in the bank manuals.
+type record struct {
+	line []byte
+	err  error
+}
+
+func newRecord(length int) *record {
+	return &record{line: bytes.Repeat([]byte{' '}, length)}
+}
+
+// alpha writes an upper case text without accents, left aligned and filled
+// with blanks. Longer texts are cut.
+func (r *record) alpha(first, last int, value string) {
+	value = strings.ToUpper(ascii.WithoutAccents(value))
+	width := last - first + 1
+	if len(value) > width {
+		value = value[:width]
+	}
+	copy(r.line[first-1:last], fmt.Sprintf("%-*s", width, value))
+}
+
+// number writes a non negative number right aligned and filled with zeros.
+func (r *record) number(first, last int, value int64) {
+	if value < 0 {
+		r.fail(first, last, fmt.Sprint(value))
+		return
+	}
+	r.digits(first, last, fmt.Sprint(value))
+}
+
+// digits writes a code right aligned and filled with zeros.
+func (r *record) digits(first, last int, value string) {
+	width := last - first + 1
+	if len(value) > width {
+		r.fail(first, last, value)
+		return
+	}
+	copy(r.line[first-1:last], strings.Repeat("0", width-len(value))+value)
+}
+
+// date writes DDMMAAAA in 8 positions or DDMMAA in 6, zeros when there's no
+// date.
+func (r *record) date(first, last int, value time.Time) {
+	width := last - first + 1
+	if value.IsZero() {
+		r.digits(first, last, "")
+		return
+	}
+	layout := "02012006"
+	if width == 6 {
+		layout = "020106"
+	}
+	r.digits(first, last, value.Format(layout))
+}
+
+// unsupported fails the record with a charge the layout can't write.
+func (r *record) unsupported(format string, args ...any) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: %s", ErrUnsupportedCharge, fmt.Sprintf(format, args...))
+	}
+}
+
+func (r *record) fail(first, last int, value string) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: %q in positions %d to %d", ErrFieldOverflow, value, first, last)
+	}
+}
+
+// recordWriter ends every record with CRLF, as the banks expect.
+type recordWriter struct {
+	w     io.Writer
+	count int64
+}
+
+func (rw *recordWriter) write(r *record) error {
+	if r.err != nil {
+		return r.err
+	}
+	rw.count++
+	_, err := rw.w.Write(append(r.line, '\r', '\n'))
+	return err
+}
+
+// fields reads a fixed-width line sent by the bank, keeping the first error
+// found. Positions are counted from 1 as in the bank manuals.
+type fields struct {
+	line string
+	err  error
+}
+
+// text returns the field without the blanks around it.
+func (f *fields) text(first, last int) string {
+	if len(f.line) < last {
+		f.fail(first, last, "")
+		return ""
+	}
+	return strings.TrimSpace(f.line[first-1 : last])
+}
+
+// number reads a field of digits, a blank field is zero.
+func (f *fields) number(first, last int) int64 {
+	value := f.text(first, last)
+	if value == "" {
+		return 0
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number < 0 {
+		f.fail(first, last, value)
+		return 0
+	}
+	return number
+}
+
+// date reads DDMMAAAA in 8 positions or DDMMAA in 6, a zero time when the
+// field is zeros or blank.
+func (f *fields) date(first, last int) time.Time {
+	value := f.text(first, last)
+	if strings.Trim(value, "0") == "" {
+		return time.Time{}
+	}
+	layout := "02012006"
+	if last-first+1 == 6 {
+		layout = "020106"
+	}
+	date, err := time.Parse(layout, value)
+	if err != nil {
+		f.fail(first, last, value)
+	}
+	return date
+}
+
+func (f *fields) fail(first, last int, value string) {
+	if f.err == nil {
+		f.err = fmt.Errorf("%w: %q in positions %d to %d", ErrInvalidField, value, first, last)
+	}
+}
@@ -0,0 +1,185 @@
+package cnab
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidReturn = errors.New("invalid CNAB return file")
+
+// Occurrence is what the bank did with a title, told by the occurrence code
+// of a return record.
+type Occurrence string
+
+const (
+	// OccurrenceConfirmed is a title registered by the bank.
+	OccurrenceConfirmed Occurrence = "CONFIRMED"
+	// OccurrenceRejected is a title the bank refused to register.
+	OccurrenceRejected Occurrence = "REJECTED"
+	OccurrencePaid     Occurrence = "PAID"
+	// OccurrenceWrittenOff is a title closed by the bank ("baixa") without a
+	// payment.
+	OccurrenceWrittenOff Occurrence = "WRITTEN_OFF"
+	OccurrenceOther      Occurrence = "OTHER"
+)
+
+// ReturnHeader identifies the layout of a return file.
+type ReturnHeader struct {
+	Format   Format
+	BankCode string
+}
+
+// ReadReturnHeader reads the first line of a return file.
+func ReadReturnHeader(line string) (ReturnHeader, error) {
+	switch {
+	case len(line) == febraban240Length && line[7] == '0':
+		if line[142] != '2' {
+			return ReturnHeader{}, fmt.Errorf("%w: CNAB 240 file is not a return", ErrInvalidReturn)
+		}
+		return ReturnHeader{Format: CNAB240, BankCode: line[0:3]}, nil
+	case len(line) == bradesco400Length && line[0] == '0':
+		if line[1] != '2' {
+			return ReturnHeader{}, fmt.Errorf("%w: CNAB 400 file is not a return", ErrInvalidReturn)
+		}
+		return ReturnHeader{Format: CNAB400, BankCode: line[76:79]}, nil
+	}
+	return ReturnHeader{}, fmt.Errorf("%w: header of %d characters is neither CNAB 240 nor CNAB 400", ErrInvalidReturn, len(line))
+}
+
+// ReturnRecord is what the bank reported about a title.
+type ReturnRecord struct {
+	// Line is where the record begins in the file, Raw its lines as sent.
+	Line int
+	Raw  string
+	// Code is the occurrence code of the bank, as in "06" for a payment.
+	Code       string
+	Occurrence Occurrence
+	OurNumber  int64
+	// ControlNumber is sent back as it was written in the remittance.
+	ControlNumber     string
+	OccurredAt        time.Time
+	CreditedAt        time.Time
+	PaidAmountInCents int64
+	FeeInCents        int64
+	// Reasons are the codes the bank explains rejections with.
+	Reasons string
+	// Err tells why the record could not be read, its other fields are then
+	// not to be trusted.
+	Err error
+}
+
+type ReturnLayout interface {
+	// Read parses the titles reported in lines, skipping headers and
+	// trailers. The line of lines[0] in the file is firstLine.
+	Read(lines []string, firstLine int) []ReturnRecord
+}
+
+var (
+	returnLayoutsMutex sync.RWMutex
+	returnLayouts      = map[layoutKey]ReturnLayout{
+		{AnyBank, CNAB240}: Febraban240{},
+		{"237", CNAB400}:   Bradesco400{},
+	}
+)
+
+// RegisterReturn makes layout the one used to read the return files of the
+// format for the bank, replacing any layout registered before.
+func RegisterReturn(bankCode string, format Format, layout ReturnLayout) {
+	returnLayoutsMutex.Lock()
+	defer returnLayoutsMutex.Unlock()
+	returnLayouts[layoutKey{bankCode, format}] = layout
+}
+
+// ReturnLayoutFor returns the return layout registered for the bank, or the
+// one registered for AnyBank.
+func ReturnLayoutFor(bankCode string, format Format) (ReturnLayout, error) {
+	returnLayoutsMutex.RLock()
+	defer returnLayoutsMutex.RUnlock()
+	if layout, ok := returnLayouts[layoutKey{bankCode, format}]; ok {
+		return layout, nil
+	}
+	if layout, ok := returnLayouts[layoutKey{AnyBank, format}]; ok {
+		return layout, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s return", ErrUnsupportedLayout, bankCode, format)
+}
+
+// ReturnReader splits a return file into records: a line, or for CNAB 240 the
+// T segment of a title with the segments following it, so the lines of a title
+// always stay together.
+type ReturnReader struct {
+	reader     *bufio.Reader
+	next       *string
+	nextErr    error
+	lines      int
+	recordLine int
+}
+
+func NewReturnReader(reader io.Reader, bufferSize int) *ReturnReader {
+	return &ReturnReader{reader: bufio.NewReaderSize(reader, bufferSize)}
+}
+
+func (r *ReturnReader) Next() (string, error) {
+	record, err := r.take()
+	if err != nil {
+		return "", err
+	}
+	r.recordLine = r.lines
+	if !isFebraban240Segment(record, 'T') {
+		return record, nil
+	}
+
+	for {
+		line, err := r.peek()
+		if err != nil || !isFebraban240Segment(line, 0) || line[13] == 'T' {
+			return record, nil
+		}
+		r.take()
+		record += "\n" + line
+	}
+}
+
+// Line returns the line, starting at 1, where the last record returned by Next
+// begins.
+func (r *ReturnReader) Line() int {
+	return r.recordLine
+}
+
+func (r *ReturnReader) peek() (string, error) {
+	if r.next == nil && r.nextErr == nil {
+		line, err := r.reader.ReadString('\n')
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		r.next, r.nextErr = &line, err
+	}
+	if r.nextErr != nil {
+		return "", r.nextErr
+	}
+	return *r.next, nil
+}
+
+func (r *ReturnReader) take() (string, error) {
+	line, err := r.peek()
+	if err != nil {
+		return "", err
+	}
+	r.next = nil
+	r.lines++
+	return line, nil
+}
+
+// isFebraban240Segment tells if line is a CNAB 240 detail record of the
+// segment, of any segment when it is 0.
+func isFebraban240Segment(line string, segment byte) bool {
+	if len(line) != febraban240Length || line[7] != '3' {
+		return false
+	}
+	return segment == 0 || line[13] == segment
+}
@@ -0,0 +1,175 @@
+package cnab
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFebraban240ReturnHeader() string {
+	r := newRecord(febraban240Length)
+	r.digits(1, 3, "001")
+	r.digits(4, 7, "0000")
+	r.digits(8, 8, "0")
+	r.digits(143, 143, "2")
+	return string(r.line)
+}
+
+// newFebraban240ReturnTitle writes the T and U segments of a title.
+func newFebraban240ReturnTitle(code string, ourNumber int64, controlNumber string, paid int64) []string {
+	t := newRecord(febraban240Length)
+	t.digits(1, 3, "001")
+	t.digits(8, 8, "3")
+	t.alpha(14, 14, "T")
+	t.digits(16, 17, code)
+	t.alpha(38, 57, "009"+strings.Repeat("0", 11))
+	t.number(41, 51, ourNumber)
+	t.alpha(106, 130, controlNumber)
+	t.number(199, 213, 250)
+	t.alpha(214, 223, "A1")
+
+	u := newRecord(febraban240Length)
+	u.digits(1, 3, "001")
+	u.digits(8, 8, "3")
+	u.alpha(14, 14, "U")
+	u.number(78, 92, paid)
+	u.date(138, 145, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+	u.date(146, 153, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC))
+	return []string{string(t.line), string(u.line)}
+}
+
+func newBradesco400ReturnTitle(code string, ourNumber int64, paid int64) string {
+	r := newRecord(bradesco400Length)
+	r.digits(1, 1, "1")
+	r.alpha(38, 62, "EA23F2CA663A4266A7429DA4C")
+	r.number(71, 81, ourNumber)
+	r.digits(109, 110, code)
+	r.date(111, 116, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+	r.number(176, 188, 180)
+	r.number(254, 266, paid)
+	r.date(296, 301, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC))
+	return string(r.line)
+}
+
+func TestReadReturnHeader(t *testing.T) {
+	header, err := ReadReturnHeader(newFebraban240ReturnHeader())
+	assert.NoError(t, err)
+	assert.Equal(t, ReturnHeader{Format: CNAB240, BankCode: "001"}, header)
+
+	r := newRecord(bradesco400Length)
+	r.alpha(1, 9, "02RETORNO")
+	r.digits(77, 79, "237")
+	header, err = ReadReturnHeader(string(r.line))
+	assert.NoError(t, err)
+	assert.Equal(t, ReturnHeader{Format: CNAB400, BankCode: "237"}, header)
+
+	r.alpha(1, 9, "01REMESSA")
+	_, err = ReadReturnHeader(string(r.line))
+	assert.ErrorIs(t, err, ErrInvalidReturn)
+
+	_, err = ReadReturnHeader("name,governmentId")
+	assert.ErrorIs(t, err, ErrInvalidReturn)
+}
+
+func TestReturnLayoutFor(t *testing.T) {
+	layout, err := ReturnLayoutFor("104", CNAB240)
+	assert.NoError(t, err)
+	assert.Equal(t, Febraban240{}, layout)
+
+	layout, err = ReturnLayoutFor("237", CNAB400)
+	assert.NoError(t, err)
+	assert.Equal(t, Bradesco400{}, layout)
+
+	_, err = ReturnLayoutFor("104", CNAB400)
+	assert.ErrorIs(t, err, ErrUnsupportedLayout)
+}
+
+func TestFebraban240_ShouldReadTitlesFromSegmentsTAndU(t *testing.T) {
+	lines := append([]string{"lot header"}, newFebraban240ReturnTitle("06", 42, "EA23F2CA663A4266A7429DA4C", 100050)...)
+	lines = append(lines, newFebraban240ReturnTitle("03", 43, "", 0)...)
+
+	records := Febraban240{}.Read(lines, 2)
+
+	assert.Len(t, records, 2)
+	paid := records[0]
+	assert.NoError(t, paid.Err)
+	assert.Equal(t, 3, paid.Line)
+	assert.Equal(t, lines[1]+"\n"+lines[2], paid.Raw)
+	assert.Equal(t, "06", paid.Code)
+	assert.Equal(t, OccurrencePaid, paid.Occurrence)
+	assert.Equal(t, int64(42), paid.OurNumber)
+	assert.Equal(t, "EA23F2CA663A4266A7429DA4C", paid.ControlNumber)
+	assert.Equal(t, int64(100050), paid.PaidAmountInCents)
+	assert.Equal(t, int64(250), paid.FeeInCents)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), paid.OccurredAt)
+	assert.Equal(t, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), paid.CreditedAt)
+
+	assert.Equal(t, OccurrenceRejected, records[1].Occurrence)
+	assert.Equal(t, "A1", records[1].Reasons)
+	assert.Equal(t, 5, records[1].Line)
+}
+
+func TestFebraban240_ShouldReportSegmentsOutOfOrder(t *testing.T) {
+	title := newFebraban240ReturnTitle("06", 42, "", 100)
+
+	records := Febraban240{}.Read([]string{title[1], title[0]}, 1)
+
+	assert.Len(t, records, 2)
+	assert.ErrorIs(t, records[0].Err, ErrInvalidReturn)
+	assert.Equal(t, 1, records[0].Line)
+	assert.ErrorIs(t, records[1].Err, ErrInvalidReturn)
+	assert.Equal(t, int64(42), records[1].OurNumber)
+}
+
+func TestBradesco400_ShouldReadTypeOneRecords(t *testing.T) {
+	trailer := newRecord(bradesco400Length)
+	trailer.digits(1, 1, "9")
+	lines := []string{newBradesco400ReturnTitle("06", 42, 100050), newBradesco400ReturnTitle("10", 43, 0), string(trailer.line)}
+
+	records := Bradesco400{}.Read(lines, 2)
+
+	assert.Len(t, records, 2)
+	paid := records[0]
+	assert.NoError(t, paid.Err)
+	assert.Equal(t, 2, paid.Line)
+	assert.Equal(t, OccurrencePaid, paid.Occurrence)
+	assert.Equal(t, int64(42), paid.OurNumber)
+	assert.Equal(t, "EA23F2CA663A4266A7429DA4C", paid.ControlNumber)
+	assert.Equal(t, int64(100050), paid.PaidAmountInCents)
+	assert.Equal(t, int64(180), paid.FeeInCents)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), paid.OccurredAt)
+	assert.Equal(t, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), paid.CreditedAt)
+	assert.Equal(t, OccurrenceWrittenOff, records[1].Occurrence)
+}
+
+func TestBradesco400_ShouldReportInvalidFields(t *testing.T) {
+	line := []byte(newBradesco400ReturnTitle("06", 42, 100050))
+	copy(line[253:266], "12X4")
+
+	records := Bradesco400{}.Read([]string{string(line)}, 1)
+
+	assert.Len(t, records, 1)
+	assert.ErrorIs(t, records[0].Err, ErrInvalidField)
+}
+
+func TestReturnReader_ShouldKeepTheSegmentsOfATitleTogether(t *testing.T) {
+	title := newFebraban240ReturnTitle("06", 42, "", 100)
+	content := strings.Join([]string{newFebraban240ReturnHeader(), "lot header", title[0], title[1], "", "lot trailer"}, "\r\n")
+	reader := NewReturnReader(strings.NewReader(content), 64)
+
+	var records []string
+	var lines []int
+	for {
+		record, err := reader.Next()
+		if err != nil {
+			break
+		}
+		records = append(records, record)
+		lines = append(lines, reader.Line())
+	}
+
+	assert.Equal(t, []string{newFebraban240ReturnHeader(), "lot header", title[0] + "\n" + title[1], "", "lot trailer"}, records)
+	assert.Equal(t, []int{1, 2, 3, 5, 6}, lines)
+}
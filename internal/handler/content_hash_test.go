@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentHash_ShouldHashAndRewindContent(t *testing.T) {
+	reader := strings.NewReader("name,debtId\nJohn,debt1\n")
+
+	hash, err := ContentHash(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "914485820df0c9eded212730d40d6b983668110a639026234a6e6c37576117af", hash)
+
+	content, _ := io.ReadAll(reader)
+	assert.Equal(t, "name,debtId\nJohn,debt1\n", string(content))
+}
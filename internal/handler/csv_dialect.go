@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+const DefaultDelimiter = ','
+
+var ErrUnsupportedDelimiter = errors.New("unsupported delimiter")
+
+var delimiterCandidates = []rune{',', ';', '\t', '|'}
+
+const delimiterSniffRecords = 20
+
+func ParseDelimiter(value string) (rune, error) {
+	if strings.EqualFold(value, "tab") || value == `\t` {
+		return '\t', nil
+	}
+	for _, candidate := range delimiterCandidates {
+		if value == string(candidate) {
+			return candidate, nil
+		}
+	}
+	return 0, ErrUnsupportedDelimiter
+}
+
+// SniffDelimiter picks the candidate that splits the header in more than one
+// column and keeps the same column count across most of the sampled records,
+// falling back to DefaultDelimiter when no candidate fits.
+func SniffDelimiter(sample string) rune {
+	sample = completeRecords(sample)
+
+	best, bestConsistent, bestColumns := DefaultDelimiter, -1, 1
+	for _, candidate := range delimiterCandidates {
+		reader := csv.NewReader(strings.NewReader(sample))
+		reader.Comma = candidate
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+
+		header, err := reader.Read()
+		if err != nil || len(header) <= 1 {
+			continue
+		}
+
+		consistent := 0
+		for range delimiterSniffRecords {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				break
+			}
+			if len(record) == len(header) {
+				consistent++
+			}
+		}
+
+		if consistent > bestConsistent || (consistent == bestConsistent && len(header) > bestColumns) {
+			best, bestConsistent, bestColumns = candidate, consistent, len(header)
+		}
+	}
+	return best
+}
+
+// completeRecords drops the last, possibly cut, line of a sample.
+func completeRecords(sample string) string {
+	sample = strings.TrimPrefix(sample, utf8BOM)
+	if i := strings.LastIndexByte(sample, '\n'); i > 0 {
+		return sample[:i+1]
+	}
+	return sample
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniffDelimiter(t *testing.T) {
+	assert.Equal(t, ',', SniffDelimiter("name,email,amount\nJohn,john@example.com,\"1.234,56\"\n"))
+	assert.Equal(t, ';', SniffDelimiter("nome;email;valor\nJoão, o Filho;joao@example.com;1.234,56\nMaria;maria@example.com;10,00\n"))
+	assert.Equal(t, '\t', SniffDelimiter("name\temail\nJohn\tjohn@example.com\n"))
+	assert.Equal(t, '|', SniffDelimiter("\ufeffname|email\nJohn|john@example.com\n"))
+}
+
+func TestSniffDelimiter_ShouldIgnoreLastCutLine(t *testing.T) {
+	assert.Equal(t, ';', SniffDelimiter("nome;email;valor\nJoão;joao@example.com;1,00\nMaria;maria@ex"))
+}
+
+func TestSniffDelimiter_ShouldFallbackToDefault(t *testing.T) {
+	assert.Equal(t, DefaultDelimiter, SniffDelimiter("name\nJohn\n"))
+	assert.Equal(t, DefaultDelimiter, SniffDelimiter(""))
+}
+
+func TestParseDelimiter(t *testing.T) {
+	for value, expected := range map[string]rune{",": ',', ";": ';', "|": '|', "tab": '\t', `\t`: '\t', "\t": '\t'} {
+		delimiter, err := ParseDelimiter(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, delimiter)
+	}
+
+	_, err := ParseDelimiter(":")
+	assert.ErrorIs(t, err, ErrUnsupportedDelimiter)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+const utf8BOM = "\ufeff"
+
+// CSVRecordReader splits a CSV stream into raw records following RFC 4180:
+// line breaks inside quoted fields belong to the record, so a record is never
+// cut in half. Records are returned untouched except for the line terminator.
+type CSVRecordReader struct {
+	reader     *bufio.Reader
+	firstRead  bool
+	lines      int
+	recordLine int
+}
+
+func NewCSVRecordReader(reader io.Reader, bufferSize int) *CSVRecordReader {
+	return &CSVRecordReader{
+		reader:    bufio.NewReaderSize(reader, bufferSize),
+		firstRead: true,
+	}
+}
+
+func (r *CSVRecordReader) Next() (string, error) {
+	var record strings.Builder
+	inQuotes := false
+	r.recordLine = r.lines + 1
+	for {
+		line, err := r.reader.ReadString('\n')
+		if len(line) > 0 {
+			r.lines++
+		}
+		if r.firstRead {
+			line = strings.TrimPrefix(line, utf8BOM)
+			r.firstRead = false
+		}
+
+		// Escaped quotes ("") do not change the parity, so an odd count
+		// toggles whether the record continues on the next line.
+		if strings.Count(line, `"`)%2 == 1 {
+			inQuotes = !inQuotes
+		}
+		record.WriteString(line)
+
+		if err == io.EOF && record.Len() > 0 {
+			return trimLineBreak(record.String()), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if !inQuotes {
+			return trimLineBreak(record.String()), nil
+		}
+	}
+}
+
+// Line returns the line, starting at 1, where the last record returned by Next
+// begins. Records spanning many lines still count every line they take.
+func (r *CSVRecordReader) Line() int {
+	return r.recordLine
+}
+
+func trimLineBreak(record string) string {
+	record = strings.TrimSuffix(record, "\n")
+	return strings.TrimSuffix(record, "\r")
+}
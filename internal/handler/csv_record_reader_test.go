@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readAllRecords(t *testing.T, content string) []string {
+	reader := NewCSVRecordReader(strings.NewReader(content), 16)
+	records := []string{}
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		assert.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestCSVRecordReader_ShouldSplitSimpleRecords(t *testing.T) {
+	records := readAllRecords(t, "name,email\nJohn,john@example.com\nMary,mary@example.com")
+
+	assert.Equal(t, []string{"name,email", "John,john@example.com", "Mary,mary@example.com"}, records)
+}
+
+func TestCSVRecordReader_ShouldKeepQuotedLineBreaksInsideRecord(t *testing.T) {
+	records := readAllRecords(t, "name,address\n\"Santos, Elijah\",\"Rua A\n123\"\nMary,\"Rua \"\"B\"\"\"\n")
+
+	assert.Equal(t, []string{"name,address", "\"Santos, Elijah\",\"Rua A\n123\"", "Mary,\"Rua \"\"B\"\"\""}, records)
+}
+
+func TestCSVRecordReader_ShouldHandleCRLFAndBOM(t *testing.T) {
+	records := readAllRecords(t, "\ufeffname,email\r\nJohn,john@example.com\r\n\r\nMary,\"a\r\nb\"\r\n")
+
+	assert.Equal(t, []string{"name,email", "John,john@example.com", "", "Mary,\"a\r\nb\""}, records)
+}
+
+func TestCSVRecordReader_ShouldReturnReadErrors(t *testing.T) {
+	reader := NewCSVRecordReader(io.MultiReader(strings.NewReader("name"), errReader{}), 16)
+
+	record, err := reader.Next()
+	assert.Equal(t, "", record)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestCSVRecordReader_ShouldTrackRecordStartingLine(t *testing.T) {
+	reader := NewCSVRecordReader(strings.NewReader("name,address\n\"Santos\",\"Rua A\n123\"\n\nMary,B\n"), 16)
+
+	lines := []int{}
+	for {
+		_, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		lines = append(lines, reader.Line())
+	}
+
+	assert.Equal(t, []int{1, 2, 4, 5}, lines)
+}
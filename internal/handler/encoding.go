@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/transform"
+)
+
+type Encoding string
+
+const (
+	EncodingUTF8        Encoding = "UTF-8"
+	EncodingISO88591    Encoding = "ISO-8859-1"
+	EncodingWindows1252 Encoding = "WINDOWS-1252"
+)
+
+var ErrUnsupportedEncoding = errors.New("unsupported encoding")
+
+var encodingAliases = map[string]Encoding{
+	"utf-8":        EncodingUTF8,
+	"utf8":         EncodingUTF8,
+	"iso-8859-1":   EncodingISO88591,
+	"iso8859-1":    EncodingISO88591,
+	"latin1":       EncodingISO88591,
+	"windows-1252": EncodingWindows1252,
+	"cp1252":       EncodingWindows1252,
+}
+
+func ParseEncoding(name string) (Encoding, error) {
+	encoding, ok := encodingAliases[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return "", ErrUnsupportedEncoding
+	}
+	return encoding, nil
+}
+
+// DetectEncoding assumes UTF-8 whenever the sample is valid UTF-8. Otherwise
+// the file is a single byte encoding: bytes in 0x80-0x9F are control
+// characters in ISO-8859-1 but printable in Windows-1252 (€, “, ” ...).
+func DetectEncoding(sample []byte) Encoding {
+	if utf8.Valid(trimIncompleteRune(sample)) {
+		return EncodingUTF8
+	}
+	for _, b := range sample {
+		if b >= 0x80 && b <= 0x9F {
+			return EncodingWindows1252
+		}
+	}
+	return EncodingISO88591
+}
+
+// NewUTF8Reader transcodes the reader content from the given encoding to UTF-8.
+func NewUTF8Reader(reader io.Reader, encoding Encoding) io.Reader {
+	switch encoding {
+	case EncodingISO88591:
+		return transform.NewReader(reader, charmap.ISO8859_1.NewDecoder())
+	case EncodingWindows1252:
+		return transform.NewReader(reader, charmap.Windows1252.NewDecoder())
+	default:
+		return reader
+	}
+}
+
+// PeekSample reads up to size bytes from the reader and returns them together
+// with a reader that yields the whole content again, sample included. A read
+// error other than EOF is replayed once the sample has been consumed.
+func PeekSample(reader io.Reader, size int) ([]byte, io.Reader) {
+	sample := make([]byte, size)
+	n, err := io.ReadFull(reader, sample)
+	sample = sample[:n]
+
+	switch {
+	case err == nil:
+		return sample, io.MultiReader(bytes.NewReader(sample), reader)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return sample, bytes.NewReader(sample)
+	default:
+		return sample, io.MultiReader(bytes.NewReader(sample), &errorReader{err: err})
+	}
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// trimIncompleteRune drops a multi-byte rune cut at the end of the sample.
+func trimIncompleteRune(sample []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(sample); i++ {
+		if utf8.RuneStart(sample[len(sample)-i]) {
+			if !utf8.FullRune(sample[len(sample)-i:]) {
+				return sample[:len(sample)-i]
+			}
+			break
+		}
+	}
+	return sample
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	assert.Equal(t, EncodingUTF8, DetectEncoding([]byte("nome;valor\nJoão;1,00")))
+	assert.Equal(t, EncodingISO88591, DetectEncoding([]byte("nome;valor\nJo\xe3o;1,00")))
+	assert.Equal(t, EncodingWindows1252, DetectEncoding([]byte("nome;valor\n\x93Jo\xe3o\x94;1,00")))
+}
+
+func TestDetectEncoding_ShouldIgnoreRuneCutAtTheEndOfSample(t *testing.T) {
+	sample := []byte("nome\nJoão")
+	assert.Equal(t, EncodingUTF8, DetectEncoding(sample[:len(sample)-2]))
+}
+
+func TestNewUTF8Reader_ShouldTranscodeSingleByteEncodings(t *testing.T) {
+	latin1, err := io.ReadAll(NewUTF8Reader(strings.NewReader("Jo\xe3o Concei\xe7\xe3o"), EncodingISO88591))
+	assert.NoError(t, err)
+	assert.Equal(t, "João Conceição", string(latin1))
+
+	windows1252, err := io.ReadAll(NewUTF8Reader(strings.NewReader("\x93Jos\xe9\x94 \x80"), EncodingWindows1252))
+	assert.NoError(t, err)
+	assert.Equal(t, "“José” €", string(windows1252))
+
+	utf8, err := io.ReadAll(NewUTF8Reader(strings.NewReader("José"), EncodingUTF8))
+	assert.NoError(t, err)
+	assert.Equal(t, "José", string(utf8))
+}
+
+func TestParseEncoding(t *testing.T) {
+	for name, expected := range map[string]Encoding{"UTF-8": EncodingUTF8, "latin1": EncodingISO88591, " cp1252 ": EncodingWindows1252} {
+		encoding, err := ParseEncoding(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, encoding)
+	}
+
+	_, err := ParseEncoding("utf-16")
+	assert.ErrorIs(t, err, ErrUnsupportedEncoding)
+}
+
+func TestPeekSample_ShouldReplayWholeContent(t *testing.T) {
+	sample, reader := PeekSample(strings.NewReader("header\nrow1\nrow2"), 8)
+	assert.Equal(t, "header\nr", string(sample))
+
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "header\nrow1\nrow2", string(content))
+
+	sample, reader = PeekSample(strings.NewReader("short"), 8)
+	assert.Equal(t, "short", string(sample))
+	content, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "short", string(content))
+}
+
+func TestPeekSample_ShouldReplayReadErrorAfterSample(t *testing.T) {
+	sample, reader := PeekSample(io.MultiReader(strings.NewReader("header\n"), &errorReader{err: assert.AnError}), 64)
+	assert.Equal(t, "header\n", string(sample))
+
+	content, err := io.ReadAll(reader)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, "header\n", string(content))
+}
@@ -1,6 +1,12 @@
 package handler
 
-import "io"
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrSavedFileNotFound = errors.New("saved file not found")
 
 type File interface {
 	FileName() string
@@ -10,10 +16,16 @@ type File interface {
 
 type SavedFile interface {
 	Delete()
+	Close()
+	// Release is called once the file is no longer being read. Whether it's
+	// deleted or kept depends on the retention policy of its storage.
+	Release()
 	Open() io.Reader
 	Filepath() string
 }
 
 type FileHandler interface {
 	SaveFile(file File) (savedFile SavedFile, err error)
+	OpenFile(path string) (savedFile SavedFile, err error)
+	PurgeExpired(now time.Time) (purged int, err error)
 }
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// LineRecordReader splits a stream into lines, for fixed-width files where
+// quotes mean nothing and every record takes exactly one line.
+type LineRecordReader struct {
+	reader    *bufio.Reader
+	firstRead bool
+	lines     int
+}
+
+func NewLineRecordReader(reader io.Reader, bufferSize int) *LineRecordReader {
+	return &LineRecordReader{
+		reader:    bufio.NewReaderSize(reader, bufferSize),
+		firstRead: true,
+	}
+}
+
+func (r *LineRecordReader) Next() (string, error) {
+	line, err := r.reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return "", err
+	}
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	r.lines++
+	if r.firstRead {
+		line = strings.TrimPrefix(line, utf8BOM)
+		r.firstRead = false
+	}
+	return trimLineBreak(line), nil
+}
+
+// Line returns the line, starting at 1, of the last record returned by Next.
+func (r *LineRecordReader) Line() int {
+	return r.lines
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineRecordReader_ShouldSplitLinesIgnoringQuotes(t *testing.T) {
+	reader := NewLineRecordReader(strings.NewReader("\ufeff1JOHN \"JR\"   \r\n\r\n1MARY        "), 16)
+
+	records, lines := []string{}, []int{}
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		records = append(records, record)
+		lines = append(lines, reader.Line())
+	}
+
+	assert.Equal(t, []string{"1JOHN \"JR\"   ", "", "1MARY        "}, records)
+	assert.Equal(t, []int{1, 2, 3}, lines)
+}
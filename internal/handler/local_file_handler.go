@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const DefaultLocalStorageDir = "./uploads"
+
+type LocalFileHandler struct {
+	dir       string
+	retention RetentionPolicy
+}
+
+func NewLocalFileHandler(dir string, retention RetentionPolicy) FileHandler {
+	return &LocalFileHandler{
+		dir:       dir,
+		retention: retention,
+	}
+}
+
+func (s *LocalFileHandler) SaveFile(file File) (savedFile SavedFile, err error) {
+	defer file.Close()
+
+	err = os.MkdirAll(s.dir, os.ModePerm)
+	if err != nil {
+		log.Printf("Error creating storage directory %s: %v", s.dir, err)
+		return nil, err
+	}
+
+	savedFilePath := filepath.Join(s.dir, savedFileName(file.FileName()))
+	dst, err := os.Create(savedFilePath)
+	if err != nil {
+		log.Printf("Error creating file %s: %v", savedFilePath, err)
+		return nil, err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, file.Reader())
+	if err != nil {
+		log.Printf("Error saving file %s: %v", savedFilePath, err)
+		return nil, err
+	}
+
+	return s.OpenFile(savedFilePath)
+}
+
+// OpenFile opens a file saved by this handler, paths outside of its directory
+// are never opened.
+func (s *LocalFileHandler) OpenFile(path string) (savedFile SavedFile, err error) {
+	relativePath, err := filepath.Rel(s.dir, path)
+	if err != nil || relativePath == "." || strings.HasPrefix(relativePath, "..") {
+		return nil, ErrSavedFileNotFound
+	}
+
+	savedOsFile, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrSavedFileNotFound
+	}
+	if err != nil {
+		log.Printf("Error opening file %s: %v", path, err)
+		return nil, err
+	}
+
+	return NewOsFile(savedOsFile, s.retention), nil
+}
+
+func (s *LocalFileHandler) PurgeExpired(now time.Time) (purged int, err error) {
+	if !s.retention.keepsReleasedFiles() {
+		return 0, nil
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || !s.retention.expired(info.ModTime(), now) {
+			continue
+		}
+		err = os.Remove(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestLocalFileHandlerSuit struct {
+	suite.Suite
+	dir              string
+	localFileHandler FileHandler
+}
+
+func (testSuit *TestLocalFileHandlerSuit) SetupTest() {
+	testSuit.dir = testSuit.T().TempDir()
+	testSuit.localFileHandler = NewLocalFileHandler(testSuit.dir, NewRetentionPolicy(1))
+}
+
+func TestLocalFileHandler(t *testing.T) {
+	suite.Run(t, new(TestLocalFileHandlerSuit))
+}
+
+func (s *TestLocalFileHandlerSuit) TestLocalFileHandler_ShouldSaveCorrectly() {
+	mockFile := NewMockFile("test_file.csv", []byte("any_file"))
+
+	savedFile, err := s.localFileHandler.SaveFile(mockFile)
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), savedFile)
+	defer savedFile.Close()
+
+	// Verifica se o arquivo foi salvo corretamente
+	assert.Equal(s.T(), s.dir, filepath.Dir(savedFile.Filepath()))
+	assert.Regexp(s.T(), `^test_file_[0-9a-f-]{36}\.csv$`, filepath.Base(savedFile.Filepath()))
+	content, err := io.ReadAll(savedFile.Open())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "any_file", string(content))
+}
+
+func (s *TestLocalFileHandlerSuit) TestLocalFileHandler_ShouldKeepReleasedFileWhileRetained() {
+	savedFile, err := s.localFileHandler.SaveFile(NewMockFile("test_file.csv", []byte("any_file")))
+	assert.NoError(s.T(), err)
+
+	savedFile.Release()
+
+	reopenedFile, err := s.localFileHandler.OpenFile(savedFile.Filepath())
+	assert.NoError(s.T(), err)
+	defer reopenedFile.Close()
+	content, _ := io.ReadAll(reopenedFile.Open())
+	assert.Equal(s.T(), "any_file", string(content))
+}
+
+func (s *TestLocalFileHandlerSuit) TestLocalFileHandler_ShouldDeleteReleasedFileWithoutRetention() {
+	localFileHandler := NewLocalFileHandler(s.dir, RetentionPolicy{})
+	savedFile, err := localFileHandler.SaveFile(NewMockFile("test_file.csv", []byte("any_file")))
+	assert.NoError(s.T(), err)
+
+	savedFile.Release()
+
+	_, err = os.Stat(savedFile.Filepath())
+	assert.ErrorIs(s.T(), err, os.ErrNotExist)
+}
+
+func (s *TestLocalFileHandlerSuit) TestLocalFileHandler_ShouldNotOpenFilesOutsideOfItsDirectory() {
+	_, err := s.localFileHandler.OpenFile(filepath.Join(s.dir, "..", "any_file.csv"))
+	assert.ErrorIs(s.T(), err, ErrSavedFileNotFound)
+
+	_, err = s.localFileHandler.OpenFile(filepath.Join(s.dir, "missing.csv"))
+	assert.ErrorIs(s.T(), err, ErrSavedFileNotFound)
+}
+
+func (s *TestLocalFileHandlerSuit) TestLocalFileHandler_ShouldPurgeOnlyExpiredFiles() {
+	expiredFile, _ := s.localFileHandler.SaveFile(NewMockFile("expired.csv", []byte("any_file")))
+	expiredFile.Release()
+	recentFile, _ := s.localFileHandler.SaveFile(NewMockFile("recent.csv", []byte("any_file")))
+	recentFile.Release()
+	os.Chtimes(expiredFile.Filepath(), time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour))
+
+	purged, err := s.localFileHandler.PurgeExpired(time.Now())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, purged)
+
+	_, err = os.Stat(expiredFile.Filepath())
+	assert.ErrorIs(s.T(), err, os.ErrNotExist)
+	_, err = os.Stat(recentFile.Filepath())
+	assert.NoError(s.T(), err)
+}
+
+type MockFile struct {
+	fileName string
+	content  []byte
+}
+
+func NewMockFile(fileName string, content []byte) *MockFile {
+	return &MockFile{
+		fileName: fileName,
+		content:  content,
+	}
+}
+
+func (m *MockFile) FileName() string {
+	return m.fileName
+}
+
+func (m *MockFile) Reader() io.Reader {
+	return bytes.NewReader(m.content)
+}
+
+func (m *MockFile) Close() {
+}
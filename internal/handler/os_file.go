@@ -6,12 +6,14 @@ import (
 )
 
 type OsFile struct {
-	file *os.File
+	file      *os.File
+	retention RetentionPolicy
 }
 
-func NewOsFile(file *os.File) *OsFile {
+func NewOsFile(file *os.File, retention RetentionPolicy) *OsFile {
 	return &OsFile{
-		file: file,
+		file:      file,
+		retention: retention,
 	}
 }
 
@@ -20,6 +22,18 @@ func (s *OsFile) Delete() {
 	os.Remove(s.file.Name())
 }
 
+func (s *OsFile) Close() {
+	s.file.Close()
+}
+
+func (s *OsFile) Release() {
+	if s.retention.keepsReleasedFiles() {
+		s.Close()
+		return
+	}
+	s.Delete()
+}
+
 func (s *OsFile) Open() io.Reader {
 	return s.file
 }
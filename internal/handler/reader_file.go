@@ -0,0 +1,29 @@
+package handler
+
+import "io"
+
+// ReaderFile is a file whose content is only available as a stream, like a
+// part of a multipart request being read.
+type ReaderFile struct {
+	fileName string
+	reader   io.ReadCloser
+}
+
+func NewReaderFile(fileName string, reader io.ReadCloser) *ReaderFile {
+	return &ReaderFile{
+		fileName: fileName,
+		reader:   reader,
+	}
+}
+
+func (s *ReaderFile) FileName() string {
+	return s.fileName
+}
+
+func (s *ReaderFile) Close() {
+	s.reader.Close()
+}
+
+func (s *ReaderFile) Reader() io.Reader {
+	return s.reader
+}
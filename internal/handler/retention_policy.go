@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionPolicy tells how long saved files are kept after being released. A
+// zero period deletes them as soon as they are released.
+type RetentionPolicy struct {
+	Period time.Duration
+}
+
+func NewRetentionPolicy(days int) RetentionPolicy {
+	return RetentionPolicy{Period: time.Duration(days) * 24 * time.Hour}
+}
+
+func (p RetentionPolicy) keepsReleasedFiles() bool {
+	return p.Period > 0
+}
+
+func (p RetentionPolicy) expired(savedAt time.Time, now time.Time) bool {
+	return p.keepsReleasedFiles() && savedAt.Before(now.Add(-p.Period))
+}
+
+// savedFileName keeps the name and extension of the uploaded file, so saved
+// files stay recognizable, and adds an unique suffix.
+func savedFileName(fileName string) string {
+	fileName = filepath.Base(fileName)
+	extension := filepath.Ext(fileName)
+	return strings.TrimSuffix(fileName, extension) + "_" + uuid.NewString() + extension
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log"
+	"path"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// s3PartSize bounds the memory used to upload files of unknown size, which
+// are sent in parts of this size.
+const s3PartSize = 16 << 20
+
+// S3FileHandler saves files in an S3 compatible bucket, so every API replica
+// and worker sharing the bucket can read them.
+type S3FileHandler struct {
+	client    *minio.Client
+	bucket    string
+	prefix    string
+	retention RetentionPolicy
+}
+
+func NewS3FileHandler(client *minio.Client, bucket string, prefix string, retention RetentionPolicy) *S3FileHandler {
+	return &S3FileHandler{
+		client:    client,
+		bucket:    bucket,
+		prefix:    prefix,
+		retention: retention,
+	}
+}
+
+// EnsureBucket creates the bucket when it doesn't exist yet.
+func (s *S3FileHandler) EnsureBucket(ctx context.Context, region string) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil || exists {
+		return err
+	}
+	return s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{Region: region})
+}
+
+func (s *S3FileHandler) SaveFile(file File) (savedFile SavedFile, err error) {
+	defer file.Close()
+
+	key := path.Join(s.prefix, savedFileName(file.FileName()))
+	_, err = s.client.PutObject(context.Background(), s.bucket, key, file.Reader(), -1, minio.PutObjectOptions{
+		PartSize:    s3PartSize,
+		ContentType: "text/csv",
+	})
+	if err != nil {
+		log.Printf("Error saving file %s to bucket %s: %v", key, s.bucket, err)
+		return nil, err
+	}
+
+	return s.newSavedFile(key), nil
+}
+
+func (s *S3FileHandler) OpenFile(key string) (savedFile SavedFile, err error) {
+	_, err = s.client.StatObject(context.Background(), s.bucket, key, minio.StatObjectOptions{})
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil, ErrSavedFileNotFound
+	}
+	if err != nil {
+		log.Printf("Error opening file %s from bucket %s: %v", key, s.bucket, err)
+		return nil, err
+	}
+	return s.newSavedFile(key), nil
+}
+
+func (s *S3FileHandler) PurgeExpired(now time.Time) (purged int, err error) {
+	if !s.retention.keepsReleasedFiles() {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	objects := s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s.prefix, Recursive: true})
+	for object := range objects {
+		if object.Err != nil {
+			return purged, object.Err
+		}
+		if !s.retention.expired(object.LastModified, now) {
+			continue
+		}
+		err = s.client.RemoveObject(ctx, s.bucket, object.Key, minio.RemoveObjectOptions{})
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (s *S3FileHandler) newSavedFile(key string) *S3SavedFile {
+	return &S3SavedFile{
+		client:    s.client,
+		bucket:    s.bucket,
+		key:       key,
+		retention: s.retention,
+	}
+}
+
+type S3SavedFile struct {
+	client    *minio.Client
+	bucket    string
+	key       string
+	retention RetentionPolicy
+	object    *minio.Object
+}
+
+// Open downloads the object as it is read, errors come up on the first read.
+func (s *S3SavedFile) Open() io.Reader {
+	object, err := s.client.GetObject(context.Background(), s.bucket, s.key, minio.GetObjectOptions{})
+	if err != nil {
+		return &failedReader{err: err}
+	}
+	s.object = object
+	return object
+}
+
+func (s *S3SavedFile) Close() {
+	if s.object != nil {
+		s.object.Close()
+	}
+}
+
+func (s *S3SavedFile) Delete() {
+	s.Close()
+	err := s.client.RemoveObject(context.Background(), s.bucket, s.key, minio.RemoveObjectOptions{})
+	if err != nil {
+		log.Printf("Error deleting file %s from bucket %s: %v", s.key, s.bucket, err)
+	}
+}
+
+func (s *S3SavedFile) Release() {
+	if s.retention.keepsReleasedFiles() {
+		s.Close()
+		return
+	}
+	s.Delete()
+}
+
+func (s *S3SavedFile) Filepath() string {
+	return s.key
+}
+
+type failedReader struct {
+	err error
+}
+
+func (r *failedReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
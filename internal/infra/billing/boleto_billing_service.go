@@ -0,0 +1,71 @@
+package billing
+
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/pix"
+)
+
+// BoletoBillingService issues a FEBRABAN boleto for each bank slip with the
+// account of its beneficiary, and a Pix BR Code for the same charge when a Pix
+// merchant is configured.
+type BoletoBillingService struct {
+	beneficiaryRepository bankSlipEntities.BeneficiaryRepository
+	pixMerchant           *pix.Merchant
+}
+
+type GenerateBillingData struct {
+	AmountInCents int64
+	DueDate       string
+	Customer      string
+	Barcode       string
+	PixPayload    string
+}
+
+// NewBoletoBillingService issues no Pix BR Code when pixMerchant is nil.
+func NewBoletoBillingService(beneficiaryRepository bankSlipEntities.BeneficiaryRepository, pixMerchant *pix.Merchant) *BoletoBillingService {
+	return &BoletoBillingService{beneficiaryRepository: beneficiaryRepository, pixMerchant: pixMerchant}
+}
+
+func (s BoletoBillingService) GenerateBiling(
+	bankSlips *map[bankSlipEntities.DebitId]*bankSlipEntities.BankSlip,
+) *map[bankSlipEntities.DebitId]error {
+	billingErrors := map[bankSlipEntities.DebitId]error{}
+	toApi := map[string]any{}
+	issuers := bankSlipEntities.NewBeneficiaryIssuers(s.beneficiaryRepository)
+
+	for _, entity := range *bankSlips {
+		issuer, err := issuers.Get(entity.BeneficiaryId)
+		if err != nil {
+			billingErrors[entity.DebtId] = err
+			continue
+		}
+		issued, err := issuer.Issue(entity.OurNumber, entity.DebtDueDate, entity.DebtAmount.Centavos())
+		if err != nil {
+			billingErrors[entity.DebtId] = err
+			continue
+		}
+		entity.AttachBoleto(issued.Barcode, issued.DigitableLine)
+
+		if s.pixMerchant != nil {
+			txId, err := pix.TxIdFromDebtId(entity.DebtId)
+			if err != nil {
+				billingErrors[entity.DebtId] = err
+				continue
+			}
+			entity.AttachPix(s.pixMerchant.Payload(txId, entity.DebtAmount.Centavos()))
+		}
+
+		toApi[entity.DebtId] = GenerateBillingData{
+			AmountInCents: entity.DebtAmount.Centavos(),
+			DueDate:       entity.DebtDueDate.Format("2006-01-02"),
+			Customer:      entity.UserEmail,
+			Barcode:       entity.Barcode,
+			PixPayload:    entity.PixPayload,
+		}
+	}
+
+	// communicate to the billing api
+	// with the data in toApi
+
+	return &billingErrors
+}
@@ -1,15 +1,29 @@
 package email
 
-import bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+import (
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"time"
+)
 
 type EmailTemplate = string
 
 var (
 	BILLING_WAITING_PAYMENT EmailTemplate = "billing_waiting_payment"
+	BILLING_DUE_REMINDER    EmailTemplate = "billing_due_reminder"
+	BILLING_OVERDUE_NOTICE  EmailTemplate = "billing_overdue_notice"
 )
 
 type EmailService interface {
 	SendBankSlipWaitingPaymentEmail(
 		data *bankSlipEntities.BankSlipMap,
 	) *map[bankSlipEntities.DebitId]error
+	// SendBankSlipDunningEmail sends the reminders before the due date and the
+	// overdue notices after it, with the amount due on date. The template is
+	// the one of the step, BILLING_DUE_REMINDER or BILLING_OVERDUE_NOTICE with
+	// the days from the due date, as billing_overdue_notice_7.
+	SendBankSlipDunningEmail(
+		data *bankSlipEntities.BankSlipMap,
+		template EmailTemplate,
+		date time.Time,
+	) *map[bankSlipEntities.DebitId]error
 }
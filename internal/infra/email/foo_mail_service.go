@@ -1,20 +1,46 @@
 package email
 
-import bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+import (
+	"bytes"
+	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"performatic-file-processor/internal/boleto"
+	"performatic-file-processor/internal/calendar"
+	"performatic-file-processor/internal/qrcode"
+	"strings"
+	"time"
+)
+
+const (
+	pixQRCodeScale  = 6
+	pixQRCodeBorder = 4
+)
+
+var dunningSubjects = map[EmailTemplate]string{
+	BILLING_DUE_REMINDER:   "Billing Due Soon",
+	BILLING_OVERDUE_NOTICE: "Billing Overdue",
+}
 
 type FooSendMail struct {
+	businessDays *calendar.Calendar
 }
 
 type SentEmailData struct {
 	To       string
 	Subject  string
 	Body     string
+	Amount   string
 	DueDate  string
 	Customer string
+	// DigitableLine is the typeable line the customer pays the bank slip with.
+	DigitableLine string
+	// PixCopyAndPaste is the Pix "copia e cola" BR Code and PixQRCode its PNG
+	// QR code, both empty when Pix is not configured.
+	PixCopyAndPaste string
+	PixQRCode       []byte
 }
 
-func NewFooSendMailService() *FooSendMail {
-	return &FooSendMail{}
+func NewFooSendMailService(businessDays *calendar.Calendar) *FooSendMail {
+	return &FooSendMail{businessDays: businessDays}
 }
 
 func (s *FooSendMail) sendMail(_ map[bankSlipEntities.DebitId]SentEmailData, _ []EmailTemplate) error {
@@ -26,13 +52,23 @@ func (s *FooSendMail) SendBankSlipWaitingPaymentEmail(
 	data *map[bankSlipEntities.DebitId]*bankSlipEntities.BankSlip,
 ) *map[bankSlipEntities.DebitId]error {
 	toApi := map[bankSlipEntities.DebitId]SentEmailData{}
+	emailErrors := map[bankSlipEntities.DebitId]error{}
 	for _, entity := range *data {
+		pixQRCode, err := pixQRCodePNG(entity.PixPayload)
+		if err != nil {
+			emailErrors[entity.DebtId] = err
+			continue
+		}
 		toApi[entity.DebtId] = SentEmailData{
-			To:       entity.UserEmail,
-			Subject:  "Billing Waiting Payment",
-			Body:     "Your billing is waiting for payment",
-			DueDate:  entity.DebtDueDate.String(),
-			Customer: entity.UserName,
+			To:              entity.UserEmail,
+			Subject:         "Billing Waiting Payment",
+			Body:            "Your billing is waiting for payment",
+			Amount:          entity.DebtAmount.BRL(),
+			DueDate:         entity.DebtDueDate.String(),
+			Customer:        entity.UserName,
+			DigitableLine:   boleto.FormatDigitableLine(entity.DigitableLine),
+			PixCopyAndPaste: entity.PixPayload,
+			PixQRCode:       pixQRCode,
 		}
 	}
 
@@ -41,5 +77,63 @@ func (s *FooSendMail) SendBankSlipWaitingPaymentEmail(
 
 	s.sendMail(toApi, []EmailTemplate{BILLING_WAITING_PAYMENT})
 
-	return &map[bankSlipEntities.DebitId]error{}
+	return &emailErrors
+}
+
+func (s *FooSendMail) SendBankSlipDunningEmail(
+	data *map[bankSlipEntities.DebitId]*bankSlipEntities.BankSlip,
+	template EmailTemplate,
+	date time.Time,
+) *map[bankSlipEntities.DebitId]error {
+	toApi := map[bankSlipEntities.DebitId]SentEmailData{}
+	emailErrors := map[bankSlipEntities.DebitId]error{}
+	for _, entity := range *data {
+		pixQRCode, err := pixQRCodePNG(entity.PixPayload)
+		if err != nil {
+			emailErrors[entity.DebtId] = err
+			continue
+		}
+		// Overdue bank slips are paid with the late fee and interest of the day.
+		toApi[entity.DebtId] = SentEmailData{
+			To:              entity.UserEmail,
+			Subject:         dunningSubject(template),
+			Body:            "Your billing is waiting for payment",
+			Amount:          entity.AmountDue(date, s.businessDays).Total().BRL(),
+			DueDate:         entity.DebtDueDate.String(),
+			Customer:        entity.UserName,
+			DigitableLine:   boleto.FormatDigitableLine(entity.DigitableLine),
+			PixCopyAndPaste: entity.PixPayload,
+			PixQRCode:       pixQRCode,
+		}
+	}
+
+	s.sendMail(toApi, []EmailTemplate{template})
+
+	return &emailErrors
+}
+
+// dunningSubject is the subject of the kind of the step template, as
+// billing_overdue_notice for billing_overdue_notice_7.
+func dunningSubject(template EmailTemplate) string {
+	for kindTemplate, subject := range dunningSubjects {
+		if strings.HasPrefix(template, kindTemplate+"_") {
+			return subject
+		}
+	}
+	return ""
+}
+
+func pixQRCodePNG(payload string) ([]byte, error) {
+	if payload == "" {
+		return nil, nil
+	}
+	qr, err := qrcode.Encode(payload)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := qr.PNG(&buffer, pixQRCodeScale, pixQRCodeBorder); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
 }
@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+var ErrShuttingDown = errors.New("background jobs are shutting down")
+
+type Runner interface {
+	Go(job func(ctx context.Context), onPanic func()) error
+}
+
+type BackgroundJobs struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	closed  bool
+	running sync.WaitGroup
+}
+
+var (
+	instance     *BackgroundJobs
+	instanceOnce sync.Once
+)
+
+func NewBackgroundJobs() *BackgroundJobs {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BackgroundJobs{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// GetInstance returns the process wide job tracker, so the HTTP handlers and
+// the graceful shutdown in cmd/api share the same jobs.
+func GetInstance() *BackgroundJobs {
+	instanceOnce.Do(func() {
+		instance = NewBackgroundJobs()
+	})
+	return instance
+}
+
+// Go runs the job in the background. onPanic, when given, runs after a panic of
+// the job is recovered, so the job can leave what it was working on in a
+// final state.
+func (b *BackgroundJobs) Go(job func(ctx context.Context), onPanic func()) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrShuttingDown
+	}
+
+	b.running.Add(1)
+	go func() {
+		defer b.running.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Background job panicked: %v\n%s", r, debug.Stack())
+				if onPanic != nil {
+					onPanic()
+				}
+			}
+		}()
+		job(b.ctx)
+	}()
+	return nil
+}
+
+// Shutdown stops accepting new jobs and waits for the running ones. When ctx
+// expires first, the jobs context is cancelled and ctx.Err() is returned.
+func (b *BackgroundJobs) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundJobs_ShutdownShouldWaitRunningJobs(t *testing.T) {
+	jobs := NewBackgroundJobs()
+	var finished atomic.Bool
+
+	err := jobs.Go(func(ctx context.Context) {
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(true)
+	}, nil)
+	assert.NoError(t, err)
+
+	err = jobs.Shutdown(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, finished.Load())
+}
+
+func TestBackgroundJobs_ShouldRejectJobsAfterShutdown(t *testing.T) {
+	jobs := NewBackgroundJobs()
+
+	err := jobs.Shutdown(context.Background())
+	assert.NoError(t, err)
+
+	err = jobs.Go(func(ctx context.Context) {}, nil)
+	assert.ErrorIs(t, err, ErrShuttingDown)
+}
+
+func TestBackgroundJobs_ShouldCancelJobsWhenShutdownTimesOut(t *testing.T) {
+	jobs := NewBackgroundJobs()
+	cancelled := make(chan struct{})
+
+	err := jobs.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	}, nil)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = jobs.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("job context was not cancelled")
+	}
+}
+
+func TestBackgroundJobs_ShouldRecoverFromPanickingJobs(t *testing.T) {
+	jobs := NewBackgroundJobs()
+
+	var recovered atomic.Bool
+
+	err := jobs.Go(func(ctx context.Context) {
+		panic("any_panic")
+	}, func() {
+		recovered.Store(true)
+	})
+	assert.NoError(t, err)
+
+	err = jobs.Shutdown(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, recovered.Load())
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"maps"
 	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -19,3 +20,14 @@ func (m *EmailServicesMock) SendBankSlipWaitingPaymentEmail(
 	args := m.Called(&bankSlipCopy)
 	return args.Get(0).(*map[bankSlipEntities.DebitId]error)
 }
+
+func (m *EmailServicesMock) SendBankSlipDunningEmail(
+	bankSlip *bankSlipEntities.BankSlipMap,
+	template string,
+	date time.Time,
+) *map[bankSlipEntities.DebitId]error {
+	bankSlipCopy := make(bankSlipEntities.BankSlipMap)
+	maps.Copy(bankSlipCopy, *bankSlip)
+	args := m.Called(&bankSlipCopy, template, date)
+	return args.Get(0).(*map[bankSlipEntities.DebitId]error)
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"mime/multipart"
 	fileHandler "performatic-file-processor/internal/handler"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -49,6 +50,21 @@ func (m *FileHandlerMock) SaveFile(file fileHandler.File) (savedFile fileHandler
 	return arg0, args.Error(1)
 }
 
+func (m *FileHandlerMock) OpenFile(path string) (savedFile fileHandler.SavedFile, err error) {
+	args := m.Called(path)
+	var arg0 fileHandler.SavedFile = nil
+	if args.Get(0) != nil {
+		arg0 = args.Get(0).(fileHandler.SavedFile)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (m *FileHandlerMock) PurgeExpired(now time.Time) (purged int, err error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
+
 func CreateMultipartFileMock(fileName string, fileContent []byte) (multipart.File, *multipart.FileHeader, error) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
@@ -102,6 +118,14 @@ func (m *SavedFileMock) Delete() {
 	m.Called()
 }
 
+func (m *SavedFileMock) Close() {
+	m.Called()
+}
+
+func (m *SavedFileMock) Release() {
+	m.Called()
+}
+
 func (m *SavedFileMock) Open() io.Reader {
 	args := m.Called()
 	return args.Get(0).(io.Reader)
@@ -0,0 +1,18 @@
+package pix
+
+// crc16 is the CRC16-CCITT (polynomial 0x1021, initial value 0xFFFF) the BR
+// Code uses as its last field.
+func crc16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := range len(data) {
+		crc ^= uint16(data[i]) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,143 @@
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"performatic-file-processor/internal/ascii"
+)
+
+// EMV MPM ids used by the Pix BR Code.
+const (
+	idPayloadFormatIndicator  = "00"
+	idPointOfInitiationMethod = "01"
+	idMerchantAccountInfo     = "26"
+	idMerchantAccountGUI      = "00"
+	idMerchantAccountKey      = "01"
+	idMerchantAccountURL      = "25"
+	idMerchantCategoryCode    = "52"
+	idTransactionCurrency     = "53"
+	idTransactionAmount       = "54"
+	idCountryCode             = "58"
+	idMerchantName            = "59"
+	idMerchantCity            = "60"
+	idAdditionalDataField     = "62"
+	idAdditionalDataTxId      = "05"
+	idCRC16                   = "63"
+)
+
+const (
+	pixGUI       = "br.gov.bcb.pix"
+	currencyReal = "986"
+	// A dynamic BR Code points to a charge that can only be paid once.
+	singleUsePointOfInitiation = "12"
+	// The txid of a dynamic BR Code is in its location.
+	dynamicTxId             = "***"
+	locationTxIdPlaceholder = "{txid}"
+
+	maxMerchantNameLength       = 25
+	maxMerchantCityLength       = 15
+	maxMerchantAccountURLLength = 77
+	maxStaticTxIdLength         = 25
+)
+
+var (
+	ErrInvalidMerchant = errors.New("invalid pix merchant")
+	ErrInvalidTxId     = errors.New("invalid pix txid")
+)
+
+// Merchant is who receives the Pix. A static BR Code carries the Pix key, a
+// dynamic one carries the location URL of the charge at the PSP, which may
+// have a {txid} placeholder.
+type Merchant struct {
+	Key         string
+	LocationURL string
+	Name        string
+	City        string
+}
+
+// NewMerchant validates the merchant. Name and city are written without
+// accents, as BR Code readers expect.
+func NewMerchant(key, locationURL, name, city string) (Merchant, error) {
+	key = strings.TrimSpace(key)
+	locationURL = strings.TrimPrefix(strings.TrimSpace(locationURL), "https://")
+	if (key == "") == (locationURL == "") {
+		return Merchant{}, fmt.Errorf("%w: either a key or a location URL must be configured", ErrInvalidMerchant)
+	}
+	if len(locationURL) > maxMerchantAccountURLLength {
+		return Merchant{}, fmt.Errorf("%w: location URL longer than %d characters", ErrInvalidMerchant, maxMerchantAccountURLLength)
+	}
+
+	name = truncate(ascii.WithoutAccents(name), maxMerchantNameLength)
+	city = truncate(ascii.WithoutAccents(city), maxMerchantCityLength)
+	if name == "" || city == "" {
+		return Merchant{}, fmt.Errorf("%w: name and city are required", ErrInvalidMerchant)
+	}
+	return Merchant{Key: key, LocationURL: locationURL, Name: name, City: city}, nil
+}
+
+func (m Merchant) Dynamic() bool {
+	return m.LocationURL != ""
+}
+
+// TxIdFromDebtId keeps the letters and digits of the debt id, up to the 25
+// characters a static BR Code accepts.
+func TxIdFromDebtId(debtId string) (string, error) {
+	txId := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, debtId)
+	if txId == "" {
+		return "", fmt.Errorf("%w: %q has no letters or digits", ErrInvalidTxId, debtId)
+	}
+	return truncate(txId, maxStaticTxIdLength), nil
+}
+
+// Payload builds the "copia e cola" BR Code of a charge, amountInCents 0 lets
+// the payer type the amount.
+func (m Merchant) Payload(txId string, amountInCents int64) string {
+	accountInfo := tlv(idMerchantAccountGUI, pixGUI)
+	additionalData := tlv(idAdditionalDataTxId, txId)
+	if m.Dynamic() {
+		accountInfo += tlv(idMerchantAccountURL, strings.ReplaceAll(m.LocationURL, locationTxIdPlaceholder, txId))
+		additionalData = tlv(idAdditionalDataTxId, dynamicTxId)
+	} else {
+		accountInfo += tlv(idMerchantAccountKey, m.Key)
+	}
+
+	var payload strings.Builder
+	payload.WriteString(tlv(idPayloadFormatIndicator, "01"))
+	if m.Dynamic() {
+		payload.WriteString(tlv(idPointOfInitiationMethod, singleUsePointOfInitiation))
+	}
+	payload.WriteString(tlv(idMerchantAccountInfo, accountInfo))
+	payload.WriteString(tlv(idMerchantCategoryCode, "0000"))
+	payload.WriteString(tlv(idTransactionCurrency, currencyReal))
+	if amountInCents > 0 {
+		payload.WriteString(tlv(idTransactionAmount, fmt.Sprintf("%d.%02d", amountInCents/100, amountInCents%100)))
+	}
+	payload.WriteString(tlv(idCountryCode, "BR"))
+	payload.WriteString(tlv(idMerchantName, m.Name))
+	payload.WriteString(tlv(idMerchantCity, m.City))
+	payload.WriteString(tlv(idAdditionalDataField, additionalData))
+
+	// The checksum covers everything up to its own id and length.
+	payload.WriteString(idCRC16 + "04")
+	fmt.Fprintf(&payload, "%04X", crc16(payload.String()))
+	return payload.String()
+}
+
+func tlv(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length]
+}
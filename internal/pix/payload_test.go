@@ -0,0 +1,82 @@
+package pix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrc16(t *testing.T) {
+	assert.Equal(t, uint16(0x29B1), crc16("123456789"))
+}
+
+// Example of the BR Code manual of the Central Bank.
+func TestMerchant_Payload(t *testing.T) {
+	merchant, err := NewMerchant("123e4567-e12b-12d1-a456-426655440000", "", "Fulano de Tal", "BRASILIA")
+	assert.NoError(t, err)
+
+	assert.Equal(t,
+		"00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		merchant.Payload("***", 0),
+	)
+}
+
+func TestMerchant_Payload_ShouldCarryAmountAndTxId(t *testing.T) {
+	merchant, err := NewMerchant("11222333000181", "", "Cobranças Ação Ltda", "São Paulo")
+	assert.NoError(t, err)
+
+	payload := merchant.Payload("ea23f2ca663a4266a7429da4c", 100050)
+	assert.Equal(t,
+		"00020126360014br.gov.bcb.pix01141122233300018152040000530398654071000.505802BR5919Cobrancas Acao Ltda6009Sao Paulo62290525ea23f2ca663a4266a7429da4c6304",
+		payload[:len(payload)-4],
+	)
+	assert.Equal(t, payload[len(payload)-4:], crcOf(payload))
+}
+
+func TestMerchant_Payload_ShouldPointDynamicBrCodeToLocation(t *testing.T) {
+	merchant, err := NewMerchant("", "https://pix.example.com/qr/v2/{txid}", "Fulano de Tal", "BRASILIA")
+	assert.NoError(t, err)
+
+	payload := merchant.Payload("abc123", 1000)
+	assert.Contains(t, payload, "010212")
+	assert.Contains(t, payload, "2528pix.example.com/qr/v2/abc123")
+	assert.Contains(t, payload, "62070503***")
+	assert.Contains(t, payload, "540510.00")
+	assert.Equal(t, payload[len(payload)-4:], crcOf(payload))
+}
+
+func TestNewMerchant_ShouldRejectInvalidConfiguration(t *testing.T) {
+	_, err := NewMerchant("", "", "Fulano de Tal", "BRASILIA")
+	assert.ErrorIs(t, err, ErrInvalidMerchant)
+	_, err = NewMerchant("key", "pix.example.com/qr", "Fulano de Tal", "BRASILIA")
+	assert.ErrorIs(t, err, ErrInvalidMerchant)
+	_, err = NewMerchant("key", "", "", "BRASILIA")
+	assert.ErrorIs(t, err, ErrInvalidMerchant)
+	_, err = NewMerchant("key", "", "Fulano de Tal", " ")
+	assert.ErrorIs(t, err, ErrInvalidMerchant)
+}
+
+func TestNewMerchant_ShouldTruncateNameAndCity(t *testing.T) {
+	merchant, err := NewMerchant("key", "", "Companhia Brasileira de Cobranças", "Santa Bárbara d'Oeste")
+	assert.NoError(t, err)
+	assert.Equal(t, "Companhia Brasileira de C", merchant.Name)
+	assert.Equal(t, "Santa Barbara d", merchant.City)
+}
+
+func TestTxIdFromDebtId(t *testing.T) {
+	txId, err := TxIdFromDebtId("ea23f2ca-663a-4266-a742-9da4c9f4fcb3")
+	assert.NoError(t, err)
+	assert.Equal(t, "ea23f2ca663a4266a7429da4c", txId)
+
+	txId, err = TxIdFromDebtId("debt-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "debt1", txId)
+
+	_, err = TxIdFromDebtId("---")
+	assert.ErrorIs(t, err, ErrInvalidTxId)
+}
+
+func crcOf(payload string) string {
+	return fmt.Sprintf("%04X", crc16(payload[:len(payload)-4]))
+}
@@ -0,0 +1,283 @@
+package qrcode
+
+func (qr *QRCode) setFunction(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.isFunction[y][x] = true
+}
+
+// drawFunctionPatterns draws everything but the data. The format bits are
+// reserved here and drawn once the mask is chosen.
+func (qr *QRCode) drawFunctionPatterns() {
+	for i := range qr.Size {
+		qr.setFunction(6, i, i%2 == 0)
+		qr.setFunction(i, 6, i%2 == 0)
+	}
+
+	qr.drawFinderPattern(3, 3)
+	qr.drawFinderPattern(qr.Size-4, 3)
+	qr.drawFinderPattern(3, qr.Size-4)
+
+	positions := alignmentPatternPositions(qr.Version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// The corners are taken by the finder patterns.
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			qr.drawAlignmentPattern(x, y)
+		}
+	}
+
+	qr.drawFormatBits(0)
+	qr.drawVersion()
+}
+
+// drawFinderPattern draws the 7x7 pattern centered at x, y and the light
+// separator around it.
+func (qr *QRCode) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= qr.Size || yy < 0 || yy >= qr.Size {
+				continue
+			}
+			distance := max(abs(dx), abs(dy))
+			qr.setFunction(xx, yy, distance != 2 && distance != 4)
+		}
+	}
+}
+
+func (qr *QRCode) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			qr.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPatternPositions returns the rows and columns the alignment
+// patterns are centered at, in ascending order.
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, position := numAlign-1, version*4+10; i > 0; i, position = i-1, position-step {
+		result[i] = position
+	}
+	return result
+}
+
+// drawFormatBits draws both copies of the error correction level and mask,
+// protected by a BCH(15, 5) code.
+func (qr *QRCode) drawFormatBits(mask int) {
+	data := eccLevelMedium<<3 | mask
+	remainder := data
+	for range 10 {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+	bits := (data<<10 | remainder) ^ 0x5412
+
+	for i := 0; i <= 5; i++ {
+		qr.setFunction(8, i, bit(bits, i))
+	}
+	qr.setFunction(8, 7, bit(bits, 6))
+	qr.setFunction(8, 8, bit(bits, 7))
+	qr.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		qr.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := range 8 {
+		qr.setFunction(qr.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunction(8, qr.Size-15+i, bit(bits, i))
+	}
+	// The dark module is always there.
+	qr.setFunction(8, qr.Size-8, true)
+}
+
+// drawVersion draws both copies of the version, protected by a BCH(18, 6)
+// code, from version 7 on.
+func (qr *QRCode) drawVersion() {
+	if qr.Version < 7 {
+		return
+	}
+	remainder := qr.Version
+	for range 12 {
+		remainder = (remainder << 1) ^ ((remainder >> 11) * 0x1F25)
+	}
+	bits := qr.Version<<12 | remainder
+
+	for i := range 18 {
+		a := qr.Size - 11 + i%3
+		b := i / 3
+		qr.setFunction(a, b, bit(bits, i))
+		qr.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords places the codewords in two module wide columns, zigzagging
+// up and down from the bottom right corner and skipping the function patterns.
+func (qr *QRCode) drawCodewords(codewords []byte) {
+	i := 0
+	for right := qr.Size - 1; right >= 1; right -= 2 {
+		// Skips the vertical timing pattern.
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vertical := range qr.Size {
+			y := vertical
+			if upward {
+				y = qr.Size - 1 - vertical
+			}
+			for j := range 2 {
+				x := right - j
+				if qr.isFunction[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				qr.modules[y][x] = bit(int(codewords[i/8]), 7-i%8)
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by the mask, applying it twice
+// undoes it.
+func (qr *QRCode) applyMask(mask int) {
+	for y := range qr.Size {
+		for x := range qr.Size {
+			if !qr.isFunction[y][x] && masked(mask, x, y) {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// Weights of the penalty rules used to pick the mask.
+const (
+	penaltyN1 = 3
+	penaltyN2 = 3
+	penaltyN3 = 40
+	penaltyN4 = 10
+)
+
+// finderLikePattern is the 1:1:3:1:1 ratio of the finder patterns, the N3 rule
+// penalizes it when it comes after or before 4 light modules.
+var finderLikePattern = []bool{true, false, true, true, true, false, true}
+
+func (qr *QRCode) penalty() int {
+	result := 0
+
+	for i := range qr.Size {
+		result += qr.runsPenalty(func(j int) bool { return qr.modules[i][j] })
+		result += qr.runsPenalty(func(j int) bool { return qr.modules[j][i] })
+		result += qr.finderLikePenalty(func(j int) bool { return qr.Dark(j, i) })
+		result += qr.finderLikePenalty(func(j int) bool { return qr.Dark(i, j) })
+	}
+
+	for y := 0; y < qr.Size-1; y++ {
+		for x := 0; x < qr.Size-1; x++ {
+			color := qr.modules[y][x]
+			if color == qr.modules[y][x+1] && color == qr.modules[y+1][x] && color == qr.modules[y+1][x+1] {
+				result += penaltyN2
+			}
+		}
+	}
+
+	dark := 0
+	for y := range qr.Size {
+		for x := range qr.Size {
+			if qr.modules[y][x] {
+				dark++
+			}
+		}
+	}
+	total := qr.Size * qr.Size
+	result += abs(dark*100/total-50) / 5 * penaltyN4
+
+	return result
+}
+
+// runsPenalty is the N1 rule, for runs of 5 or more modules of the same color.
+func (qr *QRCode) runsPenalty(module func(int) bool) int {
+	result := 0
+	run := 1
+	for j := 1; j <= qr.Size; j++ {
+		if j < qr.Size && module(j) == module(j-1) {
+			run++
+			continue
+		}
+		if run >= 5 {
+			result += penaltyN1 + run - 5
+		}
+		run = 1
+	}
+	return result
+}
+
+// finderLikePenalty is the N3 rule, modules outside of the symbol are light.
+func (qr *QRCode) finderLikePenalty(module func(int) bool) int {
+	result := 0
+	for j := 0; j+len(finderLikePattern) <= qr.Size; j++ {
+		matches := true
+		for k, dark := range finderLikePattern {
+			if module(j+k) != dark {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		lightBefore, lightAfter := true, true
+		for k := 1; k <= 4; k++ {
+			lightBefore = lightBefore && !module(j-k)
+			lightAfter = lightAfter && !module(j+len(finderLikePattern)-1+k)
+		}
+		if lightBefore || lightAfter {
+			result += penaltyN3
+		}
+	}
+	return result
+}
+
+func bit(value, i int) bool {
+	return (value>>i)&1 != 0
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
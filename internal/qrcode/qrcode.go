@@ -0,0 +1,239 @@
+// Package qrcode encodes text into QR codes (ISO/IEC 18004) in byte mode with
+// the medium error correction level, the one Pix BR Codes are usually printed
+// with.
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+const (
+	minVersion = 1
+	maxVersion = 40
+
+	modeByte = 0x4
+	// Format bits of the medium error correction level.
+	eccLevelMedium = 0
+
+	padCodewordA = 0xEC
+	padCodewordB = 0x11
+)
+
+// Error correction codewords per block and number of blocks of the medium
+// level, indexed by version.
+var (
+	eccCodewordsPerBlock = [maxVersion + 1]int{
+		-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26,
+		26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28,
+	}
+	eccBlocks = [maxVersion + 1]int{
+		-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16,
+		17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49,
+	}
+)
+
+var ErrDataTooLong = errors.New("data too long for a QR code")
+
+// QRCode is the matrix of modules of an encoded QR code, without its quiet zone.
+type QRCode struct {
+	Version int
+	Size    int
+	Mask    int
+
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// Encode picks the smallest version the text fits in and the mask with the
+// lowest penalty.
+func Encode(text string) (*QRCode, error) {
+	data := []byte(text)
+
+	version := minVersion
+	for ; version <= maxVersion; version++ {
+		if dataBits(version, len(data)) <= numDataCodewords(version)*8 {
+			break
+		}
+	}
+	if version > maxVersion {
+		return nil, fmt.Errorf("%w: %d bytes", ErrDataTooLong, len(data))
+	}
+
+	qr := newQRCode(version)
+	qr.drawFunctionPatterns()
+	qr.drawCodewords(addEccAndInterleave(version, dataCodewords(version, data)))
+
+	qr.Mask = 0
+	minPenalty := -1
+	for mask := range 8 {
+		qr.applyMask(mask)
+		qr.drawFormatBits(mask)
+		penalty := qr.penalty()
+		if minPenalty < 0 || penalty < minPenalty {
+			qr.Mask = mask
+			minPenalty = penalty
+		}
+		qr.applyMask(mask)
+	}
+	qr.applyMask(qr.Mask)
+	qr.drawFormatBits(qr.Mask)
+	return qr, nil
+}
+
+// Dark reports whether the module at column x and row y is dark.
+func (qr *QRCode) Dark(x, y int) bool {
+	return x >= 0 && x < qr.Size && y >= 0 && y < qr.Size && qr.modules[y][x]
+}
+
+// Image draws each module as a square of scale pixels, surrounded by a light
+// border of the given number of modules. Readers need a border of at least 4.
+func (qr *QRCode) Image(scale, border int) image.Image {
+	side := (qr.Size + border*2) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := range side {
+		for x := range side {
+			if qr.Dark(x/scale-border, y/scale-border) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return img
+}
+
+// PNG writes the image of the QR code as a PNG.
+func (qr *QRCode) PNG(w io.Writer, scale, border int) error {
+	return png.Encode(w, qr.Image(scale, border))
+}
+
+func newQRCode(version int) *QRCode {
+	size := version*4 + 17
+	qr := &QRCode{
+		Version:    version,
+		Size:       size,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+	for y := range size {
+		qr.modules[y] = make([]bool, size)
+		qr.isFunction[y] = make([]bool, size)
+	}
+	return qr
+}
+
+// numRawDataModules is the number of modules left for data and error
+// correction once the function patterns are drawn.
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[version]*eccBlocks[version]
+}
+
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+func dataBits(version, length int) int {
+	if length >= 1<<charCountBits(version) {
+		return 1 << 30
+	}
+	return 4 + charCountBits(version) + length*8
+}
+
+// dataCodewords lays out the mode, the byte count and the data, then fills the
+// remaining capacity with the terminator and the pad codewords.
+func dataCodewords(version int, data []byte) []byte {
+	capacity := numDataCodewords(version) * 8
+
+	bits := &bitBuffer{}
+	bits.append(modeByte, 4)
+	bits.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	bits.append(0, min(4, capacity-bits.len()))
+	bits.append(0, (8-bits.len()%8)%8)
+	for pad := padCodewordA; bits.len() < capacity; pad ^= padCodewordA ^ padCodewordB {
+		bits.append(pad, 8)
+	}
+	return bits.bytes()
+}
+
+// addEccAndInterleave splits the data in blocks, appends the error correction
+// codewords of each one and interleaves them. The first blocks may be one
+// codeword shorter than the others.
+func addEccAndInterleave(version int, data []byte) []byte {
+	numBlocks := eccBlocks[version]
+	blockEccLen := eccCodewordsPerBlock[version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockEccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		dataLen := shortBlockLen - blockEccLen
+		if i >= numShortBlocks {
+			dataLen++
+		}
+		block := append([]byte{}, data[k:k+dataLen]...)
+		k += dataLen
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i <= shortBlockLen; i++ {
+		for j, block := range blocks {
+			// Skips the placeholder of the short blocks.
+			if i != shortBlockLen-blockEccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+type bitBuffer struct {
+	bits []bool
+}
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		b.bits = append(b.bits, (value>>i)&1 != 0)
+	}
+}
+
+func (b *bitBuffer) len() int {
+	return len(b.bits)
+}
+
+func (b *bitBuffer) bytes() []byte {
+	result := make([]byte, len(b.bits)/8)
+	for i, bit := range b.bits {
+		if bit {
+			result[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Example of ISO/IEC 18004 annex I, "01234567" as 1-M.
+func TestReedSolomonRemainder(t *testing.T) {
+	data := []byte{16, 32, 12, 86, 97, 128, 236, 17, 236, 17, 236, 17, 236, 17, 236, 17}
+	assert.Equal(t,
+		[]byte{165, 36, 212, 193, 237, 54, 199, 135, 44, 85},
+		reedSolomonRemainder(data, reedSolomonDivisor(10)),
+	)
+}
+
+func TestDataCodewords(t *testing.T) {
+	assert.Equal(t,
+		[]byte{0x40, 0x24, 0x14, 0x20, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11},
+		dataCodewords(1, []byte("AB")),
+	)
+}
+
+func TestNumDataCodewords(t *testing.T) {
+	assert.Equal(t, 16, numDataCodewords(1))
+	assert.Equal(t, 124, numDataCodewords(7))
+	assert.Equal(t, 2334, numDataCodewords(40))
+}
+
+func TestAlignmentPatternPositions(t *testing.T) {
+	assert.Nil(t, alignmentPatternPositions(1))
+	assert.Equal(t, []int{6, 18}, alignmentPatternPositions(2))
+	assert.Equal(t, []int{6, 22, 38}, alignmentPatternPositions(7))
+	assert.Equal(t, []int{6, 34, 60, 86, 112, 138}, alignmentPatternPositions(32))
+	assert.Equal(t, []int{6, 30, 58, 86, 114, 142, 170}, alignmentPatternPositions(40))
+}
+
+func TestEncode_ShouldPickSmallestVersion(t *testing.T) {
+	qr, err := Encode("hello world")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, qr.Version)
+	assert.Equal(t, 21, qr.Size)
+
+	qr, err = Encode(strings.Repeat("x", 15))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, qr.Version)
+
+	qr, err = Encode(strings.Repeat("x", 2331))
+	assert.NoError(t, err)
+	assert.Equal(t, 40, qr.Version)
+	assert.Equal(t, 177, qr.Size)
+}
+
+func TestEncode_ShouldFailWhenDataDoesNotFit(t *testing.T) {
+	_, err := Encode(strings.Repeat("x", 2332))
+	assert.ErrorIs(t, err, ErrDataTooLong)
+}
+
+func TestEncode_ShouldDrawFinderPatternsAndFormatBits(t *testing.T) {
+	qr, err := Encode("00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D")
+	assert.NoError(t, err)
+
+	for _, corner := range [][2]int{{0, 0}, {qr.Size - 7, 0}, {0, qr.Size - 7}} {
+		for i := range 7 {
+			assert.True(t, qr.Dark(corner[0]+i, corner[1]))
+			assert.True(t, qr.Dark(corner[0], corner[1]+i))
+		}
+		assert.False(t, qr.Dark(corner[0]+1, corner[1]+1))
+		assert.True(t, qr.Dark(corner[0]+3, corner[1]+3))
+	}
+
+	// Reads back the first copy of the format bits.
+	bits := 0
+	for i := 0; i <= 5; i++ {
+		bits |= boolToInt(qr.Dark(8, i)) << i
+	}
+	bits |= boolToInt(qr.Dark(8, 7)) << 6
+	bits |= boolToInt(qr.Dark(8, 8)) << 7
+	bits |= boolToInt(qr.Dark(7, 8)) << 8
+	for i := 9; i < 15; i++ {
+		bits |= boolToInt(qr.Dark(14-i, 8)) << i
+	}
+	bits ^= 0x5412
+	assert.Equal(t, eccLevelMedium, bits>>13)
+	assert.Equal(t, qr.Mask, (bits>>10)&7)
+}
+
+func TestQRCode_PNG(t *testing.T) {
+	qr, err := Encode("hello world")
+	assert.NoError(t, err)
+
+	var buffer bytes.Buffer
+	assert.NoError(t, qr.PNG(&buffer, 4, 4))
+
+	img, err := png.Decode(&buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, (21+8)*4, img.Bounds().Dx())
+	assert.Equal(t, (21+8)*4, img.Bounds().Dy())
+
+	r, _, _, _ := img.At(0, 0).RGBA()
+	assert.Equal(t, uint32(0xFFFF), r)
+	r, _, _, _ = img.At(4*4, 4*4).RGBA()
+	assert.Equal(t, uint32(0), r)
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
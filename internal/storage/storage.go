@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	"performatic-file-processor/internal/handler"
+
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+
+	defaultRetentionDays = 30
+	defaultS3Prefix      = "uploads"
+)
+
+var (
+	instance     handler.FileHandler
+	instanceOnce sync.Once
+)
+
+// GetInstance returns the storage configured by UPLOAD_STORAGE. The API and
+// the workers share it, so with the s3 backend a file saved by any replica can
+// be read by all of them.
+func GetInstance() handler.FileHandler {
+	instanceOnce.Do(func() {
+		instance = newFromEnv()
+	})
+	return instance
+}
+
+func newFromEnv() handler.FileHandler {
+	retention := handler.NewRetentionPolicy(retentionDays())
+
+	switch backend := os.Getenv("UPLOAD_STORAGE"); backend {
+	case "", BackendLocal:
+		dir := os.Getenv("UPLOAD_STORAGE_DIR")
+		if dir == "" {
+			dir = handler.DefaultLocalStorageDir
+		}
+		return handler.NewLocalFileHandler(dir, retention)
+	case BackendS3:
+		return newS3FromEnv(retention)
+	default:
+		log.Fatalf("Invalid UPLOAD_STORAGE: %s", backend)
+		return nil
+	}
+}
+
+func newS3FromEnv(retention handler.RetentionPolicy) handler.FileHandler {
+	bucket := os.Getenv("S3_BUCKET")
+	if bucket == "" {
+		log.Fatalf("S3_BUCKET is required when UPLOAD_STORAGE is %s", BackendS3)
+	}
+	prefix := os.Getenv("S3_PREFIX")
+	if prefix == "" {
+		prefix = defaultS3Prefix
+	}
+	region := os.Getenv("S3_REGION")
+
+	client, err := minio.New(os.Getenv("S3_ENDPOINT"), &minio.Options{
+		Creds:  credentials.NewStaticV4(os.Getenv("S3_ACCESS_KEY_ID"), os.Getenv("S3_SECRET_ACCESS_KEY"), ""),
+		Secure: os.Getenv("S3_USE_SSL") == "true",
+		Region: region,
+	})
+	if err != nil {
+		log.Fatalf("Error creating S3 client: %v", err)
+	}
+
+	s3FileHandler := handler.NewS3FileHandler(client, bucket, prefix, retention)
+	err = s3FileHandler.EnsureBucket(context.Background(), region)
+	if err != nil {
+		log.Fatalf("Error creating bucket %s: %v", bucket, err)
+	}
+	return s3FileHandler
+}
+
+// retentionDays reads UPLOAD_RETENTION_DAYS, 0 deletes the files once they
+// have been published.
+func retentionDays() int {
+	value := os.Getenv("UPLOAD_RETENTION_DAYS")
+	if value == "" {
+		return defaultRetentionDays
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		log.Fatalf("Invalid UPLOAD_RETENTION_DAYS: %s", value)
+	}
+	return days
+}
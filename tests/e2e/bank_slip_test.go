@@ -51,7 +51,7 @@ func (f *BankSlipTestE2ESuite) SetupSuite() {
 }
 
 func (f *BankSlipTestE2ESuite) SetupTest() {
-	f.dbInstance.Exec(`truncate bank_slip_file cascade`)
+	f.dbInstance.Exec(`truncate beneficiary, bank_slip_file cascade`)
 }
 
 func (f *BankSlipTestE2ESuite) TearDownTest() {
@@ -68,8 +68,16 @@ func (f *BankSlipTestE2ESuite) TestBankSlipE2eRunSuite_UploadFileAndProcessRows(
 	consumerCtx, cancel := context.WithCancel(context.Background())
 	go consumer.Execute(consumerCtx, make(chan messaging.Message))
 
+	var beneficiaryId string
+	err := f.dbInstance.QueryRow(`INSERT INTO beneficiary (name, document, bank_code, agency, account, wallet)
+		VALUES ('Cobranças Ltda', '11222333000181', '237', '1234', '0012345', '09') RETURNING id`).Scan(&beneficiaryId)
+	if err != nil {
+		f.T().Fatal(err)
+	}
+
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
+	assert.NoError(f.T(), writer.WriteField("beneficiaryId", beneficiaryId))
 
 	file, err := os.Open("./data/test_file.csv")
 	if err != nil {
@@ -93,7 +101,8 @@ func (f *BankSlipTestE2ESuite) TestBankSlipE2eRunSuite_UploadFileAndProcessRows(
 
 	router.ServeHTTP(rr, req)
 
-	assert.Equal(f.T(), http.StatusOK, rr.Code)
+	assert.Equal(f.T(), http.StatusAccepted, rr.Code)
+	assert.Contains(f.T(), rr.Header().Get("Location"), "/upload/bank-slip/file/")
 
 	retries := 0
 	found := false
@@ -102,23 +111,23 @@ func (f *BankSlipTestE2ESuite) TestBankSlipE2eRunSuite_UploadFileAndProcessRows(
 		if retries == 10 {
 			f.T().Fatal("Timeout waiting for bank slip processing")
 		}
-		queryRes, err := f.dbInstance.Query("select user_name, government_id, user_email, debt_amount, debt_due_date, debt_id from bank_slip;")
+		queryRes, err := f.dbInstance.Query("select user_name, government_id, user_email, debt_amount_cents, debt_due_date, debt_id from bank_slip;")
 		if err != nil {
 			f.T().Fatal(err)
 		}
 
 		for queryRes.Next() {
 			var name, governmentId, email, debtId string
-			var debtAmount float64
+			var debtAmount int64
 			var debtDueDate time.Time
 			err = queryRes.Scan(&name, &governmentId, &email, &debtAmount, &debtDueDate, &debtId)
 			if err != nil {
 				f.T().Fatal(err)
 			}
 			assert.Equal(f.T(), "Elijah Santos", name)
-			assert.Equal(f.T(), "9558", governmentId)
+			assert.Equal(f.T(), "52998224725", governmentId)
 			assert.Equal(f.T(), "janet95@example.com", email)
-			assert.Equal(f.T(), 7811.0, debtAmount)
+			assert.Equal(f.T(), int64(781100), debtAmount)
 			assert.Equal(f.T(), time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC), debtDueDate)
 			assert.Equal(f.T(), "ea23f2ca-663a-4266-a742-9da4c9f4fcb3", debtId)
 			found = true
@@ -2,10 +2,13 @@ package integration
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	bankSlipEntities "performatic-file-processor/internal/bank_slip/entity"
 	bankSlipRepositories "performatic-file-processor/internal/bank_slip/repositories"
+	"performatic-file-processor/internal/cnab"
 	"performatic-file-processor/internal/database"
+	"sync"
 	"testing"
 	"time"
 
@@ -22,6 +25,7 @@ type BankSlipTestIntegration struct {
 	suite.Suite
 	bankSlipRepository     *bankSlipRepositories.BankSlipPgRepository
 	bankSlipFileRepository *bankSlipRepositories.BankSlipFilePgRepository
+	beneficiary            *bankSlipEntities.Beneficiary
 	postgresContainer      testcontainers.Container
 	db                     *sql.DB
 }
@@ -43,6 +47,14 @@ func (s *BankSlipTestIntegration) SetupTest() {
 	s.bankSlipRepository = bankSlipRepositories.NewBankSlipPgRepository(db)
 	s.bankSlipFileRepository = bankSlipRepositories.NewBankSlipFilePgRepository(db)
 	s.db = db
+
+	if s.beneficiary == nil {
+		s.beneficiary, _ = bankSlipEntities.NewBeneficiary("Cobranças Ltda", "11.222.333/0001-81", "237", "1234", "12345", "09", "")
+		err := bankSlipRepositories.NewBeneficiaryPgRepository(db).Insert(s.beneficiary)
+		if err != nil {
+			log.Fatalf("Error inserting beneficiary: %v", err)
+		}
+	}
 }
 
 func (s *BankSlipTestIntegration) TearDownSuite() {
@@ -54,7 +66,7 @@ func TestBankSlipRunSuite(t *testing.T) {
 }
 
 func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldInsertBankSlipFileCorrectly() {
-	f.bankSlipFileRepository.Insert(&bankSlipEntities.BankSlipFileMetadata{FileName: "test.csv"})
+	f.bankSlipFileRepository.Insert(&bankSlipEntities.BankSlipFileMetadata{FileName: "test.csv", BeneficiaryId: f.beneficiary.ID})
 
 	queryResult, err := f.db.Query(`select id, name, created_at from bank_slip_file where name = 'test.csv'`)
 	if err != nil {
@@ -76,7 +88,7 @@ func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldInsertBankSlipFileCorre
 }
 
 func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldInsertBankSlipCorrectly() {
-	queryResult, err := f.db.Query(`INSERT INTO bank_slip_file (name) VALUES ('test.csv') RETURNING id`)
+	queryResult, err := f.db.Query(`INSERT INTO bank_slip_file (name, beneficiary_id) VALUES ('test.csv', $1) RETURNING id`, f.beneficiary.ID)
 	if err != nil {
 		log.Fatalf("Error inserting into bank_slip_file: %v", err)
 	}
@@ -91,16 +103,16 @@ func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldInsertBankSlipCorrectly
 		"test.csv": &bankSlipEntities.BankSlip{
 			UserName:               "Test User",
 			DebtId:                 debtId,
-			DebtAmount:             100.51,
+			DebtAmount:             10051,
 			DebtDueDate:            time.Now(),
-			GovernmentId:           521,
+			GovernmentId:           "52998224725",
 			UserEmail:              "test@user.com",
 			BankSlipFileMetadataId: fileId,
 			Status:                 "PENDING",
 			ErrorMessage:           nil,
 		},
 	})
-	query := `SELECT debt_id, debt_amount, debt_due_date, user_name, government_id, user_email, 
+	query := `SELECT debt_id, debt_amount_cents, debt_due_date, user_name, government_id, user_email, 
                      bank_slip_file_id, status, error_message FROM bank_slip`
 	rows, err := f.db.Query(query)
 	if err != nil {
@@ -116,10 +128,10 @@ func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldInsertBankSlipCorrectly
 		}
 	}
 	assert.NotZero(f.T(), bankSlip.DebtId, debtId)
-	assert.Equal(f.T(), bankSlip.DebtAmount, 100.51)
+	assert.Equal(f.T(), bankSlip.DebtAmount, bankSlipEntities.Money(10051))
 	assert.NotZero(f.T(), bankSlip.DebtDueDate)
 	assert.Equal(f.T(), bankSlip.UserName, "Test User")
-	assert.Equal(f.T(), bankSlip.GovernmentId, 521)
+	assert.Equal(f.T(), bankSlip.GovernmentId, bankSlipEntities.GovernmentId("52998224725"))
 	assert.Equal(f.T(), bankSlip.UserEmail, "test@user.com")
 	assert.Equal(f.T(), bankSlip.BankSlipFileMetadataId, fileId)
 	assert.Equal(f.T(), bankSlip.Status, bankSlipEntities.BankSlipStatusPending)
@@ -127,7 +139,7 @@ func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldInsertBankSlipCorrectly
 }
 
 func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldUpdateBankSlipCorrectly() {
-	queryResult, err := f.db.Query(`INSERT INTO bank_slip_file (name) VALUES ('test.csv') RETURNING id`)
+	queryResult, err := f.db.Query(`INSERT INTO bank_slip_file (name, beneficiary_id) VALUES ('test.csv', $1) RETURNING id`, f.beneficiary.ID)
 	if err != nil {
 		log.Fatalf("Error inserting into bank_slip_file: %v", err)
 	}
@@ -141,9 +153,9 @@ func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldUpdateBankSlipCorrectly
 	bankSlip := &bankSlipEntities.BankSlip{
 		UserName:               "Test User",
 		DebtId:                 debtId,
-		DebtAmount:             100.51,
+		DebtAmount:             10051,
 		DebtDueDate:            time.Now(),
-		GovernmentId:           521,
+		GovernmentId:           "52998224725",
 		UserEmail:              "test@user.com",
 		BankSlipFileMetadataId: fileId,
 		Status:                 "PENDING",
@@ -155,7 +167,7 @@ func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldUpdateBankSlipCorrectly
 
 	// Update the bank slip
 	bankSlip.Status = bankSlipEntities.BankSlipStatusSuccess
-	bankSlip.DebtAmount = 0
+	bankSlip.DebtAmount = 1
 	err = f.bankSlipRepository.UpdateMany(&bankSlipEntities.BankSlipMap{
 		"any_key": bankSlip,
 	})
@@ -164,7 +176,7 @@ func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldUpdateBankSlipCorrectly
 	}
 
 	// Verify the update
-	query := `SELECT debt_id, debt_amount, debt_due_date, user_name, government_id, user_email, 
+	query := `SELECT debt_id, debt_amount_cents, debt_due_date, user_name, government_id, user_email, 
 					 bank_slip_file_id, status, error_message FROM bank_slip WHERE debt_id = $1`
 	row := f.db.QueryRow(query, debtId)
 	var updatedBankSlip bankSlipEntities.BankSlip
@@ -184,12 +196,174 @@ func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldUpdateBankSlipCorrectly
 	}
 
 	assert.Equal(f.T(), updatedBankSlip.DebtId, debtId)
-	assert.Equal(f.T(), updatedBankSlip.DebtAmount, 100.51)
+	assert.Equal(f.T(), updatedBankSlip.DebtAmount, bankSlipEntities.Money(10051))
 	assert.NotZero(f.T(), updatedBankSlip.DebtDueDate)
 	assert.Equal(f.T(), updatedBankSlip.UserName, "Test User")
-	assert.Equal(f.T(), updatedBankSlip.GovernmentId, 521)
+	assert.Equal(f.T(), updatedBankSlip.GovernmentId, bankSlipEntities.GovernmentId("52998224725"))
 	assert.Equal(f.T(), updatedBankSlip.UserEmail, "test@user.com")
 	assert.Equal(f.T(), updatedBankSlip.BankSlipFileMetadataId, fileId)
 	assert.Equal(f.T(), updatedBankSlip.Status, bankSlipEntities.BankSlipStatusSuccess)
 	assert.Nil(f.T(), updatedBankSlip.ErrorMessage)
+
+	history, err := bankSlipRepositories.NewBankSlipStatusHistoryPgRepository(f.db).ListByDebtId(debtId)
+	assert.NoError(f.T(), err)
+	assert.Len(f.T(), history, 1)
+	assert.Equal(f.T(), bankSlipEntities.BankSlipStatusPending, history[0].FromStatus)
+	assert.Equal(f.T(), bankSlipEntities.BankSlipStatusSuccess, history[0].ToStatus)
+}
+
+func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldReportFileProgressAndCompleteWhenAllRowsProcessed() {
+	bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata("progress.csv")
+	bankSlipFile.BeneficiaryId = f.beneficiary.ID
+	err := f.bankSlipFileRepository.Insert(bankSlipFile)
+	assert.NoError(f.T(), err)
+
+	bankSlipFile.Processing(3)
+	err = f.bankSlipFileRepository.UpdateStatus(bankSlipFile)
+	assert.NoError(f.T(), err)
+
+	bankSlip := &bankSlipEntities.BankSlip{
+		UserName:               "Test User",
+		DebtId:                 uuid.New().String(),
+		DebtAmount:             10051,
+		DebtDueDate:            time.Now(),
+		GovernmentId:           "52998224725",
+		UserEmail:              "test@user.com",
+		BankSlipFileMetadataId: bankSlipFile.ID,
+		Status:                 bankSlipEntities.BankSlipStatusSuccess,
+	}
+	_, err = f.bankSlipRepository.InsertMany(&bankSlipEntities.BankSlipMap{bankSlip.DebtId: bankSlip})
+	assert.NoError(f.T(), err)
+
+	err = f.bankSlipFileRepository.AddRejectedRows(bankSlipFile.ID, 1)
+	assert.NoError(f.T(), err)
+	err = f.bankSlipFileRepository.CompleteWhenAllRowsProcessed(bankSlipFile.ID)
+	assert.NoError(f.T(), err)
+
+	progress, err := f.bankSlipFileRepository.GetById(bankSlipFile.ID)
+	assert.NoError(f.T(), err)
+	assert.Equal(f.T(), bankSlipEntities.BankSlipFileStatusProcessing, progress.Status)
+	assert.Equal(f.T(), 1, progress.RowsByStatus[bankSlipEntities.BankSlipStatusSuccess])
+	assert.Equal(f.T(), 1, progress.RejectedRows)
+
+	err = f.bankSlipFileRepository.AddRejectedRows(bankSlipFile.ID, 1)
+	assert.NoError(f.T(), err)
+	err = f.bankSlipFileRepository.CompleteWhenAllRowsProcessed(bankSlipFile.ID)
+	assert.NoError(f.T(), err)
+
+	progress, err = f.bankSlipFileRepository.GetById(bankSlipFile.ID)
+	assert.NoError(f.T(), err)
+	assert.Equal(f.T(), bankSlipEntities.BankSlipFileStatusCompleted, progress.Status)
+	assert.Equal(f.T(), 3, progress.ProcessedRows())
+
+	files, err := f.bankSlipFileRepository.List(100, 0)
+	assert.NoError(f.T(), err)
+	assert.NotEmpty(f.T(), files)
+}
+
+func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldAllocateOurNumbersWithoutGapsConcurrently() {
+	const files, slipsPerFile = 10, 5
+
+	var wg sync.WaitGroup
+	for i := 0; i < files; i++ {
+		bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata("concurrent.csv")
+		bankSlipFile.BeneficiaryId = f.beneficiary.ID
+		assert.NoError(f.T(), f.bankSlipFileRepository.Insert(bankSlipFile))
+
+		bankSlips := bankSlipEntities.BankSlipMap{}
+		for j := 0; j < slipsPerFile; j++ {
+			debtId := uuid.New().String()
+			bankSlips[debtId] = &bankSlipEntities.BankSlip{
+				UserName:               "Test User",
+				DebtId:                 debtId,
+				DebtAmount:             10051,
+				DebtDueDate:            time.Now(),
+				GovernmentId:           "52998224725",
+				UserEmail:              "test@user.com",
+				BankSlipFileMetadataId: bankSlipFile.ID,
+				Status:                 bankSlipEntities.BankSlipStatusPending,
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := f.bankSlipRepository.InsertMany(&bankSlips)
+			assert.NoError(f.T(), err)
+		}()
+	}
+	wg.Wait()
+
+	var count, distinct, lowest, highest, lastOurNumber int64
+	err := f.db.QueryRow(`SELECT COUNT(*), COUNT(DISTINCT our_number), MIN(our_number), MAX(our_number)
+		FROM bank_slip WHERE beneficiary_id = $1`, f.beneficiary.ID).Scan(&count, &distinct, &lowest, &highest)
+	assert.NoError(f.T(), err)
+	err = f.db.QueryRow(`SELECT last_our_number FROM beneficiary WHERE id = $1`, f.beneficiary.ID).Scan(&lastOurNumber)
+	assert.NoError(f.T(), err)
+
+	assert.Equal(f.T(), count, distinct)
+	assert.Equal(f.T(), int64(1), lowest)
+	assert.Equal(f.T(), count, highest)
+	assert.Equal(f.T(), highest, lastOurNumber)
+}
+
+func (f *BankSlipTestIntegration) TestBankSlipTest_ShouldSendEachBankSlipInASingleRemittance() {
+	bankSlipFile := bankSlipEntities.NewBankSlipFileMetadata("remittance.csv")
+	bankSlipFile.BeneficiaryId = f.beneficiary.ID
+	assert.NoError(f.T(), f.bankSlipFileRepository.Insert(bankSlipFile))
+
+	bankSlips := bankSlipEntities.BankSlipMap{}
+	for i := 0; i < 3; i++ {
+		debtId := uuid.New().String()
+		bankSlips[debtId] = &bankSlipEntities.BankSlip{
+			UserName:               "Test User",
+			DebtId:                 debtId,
+			DebtAmount:             10051,
+			DebtDueDate:            time.Now(),
+			GovernmentId:           "52998224725",
+			UserEmail:              "test@user.com",
+			BankSlipFileMetadataId: bankSlipFile.ID,
+			Status:                 bankSlipEntities.BankSlipStatusPending,
+		}
+	}
+	_, err := f.bankSlipRepository.InsertMany(&bankSlips)
+	assert.NoError(f.T(), err)
+	_, err = f.db.Exec(`UPDATE bank_slip SET status = 'SUCCESS', barcode = '23791000000000000000000000000000000000000000'
+		WHERE bank_slip_file_id = $1`, bankSlipFile.ID)
+	assert.NoError(f.T(), err)
+
+	remittanceRepository := bankSlipRepositories.NewRemittancePgRepository(f.db)
+	render := func(remittance *bankSlipEntities.Remittance, bankSlips []*bankSlipEntities.BankSlip) ([]byte, error) {
+		return []byte("remessa"), nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- remittanceRepository.Create(bankSlipEntities.NewRemittance(f.beneficiary.ID, bankSlipFile.ID, cnab.CNAB240), render)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var created, nothingToRemit int
+	for err := range errs {
+		if err == nil {
+			created++
+		}
+		if errors.Is(err, bankSlipEntities.ErrNothingToRemit) {
+			nothingToRemit++
+		}
+	}
+	assert.Equal(f.T(), 1, created)
+	assert.Equal(f.T(), 1, nothingToRemit)
+
+	var remitted int
+	err = f.db.QueryRow(`SELECT COUNT(*) FROM remittance_bank_slip rbs
+		JOIN remittance r ON r.id = rbs.remittance_id WHERE r.bank_slip_file_id = $1`, bankSlipFile.ID).Scan(&remitted)
+	assert.NoError(f.T(), err)
+	assert.Equal(f.T(), 3, remitted)
 }
@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"performatic-file-processor/internal/handler"
+	sharedTestHelpers "performatic-file-processor/tests/shared"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+type S3FileHandlerTestIntegration struct {
+	suite.Suite
+	minioContainer testcontainers.Container
+	client         *minio.Client
+	fileHandler    *handler.S3FileHandler
+}
+
+func (s *S3FileHandlerTestIntegration) SetupSuite() {
+	containerFactory := sharedTestHelpers.NewContainerFactory(s.T().Context())
+	s.minioContainer = containerFactory.MakeMinioContainer()
+
+	client, err := minio.New(os.Getenv("S3_ENDPOINT"), &minio.Options{
+		Creds: credentials.NewStaticV4(sharedTestHelpers.MinioAccessKey, sharedTestHelpers.MinioSecretKey, ""),
+	})
+	if err != nil {
+		s.T().Fatalf("Error creating S3 client: %v", err)
+	}
+	s.client = client
+}
+
+func (s *S3FileHandlerTestIntegration) SetupTest() {
+	s.fileHandler = handler.NewS3FileHandler(s.client, "uploads", s.T().Name(), handler.NewRetentionPolicy(1))
+	err := s.fileHandler.EnsureBucket(s.T().Context(), "")
+	assert.NoError(s.T(), err)
+}
+
+func (s *S3FileHandlerTestIntegration) TearDownSuite() {
+	defer s.minioContainer.Terminate(s.T().Context())
+}
+
+func TestS3FileHandlerRunSuite(t *testing.T) {
+	suite.Run(t, new(S3FileHandlerTestIntegration))
+}
+
+type uploadedFile struct {
+	name    string
+	content []byte
+}
+
+func (f *uploadedFile) FileName() string  { return f.name }
+func (f *uploadedFile) Reader() io.Reader { return bytes.NewReader(f.content) }
+func (f *uploadedFile) Close()            {}
+
+func (s *S3FileHandlerTestIntegration) TestS3FileHandler_ShouldSaveAndReopenFileFromAnotherHandler() {
+	savedFile, err := s.fileHandler.SaveFile(&uploadedFile{name: "boletos.csv", content: []byte("name,debtId\nJohn,1\n")})
+	assert.NoError(s.T(), err)
+	savedFile.Release()
+
+	// Another replica sharing the bucket.
+	anotherReplica := handler.NewS3FileHandler(s.client, "uploads", s.T().Name(), handler.NewRetentionPolicy(1))
+	reopenedFile, err := anotherReplica.OpenFile(savedFile.Filepath())
+	assert.NoError(s.T(), err)
+	defer reopenedFile.Close()
+
+	content, err := io.ReadAll(reopenedFile.Open())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "name,debtId\nJohn,1\n", string(content))
+}
+
+func (s *S3FileHandlerTestIntegration) TestS3FileHandler_ShouldDeleteReleasedFileWithoutRetention() {
+	fileHandler := handler.NewS3FileHandler(s.client, "uploads", s.T().Name(), handler.RetentionPolicy{})
+	savedFile, err := fileHandler.SaveFile(&uploadedFile{name: "boletos.csv", content: []byte("any_file")})
+	assert.NoError(s.T(), err)
+
+	savedFile.Release()
+
+	_, err = fileHandler.OpenFile(savedFile.Filepath())
+	assert.ErrorIs(s.T(), err, handler.ErrSavedFileNotFound)
+}
+
+func (s *S3FileHandlerTestIntegration) TestS3FileHandler_ShouldPurgeFilesPastRetention() {
+	savedFile, err := s.fileHandler.SaveFile(&uploadedFile{name: "boletos.csv", content: []byte("any_file")})
+	assert.NoError(s.T(), err)
+	savedFile.Release()
+
+	purged, err := s.fileHandler.PurgeExpired(time.Now())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, purged)
+
+	purged, err = s.fileHandler.PurgeExpired(time.Now().Add(48 * time.Hour))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, purged)
+
+	_, err = s.fileHandler.OpenFile(savedFile.Filepath())
+	assert.ErrorIs(s.T(), err, handler.ErrSavedFileNotFound)
+}
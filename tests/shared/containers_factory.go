@@ -63,31 +63,14 @@ func (f *ContainerFactory) MakeDBContainer() testcontainers.Container {
 
 	dbInstance := database.GetInstance()
 
-	dbInstance.Exec(`
-		CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-
-		CREATE TABLE bank_slip_file (
-			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-			name VARCHAR(255) NOT NULL,
-			created_at TIMESTAMP NOT NULL DEFAULT NOW()
-		);
-
-		CREATE TABLE bank_slip (
-			debt_id UUID PRIMARY KEY UNIQUE,
-			debt_amount NUMERIC(10,2) NOT NULL,
-			debt_due_date DATE NOT NULL,
-			user_name VARCHAR(255) NOT NULL,
-			government_id INT NOT NULL,
-			user_email VARCHAR(255) NOT NULL,
-			bank_slip_file_id UUID NOT NULL,
-			error_message varchar(255),
-			status VARCHAR(50) NOT NULL,
-			FOREIGN KEY (bank_slip_file_id) REFERENCES bank_slip_file(id),
-			CONSTRAINT status_check CHECK (status IN ('PENDING', 'SUCCESS', 'GENERATING_BILLING_ERROR', 'SENT_EMAIL_WITH_ERROR'))
-		);
-
-		CREATE INDEX bank_slip_debt_id_idx ON bank_slip(debt_id);
-	`)
+	migration, err := os.ReadFile("../../db/migration.sql")
+	if err != nil {
+		log.Fatalf("Error reading migration file: %v", err)
+	}
+	_, err = dbInstance.Exec(string(migration))
+	if err != nil {
+		log.Fatalf("Error running migration: %v", err)
+	}
 
 	return dbContainer
 }
@@ -132,3 +115,46 @@ func (f *ContainerFactory) MakeKafkaLandoopContainer() testcontainers.Container
 	log.Printf("Kafka is running on port: %s\n", os.Getenv("KAFKA_BOOTSTRAP_SERVERS"))
 	return kafkaContainer
 }
+
+const (
+	MinioAccessKey = "minioadmin"
+	MinioSecretKey = "minioadmin"
+)
+
+// MakeMinioContainer starts an S3 compatible storage and points the S3_*
+// variables to it.
+func (f *ContainerFactory) MakeMinioContainer() testcontainers.Container {
+	minioContainerReq := testcontainers.ContainerRequest{
+		Image:        "minio/minio:latest",
+		ExposedPorts: []string{"9000/tcp"},
+		Env: map[string]string{
+			"MINIO_ROOT_USER":     MinioAccessKey,
+			"MINIO_ROOT_PASSWORD": MinioSecretKey,
+		},
+		Cmd:        []string{"server", "/data"},
+		WaitingFor: wait.ForHTTP("/minio/health/live").WithPort("9000/tcp"),
+	}
+
+	minioContainer, err := testcontainers.GenericContainer(f.ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: minioContainerReq,
+		Started:          true,
+	})
+	if err != nil {
+		log.Fatalf("Error starting MinIO container: %v", err)
+	}
+
+	minioPort, err := minioContainer.MappedPort(f.ctx, "9000")
+	if err != nil {
+		log.Fatalf("Error getting mapped port for MinIO: %v", err)
+	}
+	minioHost, err := minioContainer.Host(f.ctx)
+	if err != nil {
+		log.Fatalf("Error getting host for MinIO: %v", err)
+	}
+	os.Setenv("S3_ENDPOINT", fmt.Sprintf("%s:%s", minioHost, minioPort.Port()))
+	os.Setenv("S3_ACCESS_KEY_ID", MinioAccessKey)
+	os.Setenv("S3_SECRET_ACCESS_KEY", MinioSecretKey)
+
+	log.Printf("MinIO is running on: %s\n", os.Getenv("S3_ENDPOINT"))
+	return minioContainer
+}